@@ -20,6 +20,9 @@ func main() {
 			fmt.Println("Hermes Autonomous Agent", version)
 			fmt.Println("Use 'hermes --help' for available commands")
 		},
+		PersistentPreRunE: func(c *cobra.Command, args []string) error {
+			return cmd.PreRun(c, ".")
+		},
 	}
 
 	// Add subcommands
@@ -33,6 +36,12 @@ func main() {
 	rootCmd.AddCommand(cmd.NewTaskCmd())
 	rootCmd.AddCommand(cmd.NewLogCmd())
 	rootCmd.AddCommand(cmd.NewIdeaCmd())
+	rootCmd.AddCommand(cmd.NewRollbackCmd())
+	rootCmd.AddCommand(cmd.NewGraphCmd())
+	rootCmd.AddCommand(cmd.NewConfigCmd())
+	rootCmd.AddCommand(cmd.NewDiffCmd())
+	rootCmd.AddCommand(cmd.NewAnalyticsCmd())
+	rootCmd.AddCommand(cmd.NewProfileCmd())
 
 	// Set version for update command
 	cmd.SetUpdateVersion(version)
@@ -40,6 +49,6 @@ func main() {
 	rootCmd.AddCommand(cmd.NewInstallCmd())
 
 	if err := rootCmd.Execute(); err != nil {
-		os.Exit(1)
+		os.Exit(cmd.ExitCode(err))
 	}
 }
@@ -1,8 +1,15 @@
 package ai
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
 	"testing"
+	"time"
 
+	claudecode "github.com/severity1/claude-code-sdk-go"
 	"hermes/internal/task"
 )
 
@@ -38,6 +45,15 @@ func TestClaudeProviderName(t *testing.T) {
 	}
 }
 
+func TestCLICommand(t *testing.T) {
+	if got := cliCommand(&ExecuteOptions{}, "droid"); got != "droid" {
+		t.Errorf("expected default CLI 'droid', got %s", got)
+	}
+	if got := cliCommand(&ExecuteOptions{CLIPath: "/tmp/wrapper/droid"}, "droid"); got != "/tmp/wrapper/droid" {
+		t.Errorf("expected CLIPath override, got %s", got)
+	}
+}
+
 func TestDefaultRetryConfig(t *testing.T) {
 	cfg := DefaultRetryConfig()
 	if cfg.MaxRetries != 3 {
@@ -51,6 +67,149 @@ func TestDefaultRetryConfig(t *testing.T) {
 	}
 }
 
+// flakyProvider fails a fixed number of times before succeeding
+type flakyProvider struct {
+	failures int
+	calls    int
+	err      error // Error of failures; defaults to "attempt N failed"
+}
+
+func (p *flakyProvider) Name() string      { return "flaky" }
+func (p *flakyProvider) IsAvailable() bool { return true }
+func (p *flakyProvider) Capabilities() ProviderCapabilities {
+	return ProviderCapabilities{}
+}
+
+func (p *flakyProvider) Execute(ctx context.Context, opts *ExecuteOptions) (*ExecuteResult, error) {
+	p.calls++
+	if p.calls <= p.failures {
+		if p.err != nil {
+			return &ExecuteResult{Error: p.err.Error(), Cause: p.err}, p.err
+		}
+		return nil, fmt.Errorf("attempt %d failed", p.calls)
+	}
+	return &ExecuteResult{Success: true, Output: "ok"}, nil
+}
+
+func (p *flakyProvider) ExecuteStream(ctx context.Context, opts *ExecuteOptions) (<-chan StreamEvent, error) {
+	return nil, fmt.Errorf("not supported")
+}
+
+func TestExecuteWithRetrySucceedsAfterFailures(t *testing.T) {
+	provider := &flakyProvider{failures: 2}
+	cfg := &RetryConfig{MaxRetries: 3, Delay: time.Millisecond, BackoffMultiplier: 2, MaxDelay: 10 * time.Millisecond}
+
+	result, err := ExecuteWithRetry(context.Background(), provider, &ExecuteOptions{}, cfg)
+	if err != nil {
+		t.Fatalf("expected success, got %v", err)
+	}
+	if result.Output != "ok" {
+		t.Errorf("expected output ok, got %q", result.Output)
+	}
+	if provider.calls != 3 {
+		t.Errorf("expected 3 calls, got %d", provider.calls)
+	}
+}
+
+func TestExecuteWithRetryGivesUp(t *testing.T) {
+	provider := &flakyProvider{failures: 5}
+	cfg := &RetryConfig{MaxRetries: 3, Delay: time.Millisecond}
+
+	_, err := ExecuteWithRetry(context.Background(), provider, &ExecuteOptions{}, cfg)
+	if err == nil || !strings.Contains(err.Error(), "failed after 3 attempts") {
+		t.Errorf("expected failure after 3 attempts, got %v", err)
+	}
+	if provider.calls != 3 {
+		t.Errorf("expected 3 calls, got %d", provider.calls)
+	}
+}
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		err       error
+		retryable bool
+	}{
+		{nil, false},
+		{context.DeadlineExceeded, true},
+		{fmt.Errorf("claude: %w", context.DeadlineExceeded), true},
+		{context.Canceled, false},
+		{fmt.Errorf("%w: input tokens", ErrBudgetExceeded), false},
+		{claudecode.NewProcessError("Claude CLI failed", 1, "Rate limit reached, retry later"), true},
+		{fmt.Errorf("claude: %w", claudecode.NewProcessError("Claude CLI failed", 1, "API Error: 529 overloaded")), true},
+		{claudecode.NewConnectionError("failed to connect", nil), true},
+		{claudecode.NewProcessError("Claude CLI failed", 1, "Invalid API key"), false},
+		{claudecode.NewProcessError("Claude CLI failed", 2, "rate limit"), false},
+		{claudecode.NewProcessError("Claude CLI failed", 1, "wrote 429 lines to /tmp/503/eof.go"), false},
+		{errors.New("rate limit exceeded"), false},
+		{errors.New("unexpected EOF"), false},
+	}
+
+	for _, tt := range tests {
+		if got := IsRetryable(tt.err); got != tt.retryable {
+			t.Errorf("IsRetryable(%v) = %v, want %v", tt.err, got, tt.retryable)
+		}
+	}
+}
+
+func TestExecuteTaskWithRetry(t *testing.T) {
+	cfg := RetryConfig{MaxRetries: 3, Delay: time.Millisecond}
+	tk := &task.Task{ID: "T001", Name: "Retry"}
+
+	rateLimited := claudecode.NewProcessError("Claude CLI failed", 1, "rate limit exceeded")
+	provider := &flakyProvider{failures: 2, err: rateLimited}
+	result, err := NewTaskExecutor(provider, ".").ExecuteTaskWithRetry(context.Background(), tk, "", false, cfg)
+	if err != nil || result.Output != "ok" {
+		t.Fatalf("expected success after retries, got %v, %v", result, err)
+	}
+	if provider.calls != 3 {
+		t.Errorf("expected 3 calls, got %d", provider.calls)
+	}
+
+	provider = &flakyProvider{failures: 5, err: rateLimited}
+	_, err = NewTaskExecutor(provider, ".").ExecuteTaskWithRetry(context.Background(), tk, "", false, cfg)
+	if err == nil || !strings.Contains(err.Error(), "failed after 3 attempts") {
+		t.Errorf("expected failure after 3 attempts, got %v", err)
+	}
+
+	// Permanent errors are returned without retrying
+	provider = &flakyProvider{failures: 5, err: claudecode.NewProcessError("Claude CLI failed", 1, "invalid api key")}
+	_, err = NewTaskExecutor(provider, ".").ExecuteTaskWithRetry(context.Background(), tk, "", false, cfg)
+	if err == nil || provider.calls != 1 {
+		t.Errorf("expected one call and an error, got %d calls, %v", provider.calls, err)
+	}
+}
+
+func TestRetryDelayFor(t *testing.T) {
+	cfg := &RetryConfig{Delay: time.Second, BackoffMultiplier: 2, MaxDelay: 5 * time.Second}
+	expected := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second}
+	for retry, want := range expected {
+		if got := cfg.DelayFor(retry); got != want {
+			t.Errorf("DelayFor(%d) = %v, expected %v", retry, got, want)
+		}
+	}
+
+	// Multiplier of 1 or less keeps a fixed delay
+	fixed := &RetryConfig{Delay: time.Second, BackoffMultiplier: 1}
+	if got := fixed.DelayFor(4); got != time.Second {
+		t.Errorf("expected fixed delay, got %v", got)
+	}
+
+	// No cap when MaxDelay is zero
+	uncapped := &RetryConfig{Delay: time.Second, BackoffMultiplier: 3}
+	if got := uncapped.DelayFor(2); got != 9*time.Second {
+		t.Errorf("expected 9s, got %v", got)
+	}
+
+	// Jitter stays within ±10%
+	jittered := &RetryConfig{Delay: 10 * time.Second, Jitter: true}
+	for i := 0; i < 100; i++ {
+		got := jittered.DelayFor(0)
+		if got < 9*time.Second || got > 11*time.Second {
+			t.Fatalf("jittered delay %v outside ±10%%", got)
+		}
+	}
+}
+
 func TestFormatFiles(t *testing.T) {
 	// Empty files
 	result := formatFiles(nil)
@@ -121,3 +280,369 @@ func TestStreamDisplay(t *testing.T) {
 		t.Error("expected showCost = false")
 	}
 }
+
+// usageProvider reports fixed usage for every execution
+type usageProvider struct {
+	calls int
+}
+
+func (p *usageProvider) Name() string      { return "usage" }
+func (p *usageProvider) IsAvailable() bool { return true }
+func (p *usageProvider) Capabilities() ProviderCapabilities {
+	return ProviderCapabilities{}
+}
+
+func (p *usageProvider) Execute(ctx context.Context, opts *ExecuteOptions) (*ExecuteResult, error) {
+	p.calls++
+	return &ExecuteResult{Success: true, TokensIn: 400, TokensOut: 50, Cost: 0.25}, nil
+}
+
+func (p *usageProvider) ExecuteStream(ctx context.Context, opts *ExecuteOptions) (<-chan StreamEvent, error) {
+	return nil, fmt.Errorf("not supported")
+}
+
+// stopProvider streams two text events, then fails once canceled
+type stopProvider struct {
+	usageProvider
+}
+
+func (p *stopProvider) ExecuteStream(ctx context.Context, opts *ExecuteOptions) (<-chan StreamEvent, error) {
+	ch := make(chan StreamEvent)
+	go func() {
+		defer close(ch)
+		ch <- StreamEvent{Type: "text", Text: "working\n"}
+		ch <- StreamEvent{Type: "text", Text: "STATUS: COMPLETE\n"}
+		select {
+		case <-ctx.Done():
+			ch <- StreamEvent{Type: "error", Text: ctx.Err().Error(), Err: ctx.Err()}
+		case <-time.After(5 * time.Second):
+			ch <- StreamEvent{Type: "text", Text: "more output\n"}
+		}
+	}()
+	return ch, nil
+}
+
+func TestTaskExecutorStreamWatcher(t *testing.T) {
+	executor := NewTaskExecutor(&stopProvider{}, ".")
+	executor.SetStreamWatcher(func(events <-chan StreamEvent) bool {
+		for event := range events {
+			if strings.Contains(event.Text, "STATUS: COMPLETE") {
+				return true
+			}
+		}
+		return false
+	})
+
+	result, err := executor.ExecuteTask(context.Background(), &task.Task{ID: "T001"}, "", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Success || result.Output != "working\nSTATUS: COMPLETE\n" {
+		t.Errorf("expected the output up to the stop as a success, got %+v", result)
+	}
+}
+
+func TestTokenBudget(t *testing.T) {
+	budget := TokenBudget{MaxInputTokens: 1000, MaxOutputTokens: 500, MaxTotalCost: 1}
+
+	usage := TokenUsage{TokensIn: 800, TokensOut: 100, Cost: 0.5}
+	if got := budget.Utilisation(usage); got != 0.8 {
+		t.Errorf("expected utilisation 0.8, got %v", got)
+	}
+	if err := budget.Check(usage); err != nil {
+		t.Errorf("expected no error below limits, got %v", err)
+	}
+
+	usage.Cost = 1.2
+	err := budget.Check(usage)
+	if !errors.Is(err, ErrBudgetExceeded) || !strings.Contains(err.Error(), "cost") {
+		t.Errorf("expected cost budget error, got %v", err)
+	}
+
+	if !(TokenBudget{}).IsZero() || (TokenBudget{}).Check(usage) != nil {
+		t.Error("expected empty budget to have no limits")
+	}
+}
+
+func TestTaskExecutorBudget(t *testing.T) {
+	provider := &usageProvider{}
+	executor := NewTaskExecutor(provider, ".")
+	executor.SetBudget(TokenBudget{MaxInputTokens: 1000})
+	tk := &task.Task{ID: "T001", Name: "Budget"}
+
+	for i := 0; i < 2; i++ {
+		if _, err := executor.ExecuteTask(context.Background(), tk, "", false); err != nil {
+			t.Fatalf("execution %d: unexpected error %v", i+1, err)
+		}
+	}
+	if executor.BudgetUtilisation() != 0.8 {
+		t.Errorf("expected utilisation 0.8, got %v", executor.BudgetUtilisation())
+	}
+
+	// Third execution crosses the limit but still returns its result
+	result, err := executor.ExecuteTask(context.Background(), tk, "", false)
+	if !errors.Is(err, ErrBudgetExceeded) || result == nil {
+		t.Fatalf("expected result with ErrBudgetExceeded, got %v, %v", result, err)
+	}
+
+	// Further executions are refused without calling the provider
+	if _, err := executor.ExecuteTask(context.Background(), tk, "", false); !errors.Is(err, ErrBudgetExceeded) {
+		t.Errorf("expected ErrBudgetExceeded, got %v", err)
+	}
+	if provider.calls != 3 {
+		t.Errorf("expected 3 provider calls, got %d", provider.calls)
+	}
+
+	usage := executor.Usage()
+	if usage.TokensIn != 1200 || usage.TokensOut != 150 || usage.Cost != 0.75 {
+		t.Errorf("unexpected usage %+v", usage)
+	}
+}
+
+func TestTokenUsagePersistence(t *testing.T) {
+	dir, err := os.MkdirTemp("", "hermes-ai-test-*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := TokenUsagePath(dir)
+	empty, err := LoadTokenUsage(path)
+	if err != nil || empty.TokensIn != 0 {
+		t.Fatalf("expected empty usage for missing file, got %+v, %v", empty, err)
+	}
+
+	total := empty.Plus(TokenUsage{TokensIn: 10, TokensOut: 5, Cost: 0.1})
+	if err := total.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadTokenUsage(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.TokensIn != 10 || loaded.TokensOut != 5 || loaded.Cost != 0.1 {
+		t.Errorf("unexpected loaded usage %+v", loaded)
+	}
+}
+
+func TestProviderCapabilities(t *testing.T) {
+	for _, provider := range []Provider{NewClaudeProvider(), NewDroidProvider(), NewGeminiProvider()} {
+		caps := provider.Capabilities()
+		if caps.MaxContextTokens <= 0 || caps.MaxParallelRequests <= 0 || !caps.SupportsFunctions {
+			t.Errorf("%s: unexpected capabilities %+v", provider.Name(), caps)
+		}
+	}
+
+	caps := ProviderCapabilities{MaxContextTokens: 10}
+	if tokens, over := caps.ExceedsContext(strings.Repeat("word ", 8)); tokens != 10 || over {
+		t.Errorf("expected 10 tokens within the context, got %d, %v", tokens, over)
+	}
+	if _, over := caps.ExceedsContext(strings.Repeat("word ", 9)); !over {
+		t.Error("expected 45 characters to exceed a 10 token context")
+	}
+	if _, over := (ProviderCapabilities{}).ExceedsContext(strings.Repeat("x", 1000)); over {
+		t.Error("expected no limit without MaxContextTokens")
+	}
+}
+
+func TestExtraArgs(t *testing.T) {
+	args, err := ParseExtraArgs([]string{"model=claude-opus-4-5", "--verbose", "temperature=0.2"})
+	if err != nil {
+		t.Fatalf("ParseExtraArgs failed: %v", err)
+	}
+	expected := []string{"--model", "claude-opus-4-5", "--temperature", "0.2", "--verbose"}
+	if got := extraArgsList(args); strings.Join(got, " ") != strings.Join(expected, " ") {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+
+	if _, err := ParseExtraArgs([]string{"=value"}); err == nil {
+		t.Error("Expected error for an empty key")
+	}
+
+	unsafe := map[string]string{"no-permission-check": ""}
+	if err := ValidateExtraArgs(unsafe, false); err == nil {
+		t.Error("Expected unsafe argument to be rejected")
+	}
+	if err := ValidateExtraArgs(unsafe, true); err != nil {
+		t.Errorf("Expected unsafe argument to be allowed, got %v", err)
+	}
+	if err := ValidateExtraArgs(args, false); err != nil {
+		t.Errorf("Expected safe arguments to pass, got %v", err)
+	}
+}
+
+func TestCommandLine(t *testing.T) {
+	opts := &ExecuteOptions{
+		Prompt:       "Do the task",
+		Tools:        []string{"Read", "Write"},
+		SystemPrompt: "You're terse",
+		ExtraArgs:    map[string]string{"model": "x"},
+	}
+
+	got := FormatCommand(CommandLine(NewClaudeProvider(), opts))
+	expected := "claude --output-format stream-json --verbose --print <prompt> --allowed-tools Read,Write --system-prompt 'You'\\''re terse' --permission-mode bypassPermissions --setting-sources '' --model x"
+	if got != expected {
+		t.Errorf("Expected %s, got %s", expected, got)
+	}
+
+	opts.StreamOutput = true
+	opts.CLIPath = "/usr/local/bin/wrap"
+	if got := FormatCommand(CommandLine(NewClaudeProvider(), opts)); !strings.HasPrefix(got, "/usr/local/bin/wrap --output-format stream-json --verbose --input-format stream-json --allowed-tools") {
+		t.Errorf("Expected the streaming command with the CLI path, got %s", got)
+	}
+
+	opts.CLIPath = ""
+	if got := FormatCommand(CommandLine(NewDroidProvider(), opts)); got != "droid exec --skip-permissions-unsafe --file <prompt-file> --output-format stream-json --model x" {
+		t.Errorf("Unexpected droid command: %s", got)
+	}
+	if got := FormatCommand(CommandLine(NewGeminiProvider(), opts)); got != "gemini -p 'Read <prompt-file> and follow the instructions.' --output-format stream-json --yolo --model x" {
+		t.Errorf("Unexpected gemini command: %s", got)
+	}
+}
+
+func TestGeminiPrompt(t *testing.T) {
+	if got := geminiPrompt(&ExecuteOptions{Prompt: "Do T001"}); got != "Do T001" {
+		t.Errorf("Expected the prompt alone without a system prompt, got %q", got)
+	}
+
+	// The system prompt is prepended rather than replacing the CLI's own
+	got := geminiPrompt(&ExecuteOptions{Prompt: "Do T001", SystemPrompt: "Use British English"})
+	if !strings.HasPrefix(got, "## System Instructions\n\nUse British English\n") || !strings.HasSuffix(got, "\nDo T001") {
+		t.Errorf("Expected the system prompt before the prompt, got %q", got)
+	}
+
+	executor := NewTaskExecutor(NewGeminiProvider(), ".")
+	executor.SetSystemPrompt("Use British English")
+	if opts := executor.TaskOptions(&task.Task{ID: "T001"}, "prompt", false); opts.SystemPrompt != "Use British English" {
+		t.Errorf("Expected the system prompt in the task options, got %q", opts.SystemPrompt)
+	}
+}
+
+func TestOutputFilter(t *testing.T) {
+	output := strings.Join([]string{
+		"Downloading dependency 1",
+		"Editing main.go",
+		"---HERMES_STATUS---",
+		"STATUS: IN_PROGRESS",
+		"Downloading dependency 2",
+		"---END_HERMES_STATUS---",
+		"Downloading dependency 3",
+	}, "\n")
+
+	f, err := NewOutputFilter([]string{"^Downloading"}, 0)
+	if err != nil {
+		t.Fatalf("NewOutputFilter failed: %v", err)
+	}
+	got := f.Apply(output)
+	if strings.Contains(got, "dependency 1") || strings.Contains(got, "dependency 3") {
+		t.Errorf("Expected matching lines to be removed, got:\n%s", got)
+	}
+	if !strings.Contains(got, "dependency 2") || !strings.Contains(got, "STATUS: IN_PROGRESS") {
+		t.Errorf("Expected the status block to be kept, got:\n%s", got)
+	}
+
+	// Cutting to the last 3 lines would split the status block
+	f, _ = NewOutputFilter(nil, 3)
+	got = f.Apply(output)
+	if !strings.HasPrefix(got, "[2 lines truncated]\n---HERMES_STATUS---") {
+		t.Errorf("Expected truncation to keep the whole status block, got:\n%s", got)
+	}
+
+	f, _ = NewOutputFilter(nil, 2)
+	if got := f.Apply("a\nb\nc\nd"); got != "[2 lines truncated]\nc\nd" {
+		t.Errorf("Expected the last 2 lines, got %q", got)
+	}
+
+	if _, err := NewOutputFilter([]string{"("}, 0); err == nil {
+		t.Error("Expected error for an invalid pattern")
+	}
+	if _, err := NewOutputFilter(nil, -1); err == nil {
+		t.Error("Expected error for a negative line limit")
+	}
+	if f, _ := NewOutputFilter(nil, 0); !f.IsEmpty() || f.Apply("x") != "x" {
+		t.Error("Expected an empty filter to keep the output")
+	}
+}
+
+func TestTrimToTokenBudget(t *testing.T) {
+	var lines []string
+	lines = append(lines, "# Instructions", "Follow the guidelines.")
+	for i := 0; i < 50; i++ {
+		lines = append(lines, fmt.Sprintf("## Note %d", i), strings.Repeat("x", 60))
+	}
+	lines = append(lines, "<!-- TASK_START -->", "## Current Task: T001", "<!-- TASK_END -->")
+	content := strings.Join(lines, "\n")
+
+	strategy := TrimStrategy{KeepHeadLines: 2, Protected: [][2]string{{"<!-- TASK_START -->", "<!-- TASK_END -->"}}}
+	if got := TrimToTokenBudget(content, EstimateTokens(content), strategy); got != content {
+		t.Error("expected content within budget to be unchanged")
+	}
+
+	trimmed, report := TrimToTokenBudgetWithReport(content, 300, strategy)
+	if EstimateTokens(trimmed) > 300 {
+		t.Errorf("expected at most 300 tokens, got %d", EstimateTokens(trimmed))
+	}
+	if !strings.HasPrefix(trimmed, "# Instructions\nFollow the guidelines.") {
+		t.Error("expected the head lines to be kept")
+	}
+	if !strings.HasSuffix(trimmed, "<!-- TASK_START -->\n## Current Task: T001\n<!-- TASK_END -->") {
+		t.Error("expected the task section to be kept")
+	}
+	if !strings.Contains(trimmed, "lines trimmed to fit the context budget") {
+		t.Error("expected a marker for the removed lines")
+	}
+	// The middle goes first, so the first and last notes survive
+	if strings.Contains(trimmed, "## Note 25\n") || !strings.Contains(trimmed, "## Note 0\n") || !strings.Contains(trimmed, "## Note 49\n") {
+		t.Errorf("expected middle notes to be removed first, got:\n%s", trimmed)
+	}
+	if report.RemovedLines == 0 || len(report.Headings) == 0 {
+		t.Errorf("expected a report of the removed lines, got %+v", report)
+	}
+
+	// Kept lines are returned even if they alone exceed the budget
+	if got := TrimToTokenBudget(content, 1, strategy); !strings.Contains(got, "## Current Task: T001") {
+		t.Error("expected the task section to be kept at any budget")
+	}
+}
+
+func TestStructuredExtractor(t *testing.T) {
+	output := "Done.\n\n```json\n{\"status\": \"ok\", \"files\": 2}\n```\n\n" +
+		"```yml\nstatus: ignored\nowner: alice\n```\n\n" +
+		"```json\n{not json}\n```\n\n" +
+		"~~~toml\nretries = 3\n~~~\n\n" +
+		"```go\nfunc main() {}\n```\n\n" +
+		"```json\n[1, 2]\n```\n"
+	x := NewStructuredExtractor(output)
+
+	first, ok := x.ExtractFirst("json")
+	if !ok || first.(map[string]interface{})["status"] != "ok" {
+		t.Errorf("unexpected first JSON block: %v", first)
+	}
+	if all := x.ExtractAll("JSON"); len(all) != 2 {
+		t.Errorf("expected 2 valid JSON blocks, got %v", all)
+	}
+	if yaml, ok := x.ExtractFirst("yaml"); !ok || yaml.(map[string]interface{})["owner"] != "alice" {
+		t.Errorf("unexpected YAML block: %v", yaml)
+	}
+	if _, ok := x.ExtractFirst("go"); ok {
+		t.Error("expected code blocks to be ignored")
+	}
+
+	fields := x.Fields()
+	if fields["status"] != "ok" || fields["owner"] != "alice" || fields["retries"] != int64(3) || fields["files"] != float64(2) {
+		t.Errorf("unexpected fields: %v", fields)
+	}
+	if NewStructuredExtractor("no blocks").Fields() != nil {
+		t.Error("expected nil fields without structured blocks")
+	}
+
+	// Unclosed blocks run to the end of the output
+	if value, ok := NewStructuredExtractor("```json\n{\"a\": 1}").ExtractFirst("json"); !ok || value.(map[string]interface{})["a"] != float64(1) {
+		t.Errorf("unexpected unclosed block: %v", value)
+	}
+
+	result, _ := NewTaskExecutor(&flakyProvider{}, ".").processOutput(&ExecuteResult{Output: output}, nil)
+	if result.Structured["status"] != "ok" {
+		t.Errorf("expected Structured to be set, got %v", result.Structured)
+	}
+}
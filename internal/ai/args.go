@@ -0,0 +1,64 @@
+package ai
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// UnsafeArgs are CLI flags that disable the AI CLI's safety checks. They are
+// rejected as extra arguments unless explicitly allowed.
+var UnsafeArgs = []string{
+	"no-permission-check",
+	"dangerously-skip-permissions",
+	"allow-dangerously-skip-permissions",
+}
+
+// ParseExtraArgs parses key=value pairs into extra CLI arguments. A key
+// without "=" is a flag without a value. Leading dashes are ignored, so
+// "model=x" and "--model=x" both give --model x.
+func ParseExtraArgs(pairs []string) (map[string]string, error) {
+	args := make(map[string]string)
+	for _, pair := range pairs {
+		key, value, _ := strings.Cut(pair, "=")
+		key = strings.TrimLeft(strings.TrimSpace(key), "-")
+		if key == "" || strings.ContainsAny(key, " \t") {
+			return nil, fmt.Errorf("invalid AI argument %q (use key=value)", pair)
+		}
+		args[key] = value
+	}
+	return args, nil
+}
+
+// ValidateExtraArgs rejects the UnsafeArgs unless allowUnsafe is set
+func ValidateExtraArgs(args map[string]string, allowUnsafe bool) error {
+	if allowUnsafe {
+		return nil
+	}
+	for _, unsafe := range UnsafeArgs {
+		for key := range args {
+			if strings.TrimLeft(key, "-") == unsafe {
+				return fmt.Errorf("AI argument --%s disables safety checks (use --allow-unsafe-args to pass it anyway)", unsafe)
+			}
+		}
+	}
+	return nil
+}
+
+// extraArgsList returns the extra arguments as CLI flags, sorted by name
+func extraArgsList(args map[string]string) []string {
+	keys := make([]string, 0, len(args))
+	for key := range args {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var list []string
+	for _, key := range keys {
+		list = append(list, "--"+strings.TrimLeft(key, "-"))
+		if args[key] != "" {
+			list = append(list, args[key])
+		}
+	}
+	return list
+}
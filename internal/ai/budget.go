@@ -0,0 +1,126 @@
+package ai
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// BudgetWarnThreshold is the budget utilisation at which callers should warn
+const BudgetWarnThreshold = 0.8
+
+// ErrBudgetExceeded is returned by TaskExecutor once a TokenBudget limit is hit
+var ErrBudgetExceeded = errors.New("token budget exceeded")
+
+// TokenBudget limits the tokens and cost spent by a TaskExecutor (0 = no limit)
+type TokenBudget struct {
+	MaxInputTokens  int
+	MaxOutputTokens int
+	MaxTotalCost    float64
+}
+
+// TokenUsage is accumulated AI usage
+type TokenUsage struct {
+	TokensIn    int       `json:"tokensIn"`
+	TokensOut   int       `json:"tokensOut"`
+	Cost        float64   `json:"cost"`
+	LastUpdated time.Time `json:"lastUpdated,omitempty"`
+}
+
+// IsZero returns true if the budget has no limits
+func (b TokenBudget) IsZero() bool {
+	return b.MaxInputTokens <= 0 && b.MaxOutputTokens <= 0 && b.MaxTotalCost <= 0
+}
+
+// Utilisation returns the highest fraction of any limit used so far
+func (b TokenBudget) Utilisation(u TokenUsage) float64 {
+	var highest float64
+	if b.MaxInputTokens > 0 {
+		highest = max(highest, float64(u.TokensIn)/float64(b.MaxInputTokens))
+	}
+	if b.MaxOutputTokens > 0 {
+		highest = max(highest, float64(u.TokensOut)/float64(b.MaxOutputTokens))
+	}
+	if b.MaxTotalCost > 0 {
+		highest = max(highest, u.Cost/b.MaxTotalCost)
+	}
+	return highest
+}
+
+// Check returns an error wrapping ErrBudgetExceeded if usage reached any limit
+func (b TokenBudget) Check(u TokenUsage) error {
+	var hit []string
+	if b.MaxInputTokens > 0 && u.TokensIn >= b.MaxInputTokens {
+		hit = append(hit, fmt.Sprintf("input tokens %d/%d", u.TokensIn, b.MaxInputTokens))
+	}
+	if b.MaxOutputTokens > 0 && u.TokensOut >= b.MaxOutputTokens {
+		hit = append(hit, fmt.Sprintf("output tokens %d/%d", u.TokensOut, b.MaxOutputTokens))
+	}
+	if b.MaxTotalCost > 0 && u.Cost >= b.MaxTotalCost {
+		hit = append(hit, fmt.Sprintf("cost $%.4f/$%.2f", u.Cost, b.MaxTotalCost))
+	}
+	if len(hit) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%w: %s", ErrBudgetExceeded, strings.Join(hit, ", "))
+}
+
+// Add accumulates the usage of an execution result
+func (u *TokenUsage) Add(r *ExecuteResult) {
+	if r == nil {
+		return
+	}
+	u.TokensIn += r.TokensIn
+	u.TokensOut += r.TokensOut
+	u.Cost += r.Cost
+}
+
+// Plus returns the sum of two usages
+func (u TokenUsage) Plus(other TokenUsage) TokenUsage {
+	return TokenUsage{
+		TokensIn:  u.TokensIn + other.TokensIn,
+		TokensOut: u.TokensOut + other.TokensOut,
+		Cost:      u.Cost + other.Cost,
+	}
+}
+
+// TokenUsagePath returns the path of the persisted cumulative token usage
+func TokenUsagePath(workDir string) string {
+	return filepath.Join(workDir, ".hermes", "token-usage.json")
+}
+
+// Save writes the usage to a JSON file
+func (u TokenUsage) Save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create usage directory: %w", err)
+	}
+
+	u.LastUpdated = time.Now()
+	data, err := json.MarshalIndent(u, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode token usage: %w", err)
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// LoadTokenUsage reads token usage from a JSON file. A missing file yields
+// empty usage.
+func LoadTokenUsage(path string) (*TokenUsage, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &TokenUsage{}, nil
+		}
+		return nil, fmt.Errorf("failed to read token usage: %w", err)
+	}
+
+	var u TokenUsage
+	if err := json.Unmarshal(data, &u); err != nil {
+		return nil, fmt.Errorf("failed to parse token usage: %w", err)
+	}
+	return &u, nil
+}
@@ -3,6 +3,8 @@ package ai
 import (
 	"context"
 	"os/exec"
+	"strconv"
+	"strings"
 	"time"
 
 	claudecode "github.com/severity1/claude-code-sdk-go"
@@ -27,6 +29,18 @@ func (p *ClaudeProvider) IsAvailable() bool {
 	return err == nil
 }
 
+// Capabilities returns what the Claude CLI's default model supports
+func (p *ClaudeProvider) Capabilities() ProviderCapabilities {
+	return ProviderCapabilities{
+		MaxContextTokens:     200000,
+		SupportsFunctions:    true,
+		SupportsVision:       true,
+		SupportedFileTypes:   []string{".png", ".jpg", ".jpeg", ".gif", ".webp", ".pdf"},
+		MaxParallelRequests:  5,
+		SupportsSystemPrompt: true,
+	}
+}
+
 // Execute runs a prompt and returns the result
 func (p *ClaudeProvider) Execute(ctx context.Context, opts *ExecuteOptions) (*ExecuteResult, error) {
 	start := time.Now()
@@ -40,6 +54,7 @@ func (p *ClaudeProvider) Execute(ctx context.Context, opts *ExecuteOptions) (*Ex
 		return &ExecuteResult{
 			Success:  false,
 			Error:    err.Error(),
+			Cause:    err,
 			Duration: time.Since(start).Seconds(),
 		}, err
 	}
@@ -94,6 +109,7 @@ func (p *ClaudeProvider) ExecuteStream(ctx context.Context, opts *ExecuteOptions
 			events <- StreamEvent{
 				Type: "error",
 				Text: err.Error(),
+				Err:  err,
 			}
 		}
 	}()
@@ -122,9 +138,51 @@ func (p *ClaudeProvider) buildOptions(opts *ExecuteOptions) []claudecode.Option
 		sdkOpts = append(sdkOpts, claudecode.WithSystemPrompt(opts.SystemPrompt))
 	}
 
+	if opts.CLIPath != "" {
+		sdkOpts = append(sdkOpts, claudecode.WithCLIPath(opts.CLIPath))
+	}
+
+	if len(opts.ExtraArgs) > 0 {
+		// The SDK adds the dashes; an empty value is a flag without a value
+		extra := make(map[string]*string, len(opts.ExtraArgs))
+		for key, value := range opts.ExtraArgs {
+			value := value
+			if value == "" {
+				extra[strings.TrimLeft(key, "-")] = nil
+			} else {
+				extra[strings.TrimLeft(key, "-")] = &value
+			}
+		}
+		sdkOpts = append(sdkOpts, claudecode.WithExtraArgs(extra))
+	}
+
 	return sdkOpts
 }
 
+// claudeArgs returns the arguments the SDK passes to the claude CLI for the
+// options of buildOptions, in the SDK's order. Execute passes the prompt as
+// an argument, ExecuteStream sends it on stdin.
+func claudeArgs(opts *ExecuteOptions) []string {
+	args := []string{"--output-format", "stream-json", "--verbose"}
+	if opts.StreamOutput {
+		args = append(args, "--input-format", "stream-json")
+	} else {
+		args = append(args, "--print", PromptArg)
+	}
+	if len(opts.Tools) > 0 {
+		args = append(args, "--allowed-tools", strings.Join(opts.Tools, ","))
+	}
+	if opts.SystemPrompt != "" {
+		args = append(args, "--system-prompt", opts.SystemPrompt)
+	}
+	args = append(args, "--permission-mode", string(claudecode.PermissionModeBypassPermissions))
+	if opts.MaxTurns > 0 {
+		args = append(args, "--max-turns", strconv.Itoa(opts.MaxTurns))
+	}
+	args = append(args, "--setting-sources", "")
+	return append(args, extraArgsList(opts.ExtraArgs)...)
+}
+
 func (p *ClaudeProvider) processMessage(msg claudecode.Message, result *ExecuteResult) {
 	switch m := msg.(type) {
 	case *claudecode.AssistantMessage:
@@ -140,6 +198,9 @@ func (p *ClaudeProvider) processMessage(msg claudecode.Message, result *ExecuteR
 		if m.TotalCostUSD != nil {
 			result.Cost = *m.TotalCostUSD
 		}
+		if m.Usage != nil {
+			result.TokensIn, result.TokensOut = usageTokens(*m.Usage)
+		}
 		result.Duration = float64(m.DurationMs) / 1000
 	}
 }
@@ -175,11 +236,30 @@ func (p *ClaudeProvider) processStreamMessage(msg claudecode.Message, events cha
 		if m.TotalCostUSD != nil {
 			cost = *m.TotalCostUSD
 		}
+		var tokensIn, tokensOut int
+		if m.Usage != nil {
+			tokensIn, tokensOut = usageTokens(*m.Usage)
+		}
 		events <- StreamEvent{
-			Type:     "result",
-			Text:     text,
-			Cost:     cost,
-			Duration: float64(m.DurationMs) / 1000,
+			Type:      "result",
+			Text:      text,
+			Cost:      cost,
+			Duration:  float64(m.DurationMs) / 1000,
+			TokensIn:  tokensIn,
+			TokensOut: tokensOut,
+		}
+	}
+}
+
+// usageTokens returns the input (including cache) and output tokens of a
+// Claude usage object
+func usageTokens(usage map[string]any) (int, int) {
+	count := func(key string) int {
+		if v, ok := usage[key].(float64); ok {
+			return int(v)
 		}
+		return 0
 	}
+	in := count("input_tokens") + count("cache_creation_input_tokens") + count("cache_read_input_tokens")
+	return in, count("output_tokens")
 }
@@ -0,0 +1,53 @@
+package ai
+
+import "strings"
+
+// Placeholders for the prompt in a CommandLine
+const (
+	PromptArg  = "<prompt>"      // The prompt, passed as an argument
+	PromptFile = "<prompt-file>" // The temporary file the prompt is written to
+)
+
+// CommandLine returns the command a provider runs for opts: the CLI
+// followed by its arguments, with the prompt shown as PromptArg or
+// PromptFile. It returns nil for a provider that runs no CLI.
+func CommandLine(provider Provider, opts *ExecuteOptions) []string {
+	var args []string
+	switch provider.Name() {
+	case "claude":
+		args = claudeArgs(opts)
+	case "droid":
+		args = droidArgs(PromptFile, opts)
+	case "gemini":
+		format := "json"
+		if opts.StreamOutput {
+			format = "stream-json"
+		}
+		args = geminiArgs(PromptFile, format, opts)
+	default:
+		return nil
+	}
+	return append([]string{cliCommand(opts, provider.Name())}, args...)
+}
+
+// FormatCommand joins a command line for a POSIX shell, quoting the
+// arguments that need it. Prompt placeholders are left as they are.
+func FormatCommand(args []string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		quoted[i] = shellQuote(arg)
+	}
+	return strings.Join(quoted, " ")
+}
+
+// shellQuote quotes an argument in single quotes unless it only contains
+// characters that are safe in a shell
+func shellQuote(arg string) string {
+	if arg == PromptArg || arg == PromptFile {
+		return arg
+	}
+	if arg != "" && strings.Trim(arg, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_=+,.:/@%") == "" {
+		return arg
+	}
+	return "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+}
@@ -43,6 +43,16 @@ type droidStreamEvent struct {
 	FinalText  string                 `json:"finalText,omitempty"`
 }
 
+// Capabilities returns what the Droid CLI's default model supports
+func (p *DroidProvider) Capabilities() ProviderCapabilities {
+	return ProviderCapabilities{
+		MaxContextTokens:    200000,
+		SupportsFunctions:   true,
+		SupportsVision:      false,
+		MaxParallelRequests: 3,
+	}
+}
+
 // Execute runs a prompt and returns the result
 func (p *DroidProvider) Execute(ctx context.Context, opts *ExecuteOptions) (*ExecuteResult, error) {
 	start := time.Now()
@@ -60,13 +70,7 @@ func (p *DroidProvider) Execute(ctx context.Context, opts *ExecuteOptions) (*Exe
 	}
 	tmpFile.Close()
 
-	// Build command
-	args := []string{"exec", "--skip-permissions-unsafe", "--file", tmpFile.Name()}
-
-	// Add output format for parsing
-	args = append(args, "--output-format", "stream-json")
-
-	cmd := exec.CommandContext(ctx, "droid", args...)
+	cmd := exec.CommandContext(ctx, cliCommand(opts, "droid"), droidArgs(tmpFile.Name(), opts)...)
 
 	if opts.WorkDir != "" {
 		cmd.Dir = opts.WorkDir
@@ -117,6 +121,7 @@ func (p *DroidProvider) Execute(ctx context.Context, opts *ExecuteOptions) (*Exe
 	if err := cmd.Wait(); err != nil {
 		result.Success = false
 		result.Error = err.Error()
+		result.Cause = err
 	}
 
 	if result.Duration == 0 {
@@ -126,6 +131,13 @@ func (p *DroidProvider) Execute(ctx context.Context, opts *ExecuteOptions) (*Exe
 	return result, nil
 }
 
+// droidArgs returns the droid CLI arguments for a prompt written to
+// promptFile. Output is always stream-json for parsing.
+func droidArgs(promptFile string, opts *ExecuteOptions) []string {
+	args := []string{"exec", "--skip-permissions-unsafe", "--file", promptFile, "--output-format", "stream-json"}
+	return append(args, extraArgsList(opts.ExtraArgs)...)
+}
+
 // ExecuteStream runs a prompt with streaming output
 func (p *DroidProvider) ExecuteStream(ctx context.Context, opts *ExecuteOptions) (<-chan StreamEvent, error) {
 	events := make(chan StreamEvent, 100)
@@ -149,9 +161,7 @@ func (p *DroidProvider) ExecuteStream(ctx context.Context, opts *ExecuteOptions)
 		tmpFile.Close()
 
 		// Build command
-		args := []string{"exec", "--skip-permissions-unsafe", "--file", tmpFile.Name(), "--output-format", "stream-json"}
-
-		cmd := exec.CommandContext(ctx, "droid", args...)
+		cmd := exec.CommandContext(ctx, cliCommand(opts, "droid"), droidArgs(tmpFile.Name(), opts)...)
 
 		if opts.WorkDir != "" {
 			cmd.Dir = opts.WorkDir
@@ -216,7 +226,7 @@ func (p *DroidProvider) ExecuteStream(ctx context.Context, opts *ExecuteOptions)
 		}
 
 		if err := cmd.Wait(); err != nil {
-			events <- StreamEvent{Type: "error", Text: err.Error()}
+			events <- StreamEvent{Type: "error", Text: err.Error(), Err: err}
 		}
 	}()
 
@@ -3,6 +3,8 @@ package ai
 import (
 	"context"
 	"fmt"
+	"sync/atomic"
+	"time"
 
 	"hermes/internal/task"
 )
@@ -11,6 +13,14 @@ import (
 type TaskExecutor struct {
 	provider Provider
 	workDir  string
+	cliPath  string
+	extra    map[string]string
+	system   string
+	filter   *OutputFilter
+	budget   TokenBudget
+	usage    TokenUsage
+	calls    int
+	watch    func(events <-chan StreamEvent) bool
 }
 
 // NewTaskExecutor creates a new task executor
@@ -21,66 +31,222 @@ func NewTaskExecutor(provider Provider, workDir string) *TaskExecutor {
 	}
 }
 
+// SetWorkDir changes the directory the provider runs in
+func (e *TaskExecutor) SetWorkDir(dir string) {
+	e.workDir = dir
+}
+
+// SetCLIPath makes the provider run the given executable instead of its own
+// CLI, e.g. a wrapper that runs the CLI inside a container
+func (e *TaskExecutor) SetCLIPath(path string) {
+	e.cliPath = path
+}
+
+// SetExtraArgs sets additional CLI flags passed to the provider
+func (e *TaskExecutor) SetExtraArgs(args map[string]string) {
+	e.extra = args
+}
+
+// SetSystemPrompt sets the system prompt of every task execution, for
+// providers that support one
+func (e *TaskExecutor) SetSystemPrompt(prompt string) {
+	e.system = prompt
+}
+
+// SetOutputFilter sets the filter applied to the output of every execution
+func (e *TaskExecutor) SetOutputFilter(f *OutputFilter) {
+	e.filter = f
+}
+
+// SetStreamWatcher sets a function that reads the events of streamed
+// executions as they arrive. When it returns true, e.g. once the response
+// is complete, the AI is stopped and the output so far is the result.
+func (e *TaskExecutor) SetStreamWatcher(watch func(events <-chan StreamEvent) bool) {
+	e.watch = watch
+}
+
+// SetBudget limits the usage of this executor. Once a limit is reached,
+// executions return ErrBudgetExceeded.
+func (e *TaskExecutor) SetBudget(b TokenBudget) {
+	e.budget = b
+}
+
+// Usage returns the tokens and cost used by this executor so far
+func (e *TaskExecutor) Usage() TokenUsage {
+	return e.usage
+}
+
+// Calls returns the number of provider calls made by this executor,
+// including retries
+func (e *TaskExecutor) Calls() int {
+	return e.calls
+}
+
+// BudgetUtilisation returns the highest fraction of any budget limit used
+func (e *TaskExecutor) BudgetUtilisation() float64 {
+	return e.budget.Utilisation(e.usage)
+}
+
+// trackUsage accumulates the usage of an execution and reports a budget
+// overrun unless the execution already failed
+func (e *TaskExecutor) trackUsage(result *ExecuteResult, err error) (*ExecuteResult, error) {
+	e.calls++
+	e.usage.Add(result)
+	if err != nil {
+		return result, err
+	}
+	return result, e.budget.Check(e.usage)
+}
+
 // ExecuteTask executes a single task
 func (e *TaskExecutor) ExecuteTask(ctx context.Context, t *task.Task, promptContent string, streamOutput bool) (*ExecuteResult, error) {
-	prompt := e.buildTaskPrompt(t, promptContent)
+	if err := e.budget.Check(e.usage); err != nil {
+		return nil, err
+	}
 
-	opts := &ExecuteOptions{
-		Prompt:       prompt,
+	opts := e.TaskOptions(t, promptContent, streamOutput)
+	if streamOutput {
+		return e.processOutput(e.trackUsage(e.executeWithStreaming(ctx, opts)))
+	}
+
+	return e.processOutput(e.trackUsage(e.provider.Execute(ctx, opts)))
+}
+
+// TaskOptions returns the options ExecuteTask passes to the provider for a
+// task
+func (e *TaskExecutor) TaskOptions(t *task.Task, promptContent string, streamOutput bool) *ExecuteOptions {
+	return &ExecuteOptions{
+		Prompt:       e.buildTaskPrompt(t, promptContent),
 		WorkDir:      e.workDir,
 		Tools:        []string{"Read", "Write", "Edit", "Bash", "Glob", "Grep"},
+		SystemPrompt: e.system,
 		StreamOutput: streamOutput,
+		CLIPath:      e.cliPath,
+		ExtraArgs:    e.extra,
 	}
+}
 
-	if streamOutput {
-		return e.executeWithStreaming(ctx, opts)
+// processOutput applies the output filter to the result of an execution
+// and extracts the structured data of the filtered output
+func (e *TaskExecutor) processOutput(result *ExecuteResult, err error) (*ExecuteResult, error) {
+	if result == nil {
+		return result, err
 	}
+	if !e.filter.IsEmpty() {
+		result.Output = e.filter.Apply(result.Output)
+	}
+	result.Structured = NewStructuredExtractor(result.Output).Fields()
+	return result, err
+}
 
-	return e.provider.Execute(ctx, opts)
+// ExecuteTaskWithRetry executes a task like ExecuteTask and repeats it with
+// the back-off of cfg while it fails with a retryable error (see
+// IsRetryable). cfg.MaxRetries is the number of attempts; other failures
+// are returned at once.
+func (e *TaskExecutor) ExecuteTaskWithRetry(ctx context.Context, t *task.Task, promptContent string, streamOutput bool, cfg RetryConfig) (*ExecuteResult, error) {
+	attempts := cfg.MaxRetries
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	for attempt := 1; ; attempt++ {
+		result, err := e.ExecuteTask(ctx, t, promptContent, streamOutput)
+
+		failure := err
+		if failure == nil && result != nil && !result.Success {
+			failure = result.Cause
+		}
+		if failure == nil || !IsRetryable(failure) || attempt >= attempts {
+			if err != nil && attempt > 1 {
+				err = fmt.Errorf("failed after %d attempts: %w", attempt, err)
+			}
+			return result, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return result, ctx.Err()
+		case <-time.After(cfg.DelayFor(attempt - 1)):
+		}
+	}
 }
 
 // executeWithStreaming executes with real-time output to console
 func (e *TaskExecutor) executeWithStreaming(ctx context.Context, opts *ExecuteOptions) (*ExecuteResult, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
 	events, err := e.provider.ExecuteStream(ctx, opts)
 	if err != nil {
 		return nil, err
 	}
 
+	var stopped atomic.Bool
+	var watched chan StreamEvent
+	if e.watch != nil {
+		watched = make(chan StreamEvent)
+		done := make(chan struct{})
+		defer func() {
+			close(watched)
+			<-done
+		}()
+		go func() {
+			defer close(done)
+			if e.watch(watched) {
+				stopped.Store(true)
+				cancel()
+			}
+			for range watched {
+				// Keep the stream flowing after the watcher returned
+			}
+		}()
+	}
+
 	var output string
+	result := &ExecuteResult{Success: true}
 	for event := range events {
+		if watched != nil {
+			watched <- event
+		}
 		switch event.Type {
 		case "text":
 			fmt.Print(event.Text)
 			output += event.Text
+		case "result":
+			result.Cost += event.Cost
+			result.TokensIn += event.TokensIn
+			result.TokensOut += event.TokensOut
 		case "error":
-			return &ExecuteResult{Success: false, Output: output, Error: event.Text}, nil
+			if stopped.Load() {
+				continue // Canceled by the watcher
+			}
+			result.Success = false
+			result.Output = output
+			result.Error = event.Text
+			result.Cause = event.Err
+			return result, nil
 		case "done":
 			fmt.Println()
 		}
 	}
 
-	return &ExecuteResult{Success: true, Output: output}, nil
+	result.Output = output
+	return result, nil
 }
 
 // ExecuteTaskStream executes a task with streaming output
 func (e *TaskExecutor) ExecuteTaskStream(ctx context.Context, t *task.Task, promptContent string) (<-chan StreamEvent, error) {
-	prompt := e.buildTaskPrompt(t, promptContent)
-
-	opts := &ExecuteOptions{
-		Prompt:  prompt,
-		WorkDir: e.workDir,
-		Tools:   []string{"Read", "Write", "Edit", "Bash", "Glob", "Grep"},
-	}
-
-	return e.provider.ExecuteStream(ctx, opts)
+	return e.provider.ExecuteStream(ctx, e.TaskOptions(t, promptContent, false))
 }
 
 // ExecutePrompt executes a raw prompt without task context
 func (e *TaskExecutor) ExecutePrompt(ctx context.Context, prompt string, taskID string) (*ExecuteResult, error) {
 	opts := &ExecuteOptions{
-		Prompt:  prompt,
-		WorkDir: e.workDir,
-		Tools:   []string{"Read"}, // Limited tools for merge operations
+		Prompt:    prompt,
+		WorkDir:   e.workDir,
+		Tools:     []string{"Read"}, // Limited tools for merge operations
+		CLIPath:   e.cliPath,
+		ExtraArgs: e.extra,
 	}
 
 	return e.provider.Execute(ctx, opts)
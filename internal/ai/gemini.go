@@ -84,6 +84,18 @@ type geminiStreamEvent struct {
 	} `json:"stats,omitempty"`
 }
 
+// Capabilities returns what the Gemini CLI's default model supports
+func (p *GeminiProvider) Capabilities() ProviderCapabilities {
+	return ProviderCapabilities{
+		MaxContextTokens:     1000000,
+		SupportsFunctions:    true,
+		SupportsVision:       true,
+		SupportedFileTypes:   []string{".png", ".jpg", ".jpeg", ".webp", ".pdf", ".mp3", ".wav", ".mp4"},
+		MaxParallelRequests:  4,
+		SupportsSystemPrompt: true,
+	}
+}
+
 // Execute runs a prompt and returns the result
 func (p *GeminiProvider) Execute(ctx context.Context, opts *ExecuteOptions) (*ExecuteResult, error) {
 	start := time.Now()
@@ -95,21 +107,14 @@ func (p *GeminiProvider) Execute(ctx context.Context, opts *ExecuteOptions) (*Ex
 	}
 	defer os.Remove(tmpFile.Name())
 
-	if _, err := tmpFile.WriteString(opts.Prompt); err != nil {
+	if _, err := tmpFile.WriteString(geminiPrompt(opts)); err != nil {
 		tmpFile.Close()
 		return nil, fmt.Errorf("failed to write prompt: %w", err)
 	}
 	tmpFile.Close()
 
 	// Build command - use headless mode with JSON output
-	// gemini -p "prompt" --output-format json --yolo (auto-approve)
-	args := []string{
-		"-p", fmt.Sprintf("Read %s and follow the instructions.", tmpFile.Name()),
-		"--output-format", "json",
-		"--yolo", // Auto-approve all actions
-	}
-
-	cmd := exec.CommandContext(ctx, "gemini", args...)
+	cmd := exec.CommandContext(ctx, cliCommand(opts, "gemini"), geminiArgs(tmpFile.Name(), "json", opts)...)
 
 	if opts.WorkDir != "" {
 		cmd.Dir = opts.WorkDir
@@ -122,6 +127,7 @@ func (p *GeminiProvider) Execute(ctx context.Context, opts *ExecuteOptions) (*Ex
 			return &ExecuteResult{
 				Success:  false,
 				Error:    string(exitErr.Stderr),
+				Cause:    exitErr,
 				Duration: time.Since(start).Seconds(),
 			}, nil
 		}
@@ -163,6 +169,29 @@ func (p *GeminiProvider) Execute(ctx context.Context, opts *ExecuteOptions) (*Ex
 	}, nil
 }
 
+// geminiArgs returns the gemini CLI arguments for a prompt written to
+// promptFile: gemini -p "Read <file> ..." --output-format <format> --yolo
+// (auto-approve all actions)
+func geminiArgs(promptFile, outputFormat string, opts *ExecuteOptions) []string {
+	args := []string{
+		"-p", fmt.Sprintf("Read %s and follow the instructions.", promptFile),
+		"--output-format", outputFormat,
+		"--yolo",
+	}
+	return append(args, extraArgsList(opts.ExtraArgs)...)
+}
+
+// geminiPrompt returns the content of the prompt file: the prompt, preceded
+// by opts.SystemPrompt if there is one. The Gemini CLI can only replace its
+// whole built-in system prompt (GEMINI_SYSTEM_MD), which would drop its
+// tool-use instructions, so the system prompt is prepended instead.
+func geminiPrompt(opts *ExecuteOptions) string {
+	if opts.SystemPrompt == "" {
+		return opts.Prompt
+	}
+	return "## System Instructions\n\n" + opts.SystemPrompt + "\n\n---\n\n" + opts.Prompt
+}
+
 // ExecuteStream runs a prompt with streaming output
 func (p *GeminiProvider) ExecuteStream(ctx context.Context, opts *ExecuteOptions) (<-chan StreamEvent, error) {
 	events := make(chan StreamEvent, 100)
@@ -178,7 +207,7 @@ func (p *GeminiProvider) ExecuteStream(ctx context.Context, opts *ExecuteOptions
 		}
 		defer os.Remove(tmpFile.Name())
 
-		if _, err := tmpFile.WriteString(opts.Prompt); err != nil {
+		if _, err := tmpFile.WriteString(geminiPrompt(opts)); err != nil {
 			tmpFile.Close()
 			events <- StreamEvent{Type: "error", Text: err.Error()}
 			return
@@ -186,13 +215,7 @@ func (p *GeminiProvider) ExecuteStream(ctx context.Context, opts *ExecuteOptions
 		tmpFile.Close()
 
 		// Use streaming output format
-		args := []string{
-			"-p", fmt.Sprintf("Read %s and follow the instructions.", tmpFile.Name()),
-			"--output-format", "stream-json",
-			"--yolo",
-		}
-
-		cmd := exec.CommandContext(ctx, "gemini", args...)
+		cmd := exec.CommandContext(ctx, cliCommand(opts, "gemini"), geminiArgs(tmpFile.Name(), "stream-json", opts)...)
 
 		if opts.WorkDir != "" {
 			cmd.Dir = opts.WorkDir
@@ -260,7 +283,7 @@ func (p *GeminiProvider) ExecuteStream(ctx context.Context, opts *ExecuteOptions
 		}
 
 		if err := cmd.Wait(); err != nil {
-			events <- StreamEvent{Type: "error", Text: err.Error()}
+			events <- StreamEvent{Type: "error", Text: err.Error(), Err: err}
 		}
 	}()
 
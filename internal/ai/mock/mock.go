@@ -0,0 +1,192 @@
+// Package mock provides an ai.Provider returning canned results, for tests
+// that must not depend on an installed AI CLI
+package mock
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"hermes/internal/ai"
+)
+
+// Ensure MockProvider satisfies the interface
+var _ ai.Provider = (*MockProvider)(nil)
+
+// response is a canned result returned for prompts containing match
+type response struct {
+	match  string
+	result *ai.ExecuteResult
+	err    error
+}
+
+// MockProvider returns canned results keyed by a prompt substring. Task
+// prompts contain the task ID, so a task ID works as a key too. It is safe
+// for concurrent use by worker pools.
+type MockProvider struct {
+	// Delay is waited before each event sent by ExecuteStream
+	Delay time.Duration
+
+	// Caps is returned by Capabilities; the zero value has no limits
+	Caps ai.ProviderCapabilities
+
+	mu        sync.Mutex
+	responses []response
+	fallback  *ai.ExecuteResult
+	calls     []string
+}
+
+// NewMockProvider creates a mock provider that succeeds with empty output
+// for prompts without a matching response
+func NewMockProvider() *MockProvider {
+	return &MockProvider{
+		fallback: &ai.ExecuteResult{Success: true},
+	}
+}
+
+// SetResponse returns result for prompts containing match. Responses are
+// checked in the order they were set.
+func (p *MockProvider) SetResponse(match string, result *ai.ExecuteResult) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.responses = append(p.responses, response{match: match, result: result})
+}
+
+// SetError makes executions of prompts containing match fail with err
+func (p *MockProvider) SetError(match string, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.responses = append(p.responses, response{match: match, err: err})
+}
+
+// SetDefault sets the result for prompts without a matching response
+func (p *MockProvider) SetDefault(result *ai.ExecuteResult) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.fallback = result
+}
+
+// Name returns the provider name
+func (p *MockProvider) Name() string {
+	return "mock"
+}
+
+// Capabilities returns Caps
+func (p *MockProvider) Capabilities() ai.ProviderCapabilities {
+	return p.Caps
+}
+
+// IsAvailable always returns true
+func (p *MockProvider) IsAvailable() bool {
+	return true
+}
+
+// Execute records the prompt and returns its canned result
+func (p *MockProvider) Execute(ctx context.Context, opts *ai.ExecuteOptions) (*ai.ExecuteResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return p.lookup(opts.Prompt)
+}
+
+// ExecuteStream records the prompt and sends the canned result as a system,
+// assistant and result event, waiting Delay before each
+func (p *MockProvider) ExecuteStream(ctx context.Context, opts *ai.ExecuteOptions) (<-chan ai.StreamEvent, error) {
+	result, err := p.lookup(opts.Prompt)
+	if err != nil {
+		return nil, err
+	}
+
+	events := []ai.StreamEvent{
+		{Type: "system", Model: "mock"},
+		{Type: "assistant", Text: result.Output},
+	}
+	if result.Success {
+		events = append(events, ai.StreamEvent{
+			Type:      "result",
+			Text:      result.Output,
+			Cost:      result.Cost,
+			Duration:  result.Duration,
+			TokensIn:  result.TokensIn,
+			TokensOut: result.TokensOut,
+		})
+	} else {
+		events = append(events, ai.StreamEvent{Type: "error", Text: result.Error})
+	}
+
+	ch := make(chan ai.StreamEvent)
+	go func() {
+		defer close(ch)
+		for _, event := range events {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(p.Delay):
+			}
+			select {
+			case ch <- event:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch, nil
+}
+
+// lookup records a call and returns a copy of the result matching prompt
+func (p *MockProvider) lookup(prompt string) (*ai.ExecuteResult, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.calls = append(p.calls, prompt)
+
+	for _, r := range p.responses {
+		if strings.Contains(prompt, r.match) {
+			if r.err != nil {
+				return nil, r.err
+			}
+			result := *r.result
+			return &result, nil
+		}
+	}
+	result := *p.fallback
+	return &result, nil
+}
+
+// Calls returns the prompts the provider was called with, in order
+func (p *MockProvider) Calls() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]string(nil), p.calls...)
+}
+
+// CallCount returns the number of executions
+func (p *MockProvider) CallCount() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.calls)
+}
+
+// AssertCalled fails the test unless the provider was called at least once
+// and, for each match given, with a prompt containing it
+func (p *MockProvider) AssertCalled(t testing.TB, matches ...string) {
+	t.Helper()
+	calls := p.Calls()
+	if len(calls) == 0 {
+		t.Error("Expected mock provider to be called")
+		return
+	}
+	for _, match := range matches {
+		found := false
+		for _, prompt := range calls {
+			if strings.Contains(prompt, match) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Errorf("Expected mock provider to be called with a prompt containing %q", match)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package mock
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"hermes/internal/ai"
+	"hermes/internal/task"
+)
+
+func TestMockProviderExecute(t *testing.T) {
+	p := NewMockProvider()
+	p.SetResponse("T002", &ai.ExecuteResult{Output: "models done", Success: true, Cost: 0.1})
+	p.SetError("T003", errors.New("rate limited"))
+
+	result, err := p.Execute(context.Background(), &ai.ExecuteOptions{Prompt: "Task T002: Create Models"})
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if result.Output != "models done" || result.Cost != 0.1 {
+		t.Errorf("Unexpected result: %+v", result)
+	}
+
+	if _, err := p.Execute(context.Background(), &ai.ExecuteOptions{Prompt: "Task T003"}); err == nil {
+		t.Error("Expected error for T003")
+	}
+
+	result, err = p.Execute(context.Background(), &ai.ExecuteOptions{Prompt: "something else"})
+	if err != nil || !result.Success || result.Output != "" {
+		t.Errorf("Expected default result, got %+v, %v", result, err)
+	}
+
+	if p.CallCount() != 3 {
+		t.Errorf("Expected 3 calls, got %d", p.CallCount())
+	}
+	p.AssertCalled(t, "T002", "T003")
+}
+
+func TestMockProviderExecuteStream(t *testing.T) {
+	p := NewMockProvider()
+	p.Delay = 5 * time.Millisecond
+	p.SetResponse("hello", &ai.ExecuteResult{Output: "hi", Success: true, TokensIn: 10, TokensOut: 2})
+
+	start := time.Now()
+	events, err := p.ExecuteStream(context.Background(), &ai.ExecuteOptions{Prompt: "say hello"})
+	if err != nil {
+		t.Fatalf("ExecuteStream failed: %v", err)
+	}
+
+	var types []string
+	var last ai.StreamEvent
+	for event := range events {
+		types = append(types, event.Type)
+		last = event
+	}
+	if len(types) != 3 || types[1] != "assistant" || types[2] != "result" {
+		t.Errorf("Unexpected events: %v", types)
+	}
+	if last.TokensIn != 10 || last.TokensOut != 2 {
+		t.Errorf("Expected usage on the result event, got %+v", last)
+	}
+	if time.Since(start) < 15*time.Millisecond {
+		t.Error("Expected events to be delayed")
+	}
+
+	// Cancelling stops the stream
+	ctx, cancel := context.WithCancel(context.Background())
+	p.Delay = time.Hour
+	events, _ = p.ExecuteStream(ctx, &ai.ExecuteOptions{Prompt: "hello"})
+	cancel()
+	for range events {
+	}
+}
+
+func TestTaskExecutorWithMock(t *testing.T) {
+	p := NewMockProvider()
+	p.SetResponse("T001", &ai.ExecuteResult{Output: "STATUS: COMPLETE", Success: true, Cost: 0.2, TokensIn: 100})
+
+	executor := ai.NewTaskExecutor(p, ".")
+	tk := &task.Task{ID: "T001", Name: "Setup", FilesToTouch: []string{"main.go"}}
+
+	result, err := executor.ExecuteTask(context.Background(), tk, "# Prompt", false)
+	if err != nil {
+		t.Fatalf("ExecuteTask failed: %v", err)
+	}
+	if result.Output != "STATUS: COMPLETE" {
+		t.Errorf("Unexpected output: %q", result.Output)
+	}
+
+	// Streaming collects the usage from the result event
+	if _, err := executor.ExecuteTask(context.Background(), tk, "# Prompt", true); err != nil {
+		t.Fatalf("streaming ExecuteTask failed: %v", err)
+	}
+	if usage := executor.Usage(); usage.TokensIn != 200 || usage.Cost != 0.4 {
+		t.Errorf("Expected usage of both executions, got %+v", usage)
+	}
+
+	p.AssertCalled(t, "T001: Setup", "main.go")
+}
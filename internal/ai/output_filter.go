@@ -0,0 +1,115 @@
+package ai
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Markers of the status block that OutputFilter always keeps
+const (
+	statusBlockStart = "---HERMES_STATUS---"
+	statusBlockEnd   = "---END_HERMES_STATUS---"
+)
+
+// OutputFilter removes noise from AI output before it is analyzed and
+// logged: lines matching any pattern are dropped, and long outputs are cut
+// to their last lines. Lines of HERMES_STATUS blocks are never removed.
+type OutputFilter struct {
+	patterns []*regexp.Regexp
+	maxLines int // 0 = no limit
+}
+
+// NewOutputFilter compiles the patterns of an output filter. maxLines
+// limits the output to its last lines (0 = no limit).
+func NewOutputFilter(patterns []string, maxLines int) (*OutputFilter, error) {
+	if maxLines < 0 {
+		return nil, fmt.Errorf("output line limit must not be negative")
+	}
+	f := &OutputFilter{maxLines: maxLines}
+	for _, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid output filter %q: %w", pattern, err)
+		}
+		f.patterns = append(f.patterns, re)
+	}
+	return f, nil
+}
+
+// IsEmpty returns true if the filter changes nothing
+func (f *OutputFilter) IsEmpty() bool {
+	return f == nil || (len(f.patterns) == 0 && f.maxLines == 0)
+}
+
+// String describes the filter, e.g. for the debug log
+func (f *OutputFilter) String() string {
+	if f.IsEmpty() {
+		return "none"
+	}
+	var parts []string
+	for _, re := range f.patterns {
+		parts = append(parts, fmt.Sprintf("drop lines matching %q", re.String()))
+	}
+	if f.maxLines > 0 {
+		parts = append(parts, fmt.Sprintf("keep the last %d lines", f.maxLines))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Apply returns the filtered output
+func (f *OutputFilter) Apply(output string) string {
+	if f.IsEmpty() || output == "" {
+		return output
+	}
+
+	var kept []string
+	inStatus := false
+	lastStatus := -1 // Index in kept of the last status block start
+	for _, line := range strings.Split(output, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == statusBlockStart:
+			inStatus = true
+			lastStatus = len(kept)
+		case trimmed == statusBlockEnd:
+			inStatus = false
+		case !inStatus && f.matches(line):
+			continue
+		}
+		kept = append(kept, line)
+	}
+
+	// Keep the last lines, extended to the start of a status block they cut
+	if f.maxLines > 0 && len(kept) > f.maxLines {
+		start := len(kept) - f.maxLines
+		if lastStatus >= 0 && lastStatus < start && statusBlockEndsAfter(kept, lastStatus, start) {
+			start = lastStatus
+		}
+		if start > 0 {
+			kept = append([]string{fmt.Sprintf("[%d lines truncated]", start)}, kept[start:]...)
+		}
+	}
+	return strings.Join(kept, "\n")
+}
+
+// matches reports whether a line matches any pattern
+func (f *OutputFilter) matches(line string) bool {
+	for _, re := range f.patterns {
+		if re.MatchString(line) {
+			return true
+		}
+	}
+	return false
+}
+
+// statusBlockEndsAfter reports whether the status block starting at
+// lines[start] is still open at lines[index]
+func statusBlockEndsAfter(lines []string, start, index int) bool {
+	for _, line := range lines[start+1 : index] {
+		if strings.TrimSpace(line) == statusBlockEnd {
+			return false
+		}
+	}
+	return true
+}
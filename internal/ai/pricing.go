@@ -0,0 +1,50 @@
+package ai
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// estimatedOutputShare is the share of a task's tokens assumed to be output
+// when estimating cost; agentic runs mostly re-read context
+const estimatedOutputShare = 0.2
+
+// Pricing is the price of a provider's default model in USD per million tokens
+type Pricing struct {
+	InputPerMillion  float64
+	OutputPerMillion float64
+}
+
+// ProviderPricing holds the pricing of each provider by name
+var ProviderPricing = map[string]Pricing{
+	"claude": {InputPerMillion: 3, OutputPerMillion: 15},
+	"droid":  {InputPerMillion: 3, OutputPerMillion: 15},
+	"gemini": {InputPerMillion: 1.25, OutputPerMillion: 10},
+}
+
+// GetPricing returns the pricing of a provider
+func GetPricing(provider string) (Pricing, error) {
+	pricing, ok := ProviderPricing[provider]
+	if !ok {
+		var known []string
+		for name := range ProviderPricing {
+			known = append(known, name)
+		}
+		sort.Strings(known)
+		return Pricing{}, fmt.Errorf("no pricing for provider %s (known: %s)", provider, strings.Join(known, ", "))
+	}
+	return pricing, nil
+}
+
+// Cost returns the cost in USD of the given token counts
+func (p Pricing) Cost(tokensIn, tokensOut int) float64 {
+	return (float64(tokensIn)*p.InputPerMillion + float64(tokensOut)*p.OutputPerMillion) / 1e6
+}
+
+// EstimateCost returns the cost in USD of a total number of tokens, split
+// between input and output by estimatedOutputShare
+func (p Pricing) EstimateCost(tokens int) float64 {
+	tokensOut := int(float64(tokens) * estimatedOutputShare)
+	return p.Cost(tokens-tokensOut, tokensOut)
+}
@@ -10,6 +10,30 @@ type Provider interface {
 	IsAvailable() bool
 	Execute(ctx context.Context, opts *ExecuteOptions) (*ExecuteResult, error)
 	ExecuteStream(ctx context.Context, opts *ExecuteOptions) (<-chan StreamEvent, error)
+	Capabilities() ProviderCapabilities
+}
+
+// ProviderCapabilities describes what a provider's default model supports
+type ProviderCapabilities struct {
+	MaxContextTokens     int      // Context window in tokens
+	SupportsFunctions    bool     // Tool / function calling
+	SupportsVision       bool     // Image input
+	SupportedFileTypes   []string // File extensions the model can read, besides text
+	MaxParallelRequests  int      // Concurrent sessions before rate limiting (0 = no limit)
+	SupportsSystemPrompt bool     // Takes ExecuteOptions.SystemPrompt apart from the prompt
+}
+
+// EstimateTokens roughly estimates the tokens of a text at four characters
+// per token
+func EstimateTokens(text string) int {
+	return (len(text) + 3) / 4
+}
+
+// ExceedsContext reports whether a prompt is estimated to be larger than the
+// context window, returning the estimate
+func (c ProviderCapabilities) ExceedsContext(prompt string) (int, bool) {
+	tokens := EstimateTokens(prompt)
+	return tokens, c.MaxContextTokens > 0 && tokens > c.MaxContextTokens
 }
 
 // ExecuteOptions contains options for AI execution
@@ -19,8 +43,21 @@ type ExecuteOptions struct {
 	Tools        []string // Allowed tools: "Read", "Write", "Bash", etc.
 	MaxTurns     int
 	SystemPrompt string
-	Timeout      int  // Timeout in seconds
-	StreamOutput bool // Enable streaming
+	Timeout      int    // Timeout in seconds
+	StreamOutput bool   // Enable streaming
+	CLIPath      string // Run this executable instead of the provider's CLI (e.g. a container wrapper)
+
+	// ExtraArgs are additional CLI flags by name, e.g. {"model": "claude-opus-4-5"}
+	// for --model claude-opus-4-5. An empty value passes a flag without a value.
+	ExtraArgs map[string]string
+}
+
+// cliCommand returns the CLI to run: opts.CLIPath if set, otherwise name
+func cliCommand(opts *ExecuteOptions, name string) string {
+	if opts.CLIPath != "" {
+		return opts.CLIPath
+	}
+	return name
 }
 
 // ExecuteResult contains the result of AI execution
@@ -32,17 +69,29 @@ type ExecuteResult struct {
 	TokensOut int
 	Success   bool
 	Error     string
+
+	// Cause is the error behind Error when the provider has one, e.g. the
+	// exit status of its CLI (see IsRetryable)
+	Cause error
+
+	// Structured holds the fields of the json, yaml and toml code blocks
+	// of Output that contain an object (see StructuredExtractor), nil if
+	// there are none
+	Structured map[string]interface{}
 }
 
 // StreamEvent represents a streaming event from AI
 type StreamEvent struct {
-	Type     string  // "system", "assistant", "tool_use", "tool_result", "result", "error"
-	Model    string
-	Text     string
-	ToolName string
-	ToolID   string
-	Cost     float64
-	Duration float64
+	Type      string // "system", "assistant", "tool_use", "tool_result", "result", "error"
+	Model     string
+	Text      string
+	ToolName  string
+	ToolID    string
+	Cost      float64
+	Duration  float64
+	TokensIn  int
+	TokensOut int
+	Err       error // The error behind Text of an "error" event, if any
 }
 
 // GetProvider returns a provider by name
@@ -2,26 +2,62 @@ package ai
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math"
+	"math/rand"
+	"net"
+	"os/exec"
+	"regexp"
 	"time"
+
+	claudecode "github.com/severity1/claude-code-sdk-go"
 )
 
+// transientStderrRegex matches the rate limit and overload errors a CLI
+// prints to stderr before it exits with status 1
+var transientStderrRegex = regexp.MustCompile(`(?i)rate[ _-]?limit|too many requests|overloaded|service unavailable|bad gateway|\b(?:status|error|code)\W{0,3}(?:429|502|503|529)\b`)
+
 // RetryConfig contains retry configuration
 type RetryConfig struct {
 	MaxRetries int
 	Delay      time.Duration
-	MaxDelay   time.Duration
+
+	// BackoffMultiplier grows the delay on each retry when greater than 1
+	BackoffMultiplier float64
+	// MaxDelay caps the delay between retries (0 = no cap)
+	MaxDelay time.Duration
+	// Jitter adds ±10% random variation so parallel workers don't retry in lockstep
+	Jitter bool
 }
 
 // DefaultRetryConfig returns default retry configuration
 func DefaultRetryConfig() *RetryConfig {
 	return &RetryConfig{
-		MaxRetries: 3,
-		Delay:      5 * time.Second,
-		MaxDelay:   60 * time.Second,
+		MaxRetries:        3,
+		Delay:             5 * time.Second,
+		BackoffMultiplier: 2,
+		MaxDelay:          60 * time.Second,
+		Jitter:            true,
 	}
 }
 
+// DelayFor returns the wait before the given retry (0 for the first retry).
+// The delay is Delay * BackoffMultiplier^retry, capped at MaxDelay.
+func (c *RetryConfig) DelayFor(retry int) time.Duration {
+	delay := float64(c.Delay)
+	if c.BackoffMultiplier > 1 {
+		delay *= math.Pow(c.BackoffMultiplier, float64(retry))
+	}
+	if c.MaxDelay > 0 && delay > float64(c.MaxDelay) {
+		delay = float64(c.MaxDelay)
+	}
+	if c.Jitter {
+		delay *= 0.9 + rand.Float64()*0.2
+	}
+	return time.Duration(delay)
+}
+
 // ExecuteWithRetry executes with retry logic and exponential backoff
 func ExecuteWithRetry(ctx context.Context, provider Provider, opts *ExecuteOptions, cfg *RetryConfig) (*ExecuteResult, error) {
 	if cfg == nil {
@@ -29,7 +65,6 @@ func ExecuteWithRetry(ctx context.Context, provider Provider, opts *ExecuteOptio
 	}
 
 	var lastErr error
-	delay := cfg.Delay
 
 	for attempt := 1; attempt <= cfg.MaxRetries; attempt++ {
 		var result *ExecuteResult
@@ -56,13 +91,7 @@ func ExecuteWithRetry(ctx context.Context, provider Provider, opts *ExecuteOptio
 			select {
 			case <-ctx.Done():
 				return nil, ctx.Err()
-			case <-time.After(delay):
-			}
-
-			// Exponential backoff
-			delay = delay * 2
-			if delay > cfg.MaxDelay {
-				delay = cfg.MaxDelay
+			case <-time.After(cfg.DelayFor(attempt - 1)):
 			}
 		}
 	}
@@ -92,3 +121,41 @@ func executeWithStreaming(ctx context.Context, provider Provider, opts *ExecuteO
 
 	return &ExecuteResult{Success: true, Output: output}, nil
 }
+
+// IsRetryable reports whether an execution error is likely transient: a
+// deadline or network timeout, a lost connection to the CLI, or a CLI that
+// exits with status 1 after reporting a rate limit or an overloaded API.
+// Budget overruns, cancellations and errors it does not recognise, such as
+// an invalid API key, are not retryable.
+func IsRetryable(err error) bool {
+	if err == nil || errors.Is(err, ErrBudgetExceeded) || errors.Is(err, context.Canceled) {
+		return false
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	var connErr *claudecode.ConnectionError
+	if errors.As(err, &connErr) {
+		return true
+	}
+
+	var processErr *claudecode.ProcessError
+	if errors.As(err, &processErr) {
+		return isTransientExit(processErr.ExitCode, processErr.Stderr)
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return isTransientExit(exitErr.ExitCode(), string(exitErr.Stderr))
+	}
+	return false
+}
+
+// isTransientExit returns true if a CLI exit status and its stderr report a
+// rate limit or an overloaded API
+func isTransientExit(code int, stderr string) bool {
+	return code == 1 && transientStderrRegex.MatchString(stderr)
+}
@@ -0,0 +1,162 @@
+package ai
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+	"go.yaml.in/yaml/v3"
+)
+
+// StructuredExtractor finds the fenced code blocks of an AI response that
+// hold structured data (json, yaml or toml) and unmarshals them. Blocks
+// that do not parse are skipped.
+type StructuredExtractor struct {
+	blocks []structuredBlock
+}
+
+// structuredBlock is the parsed content of one fenced code block
+type structuredBlock struct {
+	lang  string
+	value interface{}
+}
+
+// NewStructuredExtractor parses the structured code blocks of output
+func NewStructuredExtractor(output string) *StructuredExtractor {
+	x := &StructuredExtractor{}
+	for _, block := range fencedBlocks(output) {
+		lang := normalizeLang(block.lang)
+		if value, ok := unmarshalBlock(lang, block.content); ok {
+			x.blocks = append(x.blocks, structuredBlock{lang: lang, value: value})
+		}
+	}
+	return x
+}
+
+// ExtractFirst returns the value of the first block in lang ("json",
+// "yaml" or "toml")
+func (x *StructuredExtractor) ExtractFirst(lang string) (interface{}, bool) {
+	lang = normalizeLang(lang)
+	for _, block := range x.blocks {
+		if block.lang == lang {
+			return block.value, true
+		}
+	}
+	return nil, false
+}
+
+// ExtractAll returns the values of all blocks in lang, in the order they
+// appear
+func (x *StructuredExtractor) ExtractAll(lang string) []interface{} {
+	lang = normalizeLang(lang)
+	var values []interface{}
+	for _, block := range x.blocks {
+		if block.lang == lang {
+			values = append(values, block.value)
+		}
+	}
+	return values
+}
+
+// Fields returns the fields of all blocks holding an object, in any
+// language. A field of an earlier block wins over the same field of a
+// later one. It returns nil if there are none.
+func (x *StructuredExtractor) Fields() map[string]interface{} {
+	var fields map[string]interface{}
+	for _, block := range x.blocks {
+		object, ok := block.value.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if fields == nil {
+			fields = make(map[string]interface{})
+		}
+		for key, value := range object {
+			if _, seen := fields[key]; !seen {
+				fields[key] = value
+			}
+		}
+	}
+	return fields
+}
+
+// fencedBlock is a fenced code block with its language tag
+type fencedBlock struct {
+	lang    string
+	content string
+}
+
+// fencedBlocks returns the ``` and ~~~ fenced code blocks of a Markdown
+// text. An unclosed block runs to the end of the text.
+func fencedBlocks(text string) []fencedBlock {
+	var blocks []fencedBlock
+	var current *fencedBlock
+	var fence string
+	var lines []string
+
+	for _, line := range strings.Split(text, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if current == nil {
+			if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+				fence = trimmed[:3]
+				info := strings.Fields(strings.TrimLeft(trimmed, fence[:1]))
+				current = &fencedBlock{}
+				if len(info) > 0 {
+					current.lang = info[0]
+				}
+				lines = nil
+			}
+			continue
+		}
+		if strings.HasPrefix(trimmed, fence) && strings.Trim(trimmed, fence[:1]) == "" {
+			current.content = strings.Join(lines, "\n")
+			blocks = append(blocks, *current)
+			current = nil
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if current != nil {
+		current.content = strings.Join(lines, "\n")
+		blocks = append(blocks, *current)
+	}
+	return blocks
+}
+
+// normalizeLang lower-cases a language tag and maps its aliases
+func normalizeLang(lang string) string {
+	lang = strings.ToLower(lang)
+	if lang == "yml" {
+		return "yaml"
+	}
+	return lang
+}
+
+// unmarshalBlock parses the content of a block in a structured language
+func unmarshalBlock(lang, content string) (interface{}, bool) {
+	if strings.TrimSpace(content) == "" {
+		return nil, false
+	}
+	switch lang {
+	case "json":
+		var value interface{}
+		if err := json.Unmarshal([]byte(content), &value); err != nil {
+			return nil, false
+		}
+		return value, true
+	case "yaml":
+		var value interface{}
+		if err := yaml.Unmarshal([]byte(content), &value); err != nil {
+			return nil, false
+		}
+		return value, true
+	case "toml":
+		// TOML documents are always tables
+		var value map[string]interface{}
+		if err := toml.Unmarshal([]byte(content), &value); err != nil {
+			return nil, false
+		}
+		return value, true
+	}
+	return nil, false
+}
@@ -0,0 +1,147 @@
+package ai
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// DefaultTrimHeadLines is the number of leading prompt lines, usually the
+// system instructions, that DefaultTrimStrategy always keeps
+const DefaultTrimHeadLines = 20
+
+// TrimStrategy controls what TrimToTokenBudget may remove
+type TrimStrategy struct {
+	KeepHeadLines int         // Leading lines that are always kept
+	Protected     [][2]string // Start and end markers of sections that are always kept
+}
+
+// DefaultTrimStrategy keeps the first DefaultTrimHeadLines lines and the
+// sections between the given start and end markers
+func DefaultTrimStrategy(protected ...[2]string) TrimStrategy {
+	return TrimStrategy{KeepHeadLines: DefaultTrimHeadLines, Protected: protected}
+}
+
+// TrimReport describes what TrimToTokenBudgetWithReport removed
+type TrimReport struct {
+	RemovedLines  int
+	RemovedTokens int      // Estimated
+	Headings      []string // Markdown headings among the removed lines
+}
+
+// trimMarker replaces each run of removed lines
+const trimMarker = "[... %d lines trimmed to fit the context budget ...]"
+
+// TrimToTokenBudget shortens content to about budget tokens, see
+// TrimToTokenBudgetWithReport
+func TrimToTokenBudget(content string, budget int, strategy TrimStrategy) string {
+	trimmed, _ := TrimToTokenBudgetWithReport(content, budget, strategy)
+	return trimmed
+}
+
+// TrimToTokenBudgetWithReport shortens content to about budget tokens by
+// removing lines from the middle outwards, keeping the head lines and the
+// protected sections of the strategy. Each run of removed lines is replaced
+// by a marker. If the kept lines alone exceed the budget, they are returned
+// anyway.
+func TrimToTokenBudgetWithReport(content string, budget int, strategy TrimStrategy) (string, TrimReport) {
+	if budget <= 0 || EstimateTokens(content) <= budget {
+		return content, TrimReport{}
+	}
+
+	lines := strings.Split(content, "\n")
+	var removable []int
+	for i, protected := range protectedLines(lines, strategy) {
+		if !protected {
+			removable = append(removable, i)
+		}
+	}
+
+	// Middle lines go first, then outwards to both ends
+	center := float64(len(removable)-1) / 2
+	order := make([]int, len(removable))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return distance(float64(order[i]), center) < distance(float64(order[j]), center)
+	})
+
+	removed := make([]bool, len(lines))
+	size := len(content)
+	maxSize := budget * 4 // EstimateTokens counts four characters per token
+	for _, pos := range order {
+		idx := removable[pos]
+		removed[idx] = true
+		size -= len(lines[idx]) + 1
+		// Markers only add to the size, so only join once it may fit
+		if size <= maxSize && len(joinTrimmed(lines, removed)) <= maxSize {
+			break
+		}
+	}
+	result := joinTrimmed(lines, removed)
+
+	var report TrimReport
+	for i, line := range lines {
+		if removed[i] {
+			report.RemovedLines++
+			report.RemovedTokens += EstimateTokens(line + "\n")
+			if strings.HasPrefix(line, "#") {
+				report.Headings = append(report.Headings, strings.TrimSpace(line))
+			}
+		}
+	}
+	return result, report
+}
+
+// protectedLines marks the head lines and the lines of protected sections
+func protectedLines(lines []string, strategy TrimStrategy) []bool {
+	protected := make([]bool, len(lines))
+	for i := 0; i < strategy.KeepHeadLines && i < len(lines); i++ {
+		protected[i] = true
+	}
+	for _, markers := range strategy.Protected {
+		inside := false
+		for i, line := range lines {
+			if strings.Contains(line, markers[0]) {
+				inside = true
+			}
+			if inside {
+				protected[i] = true
+			}
+			if strings.Contains(line, markers[1]) {
+				inside = false
+			}
+		}
+	}
+	return protected
+}
+
+// joinTrimmed joins the lines not removed, replacing each run of removed
+// lines with a marker
+func joinTrimmed(lines []string, removed []bool) string {
+	var kept []string
+	run := 0
+	for i, line := range lines {
+		if removed[i] {
+			run++
+			continue
+		}
+		if run > 0 {
+			kept = append(kept, fmt.Sprintf(trimMarker, run))
+			run = 0
+		}
+		kept = append(kept, line)
+	}
+	if run > 0 {
+		kept = append(kept, fmt.Sprintf(trimMarker, run))
+	}
+	return strings.Join(kept, "\n")
+}
+
+func distance(a, b float64) float64 {
+	if a > b {
+		return a - b
+	}
+	return b - a
+}
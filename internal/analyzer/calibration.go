@@ -0,0 +1,254 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// MinCalibrationSamples is the number of predictions needed to fit a
+// calibration
+const MinCalibrationSamples = 10
+
+// maxPredictions is how many predictions analytics.json keeps
+const maxPredictions = 1000
+
+// Prediction is the confidence the analyzer gave one loop of a task
+type Prediction struct {
+	TaskID     string    `json:"taskId"`
+	Loop       int       `json:"loop"`
+	Confidence float64   `json:"confidence"` // Raw, uncalibrated confidence
+	IsComplete bool      `json:"isComplete"` // Whether the loop was analyzed as complete
+	Timestamp  time.Time `json:"timestamp"`
+}
+
+// Analytics is the prediction history kept in .hermes/analytics.json
+type Analytics struct {
+	Predictions []Prediction `json:"predictions"`
+}
+
+// AnalyticsPath returns the path of the prediction history
+func AnalyticsPath(workDir string) string {
+	return filepath.Join(workDir, ".hermes", "analytics.json")
+}
+
+// CalibrationPath returns the path of the fitted confidence calibration
+func CalibrationPath(workDir string) string {
+	return filepath.Join(workDir, ".hermes", "calibration.json")
+}
+
+// LoadAnalytics reads the prediction history. A missing file yields an
+// empty history.
+func LoadAnalytics(path string) (*Analytics, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &Analytics{}, nil
+		}
+		return nil, fmt.Errorf("failed to read analytics: %w", err)
+	}
+
+	var a Analytics
+	if err := json.Unmarshal(data, &a); err != nil {
+		return nil, fmt.Errorf("failed to parse analytics: %w", err)
+	}
+	return &a, nil
+}
+
+// AppendPrediction adds a prediction to the history file, keeping the last
+// maxPredictions
+func AppendPrediction(path string, p Prediction) error {
+	a, err := LoadAnalytics(path)
+	if err != nil {
+		return err
+	}
+	a.Predictions = append(a.Predictions, p)
+	if len(a.Predictions) > maxPredictions {
+		a.Predictions = a.Predictions[len(a.Predictions)-maxPredictions:]
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create analytics directory: %w", err)
+	}
+	data, err := json.MarshalIndent(a, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode analytics: %w", err)
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// Outcomes labels each prediction with whether it was the loop that
+// finished its task: the last loop recorded for a task that is completed
+// now. A task reopened after a wrong "complete" thus counts as a miss.
+func (a *Analytics) Outcomes(completed map[string]bool) []bool {
+	last := make(map[string]int)
+	for i, p := range a.Predictions {
+		last[p.TaskID] = i
+	}
+	outcomes := make([]bool, len(a.Predictions))
+	for i, p := range a.Predictions {
+		outcomes[i] = completed[p.TaskID] && last[p.TaskID] == i
+	}
+	return outcomes
+}
+
+// ConfidenceCalibrator maps raw confidence scores to the probability that
+// the task is complete, by Platt scaling: 1 / (1 + exp(A*raw + B))
+type ConfidenceCalibrator struct {
+	A         float64   `json:"a"`
+	B         float64   `json:"b"`
+	Samples   int       `json:"samples"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// Calibrate returns the calibrated confidence. A nil calibrator returns
+// the raw score.
+func (c *ConfidenceCalibrator) Calibrate(raw float64) float64 {
+	if c == nil {
+		return raw
+	}
+	return 1 / (1 + math.Exp(c.A*raw+c.B))
+}
+
+// FitCalibrator fits a calibration to raw confidence scores and whether
+// each turned out complete. It needs MinCalibrationSamples scores and both
+// outcomes.
+func FitCalibrator(scores []float64, outcomes []bool) (*ConfidenceCalibrator, error) {
+	if len(scores) != len(outcomes) {
+		return nil, fmt.Errorf("got %d scores but %d outcomes", len(scores), len(outcomes))
+	}
+	if len(scores) < MinCalibrationSamples {
+		return nil, fmt.Errorf("not enough history to calibrate: %d predictions, need %d", len(scores), MinCalibrationSamples)
+	}
+
+	var positives, negatives float64
+	for _, complete := range outcomes {
+		if complete {
+			positives++
+		} else {
+			negatives++
+		}
+	}
+	if positives == 0 || negatives == 0 {
+		return nil, fmt.Errorf("not enough history to calibrate: need both completed and unfinished loops")
+	}
+
+	a, b := plattScaling(scores, outcomes, positives, negatives)
+	return &ConfidenceCalibrator{A: a, B: b, Samples: len(scores), UpdatedAt: time.Now()}, nil
+}
+
+// plattScaling fits A and B with Newton's method and a backtracking line
+// search, following Lin, Lin and Weng's "A note on Platt's probabilistic
+// outputs for support vector machines"
+func plattScaling(scores []float64, outcomes []bool, positives, negatives float64) (float64, float64) {
+	const (
+		maxIterations = 100
+		minStep       = 1e-10
+		sigma         = 1e-12
+		epsilon       = 1e-5
+	)
+
+	// Smoothed targets keep the fit from overshooting on small histories
+	hiTarget := (positives + 1) / (positives + 2)
+	loTarget := 1 / (negatives + 2)
+	targets := make([]float64, len(outcomes))
+	for i, complete := range outcomes {
+		if complete {
+			targets[i] = hiTarget
+		} else {
+			targets[i] = loTarget
+		}
+	}
+
+	objective := func(a, b float64) float64 {
+		var f float64
+		for i, score := range scores {
+			fApB := score*a + b
+			if fApB >= 0 {
+				f += targets[i]*fApB + math.Log1p(math.Exp(-fApB))
+			} else {
+				f += (targets[i]-1)*fApB + math.Log1p(math.Exp(fApB))
+			}
+		}
+		return f
+	}
+
+	a, b := 0.0, math.Log((negatives+1)/(positives+1))
+	fval := objective(a, b)
+	for iter := 0; iter < maxIterations; iter++ {
+		h11, h22, h21, g1, g2 := sigma, sigma, 0.0, 0.0, 0.0
+		for i, score := range scores {
+			fApB := score*a + b
+			var p, q float64
+			if fApB >= 0 {
+				p = math.Exp(-fApB) / (1 + math.Exp(-fApB))
+				q = 1 / (1 + math.Exp(-fApB))
+			} else {
+				p = 1 / (1 + math.Exp(fApB))
+				q = math.Exp(fApB) / (1 + math.Exp(fApB))
+			}
+			d2 := p * q
+			h11 += score * score * d2
+			h22 += d2
+			h21 += score * d2
+			d1 := targets[i] - p
+			g1 += score * d1
+			g2 += d1
+		}
+		if math.Abs(g1) < epsilon && math.Abs(g2) < epsilon {
+			break
+		}
+
+		det := h11*h22 - h21*h21
+		dA := -(h22*g1 - h21*g2) / det
+		dB := -(-h21*g1 + h11*g2) / det
+		gd := g1*dA + g2*dB
+
+		step := 1.0
+		for step >= minStep {
+			newA, newB := a+step*dA, b+step*dB
+			if newF := objective(newA, newB); newF < fval+0.0001*step*gd {
+				a, b, fval = newA, newB, newF
+				break
+			}
+			step /= 2
+		}
+		if step < minStep {
+			break
+		}
+	}
+	return a, b
+}
+
+// Save writes the calibration to a JSON file
+func (c *ConfidenceCalibrator) Save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create calibration directory: %w", err)
+	}
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode calibration: %w", err)
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// LoadCalibrator reads a calibration. A missing file yields nil, so raw
+// scores are used.
+func LoadCalibrator(path string) (*ConfidenceCalibrator, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read calibration: %w", err)
+	}
+
+	var c ConfidenceCalibrator
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("failed to parse calibration: %w", err)
+	}
+	return &c, nil
+}
@@ -1,7 +1,9 @@
 package analyzer
 
 import (
+	"path/filepath"
 	"regexp"
+	"sort"
 	"strconv"
 
 	"hermes/internal/task"
@@ -17,9 +19,18 @@ func NewFeatureAnalyzer(basePath string) *FeatureAnalyzer {
 	return &FeatureAnalyzer{basePath: basePath}
 }
 
-// GetHighestFeatureID returns the highest feature ID number
-func (a *FeatureAnalyzer) GetHighestFeatureID() (int, error) {
+// reader returns a task reader that includes archived features, so their
+// IDs are not given out again
+func (a *FeatureAnalyzer) reader() *task.Reader {
 	reader := task.NewReader(a.basePath)
+	reader.SetIncludeArchived(true)
+	return reader
+}
+
+// GetHighestFeatureID returns the highest feature ID number, archived
+// features included
+func (a *FeatureAnalyzer) GetHighestFeatureID() (int, error) {
+	reader := a.reader()
 	files, err := reader.GetFeatureFiles()
 	if err != nil {
 		return 0, err
@@ -44,9 +55,10 @@ func (a *FeatureAnalyzer) GetHighestFeatureID() (int, error) {
 	return highest, nil
 }
 
-// GetHighestTaskID returns the highest task ID number
+// GetHighestTaskID returns the highest task ID number, archived tasks
+// included
 func (a *FeatureAnalyzer) GetHighestTaskID() (int, error) {
-	reader := task.NewReader(a.basePath)
+	reader := a.reader()
 	tasks, err := reader.GetAllTasks()
 	if err != nil {
 		return 0, err
@@ -66,9 +78,82 @@ func (a *FeatureAnalyzer) GetHighestTaskID() (int, error) {
 	return highest, nil
 }
 
-// GetNextIDs returns the next available feature and task IDs
+// featureFileNumberRegex matches the number of feature file names in both
+// styles, 001-name.md and F001-name.md
+var featureFileNumberRegex = regexp.MustCompile(`^F?(\d+)-`)
+
+// GetFeatureNumbers returns, for each feature number in use, the feature
+// files using it. A file uses the number of its name and of its feature ID.
+// Archived features keep their numbers.
+func (a *FeatureAnalyzer) GetFeatureNumbers() (map[int][]string, error) {
+	reader := a.reader()
+	files, err := reader.GetFeatureFiles()
+	if err != nil {
+		return nil, err
+	}
+
+	re := regexp.MustCompile(`F(\d+)`)
+	numbers := make(map[int][]string)
+	for _, file := range files {
+		name := filepath.Base(file)
+		used := make(map[int]bool)
+		if m := featureFileNumberRegex.FindStringSubmatch(name); m != nil {
+			n, _ := strconv.Atoi(m[1])
+			used[n] = true
+		}
+		if feature, err := reader.ReadFeature(file); err == nil {
+			if m := re.FindStringSubmatch(feature.ID); len(m) > 1 {
+				n, _ := strconv.Atoi(m[1])
+				used[n] = true
+			}
+		}
+		for n := range used {
+			numbers[n] = append(numbers[n], name)
+		}
+	}
+
+	return numbers, nil
+}
+
+// GetFeatureNumberConflicts returns the feature numbers used by more than
+// one file, e.g. when both 001-auth.md and F001-login.md exist
+func (a *FeatureAnalyzer) GetFeatureNumberConflicts() (map[int][]string, error) {
+	numbers, err := a.GetFeatureNumbers()
+	if err != nil {
+		return nil, err
+	}
+
+	conflicts := make(map[int][]string)
+	for n, files := range numbers {
+		if len(files) > 1 {
+			conflicts[n] = files
+		}
+	}
+	return conflicts, nil
+}
+
+// GetNextIDs returns the next available feature and task IDs. The feature
+// ID fills the first gap in the used feature numbers, so after F001, F002
+// and F005 it is 3. The task ID follows the highest task ID, since a new
+// feature takes several consecutive task IDs that a gap may not hold.
 func (a *FeatureAnalyzer) GetNextIDs() (featureID int, taskID int, err error) {
-	fid, err := a.GetHighestFeatureID()
+	numbers, err := a.GetFeatureNumbers()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	tid, err := a.GetHighestTaskID()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return firstGap(numbers), tid + 1, nil
+}
+
+// GetNextSequentialIDs returns the IDs following the highest feature and
+// task IDs, ignoring gaps
+func (a *FeatureAnalyzer) GetNextSequentialIDs() (featureID int, taskID int, err error) {
+	numbers, err := a.GetFeatureNumbers()
 	if err != nil {
 		return 0, 0, err
 	}
@@ -78,7 +163,33 @@ func (a *FeatureAnalyzer) GetNextIDs() (featureID int, taskID int, err error) {
 		return 0, 0, err
 	}
 
-	return fid + 1, tid + 1, nil
+	highest := 0
+	for n := range numbers {
+		if n > highest {
+			highest = n
+		}
+	}
+	return highest + 1, tid + 1, nil
+}
+
+// firstGap returns the lowest positive number not in used
+func firstGap(used map[int][]string) int {
+	sorted := make([]int, 0, len(used))
+	for n := range used {
+		sorted = append(sorted, n)
+	}
+	sort.Ints(sorted)
+
+	next := 1
+	for _, n := range sorted {
+		if n > next {
+			break
+		}
+		if n == next {
+			next++
+		}
+	}
+	return next
 }
 
 // GetProgress returns the task completion progress
@@ -0,0 +1,119 @@
+package analyzer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// writeFeatures creates feature files named name with the feature ID and
+// task ID given for each
+func writeFeatures(t *testing.T, files map[string][2]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	tasksDir := filepath.Join(dir, ".hermes", "tasks")
+	os.MkdirAll(tasksDir, 0755)
+	for name, ids := range files {
+		content := fmt.Sprintf("# Feature: %s\n\n**Feature ID:** %s\n**Status:** NOT_STARTED\n\n### %s: Task\n\n**Status:** NOT_STARTED\n", name, ids[0], ids[1])
+		if err := os.WriteFile(filepath.Join(tasksDir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestGetNextIDsFillsGaps(t *testing.T) {
+	tests := []struct {
+		name        string
+		files       map[string][2]string
+		wantFeature int
+		wantNextSeq int
+		wantTask    int
+	}{
+		{
+			name:        "empty",
+			files:       map[string][2]string{},
+			wantFeature: 1, wantNextSeq: 1, wantTask: 1,
+		},
+		{
+			name: "sequential",
+			files: map[string][2]string{
+				"001-a.md": {"F001", "T001"},
+				"002-b.md": {"F002", "T002"},
+			},
+			wantFeature: 3, wantNextSeq: 3, wantTask: 3,
+		},
+		{
+			name: "gap in the middle",
+			files: map[string][2]string{
+				"001-a.md": {"F001", "T001"},
+				"002-b.md": {"F002", "T002"},
+				"005-e.md": {"F005", "T009"},
+			},
+			wantFeature: 3, wantNextSeq: 6, wantTask: 10,
+		},
+		{
+			name: "gap at the start",
+			files: map[string][2]string{
+				"F002-b.md": {"F002", "T004"},
+				"F003-c.md": {"F003", "T005"},
+			},
+			wantFeature: 1, wantNextSeq: 4, wantTask: 6,
+		},
+		{
+			name: "file name and feature ID differ",
+			files: map[string][2]string{
+				"001-a.md": {"F001", "T001"},
+				"002-b.md": {"F003", "T002"},
+			},
+			wantFeature: 4, wantNextSeq: 4, wantTask: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := NewFeatureAnalyzer(writeFeatures(t, tt.files))
+
+			featureID, taskID, err := a.GetNextIDs()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if featureID != tt.wantFeature || taskID != tt.wantTask {
+				t.Errorf("GetNextIDs() = %d, %d, want %d, %d", featureID, taskID, tt.wantFeature, tt.wantTask)
+			}
+
+			featureID, taskID, err = a.GetNextSequentialIDs()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if featureID != tt.wantNextSeq || taskID != tt.wantTask {
+				t.Errorf("GetNextSequentialIDs() = %d, %d, want %d, %d", featureID, taskID, tt.wantNextSeq, tt.wantTask)
+			}
+		})
+	}
+}
+
+func TestGetFeatureNumberConflicts(t *testing.T) {
+	dir := writeFeatures(t, map[string][2]string{
+		"001-auth.md":   {"F001", "T001"},
+		"F001-login.md": {"F001", "T002"},
+		"002-api.md":    {"F002", "T003"},
+	})
+	a := NewFeatureAnalyzer(dir)
+
+	conflicts, err := a.GetFeatureNumberConflicts()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[int][]string{1: {"001-auth.md", "F001-login.md"}}
+	if !reflect.DeepEqual(conflicts, want) {
+		t.Errorf("conflicts = %v, want %v", conflicts, want)
+	}
+
+	featureID, _, _ := a.GetNextIDs()
+	if featureID != 3 {
+		t.Errorf("expected next feature ID 3, got %d", featureID)
+	}
+}
@@ -3,14 +3,21 @@ package analyzer
 import (
 	"regexp"
 	"strings"
+
+	"hermes/internal/ai"
+	"hermes/internal/prompt"
 )
 
+// statusBlockEnds mark the end of a JSON or HERMES_STATUS block
+var statusBlockEnds = []string{"```", "---END_HERMES_STATUS---"}
+
 var (
 	hermesStatusRegex = regexp.MustCompile(`---HERMES_STATUS---\s*([\s\S]*?)\s*---END_HERMES_STATUS---`)
 	statusRegex       = regexp.MustCompile(`STATUS:\s*(\w+)`)
 	exitSignalRegex   = regexp.MustCompile(`EXIT_SIGNAL:\s*(true|false)`)
 	workTypeRegex     = regexp.MustCompile(`WORK_TYPE:\s*(\w+)`)
 	recommendRegex    = regexp.MustCompile(`RECOMMENDATION:\s*(.+)`)
+	skipTestsRegex    = regexp.MustCompile(`SKIP_TESTS:\s*(true|false)`)
 
 	completionKeywords = []string{
 		"done", "complete", "finished", "implemented",
@@ -34,13 +41,21 @@ var (
 )
 
 // ResponseAnalyzer analyzes AI responses
-type ResponseAnalyzer struct{}
+type ResponseAnalyzer struct {
+	calibrator *ConfidenceCalibrator
+}
 
 // NewResponseAnalyzer creates a new response analyzer
 func NewResponseAnalyzer() *ResponseAnalyzer {
 	return &ResponseAnalyzer{}
 }
 
+// SetCalibrator sets the calibration applied to confidence scores; nil
+// keeps the raw scores
+func (a *ResponseAnalyzer) SetCalibrator(c *ConfidenceCalibrator) {
+	a.calibrator = c
+}
+
 // Analyze analyzes an AI response and returns the result
 func (a *ResponseAnalyzer) Analyze(output string) *AnalysisResult {
 	result := &AnalysisResult{
@@ -117,11 +132,70 @@ func (a *ResponseAnalyzer) Analyze(output string) *AnalysisResult {
 	} else if result.CompletionKeyword != "" {
 		result.Confidence = 0.7
 	}
+	result.RawConfidence = result.Confidence
+	result.Confidence = a.calibrator.Calibrate(result.Confidence)
+	if result.SkipTests {
+		result.MarkSkipTests()
+	}
 
 	return result
 }
 
+// AnalyzeStream analyzes an AI response as it streams, without waiting for
+// the whole output. The text of assistant events is collected until the
+// channel closes or a complete status block arrives. In the latter case it
+// returns at once with StoppedEarly set; the caller should then cancel the
+// stream's context to stop the AI and the rest of its output.
+func (a *ResponseAnalyzer) AnalyzeStream(events <-chan ai.StreamEvent) *AnalysisResult {
+	var output strings.Builder
+	for event := range events {
+		if event.Type != "assistant" && event.Type != "text" {
+			continue
+		}
+		checked := output.Len()
+		output.WriteString(event.Text)
+
+		// A block can only be complete once an end marker has arrived,
+		// possibly split across events
+		if !statusBlockEnded(output.String(), checked) {
+			continue
+		}
+		if text := output.String(); a.HasStatusBlock(text) {
+			result := a.Analyze(text)
+			result.StoppedEarly = true
+			return result
+		}
+	}
+	return a.Analyze(output.String())
+}
+
+// statusBlockEnded reports whether an end marker appears in the output
+// after the first checked bytes
+func statusBlockEnded(output string, checked int) bool {
+	for _, end := range statusBlockEnds {
+		from := checked - len(end) + 1
+		if from < 0 {
+			from = 0
+		}
+		if strings.Contains(output[from:], end) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseStatusBlock reads the status block, preferring the JSON format over
+// the plain-text HERMES_STATUS format
 func (a *ResponseAnalyzer) parseStatusBlock(output string, result *AnalysisResult) {
+	if block := prompt.ParseStatusBlock(output); block != nil {
+		result.Status = block.Status
+		result.ExitSignal = block.ExitSignal
+		result.WorkType = block.WorkType
+		result.Recommendation = strings.TrimSpace(block.Recommendation)
+		result.SkipTests = block.SkipTests
+		return
+	}
+
 	matches := hermesStatusRegex.FindStringSubmatch(output)
 	if len(matches) < 2 {
 		return
@@ -144,15 +218,22 @@ func (a *ResponseAnalyzer) parseStatusBlock(output string, result *AnalysisResul
 	if m := recommendRegex.FindStringSubmatch(block); len(m) > 1 {
 		result.Recommendation = strings.TrimSpace(m[1])
 	}
+
+	if m := skipTestsRegex.FindStringSubmatch(block); len(m) > 1 {
+		result.SkipTests = m[1] == "true"
+	}
 }
 
-// HasStatusBlock checks if the output contains a HERMES_STATUS block
+// HasStatusBlock checks if the output contains a JSON or HERMES_STATUS block
 func (a *ResponseAnalyzer) HasStatusBlock(output string) bool {
-	return hermesStatusRegex.MatchString(output)
+	return prompt.ParseStatusBlock(output) != nil || hermesStatusRegex.MatchString(output)
 }
 
-// ExtractStatusBlock extracts the status block from output
+// ExtractStatusBlock extracts the status block from output, preferring the JSON format
 func (a *ResponseAnalyzer) ExtractStatusBlock(output string) string {
+	if block := prompt.FindStatusBlock(output); block != "" {
+		return block
+	}
 	matches := hermesStatusRegex.FindStringSubmatch(output)
 	if len(matches) >= 1 {
 		return matches[0]
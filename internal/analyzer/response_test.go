@@ -1,7 +1,13 @@
 package analyzer
 
 import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+
+	"hermes/internal/ai"
 )
 
 func TestAnalyzeStatusBlock(t *testing.T) {
@@ -40,6 +46,32 @@ RECOMMENDATION: Move to next task
 	}
 }
 
+func TestAnalyzeSkipTests(t *testing.T) {
+	a := NewResponseAnalyzer()
+
+	result := a.Analyze("Implemented the handler.\n\n---HERMES_STATUS---\nSTATUS: COMPLETE\nEXIT_SIGNAL: true\nSKIP_TESTS: true\n---END_HERMES_STATUS---\n")
+	if !result.SkipTests || !result.IsComplete {
+		t.Errorf("expected a complete response with skipped tests, got %+v", result)
+	}
+	if result.Confidence != SkipTestsMaxConfidence || result.RawConfidence != 1.0 {
+		t.Errorf("expected confidence capped at %.1f with raw 1.0, got %.2f and %.2f", SkipTestsMaxConfidence, result.Confidence, result.RawConfidence)
+	}
+
+	result = a.Analyze("Done.\n\n```json\n{\"hermes_status\": {\"status\": \"COMPLETE\", \"exit_signal\": true, \"skip_tests\": true}}\n```\n")
+	if !result.SkipTests || result.Confidence != SkipTestsMaxConfidence {
+		t.Errorf("expected skip_tests from the JSON block, got %+v", result)
+	}
+
+	result = a.Analyze("---HERMES_STATUS---\nSTATUS: IN_PROGRESS\nEXIT_SIGNAL: false\nSKIP_TESTS: false\n---END_HERMES_STATUS---")
+	if result.SkipTests {
+		t.Error("expected SKIP_TESTS: false to leave SkipTests unset")
+	}
+	result.MarkSkipTests()
+	if !result.SkipTests || result.Confidence != 0 {
+		t.Errorf("expected a low confidence to be kept, got %.2f", result.Confidence)
+	}
+}
+
 func TestAnalyzeInProgress(t *testing.T) {
 	a := NewResponseAnalyzer()
 
@@ -219,3 +251,151 @@ func TestAnalyzeProgress(t *testing.T) {
 		})
 	}
 }
+
+func TestAnalyzeJSONStatusBlock(t *testing.T) {
+	a := NewResponseAnalyzer()
+
+	output := "Implemented the endpoint.\n\n```json\n" +
+		`{"hermes_status": {"status": "COMPLETE", "exit_signal": true, "work_type": "implementation", "recommendation": "Move to next task"}}` +
+		"\n```\n"
+
+	result := a.Analyze(output)
+	if result.Status != "COMPLETE" || !result.ExitSignal || !result.IsComplete {
+		t.Errorf("expected complete result, got %+v", result)
+	}
+	if result.WorkType != "implementation" || result.Recommendation != "Move to next task" {
+		t.Errorf("unexpected work type or recommendation: %+v", result)
+	}
+	if !a.HasStatusBlock(output) || !strings.HasPrefix(a.ExtractStatusBlock(output), "```json") {
+		t.Error("expected JSON status block to be detected and extracted")
+	}
+}
+
+func TestAnalyzePrefersJSONStatusBlock(t *testing.T) {
+	a := NewResponseAnalyzer()
+
+	output := "---HERMES_STATUS---\nSTATUS: COMPLETE\nEXIT_SIGNAL: true\n---END_HERMES_STATUS---\n\n```json\n" +
+		`{"hermes_status": {"status": "IN_PROGRESS", "exit_signal": false}}` +
+		"\n```\n"
+
+	result := a.Analyze(output)
+	if result.Status != "IN_PROGRESS" || result.ExitSignal {
+		t.Errorf("expected JSON block to win, got status=%s exit=%v", result.Status, result.ExitSignal)
+	}
+}
+
+func TestAnalyzeStream(t *testing.T) {
+	a := NewResponseAnalyzer()
+
+	// The status block is split across events; the stream stops being read
+	// once it is complete
+	events := make(chan ai.StreamEvent, 10)
+	events <- ai.StreamEvent{Type: "system", Model: "test"}
+	events <- ai.StreamEvent{Type: "assistant", Text: "Implemented the handler and added tests.\n---HERMES_STATUS---\nSTATUS: COMPLETE\n"}
+	events <- ai.StreamEvent{Type: "tool_use", ToolName: "Bash"}
+	events <- ai.StreamEvent{Type: "assistant", Text: "EXIT_SIGNAL: true\n---END_HERMES"}
+	events <- ai.StreamEvent{Type: "assistant", Text: "_STATUS---\n"}
+	events <- ai.StreamEvent{Type: "assistant", Text: "More output that is never needed"}
+	close(events)
+
+	result := a.AnalyzeStream(events)
+	if !result.StoppedEarly || !result.ExitSignal || result.Status != "COMPLETE" {
+		t.Errorf("expected an early complete result, got %+v", result)
+	}
+	if len(events) != 1 {
+		t.Errorf("expected the last event to stay unread, %d left", len(events))
+	}
+
+	// Without a status block the whole stream is analyzed
+	events = make(chan ai.StreamEvent, 3)
+	events <- ai.StreamEvent{Type: "text", Text: "```go\nfunc main() {}\n```\n"}
+	events <- ai.StreamEvent{Type: "text", Text: "Created main.go"}
+	close(events)
+
+	result = a.AnalyzeStream(events)
+	if result.StoppedEarly || result.IsComplete {
+		t.Errorf("expected an incomplete result, got %+v", result)
+	}
+	if result.OutputLength != len("```go\nfunc main() {}\n```\nCreated main.go") {
+		t.Errorf("unexpected output length %d", result.OutputLength)
+	}
+}
+
+func TestConfidenceCalibrator(t *testing.T) {
+	var nilCalibrator *ConfidenceCalibrator
+	if got := nilCalibrator.Calibrate(0.7); got != 0.7 {
+		t.Errorf("Expected raw score without calibration, got %f", got)
+	}
+
+	// "complete" at 0.7 is usually wrong, at 1.0 usually right
+	var scores []float64
+	var outcomes []bool
+	for i := 0; i < 10; i++ {
+		scores = append(scores, 0.7, 1.0, 0)
+		outcomes = append(outcomes, i < 2, i < 9, false)
+	}
+	c, err := FitCalibrator(scores, outcomes)
+	if err != nil {
+		t.Fatalf("FitCalibrator failed: %v", err)
+	}
+	low, high := c.Calibrate(0.7), c.Calibrate(1.0)
+	if low >= 0.7 || high <= low || high < 0.7 {
+		t.Errorf("Expected 0.7 to be scaled down and 1.0 to stay high, got %f and %f", low, high)
+	}
+
+	a := NewResponseAnalyzer()
+	a.SetCalibrator(c)
+	result := a.Analyze("All done, the feature is implemented.")
+	if result.RawConfidence != 0.7 || result.Confidence != low {
+		t.Errorf("Expected raw 0.7 calibrated to %f, got raw %f calibrated %f", low, result.RawConfidence, result.Confidence)
+	}
+
+	if _, err := FitCalibrator(scores[:5], outcomes[:5]); err == nil {
+		t.Error("Expected error for too little history")
+	}
+	if _, err := FitCalibrator(scores[:12], make([]bool, 12)); err == nil {
+		t.Error("Expected error without completed outcomes")
+	}
+}
+
+func TestAnalyticsOutcomes(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "analytics.json")
+	for i, id := range []string{"T001", "T001", "T002", "T003"} {
+		if err := AppendPrediction(path, Prediction{TaskID: id, Loop: i + 1, Confidence: 0.7}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	history, err := LoadAnalytics(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// T002 was reopened, T003 never finished
+	got := history.Outcomes(map[string]bool{"T001": true})
+	expected := []bool{false, true, false, false}
+	for i := range expected {
+		if got[i] != expected[i] {
+			t.Errorf("Outcome %d: expected %v, got %v", i, expected[i], got[i])
+		}
+	}
+
+	// The history keeps the last maxPredictions
+	full := &Analytics{Predictions: make([]Prediction, maxPredictions)}
+	data, _ := json.Marshal(full)
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := AppendPrediction(path, Prediction{TaskID: "T004"}); err != nil {
+		t.Fatal(err)
+	}
+	if history, err = LoadAnalytics(path); err != nil {
+		t.Fatal(err)
+	}
+	if len(history.Predictions) != maxPredictions || history.Predictions[maxPredictions-1].TaskID != "T004" {
+		t.Errorf("Expected the last %d predictions ending with T004, got %d", maxPredictions, len(history.Predictions))
+	}
+
+	if c, err := LoadCalibrator(filepath.Join(t.TempDir(), "missing.json")); err != nil || c != nil {
+		t.Errorf("Expected no calibrator for a missing file, got %v, %v", c, err)
+	}
+}
@@ -11,9 +11,25 @@ type AnalysisResult struct {
 	WorkType          string  `json:"workType"`
 	Recommendation    string  `json:"recommendation"`
 	Confidence        float64 `json:"confidence"`
+	RawConfidence     float64 `json:"rawConfidence"` // Confidence before calibration
 	OutputLength      int     `json:"outputLength"`
 	ErrorCount        int     `json:"errorCount"`
 	CompletionKeyword string  `json:"completionKeyword"`
+	StoppedEarly      bool    `json:"stoppedEarly,omitempty"` // AnalyzeStream returned before the stream ended
+	SkipTests         bool    `json:"skipTests,omitempty"`    // The AI did not run tests (run --skip-tests)
+}
+
+// SkipTestsMaxConfidence caps the confidence of a response whose tests were
+// not run, since nothing checked the work
+const SkipTestsMaxConfidence = 0.8
+
+// MarkSkipTests records that the tests were not run and caps the
+// confidence at SkipTestsMaxConfidence
+func (r *AnalysisResult) MarkSkipTests() {
+	r.SkipTests = true
+	if r.Confidence > SkipTestsMaxConfidence {
+		r.Confidence = SkipTestsMaxConfidence
+	}
 }
 
 // ExitSignals tracks exit signals across loops
@@ -1,8 +1,10 @@
 package circuit
 
 import (
+	"crypto/rand"
 	"encoding/json"
 	"fmt"
+	"math"
 	"os"
 	"path/filepath"
 	"time"
@@ -13,6 +15,22 @@ const (
 	OpenThreshold     = 3 // Loops without progress before OPEN
 )
 
+// GetEffectiveThresholds scales the base thresholds by a task's estimated
+// effort in days, so a 3 day task gets 3x the loops before halting. Efforts
+// of a day or less, including unknown ones, keep the base thresholds.
+func GetEffectiveThresholds(effortDays float64) (halfOpen, open int) {
+	if effortDays <= 1 {
+		return HalfOpenThreshold, OpenThreshold
+	}
+
+	halfOpen = int(math.Round(HalfOpenThreshold * effortDays))
+	open = int(math.Round(OpenThreshold * effortDays))
+	if open <= halfOpen {
+		open = halfOpen + 1
+	}
+	return halfOpen, open
+}
+
 // Breaker implements the circuit breaker pattern
 type Breaker struct {
 	basePath    string
@@ -90,6 +108,75 @@ func (b *Breaker) CanExecute() (bool, error) {
 	return state.State != StateOpen, nil
 }
 
+// AdaptThresholds stores the thresholds for a task of the given effort in
+// the state file, where AddLoopResult reads them. An effort of 0 restores
+// the base thresholds.
+func (b *Breaker) AdaptThresholds(effortDays float64) error {
+	state, err := b.GetState()
+	if err != nil {
+		return err
+	}
+
+	halfOpen, open := GetEffectiveThresholds(effortDays)
+	if state.HalfOpenThreshold == halfOpen && state.OpenThreshold == open {
+		return nil
+	}
+	state.HalfOpenThreshold = halfOpen
+	state.OpenThreshold = open
+	return b.saveState(state)
+}
+
+// RecordCheckpoint stores the loop of the last checkpoint commit
+func (b *Breaker) RecordCheckpoint(loopNumber int) error {
+	state, err := b.GetState()
+	if err != nil {
+		return err
+	}
+
+	state.LastCheckpoint = loopNumber
+	return b.saveState(state)
+}
+
+// StartSession stores a new session ID for a run and returns it
+func (b *Breaker) StartSession() (string, error) {
+	state, err := b.GetState()
+	if err != nil {
+		return "", err
+	}
+
+	id, err := newSessionID()
+	if err != nil {
+		return "", err
+	}
+	state.SessionID = id
+	return id, b.saveState(state)
+}
+
+// newSessionID returns a random (version 4) UUID
+func newSessionID() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", fmt.Errorf("failed to generate session ID: %w", err)
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16]), nil
+}
+
+// RecordUntestedCompletion counts a task completed without running its
+// tests. The loop still counts as progress in AddLoopResult, but the count
+// shows how many completions are of lower confidence.
+func (b *Breaker) RecordUntestedCompletion(loopNumber int) error {
+	state, err := b.GetState()
+	if err != nil {
+		return err
+	}
+
+	state.UntestedCompletions++
+	state.LastUntestedCompletion = loopNumber
+	return b.saveState(state)
+}
+
 // AddLoopResult records a loop result and updates state
 func (b *Breaker) AddLoopResult(hasProgress, hasError bool, loopNumber int) (bool, error) {
 	state, err := b.GetState()
@@ -99,6 +186,7 @@ func (b *Breaker) AddLoopResult(hasProgress, hasError bool, loopNumber int) (boo
 
 	oldState := state.State
 	state.CurrentLoop = loopNumber
+	state.TotalLoops++
 
 	if hasProgress {
 		// Progress detected - reset counters and close circuit
@@ -112,13 +200,14 @@ func (b *Breaker) AddLoopResult(hasProgress, hasError bool, loopNumber int) (boo
 		// No progress
 		state.ConsecutiveNoProgress++
 
-		if state.ConsecutiveNoProgress >= OpenThreshold {
+		halfOpenThreshold, openThreshold := state.Thresholds()
+		if state.ConsecutiveNoProgress >= openThreshold {
 			if state.State != StateOpen {
 				state.State = StateOpen
 				state.TotalOpens++
 				state.Reason = fmt.Sprintf("No progress for %d loops, opening circuit", state.ConsecutiveNoProgress)
 			}
-		} else if state.ConsecutiveNoProgress >= HalfOpenThreshold {
+		} else if state.ConsecutiveNoProgress >= halfOpenThreshold {
 			if state.State == StateClosed {
 				state.State = StateHalfOpen
 				state.Reason = fmt.Sprintf("Monitoring: %d loops without progress", state.ConsecutiveNoProgress)
@@ -159,8 +248,15 @@ func (b *Breaker) Reset(reason string) error {
 	state := &BreakerState{
 		State:       StateClosed,
 		Reason:      reason,
-		TotalOpens:  oldState.TotalOpens, // Preserve total opens
+		TotalOpens:  oldState.TotalOpens, // Preserve totals
+		TotalLoops:  oldState.TotalLoops,
 		LastUpdated: time.Now(),
+
+		// Keep the thresholds of the current task
+		HalfOpenThreshold: oldState.HalfOpenThreshold,
+		OpenThreshold:     oldState.OpenThreshold,
+		LastCheckpoint:    oldState.LastCheckpoint,
+		SessionID:         oldState.SessionID,
 	}
 
 	if oldState != nil && oldState.State != StateClosed {
@@ -170,6 +170,48 @@ func TestReset(t *testing.T) {
 	}
 }
 
+func TestRecordCheckpoint(t *testing.T) {
+	tmpDir, cleanup := setupTestDir(t)
+	defer cleanup()
+
+	b := New(tmpDir)
+	b.Initialize()
+
+	if err := b.RecordCheckpoint(20); err != nil {
+		t.Fatal(err)
+	}
+	b.AddLoopResult(true, false, 21)
+	b.Reset("Manual reset")
+
+	state, _ := b.GetState()
+	if state.LastCheckpoint != 20 {
+		t.Errorf("expected last checkpoint 20 to survive loops and reset, got %d", state.LastCheckpoint)
+	}
+}
+
+func TestRecordUntestedCompletion(t *testing.T) {
+	tmpDir, cleanup := setupTestDir(t)
+	defer cleanup()
+
+	b := New(tmpDir)
+	b.Initialize()
+
+	b.AddLoopResult(true, false, 1)
+	if err := b.RecordUntestedCompletion(1); err != nil {
+		t.Fatal(err)
+	}
+	b.AddLoopResult(true, false, 2)
+	b.RecordUntestedCompletion(2)
+
+	state, _ := b.GetState()
+	if state.UntestedCompletions != 2 || state.LastUntestedCompletion != 2 {
+		t.Errorf("expected 2 untested completions, the last in loop 2, got %d and %d", state.UntestedCompletions, state.LastUntestedCompletion)
+	}
+	if state.State != StateClosed || state.LastProgress != 2 {
+		t.Errorf("expected untested completions to count as progress, got %s with last progress %d", state.State, state.LastProgress)
+	}
+}
+
 func TestShouldHalt(t *testing.T) {
 	tmpDir, cleanup := setupTestDir(t)
 	defer cleanup()
@@ -281,3 +323,125 @@ func TestTotalOpens(t *testing.T) {
 		t.Errorf("expected TotalOpens = 2, got %d", state.TotalOpens)
 	}
 }
+
+func TestTotalLoops(t *testing.T) {
+	tmpDir, cleanup := setupTestDir(t)
+	defer cleanup()
+
+	b := New(tmpDir)
+	b.Initialize()
+
+	b.AddLoopResult(true, false, 1)
+	b.AddLoopResult(false, false, 2)
+
+	state, _ := b.GetState()
+	if state.TotalLoops != 2 {
+		t.Errorf("expected 2 total loops, got %d", state.TotalLoops)
+	}
+
+	// Reset keeps the total
+	b.Reset("Manual reset")
+	b.AddLoopResult(true, false, 1)
+
+	state, _ = b.GetState()
+	if state.TotalLoops != 3 {
+		t.Errorf("expected 3 total loops after reset, got %d", state.TotalLoops)
+	}
+}
+
+func TestGetEffectiveThresholds(t *testing.T) {
+	tests := []struct {
+		effortDays     float64
+		halfOpen, open int
+	}{
+		{0, 2, 3},
+		{0.5, 2, 3},
+		{1, 2, 3},
+		{1.5, 3, 5},
+		{2, 4, 6},
+		{3, 6, 9},
+		{10, 20, 30},
+	}
+
+	for _, tt := range tests {
+		halfOpen, open := GetEffectiveThresholds(tt.effortDays)
+		if halfOpen != tt.halfOpen || open != tt.open {
+			t.Errorf("GetEffectiveThresholds(%v) = %d, %d, want %d, %d", tt.effortDays, halfOpen, open, tt.halfOpen, tt.open)
+		}
+	}
+}
+
+func TestAdaptiveThresholds(t *testing.T) {
+	tmpDir, cleanup := setupTestDir(t)
+	defer cleanup()
+
+	b := New(tmpDir)
+	b.Initialize()
+
+	// A 2 day task halts after 6 loops without progress instead of 3
+	if err := b.AdaptThresholds(2); err != nil {
+		t.Fatal(err)
+	}
+	for i := 1; i <= 5; i++ {
+		b.AddLoopResult(false, false, i)
+	}
+	state, _ := b.GetState()
+	if state.State != StateHalfOpen {
+		t.Errorf("expected HALF_OPEN after 5 loops, got %s", state.State)
+	}
+	if state.HalfOpenThreshold != 4 || state.OpenThreshold != 6 {
+		t.Errorf("expected thresholds 4/6 in the state file, got %d/%d", state.HalfOpenThreshold, state.OpenThreshold)
+	}
+
+	b.AddLoopResult(false, false, 6)
+	state, _ = b.GetState()
+	if state.State != StateOpen {
+		t.Errorf("expected OPEN after 6 loops, got %s", state.State)
+	}
+
+	// Reset keeps the thresholds, an effort of 0 restores the base ones
+	b.Reset("test")
+	state, _ = b.GetState()
+	if halfOpen, open := state.Thresholds(); halfOpen != 4 || open != 6 {
+		t.Errorf("expected thresholds to survive reset, got %d/%d", halfOpen, open)
+	}
+	b.AdaptThresholds(0)
+	state, _ = b.GetState()
+	if halfOpen, open := state.Thresholds(); halfOpen != HalfOpenThreshold || open != OpenThreshold {
+		t.Errorf("expected base thresholds, got %d/%d", halfOpen, open)
+	}
+}
+
+func TestStartSession(t *testing.T) {
+	tmpDir, cleanup := setupTestDir(t)
+	defer cleanup()
+
+	b := New(tmpDir)
+	if err := b.Initialize(); err != nil {
+		t.Fatal(err)
+	}
+
+	id, err := b.StartSession()
+	if err != nil {
+		t.Fatalf("StartSession failed: %v", err)
+	}
+	if len(id) != 36 || id[14] != '4' {
+		t.Errorf("expected a version 4 UUID, got %s", id)
+	}
+
+	state, _ := b.GetState()
+	if state.SessionID != id {
+		t.Errorf("expected session %s in the state, got %s", id, state.SessionID)
+	}
+
+	if next, _ := b.StartSession(); next == id {
+		t.Error("expected a new session ID for every run")
+	}
+
+	// Resetting the breaker keeps the session of the last run
+	state, _ = b.GetState()
+	b.Reset("manual")
+	if after, _ := b.GetState(); after.SessionID != state.SessionID {
+		t.Errorf("expected Reset to keep the session, got %s", after.SessionID)
+	}
+}
@@ -37,6 +37,13 @@ func (b *Breaker) PrintStatus() error {
 	fmt.Printf("Last progress:         Loop #%d\n", state.LastProgress)
 	fmt.Printf("Current loop:          #%d\n", state.CurrentLoop)
 	fmt.Printf("Total opens:           %d\n", state.TotalOpens)
+	fmt.Printf("Total loops:           %d\n", state.TotalLoops)
+	if state.UntestedCompletions > 0 {
+		fmt.Printf("Untested completions:  %d (last in loop #%d, tests skipped)\n", state.UntestedCompletions, state.LastUntestedCompletion)
+	}
+	if halfOpen, open := state.Thresholds(); halfOpen != HalfOpenThreshold || open != OpenThreshold {
+		fmt.Printf("Thresholds:            %d half-open, %d open (adapted to task effort)\n", halfOpen, open)
+	}
 	fmt.Println(strings.Repeat("=", 60))
 
 	return nil
@@ -19,8 +19,37 @@ type BreakerState struct {
 	LastProgress          int       `json:"lastProgress"`
 	CurrentLoop           int       `json:"currentLoop"`
 	TotalOpens            int       `json:"totalOpens"`
+	TotalLoops            int       `json:"totalLoops"`
 	LastUpdated           time.Time `json:"lastUpdated"`
 	Reason                string    `json:"reason"`
+
+	// Thresholds set by AdaptThresholds; 0 means the base threshold
+	HalfOpenThreshold int `json:"halfOpenThreshold,omitempty"`
+	OpenThreshold     int `json:"openThreshold,omitempty"`
+
+	// Loop of the last checkpoint commit (run --checkpoint-interval)
+	LastCheckpoint int `json:"lastCheckpoint,omitempty"`
+
+	// Tasks completed without running tests (run --skip-tests) and the loop
+	// of the last one
+	UntestedCompletions    int `json:"untestedCompletions,omitempty"`
+	LastUntestedCompletion int `json:"lastUntestedCompletion,omitempty"`
+
+	// ID of the current or last 'hermes run', recorded with its task status
+	// changes
+	SessionID string `json:"sessionId,omitempty"`
+}
+
+// Thresholds returns the loops without progress before HALF_OPEN and OPEN
+func (s *BreakerState) Thresholds() (halfOpen, open int) {
+	halfOpen, open = HalfOpenThreshold, OpenThreshold
+	if s.HalfOpenThreshold > 0 {
+		halfOpen = s.HalfOpenThreshold
+	}
+	if s.OpenThreshold > 0 {
+		open = s.OpenThreshold
+	}
+	return halfOpen, open
 }
 
 // HistoryEntry records a state transition
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
@@ -12,28 +13,49 @@ import (
 	"hermes/internal/ai"
 	"hermes/internal/analyzer"
 	"hermes/internal/config"
+	"hermes/internal/task"
 	"hermes/internal/ui"
 )
 
 type addOptions struct {
-	dryRun  bool
-	timeout int
-	debug   bool
+	dryRun    bool
+	timeout   int
+	debug     bool
+	fromIssue string
+	noGapFill bool
 }
 
+// defaultTargetVersion is the target version of added features without a milestone
+const defaultTargetVersion = "v1.0.0"
+
 // NewAddCmd creates the add subcommand
 func NewAddCmd() *cobra.Command {
 	opts := &addOptions{}
 
 	cmd := &cobra.Command{
-		Use:   "add <feature-description>",
+		Use:   "add [feature-description]",
 		Short: "Add a single feature",
-		Long:  "Add a new feature to the task plan using AI",
+		Long: `Add a new feature to the task plan using AI.
+
+With --from-issue the feature is built from a GitHub issue's title, body,
+labels and milestone instead of a description. GITHUB_TOKEN is used for
+private repositories.`,
 		Example: `  hermes add "user authentication with JWT"
   hermes add "dark mode toggle" --dry-run
-  hermes add "API rate limiting"`,
-		Args: cobra.ExactArgs(1),
+  hermes add "API rate limiting"
+  hermes add --from-issue https://github.com/owner/repo/issues/42
+  hermes add --from-issue owner/repo#42`,
+		Args: cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if opts.fromIssue != "" {
+				if len(args) > 0 {
+					return fmt.Errorf("a description cannot be combined with --from-issue")
+				}
+				return addFromIssueExecute(opts.fromIssue, opts)
+			}
+			if len(args) == 0 {
+				return fmt.Errorf("a feature description or --from-issue is required")
+			}
 			return addExecute(args[0], opts)
 		},
 	}
@@ -41,18 +63,28 @@ func NewAddCmd() *cobra.Command {
 	cmd.Flags().BoolVar(&opts.dryRun, "dry-run", false, "Show output without writing")
 	cmd.Flags().IntVar(&opts.timeout, "timeout", 300, "Timeout in seconds")
 	cmd.Flags().BoolVar(&opts.debug, "debug", false, "Enable debug output")
+	cmd.Flags().StringVar(&opts.fromIssue, "from-issue", "", "Create the feature from a GitHub issue (URL or owner/repo#N)")
+	cmd.Flags().BoolVar(&opts.noGapFill, "no-gap-fill", false, "Use the ID after the highest feature ID instead of filling gaps")
 
 	return cmd
 }
 
 func addExecute(featureDesc string, opts *addOptions) error {
-	ctx := context.Background()
-
 	ui.PrintBanner()
 	ui.PrintHeader("Feature Add")
 
 	fmt.Printf("Adding feature: %s\n\n", featureDesc)
 
+	return generateFeature(featureDesc, "", opts, func(featureID, taskID int) string {
+		return buildAddPrompt(featureDesc, featureID, taskID, task.PriorityP2, defaultTargetVersion)
+	})
+}
+
+// generateFeature asks the AI for a feature file built by prompt and writes
+// it. A non-empty header is written above the generated content.
+func generateFeature(name, header string, opts *addOptions, prompt func(featureID, taskID int) string) error {
+	ctx := context.Background()
+
 	// Load config
 	cfg, err := config.Load(".")
 	if err != nil {
@@ -61,11 +93,26 @@ func addExecute(featureDesc string, opts *addOptions) error {
 
 	// Get next IDs
 	featureAnalyzer := analyzer.NewFeatureAnalyzer(".")
-	nextFeatureID, nextTaskID, err := featureAnalyzer.GetNextIDs()
+	nextIDs := featureAnalyzer.GetNextIDs
+	if opts.noGapFill {
+		nextIDs = featureAnalyzer.GetNextSequentialIDs
+	}
+	nextFeatureID, nextTaskID, err := nextIDs()
 	if err != nil {
 		nextFeatureID = 1
 		nextTaskID = 1
 	}
+	if conflicts, _ := featureAnalyzer.GetFeatureNumberConflicts(); len(conflicts) > 0 {
+		numbers := make([]int, 0, len(conflicts))
+		for n := range conflicts {
+			numbers = append(numbers, n)
+		}
+		sort.Ints(numbers)
+		for _, n := range numbers {
+			ui.PrintWarning(fmt.Sprintf("Feature number %03d is used by several files: %s", n, strings.Join(conflicts[n], ", ")))
+		}
+		fmt.Println()
+	}
 
 	fmt.Printf("Next Feature ID: F%03d\n", nextFeatureID)
 	fmt.Printf("Next Task ID: T%03d\n\n", nextTaskID)
@@ -74,34 +121,39 @@ func addExecute(featureDesc string, opts *addOptions) error {
 	provider := ai.NewClaudeProvider()
 	fmt.Printf("Using AI: %s\n\n", provider.Name())
 
-	// Build prompt
-	prompt := buildAddPrompt(featureDesc, nextFeatureID, nextTaskID)
-
 	// Execute with retry
 	result, err := ai.ExecuteWithRetry(ctx, provider, &ai.ExecuteOptions{
-		Prompt:       prompt,
+		Prompt:       prompt(nextFeatureID, nextTaskID),
 		Timeout:      opts.timeout,
 		StreamOutput: cfg.AI.StreamOutput,
 	}, &ai.RetryConfig{
-		MaxRetries: 3,
-		Delay:      5 * time.Second,
+		MaxRetries:        3,
+		Delay:             5 * time.Second,
+		BackoffMultiplier: 2,
+		MaxDelay:          60 * time.Second,
+		Jitter:            true,
 	})
 
 	if err != nil {
 		return fmt.Errorf("failed to add feature: %w", err)
 	}
 
+	output := result.Output
+	if header != "" {
+		output = header + "\n\n" + output
+	}
+
 	if opts.dryRun {
 		fmt.Println("\n--- DRY RUN OUTPUT ---")
-		fmt.Println(result.Output)
+		fmt.Println(output)
 		return nil
 	}
 
 	// Write task file
-	return writeFeatureFile(result.Output, nextFeatureID, featureDesc)
+	return writeFeatureFile(output, nextFeatureID, name)
 }
 
-func buildAddPrompt(desc string, featureID, taskID int) string {
+func buildAddPrompt(desc string, featureID, taskID int, priority task.Priority, targetVersion string) string {
 	return fmt.Sprintf(`Create a comprehensive feature file for: %s
 
 Use Feature ID: F%03d
@@ -112,8 +164,8 @@ Create the feature file with this EXACT format:
 # Feature %d: <Feature Name based on description>
 
 **Feature ID:** F%03d
-**Priority:** P2 - HIGH
-**Target Version:** v1.0.0
+**Priority:** %s - %s
+**Target Version:** %s
 **Estimated Duration:** 1-2 weeks
 **Status:** NOT_STARTED
 
@@ -138,7 +190,7 @@ Create the feature file with this EXACT format:
 ### T%03d: <First Task Name>
 
 **Status:** NOT_STARTED
-**Priority:** P2
+**Priority:** %s
 **Estimated Effort:** 1 day
 
 #### Description
@@ -193,7 +245,7 @@ RULES:
 5. Success criteria must be specific and measurable
 6. Analyze the project structure to suggest correct file paths
 
-Output only the markdown content, no additional explanation.`, desc, featureID, taskID, featureID, featureID, taskID, taskID+4, taskID)
+Output only the markdown content, no additional explanation.`, desc, featureID, taskID, featureID, featureID, priority, priority.Label(), targetVersion, taskID, taskID+4, taskID, priority)
 }
 
 func writeFeatureFile(output string, featureID int, desc string) error {
@@ -203,7 +255,19 @@ func writeFeatureFile(output string, featureID int, desc string) error {
 		return err
 	}
 
-	// Generate filename
+	fileName := featureFileName(featureID, desc)
+	filePath := filepath.Join(tasksDir, fileName)
+
+	if err := os.WriteFile(filePath, []byte(output), 0644); err != nil {
+		return err
+	}
+
+	fmt.Printf("Created: %s\n", filePath)
+	return nil
+}
+
+// featureFileName returns the task file name for a feature, e.g. 003-user-login.md
+func featureFileName(featureID int, desc string) string {
 	safeName := strings.ToLower(desc)
 	safeName = strings.ReplaceAll(safeName, " ", "-")
 	// Keep only alphanumeric and hyphens
@@ -218,13 +282,5 @@ func writeFeatureFile(output string, featureID int, desc string) error {
 		safeName = safeName[:30]
 	}
 
-	fileName := fmt.Sprintf("%03d-%s.md", featureID, safeName)
-	filePath := filepath.Join(tasksDir, fileName)
-
-	if err := os.WriteFile(filePath, []byte(output), 0644); err != nil {
-		return err
-	}
-
-	fmt.Printf("Created: %s\n", filePath)
-	return nil
+	return fmt.Sprintf("%03d-%s.md", featureID, safeName)
 }
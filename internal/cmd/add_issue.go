@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"hermes/internal/github"
+	"hermes/internal/task"
+	"hermes/internal/ui"
+)
+
+// labelPriorities maps GitHub issue labels to task priorities
+var labelPriorities = map[string]task.Priority{
+	"critical":      task.PriorityP1,
+	"security":      task.PriorityP1,
+	"bug":           task.PriorityP1,
+	"enhancement":   task.PriorityP2,
+	"feature":       task.PriorityP2,
+	"performance":   task.PriorityP2,
+	"refactor":      task.PriorityP3,
+	"documentation": task.PriorityP3,
+	"chore":         task.PriorityP4,
+	"question":      task.PriorityP4,
+}
+
+func addFromIssueExecute(ref string, opts *addOptions) error {
+	owner, repo, number, err := github.ParseIssueRef(ref)
+	if err != nil {
+		return err
+	}
+
+	ui.PrintBanner()
+	ui.PrintHeader("Feature Add")
+
+	issue, err := github.NewClient().GetIssue(owner, repo, number)
+	if err != nil {
+		return fmt.Errorf("failed to fetch issue %s/%s#%d: %w", owner, repo, number, err)
+	}
+	if issue.HTMLURL == "" {
+		issue.HTMLURL = fmt.Sprintf("https://github.com/%s/%s/issues/%d", owner, repo, number)
+	}
+
+	priority := issuePriority(issue.Labels)
+	targetVersion := defaultTargetVersion
+	if issue.Milestone != nil && issue.Milestone.Title != "" {
+		targetVersion = issue.Milestone.Title
+	}
+
+	fmt.Printf("Adding feature from issue #%d: %s\n", issue.Number, issue.Title)
+	fmt.Printf("Priority: %s - %s\n\n", priority, priority.Label())
+
+	header := fmt.Sprintf("<!-- GitHub issue: %s -->", issue.HTMLURL)
+	return generateFeature(issue.Title, header, opts, func(featureID, taskID int) string {
+		return buildAddPrompt(issueDescription(issue), featureID, taskID, priority, targetVersion)
+	})
+}
+
+// issuePriority returns the highest priority mapped from the labels, or P2
+// when no label is known
+func issuePriority(labels []github.Label) task.Priority {
+	best := task.Priority("")
+	for _, label := range labels {
+		p, ok := labelPriorities[strings.ToLower(strings.TrimSpace(label.Name))]
+		if ok && (best == "" || p < best) {
+			best = p
+		}
+	}
+	if best == "" {
+		return task.PriorityP2
+	}
+	return best
+}
+
+// issueDescription renders an issue's fields as the feature description
+func issueDescription(issue *github.Issue) string {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("GitHub issue #%d: %s\n", issue.Number, strings.TrimSpace(issue.Title)))
+	sb.WriteString(fmt.Sprintf("URL: %s\n", issue.HTMLURL))
+	if len(issue.Labels) > 0 {
+		names := make([]string, len(issue.Labels))
+		for i, label := range issue.Labels {
+			names[i] = label.Name
+		}
+		sb.WriteString(fmt.Sprintf("Labels: %s\n", strings.Join(names, ", ")))
+	}
+	if issue.Milestone != nil && issue.Milestone.Title != "" {
+		sb.WriteString(fmt.Sprintf("Milestone: %s\n", issue.Milestone.Title))
+	}
+
+	body := strings.TrimSpace(issue.Body)
+	if body == "" {
+		body = "(no description)"
+	}
+	sb.WriteString("\nIssue description:\n\n")
+	sb.WriteString(body)
+	sb.WriteString("\n")
+
+	return sb.String()
+}
@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	"hermes/internal/analyzer"
+	"hermes/internal/task"
+)
+
+// NewAnalyticsCmd creates the analytics command group
+func NewAnalyticsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "analytics",
+		Short: "Analyze the history of task runs",
+		Long: `Work with the history of task runs kept in .hermes/analytics.json.
+
+Every loop of 'hermes run' records the confidence the response analyzer
+gave the task.`,
+	}
+
+	cmd.AddCommand(newAnalyticsCalibrateCmd())
+
+	return cmd
+}
+
+func newAnalyticsCalibrateCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "calibrate",
+		Short: "Calibrate completion confidence from run history",
+		Long: `Rebuild .hermes/calibration.json from the run history.
+
+A loop counts as having finished its task if it is the last loop recorded
+for a task that is completed now. The raw confidence scores are fitted to
+these outcomes by Platt scaling, and later runs report the calibrated
+confidence. Without a calibration file, raw scores are used.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return analyticsCalibrateExecute(".", os.Stdout)
+		},
+	}
+}
+
+func analyticsCalibrateExecute(basePath string, w io.Writer) error {
+	history, err := analyzer.LoadAnalytics(analyzer.AnalyticsPath(basePath))
+	if err != nil {
+		return err
+	}
+
+	tasks, err := newTaskReader(basePath).GetAllTasks()
+	if err != nil {
+		return fmt.Errorf("failed to read tasks: %w", err)
+	}
+	completed := make(map[string]bool)
+	for _, t := range tasks {
+		completed[t.ID] = t.Status == task.StatusCompleted
+	}
+
+	scores := make([]float64, len(history.Predictions))
+	for i, p := range history.Predictions {
+		scores[i] = p.Confidence
+	}
+	calibrator, err := analyzer.FitCalibrator(scores, history.Outcomes(completed))
+	if err != nil {
+		return err
+	}
+
+	path := analyzer.CalibrationPath(basePath)
+	if err := calibrator.Save(path); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "Calibrated from %d predictions: %s\n", calibrator.Samples, path)
+	for _, raw := range []float64{0, 0.7, 0.9, 1} {
+		fmt.Fprintf(w, "  raw %.2f -> %.2f\n", raw, calibrator.Calibrate(raw))
+	}
+	return nil
+}
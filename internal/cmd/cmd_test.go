@@ -1,10 +1,38 @@
 package cmd
 
 import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"runtime"
 	"strings"
 	"testing"
+	"time"
+
+	"hermes/internal/ai"
+	"hermes/internal/ai/mock"
+	"hermes/internal/analyzer"
+	"hermes/internal/circuit"
+	"hermes/internal/config"
+	"hermes/internal/github"
+	"hermes/internal/idea"
+	"hermes/internal/isolation"
+	"hermes/internal/prompt"
+	"hermes/internal/scheduler"
+	"hermes/internal/task"
+	"hermes/internal/tui"
+	"hermes/internal/ui"
 )
 
 func TestCreateGitignore(t *testing.T) {
@@ -68,7 +96,7 @@ func TestCreateGitignoreAppend(t *testing.T) {
 
 func TestBuildPrdPrompt(t *testing.T) {
 	prdContent := "This is my PRD content"
-	prompt := buildPrdPrompt(prdContent)
+	prompt := buildPrdPrompt(prdContent, nil)
 
 	if !strings.Contains(prompt, prdContent) {
 		t.Error("expected prompt to contain PRD content")
@@ -81,8 +109,97 @@ func TestBuildPrdPrompt(t *testing.T) {
 	}
 }
 
+func TestPrdSections(t *testing.T) {
+	prd := "# Product\n\n## User Accounts\ntext\n### Sub\n```\n## not a section\n```\n## Billing & Payments\n"
+	sections := prdSections(prd)
+	if len(sections) != 2 || sections[0] != "User Accounts" || sections[1] != "Billing & Payments" {
+		t.Errorf("unexpected sections: %v", sections)
+	}
+}
+
+func TestPrdFeatureSection(t *testing.T) {
+	prd := "# Product\n\n## User Accounts\nSign up\n### Login\nWith email\n```\n# not a heading\n```\n## Billing\nInvoices\n"
+
+	section := prdFeatureSection(prd, &task.Feature{ID: "F001", Name: "User Accounts"})
+	if !strings.HasPrefix(section, "## User Accounts") || !strings.Contains(section, "With email") || strings.Contains(section, "Invoices") {
+		t.Errorf("unexpected section: %q", section)
+	}
+
+	if section := prdFeatureSection(prd, &task.Feature{ID: "F002", Name: "Billing"}); section != "## Billing\nInvoices" {
+		t.Errorf("unexpected last section: %q", section)
+	}
+	if section := prdFeatureSection(prd, &task.Feature{ID: "F009", Name: "Reports"}); section != "" {
+		t.Errorf("expected no section, got %q", section)
+	}
+}
+
+func TestBuildPrdPromptSplitBySection(t *testing.T) {
+	prompt := buildPrdPrompt("PRD", []string{"User Accounts", "Billing"})
+
+	if !strings.Contains(prompt, "ONE feature file per top-level PRD section") {
+		t.Error("expected split-by-section instructions")
+	}
+	if !strings.Contains(prompt, "1. User Accounts (Feature 1, F001)") || !strings.Contains(prompt, "2. Billing (Feature 2, F002)") {
+		t.Error("expected numbered section list")
+	}
+	if !strings.Contains(prompt, "---FILE: <Section Name>---") {
+		t.Error("expected section file marker instruction")
+	}
+
+	if strings.Contains(buildPrdPrompt("PRD", nil), "SPLIT BY SECTION") {
+		t.Error("split instructions should only appear with sections")
+	}
+}
+
+func TestWriteSectionFiles(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "hermes-cmd-test-*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	oldWd, _ := os.Getwd()
+	os.Chdir(tmpDir)
+	defer os.Chdir(oldWd)
+
+	output := `---FILE: billing---
+# Feature 2: Billing
+---END_FILE---
+---FILE: User Accounts---
+# Feature 1: User Accounts
+---END_FILE---`
+
+	if err := writeSectionFiles(output, []string{"User Accounts", "Billing"}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"001-user-accounts.md", "002-billing.md"} {
+		if _, err := os.Stat(filepath.Join(".hermes", "tasks", name)); err != nil {
+			t.Errorf("expected %s to be created", name)
+		}
+	}
+
+	// Unknown sections with the same name are told apart by their feature ID
+	output = `---FILE: Extras---
+# Feature 3: Extras
+**Feature ID:** F003
+---END_FILE---
+---FILE: extras---
+# Feature 4: Extras
+**Feature ID:** F004
+---END_FILE---`
+	if err := writeSectionFiles(output, []string{"User Accounts", "Billing"}); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"003-extras.md", "004-extras.md"} {
+		if _, err := os.Stat(filepath.Join(".hermes", "tasks", name)); err != nil {
+			t.Errorf("expected %s to be created", name)
+		}
+	}
+}
+
 func TestBuildAddPrompt(t *testing.T) {
-	prompt := buildAddPrompt("user authentication", 5, 42)
+	prompt := buildAddPrompt("user authentication", 5, 42, task.PriorityP2, defaultTargetVersion)
 
 	if !strings.Contains(prompt, "user authentication") {
 		t.Error("expected prompt to contain feature description")
@@ -95,6 +212,44 @@ func TestBuildAddPrompt(t *testing.T) {
 	}
 }
 
+func TestAddFromIssuePrompt(t *testing.T) {
+	tests := []struct {
+		labels []string
+		want   task.Priority
+	}{
+		{[]string{"bug"}, task.PriorityP1},
+		{[]string{"Enhancement"}, task.PriorityP2},
+		{[]string{"documentation", "bug"}, task.PriorityP1},
+		{[]string{"question"}, task.PriorityP4},
+		{[]string{"wontfix"}, task.PriorityP2},
+		{nil, task.PriorityP2},
+	}
+	for _, tt := range tests {
+		var labels []github.Label
+		for _, name := range tt.labels {
+			labels = append(labels, github.Label{Name: name})
+		}
+		if got := issuePriority(labels); got != tt.want {
+			t.Errorf("issuePriority(%v) = %s, want %s", tt.labels, got, tt.want)
+		}
+	}
+
+	issue := &github.Issue{
+		Number:    42,
+		Title:     "Login fails with SSO",
+		Body:      "Steps to reproduce",
+		HTMLURL:   "https://github.com/owner/repo/issues/42",
+		Labels:    []github.Label{{Name: "bug"}},
+		Milestone: &github.Milestone{Title: "v1.3"},
+	}
+	prompt := buildAddPrompt(issueDescription(issue), 5, 42, task.PriorityP1, "v1.3")
+	for _, want := range []string{"GitHub issue #42: Login fails with SSO", "Labels: bug", "Milestone: v1.3", "Steps to reproduce", "**Priority:** P1 - CRITICAL", "**Target Version:** v1.3"} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("expected prompt to contain %q", want)
+		}
+	}
+}
+
 func TestWriteTaskFiles(t *testing.T) {
 	tmpDir, err := os.MkdirTemp("", "hermes-cmd-test-*")
 	if err != nil {
@@ -188,3 +343,1737 @@ func TestWriteFeatureFile(t *testing.T) {
 		t.Error("expected content to contain F005")
 	}
 }
+
+const testTaskFile = `# Feature 1: Test
+
+**Feature ID:** F001
+**Status:** NOT_STARTED
+
+### T001: First task
+
+**Status:** NOT_STARTED
+**Priority:** P2
+
+#### Dependencies
+
+- None
+
+---
+
+### T002: Second task
+
+**Status:** NOT_STARTED
+**Priority:** P2
+
+#### Dependencies
+
+- T001
+`
+
+func setupTaskDir(t *testing.T) func() {
+	tmpDir, err := os.MkdirTemp("", "hermes-cmd-test-*")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tasksDir := filepath.Join(tmpDir, ".hermes", "tasks")
+	os.MkdirAll(tasksDir, 0755)
+	os.WriteFile(filepath.Join(tasksDir, "001-test.md"), []byte(testTaskFile), 0644)
+
+	oldWd, _ := os.Getwd()
+	os.Chdir(tmpDir)
+
+	return func() {
+		os.Chdir(oldWd)
+		os.RemoveAll(tmpDir)
+	}
+}
+
+func TestTaskEditFields(t *testing.T) {
+	cleanup := setupTaskDir(t)
+	defer cleanup()
+
+	err := taskEditExecute("T002", []string{"status=IN_PROGRESS", "priority=P1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	updated, _ := task.NewReader(".").GetTaskByID("T002")
+	if updated.Status != task.StatusInProgress {
+		t.Errorf("expected IN_PROGRESS, got %s", updated.Status)
+	}
+	if updated.Priority != task.PriorityP1 {
+		t.Errorf("expected P1, got %s", updated.Priority)
+	}
+}
+
+func TestTaskBlockUnblock(t *testing.T) {
+	cleanup := setupTaskDir(t)
+	defer cleanup()
+
+	if err := taskBlockExecute("T001", "Needs design review"); err != nil {
+		t.Fatal(err)
+	}
+	blocked, _ := task.NewReader(".").GetTaskByID("T001")
+	if blocked.Status != task.StatusBlocked || !strings.Contains(blocked.BlockedReason, "Needs design review") {
+		t.Errorf("expected BLOCKED with reason, got %s %q", blocked.Status, blocked.BlockedReason)
+	}
+
+	if err := taskUnblockExecute("T001"); err != nil {
+		t.Fatal(err)
+	}
+	unblocked, _ := task.NewReader(".").GetTaskByID("T001")
+	if unblocked.Status != task.StatusNotStarted || unblocked.BlockedReason != "" {
+		t.Errorf("expected NOT_STARTED without reason, got %s %q", unblocked.Status, unblocked.BlockedReason)
+	}
+
+	if err := taskUnblockExecute("T001"); err == nil {
+		t.Error("expected error unblocking a task that is not blocked")
+	}
+}
+
+func TestTaskComplete(t *testing.T) {
+	cleanup := setupTaskDir(t)
+	defer cleanup()
+
+	injector := prompt.NewInjector(".")
+	injector.Write("# Prompt")
+	t1, _ := task.NewReader(".").GetTaskByID("T001")
+	injector.AddTask(t1)
+
+	if err := taskCompleteExecute(".", []string{"T001"}, "Done by hand", false); err != nil {
+		t.Fatal(err)
+	}
+	completed, _ := task.NewReader(".").GetTaskByID("T001")
+	if completed.Status != task.StatusCompleted || !strings.HasPrefix(completed.CompletionNote, "Done by hand") {
+		t.Errorf("expected COMPLETED with note, got %s %q", completed.Status, completed.CompletionNote)
+	}
+	if has, _ := injector.HasTaskSection(); has {
+		t.Error("expected the task to be removed from the prompt")
+	}
+	if state, _ := circuit.New(".").GetState(); state.TotalLoops != 1 {
+		t.Errorf("expected the completion to be recorded as a loop, got %d loops", state.TotalLoops)
+	}
+
+	if err := taskCompleteExecute(".", []string{"T001"}, "", false); err == nil {
+		t.Error("expected error completing a completed task")
+	}
+
+	task.NewStatusUpdater(".").UpdateTaskStatus("T002", task.StatusInProgress)
+	if err := taskCompleteExecute(".", nil, "", false); err != nil {
+		t.Fatal(err)
+	}
+	if t2, _ := task.NewReader(".").GetTaskByID("T002"); t2.Status != task.StatusCompleted {
+		t.Errorf("expected --all to complete T002, got %s", t2.Status)
+	}
+}
+
+func TestTaskEditValidation(t *testing.T) {
+	cleanup := setupTaskDir(t)
+	defer cleanup()
+
+	if err := taskEditExecute("T002", []string{"status=DONE"}); err == nil {
+		t.Error("expected error for invalid status")
+	}
+	if err := taskEditExecute("T001", []string{"deps=T002"}); err == nil {
+		t.Error("expected error for dependency cycle")
+	}
+	if err := taskEditExecute("T002", []string{"status"}); err == nil {
+		t.Error("expected error for malformed field")
+	}
+
+	// File should be unchanged after failed edits
+	content, _ := os.ReadFile(filepath.Join(".hermes", "tasks", "001-test.md"))
+	if string(content) != testTaskFile {
+		t.Error("task file should not change when validation fails")
+	}
+}
+
+func TestTaskEditEditor(t *testing.T) {
+	cleanup := setupTaskDir(t)
+	defer cleanup()
+
+	// Use sed as a non-interactive editor
+	t.Setenv("EDITOR", "sed -i s/P2/P4/")
+
+	if err := taskEditExecute("T001", nil); err != nil {
+		t.Fatal(err)
+	}
+
+	updated, _ := task.NewReader(".").GetTaskByID("T001")
+	if updated.Priority != task.PriorityP4 {
+		t.Errorf("expected P4, got %s", updated.Priority)
+	}
+	other, _ := task.NewReader(".").GetTaskByID("T002")
+	if other.Priority != task.PriorityP2 {
+		t.Errorf("T002 should be unchanged, got %s", other.Priority)
+	}
+}
+
+func TestStatusWatchStopsWhenComplete(t *testing.T) {
+	cleanup := setupTaskDir(t)
+	defer cleanup()
+
+	updater := task.NewStatusUpdater(".")
+	updater.MarkTaskCompleted("T001")
+	updater.MarkTaskCompleted("T002")
+
+	done := make(chan error, 1)
+	go func() {
+		done <- statusExecute(&statusOptions{watch: true, interval: 10 * time.Millisecond})
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("watch should stop when all tasks are complete")
+	}
+
+	if err := statusExecute(&statusOptions{watch: true}); err == nil {
+		t.Error("expected error for zero interval")
+	}
+}
+
+func TestStatusJSON(t *testing.T) {
+	cleanup := setupTaskDir(t)
+	defer cleanup()
+
+	task.NewStatusUpdater(".").MarkTaskCompleted("T001")
+
+	var buf bytes.Buffer
+	if err := statusJSON(task.NewReader("."), "", false, &buf); err != nil {
+		t.Fatal(err)
+	}
+
+	var report statusReport
+	if err := json.Unmarshal(buf.Bytes(), &report); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+	}
+	if report.TotalTasks != 2 || report.Completed != 1 || report.NotStarted != 1 || report.Percentage != 50 {
+		t.Errorf("unexpected totals: %+v", report)
+	}
+	if report.CurrentTaskID != "T002" || report.CircuitBreakerState != "CLOSED" {
+		t.Errorf("expected current task T002 and CLOSED breaker, got %s %s", report.CurrentTaskID, report.CircuitBreakerState)
+	}
+	if len(report.Features) != 1 || report.Features[0].ID != "F001" || report.Features[0].Completed != 1 {
+		t.Errorf("unexpected features: %+v", report.Features)
+	}
+	if !strings.Contains(buf.String(), "\n  \"total_tasks\": 2") {
+		t.Errorf("expected pretty-printed JSON, got %s", buf.String())
+	}
+
+	buf.Reset()
+	if err := statusJSON(task.NewReader("."), "", true, &buf); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Count(buf.String(), "\n") != 1 {
+		t.Errorf("expected compact JSON on one line, got %s", buf.String())
+	}
+
+	buf.Reset()
+	if err := statusJSON(task.NewReader("."), "F001", false, &buf); err != nil {
+		t.Fatal(err)
+	}
+	if err := statusJSON(task.NewReader("."), "F999", false, &buf); err == nil {
+		t.Error("expected error for an unknown feature")
+	}
+}
+
+const cascadeTaskFile = `# Feature 1: Cascade
+
+**Feature ID:** F001
+**Status:** IN_PROGRESS
+
+### T001: Schema
+
+**Status:** COMPLETED
+**Priority:** P1
+
+---
+
+### T002: Models
+
+**Status:** COMPLETED
+**Priority:** P1
+
+#### Dependencies
+
+- T001
+
+---
+
+### T003: API
+
+**Status:** BLOCKED
+**Priority:** P2
+**Blocked Reason:** Waiting for models (2026-01-02 15:04)
+
+#### Dependencies
+
+- T002
+
+---
+
+### T004: Docs
+
+**Status:** COMPLETED
+**Priority:** P3
+`
+
+func TestResetTaskCascade(t *testing.T) {
+	cleanup := setupTaskDir(t)
+	defer cleanup()
+	os.WriteFile(filepath.Join(".hermes", "tasks", "001-test.md"), []byte(cascadeTaskFile), 0644)
+
+	// Without --cascade only the task itself is reset
+	if err := resetTaskExecute("T002", task.StatusNotStarted, false); err != nil {
+		t.Fatal(err)
+	}
+	reader := task.NewReader(".")
+	if got, _ := reader.GetTaskByID("T002"); got.Status != task.StatusNotStarted {
+		t.Errorf("expected T002 NOT_STARTED, got %s", got.Status)
+	}
+	if got, _ := reader.GetTaskByID("T003"); got.Status != task.StatusBlocked {
+		t.Errorf("expected T003 to stay BLOCKED, got %s", got.Status)
+	}
+
+	// With --cascade transitive dependents are reset and unblocked
+	injector := prompt.NewInjector(".")
+	t3, _ := reader.GetTaskByID("T003")
+	if err := injector.AddTask(t3); err != nil {
+		t.Fatal(err)
+	}
+	if err := resetTaskExecute("T001", task.StatusInProgress, true); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]task.Status{
+		"T001": task.StatusInProgress,
+		"T002": task.StatusNotStarted,
+		"T003": task.StatusNotStarted,
+		"T004": task.StatusCompleted,
+	}
+	for id, status := range want {
+		got, _ := reader.GetTaskByID(id)
+		if got.Status != status {
+			t.Errorf("expected %s %s, got %s", id, status, got.Status)
+		}
+		if got.IsBlocked() {
+			t.Errorf("expected %s to be unblocked, reason %q", id, got.BlockedReason)
+		}
+	}
+	if current, _ := injector.GetCurrentTaskID(); current != "" {
+		t.Errorf("expected T003 to be removed from PROMPT.md, got %s", current)
+	}
+
+	if err := resetTaskExecute("T001", task.Status("DONE"), false); err == nil {
+		t.Error("expected error for invalid status")
+	}
+	if err := resetTaskExecute("T099", task.StatusNotStarted, false); err == nil {
+		t.Error("expected error for unknown task")
+	}
+}
+
+func TestTaskList(t *testing.T) {
+	cleanup := setupTaskDir(t)
+	defer cleanup()
+	os.WriteFile(filepath.Join(".hermes", "tasks", "001-test.md"), []byte(cascadeTaskFile), 0644)
+
+	list := func(opts *taskListOptions) string {
+		t.Helper()
+		var buf bytes.Buffer
+		if err := taskListExecute(opts, &buf); err != nil {
+			t.Fatal(err)
+		}
+		return buf.String()
+	}
+
+	out := list(&taskListOptions{sortBy: "status", format: "csv"})
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 5 || lines[0] != "id,feature,name,status,priority,effort,dependencies" {
+		t.Fatalf("unexpected CSV output:\n%s", out)
+	}
+	if !strings.HasPrefix(lines[1], "T003,F001,API,BLOCKED,P2,,T002") || !strings.HasPrefix(lines[2], "T001,") {
+		t.Errorf("expected BLOCKED before COMPLETED tasks sorted by ID, got:\n%s", out)
+	}
+
+	out = list(&taskListOptions{filters: []string{"status=completed", "priority=P1"}, format: "json"})
+	var tasks []task.Task
+	if err := json.Unmarshal([]byte(out), &tasks); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if len(tasks) != 2 || tasks[0].ID != "T001" || tasks[1].ID != "T002" || tasks[1].Dependencies[0] != "T001" {
+		t.Errorf("expected T001 and T002, got %+v", tasks)
+	}
+
+	out = list(&taskListOptions{sortBy: "priority", feature: "F099", format: "json"})
+	if strings.TrimSpace(out) != "[]" {
+		t.Errorf("expected empty JSON array, got %s", out)
+	}
+
+	out = list(&taskListOptions{sortBy: "priority"})
+	if !strings.Contains(out, "| Dependencies") || strings.Index(out, "T004") < strings.Index(out, "T003") {
+		t.Errorf("expected table sorted by priority, got:\n%s", out)
+	}
+
+	for _, opts := range []*taskListOptions{{sortBy: "name"}, {format: "xml"}, {filters: []string{"owner=me"}}, {filters: []string{"status=DONE"}}} {
+		if err := taskListExecute(opts, io.Discard); err == nil {
+			t.Errorf("expected error for %+v", opts)
+		}
+	}
+}
+
+func TestReadLastLines(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "hermes-cmd-test-*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	logPath := filepath.Join(tmpDir, "hermes.log")
+	content := "[INFO] one\n[ERROR] two\n[INFO] three\n[ERROR] four\n"
+	os.WriteFile(logPath, []byte(content), 0644)
+
+	lines, err := readLastLines(logPath, 2, "")
+	if err != nil {
+		t.Fatalf("readLastLines failed: %v", err)
+	}
+	if len(lines) != 2 || lines[0] != "[INFO] three" || lines[1] != "[ERROR] four" {
+		t.Errorf("Expected last two lines, got %v", lines)
+	}
+
+	lines, _ = readLastLines(logPath, 10, "ERROR")
+	if len(lines) != 2 || lines[0] != "[ERROR] two" {
+		t.Errorf("Expected ERROR lines only, got %v", lines)
+	}
+}
+
+func TestFollowLog(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "hermes-cmd-test-*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	logPath := filepath.Join(tmpDir, "hermes.log")
+	os.WriteFile(logPath, []byte("[INFO] existing\n"), 0644)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	received := make(chan string, 10)
+	done := make(chan error, 1)
+	go func() {
+		done <- followLog(ctx, logPath, "", func(line string) { received <- line })
+	}()
+
+	// Give the follower time to seek to the end
+	time.Sleep(50 * time.Millisecond)
+
+	f, _ := os.OpenFile(logPath, os.O_APPEND|os.O_WRONLY, 0644)
+	f.WriteString("[INFO] par")
+	time.Sleep(150 * time.Millisecond)
+	f.WriteString("tial\n")
+	f.Close()
+
+	select {
+	case line := <-received:
+		if line != "[INFO] partial" {
+			t.Errorf("Expected appended line to be joined, got %q", line)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Timed out waiting for appended line")
+	}
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("followLog returned error: %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("followLog did not stop after cancel")
+	}
+}
+
+func TestImportIssueFeature(t *testing.T) {
+	issues := []github.Issue{
+		{Number: 1, Title: "Fix crash", Body: "Crashes on start", Labels: []github.Label{{Name: "bug"}}},
+		{Number: 2, Title: "Add export", Labels: []github.Label{{Name: "enhancement"}}, Milestone: &github.Milestone{Title: "v2.0"}},
+		{Number: 3, Title: "Fix typo", Labels: []github.Label{{Name: "bug"}, {Name: "docs"}}, Assignee: &github.User{Login: "octo"}},
+		{Number: 4, Title: "Misc cleanup"},
+	}
+
+	groups := groupIssuesByLabel(issues)
+	if len(groups) != 3 {
+		t.Fatalf("expected 3 groups, got %d", len(groups))
+	}
+	if groups[0].Name != "bug" || len(groups[0].Issues) != 2 {
+		t.Errorf("expected bug group with 2 issues, got %s (%d)", groups[0].Name, len(groups[0].Issues))
+	}
+	if groups[2].Name != unlabelledFeature {
+		t.Errorf("expected unlabelled group, got %s", groups[2].Name)
+	}
+
+	content := formatIssueFeature(4, 10, "owner/repo", groups[0])
+	feature, err := task.ParseFeature(content, "004-bug.md")
+	if err != nil {
+		t.Fatalf("generated feature does not parse: %v", err)
+	}
+	if feature.ID != "F004" || feature.Name != "bug" {
+		t.Errorf("unexpected feature %s: %s", feature.ID, feature.Name)
+	}
+	if len(feature.Tasks) != 2 || feature.Tasks[0].ID != "T010" || feature.Tasks[1].ID != "T011" {
+		t.Fatalf("expected tasks T010 and T011, got %+v", feature.Tasks)
+	}
+	if feature.Tasks[0].Description != "Crashes on start" {
+		t.Errorf("expected issue body as description, got %q", feature.Tasks[0].Description)
+	}
+	if !strings.Contains(content, "<!-- Assignee: @octo -->") {
+		t.Error("expected assignee comment")
+	}
+
+	content = formatIssueFeature(5, 12, "owner/repo", groups[1])
+	feature, _ = task.ParseFeature(content, "005-enhancement.md")
+	if feature.TargetVersion != "v2.0" {
+		t.Errorf("expected milestone as target version, got %q", feature.TargetVersion)
+	}
+}
+
+func TestGraphExecute(t *testing.T) {
+	cleanup := setupTaskDir(t)
+	defer cleanup()
+
+	if err := graphExecute(&graphOptions{format: "dot", output: "tasks.dot"}); err != nil {
+		t.Fatal(err)
+	}
+	dot, _ := os.ReadFile("tasks.dot")
+	if !strings.Contains(string(dot), `"T001" -> "T002";`) {
+		t.Errorf("expected dependency edge in DOT output, got:\n%s", dot)
+	}
+
+	if err := graphExecute(&graphOptions{format: "mermaid", output: "tasks.mmd"}); err != nil {
+		t.Fatal(err)
+	}
+	mermaid, _ := os.ReadFile("tasks.mmd")
+	if !strings.HasPrefix(string(mermaid), "flowchart") || !strings.Contains(string(mermaid), "T001 --> T002") {
+		t.Errorf("expected Mermaid flowchart, got:\n%s", mermaid)
+	}
+
+	if err := graphExecute(&graphOptions{format: "png"}); err == nil {
+		t.Error("expected error for unknown format")
+	}
+	if err := graphExecute(&graphOptions{format: "mermaid", open: true}); err == nil {
+		t.Error("expected error for --open with mermaid")
+	}
+}
+
+func TestConfigSetGet(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "hermes-cmd-test-*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+	t.Setenv("HOME", filepath.Join(tmpDir, "home"))
+
+	if err := configSetExecute(tmpDir, "ai.timeout", "600", false); err != nil {
+		t.Fatal(err)
+	}
+	if err := configSetExecute(tmpDir, "ai.coding", "droid", true); err != nil {
+		t.Fatal(err)
+	}
+	if err := configSetExecute(tmpDir, "ai.timeout", "ten", false); err == nil {
+		t.Error("expected error for invalid integer")
+	}
+
+	cfg, _ := config.Load(tmpDir)
+	if cfg.AI.Timeout != 600 {
+		t.Errorf("expected project timeout 600, got %d", cfg.AI.Timeout)
+	}
+	if cfg.AI.Coding != "droid" {
+		t.Errorf("expected global coding droid, got %s", cfg.AI.Coding)
+	}
+
+	global, _ := loadConfigFor(tmpDir, true)
+	if global.AI.Timeout != config.DefaultConfig().AI.Timeout {
+		t.Errorf("expected global timeout to stay default, got %d", global.AI.Timeout)
+	}
+}
+
+func TestTaskPriority(t *testing.T) {
+	cleanup := setupTaskDir(t)
+	defer cleanup()
+
+	if err := taskPriorityExecute("T002", "P5", false); err == nil {
+		t.Error("expected error for invalid priority")
+	}
+	if err := taskPriorityExecute("T002", "p1", true); err != nil {
+		t.Fatal(err)
+	}
+	updated, _ := task.NewReader(".").GetTaskByID("T002")
+	if updated.Priority != task.PriorityP1 {
+		t.Errorf("expected P1, got %s", updated.Priority)
+	}
+}
+
+func TestResumePoint(t *testing.T) {
+	cleanup := setupTaskDir(t)
+	defer cleanup()
+
+	breaker := circuit.New(".")
+	breaker.Initialize()
+
+	resumeTask, _, err := findResumePoint(".", breaker)
+	if err != nil || resumeTask != nil {
+		t.Fatalf("expected no task to resume, got %v (%v)", resumeTask, err)
+	}
+
+	task.NewStatusUpdater(".").UpdateTaskStatus("T001", task.StatusInProgress)
+	breaker.AddLoopResult(true, false, 4)
+
+	resumeTask, loop, err := findResumePoint(".", breaker)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resumeTask == nil || resumeTask.ID != "T001" || loop != 4 {
+		t.Errorf("expected to resume T001 at loop 4, got %v at %d", resumeTask, loop)
+	}
+
+	reset, err := resetInProgressTasks(".")
+	if err != nil || len(reset) != 1 || reset[0] != "T001" {
+		t.Fatalf("expected T001 to be reset, got %v (%v)", reset, err)
+	}
+	if reset, _ := task.NewReader(".").GetTaskByID("T001"); reset.Status != task.StatusNotStarted {
+		t.Errorf("expected NOT_STARTED, got %s", reset.Status)
+	}
+}
+
+func TestInitFromTemplateRepo(t *testing.T) {
+	tmplDir, err := os.MkdirTemp("", "hermes-cmd-test-*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmplDir)
+
+	os.MkdirAll(filepath.Join(tmplDir, ".hermes", "tasks"), 0755)
+	os.WriteFile(filepath.Join(tmplDir, ".hermes", "tasks", "001-setup.md"), []byte("# Feature 1: Setup\n"), 0644)
+	os.WriteFile(filepath.Join(tmplDir, "PROMPT.md"), []byte("# Template prompt\n"), 0644)
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"add", "-A"},
+		{"-c", "user.name=Test", "-c", "user.email=test@example.com", "commit", "-q", "-m", "template"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = tmplDir
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v: %s", args, err, output)
+		}
+	}
+
+	if !isRepoTemplate(tmplDir) || !isRepoTemplate("acme/template") || isRepoTemplate("PROMPT.md") {
+		t.Error("unexpected isRepoTemplate result")
+	}
+
+	projectDir, err := os.MkdirTemp("", "hermes-cmd-test-*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(projectDir)
+
+	if err := initExecute(projectDir, &initOptions{template: tmplDir}); err != nil {
+		t.Fatalf("init failed: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(projectDir, ".hermes", "tasks", "001-setup.md")); err != nil {
+		t.Error("expected template task file to be copied")
+	}
+	content, _ := os.ReadFile(filepath.Join(projectDir, ".hermes", "PROMPT.md"))
+	if string(content) != "# Template prompt\n" {
+		t.Errorf("expected template PROMPT.md, got %q", content)
+	}
+
+	cfg, err := config.Load(projectDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Template.Source == "" {
+		t.Error("expected template.source to be stored in config")
+	}
+
+	if err := initExecute(projectDir, &initOptions{template: tmplDir, templateRef: "no-such-ref"}); err == nil {
+		t.Error("expected error for unknown template ref")
+	}
+}
+
+func TestCompletionHook(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hook test uses sh")
+	}
+
+	tk := &task.Task{ID: "T002", Name: "Add hashing", FeatureID: "F001"}
+	hook := completionHook{command: `echo "$HERMES_TASK_ID $HERMES_FEATURE_ID $HERMES_PROGRESS_PCT $HERMES_TASK_NAME"`}
+	output, err := hook.run(context.Background(), tk, 66.7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if output != "T002 F001 67 Add hashing" {
+		t.Errorf("unexpected hook output %q", output)
+	}
+
+	hook = completionHook{command: "exit 3"}
+	if _, err := hook.run(context.Background(), tk, 0); err == nil {
+		t.Error("expected error for failing hook")
+	}
+
+	hook = completionHook{command: "sleep 5", timeout: 50 * time.Millisecond}
+	if _, err := hook.run(context.Background(), tk, 0); err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Errorf("expected timeout error, got %v", err)
+	}
+}
+
+func TestSearchLogs(t *testing.T) {
+	logDir, err := os.MkdirTemp("", "hermes-cmd-test-*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(logDir)
+
+	old := time.Now().Add(-2 * time.Hour).Format("2006-01-02 15:04:05")
+	recent := time.Now().Add(-10 * time.Minute).Format("2006-01-02 15:04:05")
+	os.WriteFile(filepath.Join(logDir, "hermes.log"), []byte(
+		"["+old+"] [ERROR] task T042 failed\n"+
+			"["+recent+"] [INFO] Working on task T042\n"+
+			"continuation of T042 output\n"+
+			"["+recent+"] [ERROR] timeout\n"), 0644)
+	os.MkdirAll(filepath.Join(logDir, "parallel"), 0755)
+	os.WriteFile(filepath.Join(logDir, "parallel", "worker-1.log"), []byte(
+		"["+recent+"] [W1] Starting task T042\n"), 0644)
+	os.WriteFile(filepath.Join(logDir, "notes.txt"), []byte("T042\n"), 0644)
+
+	matches, err := searchLogs(logDir, "t042", "", time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(matches) != 4 {
+		t.Fatalf("expected 4 matches, got %+v", matches)
+	}
+	if matches[0].File != "hermes.log" || matches[0].Line != 1 {
+		t.Errorf("unexpected first match %+v", matches[0])
+	}
+	if matches[3].File != "parallel/worker-1.log" {
+		t.Errorf("expected worker log match, got %+v", matches[3])
+	}
+
+	// --since skips the old line; the continuation line keeps the previous timestamp
+	matches, _ = searchLogs(logDir, "T042", "", time.Now().Add(-time.Hour))
+	if len(matches) != 3 || matches[0].Line != 2 || matches[1].Line != 3 {
+		t.Errorf("expected lines 2 and 3 of hermes.log and the worker line, got %+v", matches)
+	}
+
+	matches, _ = searchLogs(logDir, "", "ERROR", time.Now().Add(-time.Hour))
+	if len(matches) != 1 || matches[0].Line != 4 {
+		t.Errorf("expected only the recent ERROR line, got %+v", matches)
+	}
+
+	if _, ok := parseLogTime(`{"ts":"2025-01-02T03:04:05Z","level":"INFO","msg":"x"}`); !ok {
+		t.Error("expected JSON timestamp to parse")
+	}
+
+	// JSON lines are filtered by their level field, not by the message text
+	if !matchesLevel(`{"ts":"2025-01-02T03:04:05Z","level":"ERROR","msg":"x"}`, "ERROR") {
+		t.Error("expected the JSON ERROR line to match --level ERROR")
+	}
+	if matchesLevel(`{"ts":"2025-01-02T03:04:05Z","level":"INFO","msg":"[ERROR] quoted"}`, "ERROR") {
+		t.Error("expected the JSON INFO line not to match --level ERROR")
+	}
+}
+
+func TestResolveStrategy(t *testing.T) {
+	cleanup := setupTaskDir(t)
+	defer cleanup()
+
+	reader := task.NewReader(".")
+	tests := []struct {
+		flags     map[string]string
+		parallel  bool // config
+		strategy  string
+		requested string
+		wantErr   bool
+	}{
+		{nil, false, "sequential", "sequential", false},
+		{nil, true, "parallel", "parallel", false},
+		{map[string]string{"parallel": "true"}, false, "parallel", "parallel", false},
+		{map[string]string{"strategy": "sequential"}, true, "sequential", "sequential", false},
+		// T002 depends on T001, so no batch has more than one task
+		{map[string]string{"strategy": "auto"}, false, "sequential", "auto", false},
+		{map[string]string{"strategy": "fastest"}, false, "", "", true},
+		{map[string]string{"strategy": "sequential", "parallel": "true"}, false, "", "", true},
+	}
+
+	for _, tt := range tests {
+		cmd := NewRunCmd()
+		for name, value := range tt.flags {
+			cmd.Flags().Set(name, value)
+		}
+		cfg := config.DefaultConfig()
+		cfg.Parallel.Enabled = tt.parallel
+
+		strategy, requested, err := resolveStrategy(cmd, cfg, reader, nil)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("flags %v: unexpected error %v", tt.flags, err)
+			continue
+		}
+		if strategy != tt.strategy || requested != tt.requested {
+			t.Errorf("flags %v: expected %s (%s), got %s (%s)", tt.flags, tt.strategy, tt.requested, strategy, requested)
+		}
+	}
+
+	// Two independent tasks run in parallel with auto
+	statusUpdater := task.NewStatusUpdater(".")
+	statusUpdater.UpdateTaskStatus("T001", task.StatusCompleted)
+	os.WriteFile(filepath.Join(".hermes", "tasks", "002-more.md"), []byte("# Feature 2: More\n\n**Feature ID:** F002\n\n### T003: Third task\n\n**Status:** NOT_STARTED\n**Priority:** P2\n"), 0644)
+
+	cmd := NewRunCmd()
+	cmd.Flags().Set("strategy", "auto")
+	if strategy, _, err := resolveStrategy(cmd, config.DefaultConfig(), reader, nil); err != nil || strategy != "parallel" {
+		t.Errorf("expected auto to pick parallel, got %s, %v", strategy, err)
+	}
+}
+
+func TestRefinePRD(t *testing.T) {
+	logger, err := ui.NewLogger(t.TempDir(), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer logger.Close()
+
+	provider := mock.NewMockProvider()
+	provider.SetResponse("Add offline mode", &ai.ExecuteResult{Output: "# PRD v2", Success: true})
+	provider.SetResponse("Drop payments", &ai.ExecuteResult{Output: "# PRD v3", Success: true})
+
+	cfg := config.DefaultConfig()
+	cfg.AI.StreamOutput = false
+	gen := idea.NewGenerator(provider, cfg, logger)
+
+	in := bufio.NewReader(strings.NewReader("Add offline mode\nDrop payments\ndone\nnever read\n"))
+	var out strings.Builder
+	revisions, err := refinePRD(gen, "todo app", "# PRD v1", &ideaOptions{timeout: 10, language: "en"}, in, &out)
+	if err != nil {
+		t.Fatalf("refinePRD failed: %v", err)
+	}
+
+	if len(revisions) != 3 || revisions[0].Request != "todo app" || revisions[2].Content != "# PRD v3" {
+		t.Fatalf("unexpected revisions: %+v", revisions)
+	}
+	if provider.CallCount() != 2 {
+		t.Errorf("expected 2 revisions, got %d AI calls", provider.CallCount())
+	}
+	calls := provider.Calls()
+	if !strings.HasPrefix(calls[1], "Revise the following PRD:\n\n# PRD v2") {
+		t.Errorf("expected the second revision to start from version 2, got %q", calls[1])
+	}
+	if !strings.Contains(out.String(), "What to change?") || !strings.Contains(out.String(), "version 3") {
+		t.Errorf("unexpected output:\n%s", out.String())
+	}
+
+	// Revisions are appended to the history file
+	path := idea.HistoryPath(t.TempDir())
+	idea.AppendHistory(path, revisions[:1])
+	idea.AppendHistory(path, revisions[1:])
+	history, err := idea.LoadHistory(path)
+	if err != nil || len(history) != 3 || history[1].Request != "Add offline mode" {
+		t.Errorf("unexpected history: %+v, %v", history, err)
+	}
+}
+
+func TestFileWatcher(t *testing.T) {
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, "src"), 0755)
+
+	w, err := newFileWatcher(dir, "*.go", 50*time.Millisecond, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+
+	expectChange := func(want string) {
+		t.Helper()
+		select {
+		case file := <-w.Changes():
+			if file != want {
+				t.Errorf("expected change to %s, got %s", want, file)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatalf("expected a change to %s", want)
+		}
+	}
+	expectNoChange := func() {
+		t.Helper()
+		select {
+		case file := <-w.Changes():
+			t.Errorf("unexpected change to %s", file)
+		case <-time.After(200 * time.Millisecond):
+		}
+	}
+
+	// A burst of writes is reported once
+	for i := 0; i < 3; i++ {
+		os.WriteFile(filepath.Join(dir, "src", "main.go"), []byte(strings.Repeat("x", i)), 0644)
+	}
+	expectChange("src/main.go")
+	expectNoChange()
+
+	os.WriteFile(filepath.Join(dir, "README.md"), []byte("docs"), 0644)
+	expectNoChange()
+
+	// New directories are watched too
+	os.MkdirAll(filepath.Join(dir, "pkg"), 0755)
+	time.Sleep(100 * time.Millisecond)
+	os.WriteFile(filepath.Join(dir, "pkg", "util.go"), []byte("package pkg"), 0644)
+	expectChange("pkg/util.go")
+
+	os.WriteFile(filepath.Join(dir, "src", "main.go"), []byte("changed"), 0644)
+	time.Sleep(200 * time.Millisecond)
+	w.Drain()
+	expectNoChange()
+}
+
+func TestFileWatcherTaskFiles(t *testing.T) {
+	dir := t.TempDir()
+
+	w, err := newFileWatcher(dir, "*.go", 50*time.Millisecond, []string{"./api.go"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+
+	os.WriteFile(filepath.Join(dir, "other.go"), []byte("package main"), 0644)
+	select {
+	case file := <-w.Changes():
+		t.Errorf("unexpected change to %s outside the task files", file)
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	os.WriteFile(filepath.Join(dir, "api.go"), []byte("package main"), 0644)
+	select {
+	case file := <-w.Changes():
+		if file != "api.go" {
+			t.Errorf("expected change to api.go, got %s", file)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected a change to api.go")
+	}
+
+	if _, err := newFileWatcher(dir, "*.go", 0, nil); err == nil {
+		t.Error("expected error for a zero debounce")
+	}
+}
+
+func TestPrdValidate(t *testing.T) {
+	prd := "# Product\n\n## Overview\n\nTODO outside features is fine\n\n" +
+		"## Feature 1: Login\n\nUsers log in.\n\n### Acceptance Criteria\n\n- User can log in\n\n" +
+		"## Feature 2: Logout\n\nUsers log out [TBD].\n\n```\n## Feature in a code block\n```\n\n" +
+		"## Feature 3: Search\n\n" + strings.Repeat("word ", 30) + "\n\nAcceptance criteria:\n1. Results appear\n"
+
+	features := prdFeatures(prd)
+	if len(features) != 3 || features[0].Name != "Feature 1: Login" || features[1].Line != 15 {
+		t.Fatalf("unexpected features: %+v", features)
+	}
+
+	issues := validatePRD(features, 20)
+	var messages []string
+	for _, issue := range issues {
+		if issue.IsError {
+			t.Errorf("unexpected error: %s", issue.Message)
+		}
+		messages = append(messages, fmt.Sprintf("%d %s", issue.Line, issue.Message))
+	}
+	want := []string{
+		`15 "Feature 2: Logout" has no acceptance criteria`,
+		`17 placeholder "[TBD]" in "Feature 2: Logout"`,
+		`23 "Feature 3: Search" has 35 words (max 20), consider splitting it`,
+	}
+	if strings.Join(messages, "\n") != strings.Join(want, "\n") {
+		t.Errorf("unexpected issues:\n%s", strings.Join(messages, "\n"))
+	}
+
+	path := filepath.Join(t.TempDir(), "prd.md")
+	os.WriteFile(path, []byte(prd), 0644)
+	var buf bytes.Buffer
+	if err := prdValidateExecute(path, 5000, &buf); err != nil {
+		t.Errorf("warnings should not fail validation: %v", err)
+	}
+	if !strings.Contains(buf.String(), "3 feature(s), 0 error(s), 2 warning(s)") {
+		t.Errorf("unexpected summary:\n%s", buf.String())
+	}
+
+	os.WriteFile(path, []byte("# Product\n\n## Overview\n"), 0644)
+	buf.Reset()
+	if err := prdValidateExecute(path, 5000, &buf); err == nil {
+		t.Error("expected error for a PRD without features")
+	}
+}
+
+func TestConfirmCost(t *testing.T) {
+	for answer, want := range map[string]bool{"y\n": true, "YES\n": true, "n\n": false, "": false} {
+		var out bytes.Buffer
+		if got := confirmCost(12.5, 5, bufio.NewReader(strings.NewReader(answer)), &out); got != want {
+			t.Errorf("answer %q: expected %v, got %v", answer, want, got)
+		}
+		if !strings.Contains(out.String(), "$12.50 exceeds $5.00") {
+			t.Errorf("unexpected prompt: %q", out.String())
+		}
+	}
+}
+
+func TestRotateLogs(t *testing.T) {
+	logDir := t.TempDir()
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	old := now.Add(-10 * 24 * time.Hour)
+
+	writeLog := func(rel, content string, modTime time.Time) {
+		path := filepath.Join(logDir, rel)
+		os.MkdirAll(filepath.Dir(path), 0755)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		os.Chtimes(path, modTime, modTime)
+	}
+	writeLog("hermes.log", "line 1\nline 2\nline 3\nline 4\n", old)
+	writeLog("parallel/worker-1.log", "worker\n", old)
+	writeLog("parallel/merge.log", "recent\n", now)
+	for i, name := range []string{"2026-01-01.tar.gz", "2026-01-02.tar.gz"} {
+		day := now.Add(-time.Duration(30-i) * 24 * time.Hour)
+		writeLog(filepath.Join(logArchiveDir, name), "", day)
+	}
+
+	opts := logRotateOptions{MaxAge: 7 * 24 * time.Hour, MaxArchives: 2, KeepLines: 1}
+	result, err := rotateLogs(logDir, opts, now)
+	if err != nil {
+		t.Fatalf("rotateLogs failed: %v", err)
+	}
+
+	if filepath.Base(result.Archive) != "2026-03-10.tar.gz" {
+		t.Errorf("unexpected archive %s", result.Archive)
+	}
+	if len(result.Archived) != 1 || result.Archived[0] != "parallel/worker-1.log" || result.DroppedLines != 3 {
+		t.Errorf("unexpected result: %+v", result)
+	}
+	if len(result.Removed) != 1 || result.Removed[0] != "2026-01-01.tar.gz" {
+		t.Errorf("expected the oldest archive to be removed, got %v", result.Removed)
+	}
+
+	if data, _ := os.ReadFile(filepath.Join(logDir, "hermes.log")); string(data) != "line 4\n" {
+		t.Errorf("expected hermes.log to keep its last line, got %q", data)
+	}
+	if _, err := os.Stat(filepath.Join(logDir, "parallel", "worker-1.log")); !os.IsNotExist(err) {
+		t.Error("expected the old worker log to be removed")
+	}
+	if _, err := os.Stat(filepath.Join(logDir, "parallel", "merge.log")); err != nil {
+		t.Error("expected the recent log to be kept")
+	}
+
+	// The archive holds the old file and the dropped lines
+	file, err := os.Open(result.Archive)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	contents := make(map[string]string)
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, _ := io.ReadAll(tr)
+		contents[header.Name] = string(data)
+	}
+	if contents["parallel/worker-1.log"] != "worker\n" || contents["hermes.log"] != "line 1\nline 2\nline 3\n" {
+		t.Errorf("unexpected archive contents: %v", contents)
+	}
+
+	// A second rotation on the same day does not overwrite the archive
+	writeLog("hermes.log", "a\nb\n", now)
+	second, err := rotateLogs(logDir, opts, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if filepath.Base(second.Archive) != "2026-03-10-2.tar.gz" {
+		t.Errorf("unexpected second archive %s", second.Archive)
+	}
+
+	if _, err := rotateLogs(logDir, logRotateOptions{MaxAge: time.Hour}, now); err == nil {
+		t.Error("expected an error for --max-archives 0")
+	}
+}
+
+func TestResolveExtraArgs(t *testing.T) {
+	configured := map[string]string{"model": "claude-sonnet-4-5", "verbose": ""}
+	args, err := resolveExtraArgs(configured, []string{"model=claude-opus-4-5"}, false)
+	if err != nil {
+		t.Fatalf("resolveExtraArgs failed: %v", err)
+	}
+	if args["model"] != "claude-opus-4-5" || len(args) != 2 {
+		t.Errorf("Expected --ai-args to override the config, got %v", args)
+	}
+	if configured["model"] != "claude-sonnet-4-5" {
+		t.Error("resolveExtraArgs should not modify the config")
+	}
+
+	if _, err := resolveExtraArgs(nil, []string{"no-permission-check"}, false); err == nil {
+		t.Error("Expected unsafe --ai-args to be rejected")
+	}
+	if _, err := resolveExtraArgs(map[string]string{"no-permission-check": ""}, nil, true); err != nil {
+		t.Errorf("Expected --allow-unsafe-args to allow it, got %v", err)
+	}
+}
+
+func TestStallTracker(t *testing.T) {
+	stalls := &stallTracker{max: 2}
+	steps := []struct {
+		taskID      string
+		hasProgress bool
+		stalled     bool
+	}{
+		{"T001", false, false},
+		{"T001", true, false},
+		{"T001", false, false},
+		{"T002", false, false}, // another task starts counting again
+		{"T002", false, true},
+	}
+	for i, step := range steps {
+		if got := stalls.record(step.taskID, step.hasProgress); got != step.stalled {
+			t.Errorf("step %d: record(%s, %v) = %v, want %v", i, step.taskID, step.hasProgress, got, step.stalled)
+		}
+	}
+}
+
+func TestExitCode(t *testing.T) {
+	stalled := &ExitError{Code: exitStalled, Err: fmt.Errorf("task T001 stalled")}
+	tests := []struct {
+		err  error
+		want int
+	}{
+		{nil, 0},
+		{fmt.Errorf("failed"), 1},
+		{stalled, 2},
+		{fmt.Errorf("run: %w", stalled), 2},
+	}
+	for _, tt := range tests {
+		if got := ExitCode(tt.err); got != tt.want {
+			t.Errorf("ExitCode(%v) = %d, want %d", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestTaskDeps(t *testing.T) {
+	cleanup := setupTaskDir(t)
+	defer cleanup()
+
+	var content strings.Builder
+	content.WriteString("# Feature 1: Test\n\n**Feature ID:** F001\n**Status:** IN_PROGRESS\n")
+	for _, tk := range []struct{ id, status, deps string }{
+		{"T002", "COMPLETED", "None"},
+		{"T003", "COMPLETED", "None"},
+		{"T004", "IN_PROGRESS", "T002"},
+		{"T005", "NOT_STARTED", "T003, T004"},
+	} {
+		fmt.Fprintf(&content, "\n### %s: Task\n\n**Status:** %s\n**Priority:** P2\n**Dependencies:** %s\n\n---\n", tk.id, tk.status, tk.deps)
+	}
+	os.WriteFile(filepath.Join(".hermes", "tasks", "001-test.md"), []byte(content.String()), 0644)
+
+	reader := task.NewReader(".")
+	var out bytes.Buffer
+	err := taskDepsExecute(reader, "T005", false, &out)
+	if err == nil || !strings.Contains(err.Error(), "T004") {
+		t.Errorf("expected an error naming T004, got %v", err)
+	}
+	expected := `T005 (NOT_STARTED)
+  └── T003 (COMPLETED) ✓
+  └── T004 (IN_PROGRESS) ⟳
+        └── T002 (COMPLETED) ✓
+`
+	if out.String() != expected {
+		t.Errorf("unexpected tree:\n%s", out.String())
+	}
+
+	out.Reset()
+	taskDepsExecute(reader, "T005", true, &out)
+	if out.String() != "T005 (NOT_STARTED)\n  └── T004 (IN_PROGRESS) ⟳\n" {
+		t.Errorf("unexpected blocking-only tree:\n%s", out.String())
+	}
+
+	out.Reset()
+	if err := taskDepsExecute(reader, "T004", false, &out); err != nil {
+		t.Errorf("expected no error when all dependencies are completed, got %v", err)
+	}
+}
+
+func TestTaskSort(t *testing.T) {
+	cleanup := setupTaskDir(t)
+	defer cleanup()
+
+	// T002 depends on T001, so putting it first breaks the dependency order
+	first := strings.Index(testTaskFile, "### T001")
+	second := strings.Index(testTaskFile, "### T002")
+	swapped := testTaskFile[:first] + testTaskFile[second:] + "\n---\n\n" + strings.TrimSuffix(testTaskFile[first:second], "---\n\n")
+	os.WriteFile(filepath.Join(".hermes", "tasks", "001-test.md"), []byte(swapped), 0644)
+
+	reader := task.NewReader(".")
+	var out bytes.Buffer
+	if err := taskSortExecute(reader, "F001", "dependency", &out); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "-  1. T002: Second task") || !strings.Contains(out.String(), "+  1. T001: First task") {
+		t.Errorf("expected a diff of the moved tasks, got:\n%s", out.String())
+	}
+	feature, _ := reader.GetFeatureByID("F001")
+	if len(feature.Tasks) != 2 || feature.Tasks[0].ID != "T001" || feature.Tasks[1].ID != "T002" {
+		t.Errorf("expected T001 first after sorting by dependency, got %+v", feature.Tasks)
+	}
+
+	out.Reset()
+	if err := taskSortExecute(reader, "F001", "id", &out); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "already sorted by id") {
+		t.Errorf("expected no change, got:\n%s", out.String())
+	}
+
+	if err := taskSortExecute(reader, "F009", "id", &out); err == nil {
+		t.Error("expected an error for an unknown feature")
+	}
+}
+
+func TestTaskImport(t *testing.T) {
+	cleanup := setupTaskDir(t)
+	defer cleanup()
+
+	export := `{"issues": [
+  {"key": "PROJ-1", "fields": {"summary": "Checkout", "issuetype": {"name": "Epic"}}},
+  {"key": "PROJ-2", "fields": {
+    "summary": "Payment form", "issuetype": {"name": "Story"}, "priority": {"name": "Highest"},
+    "parent": {"key": "PROJ-1", "fields": {"issuetype": {"name": "Epic"}}},
+    "issuelinks": [{"type": {"name": "Blocks", "inward": "is blocked by", "outward": "blocks"}, "inwardIssue": {"key": "PROJ-3"}}],
+    "subtasks": [{"key": "PROJ-4", "fields": {"summary": "Validate card", "issuetype": {"name": "Sub-task", "subtask": true}}}]
+  }},
+  {"key": "PROJ-4", "fields": {"summary": "Validate card", "issuetype": {"name": "Sub-task", "subtask": true}, "parent": {"key": "PROJ-2"}, "customfield_10016": 2}},
+  {"key": "PROJ-3", "fields": {"summary": "Card API", "issuetype": {"name": "Task"}, "customfield_10016": 3, "customfield_10014": "PROJ-1"}},
+  {"key": "PROJ-5", "fields": {"summary": "Fix typo", "issuetype": {"name": "Bug"}, "status": {"name": "Done", "statusCategory": {"key": "done"}},
+    "issuelinks": [{"type": {"name": "Blocks"}, "outwardIssue": {"key": "OTHER-1"}}]}}
+]}`
+	os.WriteFile("export.json", []byte(export), 0644)
+
+	var out bytes.Buffer
+	if err := taskImportExecute(".", "jira", "export.json", &out); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "Imported: 3 tasks in 2 features") || !strings.Contains(out.String(), "Skipped: 1 dependency link") {
+		t.Errorf("unexpected output:\n%s", out.String())
+	}
+
+	reader := task.NewReader(".")
+	epic, err := reader.GetFeatureByID("F002")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if epic.Name != "Checkout" || len(epic.Tasks) != 2 {
+		t.Fatalf("expected the epic with 2 tasks, got %+v", epic)
+	}
+	story, api := epic.Tasks[0], epic.Tasks[1]
+	if story.ID != "T003" || story.Priority != task.PriorityP1 || story.EstimatedEffort != "SP: 2" {
+		t.Errorf("unexpected story task: %+v", story)
+	}
+	if len(story.Dependencies) != 1 || story.Dependencies[0] != api.ID {
+		t.Errorf("expected the story to depend on %s, got %v", api.ID, story.Dependencies)
+	}
+	if len(story.SuccessCriteria) != 1 || !strings.Contains(story.SuccessCriteria[0], "PROJ-4: Validate card") {
+		t.Errorf("expected the sub-task as a success criterion, got %v", story.SuccessCriteria)
+	}
+	if api.EstimatedEffort != "SP: 3" {
+		t.Errorf("expected SP: 3, got %q", api.EstimatedEffort)
+	}
+
+	noEpic, err := reader.GetFeatureByID("F003")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if noEpic.Name != noEpicFeature || len(noEpic.Tasks) != 1 || noEpic.Tasks[0].Status != task.StatusCompleted {
+		t.Errorf("expected the bug in the No Epic feature, got %+v", noEpic)
+	}
+
+	if err := taskImportExecute(".", "trello", "export.json", &out); err == nil {
+		t.Error("expected an error for an unsupported source")
+	}
+}
+
+func TestPrintDryRunAI(t *testing.T) {
+	cleanup := setupTaskDir(t)
+	defer cleanup()
+
+	next, _ := task.NewReader(".").GetNextTask()
+	injector := prompt.NewInjector(".")
+	injector.Write(strings.Repeat("Follow the style guide. ", 100))
+	executor := ai.NewTaskExecutor(ai.NewClaudeProvider(), ".")
+	opts := executor.TaskOptions(next, injector.TaskPrompt(next), false)
+
+	var out bytes.Buffer
+	printDryRunAI(&out, ai.NewClaudeProvider(), next, opts)
+	output := out.String()
+	if !strings.Contains(output, "Task: T001 - First task") || !strings.Contains(output, "claude --output-format stream-json --verbose --print <prompt>") {
+		t.Errorf("expected the task and command, got:\n%s", output)
+	}
+	if !strings.Contains(output, fmt.Sprintf("Prompt (%d characters):", len(opts.Prompt))) || !strings.Contains(output, "...\n") {
+		t.Errorf("expected the prompt truncated, got:\n%s", output)
+	}
+	if !strings.Contains(output, fmt.Sprintf("Estimated tokens: %d ", ai.EstimateTokens(opts.Prompt))) {
+		t.Errorf("expected the token estimate, got:\n%s", output)
+	}
+	if content, _ := injector.Read(); strings.Contains(content, "T001") {
+		t.Error("expected the prompt file not to change")
+	}
+
+	if got := truncateText("héllo", 2); got != "h..." {
+		t.Errorf("expected the cut before a multi-byte character, got %q", got)
+	}
+}
+
+func TestTaskArchive(t *testing.T) {
+	cleanup := setupTaskDir(t)
+	defer cleanup()
+
+	var out bytes.Buffer
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	if err := taskArchiveExecute("F001", false, now, &out); err == nil || !strings.Contains(err.Error(), "unfinished") {
+		t.Fatalf("expected an error for unfinished tasks, got %v", err)
+	}
+	if err := taskArchiveExecute("F001", true, now, &out); err != nil {
+		t.Fatal(err)
+	}
+	archived := filepath.Join(".hermes", "archive", "2026-05", "001-test.md")
+	if _, err := os.Stat(archived); err != nil {
+		t.Fatalf("expected the feature file in the archive: %v", err)
+	}
+
+	cfg, _ := config.LoadFile(config.ProjectPath("."))
+	if len(cfg.Archive) != 1 || cfg.Archive[0].FeatureID != "F001" || cfg.Archive[0].ArchivedAt != "2026-05-01" || cfg.Archive[0].File != filepath.ToSlash(archived) {
+		t.Errorf("expected the archive to be recorded, got %+v", cfg.Archive)
+	}
+
+	out.Reset()
+	if err := taskListExecute(&taskListOptions{sortBy: "id", format: "csv"}, &out); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(out.String(), "T001") {
+		t.Errorf("expected archived tasks to be left out, got:\n%s", out.String())
+	}
+	out.Reset()
+	if err := taskListExecute(&taskListOptions{sortBy: "id", format: "csv", includeArchived: true}, &out); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "T001") {
+		t.Errorf("expected archived tasks with --include-archived, got:\n%s", out.String())
+	}
+
+	out.Reset()
+	if err := taskUnarchiveExecute("F001", &out); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(".hermes", "tasks", "001-test.md")); err != nil {
+		t.Errorf("expected the feature file restored: %v", err)
+	}
+	cfg, _ = config.LoadFile(config.ProjectPath("."))
+	if len(cfg.Archive) != 0 {
+		t.Errorf("expected the archive record removed, got %+v", cfg.Archive)
+	}
+	if err := taskUnarchiveExecute("F001", &out); err == nil {
+		t.Error("expected an error for a feature that is not archived")
+	}
+}
+
+func TestRunProfiler(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "profiles")
+	if _, err := startProfiling(dir, "heap", false); err == nil {
+		t.Error("expected an error for an unknown profile mode")
+	}
+
+	profiler, err := startProfiling(dir, "cpu", true)
+	if err != nil {
+		t.Fatalf("startProfiling failed: %v", err)
+	}
+	files, err := profiler.stop()
+	if err != nil {
+		t.Fatalf("stop failed: %v", err)
+	}
+	if len(files) != 2 || filepath.Base(files[0]) != cpuProfileFile || filepath.Base(files[1]) != traceProfileFile {
+		t.Errorf("expected the CPU profile and trace, got %v", files)
+	}
+
+	profiler, err = startProfiling(dir, "mem", false)
+	if err != nil {
+		t.Fatalf("startProfiling failed: %v", err)
+	}
+	files, err = profiler.stop()
+	if err != nil {
+		t.Fatalf("stop failed: %v", err)
+	}
+	if len(files) != 1 || filepath.Base(files[0]) != memProfileFile {
+		t.Fatalf("expected the heap profile, got %v", files)
+	}
+	if info, err := os.Stat(files[0]); err != nil || info.Size() == 0 {
+		t.Errorf("expected a non-empty heap profile, got %v", err)
+	}
+}
+
+func TestTopHotspots(t *testing.T) {
+	output := "File: hermes\nType: cpu\nShowing nodes accounting for 40ms, 100% of 40ms total\n" +
+		"      flat  flat%   sum%        cum   cum%\n"
+	for i := 1; i <= 12; i++ {
+		output += fmt.Sprintf("       1ms  2.50%%  %d%%        1ms  2.50%%  main.f%d\n", i, i)
+	}
+
+	top := topHotspots(output, 10)
+	if !strings.HasPrefix(top, "File: hermes\n") || !strings.Contains(top, "flat%") {
+		t.Errorf("expected the summary and table header, got:\n%s", top)
+	}
+	if !strings.Contains(top, "main.f10\n") || strings.Contains(top, "main.f11") {
+		t.Errorf("expected the first 10 rows only, got:\n%s", top)
+	}
+
+	if err := profileAnalyzeExecute(filepath.Join(t.TempDir(), "missing.pprof"), io.Discard); err == nil {
+		t.Error("expected an error for a missing profile")
+	}
+}
+
+func TestFilterChangedTasks(t *testing.T) {
+	cleanup := setupTaskDir(t)
+	defer cleanup()
+
+	updater := task.NewStatusUpdater(".")
+	updater.SetSession("3f2b9c1e-0000-4000-8000-000000000000")
+	if err := updater.UpdateTaskStatus("T001", task.StatusInProgress); err != nil {
+		t.Fatal(err)
+	}
+
+	reader := task.NewReader(".")
+	features, _ := reader.GetAllFeatures()
+	tasks, _ := reader.GetAllTasks()
+
+	// T002 has no history and falls back to the feature file's time
+	changed, err := filterChangedTasks(tasks, features, time.Hour, "", time.Now())
+	if err != nil {
+		t.Fatalf("filterChangedTasks failed: %v", err)
+	}
+	if len(changed) != 2 {
+		t.Errorf("expected both tasks changed within the hour, got %v", changed)
+	}
+
+	old := time.Now().Add(-48 * time.Hour)
+	os.Chtimes(features[0].FilePath, old, old)
+	if changed, _ := filterChangedTasks(tasks, features, 24*time.Hour, "", time.Now()); len(changed) != 1 || changed[0].ID != "T001" {
+		t.Errorf("expected only T001 changed within a day, got %v", changed)
+	}
+	if changed, _ := filterChangedTasks(tasks, features, time.Hour, "", time.Now().Add(2*time.Hour)); len(changed) != 0 {
+		t.Errorf("expected no tasks changed within the hour two hours later, got %v", changed)
+	}
+
+	if changed, _ := filterChangedTasks(tasks, features, 0, "3f2b9c1e", time.Now()); len(changed) != 1 || changed[0].ID != "T001" {
+		t.Errorf("expected T001 changed by the session, got %v", changed)
+	}
+	if changed, _ := filterChangedTasks(tasks, features, 0, "00000000", time.Now()); len(changed) != 0 {
+		t.Errorf("expected no tasks for another session, got %v", changed)
+	}
+}
+
+func TestRunWatchFilesRestartsLoop(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the stand-in provider is a shell script")
+	}
+	cleanup := setupTaskDir(t)
+	defer cleanup()
+
+	updater := task.NewStatusUpdater(".")
+	updater.UpdateTaskStatus("T001", task.StatusCompleted)
+	updater.UpdateTaskStatus("T002", task.StatusCompleted)
+
+	// A provider must be installed, though no task is left to call it
+	bin := t.TempDir()
+	os.WriteFile(filepath.Join(bin, "claude"), []byte("#!/bin/sh\nexit 1\n"), 0755)
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cmd := NewRunCmd()
+	cmd.SetContext(ctx)
+	cmd.Flags().Set("ai", "claude")
+	cmd.Flags().Set("watch-files", "*.go")
+	cmd.Flags().Set("watch-debounce", "20ms")
+
+	done := make(chan error, 1)
+	go func() { done <- runExecute(cmd, nil) }()
+
+	logContains := func(text string, count int) bool {
+		data, _ := os.ReadFile(filepath.Join(".hermes", "logs", "hermes.log"))
+		return strings.Count(string(data), text) >= count
+	}
+	waitFor := func(text string, count int) {
+		t.Helper()
+		deadline := time.Now().Add(5 * time.Second)
+		for !logContains(text, count) {
+			select {
+			case err := <-done:
+				t.Fatalf("run returned early: %v", err)
+			default:
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("expected %q in the log %d time(s)", text, count)
+			}
+			time.Sleep(20 * time.Millisecond)
+		}
+	}
+
+	waitFor("Waiting for changes to files matching *.go", 1)
+	os.WriteFile("main.go", []byte("package main"), 0644)
+	waitFor("main.go changed, restarting the loop", 1)
+	waitFor("Waiting for changes to files matching *.go", 2)
+
+	cancel()
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("expected the run to stop on cancel, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("run did not stop on cancel")
+	}
+}
+
+func TestTaskRetryConfig(t *testing.T) {
+	cmd := NewRunCmd()
+	if retry := taskRetryConfig(cmd, 4); retry.MaxRetries != 4 || retry.BackoffMultiplier != ai.DefaultRetryConfig().BackoffMultiplier {
+		t.Errorf("expected the default back-off with 4 attempts, got %+v", retry)
+	}
+
+	cmd.Flags().Set("retry-backoff", "3")
+	cmd.Flags().Set("retry-max-delay", "10s")
+	retry := taskRetryConfig(cmd, 4)
+	if retry.BackoffMultiplier != 3 || retry.MaxDelay != 10*time.Second {
+		t.Errorf("expected the back-off of the flags, got %+v", retry)
+	}
+}
+
+func TestTUIPipe(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("named pipes are not supported on Windows")
+	}
+	path := filepath.Join(t.TempDir(), "hermes.pipe")
+
+	pipe, err := newTUIPipe(path, 2, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	events := pipe.Events(ctx, nil)
+	events <- scheduler.WorkerEvent{Type: scheduler.EventBatchStart, Batch: 1, Batches: 2}
+	events <- scheduler.WorkerEvent{Type: scheduler.EventTaskStart, WorkerID: 2, Task: &task.Task{ID: "T002", Name: "Second task"}}
+
+	// The reader attaches late and is sent the events so far
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	read := make(chan []tui.PipeEvent, 1)
+	go func() {
+		var got []tui.PipeEvent
+		decoder := tui.NewEventDecoder(f)
+		for {
+			event, err := decoder.Decode()
+			if err != nil {
+				break
+			}
+			got = append(got, event)
+		}
+		read <- got
+	}()
+
+	events <- scheduler.WorkerEvent{Type: scheduler.EventTaskDone, WorkerID: 2, Result: &scheduler.TaskResult{
+		TaskID: "T002", TaskName: "Second task", Error: errors.New("tests failed"), Duration: 1500 * time.Millisecond,
+	}}
+	events <- scheduler.WorkerEvent{Type: scheduler.EventRunDone}
+
+	pipe.Close()
+
+	var got []tui.PipeEvent
+	select {
+	case got = <-read:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out reading the pipe")
+	}
+
+	var types []string
+	for _, e := range got {
+		types = append(types, e.Type)
+	}
+	if strings.Join(types, ",") != "start,batch_start,task_start,task_done,run_done" {
+		t.Fatalf("unexpected events %v", types)
+	}
+	if got[0].Workers != 2 || got[0].Total != 3 {
+		t.Errorf("unexpected start event %+v", got[0])
+	}
+	done, ok := got[3].WorkerEvent()
+	if !ok || done.WorkerID != 2 || done.Result.Success || done.Result.Error == nil ||
+		done.Result.Error.Error() != "tests failed" || done.Result.Duration != 1500*time.Millisecond {
+		t.Errorf("unexpected task done event %+v", done.Result)
+	}
+	if _, ok := got[0].WorkerEvent(); ok {
+		t.Error("expected the start event to have no scheduler equivalent")
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("expected the pipe created by the run to be removed")
+	}
+
+	regular := filepath.Join(t.TempDir(), "events.json")
+	os.WriteFile(regular, nil, 0644)
+	if _, err := newTUIPipe(regular, 1, 1); err == nil {
+		t.Error("expected an error for a path that is not a named pipe")
+	}
+}
+
+func TestAnalyticsCalibrate(t *testing.T) {
+	cleanup := setupTaskDir(t)
+	defer cleanup()
+
+	var out bytes.Buffer
+	if err := analyticsCalibrateExecute(".", &out); err == nil {
+		t.Error("expected an error without history")
+	}
+
+	tasks, _ := task.NewReader(".").GetAllTasks()
+	path := analyzer.AnalyticsPath(".")
+	for i := 0; i < 6; i++ {
+		for _, tk := range tasks {
+			analyzer.AppendPrediction(path, analyzer.Prediction{TaskID: tk.ID, Loop: i, Confidence: float64(i) / 5})
+		}
+	}
+	task.NewStatusUpdater(".").UpdateTaskStatus(tasks[0].ID, task.StatusCompleted)
+	if err := analyticsCalibrateExecute(".", &out); err != nil {
+		t.Fatalf("calibrate failed: %v", err)
+	}
+	if c, err := analyzer.LoadCalibrator(analyzer.CalibrationPath(".")); err != nil || c == nil {
+		t.Errorf("expected a saved calibration, got %v, %v", c, err)
+	}
+}
+
+func TestSeedPrompt(t *testing.T) {
+	cleanup := setupTaskDir(t)
+	defer cleanup()
+
+	os.WriteFile("seed.md", []byte("# Custom prompt\n"), 0644)
+	injector := prompt.NewInjector(".")
+
+	seeded, err := seedPrompt(injector, "seed.md", false)
+	if err != nil || seeded != "# Custom prompt\n" {
+		t.Fatalf("expected the seed to be written, got %q, %v", seeded, err)
+	}
+
+	// An existing prompt is kept unless forced
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "# Remote prompt\n")
+	}))
+	defer server.Close()
+	if seeded, _ := seedPrompt(injector, server.URL, false); seeded != "" {
+		t.Errorf("expected the existing prompt to be kept, got %q", seeded)
+	}
+	if _, err := seedPrompt(injector, server.URL, true); err != nil {
+		t.Fatalf("seeding from URL failed: %v", err)
+	}
+	if content, _ := injector.Read(); content != "# Remote prompt\n" {
+		t.Errorf("expected the remote prompt, got %q", content)
+	}
+
+	if _, err := seedPrompt(injector, "missing.md", true); err == nil {
+		t.Error("expected an error for a missing seed file")
+	}
+}
+
+func TestOutputDir(t *testing.T) {
+	cleanup := setupTaskDir(t)
+	defer cleanup()
+
+	os.MkdirAll("src", 0755)
+	os.WriteFile(filepath.Join("src", "a.go"), []byte("package src\n"), 0644)
+	outPath := t.TempDir()
+
+	out, err := newOutputDir(outPath)
+	if err != nil {
+		t.Fatalf("newOutputDir failed: %v", err)
+	}
+	seeded, missing, err := out.seed(".", &task.Task{FilesToTouch: []string{"`src/a.go`", "missing.go", "../outside.go"}})
+	if err != nil {
+		t.Fatalf("seed failed: %v", err)
+	}
+	if len(seeded) != 1 || seeded[0] != "src/a.go" {
+		t.Fatalf("expected only src/a.go to be seeded, got %v", seeded)
+	}
+	if len(missing) != 1 || missing[0] != "missing.go" {
+		t.Errorf("expected missing.go to be reported missing, got %v", missing)
+	}
+
+	// Seeded files are part of the base, only edits are applied
+	os.WriteFile(filepath.Join(outPath, "src", "a.go"), []byte("package src\n\nfunc A() {}\n"), 0644)
+	os.WriteFile(filepath.Join(outPath, "b.go"), []byte("package main\n"), 0644)
+	logger, err := ui.NewLogger(t.TempDir(), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer logger.Close()
+	if err := applyOutputDir(out, true, logger); err != nil {
+		t.Fatalf("no-apply failed: %v", err)
+	}
+	if _, err := os.Stat("b.go"); err == nil {
+		t.Fatal("expected --no-apply to leave the project untouched")
+	}
+
+	files, err := out.apply(".")
+	if err != nil {
+		t.Fatalf("apply failed: %v", err)
+	}
+	if len(files) != 2 {
+		t.Errorf("expected 2 changed files, got %v", files)
+	}
+	if content, _ := os.ReadFile(filepath.Join("src", "a.go")); string(content) != "package src\n\nfunc A() {}\n" {
+		t.Errorf("expected the edit to be applied, got %q", content)
+	}
+	if files, _ := out.apply("."); len(files) != 0 {
+		t.Errorf("expected applied changes not to be applied again, got %v", files)
+	}
+
+	// A conflicting change is kept for the next apply, also across runs
+	os.WriteFile(filepath.Join("src", "a.go"), []byte("package other\n"), 0644)
+	os.WriteFile(filepath.Join(outPath, "src", "a.go"), []byte("package src\n\nfunc B() {}\n"), 0644)
+	var conflict *isolation.PatchConflictError
+	if _, err := out.apply("."); !errors.As(err, &conflict) {
+		t.Fatalf("expected a patch conflict, got %v", err)
+	}
+	reopened, err := newOutputDir(outPath)
+	if err != nil {
+		t.Fatalf("reopening the output dir failed: %v", err)
+	}
+	if files, _ := reopened.changes(); len(files) != 1 {
+		t.Errorf("expected the unapplied change to be kept, got %v", files)
+	}
+
+	if !sameDir(".", ".") || sameDir(".", outPath) {
+		t.Error("unexpected sameDir result")
+	}
+}
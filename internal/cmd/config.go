@@ -0,0 +1,202 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"hermes/internal/config"
+	"hermes/internal/task"
+)
+
+// NewConfigCmd creates the config command
+func NewConfigCmd() *cobra.Command {
+	var global bool
+
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Get and set configuration values",
+		Long: `Read and write settings in .hermes/config.json using dot-notation keys.
+
+Keys follow the JSON layout of the config file, e.g. ai.timeout or
+parallel.maxWorkers (snake_case such as parallel.max_workers also works).
+Use --global to read and write ~/.hermes/config.json instead.`,
+		Example: `  hermes config get ai.timeout
+  hermes config set parallel.max_workers 5
+  hermes config set --global ai.coding claude
+  hermes config list
+  hermes config validate`,
+	}
+
+	cmd.PersistentFlags().BoolVar(&global, "global", false, "Use the global config (~/.hermes/config.json)")
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "get <key>",
+		Short: "Print a configuration value",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return configGetExecute(".", args[0], global)
+		},
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "set <key> <value>",
+		Short: "Set a configuration value",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return configSetExecute(".", args[0], args[1], global)
+		},
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "list",
+		Short: "Print all effective settings",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return configListExecute(".", global)
+		},
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "validate",
+		Short: "Check the configuration for invalid values",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			return configValidateExecute(".", global)
+		},
+	})
+
+	return cmd
+}
+
+// PreRun runs before every command and rejects an invalid config in
+// basePath. The config commands skip the check so that a broken config can
+// still be fixed.
+func PreRun(c *cobra.Command, basePath string) error {
+	cfg, err := config.Load(basePath)
+	if err != nil {
+		return nil
+	}
+
+	for p := c; p != nil; p = p.Parent() {
+		if p.Name() == "config" && p.HasParent() && !p.Parent().HasParent() {
+			return nil
+		}
+	}
+	if err := config.ValidationErrorsToError(cfg.Validate()); err != nil {
+		c.SilenceUsage = true
+		return err
+	}
+	return nil
+}
+
+// newTaskReader creates a task reader that converts efforts with the story
+// point and T-shirt size values of the config in basePath
+func newTaskReader(basePath string) *task.Reader {
+	reader := task.NewReader(basePath)
+	if cfg, err := config.Load(basePath); err == nil {
+		reader.SetEffortUnits(effortUnits(cfg.Task))
+	}
+	return reader
+}
+
+// effortUnits converts the task config to the units of a task.Reader
+func effortUnits(cfg config.TaskConfig) task.EffortUnits {
+	return task.EffortUnits{
+		StoryPoint: cfg.SPToMinutes,
+		Sizes: map[string]int{
+			"XS": cfg.Sizes.XS,
+			"S":  cfg.Sizes.S,
+			"M":  cfg.Sizes.M,
+			"L":  cfg.Sizes.L,
+			"XL": cfg.Sizes.XL,
+		},
+	}
+}
+
+// loadConfigFor returns the effective config, or the global file merged with defaults
+func loadConfigFor(basePath string, global bool) (*config.Config, error) {
+	if !global {
+		return config.Load(basePath)
+	}
+	path, err := config.GlobalPath()
+	if err != nil {
+		return nil, err
+	}
+	return config.LoadFile(path)
+}
+
+func configGetExecute(basePath, key string, global bool) error {
+	cfg, err := loadConfigFor(basePath, global)
+	if err != nil {
+		return err
+	}
+
+	value, err := config.GetValue(cfg, key)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(value)
+	return nil
+}
+
+func configSetExecute(basePath, key, value string, global bool) error {
+	path := config.ProjectPath(basePath)
+	if global {
+		var err error
+		if path, err = config.GlobalPath(); err != nil {
+			return err
+		}
+	}
+
+	cfg, err := config.LoadFile(path)
+	if err != nil {
+		return err
+	}
+
+	old, err := config.GetValue(cfg, key)
+	if err != nil {
+		return err
+	}
+	if err := config.SetInFile(path, key, value); err != nil {
+		return err
+	}
+
+	cfg, err = config.LoadFile(path)
+	if err != nil {
+		return err
+	}
+	updated, _ := config.GetValue(cfg, key)
+	fmt.Printf("%s: %s -> %s\n", key, old, updated)
+	return nil
+}
+
+func configListExecute(basePath string, global bool) error {
+	cfg, err := loadConfigFor(basePath, global)
+	if err != nil {
+		return err
+	}
+
+	cyan := color.New(color.FgCyan)
+	for _, key := range config.Keys() {
+		value, _ := config.GetValue(cfg, key)
+		cyan.Printf("%s", key)
+		fmt.Printf(" = %s\n", value)
+	}
+	return nil
+}
+
+func configValidateExecute(basePath string, global bool) error {
+	cfg, err := loadConfigFor(basePath, global)
+	if err != nil {
+		return err
+	}
+
+	if err := config.ValidationErrorsToError(cfg.Validate()); err != nil {
+		return err
+	}
+	color.Green("Configuration is valid")
+	return nil
+}
@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"hermes/internal/git"
+)
+
+// NewDiffCmd creates the diff command
+func NewDiffCmd() *cobra.Command {
+	var taskID string
+	var noPager bool
+
+	cmd := &cobra.Command{
+		Use:   "diff",
+		Short: "Show what the AI changed for a task",
+		Long: `Show the diff of the commit created for the last completed task.
+
+Commits are recorded in .hermes/last-commit.json by 'hermes run --auto-commit'.
+Use --task to show the commit of a specific task. The diff is paged with
+$PAGER (or less) when writing to a terminal.`,
+		Example: `  hermes diff
+  hermes diff --task T003
+  hermes diff --no-pager > changes.patch`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if taskID != "" {
+				taskID = normalizeTaskID(taskID)
+			}
+			return diffExecute(".", taskID, noPager)
+		},
+	}
+
+	cmd.Flags().StringVar(&taskID, "task", "", "Show the diff for this task's commit")
+	cmd.Flags().BoolVar(&noPager, "no-pager", false, "Print the diff without a pager")
+
+	return cmd
+}
+
+func diffExecute(basePath, taskID string, noPager bool) error {
+	gitOps := git.New(basePath, "")
+	if !gitOps.IsRepository() {
+		return fmt.Errorf("not a git repository")
+	}
+
+	sha, taskID, err := findDiffCommit(gitOps, basePath, taskID)
+	if err != nil {
+		return err
+	}
+
+	page := !noPager && isTerminal(os.Stdout)
+	diff, err := gitOps.GetCommitDiff(sha, page)
+	if err != nil {
+		return fmt.Errorf("failed to get diff for %s: %w: %s", sha, err, diff)
+	}
+
+	header := color.New(color.Bold).Sprintf("Task %s", taskID) + fmt.Sprintf(" (commit %s)\n\n", shortSHA(sha))
+	output := header + diff + "\n"
+
+	if page {
+		return pageOutput(output)
+	}
+	fmt.Print(output)
+	return nil
+}
+
+// findDiffCommit returns the commit to diff and its task. Without a task ID
+// it is the last recorded commit; otherwise the recorded commit of that
+// task, falling back to searching the git log for its commit message.
+func findDiffCommit(gitOps *git.Git, basePath, taskID string) (string, string, error) {
+	record, err := git.LoadCommitRecord(git.LastCommitPath(basePath))
+	if err != nil {
+		return "", "", err
+	}
+
+	if taskID == "" {
+		if record.SHA == "" {
+			return "", "", fmt.Errorf("no task commit recorded yet, run 'hermes run --auto-commit' first")
+		}
+		return record.SHA, record.TaskID, nil
+	}
+
+	if sha, ok := record.Tasks[taskID]; ok {
+		return sha, taskID, nil
+	}
+	sha, err := gitOps.FindTaskCommit(taskID)
+	if err != nil || sha == "" {
+		return "", "", fmt.Errorf("no commit found for task %s", taskID)
+	}
+	return sha, taskID, nil
+}
+
+// pageOutput shows text through $PAGER or less, printing it directly if
+// neither is available
+func pageOutput(text string) error {
+	pager := os.Getenv("PAGER")
+	if pager == "" {
+		if _, err := exec.LookPath("less"); err != nil {
+			fmt.Print(text)
+			return nil
+		}
+		pager = "less -R"
+	}
+
+	fields := strings.Fields(pager)
+	cmd := exec.Command(fields[0], fields[1:]...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		fmt.Print(text)
+		return nil
+	}
+	io.WriteString(stdin, text)
+	stdin.Close()
+	return cmd.Wait()
+}
+
+func shortSHA(sha string) string {
+	if len(sha) > 8 {
+		return sha[:8]
+	}
+	return sha
+}
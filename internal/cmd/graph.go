@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+
+	"github.com/spf13/cobra"
+	"hermes/internal/scheduler"
+	"hermes/internal/task"
+)
+
+const (
+	graphFormatDOT     = "dot"
+	graphFormatMermaid = "mermaid"
+)
+
+type graphOptions struct {
+	output string
+	format string
+	open   bool
+}
+
+// NewGraphCmd creates the graph command
+func NewGraphCmd() *cobra.Command {
+	opts := &graphOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "graph",
+		Short: "Show the task dependency graph",
+		Long: `Render the task dependency graph as Graphviz DOT (default) or a Mermaid flowchart.
+
+Nodes are labelled "<id>: <name>" and coloured by status:
+  green = completed, yellow = in progress, red = blocked, white = not started`,
+		Example: `  hermes graph > tasks.dot
+  hermes graph --output tasks.dot
+  hermes graph --format mermaid
+  hermes graph --open`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return graphExecute(opts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.output, "output", "o", "", "Write the graph to a file instead of stdout")
+	cmd.Flags().StringVar(&opts.format, "format", graphFormatDOT, "Output format (dot, mermaid)")
+	cmd.Flags().BoolVar(&opts.open, "open", false, "Render with 'dot -Tsvg' and open the result (macOS only)")
+
+	return cmd
+}
+
+func graphExecute(opts *graphOptions) error {
+	if opts.format != graphFormatDOT && opts.format != graphFormatMermaid {
+		return fmt.Errorf("invalid format %q (expected dot or mermaid)", opts.format)
+	}
+	if opts.open && opts.format != graphFormatDOT {
+		return fmt.Errorf("--open is only supported with --format dot")
+	}
+
+	graph, err := buildTaskGraph(".")
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	if opts.format == graphFormatMermaid {
+		err = graph.WriteMermaid(&buf)
+	} else {
+		err = graph.WriteDOT(&buf)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to render graph: %w", err)
+	}
+
+	if opts.open {
+		return openGraph(buf.Bytes())
+	}
+
+	if opts.output != "" {
+		if err := os.WriteFile(opts.output, buf.Bytes(), 0644); err != nil {
+			return fmt.Errorf("failed to write graph: %w", err)
+		}
+		fmt.Printf("Graph written to %s\n", opts.output)
+		return nil
+	}
+
+	_, err = io.Copy(os.Stdout, &buf)
+	return err
+}
+
+// buildTaskGraph builds the dependency graph for all tasks in basePath
+func buildTaskGraph(basePath string) (*scheduler.TaskGraph, error) {
+	tasks, err := newTaskReader(basePath).GetAllTasks()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tasks: %w", err)
+	}
+	if len(tasks) == 0 {
+		return nil, fmt.Errorf("no tasks found")
+	}
+
+	taskPtrs := make([]*task.Task, len(tasks))
+	for i := range tasks {
+		taskPtrs[i] = &tasks[i]
+	}
+
+	graph, err := scheduler.NewTaskGraph(taskPtrs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build task graph: %w", err)
+	}
+	return graph, nil
+}
+
+// openGraph renders DOT source to SVG and opens it with the default viewer
+func openGraph(dot []byte) error {
+	if runtime.GOOS != "darwin" {
+		return fmt.Errorf("--open is only supported on macOS (use --output and render with 'dot -Tsvg')")
+	}
+	if _, err := exec.LookPath("dot"); err != nil {
+		return fmt.Errorf("graphviz 'dot' not found in PATH")
+	}
+
+	svgPath := filepath.Join(os.TempDir(), "hermes-graph.svg")
+	render := exec.Command("dot", "-Tsvg", "-o", svgPath)
+	render.Stdin = bytes.NewReader(dot)
+	render.Stderr = os.Stderr
+	if err := render.Run(); err != nil {
+		return fmt.Errorf("dot failed: %w", err)
+	}
+
+	return exec.Command("open", svgPath).Run()
+}
@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	"hermes/internal/task"
+	"hermes/internal/ui"
+)
+
+// defaultHookTimeout is used when no on-complete timeout is configured
+const defaultHookTimeout = 30 * time.Second
+
+// completionHook is a shell command run after each completed task
+type completionHook struct {
+	command string
+	timeout time.Duration
+}
+
+// hookEnv returns the environment passed to a hook for a completed task
+func hookEnv(t *task.Task, progressPct float64) []string {
+	return append(os.Environ(),
+		"HERMES_TASK_ID="+t.ID,
+		"HERMES_TASK_NAME="+t.Name,
+		"HERMES_FEATURE_ID="+t.FeatureID,
+		fmt.Sprintf("HERMES_PROGRESS_PCT=%.0f", progressPct),
+	)
+}
+
+// run executes the hook through the shell and returns its combined output
+func (h completionHook) run(ctx context.Context, t *task.Task, progressPct float64) (string, error) {
+	timeout := h.timeout
+	if timeout <= 0 {
+		timeout = defaultHookTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", h.command)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", h.command)
+	}
+	cmd.Env = hookEnv(t, progressPct)
+	// Don't wait for children of the shell that still hold the output open
+	cmd.WaitDelay = time.Second
+
+	output, err := cmd.CombinedOutput()
+	if ctx.Err() == context.DeadlineExceeded {
+		return string(output), fmt.Errorf("hook timed out after %v", timeout)
+	}
+	if err != nil {
+		return string(output), fmt.Errorf("hook failed: %w", err)
+	}
+	return strings.TrimRight(string(output), "\n"), nil
+}
+
+// runCompletionHook runs the hook for a completed task, logging its output at debug level
+func runCompletionHook(ctx context.Context, hook completionHook, reader *task.Reader, logger *ui.Logger, t *task.Task) {
+	if hook.command == "" {
+		return
+	}
+
+	var pct float64
+	if progress, err := reader.GetProgress(); err == nil {
+		pct = progress.Percentage
+	}
+
+	output, err := hook.run(ctx, t, pct)
+	if output != "" {
+		logger.Debug("On-complete hook output for %s:\n%s", t.ID, output)
+	}
+	if err != nil {
+		logger.Warn("On-complete hook for %s: %v", t.ID, err)
+	}
+}
@@ -1,8 +1,10 @@
 package cmd
 
 import (
+	"bufio"
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
@@ -22,6 +24,8 @@ type ideaOptions struct {
 	language    string
 	timeout     int
 	debug       bool
+	refine      bool
+	format      string
 }
 
 // NewIdeaCmd creates the idea subcommand
@@ -35,10 +39,24 @@ func NewIdeaCmd() *cobra.Command {
 		Example: `  hermes idea "e-commerce website"
   hermes idea "real-time chat app" --interactive
   hermes idea "task manager" --language tr
-  hermes idea "blog platform" --dry-run`,
+  hermes idea "blog platform" --dry-run
+  hermes idea "recipe sharing app" --refine
+  hermes idea "inventory service" --format json`,
 		Args: cobra.MinimumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ideaText := strings.Join(args, " ")
+			switch opts.format {
+			case idea.FormatMarkdown:
+			case idea.FormatJSON, idea.FormatYAML:
+				if opts.refine {
+					return fmt.Errorf("--refine is only supported with --format markdown")
+				}
+				if !cmd.Flags().Changed("output") {
+					opts.output = strings.TrimSuffix(opts.output, filepath.Ext(opts.output)) + "." + opts.format
+				}
+			default:
+				return fmt.Errorf("unknown format %q (use markdown, json or yaml)", opts.format)
+			}
 			return ideaExecute(ideaText, opts)
 		},
 	}
@@ -48,6 +66,8 @@ func NewIdeaCmd() *cobra.Command {
 	cmd.Flags().BoolVarP(&opts.interactive, "interactive", "i", false, "Interactive mode with additional questions")
 	cmd.Flags().StringVarP(&opts.language, "language", "l", "en", "PRD language (en/tr)")
 	cmd.Flags().IntVar(&opts.timeout, "timeout", 600, "AI timeout in seconds")
+	cmd.Flags().BoolVar(&opts.refine, "refine", false, "Revise the generated PRD in a dialogue before writing it")
+	cmd.Flags().StringVar(&opts.format, "format", idea.FormatMarkdown, "PRD format: markdown, json, yaml (json and yaml default to .hermes/docs/PRD.json and PRD.yaml)")
 	cmd.Flags().BoolVar(&opts.debug, "debug", false, "Enable debug output")
 
 	return cmd
@@ -115,11 +135,15 @@ func ideaExecute(ideaText string, opts *ideaOptions) error {
 
 	fmt.Println("\nGenerating PRD...")
 
-	// Generate PRD
+	if opts.format != idea.FormatMarkdown {
+		return generateStructuredPRD(ctx, gen, logger, ideaText, outputPath, additionalContext, opts)
+	}
+
+	// Generate PRD. When refining, it is written once the dialogue ends.
 	result, err := gen.Generate(ctx, idea.GenerateOptions{
 		Idea:              ideaText,
 		Output:            outputPath,
-		DryRun:            opts.dryRun,
+		DryRun:            opts.dryRun || opts.refine,
 		Interactive:       opts.interactive,
 		Language:          opts.language,
 		Timeout:           opts.timeout,
@@ -129,6 +153,29 @@ func ideaExecute(ideaText string, opts *ideaOptions) error {
 		return err
 	}
 
+	if opts.refine {
+		revisions, err := refinePRD(gen, ideaText, result.PRDContent, opts, bufio.NewReader(os.Stdin), os.Stdout)
+		if err != nil {
+			return err
+		}
+		result.PRDContent = revisions[len(revisions)-1].Content
+
+		if !opts.dryRun {
+			if err := os.MkdirAll(filepath.Dir(outputPath), 0755); err != nil {
+				return fmt.Errorf("failed to create directory: %w", err)
+			}
+			if err := os.WriteFile(outputPath, []byte(result.PRDContent), 0644); err != nil {
+				return fmt.Errorf("failed to write PRD: %w", err)
+			}
+			historyPath := idea.HistoryPath(cfg.Paths.DocsDir)
+			if err := idea.AppendHistory(historyPath, revisions); err != nil {
+				logger.Warn("Failed to save PRD history: %v", err)
+			} else {
+				logger.Info("Revision history: %s (%d versions)", historyPath, len(revisions))
+			}
+		}
+	}
+
 	// Show result
 	if opts.dryRun {
 		fmt.Println("\n=== PRD Preview ===")
@@ -149,3 +196,85 @@ func ideaExecute(ideaText string, opts *ideaOptions) error {
 
 	return nil
 }
+
+// generateStructuredPRD generates the PRD as data and writes it as JSON or
+// YAML
+func generateStructuredPRD(ctx context.Context, gen *idea.Generator, logger *ui.Logger, ideaText, outputPath, additionalContext string, opts *ideaOptions) error {
+	startTime := time.Now()
+	prd, err := gen.GenerateStructured(ctx, idea.GenerateOptions{
+		Idea:              ideaText,
+		Output:            outputPath,
+		DryRun:            opts.dryRun,
+		Interactive:       opts.interactive,
+		Language:          opts.language,
+		Timeout:           opts.timeout,
+		AdditionalContext: additionalContext,
+	})
+	if err != nil {
+		return err
+	}
+	data, err := prd.Encode(opts.format)
+	if err != nil {
+		return err
+	}
+
+	if opts.dryRun {
+		fmt.Println("\n=== PRD Preview ===")
+		fmt.Print(string(data))
+		fmt.Println("===================")
+		fmt.Printf("\nWould be written to: %s\n", outputPath)
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(outputPath), 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+	if err := os.WriteFile(outputPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write PRD: %w", err)
+	}
+
+	tasks := 0
+	for _, f := range prd.Features {
+		tasks += len(f.Tasks)
+	}
+	logger.Success("PRD generated: %s (%d features, %d tasks)", outputPath, len(prd.Features), tasks)
+	logger.Info("Duration: %s", time.Since(startTime).Round(time.Millisecond))
+	return nil
+}
+
+// refinePRD shows the PRD and revises it with each change the user asks
+// for, until "done" or an empty line. It returns every version, starting
+// with the generated one.
+func refinePRD(gen *idea.Generator, ideaText, prd string, opts *ideaOptions, in *bufio.Reader, out io.Writer) ([]idea.Revision, error) {
+	revisions := []idea.Revision{{Timestamp: time.Now(), Request: ideaText, Content: prd}}
+
+	for {
+		current := revisions[len(revisions)-1].Content
+		fmt.Fprintf(out, "\n=== PRD (version %d) ===\n%s\n========================\n", len(revisions), current)
+		fmt.Fprint(out, "\nWhat to change? (empty line or \"done\" to finish)\n> ")
+
+		input, err := in.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+		change := strings.TrimSpace(input)
+		if change == "" || strings.EqualFold(change, "done") {
+			return revisions, nil
+		}
+
+		// Each revision gets the full timeout; the user's thinking time does not count
+		ctx, cancel := context.WithTimeout(context.Background(), time.Duration(opts.timeout)*time.Second)
+		fmt.Fprintln(out, "\nRevising PRD...")
+		result, reviseErr := gen.Revise(ctx, current, change, opts.language, opts.timeout)
+		cancel()
+		if reviseErr != nil {
+			fmt.Fprintf(out, "Revision failed: %v\n", reviseErr)
+		} else {
+			revisions = append(revisions, idea.Revision{Timestamp: time.Now(), Request: change, Content: result.PRDContent})
+		}
+
+		if err == io.EOF {
+			return revisions, nil
+		}
+	}
+}
@@ -11,28 +11,58 @@ import (
 	"hermes/internal/prompt"
 )
 
+type initOptions struct {
+	template    string
+	templateRef string
+	fromGit     bool
+	repo        string
+	maxIssues   int
+}
+
 // NewInitCmd creates the init subcommand
 func NewInitCmd() *cobra.Command {
+	opts := &initOptions{}
+
 	cmd := &cobra.Command{
 		Use:   "init [project-name]",
 		Short: "Initialize Hermes project",
 		Long:  "Create .hermes directory structure and default configuration",
 		Example: `  hermes init
-  hermes init my-project`,
+  hermes init my-project
+  hermes init --template ~/templates/PROMPT.md
+  hermes init --template acme/hermes-go-template
+  hermes init --template acme/hermes-go-template --template-ref v1.2.0
+  hermes init --from-git --max-issues 20`,
 		Args: cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			projectPath := "."
 			if len(args) > 0 {
 				projectPath = args[0]
 			}
-			return initExecute(projectPath)
+			return initExecute(projectPath, opts)
 		},
 	}
 
+	cmd.Flags().StringVar(&opts.template, "template", "", "PROMPT.md file to copy, or template repository (owner/repo, git URL or directory)")
+	cmd.Flags().StringVar(&opts.templateRef, "template-ref", "", "Branch, tag or commit of the template repository")
+	cmd.Flags().BoolVar(&opts.fromGit, "from-git", false, "Seed task files from open GitHub issues (uses GITHUB_TOKEN)")
+	cmd.Flags().StringVar(&opts.repo, "repo", "", "GitHub repository (owner/repo) for --from-git (default: origin remote)")
+	cmd.Flags().IntVar(&opts.maxIssues, "max-issues", 0, "Maximum number of issues to import (0 = all)")
+
 	return cmd
 }
 
-func initExecute(projectPath string) error {
+func initExecute(projectPath string, opts *initOptions) error {
+	repoTemplate := opts.template != "" && isRepoTemplate(opts.template)
+	if opts.template != "" && !repoTemplate {
+		if _, err := os.Stat(opts.template); err != nil {
+			return fmt.Errorf("template not found: %s", opts.template)
+		}
+	}
+	if opts.templateRef != "" && !repoTemplate {
+		return fmt.Errorf("--template-ref requires a template repository")
+	}
+
 	// Create project directory if needed
 	if projectPath != "." {
 		if err := os.MkdirAll(projectPath, 0755); err != nil {
@@ -53,6 +83,30 @@ func initExecute(projectPath string) error {
 		}
 	}
 
+	// Copy .hermes/ from a template repository before creating defaults
+	templatePrompt := false
+	if repoTemplate {
+		if _, err := os.Stat(filepath.Join(projectPath, ".hermes")); err == nil {
+			fmt.Println("  Warning: .hermes/ already exists, template files will overwrite existing ones")
+		}
+
+		templateDir, err := fetchTemplate(opts.template, opts.templateRef)
+		if err != nil {
+			return fmt.Errorf("failed to fetch template %s: %w", opts.template, err)
+		}
+		copied, err := applyTemplate(projectPath, templateDir)
+		os.RemoveAll(templateDir)
+		if err != nil {
+			return err
+		}
+		for _, file := range copied {
+			if file == filepath.Join(".hermes", "PROMPT.md") {
+				templatePrompt = true
+			}
+		}
+		fmt.Printf("  Copied: %d file(s) from template %s\n", len(copied), opts.template)
+	}
+
 	// Create directory structure
 	dirs := []string{
 		".hermes",
@@ -79,12 +133,42 @@ func initExecute(projectPath string) error {
 		fmt.Println("  Created: .hermes/config.json")
 	}
 
-	// Create default PROMPT.md
-	injector := prompt.NewInjector(projectPath)
-	if err := injector.CreateDefault(); err != nil {
-		return err
+	// Remember the template for later re-fetches
+	if repoTemplate {
+		source := opts.template
+		if info, err := os.Stat(source); err == nil && info.IsDir() {
+			source, _ = filepath.Abs(source)
+		}
+		if err := config.SetInFile(configPath, "template.source", source); err != nil {
+			return err
+		}
+		if err := config.SetInFile(configPath, "template.ref", opts.templateRef); err != nil {
+			return err
+		}
+	}
+
+	// Create PROMPT.md from the supplied template or the default
+	if templatePrompt {
+		fmt.Println("  Created: .hermes/PROMPT.md (from template)")
+	} else if opts.template != "" && !repoTemplate {
+		if err := prompt.NewTemplateManager(projectPath).Install(opts.template, ""); err != nil {
+			return err
+		}
+		fmt.Printf("  Created: .hermes/PROMPT.md (from %s)\n", opts.template)
+	} else {
+		injector := prompt.NewInjector(projectPath)
+		if err := injector.CreateDefault(); err != nil {
+			return err
+		}
+		fmt.Println("  Created: .hermes/PROMPT.md")
+	}
+
+	// Seed tasks from GitHub issues
+	if opts.fromGit {
+		if err := importGitHubIssues(projectPath, opts.repo, opts.maxIssues); err != nil {
+			return fmt.Errorf("failed to import GitHub issues: %w", err)
+		}
 	}
-	fmt.Println("  Created: .hermes/PROMPT.md")
 
 	// Create/update .gitignore
 	createGitignore(filepath.Join(projectPath, ".gitignore"))
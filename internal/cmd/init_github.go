@@ -0,0 +1,152 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"hermes/internal/analyzer"
+	"hermes/internal/github"
+	"hermes/internal/task"
+)
+
+// unlabelledFeature is the feature name for issues without labels
+const unlabelledFeature = "Unlabelled"
+
+// issueGroup is a set of issues that become one feature
+type issueGroup struct {
+	Name   string
+	Issues []github.Issue
+}
+
+// importGitHubIssues creates one feature file per label from the open issues of a repository
+func importGitHubIssues(projectPath, repo string, maxIssues int) error {
+	var owner, name string
+	var err error
+	if repo != "" {
+		owner, name, err = github.ParseRepo(repo)
+	} else {
+		owner, name, err = originRepo(projectPath)
+	}
+	if err != nil {
+		return err
+	}
+
+	client := github.NewClient()
+	issues, err := client.ListOpenIssues(owner, name, maxIssues)
+	if err != nil {
+		return err
+	}
+	if len(issues) == 0 {
+		fmt.Printf("  No open issues found in %s/%s\n", owner, name)
+		return nil
+	}
+
+	nextFeatureID, nextTaskID, err := analyzer.NewFeatureAnalyzer(projectPath).GetNextIDs()
+	if err != nil {
+		nextFeatureID = 1
+		nextTaskID = 1
+	}
+
+	tasksDir := filepath.Join(projectPath, ".hermes", "tasks")
+	if err := os.MkdirAll(tasksDir, 0755); err != nil {
+		return err
+	}
+
+	for _, group := range groupIssuesByLabel(issues) {
+		content := formatIssueFeature(nextFeatureID, nextTaskID, owner+"/"+name, group)
+		fileName := featureFileName(nextFeatureID, group.Name)
+		if err := os.WriteFile(filepath.Join(tasksDir, fileName), []byte(content), 0644); err != nil {
+			return err
+		}
+		fmt.Printf("  Created: .hermes/tasks/%s (%d tasks)\n", fileName, len(group.Issues))
+
+		nextFeatureID++
+		nextTaskID += len(group.Issues)
+	}
+
+	fmt.Printf("  Imported: %d issues from %s/%s\n", len(issues), owner, name)
+	return nil
+}
+
+// originRepo returns the owner and name of the origin remote
+func originRepo(projectPath string) (string, string, error) {
+	cmd := exec.Command("git", "remote", "get-url", "origin")
+	cmd.Dir = projectPath
+	output, err := cmd.Output()
+	if err != nil {
+		return "", "", fmt.Errorf("no origin remote found (use --repo owner/repo)")
+	}
+	return github.ParseRepoURL(string(output))
+}
+
+// groupIssuesByLabel groups issues by their first label, keeping first-seen order
+func groupIssuesByLabel(issues []github.Issue) []issueGroup {
+	var groups []issueGroup
+	index := make(map[string]int)
+
+	for _, issue := range issues {
+		label := unlabelledFeature
+		if len(issue.Labels) > 0 && strings.TrimSpace(issue.Labels[0].Name) != "" {
+			label = strings.TrimSpace(issue.Labels[0].Name)
+		}
+
+		i, ok := index[label]
+		if !ok {
+			i = len(groups)
+			index[label] = i
+			groups = append(groups, issueGroup{Name: label})
+		}
+		groups[i].Issues = append(groups[i].Issues, issue)
+	}
+
+	return groups
+}
+
+// formatIssueFeature renders a feature file with one task per issue
+func formatIssueFeature(featureID, firstTaskID int, repo string, group issueGroup) string {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("# Feature %d: %s\n\n", featureID, group.Name))
+	sb.WriteString(fmt.Sprintf("**Feature ID:** F%03d\n", featureID))
+	sb.WriteString("**Priority:** P2 - HIGH\n")
+	for _, issue := range group.Issues {
+		if issue.Milestone != nil && issue.Milestone.Title != "" {
+			sb.WriteString(fmt.Sprintf("**Target Version:** %s\n", issue.Milestone.Title))
+			break
+		}
+	}
+	sb.WriteString("**Status:** NOT_STARTED\n\n")
+
+	sb.WriteString("## Overview\n\n")
+	sb.WriteString(fmt.Sprintf("Imported from open GitHub issues in %s", repo))
+	if group.Name != unlabelledFeature {
+		sb.WriteString(fmt.Sprintf(" labelled \"%s\"", group.Name))
+	}
+	sb.WriteString(".\n\n")
+
+	sb.WriteString("## Tasks\n\n")
+	for i, issue := range group.Issues {
+		t := &task.Task{
+			ID:               fmt.Sprintf("T%03d", firstTaskID+i),
+			Name:             strings.TrimSpace(issue.Title),
+			Status:           task.StatusNotStarted,
+			Priority:         task.PriorityP2,
+			Description:      strings.TrimSpace(issue.Body),
+			TechnicalDetails: fmt.Sprintf("GitHub issue #%d: %s", issue.Number, issue.HTMLURL),
+		}
+		if t.Description == "" {
+			t.Description = t.Name
+		}
+
+		sb.WriteString(task.FormatTask(t))
+		if issue.Assignee != nil && issue.Assignee.Login != "" {
+			sb.WriteString(fmt.Sprintf("\n<!-- Assignee: @%s -->\n", issue.Assignee.Login))
+		}
+		sb.WriteString("\n---\n\n")
+	}
+
+	return sb.String()
+}
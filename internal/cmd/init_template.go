@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"hermes/internal/github"
+)
+
+// isRepoTemplate returns true if template names a template repository (a
+// GitHub owner/repo, a git URL or a local directory) rather than a PROMPT.md file
+func isRepoTemplate(template string) bool {
+	if info, err := os.Stat(template); err == nil {
+		return info.IsDir()
+	}
+	if strings.Contains(template, "://") || strings.HasPrefix(template, "git@") {
+		return true
+	}
+	_, _, err := github.ParseRepo(template)
+	return err == nil
+}
+
+// templateCloneURL returns the git URL for a template source
+func templateCloneURL(source string) string {
+	if _, err := os.Stat(source); err == nil {
+		return source
+	}
+	if strings.Contains(source, "://") || strings.HasPrefix(source, "git@") {
+		return source
+	}
+	owner, name, err := github.ParseRepo(source)
+	if err != nil {
+		return source
+	}
+	return fmt.Sprintf("https://github.com/%s/%s.git", owner, name)
+}
+
+// fetchTemplate checks out ref (a branch, tag or commit; empty for the
+// default branch) of the template repository into a temp directory
+func fetchTemplate(source, ref string) (string, error) {
+	dir, err := os.MkdirTemp("", "hermes-template-*")
+	if err != nil {
+		return "", err
+	}
+
+	if ref == "" {
+		ref = "HEAD"
+	}
+	steps := [][]string{
+		{"init", "-q"},
+		{"fetch", "-q", "--depth", "1", templateCloneURL(source), ref},
+		{"checkout", "-q", "FETCH_HEAD"},
+	}
+	for _, args := range steps {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if output, err := cmd.CombinedOutput(); err != nil {
+			os.RemoveAll(dir)
+			return "", fmt.Errorf("git %s failed: %w: %s", args[0], err, strings.TrimSpace(string(output)))
+		}
+	}
+
+	return dir, nil
+}
+
+// applyTemplate copies the template's .hermes/ contents into the project
+// and its top-level PROMPT.md to .hermes/PROMPT.md. It returns the copied
+// paths relative to the project.
+func applyTemplate(projectPath, templateDir string) ([]string, error) {
+	var copied []string
+
+	srcHermes := filepath.Join(templateDir, ".hermes")
+	if info, err := os.Stat(srcHermes); err == nil && info.IsDir() {
+		err := filepath.Walk(srcHermes, func(path string, info os.FileInfo, err error) error {
+			if err != nil || info.IsDir() {
+				return err
+			}
+			rel, _ := filepath.Rel(templateDir, path)
+			if err := copyFile(path, filepath.Join(projectPath, rel)); err != nil {
+				return err
+			}
+			copied = append(copied, rel)
+			return nil
+		})
+		if err != nil {
+			return copied, fmt.Errorf("failed to copy template: %w", err)
+		}
+	}
+
+	srcPrompt := filepath.Join(templateDir, "PROMPT.md")
+	if _, err := os.Stat(srcPrompt); err == nil {
+		rel := filepath.Join(".hermes", "PROMPT.md")
+		if err := copyFile(srcPrompt, filepath.Join(projectPath, rel)); err != nil {
+			return copied, fmt.Errorf("failed to copy PROMPT.md: %w", err)
+		}
+		copied = append(copied, rel)
+	}
+
+	if len(copied) == 0 {
+		return nil, fmt.Errorf("template has no .hermes/ directory or PROMPT.md")
+	}
+	return copied, nil
+}
+
+// copyFile copies src to dest, creating parent directories
+func copyFile(src, dest string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return err
+	}
+	out, err := os.Create(dest)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
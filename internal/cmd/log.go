@@ -2,14 +2,22 @@ package cmd
 
 import (
 	"bufio"
+	"context"
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
+	"os/signal"
 	"path/filepath"
 	"strings"
+	"syscall"
 	"time"
 
+	tea "github.com/charmbracelet/bubbletea"
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
+	"hermes/internal/scheduler"
+	"hermes/internal/tui"
 )
 
 // NewLogCmd creates the log command
@@ -18,90 +26,335 @@ func NewLogCmd() *cobra.Command {
 		Use:   "log",
 		Short: "View hermes logs",
 		Long:  "Display logs from .hermes/logs/hermes.log",
-		RunE:  runLog,
+		Example: `  hermes log -n 100
+  hermes log -f --level ERROR
+  hermes log --query ERROR
+  hermes log --query "task T042" --since 1h
+  hermes log --query T042 --output json
+  hermes log --parallel
+  hermes log rotate --max-age 72h`,
+		RunE: runLog,
 	}
 
 	cmd.Flags().IntP("lines", "n", 50, "Number of lines to show")
+	cmd.Flags().Int("tail", 0, "Print the last N lines and exit (with -f, keep following)")
 	cmd.Flags().BoolP("follow", "f", false, "Follow log output (like tail -f)")
 	cmd.Flags().String("level", "", "Filter by log level (ERROR, WARN, INFO, DEBUG)")
+	cmd.Flags().Bool("cost", false, "Show cumulative API calls and cost across runs")
+	cmd.Flags().String("query", "", "Search all log files, including parallel worker logs, for this text")
+	cmd.Flags().Duration("since", 0, "Only search lines logged within this duration (e.g. 1h, 30m)")
+	cmd.Flags().String("output", "text", "Search output format: text, json")
+	cmd.Flags().Bool("parallel", false, "View the aggregated worker log of the last parallel run")
+
+	cmd.AddCommand(newLogRotateCmd())
 
 	return cmd
 }
 
 func runLog(cmd *cobra.Command, args []string) error {
 	lines, _ := cmd.Flags().GetInt("lines")
+	tail, _ := cmd.Flags().GetInt("tail")
 	follow, _ := cmd.Flags().GetBool("follow")
 	level, _ := cmd.Flags().GetString("level")
 	level = strings.ToUpper(level)
 
+	if cost, _ := cmd.Flags().GetBool("cost"); cost {
+		return showCost(scheduler.ResourceStatsPath("."))
+	}
+
+	if parallel, _ := cmd.Flags().GetBool("parallel"); parallel {
+		return showAggregateLog(scheduler.AggregateLogPath("."))
+	}
+
+	query, _ := cmd.Flags().GetString("query")
+	since, _ := cmd.Flags().GetDuration("since")
+	if query != "" || since > 0 {
+		if follow {
+			return fmt.Errorf("--query and --since cannot be used with --follow")
+		}
+		output, _ := cmd.Flags().GetString("output")
+		var after time.Time
+		if since > 0 {
+			after = time.Now().Add(-since)
+		}
+		matches, err := searchLogs(filepath.Join(".hermes", "logs"), query, level, after)
+		if err != nil {
+			return err
+		}
+		return printLogMatches(matches, output)
+	}
+
 	logPath := filepath.Join(".hermes", "logs", "hermes.log")
 
 	if _, err := os.Stat(logPath); os.IsNotExist(err) {
 		return fmt.Errorf("log file not found: %s", logPath)
 	}
 
+	if cmd.Flags().Changed("tail") {
+		lines = tail
+	}
+
 	if follow {
-		return followLog(logPath, level)
+		ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer cancel()
+
+		// Like tail -n N -f, print the requested lines before following
+		if cmd.Flags().Changed("tail") {
+			if err := showLog(logPath, lines, level); err != nil {
+				return err
+			}
+		}
+
+		fmt.Fprintln(os.Stderr, "Following log... (Ctrl+C to stop)")
+		return followLog(ctx, logPath, level, printColoredLine)
 	}
 
 	return showLog(logPath, lines, level)
 }
 
+// showAggregateLog shows the aggregated worker log in the TUI log viewer,
+// or prints it when stdout is not a terminal
+func showAggregateLog(logPath string) error {
+	if _, err := os.Stat(logPath); os.IsNotExist(err) {
+		return fmt.Errorf("aggregate log not found: %s (run with --parallel first)", logPath)
+	}
+
+	if !isTerminal(os.Stdout) {
+		data, err := os.ReadFile(logPath)
+		if err != nil {
+			return err
+		}
+		for _, line := range strings.Split(strings.TrimRight(string(data), "\n"), "\n") {
+			printColoredLine(line)
+		}
+		return nil
+	}
+
+	program := tea.NewProgram(tui.NewLogViewer(logPath, "Parallel Log"), tea.WithAltScreen())
+	_, err := program.Run()
+	return err
+}
+
 func showLog(logPath string, numLines int, level string) error {
-	file, err := os.Open(logPath)
+	lines, err := readLastLines(logPath, numLines, level)
 	if err != nil {
 		return err
 	}
+
+	for _, line := range lines {
+		printColoredLine(line)
+	}
+
+	return nil
+}
+
+// readLastLines returns the last N lines of the log matching the level filter
+func readLastLines(logPath string, numLines int, level string) ([]string, error) {
+	file, err := os.Open(logPath)
+	if err != nil {
+		return nil, err
+	}
 	defer file.Close()
 
 	var allLines []string
 	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
 	for scanner.Scan() {
 		line := scanner.Text()
-		if level == "" || strings.Contains(line, "["+level+"]") {
+		if matchesLevel(line, level) {
 			allLines = append(allLines, line)
 		}
 	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
 
-	// Show last N lines
 	start := len(allLines) - numLines
 	if start < 0 {
 		start = 0
 	}
 
-	for i := start; i < len(allLines); i++ {
-		printColoredLine(allLines[i])
-	}
-
-	return nil
+	return allLines[start:], nil
 }
 
-func followLog(logPath string, level string) error {
+// followLog polls the log for appended lines and passes them to handle until ctx is cancelled
+func followLog(ctx context.Context, logPath string, level string, handle func(string)) error {
 	file, err := os.Open(logPath)
 	if err != nil {
 		return err
 	}
-	defer file.Close()
+	defer func() { file.Close() }()
 
 	// Go to end of file
-	file.Seek(0, 2)
+	if _, err := file.Seek(0, io.SeekEnd); err != nil {
+		return err
+	}
 
-	fmt.Println("Following log... (Ctrl+C to stop)")
-	fmt.Println()
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
 
 	reader := bufio.NewReader(file)
+	var partial string
 	for {
-		line, err := reader.ReadString('\n')
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				// Keep incomplete lines until the rest is written
+				partial += line
+				break
+			}
+
+			line = strings.TrimRight(partial+line, "\r\n")
+			partial = ""
+			if matchesLevel(line, level) {
+				handle(line)
+			}
+		}
+
+		// Start over if the log was truncated or rotated
+		if info, err := os.Stat(logPath); err == nil {
+			if pos, err := file.Seek(0, io.SeekCurrent); err == nil && info.Size() < pos {
+				file.Close()
+				if file, err = os.Open(logPath); err != nil {
+					return err
+				}
+				reader.Reset(file)
+				partial = ""
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// logMatch is a log line found by searchLogs
+type logMatch struct {
+	File string `json:"file"` // Relative to the logs directory
+	Line int    `json:"line"`
+	Time string `json:"time,omitempty"`
+	Text string `json:"text"`
+}
+
+// searchLogs returns the lines of all .log files under logDir containing
+// query (case-insensitive) at the given level, logged at or after since
+// (zero = no limit). Lines without a timestamp take the previous one's.
+func searchLogs(logDir, query, level string, since time.Time) ([]logMatch, error) {
+	if _, err := os.Stat(logDir); os.IsNotExist(err) {
+		return nil, fmt.Errorf("log directory not found: %s", logDir)
+	}
+
+	query = strings.ToLower(query)
+	var matches []logMatch
+	err := filepath.Walk(logDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() || filepath.Ext(path) != ".log" {
+			return err
+		}
+		rel, _ := filepath.Rel(logDir, path)
+
+		file, err := os.Open(path)
 		if err != nil {
-			time.Sleep(100 * time.Millisecond)
-			continue
+			return err
 		}
+		defer file.Close()
 
-		line = strings.TrimRight(line, "\r\n")
-		if level == "" || strings.Contains(line, "["+level+"]") {
-			printColoredLine(line)
+		var lastTime time.Time
+		scanner := bufio.NewScanner(file)
+		scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+		for lineNum := 1; scanner.Scan(); lineNum++ {
+			line := scanner.Text()
+			if ts, ok := parseLogTime(line); ok {
+				lastTime = ts
+			}
+			if !since.IsZero() && (lastTime.IsZero() || lastTime.Before(since)) {
+				continue
+			}
+			if !matchesLevel(line, level) || !strings.Contains(strings.ToLower(line), query) {
+				continue
+			}
+
+			match := logMatch{File: filepath.ToSlash(rel), Line: lineNum, Text: line}
+			if !lastTime.IsZero() {
+				match.Time = lastTime.Format(time.RFC3339)
+			}
+			matches = append(matches, match)
+		}
+		return scanner.Err()
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to search logs: %w", err)
+	}
+
+	return matches, nil
+}
+
+// parseLogTime reads the timestamp of a log line, either the
+// "[2006-01-02 15:04:05]" prefix of text logs or the "ts" field of JSON logs
+func parseLogTime(line string) (time.Time, bool) {
+	if strings.HasPrefix(line, "{") {
+		var entry struct {
+			Timestamp string `json:"ts"`
+		}
+		if json.Unmarshal([]byte(line), &entry) == nil {
+			if ts, err := time.Parse(time.RFC3339, entry.Timestamp); err == nil {
+				return ts, true
+			}
+		}
+		return time.Time{}, false
+	}
+
+	const layout = "2006-01-02 15:04:05"
+	if len(line) < len(layout)+2 || line[0] != '[' || line[len(layout)+1] != ']' {
+		return time.Time{}, false
+	}
+	ts, err := time.ParseInLocation(layout, line[1:len(layout)+1], time.Local)
+	return ts, err == nil
+}
+
+// printLogMatches prints search results as "file:line: text" or as JSON
+func printLogMatches(matches []logMatch, output string) error {
+	switch output {
+	case "json":
+		if matches == nil {
+			matches = []logMatch{}
+		}
+		data, err := json.MarshalIndent(matches, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+	case "text", "":
+		if len(matches) == 0 {
+			fmt.Fprintln(os.Stderr, "No matching log lines")
+			return nil
+		}
+		for _, m := range matches {
+			printColoredLine(fmt.Sprintf("%s:%d: %s", m.File, m.Line, m.Text))
+		}
+	default:
+		return fmt.Errorf("unknown output format: %s (use text or json)", output)
+	}
+	return nil
+}
+
+// matchesLevel returns true if a log line has the level, in the text format
+// ("[ERROR] ...") or in the JSON format ({"level":"ERROR",...})
+func matchesLevel(line, level string) bool {
+	if level == "" {
+		return true
+	}
+	if strings.HasPrefix(line, "{") {
+		var entry struct {
+			Level string `json:"level"`
+		}
+		if json.Unmarshal([]byte(line), &entry) == nil {
+			return entry.Level == level
 		}
 	}
+	return strings.Contains(line, "["+level+"]")
 }
 
 func printColoredLine(line string) {
@@ -117,3 +370,30 @@ func printColoredLine(line string) {
 		fmt.Println(line)
 	}
 }
+
+// showCost prints the cumulative resource statistics persisted by parallel runs
+func showCost(statsPath string) error {
+	if _, err := os.Stat(statsPath); os.IsNotExist(err) {
+		return fmt.Errorf("no cost data found: %s (run 'hermes run --parallel' first)", statsPath)
+	}
+
+	stats, err := scheduler.LoadResourceStats(statsPath)
+	if err != nil {
+		return err
+	}
+
+	bold := color.New(color.Bold)
+	bold.Println("Cumulative API Usage")
+	fmt.Println("═══════════════════════════════════════")
+	fmt.Printf("API Calls:  %d\n", stats.TotalAPICalls)
+	fmt.Printf("Total Cost: $%.4f\n", stats.TotalCost)
+	if stats.TotalAPICalls > 0 {
+		fmt.Printf("Avg/Call:   $%.4f\n", stats.TotalCost/float64(stats.TotalAPICalls))
+	}
+	if !stats.LastUpdated.IsZero() {
+		fmt.Printf("Updated:    %s\n", stats.LastUpdated.Format("2006-01-02 15:04:05"))
+	}
+	fmt.Println("═══════════════════════════════════════")
+
+	return nil
+}
@@ -0,0 +1,336 @@
+package cmd
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"hermes/internal/config"
+)
+
+// logArchiveDir is the directory under the logs directory holding archives
+const logArchiveDir = "archive"
+
+// logRotateOptions controls rotateLogs
+type logRotateOptions struct {
+	MaxAge      time.Duration // Archive log files not modified for this long
+	MaxArchives int           // Delete the oldest archives beyond this count
+	KeepLines   int           // Lines of hermes.log kept, the rest is archived
+}
+
+// logRotateResult reports what rotateLogs did
+type logRotateResult struct {
+	Archive      string   // Archive written, empty if nothing was archived
+	Archived     []string // Log files moved into the archive, relative to the logs directory
+	DroppedLines int      // Lines moved from hermes.log into the archive
+	Removed      []string // Old archives deleted
+}
+
+func newLogRotateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rotate",
+		Short: "Archive old log files",
+		Long: `Archive old log files into .hermes/logs/archive/YYYY-MM-DD.tar.gz.
+
+Log files not modified for --max-age are moved into the archive, and
+hermes.log is cut down to its last --keep-lines lines, with the older lines
+archived as well. Only the newest --max-archives archives are kept.
+Defaults come from log.rotation in the config.`,
+		Example: `  hermes log rotate
+  hermes log rotate --max-age 72h --keep-lines 500
+  hermes log rotate --max-archives 3`,
+		Args: cobra.NoArgs,
+		RunE: runLogRotate,
+	}
+
+	cmd.Flags().Duration("max-age", 0, "Archive log files older than this (default: log.rotation.maxAgeDays, 7 days)")
+	cmd.Flags().Int("max-archives", 0, "Number of archives to keep (default: log.rotation.maxArchives, 10)")
+	cmd.Flags().Int("keep-lines", 0, "Lines of hermes.log to keep (default: log.rotation.keepLines, 1000)")
+
+	return cmd
+}
+
+func runLogRotate(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load(".")
+	if err != nil {
+		cfg = config.DefaultConfig()
+	}
+
+	opts := logRotateDefaults(cfg.Log.Rotation)
+	if cmd.Flags().Changed("max-age") {
+		opts.MaxAge, _ = cmd.Flags().GetDuration("max-age")
+	}
+	if cmd.Flags().Changed("max-archives") {
+		opts.MaxArchives, _ = cmd.Flags().GetInt("max-archives")
+	}
+	if cmd.Flags().Changed("keep-lines") {
+		opts.KeepLines, _ = cmd.Flags().GetInt("keep-lines")
+	}
+
+	result, err := rotateLogs(filepath.Join(".hermes", "logs"), opts, time.Now())
+	if err != nil {
+		return err
+	}
+	printLogRotateResult(result)
+	return nil
+}
+
+// logRotateDefaults returns the rotation options of the config
+func logRotateDefaults(cfg config.LogRotationConfig) logRotateOptions {
+	return logRotateOptions{
+		MaxAge:      time.Duration(cfg.MaxAgeDays) * 24 * time.Hour,
+		MaxArchives: cfg.MaxArchives,
+		KeepLines:   cfg.KeepLines,
+	}
+}
+
+// rotateLogs archives the log files under logDir older than opts.MaxAge and
+// the lines of hermes.log beyond the last opts.KeepLines, then deletes all but
+// the newest opts.MaxArchives archives
+func rotateLogs(logDir string, opts logRotateOptions, now time.Time) (*logRotateResult, error) {
+	if opts.MaxAge <= 0 {
+		return nil, fmt.Errorf("--max-age must be positive")
+	}
+	if opts.MaxArchives < 1 {
+		return nil, fmt.Errorf("--max-archives must be at least 1")
+	}
+	if opts.KeepLines < 0 {
+		return nil, fmt.Errorf("--keep-lines must not be negative")
+	}
+	if _, err := os.Stat(logDir); os.IsNotExist(err) {
+		return nil, fmt.Errorf("log directory not found: %s", logDir)
+	}
+
+	result := &logRotateResult{}
+	archiveDir := filepath.Join(logDir, logArchiveDir)
+
+	// Inactive log files not modified within MaxAge
+	cutoff := now.Add(-opts.MaxAge)
+	err := filepath.Walk(logDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if path == archiveDir {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		rel, _ := filepath.Rel(logDir, path)
+		if rel != "hermes.log" && info.Mode().IsRegular() && info.ModTime().Before(cutoff) {
+			result.Archived = append(result.Archived, filepath.ToSlash(rel))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan logs: %w", err)
+	}
+
+	// The head of the active log
+	activeLog := filepath.Join(logDir, "hermes.log")
+	var dropped, kept []string
+	if data, err := os.ReadFile(activeLog); err == nil {
+		lines := strings.SplitAfter(string(data), "\n")
+		if lines[len(lines)-1] == "" {
+			lines = lines[:len(lines)-1]
+		}
+		if len(lines) > opts.KeepLines {
+			dropped = lines[:len(lines)-opts.KeepLines]
+			kept = lines[len(lines)-opts.KeepLines:]
+		}
+	}
+	result.DroppedLines = len(dropped)
+
+	if len(result.Archived) > 0 || len(dropped) > 0 {
+		if err := os.MkdirAll(archiveDir, 0755); err != nil {
+			return nil, fmt.Errorf("failed to create archive directory: %w", err)
+		}
+		result.Archive = newArchivePath(archiveDir, now)
+		if err := writeLogArchive(result.Archive, logDir, result.Archived, strings.Join(dropped, "")); err != nil {
+			os.Remove(result.Archive)
+			return nil, err
+		}
+
+		// Only remove what is safely archived. hermes.log is rewritten in
+		// place so a running logger keeps appending to it.
+		for _, rel := range result.Archived {
+			if err := os.Remove(filepath.Join(logDir, filepath.FromSlash(rel))); err != nil {
+				return nil, fmt.Errorf("failed to remove archived log: %w", err)
+			}
+		}
+		if len(dropped) > 0 {
+			if err := os.WriteFile(activeLog, []byte(strings.Join(kept, "")), 0644); err != nil {
+				return nil, fmt.Errorf("failed to truncate %s: %w", activeLog, err)
+			}
+		}
+	}
+
+	removed, err := pruneLogArchives(archiveDir, opts.MaxArchives)
+	if err != nil {
+		return nil, err
+	}
+	result.Removed = removed
+
+	return result, nil
+}
+
+// newArchivePath returns archive/YYYY-MM-DD.tar.gz, or YYYY-MM-DD-N.tar.gz
+// if logs were already archived that day
+func newArchivePath(archiveDir string, now time.Time) string {
+	date := now.Format("2006-01-02")
+	path := filepath.Join(archiveDir, date+".tar.gz")
+	for n := 2; ; n++ {
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			return path
+		}
+		path = filepath.Join(archiveDir, fmt.Sprintf("%s-%d.tar.gz", date, n))
+	}
+}
+
+// writeLogArchive writes the files, relative to logDir, and the dropped head
+// of hermes.log to a gzipped tar archive
+func writeLogArchive(path, logDir string, files []string, droppedLines string) error {
+	out, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create archive: %w", err)
+	}
+	defer out.Close()
+
+	gz := gzip.NewWriter(out)
+	tw := tar.NewWriter(gz)
+
+	for _, rel := range files {
+		if err := addFileToArchive(tw, filepath.Join(logDir, filepath.FromSlash(rel)), rel); err != nil {
+			return fmt.Errorf("failed to archive %s: %w", rel, err)
+		}
+	}
+	if droppedLines != "" {
+		header := &tar.Header{
+			Name:    "hermes.log",
+			Mode:    0644,
+			Size:    int64(len(droppedLines)),
+			ModTime: time.Now(),
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return fmt.Errorf("failed to archive hermes.log: %w", err)
+		}
+		if _, err := io.WriteString(tw, droppedLines); err != nil {
+			return fmt.Errorf("failed to archive hermes.log: %w", err)
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("failed to write archive: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return fmt.Errorf("failed to write archive: %w", err)
+	}
+	return out.Close()
+}
+
+// addFileToArchive adds a file to the archive under name
+func addFileToArchive(tw *tar.Writer, path, name string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+	header, err := tar.FileInfoHeader(info, "")
+	if err != nil {
+		return err
+	}
+	header.Name = name
+	if err := tw.WriteHeader(header); err != nil {
+		return err
+	}
+	_, err = io.Copy(tw, file)
+	return err
+}
+
+// pruneLogArchives deletes all but the newest keep archives and returns the
+// deleted file names
+func pruneLogArchives(archiveDir string, keep int) ([]string, error) {
+	entries, err := os.ReadDir(archiveDir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read archives: %w", err)
+	}
+
+	type archive struct {
+		name    string
+		modTime time.Time
+	}
+	var archives []archive
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".tar.gz") {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		archives = append(archives, archive{name: entry.Name(), modTime: info.ModTime()})
+	}
+	if len(archives) <= keep {
+		return nil, nil
+	}
+
+	// Newest first
+	sort.Slice(archives, func(i, j int) bool {
+		if !archives[i].modTime.Equal(archives[j].modTime) {
+			return archives[i].modTime.After(archives[j].modTime)
+		}
+		return archives[i].name > archives[j].name
+	})
+
+	var removed []string
+	for _, a := range archives[keep:] {
+		if err := os.Remove(filepath.Join(archiveDir, a.name)); err != nil {
+			return removed, fmt.Errorf("failed to remove archive: %w", err)
+		}
+		removed = append(removed, a.name)
+	}
+	return removed, nil
+}
+
+// rotateLogsIfLarge rotates the logs with the config defaults when hermes.log
+// is larger than maxSizeMB. It returns nil if the log was small enough.
+func rotateLogsIfLarge(logDir string, maxSizeMB int, cfg config.LogRotationConfig) (*logRotateResult, error) {
+	if maxSizeMB <= 0 {
+		return nil, nil
+	}
+	info, err := os.Stat(filepath.Join(logDir, "hermes.log"))
+	if err != nil || info.Size() <= int64(maxSizeMB)*1024*1024 {
+		return nil, nil
+	}
+	return rotateLogs(logDir, logRotateDefaults(cfg), time.Now())
+}
+
+// printLogRotateResult prints a summary of a rotation
+func printLogRotateResult(result *logRotateResult) {
+	if result.Archive == "" && len(result.Removed) == 0 {
+		fmt.Println("Nothing to rotate")
+		return
+	}
+	if result.Archive != "" {
+		fmt.Printf("Archived %d file(s) and %d line(s) of hermes.log to %s\n",
+			len(result.Archived), result.DroppedLines, result.Archive)
+	}
+	if len(result.Removed) > 0 {
+		fmt.Printf("Removed %d old archive(s): %s\n", len(result.Removed), strings.Join(result.Removed, ", "))
+	}
+}
@@ -6,20 +6,30 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
 	"hermes/internal/ai"
 	"hermes/internal/config"
+	"hermes/internal/task"
 	"hermes/internal/ui"
 )
 
+// splitBySection writes one task file per top-level PRD section
+const splitBySection = "section"
+
 type prdOptions struct {
-	dryRun     bool
-	timeout    int
-	maxRetries int
-	debug      bool
+	dryRun        bool
+	splitBy       string
+	timeout       int
+	maxRetries    int
+	retryBackoff  float64
+	retryMaxDelay time.Duration
+	debug         bool
+	update        string
+	force         bool
 }
 
 // NewPrdCmd creates the prd subcommand
@@ -32,9 +42,15 @@ func NewPrdCmd() *cobra.Command {
 		Long:  "Parse a Product Requirements Document and generate task files",
 		Example: `  hermes prd docs/PRD.md
   hermes prd requirements.md --dry-run
-  hermes prd spec.md --timeout 1200`,
+  hermes prd spec.md --timeout 1200
+  hermes prd large-prd.md --split-by section
+  hermes prd docs/PRD.md --update F003
+  hermes prd validate docs/PRD.md`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if opts.update != "" {
+				return prdUpdateExecute(args[0], normalizeFeatureID(opts.update), opts)
+			}
 			return prdExecute(args[0], opts)
 		},
 	}
@@ -42,14 +58,25 @@ func NewPrdCmd() *cobra.Command {
 	cmd.Flags().BoolVar(&opts.dryRun, "dry-run", false, "Show output without writing files")
 	cmd.Flags().IntVar(&opts.timeout, "timeout", 1200, "Timeout in seconds")
 	cmd.Flags().IntVar(&opts.maxRetries, "max-retries", 10, "Max retry attempts")
+	cmd.Flags().Float64Var(&opts.retryBackoff, "retry-backoff", 2, "Multiply the retry delay by this factor after each attempt (1 = fixed delay)")
+	cmd.Flags().DurationVar(&opts.retryMaxDelay, "retry-max-delay", 5*time.Minute, "Maximum delay between retries")
+	cmd.Flags().StringVar(&opts.splitBy, "split-by", "", "Split output into one task file per PRD unit (section)")
+	cmd.Flags().StringVar(&opts.update, "update", "", "Regenerate only the tasks of this feature from its PRD section")
+	cmd.Flags().BoolVar(&opts.force, "force", false, "With --update, also replace IN_PROGRESS tasks")
 	cmd.Flags().BoolVar(&opts.debug, "debug", false, "Enable debug output")
 
+	cmd.AddCommand(newPrdValidateCmd())
+
 	return cmd
 }
 
 func prdExecute(prdFile string, opts *prdOptions) error {
 	ctx := context.Background()
 
+	if opts.splitBy != "" && opts.splitBy != splitBySection {
+		return fmt.Errorf("invalid --split-by value %q (supported: %s)", opts.splitBy, splitBySection)
+	}
+
 	ui.PrintBanner()
 	ui.PrintHeader("PRD Parser")
 
@@ -79,13 +106,7 @@ func prdExecute(prdFile string, opts *prdOptions) error {
 	}
 
 	// Get provider from config
-	var provider ai.Provider
-	if cfg.AI.Planning != "" && cfg.AI.Planning != "auto" {
-		provider = ai.GetProvider(cfg.AI.Planning)
-	}
-	if provider == nil || !provider.IsAvailable() {
-		provider = ai.AutoDetectProvider()
-	}
+	provider := planningProvider(cfg)
 	if provider == nil {
 		return fmt.Errorf("no AI provider available")
 	}
@@ -94,8 +115,18 @@ func prdExecute(prdFile string, opts *prdOptions) error {
 		logger.Info("Using AI provider: %s", provider.Name())
 	}
 
+	// Collect top-level sections when splitting
+	var sections []string
+	if opts.splitBy == splitBySection {
+		sections = prdSections(string(prdContent))
+		if len(sections) == 0 {
+			return fmt.Errorf("no top-level sections (## Heading) found in %s", prdFile)
+		}
+		fmt.Printf("Splitting by section: %d sections\n\n", len(sections))
+	}
+
 	// Build prompt
-	prompt := buildPrdPrompt(string(prdContent))
+	prompt := buildPrdPrompt(string(prdContent), sections)
 
 	// Execute with retry
 	startTime := time.Now()
@@ -104,8 +135,11 @@ func prdExecute(prdFile string, opts *prdOptions) error {
 		Timeout:      opts.timeout,
 		StreamOutput: cfg.AI.StreamOutput,
 	}, &ai.RetryConfig{
-		MaxRetries: opts.maxRetries,
-		Delay:      10 * time.Second,
+		MaxRetries:        opts.maxRetries,
+		Delay:             10 * time.Second,
+		BackoffMultiplier: opts.retryBackoff,
+		MaxDelay:          opts.retryMaxDelay,
+		Jitter:            true,
 	})
 
 	duration := time.Since(startTime)
@@ -128,7 +162,12 @@ func prdExecute(prdFile string, opts *prdOptions) error {
 	}
 
 	// Write task files
-	if err := writeTaskFiles(result.Output); err != nil {
+	if len(sections) > 0 {
+		err = writeSectionFiles(result.Output, sections)
+	} else {
+		err = writeTaskFiles(result.Output)
+	}
+	if err != nil {
 		if logger != nil {
 			logger.Error("Failed to write task files: %v", err)
 		}
@@ -142,8 +181,24 @@ func prdExecute(prdFile string, opts *prdOptions) error {
 	return nil
 }
 
-func buildPrdPrompt(prdContent string) string {
+// planningProvider returns the AI provider configured for planning, or an
+// auto-detected one if it is not set or not available
+func planningProvider(cfg *config.Config) ai.Provider {
+	var provider ai.Provider
+	if cfg.AI.Planning != "" && cfg.AI.Planning != "auto" {
+		provider = ai.GetProvider(cfg.AI.Planning)
+	}
+	if provider == nil || !provider.IsAvailable() {
+		provider = ai.AutoDetectProvider()
+	}
+	return provider
+}
+
+// buildPrdPrompt builds the PRD parsing prompt. When sections are given, the
+// AI is asked to produce exactly one feature file per section.
+func buildPrdPrompt(prdContent string, sections []string) string {
 	return fmt.Sprintf(`Parse this PRD into comprehensive task files.
+%s
 
 For each feature, create a markdown file with this EXACT format:
 
@@ -239,12 +294,61 @@ PRD Content:
 
 %s
 
-Output each file with:
+%s`, prdSectionInstructions(sections), prdContent, prdFileInstructions(sections))
+}
+
+// prdSectionInstructions explains the one-file-per-section expectation
+func prdSectionInstructions(sections []string) string {
+	if len(sections) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	sb.WriteString("\nSPLIT BY SECTION: Create exactly ONE feature file per top-level PRD section (## heading), in this order:\n\n")
+	for i, section := range sections {
+		sb.WriteString(fmt.Sprintf("%d. %s (Feature %d, F%03d)\n", i+1, section, i+1, i+1))
+	}
+	sb.WriteString("\nAll tasks derived from a section belong in that section's file. Do not merge or skip sections.\n")
+	sb.WriteString("Task IDs must be unique and sequential across all files.\n")
+	return sb.String()
+}
+
+// prdFileInstructions describes the expected file block format
+func prdFileInstructions(sections []string) string {
+	if len(sections) == 0 {
+		return `Output each file with:
 ---FILE: XXX-feature-name.md---
 <content>
----END_FILE---`, prdContent)
+---END_FILE---`
+	}
+
+	return `Output each section's file with the exact section heading as the file name:
+---FILE: <Section Name>---
+<content>
+---END_FILE---`
 }
 
+// prdSections returns the top-level (## ) section headings of a PRD
+func prdSections(content string) []string {
+	var sections []string
+	inCodeBlock := false
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") {
+			inCodeBlock = !inCodeBlock
+			continue
+		}
+		if !inCodeBlock && strings.HasPrefix(trimmed, "## ") {
+			if name := strings.TrimSpace(strings.TrimPrefix(trimmed, "## ")); name != "" {
+				sections = append(sections, name)
+			}
+		}
+	}
+	return sections
+}
+
+var prdFileRegex = regexp.MustCompile(`---FILE:\s*(.+?)---\s*([\s\S]*?)---END_FILE---`)
+
 func writeTaskFiles(output string) error {
 	// Create tasks directory
 	tasksDir := filepath.Join(".hermes", "tasks")
@@ -253,8 +357,7 @@ func writeTaskFiles(output string) error {
 	}
 
 	// Parse FILE markers
-	fileRegex := regexp.MustCompile(`---FILE:\s*(.+?)---\s*([\s\S]*?)---END_FILE---`)
-	matches := fileRegex.FindAllStringSubmatch(output, -1)
+	matches := prdFileRegex.FindAllStringSubmatch(output, -1)
 
 	if len(matches) == 0 {
 		// No file markers, write single file
@@ -280,3 +383,70 @@ func writeTaskFiles(output string) error {
 	fmt.Printf("\nCreated %d task files in %s\n", len(matches), tasksDir)
 	return nil
 }
+
+// blockFeatureNumber returns the number of the feature ID in a returned
+// file block (5 for F005), or fallback if it has none
+func blockFeatureNumber(content string, fallback int) int {
+	feature, err := task.ParseFeature(content, "")
+	if err != nil || feature.ID == "" {
+		return fallback
+	}
+	n, err := strconv.Atoi(strings.TrimPrefix(feature.ID, "F"))
+	if err != nil {
+		return fallback
+	}
+	return n
+}
+
+// writeSectionFiles writes one file per returned block, named after the PRD
+// section it belongs to (001-section-name.md)
+func writeSectionFiles(output string, sections []string) error {
+	matches := prdFileRegex.FindAllStringSubmatch(output, -1)
+	if len(matches) == 0 {
+		return fmt.Errorf("no ---FILE: blocks found in AI output")
+	}
+
+	tasksDir := filepath.Join(".hermes", "tasks")
+	if err := os.MkdirAll(tasksDir, 0755); err != nil {
+		return err
+	}
+
+	written := make(map[string]bool)
+	for i, match := range matches {
+		name := strings.TrimSuffix(strings.TrimSpace(match[1]), ".md")
+		content := strings.TrimSpace(match[2])
+
+		// Number files by the section's position in the PRD. Blocks that
+		// match no section, or a section already written, are numbered by
+		// their feature ID so that they do not overwrite each other.
+		position := 0
+		for j, section := range sections {
+			if strings.EqualFold(section, name) {
+				position = j + 1
+				name = section
+				break
+			}
+		}
+		fileName := featureFileName(position, name)
+		if position == 0 || written[fileName] {
+			fileName = featureFileName(blockFeatureNumber(content, i+1), name)
+		}
+		if written[fileName] {
+			return fmt.Errorf("AI returned two files named %s", fileName)
+		}
+		written[fileName] = true
+
+		filePath := filepath.Join(tasksDir, fileName)
+		if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
+			return err
+		}
+		fmt.Printf("Created: %s\n", filePath)
+	}
+
+	if len(matches) != len(sections) {
+		fmt.Printf("\nWarning: expected %d section files, AI returned %d\n", len(sections), len(matches))
+	}
+
+	fmt.Printf("\nCreated %d task files in %s\n", len(matches), tasksDir)
+	return nil
+}
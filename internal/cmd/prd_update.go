@@ -0,0 +1,221 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"hermes/internal/ai"
+	"hermes/internal/analyzer"
+	"hermes/internal/config"
+	"hermes/internal/task"
+	"hermes/internal/ui"
+)
+
+var markdownHeadingRegex = regexp.MustCompile(`^(#{1,6})\s+(.+?)\s*#*$`)
+
+// prdUpdateExecute regenerates the tasks of one feature from its PRD section
+// and merges them into the feature file, keeping completed tasks
+func prdUpdateExecute(prdFile, featureID string, opts *prdOptions) error {
+	ctx := context.Background()
+
+	if opts.splitBy != "" {
+		return fmt.Errorf("--update cannot be combined with --split-by")
+	}
+
+	ui.PrintBanner()
+	ui.PrintHeader("PRD Update")
+
+	cfg, err := config.Load(".")
+	if err != nil {
+		cfg = config.DefaultConfig()
+	}
+
+	feature, err := newTaskReader(".").GetFeatureByID(featureID)
+	if err != nil {
+		return err
+	}
+	if feature == nil {
+		return fmt.Errorf("feature %s not found", featureID)
+	}
+
+	prdContent, err := os.ReadFile(prdFile)
+	if err != nil {
+		return fmt.Errorf("failed to read PRD: %w", err)
+	}
+	section := prdFeatureSection(string(prdContent), feature)
+	if section == "" {
+		return fmt.Errorf("no section for %s (%s) found in %s", feature.ID, feature.Name, prdFile)
+	}
+
+	// Refuse before calling the AI if work in progress would be lost
+	var inProgress []string
+	for _, t := range feature.Tasks {
+		if t.Status == task.StatusInProgress {
+			inProgress = append(inProgress, t.ID)
+		}
+	}
+	if len(inProgress) > 0 && !opts.force {
+		return fmt.Errorf("tasks %s are IN_PROGRESS and would be replaced (use --force)", strings.Join(inProgress, ", "))
+	}
+
+	content, err := os.ReadFile(feature.FilePath)
+	if err != nil {
+		return err
+	}
+
+	_, nextTaskID, err := analyzer.NewFeatureAnalyzer(".").GetNextIDs()
+	if err != nil {
+		nextTaskID = 1
+	}
+
+	provider := planningProvider(cfg)
+	if provider == nil {
+		return fmt.Errorf("no AI provider available")
+	}
+	fmt.Printf("Feature: %s - %s\n", feature.ID, feature.Name)
+	fmt.Printf("PRD section: %d chars\n", len(section))
+	fmt.Printf("Using AI: %s\n\n", provider.Name())
+
+	result, err := ai.ExecuteWithRetry(ctx, provider, &ai.ExecuteOptions{
+		Prompt:       buildPrdUpdatePrompt(section, string(content), feature, nextTaskID),
+		Timeout:      opts.timeout,
+		StreamOutput: cfg.AI.StreamOutput,
+	}, &ai.RetryConfig{
+		MaxRetries:        opts.maxRetries,
+		Delay:             10 * time.Second,
+		BackoffMultiplier: opts.retryBackoff,
+		MaxDelay:          opts.retryMaxDelay,
+		Jitter:            true,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to regenerate tasks: %w", err)
+	}
+
+	generated := result.Output
+	if m := prdFileRegex.FindStringSubmatch(generated); m != nil {
+		generated = m[2]
+	}
+
+	merged, removed, added, err := task.MergeRegeneratedTasks(string(content), generated)
+	if err != nil {
+		return err
+	}
+
+	printTaskMergeDiff(feature, removed, added)
+
+	if opts.dryRun {
+		fmt.Println("\nDry run: no files written.")
+		return nil
+	}
+
+	if err := os.WriteFile(feature.FilePath, []byte(merged), 0644); err != nil {
+		return err
+	}
+	fmt.Printf("\nUpdated: %s\n", feature.FilePath)
+	return nil
+}
+
+// prdFeatureSection returns the PRD section whose heading names the feature
+// or contains its ID, up to the next heading of the same or a higher level
+func prdFeatureSection(content string, feature *task.Feature) string {
+	lines := strings.Split(content, "\n")
+	name := strings.ToLower(feature.Name)
+
+	start, level := -1, 0
+	inCodeBlock := false
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") {
+			inCodeBlock = !inCodeBlock
+			continue
+		}
+		if inCodeBlock {
+			continue
+		}
+
+		m := markdownHeadingRegex.FindStringSubmatch(trimmed)
+		if m == nil {
+			continue
+		}
+		if start >= 0 {
+			if len(m[1]) <= level {
+				return strings.TrimSpace(strings.Join(lines[start:i], "\n"))
+			}
+			continue
+		}
+
+		heading := strings.ToLower(m[2])
+		if (name != "" && strings.Contains(heading, name)) || strings.Contains(heading, strings.ToLower(feature.ID)) {
+			start, level = i, len(m[1])
+		}
+	}
+
+	if start < 0 {
+		return ""
+	}
+	return strings.TrimSpace(strings.Join(lines[start:], "\n"))
+}
+
+// buildPrdUpdatePrompt asks for the feature file regenerated from the updated
+// PRD section, using the current file as the format reference
+func buildPrdUpdatePrompt(section, current string, feature *task.Feature, nextTaskID int) string {
+	var completed []string
+	for _, t := range feature.Tasks {
+		if t.Status == task.StatusCompleted {
+			completed = append(completed, fmt.Sprintf("- %s: %s", t.ID, t.Name))
+		}
+	}
+	completedList := "- None"
+	if len(completed) > 0 {
+		completedList = strings.Join(completed, "\n")
+	}
+
+	return fmt.Sprintf(`The PRD section for feature %s (%s) has changed. Regenerate the feature's tasks from the updated section.
+
+Current feature file (use its EXACT format):
+
+%s
+
+These tasks are already COMPLETED. Do not repeat them; new tasks may depend on them:
+
+%s
+
+RULES:
+1. Output the complete feature file with Feature ID %s
+2. Number new tasks from T%03d
+3. Every new task has **Status:** NOT_STARTED
+4. Create tasks only for work the updated section requires that is not completed yet
+5. Tasks should be 0.5-3 days of work, atomic and independently testable
+
+Updated PRD section:
+
+%s
+
+Output only the markdown content, no additional explanation.`, feature.ID, feature.Name, current, completedList, feature.ID, nextTaskID, section)
+}
+
+// printTaskMergeDiff shows the tasks removed from and added to a feature
+func printTaskMergeDiff(feature *task.Feature, removed, added []task.Task) {
+	bold := color.New(color.Bold)
+	red := color.New(color.FgRed)
+	green := color.New(color.FgGreen)
+
+	fmt.Println()
+	bold.Printf("Changes to %s:\n", feature.ID)
+	if len(removed) == 0 && len(added) == 0 {
+		fmt.Println("  (no task changes)")
+		return
+	}
+	for _, t := range removed {
+		red.Printf("  - %s: %s (%s)\n", t.ID, t.Name, t.Status)
+	}
+	for _, t := range added {
+		green.Printf("  + %s: %s\n", t.ID, t.Name)
+	}
+	fmt.Printf("\n%d removed, %d added, %d completed kept\n", len(removed), len(added), len(feature.Tasks)-len(removed))
+}
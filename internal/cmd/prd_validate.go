@@ -0,0 +1,183 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+// defaultMaxFeatureWords is the feature length above which prd validate warns
+const defaultMaxFeatureWords = 5000
+
+var (
+	prdFeatureHeadingRegex = regexp.MustCompile(`(?i)^##\s+feature\b`)
+	prdListItemRegex       = regexp.MustCompile(`^\s*(?:[-*+]|\d+[.)])\s+\S`)
+	prdPlaceholderRegex    = regexp.MustCompile(`\[TBD\]|\bTBD\b|\bTODO\b|\bFIXME\b|\?\?\?`)
+)
+
+// prdIssue is a problem found in a PRD. Errors make the PRD unusable,
+// warnings point at parts the AI is likely to get wrong.
+type prdIssue struct {
+	Line    int // 1-based, 0 for the whole file
+	IsError bool
+	Message string
+}
+
+// prdFeature is a "## Feature" section of a PRD
+type prdFeature struct {
+	Name  string
+	Line  int
+	Lines []string
+}
+
+func newPrdValidateCmd() *cobra.Command {
+	var maxWords int
+
+	cmd := &cobra.Command{
+		Use:   "validate <file>",
+		Short: "Check a PRD for problems before parsing it",
+		Long: `Check a PRD for problems that waste AI tokens on bad task files.
+
+Errors (exit code 1):
+  - no "## Feature" sections
+
+Warnings (exit code 0):
+  - a feature without acceptance criteria
+  - a feature longer than --max-feature-words words
+  - placeholder text such as [TBD], TODO or FIXME in a feature`,
+		Example: `  hermes prd validate docs/PRD.md
+  hermes prd validate docs/PRD.md --max-feature-words 3000`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return prdValidateExecute(args[0], maxWords, os.Stdout)
+		},
+	}
+
+	cmd.Flags().IntVar(&maxWords, "max-feature-words", defaultMaxFeatureWords, "Warn about features longer than this many words")
+
+	return cmd
+}
+
+func prdValidateExecute(prdFile string, maxWords int, w io.Writer) error {
+	if maxWords <= 0 {
+		return fmt.Errorf("--max-feature-words must be positive")
+	}
+
+	content, err := os.ReadFile(prdFile)
+	if err != nil {
+		return fmt.Errorf("failed to read PRD: %w", err)
+	}
+
+	features := prdFeatures(string(content))
+	issues := validatePRD(features, maxWords)
+
+	red := color.New(color.FgRed)
+	yellow := color.New(color.FgYellow)
+	errors, warnings := 0, 0
+	for _, issue := range issues {
+		location := prdFile
+		if issue.Line > 0 {
+			location = fmt.Sprintf("%s:%d", prdFile, issue.Line)
+		}
+		if issue.IsError {
+			errors++
+			red.Fprintf(w, "error: ")
+		} else {
+			warnings++
+			yellow.Fprintf(w, "warning: ")
+		}
+		fmt.Fprintf(w, "%s: %s\n", location, issue.Message)
+	}
+
+	if len(issues) > 0 {
+		fmt.Fprintln(w)
+	}
+	fmt.Fprintf(w, "%d feature(s), %d error(s), %d warning(s)\n", len(features), errors, warnings)
+
+	if errors > 0 {
+		return fmt.Errorf("%s has %d error(s)", prdFile, errors)
+	}
+	return nil
+}
+
+// prdFeatures returns the "## Feature" sections of a PRD, each up to the next
+// heading of level 1 or 2. Headings in code blocks are ignored.
+func prdFeatures(content string) []prdFeature {
+	var features []prdFeature
+	var current *prdFeature
+	inCodeBlock := false
+
+	for i, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") {
+			inCodeBlock = !inCodeBlock
+		}
+		if !inCodeBlock {
+			if prdFeatureHeadingRegex.MatchString(trimmed) {
+				features = append(features, prdFeature{
+					Name: strings.TrimSpace(strings.TrimLeft(trimmed, "#")),
+					Line: i + 1,
+				})
+				current = &features[len(features)-1]
+				continue
+			}
+			if strings.HasPrefix(trimmed, "# ") || strings.HasPrefix(trimmed, "## ") {
+				current = nil
+			}
+		}
+		if current != nil {
+			current.Lines = append(current.Lines, line)
+		}
+	}
+	return features
+}
+
+// validatePRD checks the features of a PRD
+func validatePRD(features []prdFeature, maxWords int) []prdIssue {
+	if len(features) == 0 {
+		return []prdIssue{{IsError: true, Message: `no feature sections found (add "## Feature: <name>" headings)`}}
+	}
+
+	var issues []prdIssue
+	for _, f := range features {
+		if !hasAcceptanceCriteria(f.Lines) {
+			issues = append(issues, prdIssue{Line: f.Line, Message: fmt.Sprintf("%q has no acceptance criteria", f.Name)})
+		}
+
+		words := 0
+		for _, line := range f.Lines {
+			words += len(strings.Fields(line))
+		}
+		if words > maxWords {
+			issues = append(issues, prdIssue{Line: f.Line, Message: fmt.Sprintf("%q has %d words (max %d), consider splitting it", f.Name, words, maxWords)})
+		}
+
+		for i, line := range f.Lines {
+			if m := prdPlaceholderRegex.FindString(line); m != "" {
+				issues = append(issues, prdIssue{Line: f.Line + 1 + i, Message: fmt.Sprintf("placeholder %q in %q", m, f.Name)})
+			}
+		}
+	}
+	return issues
+}
+
+// hasAcceptanceCriteria reports whether the lines contain an "Acceptance
+// Criteria" label or heading followed by at least one list item
+func hasAcceptanceCriteria(lines []string) bool {
+	found := false
+	for _, line := range lines {
+		if !found {
+			found = strings.Contains(strings.ToLower(line), "acceptance criteri")
+			continue
+		}
+		if prdListItemRegex.MatchString(line) {
+			return true
+		}
+	}
+	return false
+}
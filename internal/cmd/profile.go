@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// profileTopHotspots is how many functions 'hermes profile analyze' prints
+const profileTopHotspots = 10
+
+// NewProfileCmd creates the profile command
+func NewProfileCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "profile",
+		Short: "Analyze profiles written by 'hermes run --profile'",
+		Long: `Analyze the CPU and memory profiles 'hermes run --profile cpu|mem' writes to
+.hermes/profiles/.`,
+		Example: `  hermes profile analyze .hermes/profiles/cpu.pprof`,
+	}
+
+	cmd.AddCommand(newProfileAnalyzeCmd())
+
+	return cmd
+}
+
+// newProfileAnalyzeCmd creates the profile analyze subcommand
+func newProfileAnalyzeCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "analyze <file>",
+		Short: "Print the top hotspots of a profile",
+		Long: fmt.Sprintf(`Print the %d functions with the most samples in a profile, using
+'go tool pprof -top'. Requires the Go toolchain. Execution traces
+(--trace) are viewed with 'go tool trace' instead.`, profileTopHotspots),
+		Example: `  hermes profile analyze .hermes/profiles/cpu.pprof
+  hermes profile analyze .hermes/profiles/mem.pprof`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return profileAnalyzeExecute(args[0], os.Stdout)
+		},
+	}
+}
+
+func profileAnalyzeExecute(file string, w io.Writer) error {
+	if _, err := os.Stat(file); err != nil {
+		return fmt.Errorf("profile not found: %w", err)
+	}
+	if _, err := exec.LookPath("go"); err != nil {
+		return fmt.Errorf("analyzing profiles requires the Go toolchain (go not found in PATH)")
+	}
+
+	output, err := exec.Command("go", "tool", "pprof", "-top", file).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("go tool pprof failed: %w: %s", err, strings.TrimSpace(string(output)))
+	}
+
+	fmt.Fprint(w, topHotspots(string(output), profileTopHotspots))
+	return nil
+}
+
+// topHotspots keeps the summary of 'go tool pprof -top' output and the
+// first n rows of its table
+func topHotspots(output string, n int) string {
+	var sb strings.Builder
+	rows := -1 // Counts rows once the table header is seen
+	for _, line := range strings.Split(strings.TrimRight(output, "\n"), "\n") {
+		if rows >= n {
+			break
+		}
+		if rows >= 0 {
+			rows++
+		} else if strings.Contains(line, "flat%") && strings.Contains(line, "cum%") {
+			rows = 0
+		}
+		sb.WriteString(line + "\n")
+	}
+	return sb.String()
+}
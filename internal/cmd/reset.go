@@ -2,22 +2,51 @@ package cmd
 
 import (
 	"fmt"
+	"sort"
 
 	"github.com/spf13/cobra"
 	"hermes/internal/circuit"
+	"hermes/internal/prompt"
+	"hermes/internal/task"
 )
 
+type resetOptions struct {
+	taskID  string
+	status  string
+	cascade bool
+}
+
 // NewResetCmd creates the reset subcommand
 func NewResetCmd() *cobra.Command {
+	opts := &resetOptions{}
+
 	cmd := &cobra.Command{
 		Use:   "reset",
-		Short: "Reset circuit breaker",
-		Long:  "Reset the circuit breaker to allow execution to continue",
+		Short: "Reset circuit breaker or a task",
+		Long: `Reset the circuit breaker to allow execution to continue.
+
+With --task, reset a single task to NOT_STARTED (or --status) instead, e.g.
+to retry a failed task without redoing completed work. --cascade also resets
+every task that depends on it, directly or through other tasks.`,
+		Example: `  hermes reset
+  hermes reset --task T003
+  hermes reset --task 3 --cascade
+  hermes reset --task T003 --status BLOCKED`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return resetExecute()
+			if opts.taskID == "" {
+				if cmd.Flags().Changed("status") || opts.cascade {
+					return fmt.Errorf("--status and --cascade require --task")
+				}
+				return resetExecute()
+			}
+			return resetTaskExecute(normalizeTaskID(opts.taskID), task.Status(opts.status), opts.cascade)
 		},
 	}
 
+	cmd.Flags().StringVar(&opts.taskID, "task", "", "Reset only this task instead of the circuit breaker")
+	cmd.Flags().StringVar(&opts.status, "status", string(task.StatusNotStarted), "Status to reset the task to")
+	cmd.Flags().BoolVar(&opts.cascade, "cascade", false, "Also reset the tasks that depend on the task to NOT_STARTED")
+
 	return cmd
 }
 
@@ -46,3 +75,65 @@ func resetExecute() error {
 
 	return nil
 }
+
+// resetTaskExecute sets a task to the given status and, with cascade, its
+// dependents to NOT_STARTED. The task section is removed from PROMPT.md if
+// a reset task is the one injected there.
+func resetTaskExecute(taskID string, status task.Status, cascade bool) error {
+	if !task.IsValidStatus(status) {
+		return fmt.Errorf("invalid status: %s", status)
+	}
+
+	target, err := findTask(taskID)
+	if err != nil {
+		return err
+	}
+
+	resets := []*task.Task{target}
+	if cascade {
+		dependents, err := newTaskReader(".").GetDependents(taskID)
+		if err != nil {
+			return err
+		}
+		ids := make([]string, 0, len(dependents))
+		for id := range dependents {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+		for _, id := range ids {
+			resets = append(resets, dependents[id])
+		}
+	}
+
+	updater := task.NewStatusUpdater(".")
+	injector := prompt.NewInjector(".")
+	for i, t := range resets {
+		to := task.StatusNotStarted
+		if i == 0 {
+			to = status
+		}
+		if t.Status == to && (to == task.StatusBlocked || t.BlockedReason == "") {
+			continue
+		}
+
+		// A block reason would keep the task blocked, so clear it first
+		if t.BlockedReason != "" && to != task.StatusBlocked {
+			if err := updater.UnblockTask(t.ID); err != nil {
+				return fmt.Errorf("failed to reset %s: %w", t.ID, err)
+			}
+		}
+		if err := updater.UpdateTaskStatus(t.ID, to); err != nil {
+			return fmt.Errorf("failed to reset %s: %w", t.ID, err)
+		}
+		printStatusChange(t.ID, t.Status, to)
+
+		if current, _ := injector.GetCurrentTaskID(t.FeatureID); current == t.ID {
+			if err := injector.RemoveTask(t.FeatureID); err != nil {
+				return fmt.Errorf("failed to clear %s from PROMPT.md: %w", t.ID, err)
+			}
+			fmt.Printf("  Removed %s from PROMPT.md\n", t.ID)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"hermes/internal/git"
+	"hermes/internal/scheduler"
+)
+
+type rollbackOptions struct {
+	dryRun     bool
+	checkpoint int
+}
+
+// NewRollbackCmd creates the rollback command
+func NewRollbackCmd() *cobra.Command {
+	opts := &rollbackOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "rollback",
+		Short: "Rollback changes from parallel execution",
+		Long: `Reset the repository to a snapshot saved during parallel execution.
+
+Snapshots are stored in .hermes/snapshots.json and are taken before each
+task runs. Use --dry-run to see the git command without executing it.
+
+With --checkpoint, reset to the checkpoint commit created at a loop by
+'hermes run --checkpoint-interval' instead.`,
+		Example: `  hermes rollback task T003
+  hermes rollback batch T003 T004
+  hermes rollback all --dry-run
+  hermes rollback --checkpoint 20`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if cmd.Flags().Changed("checkpoint") {
+				return rollbackCheckpointExecute(opts)
+			}
+			rollback, err := scheduler.LoadRollback(".")
+			if err != nil {
+				return err
+			}
+			rollback.PrintStatus()
+			return nil
+		},
+	}
+
+	cmd.PersistentFlags().BoolVar(&opts.dryRun, "dry-run", false, "Print the git reset command without executing it")
+	cmd.Flags().IntVar(&opts.checkpoint, "checkpoint", 0, "Reset to the checkpoint commit of this loop")
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "task <id>",
+		Short: "Rollback to the snapshot taken before a task",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			taskID := normalizeTaskID(args[0])
+			return rollbackExecute(opts, "task "+taskID,
+				func(r *scheduler.Rollback) (string, error) { return r.TaskTarget(taskID) },
+				func(r *scheduler.Rollback) error { return r.RollbackTask(taskID) })
+		},
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "batch <ids...>",
+		Short: "Rollback to the earliest snapshot of a batch of tasks",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			taskIDs := make([]string, len(args))
+			for i, arg := range args {
+				taskIDs[i] = normalizeTaskID(arg)
+			}
+			return rollbackExecute(opts, "batch "+strings.Join(taskIDs, ", "),
+				func(r *scheduler.Rollback) (string, error) { return r.BatchTarget(taskIDs) },
+				func(r *scheduler.Rollback) error { return r.RollbackBatch(taskIDs) })
+		},
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "all",
+		Short: "Rollback to the state before parallel execution started",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return rollbackExecute(opts, "all tasks",
+				func(r *scheduler.Rollback) (string, error) { return r.AllTarget() },
+				func(r *scheduler.Rollback) error { return r.RollbackAll() })
+		},
+	})
+
+	return cmd
+}
+
+func rollbackExecute(opts *rollbackOptions, scope string, target func(*scheduler.Rollback) (string, error), apply func(*scheduler.Rollback) error) error {
+	rollback, err := scheduler.LoadRollback(".")
+	if err != nil {
+		return err
+	}
+
+	commitHash, err := target(rollback)
+	if err != nil {
+		return err
+	}
+
+	if opts.dryRun {
+		fmt.Printf("Would rollback %s:\n", scope)
+		fmt.Printf("  git reset --hard %s\n", commitHash)
+		return nil
+	}
+
+	if err := apply(rollback); err != nil {
+		return fmt.Errorf("rollback failed: %w", err)
+	}
+
+	color.Green("Rolled back %s to %s\n", scope, commitHash[:8])
+	return nil
+}
+
+// rollbackCheckpointExecute resets the repository to a checkpoint commit
+func rollbackCheckpointExecute(opts *rollbackOptions) error {
+	gitOps := git.New(".", "")
+	commitHash, err := gitOps.GetCheckpointCommit(opts.checkpoint)
+	if err != nil {
+		return err
+	}
+
+	scope := "checkpoint " + git.CheckpointTag(opts.checkpoint)
+	if opts.dryRun {
+		fmt.Printf("Would rollback %s:\n", scope)
+		fmt.Printf("  git reset --hard %s\n", commitHash)
+		return nil
+	}
+
+	if err := gitOps.ResetHard(commitHash); err != nil {
+		return fmt.Errorf("rollback failed: %w", err)
+	}
+
+	color.Green("Rolled back %s to %s\n", scope, commitHash[:8])
+	return nil
+}
+
+// normalizeTaskID converts user input like "3" or "t003" to "T003"
+func normalizeTaskID(id string) string {
+	taskID := strings.ToUpper(id)
+	if !strings.HasPrefix(taskID, "T") && taskID != "INITIAL" {
+		// Pad with zeros if numeric (1 -> T001, 12 -> T012)
+		taskID = fmt.Sprintf("T%03s", taskID)
+	}
+	return taskID
+}
@@ -3,24 +3,122 @@ package cmd
 import (
 	"bufio"
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
+	tea "github.com/charmbracelet/bubbletea"
 	"github.com/spf13/cobra"
 	"hermes/internal/ai"
 	"hermes/internal/analyzer"
 	"hermes/internal/circuit"
 	"hermes/internal/config"
 	"hermes/internal/git"
+	"hermes/internal/isolation"
+	"hermes/internal/merger"
+	"hermes/internal/metrics"
+	"hermes/internal/notifier"
 	"hermes/internal/prompt"
 	"hermes/internal/scheduler"
 	"hermes/internal/task"
+	"hermes/internal/tui"
 	"hermes/internal/ui"
 )
 
+// conflictWatchInterval is how often task branches are checked for new
+// conflicts during a parallel run
+const conflictWatchInterval = 10 * time.Second
+
+// minFreeDiskMB is the free disk space parallel workers check for before
+// each task
+const minFreeDiskMB = 500
+
+// Exit codes of 'hermes run --fail-fast'
+const (
+	exitFailed  = 1 // The AI failed on a task
+	exitStalled = 2 // A task made no progress for --max-stall-loops loops
+)
+
+// ExitError is an error that exits the process with Code
+type ExitError struct {
+	Code int
+	Err  error
+}
+
+func (e *ExitError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
+
+// ExitCode returns the process exit code for an error returned by a command:
+// 0 for nil, the code of an ExitError, and 1 otherwise
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	var exitErr *ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.Code
+	}
+	return 1
+}
+
+// stallTracker counts the consecutive loops a task runs without progress
+type stallTracker struct {
+	max    int
+	taskID string
+	loops  int
+}
+
+// record adds a loop of the task and reports whether it has now run max
+// loops in a row without progress
+func (s *stallTracker) record(taskID string, hasProgress bool) bool {
+	if taskID != s.taskID || hasProgress {
+		s.taskID, s.loops = taskID, 0
+	}
+	if !hasProgress {
+		s.loops++
+	}
+	return s.max > 0 && s.loops >= s.max
+}
+
+// parallelOptions contains settings for parallel execution
+type parallelOptions struct {
+	workers      int
+	dryRun       bool
+	autoCommit   bool
+	showTUI      bool
+	isolation    string
+	dockerImage  string
+	filter       *task.Filter
+	metrics      *metrics.Collector
+	hook         completionHook
+	context      []string
+	extraArgs    map[string]string
+	systemPrompt string
+	outputFilter *ai.OutputFilter
+	retry        ai.RetryConfig
+	ignoreState  bool
+	noIgnore     bool
+	noApply      bool
+	rebase       bool
+	shard        *scheduler.Shard
+	shardLock    scheduler.ShardLock
+	healthRetry  int
+	tuiPipe      string
+	session      string
+}
+
 // NewRunCmd creates the run subcommand
 func NewRunCmd() *cobra.Command {
 	cmd := &cobra.Command{
@@ -29,28 +127,117 @@ func NewRunCmd() *cobra.Command {
 		Long:  "Execute tasks from task files using Claude CLI",
 		Example: `  hermes run
   hermes run --auto-branch --auto-commit
+  hermes run --auto-commit --git-push --git-push-tags --remote upstream
   hermes run --autonomous=false
+  hermes run --resume
+  hermes run --reset-in-progress
+  hermes run --filter feature=F001
+  hermes run --token-budget 2000000:200000 --cost-budget 10
+  hermes run --max-loops 20
+  hermes run --fail-fast --max-stall-loops 5
+  hermes run --checkpoint-interval 10
+  hermes run --exclude feature=F003
+  hermes run --scope services/auth --scope libs/crypto
+  hermes run --watch-files "src/**/*.go" --filter feature=F002
+  hermes run --metrics-port 9090
+  hermes run --on-complete ./scripts/deploy.sh
+  hermes run --notify slack:https://hooks.slack.com/services/T000/B000/XXXX --notify-only-failures
+  hermes run --context-file docs/adr/001-architecture.md --context-file STYLE.md
+  hermes run --ai-args model=claude-opus-4-5 --ai-args verbose
+  hermes run --ai-system-prompt .hermes/SYSTEM.md
+  hermes run --output-dir /tmp/hermes-out --no-apply
+  hermes run --skip-tests --filter feature=F004
+  hermes run --dry-run-ai --ai gemini
+  hermes run --profile cpu --trace
   hermes run --parallel --workers 3
-  hermes run --parallel --dry-run`,
+  hermes run --strategy auto --workers 4
+  hermes run --parallel --isolation docker
+  hermes run --parallel --dry-run
+  hermes run --parallel --rebase-before-merge
+  hermes run --parallel --tui-pipe /tmp/hermes.pipe
+  hermes run --parallel --shard 1/3 --shard-lock-url redis://ci-redis:6379/0
+  hermes run --estimate-cost --filter feature=F002
+  hermes run --parallel --cost-confirm 5`,
 		RunE: runExecute,
 	}
 
 	cmd.Flags().Bool("auto-branch", false, "Create feature branches (overrides config)")
 	cmd.Flags().Bool("auto-commit", false, "Commit on task completion (overrides config)")
+	cmd.Flags().Bool("git-push", false, "Push the branch to the remote after each completed task (the feature branch with --auto-branch)")
+	cmd.Flags().Bool("git-push-tags", false, "Push the version tags created for completed features")
+	cmd.Flags().String("remote", git.DefaultRemote, "Remote for --git-push and --git-push-tags")
 	cmd.Flags().Bool("autonomous", true, "Run without pausing (overrides config)")
 	cmd.Flags().Int("timeout", 0, "AI timeout in seconds (0 = use config)")
 	cmd.Flags().Bool("debug", false, "Enable debug output")
 	cmd.Flags().String("ai", "", "AI provider: claude, droid, gemini, auto (default: from config or auto)")
+	cmd.Flags().StringArray("ai-args", nil, "Pass a flag to the AI CLI: key=value for --key value, or key for --key (repeatable, added to config ai.extraArgs)")
+	cmd.Flags().String("ai-system-prompt", "", "Pass this file's content to the AI as the system prompt (claude, gemini)")
+	cmd.Flags().Bool("allow-unsafe-args", false, "Allow --ai-args that disable the AI CLI's safety checks")
+	cmd.Flags().StringArray("output-filter", nil, "Drop AI output lines matching this regex before analysis and logging (repeatable)")
+	cmd.Flags().Int("output-max-lines", 0, "Keep only the last N lines of AI output (0 = no limit)")
+	cmd.Flags().String("output-dir", "", "Run the AI in this directory, seeded with the task's files to touch, and apply its changes to the project with git apply")
+	cmd.Flags().Int("context-window-budget", 0, "Trim each prompt to about this many tokens, keeping its first lines and the task section (0 = no limit)")
+	cmd.Flags().Bool("skip-tests", false, "Tell the AI not to run tests; completions count as lower confidence")
+	cmd.Flags().String("profile", "", "Profile the run and write cpu.pprof or mem.pprof to .hermes/profiles: cpu, mem")
+	cmd.Flags().Bool("trace", false, "Write a Go execution trace of the run to .hermes/profiles/trace.out")
 	// Parallel execution flags
-	cmd.Flags().Bool("parallel", false, "Enable parallel task execution")
-	cmd.Flags().Int("workers", 3, "Number of parallel workers (default: 3)")
+	cmd.Flags().BoolP("parallel", "p", false, "Enable parallel task execution (same as --strategy parallel)")
+	cmd.Flags().String("strategy", "", "Execution strategy: sequential, parallel, auto (default: sequential, or parallel if enabled in config)")
+	cmd.Flags().Int("workers", 3, "Number of parallel workers for the parallel and auto strategies (default: 3)")
 	cmd.Flags().Bool("dry-run", false, "Show execution plan without running")
+	cmd.Flags().Bool("dry-run-ai", false, "Print the AI command and prompt for the next task and exit without calling the AI")
+	cmd.Flags().String("isolation", "", "Parallel workspace isolation: none, worktree, docker, copy (default: from config or worktree)")
+	cmd.Flags().String("docker-image", isolation.DefaultDockerImage, "Image for --isolation docker (must contain git and the AI CLI)")
+	cmd.Flags().Bool("no-tui", false, "Disable the live parallel TUI and print plain output")
+	cmd.Flags().Bool("ignore-state", false, "Discard the parallel graph state of an interrupted run and run its completed tasks again")
+	cmd.Flags().Bool("no-ignore", false, "Check all files for conflicts, ignoring .hermesignore")
+	cmd.Flags().Bool("no-apply", false, "Only check that parallel task changes apply, keeping task branches unmerged; with --output-dir, keep the changes in the output dir")
+	cmd.Flags().Bool("rebase-before-merge", false, "Rebase each parallel task branch onto the base branch before merging it (worktree isolation)")
+	cmd.Flags().String("tui-pipe", "", "Stream parallel progress as JSON events to this named pipe for 'hermes tui --attach'")
+	cmd.Flags().Int("health-retry-limit", scheduler.DefaultHealthRetryLimit, "Times a parallel task is re-queued while a worker health check fails")
+	cmd.Flags().String("shard", "", "Run only shard N of M of the tasks, for distributed parallel execution: N/M")
+	cmd.Flags().String("shard-lock-url", "", "Redis URL used to claim each task so no two machines run it: redis://host:port/db")
+	cmd.Flags().Bool("estimate-cost", false, "Print the estimated API cost of the pending tasks and exit")
+	cmd.Flags().Float64("cost-confirm", 0, "Ask before running if the estimated API cost exceeds this many USD (0 = never ask)")
+	cmd.Flags().Float64("cost-limit", 0, "Maximum API cost in USD for parallel execution (0 = use config)")
+	cmd.Flags().Int("max-log-size-mb", 0, "Rotate the logs before running if hermes.log is larger than this (default: log.rotation.maxSizeMB, 0 = never)")
+	cmd.Flags().String("log-format", "", "Log output format: text, json (default: from config or text)")
+	cmd.Flags().Bool("no-webhooks", false, "Disable webhook notifications")
+	cmd.Flags().String("notify", "", "Send Slack notifications: slack:<webhook-url> (saved to the project config)")
+	cmd.Flags().Bool("notify-only-failures", false, "Only notify when the circuit breaker opens or goes half-open")
+	cmd.Flags().Bool("test-notify", false, "Send a test notification to the configured webhooks and exit")
+	cmd.Flags().Float64("retry-backoff", 1, "Multiply the error delay by this factor after each consecutive failure (1 = fixed delay); also the back-off of transient provider errors")
+	cmd.Flags().Duration("retry-max-delay", 0, "Maximum delay between retries after failures (0 = no cap); also caps the back-off of transient provider errors")
+	cmd.Flags().Bool("resume", false, "Continue the IN_PROGRESS task left by an interrupted run")
+	cmd.Flags().Bool("reset-in-progress", false, "Reset IN_PROGRESS tasks to NOT_STARTED before starting")
+	cmd.Flags().String("token-budget", "", "Stop after this many tokens: N for input and output, or IN:OUT")
+	cmd.Flags().Float64("cost-budget", 0, "Stop after this much API cost in USD (0 = no limit)")
+	cmd.Flags().Int("max-loops", 0, "Stop after this many loops (0 = use config, unlimited by default)")
+	cmd.Flags().Bool("fail-fast", false, "Stop the run when the AI fails on a task (exit code 1) or a task stalls (exit code 2)")
+	cmd.Flags().Int("max-stall-loops", 3, "With --fail-fast, loops a task may run without progress before it counts as stalled")
+	cmd.Flags().Int("checkpoint-interval", 0, "Commit and tag the work every N loops as hermes-checkpoint-<loop> (0 = disabled)")
+	cmd.Flags().String("seed-prompt", "", "Create .hermes/PROMPT.md from this file or http(s) URL if it does not exist")
+	cmd.Flags().Bool("force", false, "With --seed-prompt, replace an existing .hermes/PROMPT.md")
+	cmd.Flags().StringArray("filter", nil, "Only run matching tasks: feature=<id> or task=<id> (repeatable)")
+	cmd.Flags().StringArray("scope", nil, "Only run tasks with a file to touch under this path (repeatable, any path matches)")
+	cmd.Flags().Bool("scope-strict", false, "Fail instead of skipping pending tasks outside --scope")
+	cmd.Flags().StringArray("exclude", nil, "Skip a feature's tasks: feature=<id> (repeatable)")
+	cmd.Flags().String("watch-files", "", "After all tasks are done, wait for changes to files matching this glob and restart the loop")
+	cmd.Flags().Duration("watch-debounce", defaultWatchDebounce, "Wait this long after the last file change before restarting")
+	cmd.Flags().Int("metrics-port", 0, "Serve Prometheus metrics on this port at /metrics (0 = disabled)")
+	cmd.Flags().String("on-complete", "", "Shell command to run after each completed task (overrides config)")
+	cmd.Flags().Int("on-complete-timeout", 30, "Timeout for the on-complete command in seconds")
+	cmd.Flags().StringArray("context-file", nil, "Add this file's content to every task prompt (repeatable, added to config contextFiles)")
 
 	return cmd
 }
 
 func runExecute(cmd *cobra.Command, args []string) error {
-	ctx, cancel := context.WithCancel(context.Background())
+	parent := cmd.Context()
+	if parent == nil {
+		parent = context.Background()
+	}
+	ctx, cancel := context.WithCancel(parent)
 	defer cancel()
 
 	// Handle Ctrl+C
@@ -87,33 +274,168 @@ func runExecute(cmd *cobra.Command, args []string) error {
 		debug, _ = cmd.Flags().GetBool("debug")
 	}
 
+	// Rotate a large log before the logger opens it
+	maxLogSize := cfg.Log.Rotation.MaxSizeMB
+	if cmd.Flags().Changed("max-log-size-mb") {
+		maxLogSize, _ = cmd.Flags().GetInt("max-log-size-mb")
+	}
+	rotated, rotateErr := rotateLogsIfLarge(filepath.Join(".hermes", "logs"), maxLogSize, cfg.Log.Rotation)
+
 	// Initialize logger
 	logger, err := ui.NewLogger(".", debug)
 	if err != nil {
 		return err
 	}
 	defer logger.Close()
+	if rotateErr != nil {
+		logger.Warn("Failed to rotate logs: %v", rotateErr)
+	} else if rotated != nil && rotated.Archive != "" {
+		logger.Info("hermes.log exceeded %d MB, rotated logs to %s", maxLogSize, rotated.Archive)
+	}
+
+	logFormat := cfg.LogFormat
+	if cmd.Flags().Changed("log-format") {
+		logFormat, _ = cmd.Flags().GetString("log-format")
+	}
+	if err := logger.SetFormat(logFormat); err != nil {
+		return err
+	}
+
+	// Profile the whole run, including task parsing and graph construction
+	profileMode, _ := cmd.Flags().GetString("profile")
+	withTrace, _ := cmd.Flags().GetBool("trace")
+	if profileMode != "" || withTrace {
+		profiler, err := startProfiling(profileDir("."), profileMode, withTrace)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			files, err := profiler.stop()
+			for _, file := range files {
+				if filepath.Base(file) == traceProfileFile {
+					logger.Info("Wrote execution trace %s (view with 'go tool trace %s')", file, file)
+				} else {
+					logger.Info("Wrote profile %s (analyze with 'hermes profile analyze %s')", file, file)
+				}
+			}
+			if err != nil {
+				logger.Warn("Failed to write profile: %v", err)
+			}
+		}()
+	}
 
 	ui.PrintBanner()
 	ui.PrintHeader("Task Execution Loop")
 
 	// Initialize components
-	reader := task.NewReader(".")
+	reader := newTaskReader(".")
 	breaker := circuit.New(".")
-	gitOps := git.New(".")
+	remote, _ := cmd.Flags().GetString("remote")
+	gitOps := git.New(".", remote)
+	gitPush, _ := cmd.Flags().GetBool("git-push")
+	gitPushTags, _ := cmd.Flags().GetBool("git-push-tags")
 	injector := prompt.NewInjector(".")
 	respAnalyzer := analyzer.NewResponseAnalyzer()
+	if calibrator, err := analyzer.LoadCalibrator(analyzer.CalibrationPath(".")); err != nil {
+		logger.Warn("Ignoring confidence calibration: %v", err)
+	} else if calibrator != nil {
+		respAnalyzer.SetCalibrator(calibrator)
+		logger.Debug("Confidence calibration: %d samples", calibrator.Samples)
+	}
+	if target, _ := cmd.Flags().GetString("notify"); target != "" {
+		slackURL, err := parseNotifyTarget(target)
+		if err != nil {
+			return err
+		}
+		if err := config.SaveSlackWebhook(config.ProjectPath("."), slackURL); err != nil {
+			return fmt.Errorf("failed to save Slack webhook: %w", err)
+		}
+		cfg.Webhooks = config.WithSlackWebhook(cfg.Webhooks, slackURL)
+		logger.Info("Slack notifications enabled and saved to %s", config.ProjectPath("."))
+	}
+	notify := notifier.New(cfg.Webhooks)
+	if noWebhooks, _ := cmd.Flags().GetBool("no-webhooks"); noWebhooks {
+		notify.Disable()
+	}
+	if onlyFailures, _ := cmd.Flags().GetBool("notify-only-failures"); onlyFailures {
+		notify.SetOnlyFailures(true)
+	}
+	if testNotify, _ := cmd.Flags().GetBool("test-notify"); testNotify {
+		if !notify.IsEnabled() {
+			return fmt.Errorf("no webhooks configured (use --notify slack:<webhook-url>)")
+		}
+		if err := notify.SendTest(); err != nil {
+			return err
+		}
+		logger.Success("Test notification sent")
+		return nil
+	}
 
 	// Initialize circuit breaker
 	if err := breaker.Initialize(); err != nil {
 		return err
 	}
 
+	if source, _ := cmd.Flags().GetString("seed-prompt"); source != "" {
+		force, _ := cmd.Flags().GetBool("force")
+		seeded, err := seedPrompt(injector, source, force)
+		if err != nil {
+			return err
+		}
+		if seeded == "" {
+			logger.Info("%s exists, ignoring --seed-prompt (use --force to replace it)", injector.GetPromptPath())
+		} else {
+			logger.Info("Seeded %s from %s", injector.GetPromptPath(), source)
+			if !strings.Contains(seeded, prompt.TaskSectionStart) {
+				logger.Warn("Seed prompt has no %s placeholder for the task section, each task is appended to the end of the prompt", prompt.TaskSectionStart)
+			}
+		}
+	} else if cmd.Flags().Changed("force") {
+		return fmt.Errorf("--force requires --seed-prompt")
+	}
+
 	// Check for tasks
 	if !reader.HasTasks() {
 		return fmt.Errorf("no tasks found, run 'hermes prd <file>' first")
 	}
 
+	includes, _ := cmd.Flags().GetStringArray("filter")
+	excludes, _ := cmd.Flags().GetStringArray("exclude")
+	filter, err := task.ParseFilter(includes, excludes)
+	if err != nil {
+		return err
+	}
+	filter.Scope, _ = cmd.Flags().GetStringArray("scope")
+	if strict, _ := cmd.Flags().GetBool("scope-strict"); strict {
+		if len(filter.Scope) == 0 {
+			return fmt.Errorf("--scope-strict requires --scope")
+		}
+		outside, err := tasksOutOfScope(reader, filter)
+		if err != nil {
+			return err
+		}
+		if len(outside) > 0 {
+			return fmt.Errorf("task(s) %s touch no files under %s (--scope-strict)", strings.Join(outside, ", "), strings.Join(filter.Scope, ", "))
+		}
+	}
+	if !filter.IsEmpty() {
+		reader.SetFilter(filter)
+		logger.Warn("Task filter active (%s): tasks not matching it are skipped", filter)
+	}
+
+	resume, _ := cmd.Flags().GetBool("resume")
+	if resetInProgress, _ := cmd.Flags().GetBool("reset-in-progress"); resetInProgress {
+		reset, err := resetInProgressTasks(".")
+		if err != nil {
+			return err
+		}
+		if len(reset) > 0 {
+			logger.Info("Reset %d IN_PROGRESS task(s) to NOT_STARTED: %v", len(reset), reset)
+		}
+		// Nothing is left in progress, so resuming means picking the next task
+		resume = false
+	}
+
 	// Get AI provider
 	aiFlag, _ := cmd.Flags().GetString("ai")
 	var provider ai.Provider
@@ -142,26 +464,418 @@ func runExecute(cmd *cobra.Command, args []string) error {
 
 	logger.Info("Using AI provider: %s", provider.Name())
 
-	// Check for parallel execution mode
-	parallel, _ := cmd.Flags().GetBool("parallel")
-	dryRun, _ := cmd.Flags().GetBool("dry-run")
-	workers, _ := cmd.Flags().GetInt("workers")
+	aiArgs, _ := cmd.Flags().GetStringArray("ai-args")
+	allowUnsafe, _ := cmd.Flags().GetBool("allow-unsafe-args")
+	extraArgs, err := resolveExtraArgs(cfg.AI.ExtraArgs, aiArgs, allowUnsafe)
+	if err != nil {
+		return err
+	}
+	if len(extraArgs) > 0 {
+		logger.Debug("Extra AI arguments: %v", extraArgs)
+	}
+
+	var systemPrompt string
+	if path, _ := cmd.Flags().GetString("ai-system-prompt"); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read system prompt: %w", err)
+		}
+		systemPrompt = strings.TrimSpace(string(data))
+		switch {
+		case systemPrompt == "":
+			return fmt.Errorf("system prompt file %s is empty", path)
+		case !provider.Capabilities().SupportsSystemPrompt:
+			logger.Warn("%s does not take a system prompt, ignoring --ai-system-prompt", provider.Name())
+			systemPrompt = ""
+		default:
+			logger.Debug("System prompt: %s (%d characters)", path, len(systemPrompt))
+		}
+	}
+
+	filterPatterns, _ := cmd.Flags().GetStringArray("output-filter")
+	maxOutputLines, _ := cmd.Flags().GetInt("output-max-lines")
+	outputFilter, err := ai.NewOutputFilter(filterPatterns, maxOutputLines)
+	if err != nil {
+		return err
+	}
+	if !outputFilter.IsEmpty() {
+		logger.Debug("Output filter: %s", outputFilter)
+	}
 
-	// Override with config if flag not set
-	if !cmd.Flags().Changed("parallel") {
-		parallel = cfg.Parallel.Enabled
+	// Serve Prometheus metrics
+	collector := metrics.New(breaker)
+	if metricsPort, _ := cmd.Flags().GetInt("metrics-port"); metricsPort > 0 {
+		server, err := metrics.Serve(metricsPort, collector)
+		if err != nil {
+			return err
+		}
+		defer server.Close()
+		logger.Info("Serving metrics at %s", server.URL())
 	}
+
+	// Choose the execution strategy
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	dryRunAI, _ := cmd.Flags().GetBool("dry-run-ai")
+	workers, _ := cmd.Flags().GetInt("workers")
 	if !cmd.Flags().Changed("workers") {
 		workers = cfg.Parallel.MaxWorkers
 	}
+	strategy, requestedStrategy, err := resolveStrategy(cmd, cfg, reader, filter)
+	if err != nil {
+		return err
+	}
+	if requestedStrategy == scheduler.StrategyAuto {
+		logger.Info("Strategy auto: using %s execution", strategy)
+	}
+	if strategy == scheduler.StrategySequential && cmd.Flags().Changed("workers") {
+		logger.Warn("--workers only applies to the parallel and auto strategies, ignoring it")
+	}
+	parallel := strategy == scheduler.StrategyParallel
+	if cmd.Flags().Changed("cost-limit") {
+		cfg.Parallel.MaxCostPerHour, _ = cmd.Flags().GetFloat64("cost-limit")
+	}
+
+	budget, err := parseBudgetFlags(cmd)
+	if err != nil {
+		return err
+	}
+
+	checkpointInterval, _ := cmd.Flags().GetInt("checkpoint-interval")
+	failFast, _ := cmd.Flags().GetBool("fail-fast")
+	maxStallLoops, _ := cmd.Flags().GetInt("max-stall-loops")
+	contextBudget, _ := cmd.Flags().GetInt("context-window-budget")
+	if contextBudget < 0 {
+		return fmt.Errorf("--context-window-budget must not be negative")
+	}
+	if maxStallLoops < 1 {
+		return fmt.Errorf("--max-stall-loops must be at least 1")
+	}
+	if checkpointInterval < 0 {
+		return fmt.Errorf("--checkpoint-interval must not be negative")
+	}
+	if checkpointInterval > 0 && !gitOps.IsRepository() {
+		logger.Warn("Not a git repository, --checkpoint-interval is ignored")
+		checkpointInterval = 0
+	}
+	if (gitPush || gitPushTags) && !gitOps.IsRepository() {
+		logger.Warn("Not a git repository, --git-push and --git-push-tags are ignored")
+		gitPush, gitPushTags = false, false
+	}
+
+	hook := completionHook{
+		command: cfg.Hooks.OnTaskComplete,
+		timeout: time.Duration(cfg.Hooks.Timeout) * time.Second,
+	}
+	if cmd.Flags().Changed("on-complete") {
+		hook.command, _ = cmd.Flags().GetString("on-complete")
+	}
+	if cmd.Flags().Changed("on-complete-timeout") {
+		seconds, _ := cmd.Flags().GetInt("on-complete-timeout")
+		hook.timeout = time.Duration(seconds) * time.Second
+	}
+
+	contextFiles, _ := cmd.Flags().GetStringArray("context-file")
+	promptContext, err := readContextFiles(append(cfg.ContextFiles, contextFiles...))
+	if err != nil {
+		return err
+	}
+
+	// Estimate the cost before anything runs
+	estimateCost, _ := cmd.Flags().GetBool("estimate-cost")
+	costConfirm, _ := cmd.Flags().GetFloat64("cost-confirm")
+	if costConfirm < 0 {
+		return fmt.Errorf("--cost-confirm must not be negative")
+	}
+	if estimateCost || (costConfirm > 0 && !dryRun && !dryRunAI) {
+		tasks, err := schedulableTasks(reader, filter)
+		if err != nil {
+			return err
+		}
+		taskPtrs := make([]*task.Task, len(tasks))
+		for i := range tasks {
+			taskPtrs[i] = &tasks[i]
+		}
+		estimate, err := scheduler.New(&cfg.Parallel, provider, ".", logger).EstimateCost(taskPtrs)
+		if err != nil {
+			return fmt.Errorf("failed to estimate cost: %w", err)
+		}
+		if estimateCost {
+			fmt.Printf("Estimated cost: $%.2f (%s, %d tokens per task minute)\n", estimate, provider.Name(), cfg.Parallel.TokensPerMinute)
+			return nil
+		}
+		if estimate > costConfirm && !confirmCost(estimate, costConfirm, bufio.NewReader(os.Stdin), os.Stdout) {
+			logger.Info("Cancelled")
+			return nil
+		}
+	}
+
+	// The session ID is recorded with the task status changes of this run,
+	// for 'hermes status --changed-by'
+	var sessionID string
+	if !dryRun && !dryRunAI {
+		if sessionID, err = breaker.StartSession(); err != nil {
+			logger.Warn("Failed to start run session: %v", err)
+		} else {
+			logger.Info("Run session: %s", sessionID)
+		}
+
+		info := scheduler.RunInfo{Strategy: strategy, Requested: requestedStrategy, StartedAt: time.Now()}
+		if parallel {
+			info.Workers = workers
+		}
+		if err := info.Save(scheduler.RunInfoPath(".")); err != nil {
+			logger.Warn("Failed to save run info: %v", err)
+		}
+		logger.Info("Execution strategy: %s", strategy)
+	}
 
 	// Handle parallel execution
 	if parallel || dryRun {
-		return runParallel(ctx, cfg, provider, reader, logger, workers, dryRun)
+		if !budget.IsZero() {
+			return fmt.Errorf("--token-budget and --cost-budget are not supported with --parallel (use --cost-limit)")
+		}
+		if resume {
+			return fmt.Errorf("--resume is not supported with --parallel (use --reset-in-progress)")
+		}
+		if checkpointInterval > 0 {
+			return fmt.Errorf("--checkpoint-interval is not supported with --parallel")
+		}
+		if gitPush || gitPushTags {
+			return fmt.Errorf("--git-push and --git-push-tags are not supported with --parallel")
+		}
+		if cmd.Flags().Changed("watch-files") {
+			return fmt.Errorf("--watch-files is not supported with --parallel")
+		}
+		if cmd.Flags().Changed("max-stall-loops") {
+			return fmt.Errorf("--max-stall-loops is not supported with --parallel")
+		}
+		if cmd.Flags().Changed("context-window-budget") {
+			return fmt.Errorf("--context-window-budget is not supported with --parallel")
+		}
+		if cmd.Flags().Changed("output-dir") {
+			return fmt.Errorf("--output-dir is not supported with --parallel (use --isolation copy)")
+		}
+		if cmd.Flags().Changed("skip-tests") {
+			return fmt.Errorf("--skip-tests is not supported with --parallel")
+		}
+		if dryRunAI {
+			return fmt.Errorf("--dry-run-ai is not supported with --parallel or --dry-run")
+		}
+		if failFast {
+			cfg.Parallel.FailureStrategy = "fail-fast"
+		}
+		noTUI, _ := cmd.Flags().GetBool("no-tui")
+		ignoreState, _ := cmd.Flags().GetBool("ignore-state")
+		noIgnore, _ := cmd.Flags().GetBool("no-ignore")
+		noApply, _ := cmd.Flags().GetBool("no-apply")
+		isolationMode := cfg.Parallel.IsolationMode
+		if cmd.Flags().Changed("isolation") {
+			isolationMode, _ = cmd.Flags().GetString("isolation")
+		}
+		dockerImage, _ := cmd.Flags().GetString("docker-image")
+		switch isolationMode {
+		case "":
+			isolationMode = isolation.ModeWorktree
+		case isolation.ModeWorktree, isolation.ModeCopy:
+		case isolation.ModeNone:
+			logger.Warn("Isolation mode none: parallel tasks share the project directory and may overwrite each other's files")
+		case isolation.ModeDocker:
+			if !dryRun && !isolation.DockerAvailable() {
+				logger.Warn("Docker is not available, falling back to git worktree isolation")
+				isolationMode = isolation.ModeWorktree
+			}
+		default:
+			return fmt.Errorf("unknown isolation mode: %s (use %s)", isolationMode, strings.Join(isolation.Modes, ", "))
+		}
+		if noApply && isolationMode == isolation.ModeNone {
+			return fmt.Errorf("--no-apply requires isolated workspaces (use --isolation worktree, docker or copy)")
+		}
+		rebase, _ := cmd.Flags().GetBool("rebase-before-merge")
+		if rebase && isolationMode != isolation.ModeWorktree {
+			return fmt.Errorf("--rebase-before-merge requires task branches (use --isolation worktree)")
+		}
+		tuiPipe, _ := cmd.Flags().GetString("tui-pipe")
+		healthRetry, _ := cmd.Flags().GetInt("health-retry-limit")
+		if healthRetry < 0 {
+			return fmt.Errorf("--health-retry-limit must not be negative")
+		}
+		var shard *scheduler.Shard
+		if value, _ := cmd.Flags().GetString("shard"); value != "" {
+			if shard, err = scheduler.ParseShard(value); err != nil {
+				return err
+			}
+		}
+		var shardLock scheduler.ShardLock
+		if lockURL, _ := cmd.Flags().GetString("shard-lock-url"); lockURL != "" {
+			if shardLock, err = scheduler.NewRedisLock(lockURL); err != nil {
+				return err
+			}
+		}
+		return runParallel(ctx, cfg, provider, reader, logger, parallelOptions{
+			filter:       filter,
+			metrics:      collector,
+			hook:         hook,
+			context:      promptContext,
+			extraArgs:    extraArgs,
+			systemPrompt: systemPrompt,
+			outputFilter: outputFilter,
+			retry:        taskRetryConfig(cmd, cfg.Parallel.MaxRetries),
+			workers:      workers,
+			dryRun:       dryRun,
+			autoCommit:   autoCommit,
+			showTUI:      !noTUI && logFormat != ui.LogFormatJSON && isTerminal(os.Stdout),
+			isolation:    isolationMode,
+			dockerImage:  dockerImage,
+			ignoreState:  ignoreState,
+			noIgnore:     noIgnore,
+			noApply:      noApply,
+			rebase:       rebase,
+			shard:        shard,
+			shardLock:    shardLock,
+			healthRetry:  healthRetry,
+			tuiPipe:      tuiPipe,
+			session:      sessionID,
+		})
+	}
+	if cmd.Flags().Changed("shard") || cmd.Flags().Changed("shard-lock-url") {
+		return fmt.Errorf("--shard and --shard-lock-url require --parallel")
+	}
+	if cmd.Flags().Changed("rebase-before-merge") {
+		return fmt.Errorf("--rebase-before-merge requires --parallel")
+	}
+	if cmd.Flags().Changed("tui-pipe") {
+		return fmt.Errorf("--tui-pipe requires --parallel")
+	}
+
+	var outDir *outputDir
+	noApply, _ := cmd.Flags().GetBool("no-apply")
+	if path, _ := cmd.Flags().GetString("output-dir"); path != "" {
+		if sameDir(path, ".") {
+			return fmt.Errorf("--output-dir must not be the project directory")
+		}
+		if outDir, err = newOutputDir(path); err != nil {
+			return err
+		}
+		if noApply {
+			logger.Info("AI works in %s, changes are kept there (--no-apply)", outDir.path)
+		} else {
+			logger.Info("AI works in %s, changes are applied to the project after each loop", outDir.path)
+		}
+	} else if noApply {
+		return fmt.Errorf("--no-apply requires --parallel or --output-dir")
 	}
 
 	// Sequential execution (original behavior)
+	retryBackoff, _ := cmd.Flags().GetFloat64("retry-backoff")
+	retryMaxDelay, _ := cmd.Flags().GetDuration("retry-max-delay")
+	errorRetry := &ai.RetryConfig{
+		Delay:             time.Duration(cfg.Loop.ErrorDelay) * time.Second,
+		BackoffMultiplier: retryBackoff,
+		MaxDelay:          retryMaxDelay,
+		Jitter:            retryBackoff > 1,
+	}
+	// Transient provider errors are retried within a loop before they count
+	// as a loop error
+	taskRetry := taskRetryConfig(cmd, cfg.AI.MaxRetries)
+	consecutiveErrors := 0
+
+	for _, content := range promptContext {
+		injector.AddContext(content)
+	}
+	skipTests, _ := cmd.Flags().GetBool("skip-tests")
+	injector.SetSkipTests(skipTests)
+
+	// One executor for the whole run so the budget covers every loop
+	executor := ai.NewTaskExecutor(provider, ".")
+	executor.SetBudget(budget)
+	executor.SetExtraArgs(extraArgs)
+	executor.SetSystemPrompt(systemPrompt)
+	executor.SetOutputFilter(outputFilter)
+	executor.SetStreamWatcher(func(events <-chan ai.StreamEvent) bool {
+		// Stop the AI once its status block has streamed
+		return respAnalyzer.AnalyzeStream(events).StoppedEarly
+	})
+	if outDir != nil {
+		executor.SetWorkDir(outDir.path)
+	}
+	usagePath := ai.TokenUsagePath(".")
+	previousUsage, err := ai.LoadTokenUsage(usagePath)
+	if err != nil {
+		logger.Warn("Failed to load token usage: %v", err)
+		previousUsage = &ai.TokenUsage{}
+	}
+	budgetWarned := false
+	var budgetErr error
+
+	maxLoops := cfg.Loop.MaxLoops
+	if cmd.Flags().Changed("max-loops") {
+		maxLoops, _ = cmd.Flags().GetInt("max-loops")
+	}
+	loopsRun := 0
+	stalls := &stallTracker{max: maxStallLoops}
+
+	var watcher *fileWatcher
+	watchPattern, _ := cmd.Flags().GetString("watch-files")
+	if watchPattern != "" {
+		debounce, _ := cmd.Flags().GetDuration("watch-debounce")
+		watcher, err = newFileWatcher(".", watchPattern, debounce, watchedTaskFiles(reader, filter))
+		if err != nil {
+			return err
+		}
+		defer watcher.Close()
+	}
+
+	// waitForRestart waits for a watched file to change once all tasks are
+	// done; the loops of the restarted run count from zero again
+	waitForRestart := func() error {
+		logger.Info("Waiting for changes to files matching %s (Ctrl+C to stop)", watchPattern)
+		path, err := watcher.WaitForChange(ctx)
+		if err != nil {
+			return err
+		}
+		logger.Info("%s changed, restarting the loop", path)
+		watcher.Drain()
+		loopsRun = 0
+		return nil
+	}
+
 	loopNumber := 0
+	var resumeTask *task.Task
+	if resume {
+		resumeTask, loopNumber, err = findResumePoint(".", breaker)
+		if err != nil {
+			return err
+		}
+		if resumeTask != nil && !filter.Match(*resumeTask) {
+			logger.Warn("IN_PROGRESS task %s does not match the filter, not resuming it", resumeTask.ID)
+			resumeTask = nil
+		}
+		if resumeTask == nil {
+			logger.Info("No IN_PROGRESS task to resume, continuing with the next task")
+		}
+	}
+
+	// Show the AI call for the next task without making it or changing
+	// the prompt file
+	if dryRunAI {
+		nextTask := resumeTask
+		if nextTask == nil {
+			if nextTask, err = reader.GetNextTask(); err != nil {
+				return err
+			}
+		}
+		if nextTask == nil {
+			logger.Info("No pending tasks")
+			return nil
+		}
+		promptContent := injector.TaskPrompt(nextTask)
+		if contextBudget > 0 {
+			promptContent = trimPrompt(promptContent, contextBudget, logger)
+		}
+		printDryRunAI(os.Stdout, provider, nextTask, executor.TaskOptions(nextTask, promptContent, cfg.AI.StreamOutput))
+		return nil
+	}
+
 	for {
 		select {
 		case <-ctx.Done():
@@ -169,7 +883,32 @@ func runExecute(cmd *cobra.Command, args []string) error {
 		default:
 		}
 
+		if budgetErr != nil {
+			logger.Error("%v", budgetErr)
+			return budgetErr
+		}
+
+		// Hard cap on loops in this run, regardless of the circuit breaker
+		if maxLoops > 0 && loopsRun >= maxLoops {
+			if remaining, err := reader.GetNextTask(); err == nil && remaining == nil {
+				logger.Success("All tasks completed!")
+				if watcher == nil {
+					return nil
+				}
+				if err := waitForRestart(); err != nil {
+					return err
+				}
+				continue
+			}
+			err := fmt.Errorf("reached the maximum of %d loops with tasks remaining", maxLoops)
+			logger.Error("%v", err)
+			return err
+		}
+		loopsRun++
+
 		loopNumber++
+		logger.SetLoop(loopNumber)
+		collector.IncLoops()
 		ui.PrintLoopHeader(loopNumber)
 
 		// Check circuit breaker
@@ -182,23 +921,60 @@ func runExecute(cmd *cobra.Command, args []string) error {
 			return nil
 		}
 
-		// Get next task
-		nextTask, err := reader.GetNextTask()
-		if err != nil {
-			return err
+		// Get next task, starting with the interrupted one when resuming
+		nextTask, resumed := resumeTask, resumeTask != nil
+		resumeTask = nil
+		if !resumed {
+			nextTask, err = reader.GetNextTask()
+			if err != nil {
+				return err
+			}
 		}
 		if nextTask == nil {
-			logger.Success("All tasks completed!")
-			return nil
+			logger.SetTaskContext("")
+			if !filter.IsEmpty() {
+				logger.Success("All tasks matching the filter completed!")
+			} else {
+				logger.Success("All tasks completed!")
+			}
+			if watcher == nil {
+				return nil
+			}
+			if err := waitForRestart(); err != nil {
+				return err
+			}
+			continue
 		}
 
-		ui.PrintTaskHeader(nextTask)
-		logger.Info("Working on task: %s - %s", nextTask.ID, nextTask.Name)
+		logger.SetTaskContext(nextTask.ID)
 
-		// Set task status to IN_PROGRESS before starting
+		// Give complex tasks more loops without progress before halting
+		effortDays := 0.0
+		if cfg.Circuit.AdaptiveMode {
+			effortDays = reader.EffortUnits().Days(nextTask.EstimatedEffort)
+		}
+		if err := breaker.AdaptThresholds(effortDays); err != nil {
+			logger.Warn("Failed to adapt circuit breaker thresholds: %v", err)
+		} else if effortDays > 1 {
+			halfOpen, open := circuit.GetEffectiveThresholds(effortDays)
+			logger.Debug("Circuit breaker thresholds for %.1f day(s) of effort: %d half-open, %d open", effortDays, halfOpen, open)
+		}
+
+		ui.PrintTaskHeader(nextTask)
 		statusUpdater := task.NewStatusUpdater(".")
-		if err := statusUpdater.UpdateTaskStatus(nextTask.ID, task.StatusInProgress); err != nil {
-			logger.Warn("Failed to set task IN_PROGRESS: %v", err)
+		statusUpdater.SetSession(sessionID)
+		if resumed {
+			// Already IN_PROGRESS from the interrupted run
+			logger.Info("Resuming task: %s - %s", nextTask.ID, nextTask.Name)
+		} else {
+			logger.Info("Working on task: %s - %s", nextTask.ID, nextTask.Name)
+
+			// Set task status to IN_PROGRESS before starting
+			if err := statusUpdater.UpdateTaskStatus(nextTask.ID, task.StatusInProgress); err != nil {
+				logger.Warn("Failed to set task IN_PROGRESS: %v", err)
+			} else {
+				notifyTask(notify, reader, logger, notifier.EventTaskStarted, nextTask)
+			}
 		}
 
 		// Handle branching
@@ -216,51 +992,142 @@ func runExecute(cmd *cobra.Command, args []string) error {
 		if err := injector.AddTask(nextTask); err != nil {
 			logger.Warn("Failed to inject task: %v", err)
 		}
-		promptContent, _ := injector.Read()
+		promptContent, _ := injector.Read(nextTask.FeatureID)
+		if contextBudget > 0 {
+			promptContent = trimPrompt(promptContent, contextBudget, logger)
+		}
+		if tokens, over := provider.Capabilities().ExceedsContext(promptContent); over {
+			logger.Warn("Prompt is about %d tokens, more than the %d token context of %s",
+				tokens, provider.Capabilities().MaxContextTokens, provider.Name())
+		}
+
+		if outDir != nil {
+			seeded, missing, err := outDir.seed(".", nextTask)
+			if err != nil {
+				logger.Warn("%v", err)
+			} else if len(seeded) > 0 {
+				logger.Debug("Copied to the output dir: %v", seeded)
+			}
+			if len(missing) > 0 {
+				logger.Warn("Files to touch not found in the project, not copied to the output dir (fine for new files): %s", strings.Join(missing, ", "))
+			}
+		}
 
 		// Execute AI
-		executor := ai.NewTaskExecutor(provider, ".")
-		result, err := executor.ExecuteTask(ctx, nextTask, promptContent, cfg.AI.StreamOutput)
+		result, err := executor.ExecuteTaskWithRetry(ctx, nextTask, promptContent, cfg.AI.StreamOutput, taskRetry)
+		if result != nil {
+			collector.AddTokens(provider.Name(), result.TokensIn+result.TokensOut)
+		}
+		if outDir != nil {
+			if applyErr := applyOutputDir(outDir, noApply, logger); applyErr != nil {
+				logger.Error("%v", applyErr)
+				return applyErr
+			}
+		}
+
+		if saveErr := previousUsage.Plus(executor.Usage()).Save(usagePath); saveErr != nil {
+			logger.Warn("Failed to save token usage: %v", saveErr)
+		}
+		if errors.Is(err, ai.ErrBudgetExceeded) {
+			if result == nil {
+				logger.Error("%v", err)
+				return err
+			}
+			// Finish this loop's work, then stop before the next task
+			budgetErr, err = err, nil
+			logger.Warn("%v, stopping after this loop", budgetErr)
+		}
+		if !budget.IsZero() && !budgetWarned && executor.BudgetUtilisation() >= ai.BudgetWarnThreshold {
+			budgetWarned = true
+			logger.Warn("Token budget %.0f%% used", executor.BudgetUtilisation()*100)
+		}
 
 		if err != nil {
 			logger.Error("AI execution failed: %v", err)
-			breaker.AddLoopResult(false, true, loopNumber)
+			addLoopResult(breaker, notify, reader, logger, false, true, loopNumber, nextTask)
+			createCheckpoint(gitOps, breaker, logger, checkpointInterval, loopNumber, nextTask.ID)
+			if failFast {
+				return &ExitError{Code: exitFailed, Err: fmt.Errorf("task %s failed: %w", nextTask.ID, err)}
+			}
 
-			// Wait before retry
-			time.Sleep(time.Duration(cfg.Loop.ErrorDelay) * time.Second)
+			// Wait before retry, backing off on consecutive failures
+			time.Sleep(errorRetry.DelayFor(consecutiveErrors))
+			consecutiveErrors++
 			continue
 		}
+		consecutiveErrors = 0
 
 		// Analyze response
 		analysis := respAnalyzer.Analyze(result.Output)
+		if skipTests {
+			analysis.MarkSkipTests()
+		}
 		logger.Debug("Analysis: progress=%v complete=%v confidence=%.2f",
 			analysis.HasProgress, analysis.IsComplete, analysis.Confidence)
+		if err := analyzer.AppendPrediction(analyzer.AnalyticsPath("."), analyzer.Prediction{
+			TaskID:     nextTask.ID,
+			Loop:       loopNumber,
+			Confidence: analysis.RawConfidence,
+			IsComplete: analysis.IsComplete,
+			Timestamp:  time.Now(),
+		}); err != nil {
+			logger.Warn("Failed to record prediction: %v", err)
+		}
 
 		// Update circuit breaker
-		breaker.AddLoopResult(analysis.HasProgress, false, loopNumber)
+		addLoopResult(breaker, notify, reader, logger, analysis.HasProgress, false, loopNumber, nextTask)
+
+		// Stop on a stalled task before the circuit breaker halts the run
+		if failFast && !analysis.IsComplete && stalls.record(nextTask.ID, analysis.HasProgress) {
+			createCheckpoint(gitOps, breaker, logger, checkpointInterval, loopNumber, nextTask.ID)
+			err := fmt.Errorf("task %s stalled: no progress in %d consecutive loops", nextTask.ID, maxStallLoops)
+			logger.Error("%v", err)
+			return &ExitError{Code: exitStalled, Err: err}
+		}
 
 		// Update task status if complete
 		if analysis.IsComplete {
 			// Remove task from prompt
-			injector.RemoveTask()
+			injector.RemoveTask(nextTask.FeatureID)
 
 			// Set task status to COMPLETED before commit
 			if err := statusUpdater.UpdateTaskStatus(nextTask.ID, task.StatusCompleted); err != nil {
 				logger.Warn("Failed to update task status: %v", err)
+			} else {
+				notifyTask(notify, reader, logger, notifier.EventTaskCompleted, nextTask)
 			}
+			collector.IncTasksCompleted(nextTask.FeatureID)
 
 			// Auto-commit (includes the status update)
 			if autoCommit && gitOps.HasUncommittedChanges() {
-				if err := gitOps.StageAll(); err == nil {
-					if err := gitOps.CommitTask(nextTask.ID, nextTask.Name); err != nil {
-						logger.Warn("Failed to commit: %v", err)
-					} else {
-						logger.Success("Committed task %s", nextTask.ID)
+				err := gitOps.WithLock(func(g *git.Git) error {
+					if err := g.StageAll(); err != nil {
+						return err
+					}
+					return g.CommitTask(nextTask.ID, nextTask.Name)
+				})
+				if err != nil {
+					logger.Warn("Failed to commit: %v", err)
+				} else {
+					logger.Success("Committed task %s", nextTask.ID)
+					if err := gitOps.RecordLastCommit(nextTask.ID); err != nil {
+						logger.Warn("Failed to record commit: %v", err)
 					}
 				}
 			}
 
+			if gitPush {
+				pushTaskBranch(gitOps, reader, logger, autoBranch, nextTask)
+			}
+
 			logger.Success("Task %s completed", nextTask.ID)
+			if analysis.SkipTests {
+				logger.Warn("Task %s completed without running tests (confidence %.2f)", nextTask.ID, analysis.Confidence)
+				if err := breaker.RecordUntestedCompletion(loopNumber); err != nil {
+					logger.Warn("Failed to record untested completion: %v", err)
+				}
+			}
+			runCompletionHook(ctx, hook, reader, logger, nextTask)
 
 			// Check if feature is complete and create tag
 			if featureComplete, _ := reader.IsFeatureComplete(nextTask.FeatureID); featureComplete {
@@ -273,7 +1140,15 @@ func runExecute(cmd *cobra.Command, args []string) error {
 						if err := gitOps.CreateFeatureTag(feature.ID, feature.Name, feature.TargetVersion); err != nil {
 							logger.Warn("Failed to create tag: %v", err)
 						} else {
-							logger.Success("Created tag: %s", feature.TargetVersion)
+							tag := git.FeatureTagName(feature.TargetVersion)
+							logger.Success("Created tag: %s", tag)
+							if gitPushTags {
+								if err := gitOps.PushTag(tag, ""); err != nil {
+									logger.Warn("%v", err)
+								} else {
+									logger.Success("Pushed tag %s to %s", tag, gitOps.Remote())
+								}
+							}
 						}
 					}
 				}
@@ -286,6 +1161,8 @@ func runExecute(cmd *cobra.Command, args []string) error {
 			}
 		}
 
+		createCheckpoint(gitOps, breaker, logger, checkpointInterval, loopNumber, nextTask.ID)
+
 		// Pause between tasks if not autonomous
 		if !autonomous && analysis.IsComplete {
 			fmt.Println("\nPress Enter to continue or Ctrl+C to stop...")
@@ -294,14 +1171,236 @@ func runExecute(cmd *cobra.Command, args []string) error {
 	}
 }
 
+// readContextFiles reads the files whose content is added to every prompt
+func readContextFiles(paths []string) ([]string, error) {
+	var contents []string
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read context file: %w", err)
+		}
+		contents = append(contents, string(data))
+	}
+	return contents, nil
+}
+
+// createCheckpoint commits and tags the work of every interval-th loop, so a
+// crash loses at most interval loops of work
+func createCheckpoint(gitOps *git.Git, breaker *circuit.Breaker, logger *ui.Logger, interval, loopNumber int, taskID string) {
+	if interval <= 0 || loopNumber%interval != 0 {
+		return
+	}
+
+	if err := gitOps.CreateCheckpoint(loopNumber, taskID); err != nil {
+		logger.Warn("Failed to create checkpoint: %v", err)
+		return
+	}
+	if err := breaker.RecordCheckpoint(loopNumber); err != nil {
+		logger.Warn("Failed to record checkpoint: %v", err)
+	}
+	logger.Info("Checkpoint %s created", git.CheckpointTag(loopNumber))
+}
+
+// notifyTask sends a task event with the current progress to webhooks
+func notifyTask(notify *notifier.Notifier, reader *task.Reader, logger *ui.Logger, event string, t *task.Task) {
+	if !notify.IsEnabled() {
+		return
+	}
+
+	if err := notify.Send(event, taskPayload(reader, t)); err != nil {
+		logger.Warn("Webhook notification failed: %v", err)
+	}
+}
+
+// addLoopResult records a loop result and notifies webhooks on circuit state transitions
+func addLoopResult(breaker *circuit.Breaker, notify *notifier.Notifier, reader *task.Reader, logger *ui.Logger, hasProgress, hasError bool, loopNumber int, t *task.Task) {
+	before, _ := breaker.GetState()
+	breaker.AddLoopResult(hasProgress, hasError, loopNumber)
+
+	if !notify.IsEnabled() || before == nil {
+		return
+	}
+
+	after, err := breaker.GetState()
+	if err != nil || after.State == before.State {
+		return
+	}
+
+	payload := taskPayload(reader, t)
+	payload.Message = after.Reason
+	if err := notify.Send(notifier.CircuitEvent(after.State), payload); err != nil {
+		logger.Warn("Webhook notification failed: %v", err)
+	}
+}
+
+// taskPayload returns the notification payload for a task with the current
+// progress and the absolute path of its feature file
+func taskPayload(reader *task.Reader, t *task.Task) notifier.Payload {
+	progress, _ := reader.GetProgress()
+	payload := notifier.Payload{TaskID: t.ID, TaskName: t.Name, Progress: progress}
+	if feature, _ := reader.GetFeatureByID(t.FeatureID); feature != nil {
+		payload.FeatureFile, _ = filepath.Abs(feature.FilePath)
+	}
+	return payload
+}
+
+// parseNotifyTarget returns the webhook URL of a --notify value
+func parseNotifyTarget(target string) (string, error) {
+	url, ok := strings.CutPrefix(target, "slack:")
+	if !ok {
+		return "", fmt.Errorf("invalid --notify %q (expected slack:<webhook-url>)", target)
+	}
+	if !strings.HasPrefix(url, "https://") && !strings.HasPrefix(url, "http://") {
+		return "", fmt.Errorf("invalid Slack webhook URL %q", url)
+	}
+	return url, nil
+}
+
+// schedulableTasks returns the tasks matching the filter plus the completed
+// ones, which stay for dependency resolution
+func schedulableTasks(reader *task.Reader, filter *task.Filter) ([]task.Task, error) {
+	allTasks, err := reader.GetAllTasks()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tasks: %w", err)
+	}
+	// Completed archived tasks meet the dependencies on them
+	for _, t := range reader.GetArchivedTasks() {
+		if t.Status == task.StatusCompleted {
+			allTasks = append(allTasks, t)
+		}
+	}
+	if filter.IsEmpty() {
+		return allTasks, nil
+	}
+
+	var filtered []task.Task
+	for _, t := range allTasks {
+		if filter.Match(t) || t.Status == task.StatusCompleted {
+			filtered = append(filtered, t)
+		}
+	}
+	return filtered, nil
+}
+
+// tasksOutOfScope returns the IDs of the unfinished tasks that match the
+// filter except for its scope
+func tasksOutOfScope(reader *task.Reader, filter *task.Filter) ([]string, error) {
+	tasks, err := reader.GetAllTasks()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tasks: %w", err)
+	}
+	unscoped := *filter
+	unscoped.Scope = nil
+
+	var outside []string
+	for _, t := range tasks {
+		if t.Status != task.StatusCompleted && unscoped.Match(t) && !filter.Match(t) {
+			outside = append(outside, t.ID)
+		}
+	}
+	return outside, nil
+}
+
+// resolveStrategy picks the execution strategy from --strategy, --parallel
+// and the config, resolving "auto" from the task graph
+func resolveStrategy(cmd *cobra.Command, cfg *config.Config, reader *task.Reader, filter *task.Filter) (string, string, error) {
+	parallel := cfg.Parallel.Enabled
+	if cmd.Flags().Changed("parallel") {
+		parallel, _ = cmd.Flags().GetBool("parallel")
+	}
+	requested := scheduler.StrategySequential
+	if parallel {
+		requested = scheduler.StrategyParallel
+	}
+
+	if cmd.Flags().Changed("strategy") {
+		requested, _ = cmd.Flags().GetString("strategy")
+		if !scheduler.IsValidStrategy(requested) {
+			return "", "", fmt.Errorf("unknown strategy: %s (use sequential, parallel or auto)", requested)
+		}
+		if cmd.Flags().Changed("parallel") && parallel && requested == scheduler.StrategySequential {
+			return "", "", fmt.Errorf("--parallel conflicts with --strategy sequential")
+		}
+	}
+
+	if requested != scheduler.StrategyAuto {
+		return requested, requested, nil
+	}
+
+	allTasks, err := schedulableTasks(reader, filter)
+	if err != nil {
+		return "", "", err
+	}
+	ptrs := make([]*task.Task, len(allTasks))
+	for i := range allTasks {
+		ptrs[i] = &allTasks[i]
+	}
+	strategy, err := scheduler.ChooseStrategy(ptrs)
+	if err != nil {
+		return "", "", err
+	}
+	return strategy, requested, nil
+}
+
+// taskRetryConfig returns the retries of transient provider errors, with
+// the back-off of --retry-backoff and --retry-max-delay when they are set
+func taskRetryConfig(cmd *cobra.Command, maxRetries int) ai.RetryConfig {
+	retry := *ai.DefaultRetryConfig()
+	retry.MaxRetries = maxRetries
+	if cmd.Flags().Changed("retry-backoff") {
+		retry.BackoffMultiplier, _ = cmd.Flags().GetFloat64("retry-backoff")
+		retry.Jitter = retry.BackoffMultiplier > 1
+	}
+	if cmd.Flags().Changed("retry-max-delay") {
+		retry.MaxDelay, _ = cmd.Flags().GetDuration("retry-max-delay")
+	}
+	return retry
+}
+
+// pushTaskBranch pushes the branch of a completed task: the feature branch
+// with --auto-branch, otherwise the current branch. Failures are logged.
+func pushTaskBranch(gitOps *git.Git, reader *task.Reader, logger *ui.Logger, autoBranch bool, t *task.Task) {
+	var branch string
+	if autoBranch {
+		if feature, _ := reader.GetFeatureByID(t.FeatureID); feature != nil {
+			branch = gitOps.GetFeatureBranchName(feature.ID, feature.Name)
+		}
+	}
+	if branch == "" {
+		current, err := gitOps.GetCurrentBranch()
+		if err != nil {
+			logger.Warn("Failed to get the branch to push: %v", err)
+			return
+		}
+		branch = current
+	}
+
+	if err := gitOps.Push(branch, ""); err != nil {
+		logger.Warn("%v", err)
+		return
+	}
+	logger.Success("Pushed %s to %s", branch, gitOps.Remote())
+}
+
+// confirmCost asks whether to run although the estimated cost exceeds the
+// threshold and reports whether the answer was yes
+func confirmCost(estimate, threshold float64, in *bufio.Reader, out io.Writer) bool {
+	fmt.Fprintf(out, "Estimated cost $%.2f exceeds $%.2f. Continue? (y/n) ", estimate, threshold)
+	answer, _ := in.ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
+
 // runParallel executes tasks in parallel mode
-func runParallel(ctx context.Context, cfg *config.Config, provider ai.Provider, reader *task.Reader, logger *ui.Logger, workers int, dryRun bool) error {
+func runParallel(ctx context.Context, cfg *config.Config, provider ai.Provider, reader *task.Reader, logger *ui.Logger, opts parallelOptions) error {
 	ui.PrintHeader("Parallel Task Execution")
 
+	workers := opts.workers
+
 	// Get all tasks (including completed for dependency resolution)
-	allTasks, err := reader.GetAllTasks()
+	allTasks, err := schedulableTasks(reader, opts.filter)
 	if err != nil {
-		return fmt.Errorf("failed to get tasks: %w", err)
+		return err
 	}
 
 	// Count pending tasks
@@ -318,6 +1417,10 @@ func runParallel(ctx context.Context, cfg *config.Config, provider ai.Provider,
 	}
 
 	logger.Info("Found %d pending tasks", pendingCount)
+	if capped := scheduler.CapWorkers(provider, workers); capped < workers {
+		logger.Warn("%s allows %d parallel requests, reducing workers from %d", provider.Name(), capped, workers)
+		workers = capped
+	}
 	logger.Info("Using %d parallel workers", workers)
 
 	// Convert to pointer slice for scheduler (includes all tasks for dependency resolution)
@@ -332,6 +1435,46 @@ func runParallel(ctx context.Context, cfg *config.Config, provider ai.Provider,
 
 	// Create scheduler
 	sched := scheduler.New(&parallelCfg, provider, ".", logger)
+	sched.SetIsolation(opts.isolation, opts.dockerImage)
+	sched.SetPromptContext(strings.Join(opts.context, "\n\n"))
+	sched.SetExtraArgs(opts.extraArgs)
+	sched.SetSystemPrompt(opts.systemPrompt)
+	sched.SetOutputFilter(opts.outputFilter)
+	sched.SetRetry(opts.retry)
+	sched.SetIgnoreState(opts.ignoreState)
+	if opts.noIgnore {
+		sched.SetIgnoreMatcher(nil)
+	}
+	sched.SetNoApply(opts.noApply)
+	sched.SetRebaseBeforeMerge(opts.rebase)
+	if opts.shard != nil || opts.shardLock != nil {
+		sched.SetShard(opts.shard, opts.shardLock)
+	}
+	sched.AddHealthCheck("disk-space", scheduler.DiskSpaceCheck(minFreeDiskMB))
+	if opts.isolation == isolation.ModeWorktree {
+		sched.AddHealthCheck("git-repo", scheduler.GitRepoCheck("."))
+	}
+	if opts.healthRetry == 0 {
+		sched.SetHealthRetryLimit(-1)
+	} else {
+		sched.SetHealthRetryLimit(opts.healthRetry)
+	}
+
+	// Validate tasks and report conflicts without executing
+	if opts.dryRun {
+		sched.DryRun = true
+		result, err := sched.Execute(ctx, allTaskPtrs)
+		if err != nil {
+			return err
+		}
+		sched.PrintDryRunResult(result)
+
+		if len(result.Conflicts) > 0 || result.Failed > 0 {
+			return fmt.Errorf("dry run found %d file conflict(s) and %d invalid task(s)", len(result.Conflicts), result.Failed)
+		}
+		logger.Info("Dry run complete. Use --parallel without --dry-run to execute.")
+		return nil
+	}
 
 	// Get execution plan (uses all tasks for dependency resolution, but only executes pending)
 	plan, err := sched.GetExecutionPlan(allTaskPtrs)
@@ -342,12 +1485,6 @@ func runParallel(ctx context.Context, cfg *config.Config, provider ai.Provider,
 	// Print execution plan
 	sched.PrintExecutionPlan(plan)
 
-	// If dry-run, stop here
-	if dryRun {
-		logger.Info("Dry run complete. Use --parallel without --dry-run to execute.")
-		return nil
-	}
-
 	// Initialize parallel logger
 	parallelLogger, err := scheduler.NewParallelLogger(".", workers)
 	if err != nil {
@@ -368,9 +1505,23 @@ func runParallel(ctx context.Context, cfg *config.Config, provider ai.Provider,
 	if cfg.Parallel.MaxCostPerHour > 0 {
 		resourceMonitor.SetCostLimit(cfg.Parallel.MaxCostPerHour)
 	}
+	sched.SetResourceMonitor(resourceMonitor)
+	if opts.metrics != nil {
+		opts.metrics.SetResourceStats(resourceMonitor.GetStats)
+		sched.SetHooks(scheduler.ExecutionHooks{
+			OnTaskDone: func(r *scheduler.TaskResult) { opts.metrics.AddTaskResult(provider.Name(), r) },
+		})
+	}
 
 	// Initialize rollback manager
 	rollback := scheduler.NewRollback(".")
+	sched.SetRollback(rollback)
+
+	// Commit each task's branch separately when auto-commit is enabled.
+	// Only worktree workspaces have a task branch.
+	if opts.autoCommit && opts.isolation == isolation.ModeWorktree {
+		sched.SetAutoCommit(git.NewParallelBranchManager(git.New(".", "")))
+	}
 	defer func() {
 		// Cleanup on exit
 		if rollback.HasSnapshots() {
@@ -393,12 +1544,39 @@ func runParallel(ctx context.Context, cfg *config.Config, provider ai.Provider,
 		parallelLogger.Main("Total tasks: %d, Batches: %d", pendingCount, len(plan.Batches))
 	}
 
+	// Stream progress to 'hermes tui --attach'
+	var pipe *tuiPipe
+	if opts.tuiPipe != "" {
+		if pipe, err = newTUIPipe(opts.tuiPipe, workers, pendingCount); err != nil {
+			return err
+		}
+		defer pipe.Close()
+		logger.Info("Streaming progress to %s (attach with: hermes tui --attach %s)", opts.tuiPipe, opts.tuiPipe)
+	}
+
 	// Execute tasks
 	logger.Info("Starting parallel execution...")
 	startTime := time.Now()
 
-	result, err := sched.Execute(ctx, allTaskPtrs)
-	
+	var result *scheduler.ExecutionResult
+	if opts.showTUI {
+		// Only worktree workspaces have a task branch to watch
+		var detector *merger.ConflictDetector
+		repo := git.New(".", "")
+		if opts.isolation == isolation.ModeWorktree && repo.IsRepository() {
+			baseBranch, _ := repo.GetCurrentBranch()
+			detector = merger.NewConflictDetector()
+			detector.SetRepository(repo, baseBranch)
+			detector.SetWatchFilter(sched.InBatch)
+		}
+		result, err = executeWithTUI(ctx, sched, detector, pipe, allTaskPtrs, logger, workers, pendingCount)
+	} else {
+		if pipe != nil {
+			sched.SetEventChannel(pipe.Events(ctx, nil))
+		}
+		result, err = sched.Execute(ctx, allTaskPtrs)
+	}
+
 	executionTime := time.Since(startTime)
 
 	if err != nil {
@@ -424,6 +1602,9 @@ func runParallel(ctx context.Context, cfg *config.Config, provider ai.Provider,
 
 	// Print results
 	sched.PrintExecutionResult(result)
+	for _, c := range sched.RebaseConflicts() {
+		logger.Warn("Rebase conflict in %s: %s", c.File, c.Description)
+	}
 
 	// Log completion
 	if parallelLogger != nil {
@@ -441,11 +1622,21 @@ func runParallel(ctx context.Context, cfg *config.Config, provider ai.Provider,
 
 	// Update task statuses
 	statusUpdater := task.NewStatusUpdater(".")
+	statusUpdater.SetSession(opts.session)
 	for _, r := range result.Results {
 		if r.Success {
 			if err := statusUpdater.UpdateTaskStatus(r.TaskID, task.StatusCompleted); err != nil {
 				logger.Warn("Failed to update task %s status: %v", r.TaskID, err)
 			}
+			for i := range allTasks {
+				if allTasks[i].ID != r.TaskID {
+					continue
+				}
+				if opts.metrics != nil {
+					opts.metrics.IncTasksCompleted(allTasks[i].FeatureID)
+				}
+				runCompletionHook(ctx, opts.hook, reader, logger, &allTasks[i])
+			}
 		}
 	}
 
@@ -460,3 +1651,129 @@ func runParallel(ctx context.Context, cfg *config.Config, provider ai.Provider,
 	logger.Success("All %d tasks completed successfully!", result.Successful)
 	return nil
 }
+
+// executeWithTUI runs the scheduler while rendering live worker status in the
+// ParallelModel TUI. If detector is set, conflicts between task branches are
+// shown as soon as workers commit them. If pipe is set, the events are also
+// streamed to it.
+func executeWithTUI(ctx context.Context, sched *scheduler.Scheduler, detector *merger.ConflictDetector, pipe *tuiPipe, tasks []*task.Task, logger *ui.Logger, workers, pending int) (*scheduler.ExecutionResult, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	model := tui.NewParallelModel(".", workers)
+	model.SetTotal(pending)
+	model.SetPauser(sched)
+
+	sched.SetEventChannel(pipe.Events(ctx, model.Events()))
+
+	if detector != nil {
+		detected := detector.WatchForConflicts(ctx, conflictWatchInterval)
+		go func() {
+			for conflicts := range detected {
+				for _, c := range conflicts {
+					logger.Warn("Conflict detected in %s between %s: %s", c.File, strings.Join(c.Tasks, ", "), c.Description)
+				}
+				select {
+				case model.Conflicts() <- conflicts:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+
+	// Console logging would corrupt the TUI, keep writing to the log file only
+	logger.SetQuiet(true)
+	defer logger.SetQuiet(false)
+
+	program := tea.NewProgram(model, tea.WithAltScreen())
+	programDone := make(chan struct{})
+	go func() {
+		defer close(programDone)
+		final, _ := program.Run()
+		// User quit the TUI before execution finished
+		if m, ok := final.(*tui.ParallelModel); !ok || !m.IsDone() {
+			cancel()
+		}
+	}()
+
+	result, err := sched.Execute(ctx, tasks)
+
+	program.Quit()
+	<-programDone
+
+	return result, err
+}
+
+// parseBudgetFlags builds the token budget from --token-budget and --cost-budget
+func parseBudgetFlags(cmd *cobra.Command) (ai.TokenBudget, error) {
+	var budget ai.TokenBudget
+	budget.MaxTotalCost, _ = cmd.Flags().GetFloat64("cost-budget")
+
+	tokens, _ := cmd.Flags().GetString("token-budget")
+	if tokens == "" {
+		return budget, nil
+	}
+	in, out, split := strings.Cut(tokens, ":")
+	if !split {
+		out = in
+	}
+	var err error
+	if budget.MaxInputTokens, err = strconv.Atoi(strings.TrimSpace(in)); err != nil {
+		return budget, fmt.Errorf("invalid --token-budget %q: expected N or IN:OUT", tokens)
+	}
+	if budget.MaxOutputTokens, err = strconv.Atoi(strings.TrimSpace(out)); err != nil {
+		return budget, fmt.Errorf("invalid --token-budget %q: expected N or IN:OUT", tokens)
+	}
+	return budget, nil
+}
+
+// trimPrompt trims a prompt to the context window budget, keeping its first
+// lines and the task section, and warns about what was removed
+func trimPrompt(content string, budget int, logger *ui.Logger) string {
+	trimmed, report := ai.TrimToTokenBudgetWithReport(content, budget,
+		ai.DefaultTrimStrategy([2]string{prompt.TaskSectionStart, prompt.TaskSectionEnd}))
+	if report.RemovedLines == 0 {
+		return content
+	}
+
+	removed := fmt.Sprintf("%d lines, about %d tokens", report.RemovedLines, report.RemovedTokens)
+	if len(report.Headings) > 0 {
+		removed += ", including " + strings.Join(report.Headings, ", ")
+	}
+	logger.Warn("Prompt trimmed to fit --context-window-budget %d: removed %s", budget, removed)
+	if tokens := ai.EstimateTokens(trimmed); tokens > budget {
+		logger.Warn("Prompt is still about %d tokens: its first lines and the task section exceed the budget", tokens)
+	}
+	return trimmed
+}
+
+// resolveExtraArgs merges the configured extra AI arguments with --ai-args,
+// which override them, and rejects unsafe ones unless allowed
+func resolveExtraArgs(configured map[string]string, flags []string, allowUnsafe bool) (map[string]string, error) {
+	parsed, err := ai.ParseExtraArgs(flags)
+	if err != nil {
+		return nil, err
+	}
+
+	args := make(map[string]string, len(configured)+len(parsed))
+	for key, value := range configured {
+		args[strings.TrimLeft(key, "-")] = value
+	}
+	for key, value := range parsed {
+		args[key] = value
+	}
+	if err := ai.ValidateExtraArgs(args, allowUnsafe); err != nil {
+		return nil, err
+	}
+	return args, nil
+}
+
+// isTerminal returns true if the file is attached to a terminal
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"unicode/utf8"
+
+	"hermes/internal/ai"
+	"hermes/internal/task"
+)
+
+// dryRunAIPromptLimit is how many characters of the prompt --dry-run-ai
+// prints
+const dryRunAIPromptLimit = 2000
+
+// printDryRunAI prints what would be sent to the AI for a task: the CLI
+// command, the prompt and its estimated size in tokens
+func printDryRunAI(w io.Writer, provider ai.Provider, t *task.Task, opts *ai.ExecuteOptions) {
+	fmt.Fprintf(w, "Task: %s - %s\n", t.ID, t.Name)
+	fmt.Fprintf(w, "Provider: %s\n", provider.Name())
+	if opts.WorkDir != "" {
+		fmt.Fprintf(w, "Working directory: %s\n", opts.WorkDir)
+	}
+
+	fmt.Fprintln(w, "\nCommand:")
+	if args := ai.CommandLine(provider, opts); args != nil {
+		fmt.Fprintf(w, "  %s\n", ai.FormatCommand(args))
+	} else {
+		fmt.Fprintf(w, "  (%s runs no CLI)\n", provider.Name())
+	}
+	if opts.SystemPrompt != "" {
+		fmt.Fprintf(w, "\nSystem prompt (%d characters):\n%s\n", len(opts.SystemPrompt), truncateText(opts.SystemPrompt, dryRunAIPromptLimit))
+	}
+
+	fmt.Fprintf(w, "\nPrompt (%d characters):\n%s\n", len(opts.Prompt), truncateText(opts.Prompt, dryRunAIPromptLimit))
+
+	tokens := ai.EstimateTokens(opts.SystemPrompt + opts.Prompt)
+	if max := provider.Capabilities().MaxContextTokens; max > 0 {
+		fmt.Fprintf(w, "\nEstimated tokens: %d (%.1f%% of the %d token context)\n", tokens, float64(tokens)*100/float64(max), max)
+	} else {
+		fmt.Fprintf(w, "\nEstimated tokens: %d\n", tokens)
+	}
+}
+
+// truncateText cuts text to at most limit bytes, without splitting a
+// character, and marks the cut with "..."
+func truncateText(text string, limit int) string {
+	if len(text) <= limit {
+		return text
+	}
+	cut := limit
+	for cut > 0 && !utf8.RuneStart(text[cut]) {
+		cut--
+	}
+	return text[:cut] + "..."
+}
@@ -0,0 +1,230 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"hermes/internal/isolation"
+	"hermes/internal/task"
+	"hermes/internal/ui"
+)
+
+// outputDir is a directory the AI works in instead of the project, for
+// projects that are read-only. A git repository in it tracks the AI's
+// changes since the last apply, so they can be patched into the project.
+type outputDir struct {
+	path string
+	base string // Commit the next patch is diffed against
+}
+
+// newOutputDir creates the output directory and its tracking repository
+func newOutputDir(path string) (*outputDir, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("invalid output dir: %w", err)
+	}
+	if err := os.MkdirAll(abs, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create output dir: %w", err)
+	}
+
+	// A dir reused from an earlier run keeps its base, so changes that were
+	// not applied then are applied with the next patch. The check for .git
+	// keeps git from finding the project repository above the dir.
+	d := &outputDir{path: abs}
+	if _, err := os.Stat(filepath.Join(abs, ".git")); err == nil {
+		if head, err := d.git("rev-parse", "HEAD"); err == nil {
+			d.base = strings.TrimSpace(head)
+			return d, nil
+		}
+	} else if _, err := d.git("init", "-q"); err != nil {
+		return nil, fmt.Errorf("failed to init output dir: %w", err)
+	}
+	if err := d.commit("Output dir base"); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// seed copies the task's files to touch from the project into the output
+// dir, so the AI can edit them. Files already in the output dir are kept,
+// directories are copied without their subdirectories, and paths outside
+// the project are skipped. Files not in the project are returned as
+// missing. The copies become part of the base, so only the AI's edits end
+// up in the patch.
+func (d *outputDir) seed(projectDir string, t *task.Task) ([]string, []string, error) {
+	var seeded, missing []string
+	for _, file := range t.FilesToTouch {
+		file = task.UnquoteItem(strings.TrimSpace(file))
+		rel := filepath.Clean(filepath.FromSlash(file))
+		if filepath.IsAbs(rel) || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			continue
+		}
+		src := filepath.Join(projectDir, rel)
+		info, err := os.Stat(src)
+		if err != nil {
+			missing = append(missing, filepath.ToSlash(rel))
+			continue
+		}
+
+		paths := []string{rel}
+		if info.IsDir() {
+			entries, err := os.ReadDir(src)
+			if err != nil {
+				return seeded, missing, fmt.Errorf("failed to read %s: %w", file, err)
+			}
+			paths = paths[:0]
+			for _, entry := range entries {
+				if entry.Type().IsRegular() {
+					paths = append(paths, filepath.Join(rel, entry.Name()))
+				}
+			}
+		}
+		for _, path := range paths {
+			copied, err := seedFile(filepath.Join(projectDir, path), filepath.Join(d.path, path))
+			if err != nil {
+				return seeded, missing, fmt.Errorf("failed to copy %s to the output dir: %w", path, err)
+			}
+			if copied {
+				seeded = append(seeded, filepath.ToSlash(path))
+			}
+		}
+	}
+	return seeded, missing, d.commit("Seed files")
+}
+
+// changes returns the files changed in the output dir since the last apply
+func (d *outputDir) changes() ([]string, error) {
+	if _, err := d.git("add", "-A"); err != nil {
+		return nil, fmt.Errorf("failed to stage output dir changes: %w", err)
+	}
+	output, err := d.git("diff", "--cached", "--name-only", d.base)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get output dir changes: %w", err)
+	}
+	var files []string
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		if line != "" {
+			files = append(files, line)
+		}
+	}
+	return files, nil
+}
+
+// apply patches the changes made in the output dir since the last apply
+// into targetDir with git apply and returns the changed files. A patch that
+// does not apply returns an *isolation.PatchConflictError, leaving
+// targetDir unchanged and keeping the changes for the next apply.
+func (d *outputDir) apply(targetDir string) ([]string, error) {
+	files, err := d.changes()
+	if err != nil || len(files) == 0 {
+		return nil, err
+	}
+
+	patch, err := d.git("diff", "--cached", "--binary", d.base)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get output dir diff: %w", err)
+	}
+	if err := isolation.ApplyPatch(targetDir, patch, false); err != nil {
+		return files, err
+	}
+	return files, d.commit("Applied changes")
+}
+
+// commit records the output dir content as the base of the next patch
+func (d *outputDir) commit(message string) error {
+	if _, err := d.git("add", "-A"); err != nil {
+		return fmt.Errorf("failed to stage output dir: %w", err)
+	}
+	if d.base != "" {
+		if _, err := d.git("diff", "--cached", "--quiet"); err == nil {
+			return nil
+		}
+	}
+	// The output dir has no user config, so the identity is given explicitly
+	if _, err := d.git("-c", "user.name=Hermes", "-c", "user.email=hermes@localhost",
+		"commit", "-q", "--allow-empty", "-m", message); err != nil {
+		return fmt.Errorf("failed to commit output dir: %w", err)
+	}
+	head, err := d.git("rev-parse", "HEAD")
+	if err != nil {
+		return fmt.Errorf("failed to get output dir base: %w", err)
+	}
+	d.base = strings.TrimSpace(head)
+	return nil
+}
+
+// git runs a git command in the output dir
+func (d *outputDir) git(args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = d.path
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", err, string(output))
+	}
+	return string(output), nil
+}
+
+// applyOutputDir applies the output dir changes of a loop to the project,
+// or with noApply only logs them. Changes that do not apply stop the run,
+// since later loops would build on them.
+func applyOutputDir(d *outputDir, noApply bool, logger *ui.Logger) error {
+	if noApply {
+		files, err := d.changes()
+		if err != nil {
+			return err
+		}
+		if len(files) > 0 {
+			logger.Info("%d file(s) changed in %s: %s", len(files), d.path, strings.Join(files, ", "))
+		}
+		return nil
+	}
+
+	files, err := d.apply(".")
+	if err != nil {
+		return fmt.Errorf("failed to apply the changes in %s to the project: %w", d.path, err)
+	}
+	if len(files) > 0 {
+		logger.Info("Applied %d file(s) from %s: %s", len(files), d.path, strings.Join(files, ", "))
+	}
+	return nil
+}
+
+// sameDir reports whether two paths name the same directory
+func sameDir(a, b string) bool {
+	infoA, errA := os.Stat(a)
+	infoB, errB := os.Stat(b)
+	return errA == nil && errB == nil && os.SameFile(infoA, infoB)
+}
+
+// seedFile copies a file unless dst exists, reporting whether it copied
+func seedFile(src, dst string) (bool, error) {
+	if _, err := os.Lstat(dst); err == nil {
+		return false, nil
+	}
+	info, err := os.Stat(src)
+	if err != nil {
+		return false, err
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return false, err
+	}
+
+	in, err := os.Open(src)
+	if err != nil {
+		return false, err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, info.Mode().Perm())
+	if err != nil {
+		return false, err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return false, err
+	}
+	return true, out.Close()
+}
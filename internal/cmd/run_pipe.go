@@ -0,0 +1,189 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"hermes/internal/scheduler"
+	"hermes/internal/tui"
+)
+
+// tuiPipeFlushTimeout is how long Close waits for an attached TUI to read
+// the last events
+const tuiPipeFlushTimeout = 2 * time.Second
+
+// tuiPipe streams the events of a parallel run as JSON lines to a named
+// pipe for 'hermes tui --attach'. Every event is kept, so a TUI that
+// attaches late, or again after it was closed, is sent the whole run so far.
+// Without a reader the events are only kept, never blocking the run.
+type tuiPipe struct {
+	path    string
+	created bool // The pipe was made by the run and is removed by Close
+
+	mu       sync.Mutex
+	cond     *sync.Cond
+	lines    [][]byte
+	closed   bool
+	attached bool          // A reader has the pipe open
+	served   chan struct{} // Closed when serve returns
+
+	forwarded chan struct{} // Closed when the Events goroutine returns
+}
+
+// newTUIPipe creates the named pipe at path unless it exists and starts
+// serving readers. The stream opens with a start event for the number of
+// workers and pending tasks.
+func newTUIPipe(path string, workers, total int) (*tuiPipe, error) {
+	p := &tuiPipe{path: path, served: make(chan struct{})}
+	p.cond = sync.NewCond(&p.mu)
+
+	info, err := os.Stat(path)
+	switch {
+	case os.IsNotExist(err):
+		if err := makeFifo(path); err != nil {
+			return nil, fmt.Errorf("failed to create TUI pipe %s: %w", path, err)
+		}
+		p.created = true
+	case err != nil:
+		return nil, fmt.Errorf("failed to check TUI pipe %s: %w", path, err)
+	case info.Mode()&os.ModeNamedPipe == 0:
+		return nil, fmt.Errorf("%s exists and is not a named pipe", path)
+	}
+
+	p.add(tui.NewStartEvent(workers, total))
+	go p.serve()
+	return p, nil
+}
+
+// Events returns the channel to pass to Scheduler.SetEventChannel. Events
+// sent on it are written to the pipe and passed on to next unless it is
+// nil, e.g. to the channel of a ParallelModel. Forwarding stops after the
+// run done event or when ctx is done. A nil pipe returns next.
+func (p *tuiPipe) Events(ctx context.Context, next chan<- scheduler.WorkerEvent) chan<- scheduler.WorkerEvent {
+	if p == nil {
+		return next
+	}
+	events := make(chan scheduler.WorkerEvent, 16)
+	p.forwarded = make(chan struct{})
+	go func() {
+		defer close(p.forwarded)
+		for {
+			var event scheduler.WorkerEvent
+			select {
+			case event = <-events:
+			case <-ctx.Done():
+				return
+			}
+			p.add(tui.NewPipeEvent(event))
+			if next != nil {
+				select {
+				case next <- event:
+				case <-ctx.Done():
+					return
+				}
+			}
+			if event.Type == scheduler.EventRunDone {
+				return
+			}
+		}
+	}()
+	return events
+}
+
+// add encodes an event and wakes the reader
+func (p *tuiPipe) add(event tui.PipeEvent) {
+	line, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+	p.mu.Lock()
+	p.lines = append(p.lines, append(line, '\n'))
+	p.mu.Unlock()
+	p.cond.Broadcast()
+}
+
+// serve writes the events to each reader in turn. Opening the pipe blocks
+// until a reader attaches; a reader that goes away makes the write fail,
+// and the next one is sent the events from the start.
+func (p *tuiPipe) serve() {
+	defer close(p.served)
+	for {
+		f, err := os.OpenFile(p.path, os.O_WRONLY, 0)
+		if err != nil {
+			return
+		}
+		p.setAttached(true)
+
+		sent := 0
+		for {
+			p.mu.Lock()
+			for sent == len(p.lines) && !p.closed {
+				p.cond.Wait()
+			}
+			pending := p.lines[sent:]
+			closed := p.closed
+			p.mu.Unlock()
+
+			if err := writeLines(f, pending); err != nil {
+				break
+			}
+			sent += len(pending)
+			if closed {
+				f.Close()
+				return
+			}
+		}
+		f.Close()
+		p.setAttached(false)
+	}
+}
+
+func (p *tuiPipe) setAttached(attached bool) {
+	p.mu.Lock()
+	p.attached = attached
+	p.mu.Unlock()
+}
+
+// Close ends the stream once the events sent on the Events channel are
+// queued, giving an attached TUI a moment to read them, and removes the
+// pipe if the run created it
+func (p *tuiPipe) Close() {
+	if p == nil {
+		return
+	}
+	if p.forwarded != nil {
+		select {
+		case <-p.forwarded:
+		case <-time.After(tuiPipeFlushTimeout):
+		}
+	}
+
+	p.mu.Lock()
+	p.closed = true
+	attached := p.attached
+	p.mu.Unlock()
+	p.cond.Broadcast()
+
+	if attached {
+		select {
+		case <-p.served:
+		case <-time.After(tuiPipeFlushTimeout):
+		}
+	}
+	if p.created {
+		os.Remove(p.path)
+	}
+}
+
+func writeLines(f *os.File, lines [][]byte) error {
+	for _, line := range lines {
+		if _, err := f.Write(line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,10 @@
+//go:build !windows
+
+package cmd
+
+import "syscall"
+
+// makeFifo creates a named pipe readable and writable by the current user
+func makeFifo(path string) error {
+	return syscall.Mkfifo(path, 0600)
+}
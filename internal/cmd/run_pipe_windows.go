@@ -0,0 +1,11 @@
+//go:build windows
+
+package cmd
+
+import "errors"
+
+// makeFifo reports that named pipes in the file system are not available
+// on Windows
+func makeFifo(path string) error {
+	return errors.New("named pipes are not supported on Windows")
+}
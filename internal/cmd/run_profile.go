@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/pprof"
+	"runtime/trace"
+)
+
+// Profile file names in profileDir
+const (
+	cpuProfileFile   = "cpu.pprof"
+	memProfileFile   = "mem.pprof"
+	traceProfileFile = "trace.out"
+)
+
+// profileDir returns the directory 'hermes run --profile' writes to
+func profileDir(basePath string) string {
+	return filepath.Join(basePath, ".hermes", "profiles")
+}
+
+// runProfiler profiles a run for --profile and --trace
+type runProfiler struct {
+	dir   string
+	mem   bool
+	cpu   *os.File
+	trace *os.File
+}
+
+// startProfiling starts CPU profiling (mode "cpu") and the execution trace
+// in dir. A heap profile (mode "mem") is written by stop.
+func startProfiling(dir, mode string, withTrace bool) (*runProfiler, error) {
+	if mode != "" && mode != "cpu" && mode != "mem" {
+		return nil, fmt.Errorf("invalid --profile %q (use cpu or mem)", mode)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create profile directory: %w", err)
+	}
+
+	p := &runProfiler{dir: dir, mem: mode == "mem"}
+	if mode == "cpu" {
+		f, err := os.Create(filepath.Join(dir, cpuProfileFile))
+		if err != nil {
+			return nil, err
+		}
+		if err := pprof.StartCPUProfile(f); err != nil {
+			f.Close()
+			return nil, fmt.Errorf("failed to start CPU profile: %w", err)
+		}
+		p.cpu = f
+	}
+	if withTrace {
+		f, err := os.Create(filepath.Join(dir, traceProfileFile))
+		if err != nil {
+			p.stop()
+			return nil, err
+		}
+		if err := trace.Start(f); err != nil {
+			f.Close()
+			p.stop()
+			return nil, fmt.Errorf("failed to start trace: %w", err)
+		}
+		p.trace = f
+	}
+	return p, nil
+}
+
+// stop ends profiling and returns the files written
+func (p *runProfiler) stop() ([]string, error) {
+	var files []string
+	if p.cpu != nil {
+		pprof.StopCPUProfile()
+		p.cpu.Close()
+		files = append(files, p.cpu.Name())
+		p.cpu = nil
+	}
+	if p.trace != nil {
+		trace.Stop()
+		p.trace.Close()
+		files = append(files, p.trace.Name())
+		p.trace = nil
+	}
+	if p.mem {
+		p.mem = false
+		path := filepath.Join(p.dir, memProfileFile)
+		f, err := os.Create(path)
+		if err != nil {
+			return files, err
+		}
+		defer f.Close()
+		// Collect garbage so the profile shows live memory
+		runtime.GC()
+		if err := pprof.WriteHeapProfile(f); err != nil {
+			return files, fmt.Errorf("failed to write heap profile: %w", err)
+		}
+		files = append(files, path)
+	}
+	return files, nil
+}
@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"fmt"
+
+	"hermes/internal/circuit"
+	"hermes/internal/task"
+)
+
+// resetInProgressTasks sets every IN_PROGRESS task back to NOT_STARTED and
+// returns the IDs that were reset
+func resetInProgressTasks(basePath string) ([]string, error) {
+	inProgress, err := newTaskReader(basePath).GetTasksByStatus(task.StatusInProgress)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tasks: %w", err)
+	}
+
+	updater := task.NewStatusUpdater(basePath)
+	var reset []string
+	for _, t := range inProgress {
+		if err := updater.UpdateTaskStatus(t.ID, task.StatusNotStarted); err != nil {
+			return reset, fmt.Errorf("failed to reset %s: %w", t.ID, err)
+		}
+		reset = append(reset, t.ID)
+	}
+	return reset, nil
+}
+
+// findResumePoint returns the first IN_PROGRESS task left by an interrupted
+// run and the loop number recorded by the circuit breaker. The task is nil
+// if nothing was in progress.
+func findResumePoint(basePath string, breaker *circuit.Breaker) (*task.Task, int, error) {
+	state, err := breaker.GetState()
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to read circuit breaker state: %w", err)
+	}
+	if state.State == circuit.StateOpen {
+		return nil, 0, fmt.Errorf("circuit breaker is open (%s), run 'hermes reset' before resuming", state.Reason)
+	}
+
+	inProgress, err := newTaskReader(basePath).GetTasksByStatus(task.StatusInProgress)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to read tasks: %w", err)
+	}
+	if len(inProgress) == 0 {
+		return nil, state.CurrentLoop, nil
+	}
+
+	return &inProgress[0], state.CurrentLoop, nil
+}
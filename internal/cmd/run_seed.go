@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"hermes/internal/prompt"
+)
+
+// seedPromptTimeout bounds fetching a seed prompt from a URL
+const seedPromptTimeout = 30 * time.Second
+
+// seedPrompt writes the prompt read from source, a file or an http(s) URL,
+// to PROMPT.md. An existing PROMPT.md is only replaced if force is set. It
+// returns the seeded content, or "" if PROMPT.md was kept.
+func seedPrompt(injector *prompt.Injector, source string, force bool) (string, error) {
+	if injector.Exists() && !force {
+		return "", nil
+	}
+
+	content, err := readSeedPrompt(source)
+	if err != nil {
+		return "", err
+	}
+	if strings.TrimSpace(content) == "" {
+		return "", fmt.Errorf("seed prompt %s is empty", source)
+	}
+	if err := injector.Write(content); err != nil {
+		return "", fmt.Errorf("failed to write %s: %w", injector.GetPromptPath(), err)
+	}
+	return content, nil
+}
+
+// readSeedPrompt reads a prompt from a file or fetches it from a URL
+func readSeedPrompt(source string) (string, error) {
+	if !strings.HasPrefix(source, "http://") && !strings.HasPrefix(source, "https://") {
+		data, err := os.ReadFile(source)
+		if err != nil {
+			return "", fmt.Errorf("failed to read seed prompt: %w", err)
+		}
+		return string(data), nil
+	}
+
+	client := &http.Client{Timeout: seedPromptTimeout}
+	resp, err := client.Get(source)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch seed prompt: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to fetch seed prompt: %s returned status %d", source, resp.StatusCode)
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch seed prompt: %w", err)
+	}
+	return string(data), nil
+}
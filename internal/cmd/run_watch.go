@@ -0,0 +1,215 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"hermes/internal/scheduler"
+	"hermes/internal/task"
+)
+
+// defaultWatchDebounce is how long a file must stay unchanged before a
+// change restarts the loop
+const defaultWatchDebounce = 500 * time.Millisecond
+
+// watchSkipDirs are directories never watched, since they change constantly
+// or are not part of the project
+var watchSkipDirs = map[string]bool{
+	".git":              true,
+	"node_modules":      true,
+	".hermes/logs":      true,
+	".hermes/worktrees": true,
+}
+
+// fileWatcher reports changes to project files matching a pattern. Changes
+// are debounced, so a burst of writes is reported once.
+type fileWatcher struct {
+	root     string
+	matcher  *scheduler.IgnoreMatcher // Matches the watched files
+	files    map[string]bool          // When set, only these files count
+	debounce time.Duration
+	watcher  *fsnotify.Watcher
+	changes  chan string
+	done     chan struct{}
+	once     sync.Once
+}
+
+// newFileWatcher watches the files under root matching pattern, which uses
+// .hermesignore syntax (e.g. "*.go" or "src/**/*.ts"). A non-empty files
+// list restricts the watch to those paths relative to root.
+func newFileWatcher(root, pattern string, debounce time.Duration, files []string) (*fileWatcher, error) {
+	if strings.TrimSpace(pattern) == "" {
+		return nil, fmt.Errorf("empty watch pattern")
+	}
+	if debounce <= 0 {
+		return nil, fmt.Errorf("--watch-debounce must be positive")
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create file watcher: %w", err)
+	}
+
+	w := &fileWatcher{
+		root:     root,
+		matcher:  scheduler.ParseIgnore(pattern),
+		debounce: debounce,
+		watcher:  watcher,
+		changes:  make(chan string, 1),
+		done:     make(chan struct{}),
+	}
+	if len(files) > 0 {
+		w.files = make(map[string]bool)
+		for _, file := range files {
+			w.files[filepath.ToSlash(filepath.Clean(file))] = true
+		}
+	}
+
+	if err := w.addTree(root); err != nil {
+		watcher.Close()
+		return nil, err
+	}
+
+	go w.run()
+	return w, nil
+}
+
+// Changes returns the channel receiving the path of a changed file
+func (w *fileWatcher) Changes() <-chan string {
+	return w.changes
+}
+
+// Drain discards changes reported so far, e.g. the AI's own edits
+func (w *fileWatcher) Drain() {
+	select {
+	case <-w.changes:
+	default:
+	}
+}
+
+// WaitForChange blocks until a watched file changes and returns its path.
+// Changes reported before the call, such as the AI's edits while a task
+// ran, are discarded first.
+func (w *fileWatcher) WaitForChange(ctx context.Context) (string, error) {
+	w.Drain()
+	select {
+	case <-ctx.Done():
+		return "", ctx.Err()
+	case path := <-w.changes:
+		return path, nil
+	}
+}
+
+// Close stops watching
+func (w *fileWatcher) Close() error {
+	w.once.Do(func() { close(w.done) })
+	return w.watcher.Close()
+}
+
+// addTree watches dir and its subdirectories
+func (w *fileWatcher) addTree(dir string) error {
+	return filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			// Directories may disappear while walking
+			return nil
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		if rel, ok := w.relative(path); ok && watchSkipDirs[rel] {
+			return filepath.SkipDir
+		}
+		if err := w.watcher.Add(path); err != nil {
+			return fmt.Errorf("failed to watch %s: %w", path, err)
+		}
+		return nil
+	})
+}
+
+// run debounces matching events until Close
+func (w *fileWatcher) run() {
+	timer := time.NewTimer(w.debounce)
+	timer.Stop()
+	var pending string
+
+	for {
+		select {
+		case <-w.done:
+			timer.Stop()
+			return
+		case event, ok := <-w.watcher.Events:
+			if !ok {
+				return
+			}
+			if event.Has(fsnotify.Create) {
+				if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+					w.addTree(event.Name)
+					continue
+				}
+			}
+			if event.Has(fsnotify.Chmod) || !w.matches(event.Name) {
+				continue
+			}
+			pending, _ = w.relative(event.Name)
+			timer.Reset(w.debounce)
+		case <-timer.C:
+			select {
+			case w.changes <- pending:
+			default:
+				// A change is already waiting to be read
+			}
+		case _, ok := <-w.watcher.Errors:
+			if !ok {
+				return
+			}
+		}
+	}
+}
+
+// matches reports whether a changed path is one of the watched files
+func (w *fileWatcher) matches(path string) bool {
+	rel, ok := w.relative(path)
+	if !ok {
+		return false
+	}
+	if w.files != nil && !w.files[rel] {
+		return false
+	}
+	return w.matcher.Match(rel)
+}
+
+// relative returns path relative to the watched root with forward slashes
+func (w *fileWatcher) relative(path string) (string, bool) {
+	rel, err := filepath.Rel(w.root, path)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return "", false
+	}
+	return filepath.ToSlash(rel), true
+}
+
+// watchedTaskFiles returns the files to touch of the tasks matching the
+// filter, which limit --watch-files to the active feature. It returns nil
+// for an empty filter or when no task lists its files.
+func watchedTaskFiles(reader *task.Reader, filter *task.Filter) []string {
+	if filter.IsEmpty() {
+		return nil
+	}
+	tasks, err := reader.GetAllTasks()
+	if err != nil {
+		return nil
+	}
+
+	var files []string
+	for _, t := range tasks {
+		if filter.Match(t) {
+			files = append(files, t.FilesToTouch...)
+		}
+	}
+	return files
+}
@@ -1,17 +1,61 @@
 package cmd
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
 
 	"github.com/spf13/cobra"
 	"hermes/internal/circuit"
+	"hermes/internal/scheduler"
 	"hermes/internal/task"
 	"hermes/internal/ui"
 )
 
 type statusOptions struct {
-	filter   string
-	priority string
+	filter    string
+	priority  string
+	feature   string
+	watch     bool
+	interval  time.Duration
+	json      bool
+	compact   bool
+	archived  bool
+	since     time.Duration
+	changedBy string
+}
+
+// statusReport is the output of status --json
+type statusReport struct {
+	TotalTasks          int                   `json:"total_tasks"`
+	Completed           int                   `json:"completed"`
+	InProgress          int                   `json:"in_progress"`
+	NotStarted          int                   `json:"not_started"`
+	Blocked             int                   `json:"blocked"`
+	Percentage          float64               `json:"percentage"`
+	CircuitBreakerState string                `json:"circuit_breaker_state"`
+	CurrentTaskID       string                `json:"current_task_id"`
+	CurrentTaskName     string                `json:"current_task_name"`
+	Features            []featureStatusReport `json:"features"`
+}
+
+// featureStatusReport is the progress of one feature in a statusReport
+type featureStatusReport struct {
+	ID         string  `json:"id"`
+	Name       string  `json:"name"`
+	Status     string  `json:"status"`
+	TotalTasks int     `json:"total_tasks"`
+	Completed  int     `json:"completed"`
+	InProgress int     `json:"in_progress"`
+	NotStarted int     `json:"not_started"`
+	Blocked    int     `json:"blocked"`
+	Percentage float64 `json:"percentage"`
 }
 
 // NewStatusCmd creates the status subcommand
@@ -24,7 +68,12 @@ func NewStatusCmd() *cobra.Command {
 		Long:  "Display task progress table and statistics",
 		Example: `  hermes status
   hermes status --filter IN_PROGRESS
-  hermes status --priority P1`,
+  hermes status --priority P1
+  hermes status --feature F001
+  hermes status --watch --interval 5s
+  hermes status --since 24h
+  hermes status --changed-by 3f2b9c1e
+  hermes status --json | jq '.percentage >= 100'`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return statusExecute(opts)
 		},
@@ -32,21 +81,104 @@ func NewStatusCmd() *cobra.Command {
 
 	cmd.Flags().StringVar(&opts.filter, "filter", "", "Filter by status (NOT_STARTED, IN_PROGRESS, COMPLETED, BLOCKED)")
 	cmd.Flags().StringVar(&opts.priority, "priority", "", "Filter by priority (P1, P2, P3, P4)")
+	cmd.Flags().StringVar(&opts.feature, "feature", "", "Only show this feature's tasks and progress")
+	cmd.Flags().BoolVarP(&opts.watch, "watch", "w", false, "Re-render status periodically until all tasks complete")
+	cmd.Flags().DurationVar(&opts.interval, "interval", 2*time.Second, "Refresh interval for --watch")
+	cmd.Flags().BoolVar(&opts.json, "json", false, "Print progress as a JSON object")
+	cmd.Flags().BoolVar(&opts.compact, "compact", false, "Print the --json output on one line")
+	cmd.Flags().BoolVar(&opts.archived, "include-archived", false, "Include archived features")
+	cmd.Flags().DurationVar(&opts.since, "since", 0, "Only show tasks changed within this duration (e.g. 24h, 30m)")
+	cmd.Flags().StringVar(&opts.changedBy, "changed-by", "", "Only show tasks whose status was changed by this run session ID (or a prefix of it)")
 
 	return cmd
 }
 
 func statusExecute(opts *statusOptions) error {
-	reader := task.NewReader(".")
+	reader := newTaskReader(".")
+	reader.SetIncludeArchived(opts.archived)
+	if opts.feature != "" {
+		opts.feature = normalizeFeatureID(opts.feature)
+	}
+
+	if opts.json {
+		if opts.watch {
+			return fmt.Errorf("--json cannot be combined with --watch")
+		}
+		return statusJSON(reader, opts.feature, opts.compact, os.Stdout)
+	}
 
 	if !reader.HasTasks() {
 		fmt.Println("No tasks found. Run 'hermes prd <file>' to create tasks.")
 		return nil
 	}
 
-	tasks, err := reader.GetAllTasks()
+	if opts.watch {
+		return statusWatch(opts, reader)
+	}
+
+	_, err := statusRender(opts, reader)
+	return err
+}
+
+// statusWatch re-renders the status on every tick until interrupted or all tasks complete
+func statusWatch(opts *statusOptions, reader *task.Reader) error {
+	if opts.interval <= 0 {
+		return fmt.Errorf("interval must be positive")
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	ticker := time.NewTicker(opts.interval)
+	defer ticker.Stop()
+
+	for {
+		ui.ClearScreen()
+		fmt.Printf("Every %s: hermes status    %s\n", opts.interval, time.Now().Format("2006-01-02 15:04:05"))
+
+		progress, err := statusRender(opts, reader)
+		if err != nil {
+			return err
+		}
+
+		if next, _ := reader.GetNextTask(); next != nil {
+			fmt.Printf("\nNext task: %s - %s\n", next.ID, next.Name)
+		}
+
+		if progress.Total > 0 && progress.Completed == progress.Total {
+			fmt.Println("\nAll tasks completed.")
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			fmt.Println()
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// statusRender prints the task table, progress and circuit breaker state once
+func statusRender(opts *statusOptions, reader *task.Reader) (*task.Progress, error) {
+	features, err := reader.GetAllFeatures()
 	if err != nil {
-		return err
+		return nil, err
+	}
+	if opts.feature != "" {
+		feature, err := reader.GetFeatureByID(opts.feature)
+		if err != nil {
+			return nil, err
+		}
+		if feature == nil {
+			return nil, fmt.Errorf("feature %s not found", opts.feature)
+		}
+		features = []task.Feature{*feature}
+	}
+
+	var tasks []task.Task
+	for _, f := range features {
+		tasks = append(tasks, f.Tasks...)
 	}
 
 	// Apply filters
@@ -56,24 +188,187 @@ func statusExecute(opts *statusOptions) error {
 	if opts.priority != "" {
 		tasks = ui.FilterTasksByPriority(tasks, task.Priority(opts.priority))
 	}
+	if opts.since > 0 || opts.changedBy != "" {
+		if tasks, err = filterChangedTasks(tasks, features, opts.since, opts.changedBy, time.Now()); err != nil {
+			return nil, err
+		}
+	}
 
 	// Display table
 	ui.PrintTaskTable(tasks)
 
-	// Show progress
-	progress, err := reader.GetProgress()
-	if err != nil {
-		return err
+	// Show progress, overall and per feature
+	var progress *task.Progress
+	if opts.feature != "" {
+		progress = features[0].Progress()
+	} else if progress, err = reader.GetProgress(); err != nil {
+		return nil, err
 	}
 	ui.PrintProgress(progress)
+	ui.PrintFeatureProgress(features)
+
+	// Show how the last run executed
+	if info, err := scheduler.LoadRunInfo(scheduler.RunInfoPath(".")); err == nil && info != nil {
+		fmt.Printf("\nLast run: %s\n", formatRunInfo(info))
+	}
 
 	// Show circuit breaker status
 	breaker := circuit.New(".")
 	state, _ := breaker.GetState()
+	if state != nil && state.SessionID != "" {
+		fmt.Printf("Run session: %s\n", state.SessionID)
+	}
 	if state != nil && state.State != circuit.StateClosed {
 		fmt.Println()
 		breaker.PrintStatus()
+	} else if state != nil && state.TotalLoops > 0 {
+		fmt.Printf("\nTotal loops: %d\n", state.TotalLoops)
+	}
+
+	return progress, nil
+}
+
+// filterChangedTasks keeps the tasks changed within since of now and, if
+// changedBy is set, whose status was changed by that run session. A task's
+// last change is its last status change in the task history, or the
+// modification time of its feature file if it has none.
+func filterChangedTasks(tasks []task.Task, features []task.Feature, since time.Duration, changedBy string, now time.Time) ([]task.Task, error) {
+	history, err := task.LoadHistory(task.HistoryPath("."))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read task history: %w", err)
+	}
+	lastChanges := task.LastChanges(history)
+	changedInSession := task.ChangedBySession(history, changedBy)
+
+	featureFiles := make(map[string]string)
+	for _, f := range features {
+		featureFiles[f.ID] = f.FilePath
 	}
 
-	return nil
+	var filtered []task.Task
+	for _, t := range tasks {
+		if changedBy != "" && !changedInSession[t.ID] {
+			continue
+		}
+		if since > 0 {
+			changed, ok := lastChanges[t.ID]
+			if !ok {
+				info, err := os.Stat(featureFiles[t.FeatureID])
+				if err != nil {
+					continue
+				}
+				changed = info.ModTime()
+			}
+			if now.Sub(changed) > since {
+				continue
+			}
+		}
+		filtered = append(filtered, t)
+	}
+	return filtered, nil
+}
+
+// statusJSON writes the progress of all tasks and features as JSON, or of
+// only featureID if set. Other filters do not apply, so the totals can be
+// compared across runs.
+func statusJSON(reader *task.Reader, featureID string, compact bool, w io.Writer) error {
+	report := &statusReport{
+		CircuitBreakerState: string(circuit.StateClosed),
+		Features:            []featureStatusReport{},
+	}
+
+	if reader.HasTasks() {
+		features, err := reader.GetAllFeatures()
+		if err != nil {
+			return err
+		}
+		if featureID != "" {
+			var selected []task.Feature
+			for _, f := range features {
+				if f.ID == featureID {
+					selected = append(selected, f)
+				}
+			}
+			if len(selected) == 0 {
+				return fmt.Errorf("feature %s not found", featureID)
+			}
+			features = selected
+		}
+
+		var current *task.Task
+		for _, f := range features {
+			p := f.Progress()
+			fr := featureStatusReport{
+				ID:         f.ID,
+				Name:       f.Name,
+				Status:     string(f.Status),
+				TotalTasks: p.Total,
+				Completed:  p.Completed,
+				InProgress: p.InProgress,
+				NotStarted: p.NotStarted,
+				Blocked:    p.Blocked,
+				Percentage: p.Percentage,
+			}
+			for i, t := range f.Tasks {
+				if t.Status == task.StatusInProgress && current == nil {
+					current = &f.Tasks[i]
+				}
+			}
+			report.Features = append(report.Features, fr)
+
+			report.TotalTasks += fr.TotalTasks
+			report.Completed += fr.Completed
+			report.InProgress += fr.InProgress
+			report.NotStarted += fr.NotStarted
+			report.Blocked += fr.Blocked
+		}
+		if report.TotalTasks > 0 {
+			report.Percentage = float64(report.Completed) / float64(report.TotalTasks) * 100
+		}
+
+		if current == nil && featureID == "" {
+			current, _ = reader.GetNextTask()
+		}
+		if current != nil {
+			report.CurrentTaskID = current.ID
+			report.CurrentTaskName = current.Name
+		}
+	}
+
+	if state, _ := circuit.New(".").GetState(); state != nil && state.State != "" {
+		report.CircuitBreakerState = string(state.State)
+	}
+
+	var data []byte
+	var err error
+	if compact {
+		data, err = json.Marshal(report)
+	} else {
+		data, err = json.MarshalIndent(report, "", "  ")
+	}
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(w, string(data))
+	return err
+}
+
+// formatRunInfo describes a run, e.g. "parallel (auto, 3 workers), started 2026-01-02 15:04"
+func formatRunInfo(info *scheduler.RunInfo) string {
+	var details []string
+	if info.Requested != "" && info.Requested != info.Strategy {
+		details = append(details, info.Requested)
+	}
+	if info.Workers > 0 {
+		details = append(details, fmt.Sprintf("%d workers", info.Workers))
+	}
+
+	result := info.Strategy
+	if len(details) > 0 {
+		result += " (" + strings.Join(details, ", ") + ")"
+	}
+	if !info.StartedAt.IsZero() {
+		result += ", started " + info.StartedAt.Format("2006-01-02 15:04")
+	}
+	return result
 }
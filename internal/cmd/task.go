@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/fatih/color"
@@ -18,17 +19,38 @@ func NewTaskCmd() *cobra.Command {
 		Args:  cobra.ExactArgs(1),
 		RunE:  runTask,
 	}
+
+	cmd.AddCommand(newTaskShowCmd())
+	cmd.AddCommand(newTaskListCmd())
+	cmd.AddCommand(newTaskEditCmd())
+	cmd.AddCommand(newTaskBlockCmd())
+	cmd.AddCommand(newTaskUnblockCmd())
+	cmd.AddCommand(newTaskCompleteCmd())
+	cmd.AddCommand(newTaskPriorityCmd())
+	cmd.AddCommand(newTaskMoveCmd())
+	cmd.AddCommand(newTaskTemplateCmd())
+	cmd.AddCommand(newTaskDepsCmd())
+	cmd.AddCommand(newTaskSortCmd())
+	cmd.AddCommand(newTaskImportCmd())
+	cmd.AddCommand(newTaskArchiveCmd())
+	cmd.AddCommand(newTaskUnarchiveCmd())
+
 	return cmd
 }
 
-func runTask(cmd *cobra.Command, args []string) error {
-	taskID := strings.ToUpper(args[0])
-	if !strings.HasPrefix(taskID, "T") {
-		// Pad with zeros if numeric (1 -> T001, 12 -> T012)
-		taskID = fmt.Sprintf("T%03s", taskID)
+func newTaskShowCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "show <id>",
+		Short: "Show task details",
+		Args:  cobra.ExactArgs(1),
+		RunE:  runTask,
 	}
+}
+
+func runTask(cmd *cobra.Command, args []string) error {
+	taskID := normalizeTaskID(args[0])
 
-	reader := task.NewReader(".")
+	reader := newTaskReader(".")
 	tasks, err := reader.GetAllTasks()
 	if err != nil {
 		return fmt.Errorf("failed to read tasks: %w", err)
@@ -81,6 +103,12 @@ func runTask(cmd *cobra.Command, args []string) error {
 	}
 	
 	fmt.Printf("Feature:  %s\n", found.FeatureID)
+	if found.BlockedReason != "" {
+		fmt.Printf("Blocked:  %s\n", found.BlockedReason)
+	}
+	if found.CompletionNote != "" {
+		fmt.Printf("Note:     %s\n", found.CompletionNote)
+	}
 	
 	// Files
 	if len(found.FilesToTouch) > 0 {
@@ -91,12 +119,29 @@ func runTask(cmd *cobra.Command, args []string) error {
 		}
 	}
 	
-	// Dependencies
-	if len(found.Dependencies) > 0 {
+	// Dependencies, direct ones first and the rest in parentheses
+	ancestors, err := reader.GetDependencyTree(found.ID)
+	if err != nil {
+		return err
+	}
+	dependents, err := reader.GetDependents(found.ID)
+	if err != nil {
+		return err
+	}
+	var requiredBy []string
+	for id, t := range dependents {
+		if containsString(t.DependencyIDs(), found.ID) {
+			requiredBy = append(requiredBy, id)
+		}
+	}
+	if len(ancestors) > 0 || len(dependents) > 0 {
 		fmt.Println()
 		cyan.Println("Dependencies:")
-		for _, d := range found.Dependencies {
-			fmt.Printf("  - %s\n", d)
+		if len(ancestors) > 0 {
+			fmt.Printf("  Depends on:  %s\n", formatDependencyIDs(found.DependencyIDs(), ancestors))
+		}
+		if len(dependents) > 0 {
+			fmt.Printf("  Required by: %s\n", formatDependencyIDs(requiredBy, dependents))
 		}
 	}
 	
@@ -112,3 +157,39 @@ func runTask(cmd *cobra.Command, args []string) error {
 	fmt.Println()
 	return nil
 }
+
+// formatDependencyIDs formats the direct IDs followed by the other tasks in
+// all, e.g. "T001, T002 (transitively: T000)"
+func formatDependencyIDs(direct []string, all map[string]*task.Task) string {
+	var shown, indirect []string
+	for _, id := range direct {
+		if all[id] != nil {
+			shown = append(shown, id)
+		}
+	}
+	for id := range all {
+		if !containsString(shown, id) {
+			indirect = append(indirect, id)
+		}
+	}
+	sort.Strings(shown)
+	sort.Strings(indirect)
+
+	result := strings.Join(shown, ", ")
+	if len(indirect) > 0 {
+		if result != "" {
+			result += " "
+		}
+		result += fmt.Sprintf("(transitively: %s)", strings.Join(indirect, ", "))
+	}
+	return result
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
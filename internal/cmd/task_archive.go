@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"hermes/internal/config"
+	"hermes/internal/task"
+)
+
+// newTaskArchiveCmd creates the task archive subcommand
+func newTaskArchiveCmd() *cobra.Command {
+	var force bool
+
+	cmd := &cobra.Command{
+		Use:   "archive <feature-id>",
+		Short: "Move a completed feature out of active tracking",
+		Long: `Move a feature's file to .hermes/archive/YYYY-MM/ and record the archive date
+in .hermes/config.json. Archived features are left out of 'hermes status',
+'hermes task list' and runs; use --include-archived to list them.
+
+Dependencies on completed archived tasks still count as met, and their IDs are
+not given to new features or tasks. Only completed features are archived
+unless --force is given.`,
+		Example: `  hermes task archive F001
+  hermes task archive 3 --force`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return taskArchiveExecute(normalizeFeatureID(args[0]), force, time.Now(), os.Stdout)
+		},
+	}
+
+	cmd.Flags().BoolVar(&force, "force", false, "Archive the feature even if it has unfinished tasks")
+
+	return cmd
+}
+
+// newTaskUnarchiveCmd creates the task unarchive subcommand
+func newTaskUnarchiveCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:     "unarchive <feature-id>",
+		Short:   "Restore an archived feature",
+		Long:    "Move an archived feature's file back to .hermes/tasks/ and remove its archive record.",
+		Example: `  hermes task unarchive F001`,
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return taskUnarchiveExecute(normalizeFeatureID(args[0]), os.Stdout)
+		},
+	}
+}
+
+func taskArchiveExecute(featureID string, force bool, now time.Time, w io.Writer) error {
+	feature, err := newTaskReader(".").GetFeatureByID(featureID)
+	if err != nil {
+		return fmt.Errorf("failed to read tasks: %w", err)
+	}
+	if feature == nil {
+		return fmt.Errorf("feature %s not found", featureID)
+	}
+	if progress := feature.Progress(); progress.Completed < progress.Total && !force {
+		return fmt.Errorf("feature %s has %d unfinished task(s) (use --force to archive it anyway)", featureID, progress.Total-progress.Completed)
+	}
+
+	from, to, err := task.NewWriter(".").ArchiveFeature(featureID, now)
+	if err != nil {
+		return fmt.Errorf("failed to archive feature: %w", err)
+	}
+	if err := config.SaveArchivedFeature(config.ProjectPath("."), config.ArchivedFeature{
+		FeatureID:  featureID,
+		File:       filepath.ToSlash(to),
+		ArchivedAt: now.Format("2006-01-02"),
+	}); err != nil {
+		return fmt.Errorf("feature archived to %s, but failed to record it: %w", to, err)
+	}
+
+	bold := color.New(color.Bold)
+	bold.Fprintf(w, "%s: ", featureID)
+	fmt.Fprintf(w, "archived %s\n", feature.Name)
+	fmt.Fprintf(w, "  From: %s\n", from)
+	fmt.Fprintf(w, "  To:   %s\n", to)
+	return nil
+}
+
+func taskUnarchiveExecute(featureID string, w io.Writer) error {
+	from, to, err := task.NewWriter(".").UnarchiveFeature(featureID)
+	if err != nil {
+		return fmt.Errorf("failed to unarchive feature: %w", err)
+	}
+	if err := config.RemoveArchivedFeature(config.ProjectPath("."), featureID); err != nil {
+		return fmt.Errorf("feature restored to %s, but failed to update its archive record: %w", to, err)
+	}
+
+	bold := color.New(color.Bold)
+	bold.Fprintf(w, "%s: ", featureID)
+	fmt.Fprintln(w, "restored")
+	fmt.Fprintf(w, "  From: %s\n", from)
+	fmt.Fprintf(w, "  To:   %s\n", to)
+	return nil
+}
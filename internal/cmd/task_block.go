@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"hermes/internal/task"
+)
+
+// newTaskBlockCmd creates the task block subcommand
+func newTaskBlockCmd() *cobra.Command {
+	var reason string
+
+	cmd := &cobra.Command{
+		Use:   "block <id>",
+		Short: "Mark a task as BLOCKED",
+		Long:  "Set a task's status to BLOCKED and record why. Blocked tasks are skipped by 'hermes run'.",
+		Example: `  hermes task block T003 --reason "Waiting for API credentials"
+  hermes task block 3 -r "Upstream bug"`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if strings.TrimSpace(reason) == "" {
+				return fmt.Errorf("--reason is required")
+			}
+			return taskBlockExecute(normalizeTaskID(args[0]), reason)
+		},
+	}
+
+	cmd.Flags().StringVarP(&reason, "reason", "r", "", "Why the task is blocked (required)")
+
+	return cmd
+}
+
+// newTaskUnblockCmd creates the task unblock subcommand
+func newTaskUnblockCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:     "unblock <id>",
+		Short:   "Clear a task's block and reset it to NOT_STARTED",
+		Example: `  hermes task unblock T003`,
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return taskUnblockExecute(normalizeTaskID(args[0]))
+		},
+	}
+}
+
+func taskBlockExecute(taskID, reason string) error {
+	before, err := findTask(taskID)
+	if err != nil {
+		return err
+	}
+	if before.Status == task.StatusCompleted {
+		return fmt.Errorf("task %s is already completed", taskID)
+	}
+
+	if err := task.NewStatusUpdater(".").BlockTask(taskID, reason); err != nil {
+		return fmt.Errorf("failed to block task: %w", err)
+	}
+
+	after, err := findTask(taskID)
+	if err != nil {
+		return err
+	}
+
+	printStatusChange(taskID, before.Status, after.Status)
+	fmt.Printf("  Reason: %s\n", after.BlockedReason)
+	return nil
+}
+
+func taskUnblockExecute(taskID string) error {
+	before, err := findTask(taskID)
+	if err != nil {
+		return err
+	}
+	if !before.IsBlocked() {
+		return fmt.Errorf("task %s is not blocked", taskID)
+	}
+
+	if err := task.NewStatusUpdater(".").UnblockTask(taskID); err != nil {
+		return fmt.Errorf("failed to unblock task: %w", err)
+	}
+
+	printStatusChange(taskID, before.Status, task.StatusNotStarted)
+	if before.BlockedReason != "" {
+		fmt.Printf("  Cleared reason: %s\n", before.BlockedReason)
+	}
+	return nil
+}
+
+// findTask returns the task with the given ID
+func findTask(taskID string) (*task.Task, error) {
+	tasks, err := newTaskReader(".").GetAllTasks()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tasks: %w", err)
+	}
+
+	for i := range tasks {
+		if tasks[i].ID == taskID {
+			return &tasks[i], nil
+		}
+	}
+	return nil, fmt.Errorf("task %s not found", taskID)
+}
+
+func printStatusChange(taskID string, from, to task.Status) {
+	bold := color.New(color.Bold)
+	bold.Printf("%s: ", taskID)
+	fmt.Printf("%s -> %s\n", from, to)
+}
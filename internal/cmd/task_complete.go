@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"hermes/internal/circuit"
+	"hermes/internal/git"
+	"hermes/internal/prompt"
+	"hermes/internal/task"
+)
+
+// newTaskCompleteCmd creates the task complete subcommand
+func newTaskCompleteCmd() *cobra.Command {
+	var message string
+	var all, autoCommit bool
+
+	cmd := &cobra.Command{
+		Use:   "complete [id]",
+		Short: "Mark a task as COMPLETED by hand",
+		Long: `Mark a task finished outside of 'hermes run' as COMPLETED.
+
+The task is removed from PROMPT.md if it is the current task there, and the
+circuit breaker records the completion as progress. --message is kept in the
+task file as the task's Completion Note.`,
+		Example: `  hermes task complete T003 --message "Finished by hand, needed VPN access"
+  hermes task complete T003 --auto-commit
+  hermes task complete --all`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if all == (len(args) == 1) {
+				return fmt.Errorf("give either a task ID or --all")
+			}
+			var ids []string
+			if len(args) == 1 {
+				ids = []string{normalizeTaskID(args[0])}
+			}
+			return taskCompleteExecute(".", ids, message, autoCommit)
+		},
+	}
+
+	cmd.Flags().StringVarP(&message, "message", "m", "", "Completion note recorded in the task")
+	cmd.Flags().BoolVar(&all, "all", false, "Complete all IN_PROGRESS tasks")
+	cmd.Flags().BoolVar(&autoCommit, "auto-commit", false, "Commit all changes afterwards")
+
+	return cmd
+}
+
+// taskCompleteExecute completes the given tasks, or all IN_PROGRESS tasks
+// if ids is empty
+func taskCompleteExecute(basePath string, ids []string, message string, autoCommit bool) error {
+	reader := newTaskReader(basePath)
+	var tasks []task.Task
+	if len(ids) == 0 {
+		inProgress, err := reader.GetTasksByStatus(task.StatusInProgress)
+		if err != nil {
+			return fmt.Errorf("failed to read tasks: %w", err)
+		}
+		if len(inProgress) == 0 {
+			fmt.Println("No IN_PROGRESS tasks to complete")
+			return nil
+		}
+		tasks = inProgress
+	} else {
+		for _, id := range ids {
+			t, err := reader.GetTaskByID(id)
+			if err != nil {
+				return fmt.Errorf("failed to read tasks: %w", err)
+			}
+			if t == nil {
+				return fmt.Errorf("task %s not found", id)
+			}
+			if t.Status == task.StatusCompleted {
+				return fmt.Errorf("task %s is already completed", id)
+			}
+			tasks = append(tasks, *t)
+		}
+	}
+
+	updater := task.NewStatusUpdater(basePath)
+	injector := prompt.NewInjector(basePath)
+	var completed []string
+	for _, t := range tasks {
+		if err := updater.CompleteTask(t.ID, message); err != nil {
+			return fmt.Errorf("failed to complete %s: %w", t.ID, err)
+		}
+		if current, _ := injector.GetCurrentTaskID(t.FeatureID); current == t.ID {
+			if err := injector.RemoveTask(t.FeatureID); err != nil {
+				return fmt.Errorf("failed to remove %s from the prompt: %w", t.ID, err)
+			}
+		}
+		printStatusChange(t.ID, t.Status, task.StatusCompleted)
+		completed = append(completed, t.ID)
+	}
+
+	// A finished task is progress, so a breaker opened by the stuck task closes
+	breaker := circuit.New(basePath)
+	state, err := breaker.GetState()
+	if err == nil {
+		_, err = breaker.AddLoopResult(true, false, state.CurrentLoop)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update circuit breaker: %w", err)
+	}
+
+	if autoCommit {
+		return commitCompletedTasks(git.New(basePath, ""), tasks)
+	}
+	return nil
+}
+
+// commitCompletedTasks commits all changes for manually completed tasks
+func commitCompletedTasks(g *git.Git, tasks []task.Task) error {
+	if !g.IsRepository() || !g.HasUncommittedChanges() {
+		fmt.Println("Nothing to commit")
+		return nil
+	}
+
+	return g.WithLock(func(g *git.Git) error {
+		if err := g.StageAll(); err != nil {
+			return fmt.Errorf("failed to stage changes: %w", err)
+		}
+		var err error
+		if len(tasks) == 1 {
+			err = g.CommitTask(tasks[0].ID, tasks[0].Name)
+		} else {
+			ids := make([]string, len(tasks))
+			for i, t := range tasks {
+				ids[i] = t.ID
+			}
+			err = g.Commit(fmt.Sprintf("feat(%s): complete %d tasks", strings.Join(ids, ", "), len(tasks)))
+		}
+		if err != nil {
+			return fmt.Errorf("failed to commit: %w", err)
+		}
+		fmt.Println("Committed the changes")
+		return nil
+	})
+}
@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"hermes/internal/task"
+)
+
+func newTaskDepsCmd() *cobra.Command {
+	var blockingOnly bool
+
+	cmd := &cobra.Command{
+		Use:   "deps <id>",
+		Short: "Show the dependency tree of a task",
+		Long: `Show every task the given task depends on, directly or transitively, as a
+tree with the status of each task.
+
+Exits with code 1 if any dependency is not completed, so it can be used as a
+CI gate.`,
+		Example: `  hermes task deps T005
+  hermes task deps T005 --blocking-only`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return taskDepsExecute(newTaskReader("."), normalizeTaskID(args[0]), blockingOnly, os.Stdout)
+		},
+	}
+
+	cmd.Flags().BoolVar(&blockingOnly, "blocking-only", false, "Only show dependencies that are not completed")
+
+	return cmd
+}
+
+func taskDepsExecute(reader *task.Reader, taskID string, blockingOnly bool, w io.Writer) error {
+	root, err := reader.GetTaskByID(taskID)
+	if err != nil {
+		return fmt.Errorf("failed to read tasks: %w", err)
+	}
+	if root == nil {
+		return fmt.Errorf("task %s not found", taskID)
+	}
+	deps, err := reader.GetDependencyTree(taskID)
+	if err != nil {
+		return err
+	}
+
+	for _, line := range formatDependencyTree(root, deps, blockingOnly) {
+		fmt.Fprintln(w, line)
+	}
+
+	var blocking []string
+	for id, t := range deps {
+		if t.Status != task.StatusCompleted {
+			blocking = append(blocking, id)
+		}
+	}
+	if len(blocking) > 0 {
+		sort.Strings(blocking)
+		return fmt.Errorf("%s has %d uncompleted dependencies: %s", taskID, len(blocking), strings.Join(blocking, ", "))
+	}
+	return nil
+}
+
+// formatDependencyTree returns the lines of a tree of root's dependencies,
+// looked up in deps. A dependency that appears on its own path (a cycle) is
+// shown but not expanded again.
+func formatDependencyTree(root *task.Task, deps map[string]*task.Task, blockingOnly bool) []string {
+	lines := []string{formatTreeTask(root)}
+
+	var walk func(t *task.Task, depth int, path map[string]bool)
+	walk = func(t *task.Task, depth int, path map[string]bool) {
+		ids := append([]string(nil), t.DependencyIDs()...)
+		sort.Strings(ids)
+		for _, id := range ids {
+			dep := deps[id]
+			if dep == nil || (blockingOnly && dep.Status == task.StatusCompleted) {
+				continue
+			}
+			prefix := "  " + strings.Repeat("      ", depth) + "└── "
+			if path[id] {
+				lines = append(lines, prefix+formatTreeTask(dep)+" (cycle)")
+				continue
+			}
+			lines = append(lines, prefix+formatTreeTask(dep))
+			path[id] = true
+			walk(dep, depth+1, path)
+			delete(path, id)
+		}
+	}
+	walk(root, 0, map[string]bool{root.ID: true})
+
+	return lines
+}
+
+// formatTreeTask formats a task as "T001 (STATUS)" with a coloured status
+// and an icon for completed, running and blocked tasks
+func formatTreeTask(t *task.Task) string {
+	status := fmt.Sprintf("(%s)", t.Status)
+	switch t.Status {
+	case task.StatusCompleted:
+		return t.ID + " " + color.GreenString(status) + " ✓"
+	case task.StatusInProgress:
+		return t.ID + " " + color.YellowString(status) + " ⟳"
+	case task.StatusBlocked:
+		return t.ID + " " + color.RedString(status) + " ✗"
+	default:
+		return t.ID + " " + status
+	}
+}
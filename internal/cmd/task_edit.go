@@ -0,0 +1,168 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"hermes/internal/task"
+)
+
+// newTaskEditCmd creates the task edit subcommand
+func newTaskEditCmd() *cobra.Command {
+	var fields []string
+
+	cmd := &cobra.Command{
+		Use:   "edit <id>",
+		Short: "Edit a task in $EDITOR or via --field",
+		Long: `Open the task's section of its feature file in $EDITOR (falls back to nano).
+After the editor exits the task is re-parsed and validated before being saved.
+
+Use --field key=value for non-interactive edits. Supported keys:
+  name, status, priority, effort, description, details, files, deps, criteria`,
+		Example: `  hermes task edit T003
+  hermes task edit 3 --field status=IN_PROGRESS
+  hermes task edit T003 --field priority=P1 --field deps=T001,T002`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return taskEditExecute(normalizeTaskID(args[0]), fields)
+		},
+	}
+
+	cmd.Flags().StringArrayVar(&fields, "field", nil, "Set a field non-interactively (key=value, repeatable)")
+
+	return cmd
+}
+
+func taskEditExecute(taskID string, fields []string) error {
+	reader := newTaskReader(".")
+	allTasks, err := reader.GetAllTasks()
+	if err != nil {
+		return fmt.Errorf("failed to read tasks: %w", err)
+	}
+
+	var original *task.Task
+	for i := range allTasks {
+		if allTasks[i].ID == taskID {
+			original = &allTasks[i]
+			break
+		}
+	}
+	if original == nil {
+		return fmt.Errorf("task %s not found", taskID)
+	}
+
+	writer := task.NewWriter(".")
+	var updated *task.Task
+
+	if len(fields) > 0 {
+		edited := *original
+		for _, field := range fields {
+			key, value, ok := strings.Cut(field, "=")
+			if !ok {
+				return fmt.Errorf("invalid field %q (expected key=value)", field)
+			}
+			if err := task.ApplyField(&edited, key, value); err != nil {
+				return err
+			}
+		}
+
+		if err := task.ValidateTask(&edited, allTasks); err != nil {
+			return fmt.Errorf("validation failed: %w", err)
+		}
+		if err := writer.WriteTask(&edited); err != nil {
+			return fmt.Errorf("failed to write task: %w", err)
+		}
+		updated = &edited
+	} else {
+		section, _, err := writer.ReadTaskSection(taskID)
+		if err != nil {
+			return err
+		}
+
+		editedSection, err := editInEditor(taskID, section)
+		if err != nil {
+			return err
+		}
+		if editedSection == section {
+			fmt.Println("No changes made.")
+			return nil
+		}
+
+		edited, err := task.ParseTaskSection(editedSection, original.FeatureID)
+		if err != nil {
+			return fmt.Errorf("failed to parse edited task: %w", err)
+		}
+		if edited.ID != taskID {
+			return fmt.Errorf("task ID cannot be changed (%s -> %s)", taskID, edited.ID)
+		}
+		if err := task.ValidateTask(edited, allTasks); err != nil {
+			return fmt.Errorf("validation failed: %w", err)
+		}
+		if err := writer.ReplaceTaskSection(taskID, editedSection); err != nil {
+			return fmt.Errorf("failed to write task: %w", err)
+		}
+		updated = edited
+	}
+
+	printTaskDiff(taskID, task.DiffTasks(original, updated))
+	return nil
+}
+
+// editInEditor opens content in the user's editor and returns the edited result
+func editInEditor(taskID, content string) (string, error) {
+	tmpFile, err := os.CreateTemp("", "hermes-"+taskID+"-*.md")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temp file: %w", err)
+	}
+	defer os.Remove(tmpFile.Name())
+
+	if _, err := tmpFile.WriteString(content); err != nil {
+		tmpFile.Close()
+		return "", fmt.Errorf("failed to write temp file: %w", err)
+	}
+	tmpFile.Close()
+
+	editor := os.Getenv("EDITOR")
+	if editor == "" {
+		editor = "nano"
+	}
+	parts := strings.Fields(editor)
+
+	editorCmd := exec.Command(parts[0], append(parts[1:], tmpFile.Name())...)
+	editorCmd.Stdin = os.Stdin
+	editorCmd.Stdout = os.Stdout
+	editorCmd.Stderr = os.Stderr
+	if err := editorCmd.Run(); err != nil {
+		return "", fmt.Errorf("editor failed: %w", err)
+	}
+
+	edited, err := os.ReadFile(tmpFile.Name())
+	if err != nil {
+		return "", fmt.Errorf("failed to read edited file: %w", err)
+	}
+
+	return string(edited), nil
+}
+
+func printTaskDiff(taskID string, changes []task.FieldChange) {
+	if len(changes) == 0 {
+		fmt.Printf("Task %s saved (no field changes).\n", taskID)
+		return
+	}
+
+	bold := color.New(color.Bold)
+	red := color.New(color.FgRed)
+	green := color.New(color.FgGreen)
+
+	fmt.Println()
+	bold.Printf("Updated %s:\n", taskID)
+	for _, c := range changes {
+		fmt.Printf("  %s:\n", c.Field)
+		red.Printf("    - %s\n", c.Old)
+		green.Printf("    + %s\n", c.New)
+	}
+}
@@ -0,0 +1,383 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"hermes/internal/analyzer"
+	"hermes/internal/jira"
+	"hermes/internal/task"
+)
+
+// noEpicFeature is the feature name for JIRA issues without an epic
+const noEpicFeature = "No Epic"
+
+// importSources lists the formats 'hermes task import' reads
+var importSources = []string{"jira"}
+
+// newTaskImportCmd creates the task import subcommand
+func newTaskImportCmd() *cobra.Command {
+	var from string
+
+	cmd := &cobra.Command{
+		Use:   "import --from jira <file>",
+		Short: "Import tasks from another tracker's export",
+		Long: `Create feature files from an export of another issue tracker.
+
+JIRA (--from jira) reads the JSON of the search API or a JIRA export with an
+"issues" array. Each epic becomes a feature and its stories, tasks and bugs
+become tasks. Issues without an epic go to a "No Epic" feature. Sub-tasks
+become success criteria of their parent task. "Blocks" and "depends on"
+links become task dependencies, story points the estimated effort
+("SP: 5"), and JIRA priorities and statuses are mapped to Hermes ones.`,
+		Example: `  hermes task import --from jira jira-export.json`,
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return taskImportExecute(".", from, args[0], os.Stdout)
+		},
+	}
+
+	cmd.Flags().StringVar(&from, "from", "", "Format of the export: "+strings.Join(importSources, ", "))
+	cmd.MarkFlagRequired("from")
+
+	return cmd
+}
+
+func taskImportExecute(projectPath, from, file string, w io.Writer) error {
+	if from != "jira" {
+		return fmt.Errorf("unsupported import source %q (supported: %s)", from, strings.Join(importSources, ", "))
+	}
+
+	issues, err := jira.ReadExport(file)
+	if err != nil {
+		return err
+	}
+	if len(issues) == 0 {
+		fmt.Fprintf(w, "  No issues found in %s\n", file)
+		return nil
+	}
+
+	nextFeatureID, nextTaskID, err := analyzer.NewFeatureAnalyzer(projectPath).GetNextIDs()
+	if err != nil {
+		nextFeatureID = 1
+		nextTaskID = 1
+	}
+
+	features, skipped := buildJiraFeatures(issues, nextFeatureID, nextTaskID)
+
+	tasksDir := filepath.Join(projectPath, ".hermes", "tasks")
+	if err := os.MkdirAll(tasksDir, 0755); err != nil {
+		return err
+	}
+	imported := 0
+	for _, f := range features {
+		fileName := featureFileName(f.id, f.name)
+		if err := os.WriteFile(filepath.Join(tasksDir, fileName), []byte(formatJiraFeature(f)), 0644); err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "  Created: .hermes/tasks/%s (%d tasks)\n", fileName, len(f.tasks))
+		imported += len(f.tasks)
+	}
+
+	if skipped > 0 {
+		fmt.Fprintf(w, "  Skipped: %d dependency link(s) to issues not in the export\n", skipped)
+	}
+	fmt.Fprintf(w, "  Imported: %d tasks in %d features from %s\n", imported, len(features), file)
+	return nil
+}
+
+// jiraFeature is a feature made from a JIRA epic, or from the issues
+// without one
+type jiraFeature struct {
+	id    int
+	name  string
+	epic  *jira.Issue // nil for noEpicFeature
+	tasks []*task.Task
+}
+
+// buildJiraFeatures groups the issues into features by epic, in the order
+// the epics first appear, followed by the issues without an epic. Tasks
+// are numbered from firstTaskID. It also returns the number of dependency
+// links to issues that are not in the export.
+func buildJiraFeatures(issues []jira.Issue, firstFeatureID, firstTaskID int) ([]*jiraFeature, int) {
+	// Sub-tasks may be nested in their parent, listed on their own, or both
+	byKey := make(map[string]*jira.Issue)
+	var all []*jira.Issue
+	add := func(issue *jira.Issue) {
+		if byKey[issue.Key] == nil {
+			byKey[issue.Key] = issue
+			all = append(all, issue)
+		}
+	}
+	for i := range issues {
+		add(&issues[i])
+	}
+	for i := range issues {
+		for j := range issues[i].Subtasks {
+			add(&issues[i].Subtasks[j])
+		}
+	}
+
+	// A sub-task whose parent is not in the export is imported as a task
+	subtasks := make(map[string][]*jira.Issue)
+	var taskIssues []*jira.Issue
+	for _, issue := range all {
+		switch {
+		case issue.IsEpic():
+		case issue.IsSubtask() && byKey[issue.Parent] != nil && !byKey[issue.Parent].IsEpic():
+			subtasks[issue.Parent] = append(subtasks[issue.Parent], issue)
+		default:
+			taskIssues = append(taskIssues, issue)
+		}
+	}
+
+	var features []*jiraFeature
+	byEpic := make(map[string]*jiraFeature)
+	featureFor := func(epicKey string) *jiraFeature {
+		if f := byEpic[epicKey]; f != nil {
+			return f
+		}
+		f := &jiraFeature{name: noEpicFeature}
+		if epicKey != "" {
+			// An epic that is not in the export is named by its key
+			f.name = epicKey
+			if epic := byKey[epicKey]; epic != nil {
+				f.epic = epic
+				if epic.Summary != "" {
+					f.name = epic.Summary
+				}
+			}
+		}
+		byEpic[epicKey] = f
+		features = append(features, f)
+		return f
+	}
+	// Epics come first, in export order, so epics without issues get a
+	// feature too
+	for _, issue := range all {
+		if issue.IsEpic() {
+			featureFor(issue.Key)
+		}
+	}
+
+	assigned := make(map[*jiraFeature][]*jira.Issue)
+	for _, issue := range taskIssues {
+		f := featureFor(jiraEpicKey(issue, byKey))
+		assigned[f] = append(assigned[f], issue)
+	}
+	// Issues without an epic go last
+	if f := byEpic[""]; f != nil {
+		for i, g := range features {
+			if g == f {
+				features = append(append(features[:i:i], features[i+1:]...), f)
+				break
+			}
+		}
+	}
+
+	taskIDs := make(map[string]string)
+	issueOf := make(map[*task.Task]*jira.Issue)
+	nextTaskID := firstTaskID
+	for i, f := range features {
+		f.id = firstFeatureID + i
+		for _, issue := range assigned[f] {
+			t := jiraTask(issue, subtasks[issue.Key])
+			t.ID = fmt.Sprintf("T%03d", nextTaskID)
+			t.FeatureID = fmt.Sprintf("F%03d", f.id)
+			nextTaskID++
+			taskIDs[issue.Key] = t.ID
+			for _, sub := range subtasks[issue.Key] {
+				taskIDs[sub.Key] = t.ID
+			}
+			issueOf[t] = issue
+			f.tasks = append(f.tasks, t)
+		}
+	}
+
+	// Links of a task and its sub-tasks become dependencies, in either
+	// direction
+	deps := make(map[string]map[string]bool)
+	addDep := func(from, to string) {
+		if from == to {
+			return
+		}
+		if deps[from] == nil {
+			deps[from] = make(map[string]bool)
+		}
+		deps[from][to] = true
+	}
+	skipped := 0
+	for _, f := range features {
+		for _, t := range f.tasks {
+			issue := issueOf[t]
+			for _, source := range append([]*jira.Issue{issue}, subtasks[issue.Key]...) {
+				for _, link := range source.Links {
+					dependsOn, blocks := link.Dependency()
+					if !dependsOn && !blocks {
+						continue
+					}
+					other, ok := taskIDs[link.Key]
+					if !ok {
+						skipped++
+						continue
+					}
+					if dependsOn {
+						addDep(t.ID, other)
+					} else {
+						addDep(other, t.ID)
+					}
+				}
+			}
+		}
+	}
+	for _, f := range features {
+		for _, t := range f.tasks {
+			for id := range deps[t.ID] {
+				t.Dependencies = append(t.Dependencies, id)
+			}
+			sort.Strings(t.Dependencies)
+			t.DependsOn = t.Dependencies
+		}
+	}
+
+	return features, skipped
+}
+
+// jiraEpicKey returns the epic of an issue from its epic link, or its
+// parent if that is an epic
+func jiraEpicKey(issue *jira.Issue, byKey map[string]*jira.Issue) string {
+	if issue.Epic != "" {
+		return issue.Epic
+	}
+	if issue.Parent == "" {
+		return ""
+	}
+	if parent := byKey[issue.Parent]; parent != nil {
+		if parent.IsEpic() {
+			return parent.Key
+		}
+		// A sub-task imported as a task belongs to the epic of its parent
+		return jiraEpicKey(parent, byKey)
+	}
+	if strings.EqualFold(issue.ParentType, "Epic") {
+		return issue.Parent
+	}
+	return ""
+}
+
+// jiraTask converts an issue and its sub-tasks to a task without an ID
+func jiraTask(issue *jira.Issue, subtasks []*jira.Issue) *task.Task {
+	t := &task.Task{
+		Name:             issue.Summary,
+		Status:           jiraStatus(issue),
+		Priority:         jiraPriority(issue.Priority),
+		Description:      issue.Description,
+		TechnicalDetails: fmt.Sprintf("JIRA %s %s", strings.ToLower(issue.Type), issue.Key),
+	}
+	if t.Name == "" {
+		t.Name = issue.Key
+	}
+	if t.Description == "" {
+		t.Description = t.Name
+	}
+	if issue.Type == "" {
+		t.TechnicalDetails = "JIRA issue " + issue.Key
+	}
+
+	// Sub-task estimates add up to the parent's when it has none
+	points := issue.StoryPoints
+	for _, sub := range subtasks {
+		criterion := fmt.Sprintf("%s: %s", sub.Key, sub.Summary)
+		if jiraStatus(sub) == task.StatusCompleted {
+			criterion += " (done)"
+		}
+		t.SuccessCriteria = append(t.SuccessCriteria, criterion)
+		if issue.StoryPoints == 0 {
+			points += sub.StoryPoints
+		}
+	}
+	if points > 0 {
+		t.EstimatedEffort = "SP: " + strconv.FormatFloat(points, 'f', -1, 64)
+	}
+	return t
+}
+
+// jiraPriority maps a JIRA priority to a task priority, P2 if unknown
+func jiraPriority(priority string) task.Priority {
+	switch strings.ToLower(priority) {
+	case "highest", "blocker", "critical":
+		return task.PriorityP1
+	case "medium", "normal", "major":
+		return task.PriorityP3
+	case "low", "lowest", "minor", "trivial":
+		return task.PriorityP4
+	}
+	return task.PriorityP2
+}
+
+// jiraStatus maps the status of an issue to a task status by its status
+// category, or by its name if the export has no category
+func jiraStatus(issue *jira.Issue) task.Status {
+	name := strings.ToLower(issue.Status)
+	switch {
+	case strings.Contains(name, "blocked"):
+		return task.StatusBlocked
+	case issue.StatusCategory == "done":
+		return task.StatusCompleted
+	case issue.StatusCategory == "indeterminate":
+		return task.StatusInProgress
+	case issue.StatusCategory != "":
+		return task.StatusNotStarted
+	}
+	switch name {
+	case "done", "closed", "resolved", "complete", "completed":
+		return task.StatusCompleted
+	case "in progress", "in review", "in development", "review":
+		return task.StatusInProgress
+	}
+	return task.StatusNotStarted
+}
+
+// formatJiraFeature renders a feature file with its tasks
+func formatJiraFeature(f *jiraFeature) string {
+	var sb strings.Builder
+
+	priority := task.PriorityP2
+	status := task.StatusNotStarted
+	if f.epic != nil {
+		priority = jiraPriority(f.epic.Priority)
+		status = jiraStatus(f.epic)
+	}
+
+	sb.WriteString(fmt.Sprintf("# Feature %d: %s\n\n", f.id, f.name))
+	sb.WriteString(fmt.Sprintf("**Feature ID:** F%03d\n", f.id))
+	sb.WriteString(fmt.Sprintf("**Priority:** %s\n", priority))
+	sb.WriteString(fmt.Sprintf("**Status:** %s\n\n", status))
+
+	sb.WriteString("## Overview\n\n")
+	switch {
+	case f.epic == nil && f.name == noEpicFeature:
+		sb.WriteString("Imported from JIRA issues without an epic.\n\n")
+	case f.epic == nil:
+		sb.WriteString(fmt.Sprintf("Imported from the issues of JIRA epic %s.\n\n", f.name))
+	default:
+		if f.epic.Description != "" {
+			sb.WriteString(f.epic.Description + "\n\n")
+		}
+		sb.WriteString(fmt.Sprintf("Imported from JIRA epic %s.\n\n", f.epic.Key))
+	}
+
+	sb.WriteString("## Tasks\n\n")
+	for _, t := range f.tasks {
+		sb.WriteString(task.FormatTask(t))
+		sb.WriteString("\n---\n\n")
+	}
+
+	return sb.String()
+}
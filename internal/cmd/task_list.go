@@ -0,0 +1,187 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"hermes/internal/scheduler"
+	"hermes/internal/task"
+	"hermes/internal/ui"
+)
+
+type taskListOptions struct {
+	sortBy          string
+	filters         []string
+	feature         string
+	format          string
+	includeArchived bool
+}
+
+// statusOrder sorts unfinished work first for task list --sort status
+var statusOrder = map[task.Status]int{
+	task.StatusInProgress: 0,
+	task.StatusNotStarted: 1,
+	task.StatusBlocked:    2,
+	task.StatusCompleted:  3,
+}
+
+// newTaskListCmd creates the task list subcommand
+func newTaskListCmd() *cobra.Command {
+	opts := &taskListOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List tasks as a table, JSON or CSV",
+		Long: `List all tasks with their feature, status, priority, effort and dependencies.
+
+--filter takes status=<status>, priority=<P1-P4> or feature=<id> and can be
+repeated; a task is listed if it matches all filters. JSON output is an array
+of tasks with the fields of the task files.`,
+		Example: `  hermes task list
+  hermes task list --sort priority --filter status=NOT_STARTED
+  hermes task list --feature F001 --format csv
+  hermes task list --format json | jq '.[].id'`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return taskListExecute(opts, os.Stdout)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.sortBy, "sort", "id", "Sort by id, priority, status or effort")
+	cmd.Flags().StringArrayVar(&opts.filters, "filter", nil, "Only list tasks with status=, priority= or feature= (repeatable)")
+	cmd.Flags().StringVar(&opts.feature, "feature", "", "Only list the tasks of this feature")
+	cmd.Flags().StringVar(&opts.format, "format", "table", "Output format: table, json, csv")
+	cmd.Flags().BoolVar(&opts.includeArchived, "include-archived", false, "Also list the tasks of archived features")
+
+	return cmd
+}
+
+func taskListExecute(opts *taskListOptions, w io.Writer) error {
+	match, err := parseTaskListFilters(opts.filters, opts.feature)
+	if err != nil {
+		return err
+	}
+
+	reader := newTaskReader(".")
+	reader.SetIncludeArchived(opts.includeArchived)
+	all, err := reader.GetAllTasks()
+	if err != nil {
+		return fmt.Errorf("failed to read tasks: %w", err)
+	}
+
+	tasks := []task.Task{}
+	for _, t := range all {
+		if match(t) {
+			tasks = append(tasks, t)
+		}
+	}
+	if err := sortTaskList(tasks, opts.sortBy); err != nil {
+		return err
+	}
+
+	switch opts.format {
+	case "table", "":
+		fmt.Fprintln(w, strings.TrimRight(ui.FormatTaskList(tasks), "\n"))
+	case "json":
+		data, err := json.MarshalIndent(tasks, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(w, string(data))
+	case "csv":
+		out := csv.NewWriter(w)
+		out.Write([]string{"id", "feature", "name", "status", "priority", "effort", "dependencies"})
+		for _, t := range tasks {
+			out.Write([]string{
+				t.ID, t.FeatureID, t.Name, string(t.Status), string(t.Priority), t.EstimatedEffort,
+				strings.Join(t.DependencyIDs(), ";"),
+			})
+		}
+		out.Flush()
+		return out.Error()
+	default:
+		return fmt.Errorf("unknown format: %s (use table, json or csv)", opts.format)
+	}
+	return nil
+}
+
+// parseTaskListFilters builds a predicate from key=value filters and the
+// --feature flag
+func parseTaskListFilters(filters []string, feature string) (func(task.Task) bool, error) {
+	var checks []func(task.Task) bool
+	if feature != "" {
+		filters = append(filters, "feature="+feature)
+	}
+
+	for _, f := range filters {
+		key, value, ok := strings.Cut(f, "=")
+		if !ok || value == "" {
+			return nil, fmt.Errorf("invalid filter %q (use key=value)", f)
+		}
+		value = strings.ToUpper(strings.TrimSpace(value))
+
+		switch strings.ToLower(strings.TrimSpace(key)) {
+		case "status":
+			status := task.Status(value)
+			if !task.IsValidStatus(status) {
+				return nil, fmt.Errorf("invalid status: %s", value)
+			}
+			checks = append(checks, func(t task.Task) bool { return t.Status == status })
+		case "priority":
+			priority, err := task.ParsePriority(value)
+			if err != nil {
+				return nil, err
+			}
+			checks = append(checks, func(t task.Task) bool { return t.Priority == priority })
+		case "feature":
+			checks = append(checks, func(t task.Task) bool { return strings.EqualFold(t.FeatureID, value) })
+		default:
+			return nil, fmt.Errorf("unknown filter key %q (use status, priority or feature)", key)
+		}
+	}
+
+	return func(t task.Task) bool {
+		for _, check := range checks {
+			if !check(t) {
+				return false
+			}
+		}
+		return true
+	}, nil
+}
+
+// sortTaskList sorts tasks by ID, then stably by the given key
+func sortTaskList(tasks []task.Task, by string) error {
+	sort.SliceStable(tasks, func(i, j int) bool { return tasks[i].ID < tasks[j].ID })
+
+	var less func(a, b task.Task) bool
+	switch by {
+	case "id", "":
+		return nil
+	case "priority":
+		less = func(a, b task.Task) bool { return priorityRank(a.Priority) < priorityRank(b.Priority) }
+	case "status":
+		less = func(a, b task.Task) bool { return statusOrder[a.Status] < statusOrder[b.Status] }
+	case "effort":
+		less = func(a, b task.Task) bool { return a.EstimatedMinutes < b.EstimatedMinutes }
+	default:
+		return fmt.Errorf("unknown sort key: %s (use id, priority, status or effort)", by)
+	}
+
+	sort.SliceStable(tasks, func(i, j int) bool { return less(tasks[i], tasks[j]) })
+	return nil
+}
+
+// priorityRank orders priorities P1 first and tasks without one last
+func priorityRank(p task.Priority) int {
+	if rank, ok := scheduler.PriorityOrder[p]; ok {
+		return rank
+	}
+	return len(scheduler.PriorityOrder) + 1
+}
@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"hermes/internal/task"
+)
+
+// newTaskMoveCmd creates the task move subcommand
+func newTaskMoveCmd() *cobra.Command {
+	var toFeature string
+
+	cmd := &cobra.Command{
+		Use:   "move <id> --to-feature <feature-id>",
+		Short: "Move a task to another feature",
+		Long: `Move a task's section from its feature file to the end of another feature's
+tasks, e.g. after a scope change. The task keeps its ID and dependencies.
+
+The move is refused if it would make two features depend on each other.`,
+		Example: `  hermes task move T004 --to-feature F002
+  hermes task move 4 --to-feature 2`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return taskMoveExecute(normalizeTaskID(args[0]), normalizeFeatureID(toFeature))
+		},
+	}
+
+	cmd.Flags().StringVar(&toFeature, "to-feature", "", "ID of the feature to move the task to")
+	cmd.MarkFlagRequired("to-feature")
+
+	return cmd
+}
+
+func taskMoveExecute(taskID, featureID string) error {
+	from, to, err := task.NewWriter(".").MoveTask(taskID, featureID)
+	if err != nil {
+		return fmt.Errorf("failed to move task: %w", err)
+	}
+
+	bold := color.New(color.Bold)
+	bold.Printf("%s: ", taskID)
+	fmt.Printf("moved to %s\n", featureID)
+	fmt.Printf("  From: %s\n", from)
+	fmt.Printf("  To:   %s\n", to)
+	return nil
+}
+
+// normalizeFeatureID converts a numeric feature ID to the F-prefixed form (2 -> F002)
+func normalizeFeatureID(id string) string {
+	featureID := strings.ToUpper(id)
+	if !strings.HasPrefix(featureID, "F") {
+		featureID = fmt.Sprintf("F%03s", featureID)
+	}
+	return featureID
+}
@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"hermes/internal/task"
+)
+
+// newTaskPriorityCmd creates the task priority subcommand
+func newTaskPriorityCmd() *cobra.Command {
+	var sortFeature bool
+
+	cmd := &cobra.Command{
+		Use:   "priority <id> <P1|P2|P3|P4>",
+		Short: "Change a task's priority",
+		Long: `Update the Priority field of a task. Higher priority tasks (P1) are picked
+first by 'hermes run' among tasks whose dependencies are complete.
+
+Use --sort to also reorder the tasks in the feature file by priority.`,
+		Example: `  hermes task priority T003 P1
+  hermes task priority 3 p4 --sort`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return taskPriorityExecute(normalizeTaskID(args[0]), args[1], sortFeature)
+		},
+	}
+
+	cmd.Flags().BoolVar(&sortFeature, "sort", false, "Reorder the feature's tasks by priority")
+
+	return cmd
+}
+
+func taskPriorityExecute(taskID, value string, sortFeature bool) error {
+	priority, err := task.ParsePriority(value)
+	if err != nil {
+		return err
+	}
+
+	before, err := findTask(taskID)
+	if err != nil {
+		return err
+	}
+
+	if err := task.NewStatusUpdater(".").SetTaskPriority(taskID, priority); err != nil {
+		return fmt.Errorf("failed to update priority: %w", err)
+	}
+
+	bold := color.New(color.Bold)
+	bold.Printf("%s: ", taskID)
+	fmt.Printf("%s -> %s\n", before.Priority, priority)
+
+	if sortFeature {
+		writer := task.NewWriter(".")
+		file, err := writer.FindTaskFile(taskID)
+		if err != nil {
+			return err
+		}
+		if err := writer.SortTasksByPriority(file); err != nil {
+			return fmt.Errorf("failed to sort tasks: %w", err)
+		}
+		fmt.Printf("  Sorted tasks in %s by priority\n", file)
+	}
+
+	return nil
+}
@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"hermes/internal/task"
+)
+
+// newTaskSortCmd creates the task sort subcommand
+func newTaskSortCmd() *cobra.Command {
+	var by string
+
+	cmd := &cobra.Command{
+		Use:   "sort <feature-id> --by dependency|priority|id|name",
+		Short: "Reorder the tasks of a feature file",
+		Long: `Reorder the task sections of a feature file. Everything around the tasks,
+such as the feature header and other sections, is kept in place.
+
+  dependency  each task after the tasks it depends on (topological order)
+  priority    P1 first
+  id          by task ID
+  name        alphabetically by task name
+
+Ties keep their current order. The moved tasks are shown as a diff.`,
+		Example: `  hermes task sort F002 --by dependency
+  hermes task sort 2 --by priority`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return taskSortExecute(newTaskReader("."), normalizeFeatureID(args[0]), by, os.Stdout)
+		},
+	}
+
+	cmd.Flags().StringVar(&by, "by", "dependency", "Sort key: "+strings.Join(task.SortKeys, ", "))
+
+	return cmd
+}
+
+func taskSortExecute(reader *task.Reader, featureID, by string, w io.Writer) error {
+	feature, err := reader.GetFeatureByID(featureID)
+	if err != nil {
+		return fmt.Errorf("failed to read tasks: %w", err)
+	}
+	if feature == nil {
+		return fmt.Errorf("feature %s not found", featureID)
+	}
+
+	before, after, err := task.NewWriter(".").SortTasks(feature.FilePath, by)
+	if err != nil {
+		return fmt.Errorf("failed to sort tasks: %w", err)
+	}
+
+	names := make(map[string]string, len(feature.Tasks))
+	for _, t := range feature.Tasks {
+		names[t.ID] = t.Name
+	}
+	diff := formatSortDiff(before, after, names)
+	if len(diff) == 0 {
+		fmt.Fprintf(w, "%s is already sorted by %s\n", feature.FilePath, by)
+		return nil
+	}
+	for _, line := range diff {
+		fmt.Fprintln(w, line)
+	}
+	fmt.Fprintf(w, "Sorted tasks in %s by %s\n", feature.FilePath, by)
+	return nil
+}
+
+// formatSortDiff returns a diff of the task order, listing the old and new
+// task at each position that changed
+func formatSortDiff(before, after []string, names map[string]string) []string {
+	red := color.New(color.FgRed).SprintFunc()
+	green := color.New(color.FgGreen).SprintFunc()
+
+	var lines []string
+	for i := range before {
+		if i >= len(after) || before[i] == after[i] {
+			continue
+		}
+		lines = append(lines,
+			red(fmt.Sprintf("- %2d. %s: %s", i+1, before[i], names[before[i]])),
+			green(fmt.Sprintf("+ %2d. %s: %s", i+1, after[i], names[after[i]])))
+	}
+	return lines
+}
@@ -0,0 +1,194 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"hermes/internal/analyzer"
+	"hermes/internal/task"
+)
+
+// newTaskTemplateCmd creates the task template command
+func newTaskTemplateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "template",
+		Short: "Manage reusable task templates",
+		Long: `Save tasks as reusable templates and create new tasks from them.
+
+Templates are stored in .hermes/templates/<name>.yaml. Their task name,
+description, technical details, files and success criteria are Go templates
+that can use variables such as {{.EntityName}}.`,
+		Example: `  hermes task template create --from-task T001 --name crud-endpoint --var EntityName=User
+  hermes task template list
+  hermes task template apply crud-endpoint --feature F002 --var EntityName=Order`,
+	}
+
+	cmd.AddCommand(newTaskTemplateCreateCmd())
+	cmd.AddCommand(newTaskTemplateListCmd())
+	cmd.AddCommand(newTaskTemplateApplyCmd())
+
+	return cmd
+}
+
+func newTaskTemplateCreateCmd() *cobra.Command {
+	var fromTask, name string
+	var vars []string
+	var force bool
+
+	cmd := &cobra.Command{
+		Use:   "create --from-task <id> --name <name>",
+		Short: "Save a task as a template",
+		Long: `Save a task as a template. Each --var NAME=VALUE replaces VALUE in the
+task's text with {{.NAME}}, so the template can be applied to other values.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			values, err := parseTemplateVars(vars)
+			if err != nil {
+				return err
+			}
+			return taskTemplateCreateExecute(normalizeTaskID(fromTask), name, values, force)
+		},
+	}
+
+	cmd.Flags().StringVar(&fromTask, "from-task", "", "ID of the task to save")
+	cmd.Flags().StringVar(&name, "name", "", "Template name")
+	cmd.Flags().StringArrayVar(&vars, "var", nil, "Turn a value into a variable: NAME=VALUE (repeatable)")
+	cmd.Flags().BoolVar(&force, "force", false, "Overwrite an existing template")
+	cmd.MarkFlagRequired("from-task")
+	cmd.MarkFlagRequired("name")
+
+	return cmd
+}
+
+func newTaskTemplateListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List task templates",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return taskTemplateListExecute()
+		},
+	}
+}
+
+func newTaskTemplateApplyCmd() *cobra.Command {
+	var featureID string
+	var vars []string
+
+	cmd := &cobra.Command{
+		Use:   "apply <name> --feature <id>",
+		Short: "Add a task created from a template to a feature",
+		Long: `Render a template with the given variables and add the result as a new
+NOT_STARTED task at the end of the feature's tasks, using the next free task ID.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			values, err := parseTemplateVars(vars)
+			if err != nil {
+				return err
+			}
+			return taskTemplateApplyExecute(args[0], normalizeFeatureID(featureID), values)
+		},
+	}
+
+	cmd.Flags().StringVar(&featureID, "feature", "", "ID of the feature to add the task to")
+	cmd.Flags().StringArrayVar(&vars, "var", nil, "Template variable: NAME=VALUE (repeatable)")
+	cmd.MarkFlagRequired("feature")
+
+	return cmd
+}
+
+func taskTemplateCreateExecute(taskID, name string, vars map[string]string, force bool) error {
+	t, err := newTaskReader(".").GetTaskByID(taskID)
+	if err != nil {
+		return fmt.Errorf("failed to read tasks: %w", err)
+	}
+	if t == nil {
+		return fmt.Errorf("task %s not found", taskID)
+	}
+
+	tmpl := task.TemplateFromTask(name, t, vars)
+	path, err := task.SaveTemplate(".", tmpl, force)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Saved %s as template %s: %s\n", taskID, name, path)
+	if used := tmpl.Variables(); len(used) > 0 {
+		fmt.Printf("Variables: %s\n", strings.Join(used, ", "))
+	}
+	return nil
+}
+
+func taskTemplateListExecute() error {
+	templates, err := task.ListTemplates(".")
+	if err != nil {
+		return err
+	}
+	if len(templates) == 0 {
+		fmt.Printf("No templates found in %s\n", task.TemplatesDir("."))
+		return nil
+	}
+
+	bold := color.New(color.Bold)
+	for _, tmpl := range templates {
+		bold.Printf("%s", tmpl.Name)
+		fmt.Printf(" - %s\n", tmpl.TaskName)
+		if vars := tmpl.Variables(); len(vars) > 0 {
+			fmt.Printf("  Variables: %s\n", strings.Join(vars, ", "))
+		}
+	}
+	return nil
+}
+
+func taskTemplateApplyExecute(name, featureID string, vars map[string]string) error {
+	tmpl, err := task.LoadTemplate(".", name)
+	if err != nil {
+		return err
+	}
+
+	var missing []string
+	for _, v := range tmpl.Variables() {
+		if _, ok := vars[v]; !ok {
+			missing = append(missing, v)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("missing template variable(s): %s (use --var NAME=VALUE)", strings.Join(missing, ", "))
+	}
+
+	highest, err := analyzer.NewFeatureAnalyzer(".").GetHighestTaskID()
+	if err != nil {
+		return fmt.Errorf("failed to read task IDs: %w", err)
+	}
+	t, err := tmpl.Render(fmt.Sprintf("T%03d", highest+1), featureID, vars)
+	if err != nil {
+		return err
+	}
+
+	file, err := task.NewWriter(".").AddTask(featureID, t)
+	if err != nil {
+		return fmt.Errorf("failed to add task: %w", err)
+	}
+
+	bold := color.New(color.Bold)
+	bold.Printf("%s: ", t.ID)
+	fmt.Printf("%s\n", t.Name)
+	fmt.Printf("  Added to %s from template %s\n", file, name)
+	return nil
+}
+
+// parseTemplateVars parses NAME=VALUE pairs
+func parseTemplateVars(pairs []string) (map[string]string, error) {
+	vars := make(map[string]string)
+	for _, pair := range pairs {
+		name, value, ok := strings.Cut(pair, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid --var %q (use NAME=VALUE)", pair)
+		}
+		vars[name] = value
+	}
+	return vars, nil
+}
@@ -1,24 +1,41 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"io"
+	"os"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/spf13/cobra"
+	"hermes/internal/scheduler"
 	"hermes/internal/tui"
 )
 
 // NewTuiCmd creates the tui subcommand
 func NewTuiCmd() *cobra.Command {
+	var attach string
+
 	cmd := &cobra.Command{
 		Use:   "tui",
 		Short: "Launch interactive TUI",
-		Long:  "Start the interactive terminal user interface",
+		Long: `Start the interactive terminal user interface.
+
+With --attach, show the live progress of a 'hermes run --parallel --tui-pipe'
+running in another terminal instead. Quitting only detaches; the run goes on
+and can be attached to again.`,
+		Example: `  hermes tui
+  hermes tui --attach /tmp/hermes.pipe`,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if attach != "" {
+				return tuiAttachExecute(attach)
+			}
 			return tuiExecute()
 		},
 	}
 
+	cmd.Flags().StringVar(&attach, "attach", "", "Named pipe of a parallel run started with --tui-pipe")
+
 	return cmd
 }
 
@@ -36,3 +53,59 @@ func tuiExecute() error {
 
 	return nil
 }
+
+func tuiAttachExecute(path string) error {
+	if _, err := os.Stat(path); err != nil {
+		return fmt.Errorf("no pipe at %s (start 'hermes run --parallel --tui-pipe %s' first): %w", path, path, err)
+	}
+
+	fmt.Printf("Waiting for the run on %s...\n", path)
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer f.Close()
+
+	decoder := tui.NewEventDecoder(f)
+	start, err := decoder.Decode()
+	if err != nil {
+		return fmt.Errorf("failed to read from %s: %w", path, err)
+	}
+	if start.Type != tui.PipeEventStart {
+		return fmt.Errorf("unexpected first event %q from %s", start.Type, path)
+	}
+
+	model := tui.NewParallelModel(".", start.Workers)
+	model.SetTotal(start.Total)
+
+	// A stream that ends without run_done means the run stopped
+	streamErr := make(chan error, 1)
+	go func() {
+		for {
+			event, err := decoder.Decode()
+			if err != nil {
+				if !errors.Is(err, io.EOF) {
+					streamErr <- err
+				}
+				model.Events() <- scheduler.WorkerEvent{Type: scheduler.EventRunDone}
+				return
+			}
+			if workerEvent, ok := event.WorkerEvent(); ok {
+				model.Events() <- workerEvent
+				if workerEvent.Type == scheduler.EventRunDone {
+					return
+				}
+			}
+		}
+	}()
+
+	if _, err := tea.NewProgram(model, tea.WithAltScreen()).Run(); err != nil {
+		return fmt.Errorf("TUI error: %w", err)
+	}
+	select {
+	case err := <-streamErr:
+		return fmt.Errorf("failed to read from %s: %w", path, err)
+	default:
+		return nil
+	}
+}
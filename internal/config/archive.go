@@ -0,0 +1,60 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// SaveArchivedFeature records an archived feature in the config file at
+// path, replacing an earlier record of the same feature and leaving other
+// settings untouched
+func SaveArchivedFeature(path string, archived ArchivedFeature) error {
+	return updateArchive(path, archived.FeatureID, &archived)
+}
+
+// RemoveArchivedFeature removes the record of an archived feature from the
+// config file at path
+func RemoveArchivedFeature(path, featureID string) error {
+	return updateArchive(path, featureID, nil)
+}
+
+// updateArchive replaces the archive record of a feature with archived, or
+// removes it if archived is nil
+func updateArchive(path, featureID string, archived *ArchivedFeature) error {
+	raw := make(map[string]interface{})
+	if data, err := os.ReadFile(path); err == nil {
+		if err := json.Unmarshal(data, &raw); err != nil {
+			return fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+	} else if archived == nil {
+		return nil
+	}
+
+	entries := []interface{}{}
+	existing, _ := raw["archive"].([]interface{})
+	for _, entry := range existing {
+		if record, ok := entry.(map[string]interface{}); ok && record["featureId"] == featureID {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	if archived != nil {
+		entries = append(entries, archived)
+	}
+	if len(entries) == 0 {
+		delete(raw, "archive")
+	} else {
+		raw["archive"] = entries
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(raw, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
@@ -13,14 +13,12 @@ func Load(basePath string) (*Config, error) {
 	cfg := DefaultConfig()
 
 	// Global config: ~/.hermes/config.json
-	if homeDir, err := os.UserHomeDir(); err == nil {
-		globalPath := filepath.Join(homeDir, ".hermes", "config.json")
+	if globalPath, err := GlobalPath(); err == nil {
 		loadFile(globalPath, cfg)
 	}
 
 	// Project config: .hermes/config.json
-	projectPath := filepath.Join(basePath, ".hermes", "config.json")
-	loadFile(projectPath, cfg)
+	loadFile(ProjectPath(basePath), cfg)
 
 	return cfg, nil
 }
@@ -35,7 +33,15 @@ func loadFile(path string, cfg *Config) error {
 		return err
 	}
 
-	return v.Unmarshal(cfg)
+	if err := v.Unmarshal(cfg); err != nil {
+		return err
+	}
+
+	// isolatedWorkspaces: false predates isolationMode and meant no isolation
+	if v.IsSet("parallel.isolatedWorkspaces") && !v.IsSet("parallel.isolationMode") && !v.GetBool("parallel.isolatedWorkspaces") {
+		cfg.Parallel.IsolationMode = "none"
+	}
+	return nil
 }
 
 // GetAIForTask returns the AI provider for a given task type
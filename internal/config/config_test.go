@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -156,3 +157,209 @@ func TestEnsureDirectories(t *testing.T) {
 		}
 	}
 }
+
+func TestGetSetValue(t *testing.T) {
+	cfg := DefaultConfig()
+
+	if err := SetValue(cfg, "parallel.max_workers", "7"); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Parallel.MaxWorkers != 7 {
+		t.Errorf("expected MaxWorkers = 7, got %d", cfg.Parallel.MaxWorkers)
+	}
+
+	if err := SetValue(cfg, "ai.streamOutput", "false"); err != nil {
+		t.Fatal(err)
+	}
+	if value, _ := GetValue(cfg, "ai.streamOutput"); value != "false" {
+		t.Errorf("expected ai.streamOutput = false, got %s", value)
+	}
+
+	if err := SetValue(cfg, "parallel.maxCostPerHour", "2.5"); err != nil || cfg.Parallel.MaxCostPerHour != 2.5 {
+		t.Errorf("expected MaxCostPerHour = 2.5, got %v (%v)", cfg.Parallel.MaxCostPerHour, err)
+	}
+
+	if err := SetValue(cfg, "ai.timeout", "soon"); err == nil {
+		t.Error("expected type error for non-integer timeout")
+	}
+	if _, err := GetValue(cfg, "ai.unknown"); err == nil {
+		t.Error("expected error for unknown key")
+	}
+	if _, err := GetValue(cfg, "webhooks"); err == nil {
+		t.Error("expected error for non-scalar key")
+	}
+}
+
+func TestKeys(t *testing.T) {
+	keys := Keys()
+	cfg := DefaultConfig()
+
+	for _, expected := range []string{"ai.timeout", "parallel.maxWorkers", "logFormat"} {
+		found := false
+		for _, k := range keys {
+			if k == expected {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("expected key %s in %v", expected, keys)
+		}
+	}
+
+	for _, k := range keys {
+		if _, err := GetValue(cfg, k); err != nil {
+			t.Errorf("key %s not readable: %v", k, err)
+		}
+	}
+}
+
+func TestSetInFile(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "hermes-config-test-*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	path := filepath.Join(tmpDir, ".hermes", "config.json")
+	os.MkdirAll(filepath.Dir(path), 0755)
+	os.WriteFile(path, []byte(`{"ai": {"coding": "droid"}}`), 0644)
+
+	if err := SetInFile(path, "ai.max_retries", "3"); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetInFile(path, "ai.maxRetries", "many"); err == nil {
+		t.Error("expected type error")
+	}
+
+	data, _ := os.ReadFile(path)
+	if strings.Contains(string(data), "timeout") {
+		t.Errorf("expected only set keys in file, got %s", data)
+	}
+
+	cfg, _ := LoadFile(path)
+	if cfg.AI.MaxRetries != 3 || cfg.AI.Coding != "droid" {
+		t.Errorf("expected maxRetries=3 and coding=droid, got %d %s", cfg.AI.MaxRetries, cfg.AI.Coding)
+	}
+}
+
+func TestSaveSlackWebhook(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".hermes", "config.json")
+	os.MkdirAll(filepath.Dir(path), 0755)
+	os.WriteFile(path, []byte(`{"ai": {"coding": "droid"}, "webhooks": [{"url": "https://example.com/hook"}, {"slack": "https://hooks.slack.com/old"}]}`), 0644)
+
+	if err := SaveSlackWebhook(path, "https://hooks.slack.com/new"); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.AI.Coding != "droid" {
+		t.Errorf("expected other settings to be kept, got coding=%s", cfg.AI.Coding)
+	}
+	if len(cfg.Webhooks) != 2 || cfg.Webhooks[0].URL != "https://example.com/hook" || cfg.Webhooks[1].Slack != "https://hooks.slack.com/new" {
+		t.Errorf("expected the Slack webhook to be replaced, got %+v", cfg.Webhooks)
+	}
+
+	webhooks := WithSlackWebhook(cfg.Webhooks, "https://hooks.slack.com/other")
+	if len(webhooks) != 2 || webhooks[1].Slack != "https://hooks.slack.com/other" {
+		t.Errorf("WithSlackWebhook = %+v", webhooks)
+	}
+}
+
+func TestSaveArchivedFeature(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".hermes", "config.json")
+	os.MkdirAll(filepath.Dir(path), 0755)
+	os.WriteFile(path, []byte(`{"ai": {"coding": "droid"}}`), 0644)
+
+	SaveArchivedFeature(path, ArchivedFeature{FeatureID: "F001", File: "a.md", ArchivedAt: "2026-01-02"})
+	SaveArchivedFeature(path, ArchivedFeature{FeatureID: "F002", File: "b.md", ArchivedAt: "2026-01-03"})
+	if err := SaveArchivedFeature(path, ArchivedFeature{FeatureID: "F001", File: "c.md", ArchivedAt: "2026-02-01"}); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.AI.Coding != "droid" {
+		t.Errorf("expected other settings to be kept, got coding=%s", cfg.AI.Coding)
+	}
+	if len(cfg.Archive) != 2 || cfg.Archive[0].FeatureID != "F002" || cfg.Archive[1].File != "c.md" || cfg.Archive[1].ArchivedAt != "2026-02-01" {
+		t.Errorf("expected F001 replaced after F002, got %+v", cfg.Archive)
+	}
+
+	RemoveArchivedFeature(path, "F001")
+	RemoveArchivedFeature(path, "F002")
+	data, _ := os.ReadFile(path)
+	if strings.Contains(string(data), "archive") {
+		t.Errorf("expected no archive left, got %s", data)
+	}
+
+	if err := RemoveArchivedFeature(filepath.Join(t.TempDir(), "missing.json"), "F001"); err != nil {
+		t.Errorf("expected removing from a missing config to do nothing, got %v", err)
+	}
+}
+
+func TestIsolatedWorkspacesCompat(t *testing.T) {
+	tests := []struct {
+		content string
+		want    string
+	}{
+		{`{}`, "worktree"},
+		{`{"parallel": {"isolatedWorkspaces": false}}`, "none"},
+		{`{"parallel": {"isolatedWorkspaces": true}}`, "worktree"},
+		{`{"parallel": {"isolatedWorkspaces": false, "isolationMode": "copy"}}`, "copy"},
+	}
+
+	for _, tt := range tests {
+		path := filepath.Join(t.TempDir(), "config.json")
+		os.WriteFile(path, []byte(tt.content), 0644)
+
+		cfg, err := LoadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cfg.Parallel.IsolationMode != tt.want {
+			t.Errorf("%s: expected isolation mode %s, got %s", tt.content, tt.want, cfg.Parallel.IsolationMode)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if errs := DefaultConfig().Validate(); len(errs) != 0 {
+		t.Errorf("default config should be valid, got %v", errs)
+	}
+
+	cfg := DefaultConfig()
+	cfg.AI.Timeout = 0
+	cfg.AI.MaxRetries = 0
+	cfg.AI.Coding = "gpt"
+	cfg.Parallel.MaxWorkers = 21
+	cfg.Paths.TasksDir = "../tasks"
+	cfg.Loop.ErrorDelay = -1
+
+	var keys []string
+	for _, e := range cfg.Validate() {
+		keys = append(keys, e.Key)
+	}
+	expected := []string{"ai.timeout", "ai.maxRetries", "ai.coding", "parallel.maxWorkers", "paths.tasksDir", "loop.errorDelay"}
+	if strings.Join(keys, ",") != strings.Join(expected, ",") {
+		t.Errorf("Validate() reported %v, expected %v", keys, expected)
+	}
+
+	err := ValidationErrorsToError(cfg.Validate())
+	if err == nil || !strings.Contains(err.Error(), `unknown provider "gpt"`) {
+		t.Errorf("expected a formatted error, got %v", err)
+	}
+	if ValidationErrorsToError(nil) != nil {
+		t.Error("expected nil for no violations")
+	}
+
+	cfg = DefaultConfig()
+	cfg.Paths.TasksDir = "/etc/tasks"
+	if errs := cfg.Validate(); len(errs) != 1 || errs[0].Key != "paths.tasksDir" {
+		t.Errorf("expected an absolute tasksDir to be rejected, got %v", errs)
+	}
+}
@@ -17,6 +17,16 @@ func DefaultConfig() *Config {
 			Autonomous:           true,
 			MaxConsecutiveErrors: 5,
 		},
+		Task: TaskConfig{
+			SPToMinutes: 240,
+			Sizes: TShirtSizeConfig{
+				XS: 60,
+				S:  240,
+				M:  480,
+				L:  1440,
+				XL: 2400,
+			},
+		},
 		Loop: LoopConfig{
 			MaxCallsPerHour: 100,
 			TimeoutMinutes:  15,
@@ -33,11 +43,22 @@ func DefaultConfig() *Config {
 			MaxWorkers:         3,
 			Strategy:           "branch-per-task",
 			ConflictResolution: "ai-assisted",
-			IsolatedWorkspaces: true,
+			IsolationMode:      "worktree",
 			MergeStrategy:      "sequential",
 			MaxCostPerHour:     0, // 0 means no limit
 			FailureStrategy:    "continue",
 			MaxRetries:         2,
+			TokensPerMinute:    20000,
+			AIConfidence:       0.75,
+		},
+		LogFormat: "text",
+		Log: LogConfig{
+			Rotation: LogRotationConfig{
+				MaxAgeDays:  7,
+				MaxArchives: 10,
+				KeepLines:   1000,
+				MaxSizeMB:   10,
+			},
 		},
 	}
 }
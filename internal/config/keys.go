@@ -0,0 +1,190 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// ProjectPath returns the path of the project config file
+func ProjectPath(basePath string) string {
+	return filepath.Join(basePath, ".hermes", "config.json")
+}
+
+// GlobalPath returns the path of the global config file (~/.hermes/config.json)
+func GlobalPath() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, ".hermes", "config.json"), nil
+}
+
+// LoadFile loads a single config file on top of the defaults.
+// A missing file yields the defaults.
+func LoadFile(path string) (*Config, error) {
+	cfg := DefaultConfig()
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return cfg, nil
+	}
+	if err := loadFile(path, cfg); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// Keys returns the dot-notation keys of all settable values, e.g. "ai.timeout"
+func Keys() []string {
+	var keys []string
+	walkKeys(reflect.TypeOf(Config{}), "", &keys)
+	return keys
+}
+
+func walkKeys(t reflect.Type, prefix string, keys *[]string) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := prefix + jsonName(field)
+		switch field.Type.Kind() {
+		case reflect.Struct:
+			walkKeys(field.Type, name+".", keys)
+		case reflect.String, reflect.Int, reflect.Bool, reflect.Float64:
+			*keys = append(*keys, name)
+		}
+	}
+}
+
+// GetValue returns the value of a dot-notation key as a string
+func GetValue(cfg *Config, key string) (string, error) {
+	v, _, err := lookupField(cfg, key)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprint(v.Interface()), nil
+}
+
+// SetValue parses value according to the key's type and stores it in cfg
+func SetValue(cfg *Config, key, value string) error {
+	v, _, err := lookupField(cfg, key)
+	if err != nil {
+		return err
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(value)
+	case reflect.Int:
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("%s expects an integer, got %q", key, value)
+		}
+		v.SetInt(int64(n))
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("%s expects true or false, got %q", key, value)
+		}
+		v.SetBool(b)
+	case reflect.Float64:
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("%s expects a number, got %q", key, value)
+		}
+		v.SetFloat(f)
+	}
+	return nil
+}
+
+// lookupField resolves a dot-notation key to a settable scalar field and
+// returns the field with its canonical json path. Segments match json names
+// case-insensitively and ignore '_' and '-', so "parallel.max_workers" and
+// "parallel.maxWorkers" are equivalent.
+func lookupField(cfg *Config, key string) (reflect.Value, []string, error) {
+	v := reflect.ValueOf(cfg).Elem()
+	var path []string
+
+	for _, segment := range strings.Split(key, ".") {
+		if v.Kind() != reflect.Struct {
+			return reflect.Value{}, nil, fmt.Errorf("unknown config key: %s", key)
+		}
+
+		found := false
+		for i := 0; i < v.NumField(); i++ {
+			name := jsonName(v.Type().Field(i))
+			if normalizeKey(name) == normalizeKey(segment) {
+				v = v.Field(i)
+				path = append(path, name)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return reflect.Value{}, nil, fmt.Errorf("unknown config key: %s", key)
+		}
+	}
+
+	switch v.Kind() {
+	case reflect.String, reflect.Int, reflect.Bool, reflect.Float64:
+		return v, path, nil
+	default:
+		return reflect.Value{}, nil, fmt.Errorf("config key %s is not a single value (edit config.json directly)", key)
+	}
+}
+
+// SetInFile validates value for key and writes only that key to the config
+// file at path, leaving other settings untouched so that unset keys keep
+// falling back to the global config and defaults.
+func SetInFile(path, key, value string) error {
+	cfg, err := LoadFile(path)
+	if err != nil {
+		return err
+	}
+	if err := SetValue(cfg, key, value); err != nil {
+		return err
+	}
+	v, fieldPath, _ := lookupField(cfg, key)
+
+	raw := make(map[string]interface{})
+	if data, err := os.ReadFile(path); err == nil {
+		if err := json.Unmarshal(data, &raw); err != nil {
+			return fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+	}
+
+	node := raw
+	for _, segment := range fieldPath[:len(fieldPath)-1] {
+		child, ok := node[segment].(map[string]interface{})
+		if !ok {
+			child = make(map[string]interface{})
+			node[segment] = child
+		}
+		node = child
+	}
+	node[fieldPath[len(fieldPath)-1]] = v.Interface()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(raw, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
+
+func jsonName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "" {
+		return field.Name
+	}
+	return name
+}
+
+func normalizeKey(s string) string {
+	s = strings.ReplaceAll(s, "_", "")
+	s = strings.ReplaceAll(s, "-", "")
+	return strings.ToLower(s)
+}
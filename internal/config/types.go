@@ -2,11 +2,22 @@ package config
 
 // Config represents the complete Hermes configuration
 type Config struct {
-	AI       AIConfig       `json:"ai" mapstructure:"ai"`
-	TaskMode TaskModeConfig `json:"taskMode" mapstructure:"taskMode"`
-	Loop     LoopConfig     `json:"loop" mapstructure:"loop"`
-	Paths    PathsConfig    `json:"paths" mapstructure:"paths"`
-	Parallel ParallelConfig `json:"parallel" mapstructure:"parallel"`
+	AI        AIConfig        `json:"ai" mapstructure:"ai"`
+	TaskMode  TaskModeConfig  `json:"taskMode" mapstructure:"taskMode"`
+	Task      TaskConfig      `json:"task" mapstructure:"task"`
+	Loop      LoopConfig      `json:"loop" mapstructure:"loop"`
+	Paths     PathsConfig     `json:"paths" mapstructure:"paths"`
+	Parallel  ParallelConfig  `json:"parallel" mapstructure:"parallel"`
+	LogFormat string          `json:"logFormat" mapstructure:"logFormat"` // "text" or "json"
+	Webhooks  []WebhookConfig `json:"webhooks" mapstructure:"webhooks"`
+	Template  TemplateConfig  `json:"template" mapstructure:"template"`
+	Hooks     HooksConfig     `json:"hooks" mapstructure:"hooks"`
+	Circuit   CircuitConfig   `json:"circuit" mapstructure:"circuit"`
+	Log       LogConfig       `json:"log" mapstructure:"log"`
+	// ContextFiles are added to every task prompt, e.g. ADRs or style guides
+	ContextFiles []string `json:"contextFiles" mapstructure:"contextFiles"`
+	// Archive records the features moved out by 'hermes task archive'
+	Archive []ArchivedFeature `json:"archive,omitempty" mapstructure:"archive"`
 }
 
 // AIConfig contains AI provider settings
@@ -17,6 +28,9 @@ type AIConfig struct {
 	PrdTimeout   int    `json:"prdTimeout" mapstructure:"prdTimeout"`
 	MaxRetries   int    `json:"maxRetries" mapstructure:"maxRetries"`
 	StreamOutput bool   `json:"streamOutput" mapstructure:"streamOutput"`
+
+	// ExtraArgs are additional flags passed to the AI CLI, e.g. {"model": "claude-opus-4-5"}
+	ExtraArgs map[string]string `json:"extraArgs,omitempty" mapstructure:"extraArgs"`
 }
 
 // TaskModeConfig contains task execution settings
@@ -27,11 +41,27 @@ type TaskModeConfig struct {
 	MaxConsecutiveErrors int  `json:"maxConsecutiveErrors" mapstructure:"maxConsecutiveErrors"`
 }
 
+// TaskConfig contains task file settings
+type TaskConfig struct {
+	SPToMinutes int              `json:"spToMinutes" mapstructure:"spToMinutes"` // Minutes per story point in effort estimates
+	Sizes       TShirtSizeConfig `json:"sizes" mapstructure:"sizes"`
+}
+
+// TShirtSizeConfig contains the minutes of T-shirt size effort estimates
+type TShirtSizeConfig struct {
+	XS int `json:"xs" mapstructure:"xs"`
+	S  int `json:"s" mapstructure:"s"`
+	M  int `json:"m" mapstructure:"m"`
+	L  int `json:"l" mapstructure:"l"`
+	XL int `json:"xl" mapstructure:"xl"`
+}
+
 // LoopConfig contains loop execution settings
 type LoopConfig struct {
 	MaxCallsPerHour int `json:"maxCallsPerHour" mapstructure:"maxCallsPerHour"`
 	TimeoutMinutes  int `json:"timeoutMinutes" mapstructure:"timeoutMinutes"`
 	ErrorDelay      int `json:"errorDelay" mapstructure:"errorDelay"`
+	MaxLoops        int `json:"maxLoops" mapstructure:"maxLoops"` // 0 = unlimited
 }
 
 // PathsConfig contains directory paths
@@ -48,9 +78,59 @@ type ParallelConfig struct {
 	MaxWorkers         int     `json:"maxWorkers" mapstructure:"maxWorkers"`
 	Strategy           string  `json:"strategy" mapstructure:"strategy"`
 	ConflictResolution string  `json:"conflictResolution" mapstructure:"conflictResolution"`
-	IsolatedWorkspaces bool    `json:"isolatedWorkspaces" mapstructure:"isolatedWorkspaces"`
+	IsolationMode      string  `json:"isolationMode" mapstructure:"isolationMode"` // none, worktree, docker, copy
 	MergeStrategy      string  `json:"mergeStrategy" mapstructure:"mergeStrategy"`
 	MaxCostPerHour     float64 `json:"maxCostPerHour" mapstructure:"maxCostPerHour"`
 	FailureStrategy    string  `json:"failureStrategy" mapstructure:"failureStrategy"`
 	MaxRetries         int     `json:"maxRetries" mapstructure:"maxRetries"`
+	TokensPerMinute    int     `json:"tokensPerMinute" mapstructure:"tokensPerMinute"`             // Assumed AI usage for cost estimates
+	AIConfidence       float64 `json:"aiConfidenceThreshold" mapstructure:"aiConfidenceThreshold"` // Confidence an AI conflict resolution must exceed
+}
+
+// CircuitConfig contains circuit breaker settings
+type CircuitConfig struct {
+	// AdaptiveMode scales the no-progress thresholds by the current task's
+	// estimated effort in days
+	AdaptiveMode bool `json:"adaptiveMode" mapstructure:"adaptiveMode"`
+}
+
+// LogConfig contains log file settings
+type LogConfig struct {
+	Rotation LogRotationConfig `json:"rotation" mapstructure:"rotation"`
+}
+
+// LogRotationConfig contains the defaults of 'hermes log rotate'
+type LogRotationConfig struct {
+	MaxAgeDays  int `json:"maxAgeDays" mapstructure:"maxAgeDays"`   // Archive log files older than this
+	MaxArchives int `json:"maxArchives" mapstructure:"maxArchives"` // Keep this many archives
+	KeepLines   int `json:"keepLines" mapstructure:"keepLines"`     // Lines of hermes.log kept after rotation
+	MaxSizeMB   int `json:"maxSizeMB" mapstructure:"maxSizeMB"`     // 'hermes run' rotates above this size (0 = never)
+}
+
+// TemplateConfig records the project template used by 'hermes init --template'
+type TemplateConfig struct {
+	Source string `json:"source" mapstructure:"source"` // owner/repo, git URL or local path
+	Ref    string `json:"ref" mapstructure:"ref"`       // Branch, tag or commit (empty = default branch)
+}
+
+// HooksConfig contains shell commands run on task events
+type HooksConfig struct {
+	OnTaskComplete string `json:"onTaskComplete" mapstructure:"onTaskComplete"` // Run after each completed task
+	Timeout        int    `json:"timeout" mapstructure:"timeout"`               // Seconds (0 = 30)
+}
+
+// WebhookConfig contains webhook notification settings
+type WebhookConfig struct {
+	URL     string            `json:"url" mapstructure:"url"`
+	Slack   string            `json:"slack" mapstructure:"slack"`   // Slack incoming webhook URL, used instead of URL
+	Method  string            `json:"method" mapstructure:"method"` // POST or PUT
+	Headers map[string]string `json:"headers" mapstructure:"headers"`
+	Events  []string          `json:"events" mapstructure:"events"` // e.g. task.completed, circuit.opened (empty = all)
+}
+
+// ArchivedFeature records a feature archived by 'hermes task archive'
+type ArchivedFeature struct {
+	FeatureID  string `json:"featureId" mapstructure:"featureId"`
+	File       string `json:"file" mapstructure:"file"`             // Archived feature file, relative to the project
+	ArchivedAt string `json:"archivedAt" mapstructure:"archivedAt"` // YYYY-MM-DD
 }
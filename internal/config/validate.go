@@ -0,0 +1,80 @@
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// KnownProviders are the values accepted for ai.planning and ai.coding
+var KnownProviders = []string{"auto", "claude", "droid", "gemini"}
+
+// MaxWorkers is the highest accepted parallel.maxWorkers
+const MaxWorkers = 20
+
+// ValidationError is a setting with an invalid value
+type ValidationError struct {
+	Key     string // Dot-notation key, e.g. "ai.timeout"
+	Message string
+}
+
+func (e ValidationError) Error() string {
+	return e.Key + ": " + e.Message
+}
+
+// Validate checks the settings that otherwise fail late with unclear errors
+// and returns every violation found
+func (c *Config) Validate() []ValidationError {
+	var errs []ValidationError
+	add := func(key, format string, args ...interface{}) {
+		errs = append(errs, ValidationError{Key: key, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if c.AI.Timeout <= 0 {
+		add("ai.timeout", "must be greater than 0 (got %d)", c.AI.Timeout)
+	}
+	if c.AI.MaxRetries < 1 {
+		add("ai.maxRetries", "must be at least 1 (got %d)", c.AI.MaxRetries)
+	}
+	if c.AI.Planning != "" && !containsString(KnownProviders, c.AI.Planning) {
+		add("ai.planning", "unknown provider %q (use %s)", c.AI.Planning, strings.Join(KnownProviders, ", "))
+	}
+	if c.AI.Coding != "" && !containsString(KnownProviders, c.AI.Coding) {
+		add("ai.coding", "unknown provider %q (use %s)", c.AI.Coding, strings.Join(KnownProviders, ", "))
+	}
+	if c.Parallel.MaxWorkers < 1 || c.Parallel.MaxWorkers > MaxWorkers {
+		add("parallel.maxWorkers", "must be between 1 and %d (got %d)", MaxWorkers, c.Parallel.MaxWorkers)
+	}
+	if filepath.IsAbs(c.Paths.TasksDir) {
+		add("paths.tasksDir", "must be relative to the project, e.g. .hermes/tasks (got %s)", c.Paths.TasksDir)
+	} else if clean := filepath.Clean(c.Paths.TasksDir); clean == ".." || strings.HasPrefix(clean, ".."+string(filepath.Separator)) {
+		add("paths.tasksDir", "must be inside the project, e.g. .hermes/tasks (got %s)", c.Paths.TasksDir)
+	}
+	if c.Loop.ErrorDelay < 0 {
+		add("loop.errorDelay", "must not be negative (got %d)", c.Loop.ErrorDelay)
+	}
+	return errs
+}
+
+// ValidationErrorsToError returns an error listing all violations, or nil
+// if there are none
+func ValidationErrorsToError(errs []ValidationError) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	var b strings.Builder
+	b.WriteString("invalid configuration (fix with 'hermes config set <key> <value>'):")
+	for _, e := range errs {
+		b.WriteString("\n  - " + e.Error())
+	}
+	return fmt.Errorf("%s", b.String())
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
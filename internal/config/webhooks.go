@@ -0,0 +1,52 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// WithSlackWebhook returns the webhooks with any Slack webhook replaced by
+// one posting to url
+func WithSlackWebhook(webhooks []WebhookConfig, url string) []WebhookConfig {
+	var result []WebhookConfig
+	for _, hook := range webhooks {
+		if hook.Slack == "" {
+			result = append(result, hook)
+		}
+	}
+	return append(result, WebhookConfig{Slack: url})
+}
+
+// SaveSlackWebhook stores a Slack webhook URL in the config file at path,
+// replacing an existing Slack webhook and leaving other settings untouched
+func SaveSlackWebhook(path, url string) error {
+	raw := make(map[string]interface{})
+	if data, err := os.ReadFile(path); err == nil {
+		if err := json.Unmarshal(data, &raw); err != nil {
+			return fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+	}
+
+	webhooks := []interface{}{}
+	existing, _ := raw["webhooks"].([]interface{})
+	for _, entry := range existing {
+		if hook, ok := entry.(map[string]interface{}); ok {
+			if slack, _ := hook["slack"].(string); slack != "" {
+				continue
+			}
+		}
+		webhooks = append(webhooks, entry)
+	}
+	raw["webhooks"] = append(webhooks, map[string]interface{}{"slack": url})
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(raw, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
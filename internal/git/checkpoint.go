@@ -0,0 +1,48 @@
+package git
+
+import "fmt"
+
+// CheckpointTag returns the tag of the checkpoint taken at a loop
+func CheckpointTag(loop int) string {
+	return fmt.Sprintf("hermes-checkpoint-%d", loop)
+}
+
+// CreateCheckpoint commits all changes as a checkpoint of the task and tags
+// HEAD with CheckpointTag(loop). HEAD is tagged even if there was nothing to
+// commit; the tag of an earlier run at the same loop is moved.
+func (g *Git) CreateCheckpoint(loop int, taskID string) error {
+	if g.HasUncommittedChanges() {
+		if err := g.StageAll(); err != nil {
+			return err
+		}
+		if err := g.CommitTask(taskID, fmt.Sprintf("checkpoint at loop %d", loop)); err != nil {
+			return err
+		}
+	}
+
+	tag := CheckpointTag(loop)
+	if g.TagExists(tag) {
+		if err := g.DeleteTag(tag); err != nil {
+			return err
+		}
+	}
+	return g.CreateLightweightTag(tag)
+}
+
+// GetCheckpointCommit returns the commit tagged as the checkpoint of a loop
+func (g *Git) GetCheckpointCommit(loop int) (string, error) {
+	sha, err := g.run("rev-parse", "--verify", "--quiet", "refs/tags/"+CheckpointTag(loop)+"^{commit}")
+	if err != nil || sha == "" {
+		return "", fmt.Errorf("no checkpoint for loop %d", loop)
+	}
+	return sha, nil
+}
+
+// ResetHard resets HEAD, the index and the working tree to a commit
+func (g *Git) ResetHard(ref string) error {
+	output, err := g.run("reset", "--hard", ref)
+	if err != nil {
+		return fmt.Errorf("git reset --hard %s: %s", ref, output)
+	}
+	return nil
+}
@@ -57,6 +57,57 @@ func (g *Git) GetLastCommitShortHash() (string, error) {
 	return g.run("rev-parse", "--short", "HEAD")
 }
 
+// FindTaskCommit returns the SHA of the latest commit created by CommitTask
+// for taskID, or "" if there is none
+func (g *Git) FindTaskCommit(taskID string) (string, error) {
+	return g.run("log", "-1", "--format=%H", "--fixed-strings", "--grep", fmt.Sprintf("feat(%s):", taskID))
+}
+
+// GetCommitDiff returns the changes introduced by a commit
+func (g *Git) GetCommitDiff(sha string, color bool) (string, error) {
+	args := []string{"diff"}
+	if color {
+		args = append(args, "--color=always")
+	}
+	return g.run(append(args, sha+"^", sha)...)
+}
+
+// GetFileDiff returns the unified diff of a file between two commits
+func (g *Git) GetFileDiff(sha1, sha2, file string) (string, error) {
+	return g.runRaw("diff", sha1+".."+sha2, "--", file)
+}
+
+// GetFileAt returns the contents of a file at a commit
+func (g *Git) GetFileAt(sha, file string) (string, error) {
+	return g.runRaw("show", sha+":"+file)
+}
+
+// GetCommittedFiles returns the files changed between two commits
+func (g *Git) GetCommittedFiles(sha1, sha2 string) ([]string, error) {
+	output, err := g.run("diff", "--name-only", sha1+".."+sha2)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	for _, line := range strings.Split(output, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			files = append(files, line)
+		}
+	}
+	return files, nil
+}
+
+// GetBranchCommit returns the commit a branch points to
+func (g *Git) GetBranchCommit(branch string) (string, error) {
+	return g.run("rev-parse", "--verify", "--quiet", branch+"^{commit}")
+}
+
+// MergeBase returns the best common ancestor of two commits or branches
+func (g *Git) MergeBase(ref1, ref2 string) (string, error) {
+	return g.run("merge-base", ref1, ref2)
+}
+
 // AmendCommit amends the last commit with staged changes
 func (g *Git) AmendCommit() error {
 	_, err := g.run("commit", "--amend", "--no-edit")
@@ -104,16 +155,22 @@ func (g *Git) ListTags() ([]string, error) {
 	return strings.Split(output, "\n"), nil
 }
 
+// FeatureTagName returns the tag CreateFeatureTag creates for a version,
+// which adds a v prefix if missing
+func FeatureTagName(version string) string {
+	if version != "" && version[0] != 'v' {
+		return "v" + version
+	}
+	return version
+}
+
 // CreateFeatureTag creates a tag for a completed feature with version
 func (g *Git) CreateFeatureTag(featureID, featureName, version string) error {
 	if version == "" {
 		return nil
 	}
 
-	// Normalize version (add v prefix if missing)
-	if version[0] != 'v' {
-		version = "v" + version
-	}
+	version = FeatureTagName(version)
 
 	// Check if tag already exists
 	if g.TagExists(version) {
@@ -4,16 +4,31 @@ import (
 	"fmt"
 	"os/exec"
 	"strings"
+	"sync"
 )
 
+// DefaultRemote is the remote pushed to when New is given none
+const DefaultRemote = "origin"
+
 // Git provides git operations
 type Git struct {
 	workDir string
+	remote  string
+	mu      sync.Mutex // Held by WithLock and StashAndApply
 }
 
-// New creates a new Git instance
-func New(workDir string) *Git {
-	return &Git{workDir: workDir}
+// New creates a new Git instance. Push and PushTag use remote when they
+// are given none; an empty remote means DefaultRemote.
+func New(workDir, remote string) *Git {
+	if remote == "" {
+		remote = DefaultRemote
+	}
+	return &Git{workDir: workDir, remote: remote}
+}
+
+// Remote returns the default remote
+func (g *Git) Remote() string {
+	return g.remote
 }
 
 // run executes a git command and returns the output
@@ -24,6 +39,18 @@ func (g *Git) run(args ...string) (string, error) {
 	return strings.TrimSpace(string(output)), err
 }
 
+// runRaw executes a git command and returns its output unmodified, for
+// diffs and file contents whose leading and trailing whitespace matters
+func (g *Git) runRaw(args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = g.workDir
+	output, err := cmd.Output()
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		err = fmt.Errorf("%w: %s", err, strings.TrimSpace(string(exitErr.Stderr)))
+	}
+	return string(output), err
+}
+
 // IsRepository checks if the directory is a git repository
 func (g *Git) IsRepository() bool {
 	_, err := g.run("rev-parse", "--git-dir")
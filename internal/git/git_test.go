@@ -1,10 +1,12 @@
 package git
 
 import (
+	"errors"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
 )
 
@@ -54,7 +56,7 @@ func TestIsRepository(t *testing.T) {
 	repoDir, cleanup := setupTestRepo(t)
 	defer cleanup()
 
-	g := New(repoDir)
+	g := New(repoDir, "")
 	if !g.IsRepository() {
 		t.Error("expected IsRepository = true")
 	}
@@ -63,7 +65,7 @@ func TestIsRepository(t *testing.T) {
 	tmpDir, _ := os.MkdirTemp("", "hermes-non-git-*")
 	defer os.RemoveAll(tmpDir)
 
-	g2 := New(tmpDir)
+	g2 := New(tmpDir, "")
 	if g2.IsRepository() {
 		t.Error("expected IsRepository = false for non-git directory")
 	}
@@ -73,7 +75,7 @@ func TestGetCurrentBranch(t *testing.T) {
 	repoDir, cleanup := setupTestRepo(t)
 	defer cleanup()
 
-	g := New(repoDir)
+	g := New(repoDir, "")
 	branch, err := g.GetCurrentBranch()
 	if err != nil {
 		t.Fatal(err)
@@ -108,7 +110,7 @@ func TestSanitizeBranchName(t *testing.T) {
 }
 
 func TestGetFeatureBranchName(t *testing.T) {
-	g := New(".")
+	g := New(".", "")
 
 	name := g.GetFeatureBranchName("F001", "User Authentication")
 	expected := "feature/F001-user-authentication"
@@ -119,7 +121,7 @@ func TestGetFeatureBranchName(t *testing.T) {
 }
 
 func TestGetFeatureBranchNameTruncation(t *testing.T) {
-	g := New(".")
+	g := New(".", "")
 
 	// Very long name should be truncated
 	longName := "This is a very long feature name that should be truncated"
@@ -138,7 +140,7 @@ func TestIsWorkingTreeClean(t *testing.T) {
 	repoDir, cleanup := setupTestRepo(t)
 	defer cleanup()
 
-	g := New(repoDir)
+	g := New(repoDir, "")
 
 	// Should be clean initially
 	if !g.IsWorkingTreeClean() {
@@ -158,7 +160,7 @@ func TestHasUncommittedChanges(t *testing.T) {
 	repoDir, cleanup := setupTestRepo(t)
 	defer cleanup()
 
-	g := New(repoDir)
+	g := New(repoDir, "")
 
 	if g.HasUncommittedChanges() {
 		t.Error("expected no uncommitted changes initially")
@@ -177,7 +179,7 @@ func TestStageAndCommit(t *testing.T) {
 	repoDir, cleanup := setupTestRepo(t)
 	defer cleanup()
 
-	g := New(repoDir)
+	g := New(repoDir, "")
 
 	// Create a new file
 	testFile := filepath.Join(repoDir, "newfile.txt")
@@ -212,11 +214,109 @@ func TestStageAndCommit(t *testing.T) {
 	}
 }
 
+func TestGetFileDiffAndCommittedFiles(t *testing.T) {
+	repoDir, cleanup := setupTestRepo(t)
+	defer cleanup()
+
+	g := New(repoDir, "")
+	base, _ := g.GetLastCommitHash()
+
+	os.WriteFile(filepath.Join(repoDir, "README.md"), []byte("# Test\n\nUpdated"), 0644)
+	os.MkdirAll(filepath.Join(repoDir, "src"), 0755)
+	os.WriteFile(filepath.Join(repoDir, "src", "main.go"), []byte("package main\n"), 0644)
+	g.StageAll()
+	g.Commit("Change files")
+	head, _ := g.GetLastCommitHash()
+
+	files, err := g.GetCommittedFiles(base, head)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(files, ",") != "README.md,src/main.go" {
+		t.Errorf("expected README.md and src/main.go, got %v", files)
+	}
+
+	diff, err := g.GetFileDiff(base, head, "README.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(diff, "+Updated") || strings.Contains(diff, "main.go") {
+		t.Errorf("expected README.md diff only, got %s", diff)
+	}
+
+	content, err := g.GetFileAt(head, "src/main.go")
+	if err != nil || content != "package main\n" {
+		t.Errorf("expected src/main.go at head, got %q, %v", content, err)
+	}
+
+	// A blank last context line is kept
+	os.WriteFile(filepath.Join(repoDir, "notes.txt"), []byte("one\ntwo\n\n"), 0644)
+	g.StageAll()
+	g.Commit("Add notes")
+	base, _ = g.GetLastCommitHash()
+	os.WriteFile(filepath.Join(repoDir, "notes.txt"), []byte("ONE\ntwo\n\n"), 0644)
+	g.StageAll()
+	g.Commit("Change notes")
+	head, _ = g.GetLastCommitHash()
+	diff, err = g.GetFileDiff(base, head, "notes.txt")
+	if err != nil || !strings.HasSuffix(diff, "+ONE\n two\n \n") {
+		t.Errorf("expected the blank context line to be kept, got %q, %v", diff, err)
+	}
+
+	if _, err := g.GetCommittedFiles("unknown", head); err == nil {
+		t.Error("expected error for unknown commit")
+	}
+}
+
+func TestCheckpoints(t *testing.T) {
+	repoDir, cleanup := setupTestRepo(t)
+	defer cleanup()
+
+	g := New(repoDir, "")
+	os.WriteFile(filepath.Join(repoDir, "work.txt"), []byte("loop 5"), 0644)
+	if err := g.CreateCheckpoint(5, "T001"); err != nil {
+		t.Fatal(err)
+	}
+	if g.HasUncommittedChanges() {
+		t.Error("expected checkpoint to commit all changes")
+	}
+	msg, _ := g.GetLastCommitMessage()
+	if msg != "feat(T001): checkpoint at loop 5" {
+		t.Errorf("unexpected checkpoint message %q", msg)
+	}
+	checkpoint, err := g.GetCheckpointCommit(5)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A clean tree is tagged without a new commit
+	if err := g.CreateCheckpoint(10, "T001"); err != nil {
+		t.Fatal(err)
+	}
+	if sha, _ := g.GetCheckpointCommit(10); sha != checkpoint {
+		t.Errorf("expected checkpoint 10 at %s, got %s", checkpoint, sha)
+	}
+
+	os.WriteFile(filepath.Join(repoDir, "work.txt"), []byte("loop 15"), 0644)
+	g.CreateCheckpoint(15, "T002")
+	if err := g.ResetHard(checkpoint); err != nil {
+		t.Fatal(err)
+	}
+	content, _ := os.ReadFile(filepath.Join(repoDir, "work.txt"))
+	if string(content) != "loop 5" {
+		t.Errorf("expected work of loop 5 after reset, got %q", content)
+	}
+
+	if _, err := g.GetCheckpointCommit(99); err == nil {
+		t.Error("expected error for missing checkpoint")
+	}
+}
+
 func TestCommitTask(t *testing.T) {
 	repoDir, cleanup := setupTestRepo(t)
 	defer cleanup()
 
-	g := New(repoDir)
+	g := New(repoDir, "")
 
 	// Create and stage a file
 	testFile := filepath.Join(repoDir, "task.txt")
@@ -239,7 +339,7 @@ func TestBranchOperations(t *testing.T) {
 	repoDir, cleanup := setupTestRepo(t)
 	defer cleanup()
 
-	g := New(repoDir)
+	g := New(repoDir, "")
 
 	// Create feature branch
 	branchName, err := g.CreateFeatureBranch("F001", "User Auth")
@@ -268,7 +368,7 @@ func TestListBranches(t *testing.T) {
 	repoDir, cleanup := setupTestRepo(t)
 	defer cleanup()
 
-	g := New(repoDir)
+	g := New(repoDir, "")
 
 	branches, err := g.ListBranches()
 	if err != nil {
@@ -279,3 +379,261 @@ func TestListBranches(t *testing.T) {
 		t.Error("expected at least one branch")
 	}
 }
+
+func TestCommitTaskBranch(t *testing.T) {
+	repoDir, cleanup := setupTestRepo(t)
+	defer cleanup()
+
+	g := New(repoDir, "")
+	m := NewParallelBranchManager(g)
+
+	// Nothing to commit is not an error
+	if err := m.CommitTaskBranch("T001", "Noop", repoDir); err != nil {
+		t.Fatalf("CommitTaskBranch with clean tree failed: %v", err)
+	}
+
+	os.WriteFile(filepath.Join(repoDir, "task.txt"), []byte("done"), 0644)
+	if err := m.CommitTaskBranch("T001", "Add file", repoDir); err != nil {
+		t.Fatalf("CommitTaskBranch failed: %v", err)
+	}
+
+	if g.HasUncommittedChanges() {
+		t.Error("expected changes to be committed")
+	}
+
+	out, _ := exec.Command("git", "-C", repoDir, "log", "-1", "--format=%s").Output()
+	if !strings.Contains(string(out), "T001") {
+		t.Errorf("expected commit message to reference T001, got %q", string(out))
+	}
+}
+
+func TestRecordLastCommitAndDiff(t *testing.T) {
+	repoDir, cleanup := setupTestRepo(t)
+	defer cleanup()
+
+	g := New(repoDir, "")
+
+	for _, id := range []string{"T001", "T002"} {
+		os.WriteFile(filepath.Join(repoDir, id+".txt"), []byte("content of "+id), 0644)
+		g.StageAll()
+		if err := g.CommitTask(id, "Add "+id); err != nil {
+			t.Fatalf("CommitTask failed: %v", err)
+		}
+		if err := g.RecordLastCommit(id); err != nil {
+			t.Fatalf("RecordLastCommit failed: %v", err)
+		}
+	}
+
+	record, err := LoadCommitRecord(LastCommitPath(repoDir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	head, _ := g.GetLastCommitHash()
+	if record.TaskID != "T002" || record.SHA != head {
+		t.Errorf("expected last commit T002 at %s, got %s at %s", head, record.TaskID, record.SHA)
+	}
+	if len(record.Tasks) != 2 {
+		t.Errorf("expected 2 recorded tasks, got %v", record.Tasks)
+	}
+
+	found, err := g.FindTaskCommit("T001")
+	if err != nil || found != record.Tasks["T001"] {
+		t.Errorf("expected FindTaskCommit to return %s, got %s (%v)", record.Tasks["T001"], found, err)
+	}
+
+	diff, err := g.GetCommitDiff(record.Tasks["T001"], false)
+	if err != nil {
+		t.Fatalf("GetCommitDiff failed: %v", err)
+	}
+	if !strings.Contains(diff, "+content of T001") || strings.Contains(diff, "T002") {
+		t.Errorf("expected diff of T001 only, got %q", diff)
+	}
+}
+
+func TestLoadCommitRecordMissing(t *testing.T) {
+	record, err := LoadCommitRecord(filepath.Join(os.TempDir(), "hermes-missing", "last-commit.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if record.SHA != "" || record.Tasks == nil {
+		t.Errorf("expected empty record, got %+v", record)
+	}
+}
+
+func TestPush(t *testing.T) {
+	tmpDir, cleanup := setupTestRepo(t)
+	defer cleanup()
+
+	remoteDir := t.TempDir()
+	if output, err := exec.Command("git", "init", "--bare", remoteDir).CombinedOutput(); err != nil {
+		t.Fatalf("git init --bare failed: %v: %s", err, output)
+	}
+	cmd := exec.Command("git", "remote", "add", "upstream", remoteDir)
+	cmd.Dir = tmpDir
+	if err := cmd.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	g := New(tmpDir, "")
+	if g.Remote() != DefaultRemote {
+		t.Errorf("expected default remote %s, got %s", DefaultRemote, g.Remote())
+	}
+	if err := g.Push("master", ""); err == nil {
+		t.Error("expected pushing to the missing origin remote to fail")
+	}
+
+	g = New(tmpDir, "upstream")
+	if g.Remote() != "upstream" {
+		t.Errorf("expected remote upstream, got %s", g.Remote())
+	}
+	branch, _ := g.GetCurrentBranch()
+	if err := g.Push(branch, ""); err != nil {
+		t.Fatalf("Push failed: %v", err)
+	}
+	if err := g.CreateFeatureTag("F001", "Auth", "1.2.0"); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.PushTag(FeatureTagName("1.2.0"), ""); err != nil {
+		t.Fatalf("PushTag failed: %v", err)
+	}
+
+	for _, ref := range []string{"refs/heads/" + branch, "refs/tags/v1.2.0"} {
+		cmd := exec.Command("git", "rev-parse", "--verify", ref)
+		cmd.Dir = remoteDir
+		if err := cmd.Run(); err != nil {
+			t.Errorf("expected %s on the remote", ref)
+		}
+	}
+}
+
+func TestStashAndApply(t *testing.T) {
+	repoDir, cleanup := setupTestRepo(t)
+	defer cleanup()
+
+	g := New(repoDir, "")
+	os.WriteFile(filepath.Join(repoDir, "wip.txt"), []byte("work in progress"), 0644)
+
+	var cleanDuringFn bool
+	err := g.StashAndApply(func() error {
+		cleanDuringFn = g.IsWorkingTreeClean()
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("StashAndApply failed: %v", err)
+	}
+	if !cleanDuringFn {
+		t.Error("expected a clean working tree while fn runs")
+	}
+	if data, err := os.ReadFile(filepath.Join(repoDir, "wip.txt")); err != nil || string(data) != "work in progress" {
+		t.Errorf("expected the stashed file to be restored, got %q (%v)", data, err)
+	}
+
+	// The stash is popped even if fn fails
+	fnErr := g.StashAndApply(func() error { return os.ErrInvalid })
+	if fnErr != os.ErrInvalid {
+		t.Errorf("expected fn's error, got %v", fnErr)
+	}
+	if g.IsWorkingTreeClean() {
+		t.Error("expected the changes to be restored after a failing fn")
+	}
+}
+
+func TestWithLock(t *testing.T) {
+	g := New(t.TempDir(), "")
+
+	// Unsynchronized access inside WithLock is safe (checked by go test -race)
+	var wg sync.WaitGroup
+	count := 0
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			g.WithLock(func(*Git) error {
+				count++
+				return nil
+			})
+		}()
+	}
+	wg.Wait()
+	if count != 5 {
+		t.Errorf("expected 5 calls, got %d", count)
+	}
+}
+
+func TestRebaseOntoBase(t *testing.T) {
+	repoDir, cleanup := setupTestRepo(t)
+	defer cleanup()
+
+	g := New(repoDir, "")
+	m := NewParallelBranchManager(g)
+	if ok, _ := m.CanRebase("T001"); ok {
+		t.Error("expected a task without branch not to be rebasable")
+	}
+
+	worktree, err := m.CreateWorktree("T001")
+	if err != nil {
+		t.Fatalf("CreateWorktree failed: %v", err)
+	}
+	defer m.RemoveWorktree("T001")
+	commit := func(dir, file, content string) {
+		t.Helper()
+		os.WriteFile(filepath.Join(dir, file), []byte(content), 0644)
+		if err := New(dir, "").StageAll(); err != nil {
+			t.Fatal(err)
+		}
+		if err := New(dir, "").Commit("Change " + file); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	commit(worktree, "task.txt", "task")
+	commit(repoDir, "base.txt", "base")
+	ok, reason := m.CanRebase("T001")
+	if !ok || !strings.Contains(reason, "1 commit(s) behind") {
+		t.Fatalf("expected T001 to be rebasable, got %v, %q", ok, reason)
+	}
+	if err := m.RebaseOntoBase("T001"); err != nil {
+		t.Fatalf("RebaseOntoBase failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(worktree, "base.txt")); err != nil {
+		t.Error("expected the base change in the rebased worktree")
+	}
+
+	// A detached worktree is put back on its branch first
+	if _, err := New(worktree, "").run("checkout", "--detach"); err != nil {
+		t.Fatal(err)
+	}
+	commit(repoDir, "base2.txt", "base")
+	if err := m.RebaseOntoBase("T001"); err != nil {
+		t.Fatalf("RebaseOntoBase of a detached worktree failed: %v", err)
+	}
+	if branch, _ := New(worktree, "").GetCurrentBranch(); branch != "hermes/T001" {
+		t.Errorf("expected the worktree on hermes/T001, got %s", branch)
+	}
+
+	// Conflicting changes abort the rebase, the merge fallback resolves them
+	commit(worktree, "README.md", "# Task")
+	commit(repoDir, "README.md", "# Base")
+	head, _ := New(worktree, "").GetLastCommitHash()
+	var conflict *RebaseConflictError
+	if err := m.RebaseOntoBase("T001"); !errors.As(err, &conflict) || len(conflict.Files) != 1 || conflict.Files[0] != "README.md" {
+		t.Fatalf("expected a conflict in README.md, got %v", err)
+	}
+	if after, _ := New(worktree, "").GetLastCommitHash(); after != head {
+		t.Error("expected a conflicting rebase to leave the branch unchanged")
+	}
+
+	err = m.MergeBaseIntoTask("T001", func(file, original, ours, theirs string) (string, error) {
+		if original != "# Test" || ours != "# Task" || theirs != "# Base" {
+			t.Errorf("unexpected versions of %s: %q, %q, %q", file, original, ours, theirs)
+		}
+		return "# Task and Base\n", nil
+	})
+	if err != nil {
+		t.Fatalf("MergeBaseIntoTask failed: %v", err)
+	}
+	content, _ := os.ReadFile(filepath.Join(worktree, "README.md"))
+	if string(content) != "# Task and Base\n" || New(worktree, "").HasUncommittedChanges() {
+		t.Errorf("expected the resolved merge to be committed, got %q", content)
+	}
+}
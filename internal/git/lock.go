@@ -0,0 +1,35 @@
+package git
+
+import "fmt"
+
+// WithLock runs fn with exclusive access to the repository: callers sharing
+// this Git serialize, so their status, add and commit calls do not
+// interleave. fn must not call WithLock or StashAndApply itself.
+func (g *Git) WithLock(fn func(*Git) error) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return fn(g)
+}
+
+// StashAndApply stashes the uncommitted changes, including untracked files,
+// runs fn on the clean working tree and pops the stash again. It holds the
+// lock of WithLock while doing so. The stash is popped even if fn fails.
+func (g *Git) StashAndApply(fn func() error) error {
+	return g.WithLock(func(g *Git) error {
+		if g.IsWorkingTreeClean() {
+			return fn()
+		}
+
+		if output, err := g.run("stash", "push", "--include-untracked", "-m", "hermes: StashAndApply"); err != nil {
+			return fmt.Errorf("git stash: %s", output)
+		}
+		fnErr := fn()
+		if output, err := g.run("stash", "pop"); err != nil {
+			if fnErr != nil {
+				return fmt.Errorf("%w (and git stash pop failed: %s)", fnErr, output)
+			}
+			return fmt.Errorf("git stash pop: %s", output)
+		}
+		return fnErr
+	})
+}
@@ -5,6 +5,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 )
 
 // ParallelBranchManager manages branches for parallel task execution
@@ -13,6 +14,7 @@ type ParallelBranchManager struct {
 	baseBranch string
 	branches   map[string]string // taskID -> branchName
 	worktrees  map[string]string // taskID -> worktree path
+	mu         sync.Mutex
 }
 
 // NewParallelBranchManager creates a new parallel branch manager
@@ -140,6 +142,30 @@ func (m *ParallelBranchManager) MergeBranches(taskIDs []string) error {
 	return nil
 }
 
+// CommitTaskBranch commits all changes in a task's working directory as a
+// separate task commit on its branch. Safe to call from multiple workers.
+func (m *ParallelBranchManager) CommitTaskBranch(taskID, taskName, workPath string) error {
+	taskGit := New(workPath, m.git.remote)
+	if !taskGit.HasUncommittedChanges() {
+		return nil
+	}
+
+	if err := taskGit.StageAll(); err != nil {
+		return fmt.Errorf("failed to stage changes for %s: %w", taskID, err)
+	}
+	if err := taskGit.CommitTask(taskID, taskName); err != nil {
+		return fmt.Errorf("failed to commit task %s: %w", taskID, err)
+	}
+
+	if branch, err := taskGit.GetCurrentBranch(); err == nil {
+		m.mu.Lock()
+		m.branches[taskID] = branch
+		m.mu.Unlock()
+	}
+
+	return nil
+}
+
 // GetConflicts returns conflicting files between two branches
 func (m *ParallelBranchManager) GetConflicts(taskID1, taskID2 string) ([]string, error) {
 	branch1, ok := m.branches[taskID1]
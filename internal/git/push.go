@@ -0,0 +1,26 @@
+package git
+
+import "fmt"
+
+// Push pushes a branch to a remote and sets it as the branch's upstream.
+// An empty remote uses the default remote.
+func (g *Git) Push(branchName, remote string) error {
+	if remote == "" {
+		remote = g.remote
+	}
+	if output, err := g.run("push", "-u", remote, branchName); err != nil {
+		return fmt.Errorf("failed to push %s to %s: %s", branchName, remote, output)
+	}
+	return nil
+}
+
+// PushTag pushes a tag to a remote. An empty remote uses the default remote.
+func (g *Git) PushTag(tag, remote string) error {
+	if remote == "" {
+		remote = g.remote
+	}
+	if output, err := g.run("push", remote, "refs/tags/"+tag); err != nil {
+		return fmt.Errorf("failed to push tag %s to %s: %s", tag, remote, output)
+	}
+	return nil
+}
@@ -0,0 +1,229 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// RebaseConflictError is returned by RebaseOntoBase when the task branch
+// does not rebase cleanly. The rebase is aborted, so the branch is left as
+// it was.
+type RebaseConflictError struct {
+	TaskID string
+	Base   string
+	Files  []string // Files with conflicting changes
+}
+
+func (e *RebaseConflictError) Error() string {
+	return fmt.Sprintf("rebasing task %s onto %s conflicts in %s", e.TaskID, e.Base, strings.Join(e.Files, ", "))
+}
+
+// CanRebase reports whether the branch of a task can be rebased onto the
+// base branch, with the reason if it cannot or how far behind it is if it
+// can
+func (m *ParallelBranchManager) CanRebase(taskID string) (bool, string) {
+	if m.baseBranch == "" || m.baseBranch == "HEAD" {
+		return false, "no base branch"
+	}
+	branch := m.taskBranch(taskID)
+	if !m.git.BranchExists(branch) {
+		return false, fmt.Sprintf("branch %s does not exist", branch)
+	}
+	path, err := m.taskWorktree(taskID)
+	if err != nil {
+		return false, err.Error()
+	}
+
+	wt := New(path, m.git.remote)
+	if wt.IsMergeInProgress() || wt.isRebaseInProgress() {
+		return false, "a merge or rebase is in progress in the worktree"
+	}
+	if wt.HasUncommittedChanges() {
+		return false, "the worktree has uncommitted changes"
+	}
+
+	behind, err := wt.run("rev-list", "--count", "HEAD.."+m.baseBranch)
+	if err != nil {
+		return false, fmt.Sprintf("failed to compare with %s: %s", m.baseBranch, behind)
+	}
+	if behind == "0" {
+		return true, fmt.Sprintf("up to date with %s", m.baseBranch)
+	}
+	return true, fmt.Sprintf("%s commit(s) behind %s", behind, m.baseBranch)
+}
+
+// RebaseOntoBase rebases the branch of a task onto the base branch in the
+// task's worktree, so its changes apply to the current base. A worktree in
+// detached HEAD state is first put back on the task branch, as long as the
+// detached commit contains the branch. A conflicting rebase is aborted and
+// returns a *RebaseConflictError.
+func (m *ParallelBranchManager) RebaseOntoBase(taskID string) error {
+	if ok, reason := m.CanRebase(taskID); !ok {
+		return fmt.Errorf("cannot rebase task %s: %s", taskID, reason)
+	}
+	branch := m.taskBranch(taskID)
+	path, err := m.taskWorktree(taskID)
+	if err != nil {
+		return err
+	}
+	wt := New(path, m.git.remote)
+
+	if current, _ := wt.GetCurrentBranch(); current == "HEAD" {
+		if _, err := wt.run("merge-base", "--is-ancestor", branch, "HEAD"); err != nil {
+			return fmt.Errorf("worktree of task %s is detached at a commit without the commits of %s", taskID, branch)
+		}
+		if output, err := wt.run("checkout", "-B", branch); err != nil {
+			return fmt.Errorf("failed to check out %s in the worktree: %s", branch, output)
+		}
+	}
+
+	if output, err := wt.run("rebase", m.baseBranch); err != nil {
+		files, _ := wt.unmergedFiles()
+		wt.run("rebase", "--abort")
+		if len(files) == 0 {
+			return fmt.Errorf("failed to rebase task %s onto %s: %s", taskID, m.baseBranch, output)
+		}
+		return &RebaseConflictError{TaskID: taskID, Base: m.baseBranch, Files: files}
+	}
+
+	m.mu.Lock()
+	m.branches[taskID] = branch
+	m.mu.Unlock()
+	return nil
+}
+
+// taskBranch returns the branch of a task: the one recorded by
+// CreateTaskBranch or CommitTaskBranch, or hermes/<task>
+func (m *ParallelBranchManager) taskBranch(taskID string) string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if branch, ok := m.branches[taskID]; ok {
+		return branch
+	}
+	return fmt.Sprintf("hermes/%s", taskID)
+}
+
+// taskWorktree returns the worktree of a task: the one made by
+// CreateWorktree, else the worktree with the task branch checked out, else
+// a worktree named after the task (hermes-<task> or wt-<task>), which may
+// be detached
+func (m *ParallelBranchManager) taskWorktree(taskID string) (string, error) {
+	m.mu.Lock()
+	path, ok := m.worktrees[taskID]
+	m.mu.Unlock()
+	if ok {
+		return path, nil
+	}
+
+	output, err := m.git.run("worktree", "list", "--porcelain")
+	if err != nil {
+		return "", fmt.Errorf("failed to list worktrees: %s", output)
+	}
+	ref := "branch refs/heads/" + m.taskBranch(taskID)
+	var named string
+	for _, block := range strings.Split(output, "\n\n") {
+		lines := strings.Split(strings.TrimSpace(block), "\n")
+		if len(lines) == 0 || !strings.HasPrefix(lines[0], "worktree ") {
+			continue
+		}
+		path := strings.TrimPrefix(lines[0], "worktree ")
+		for _, line := range lines[1:] {
+			if line == ref {
+				return path, nil
+			}
+		}
+		if base := filepath.Base(path); base == "hermes-"+taskID || base == "wt-"+taskID {
+			named = path
+		}
+	}
+	if named == "" {
+		return "", fmt.Errorf("no worktree found for task %s", taskID)
+	}
+	return named, nil
+}
+
+// isRebaseInProgress checks if a rebase is in progress
+func (g *Git) isRebaseInProgress() bool {
+	for _, dir := range []string{"rebase-merge", "rebase-apply"} {
+		path, err := g.run("rev-parse", "--git-path", dir)
+		if err != nil {
+			continue
+		}
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(g.workDir, path)
+		}
+		if _, err := os.Stat(path); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// unmergedFiles returns the files with unresolved conflicts
+func (g *Git) unmergedFiles() ([]string, error) {
+	output, err := g.run("diff", "--name-only", "--diff-filter=U")
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, line := range strings.Split(output, "\n") {
+		if line != "" {
+			files = append(files, line)
+		}
+	}
+	return files, nil
+}
+
+// ResolveFunc returns the resolved content of a conflicting file from its
+// content at the merge base, on the task branch (ours) and on the base
+// branch (theirs)
+type ResolveFunc func(file, original, ours, theirs string) (string, error)
+
+// MergeBaseIntoTask merges the base branch into the branch of a task in its
+// worktree, the fallback when RebaseOntoBase conflicts. Each conflicting
+// file is resolved by resolve. If any file cannot be resolved, the merge is
+// aborted and the branch is left as it was.
+func (m *ParallelBranchManager) MergeBaseIntoTask(taskID string, resolve ResolveFunc) error {
+	path, err := m.taskWorktree(taskID)
+	if err != nil {
+		return err
+	}
+	wt := New(path, m.git.remote)
+
+	message := fmt.Sprintf("Merge %s into task %s", m.baseBranch, taskID)
+	output, err := wt.run("merge", "--no-ff", "-m", message, m.baseBranch)
+	if err == nil {
+		return nil
+	}
+	files, _ := wt.unmergedFiles()
+	if len(files) == 0 {
+		wt.run("merge", "--abort")
+		return fmt.Errorf("failed to merge %s into task %s: %s", m.baseBranch, taskID, output)
+	}
+
+	for _, file := range files {
+		// Stages 1-3 are missing for files added or deleted on one side
+		original, _ := wt.run("show", ":1:"+file)
+		ours, _ := wt.run("show", ":2:"+file)
+		theirs, _ := wt.run("show", ":3:"+file)
+		resolved, err := resolve(file, original, ours, theirs)
+		if err == nil {
+			err = os.WriteFile(filepath.Join(path, file), []byte(resolved), 0644)
+		}
+		if err == nil {
+			_, err = wt.run("add", "--", file)
+		}
+		if err != nil {
+			wt.run("merge", "--abort")
+			return fmt.Errorf("failed to resolve %s: %w", file, err)
+		}
+	}
+
+	if output, err := wt.run("commit", "--no-edit"); err != nil {
+		wt.run("merge", "--abort")
+		return fmt.Errorf("failed to commit merge of %s into task %s: %s", m.baseBranch, taskID, output)
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package git
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// CommitRecord remembers the commits created for completed tasks so their
+// changes can be reviewed later with 'hermes diff'
+type CommitRecord struct {
+	TaskID string            `json:"taskId"` // Last committed task
+	SHA    string            `json:"sha"`
+	Time   time.Time         `json:"time"`
+	Tasks  map[string]string `json:"tasks"` // Task ID -> commit SHA
+}
+
+// LastCommitPath returns the path of the persisted commit record
+func LastCommitPath(workDir string) string {
+	return filepath.Join(workDir, ".hermes", "last-commit.json")
+}
+
+// LoadCommitRecord reads the commit record from a JSON file.
+// A missing file yields an empty record.
+func LoadCommitRecord(path string) (*CommitRecord, error) {
+	record := &CommitRecord{Tasks: make(map[string]string)}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return record, nil
+		}
+		return nil, fmt.Errorf("failed to read commit record: %w", err)
+	}
+	if err := json.Unmarshal(data, record); err != nil {
+		return nil, fmt.Errorf("failed to parse commit record: %w", err)
+	}
+	if record.Tasks == nil {
+		record.Tasks = make(map[string]string)
+	}
+	return record, nil
+}
+
+// Record marks sha as the commit of taskID and as the last commit
+func (r *CommitRecord) Record(taskID, sha string) {
+	r.TaskID = taskID
+	r.SHA = sha
+	r.Time = time.Now()
+	r.Tasks[taskID] = sha
+}
+
+// Save writes the commit record to a JSON file
+func (r *CommitRecord) Save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create record directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode commit record: %w", err)
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// RecordLastCommit stores HEAD as the commit of taskID in .hermes/last-commit.json
+func (g *Git) RecordLastCommit(taskID string) error {
+	sha, err := g.GetLastCommitHash()
+	if err != nil {
+		return fmt.Errorf("failed to get commit hash: %w", err)
+	}
+
+	path := LastCommitPath(g.workDir)
+	record, err := LoadCommitRecord(path)
+	if err != nil {
+		return err
+	}
+	record.Record(taskID, sha)
+	return record.Save(path)
+}
@@ -0,0 +1,179 @@
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultBaseURL is the GitHub REST API endpoint
+const DefaultBaseURL = "https://api.github.com"
+
+// maxPerPage is the largest page size accepted by the issues API
+const maxPerPage = 100
+
+var (
+	repoURLRegex  = regexp.MustCompile(`github\.com[:/]([^/]+)/([^/]+?)(?:\.git)?/?$`)
+	issueURLRegex = regexp.MustCompile(`^(?:https?://)?github\.com/([^/]+)/([^/]+)/issues/(\d+)/?(?:[?#].*)?$`)
+	issueRefRegex = regexp.MustCompile(`^([^/\s]+)/([^/#\s]+)#(\d+)$`)
+)
+
+// Label is a GitHub issue label
+type Label struct {
+	Name string `json:"name"`
+}
+
+// User is a GitHub user reference
+type User struct {
+	Login string `json:"login"`
+}
+
+// Milestone is a GitHub milestone
+type Milestone struct {
+	Title string `json:"title"`
+}
+
+// Issue is an open GitHub issue
+type Issue struct {
+	Number      int        `json:"number"`
+	Title       string     `json:"title"`
+	Body        string     `json:"body"`
+	HTMLURL     string     `json:"html_url"`
+	Labels      []Label    `json:"labels"`
+	Assignee    *User      `json:"assignee"`
+	Milestone   *Milestone `json:"milestone"`
+	PullRequest *struct{}  `json:"pull_request,omitempty"`
+}
+
+// Client is a minimal GitHub REST API client
+type Client struct {
+	baseURL string
+	token   string
+	client  *http.Client
+}
+
+// NewClient creates a client authenticated with GITHUB_TOKEN if it is set
+func NewClient() *Client {
+	return &Client{
+		baseURL: DefaultBaseURL,
+		token:   os.Getenv("GITHUB_TOKEN"),
+		client:  &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// SetBaseURL overrides the API endpoint (used for GitHub Enterprise and tests)
+func (c *Client) SetBaseURL(baseURL string) {
+	c.baseURL = strings.TrimRight(baseURL, "/")
+}
+
+// ListOpenIssues returns up to max open issues of a repository, excluding
+// pull requests. A max of 0 or less returns all open issues.
+func (c *Client) ListOpenIssues(owner, repo string, max int) ([]Issue, error) {
+	var issues []Issue
+
+	for page := 1; ; page++ {
+		url := fmt.Sprintf("%s/repos/%s/%s/issues?state=open&per_page=%d&page=%d", c.baseURL, owner, repo, maxPerPage, page)
+		var batch []Issue
+		if err := c.get(url, &batch); err != nil {
+			return nil, err
+		}
+
+		for _, issue := range batch {
+			if issue.PullRequest != nil {
+				continue
+			}
+			issues = append(issues, issue)
+			if max > 0 && len(issues) >= max {
+				return issues, nil
+			}
+		}
+
+		if len(batch) < maxPerPage {
+			return issues, nil
+		}
+	}
+}
+
+// GetIssue returns a single issue of a repository
+func (c *Client) GetIssue(owner, repo string, number int) (*Issue, error) {
+	url := fmt.Sprintf("%s/repos/%s/%s/issues/%d", c.baseURL, owner, repo, number)
+	var issue Issue
+	if err := c.get(url, &issue); err != nil {
+		return nil, err
+	}
+	return &issue, nil
+}
+
+func (c *Client) get(url string, out interface{}) error {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Accept", "application/vnd.github+json")
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("GitHub request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GitHub API returned status %d for %s", resp.StatusCode, url)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode GitHub response: %w", err)
+	}
+	return nil
+}
+
+// ParseRepoURL extracts the owner and repository name from a GitHub remote URL
+// (https://github.com/owner/repo.git or git@github.com:owner/repo.git)
+func ParseRepoURL(url string) (string, string, error) {
+	m := repoURLRegex.FindStringSubmatch(strings.TrimSpace(url))
+	if len(m) < 3 {
+		return "", "", fmt.Errorf("not a GitHub repository URL: %s", url)
+	}
+	return m[1], m[2], nil
+}
+
+// ParseRepo parses an owner/repo slug or a GitHub URL
+func ParseRepo(repo string) (string, string, error) {
+	if strings.Contains(repo, "github.com") {
+		return ParseRepoURL(repo)
+	}
+
+	parts := strings.Split(strings.Trim(repo, "/"), "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("invalid repository %q (expected owner/repo)", repo)
+	}
+	return parts[0], parts[1], nil
+}
+
+// ParseIssueRef parses an issue URL (https://github.com/owner/repo/issues/N)
+// or reference (owner/repo#N) into the owner, repository and issue number
+func ParseIssueRef(ref string) (string, string, int, error) {
+	ref = strings.TrimSpace(ref)
+	m := issueURLRegex.FindStringSubmatch(ref)
+	if m == nil {
+		m = issueRefRegex.FindStringSubmatch(ref)
+	}
+	if m == nil {
+		return "", "", 0, fmt.Errorf("invalid issue %q (expected https://github.com/owner/repo/issues/N or owner/repo#N)", ref)
+	}
+
+	number, err := strconv.Atoi(m[3])
+	if err != nil || number <= 0 {
+		return "", "", 0, fmt.Errorf("invalid issue number in %q", ref)
+	}
+	return m[1], m[2], number, nil
+}
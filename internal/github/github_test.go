@@ -0,0 +1,176 @@
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestListOpenIssues(t *testing.T) {
+	var auth, path, state string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		path = r.URL.Path
+		state = r.URL.Query().Get("state")
+		json.NewEncoder(w).Encode([]map[string]interface{}{
+			{"number": 1, "title": "Bug", "labels": []map[string]string{{"name": "bug"}}},
+			{"number": 2, "title": "PR", "pull_request": map[string]string{"url": "x"}},
+			{"number": 3, "title": "Feature", "assignee": map[string]string{"login": "octo"}, "milestone": map[string]string{"title": "v1.2"}},
+		})
+	}))
+	defer server.Close()
+
+	t.Setenv("GITHUB_TOKEN", "secret")
+	c := NewClient()
+	c.SetBaseURL(server.URL)
+
+	issues, err := c.ListOpenIssues("owner", "repo", 0)
+	if err != nil {
+		t.Fatalf("ListOpenIssues failed: %v", err)
+	}
+
+	if auth != "Bearer secret" {
+		t.Errorf("expected token header, got %q", auth)
+	}
+	if path != "/repos/owner/repo/issues" || state != "open" {
+		t.Errorf("unexpected request %s state=%s", path, state)
+	}
+	if len(issues) != 2 {
+		t.Fatalf("expected pull requests to be skipped, got %d issues", len(issues))
+	}
+	if issues[1].Assignee == nil || issues[1].Assignee.Login != "octo" {
+		t.Errorf("expected assignee octo, got %+v", issues[1].Assignee)
+	}
+	if issues[1].Milestone == nil || issues[1].Milestone.Title != "v1.2" {
+		t.Errorf("expected milestone v1.2, got %+v", issues[1].Milestone)
+	}
+}
+
+func TestListOpenIssuesPaginationAndMax(t *testing.T) {
+	var pages int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		pages++
+		var batch []map[string]interface{}
+		for i := 0; i < maxPerPage; i++ {
+			batch = append(batch, map[string]interface{}{"number": pages*1000 + i, "title": fmt.Sprintf("Issue %d", i)})
+		}
+		json.NewEncoder(w).Encode(batch)
+	}))
+	defer server.Close()
+
+	c := NewClient()
+	c.SetBaseURL(server.URL)
+
+	issues, err := c.ListOpenIssues("owner", "repo", 150)
+	if err != nil {
+		t.Fatalf("ListOpenIssues failed: %v", err)
+	}
+	if len(issues) != 150 {
+		t.Errorf("expected 150 issues, got %d", len(issues))
+	}
+	if pages != 2 {
+		t.Errorf("expected 2 pages, got %d", pages)
+	}
+}
+
+func TestListOpenIssuesError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	c := NewClient()
+	c.SetBaseURL(server.URL)
+
+	if _, err := c.ListOpenIssues("owner", "repo", 0); err == nil {
+		t.Error("expected error for 401 response")
+	}
+}
+
+func TestParseRepo(t *testing.T) {
+	tests := []struct {
+		input string
+		owner string
+		repo  string
+		ok    bool
+	}{
+		{"https://github.com/owner/repo.git", "owner", "repo", true},
+		{"https://github.com/owner/repo", "owner", "repo", true},
+		{"git@github.com:owner/repo.git\n", "owner", "repo", true},
+		{"owner/repo", "owner", "repo", true},
+		{"owner", "", "", false},
+		{"https://gitlab.com/owner/repo.git", "", "", false},
+	}
+
+	for _, tt := range tests {
+		owner, repo, err := ParseRepo(tt.input)
+		if (err == nil) != tt.ok {
+			t.Errorf("ParseRepo(%q) error = %v, expected ok=%v", tt.input, err, tt.ok)
+			continue
+		}
+		if owner != tt.owner || repo != tt.repo {
+			t.Errorf("ParseRepo(%q) = %s/%s, expected %s/%s", tt.input, owner, repo, tt.owner, tt.repo)
+		}
+	}
+}
+
+func TestGetIssue(t *testing.T) {
+	var path string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"number":    42,
+			"title":     "Login fails",
+			"body":      "Steps to reproduce",
+			"html_url":  "https://github.com/owner/repo/issues/42",
+			"labels":    []map[string]string{{"name": "bug"}},
+			"milestone": map[string]string{"title": "v1.3"},
+		})
+	}))
+	defer server.Close()
+
+	c := NewClient()
+	c.SetBaseURL(server.URL)
+
+	issue, err := c.GetIssue("owner", "repo", 42)
+	if err != nil {
+		t.Fatalf("GetIssue failed: %v", err)
+	}
+	if path != "/repos/owner/repo/issues/42" {
+		t.Errorf("unexpected request path %s", path)
+	}
+	if issue.Title != "Login fails" || len(issue.Labels) != 1 || issue.Milestone == nil || issue.Milestone.Title != "v1.3" {
+		t.Errorf("unexpected issue: %+v", issue)
+	}
+}
+
+func TestParseIssueRef(t *testing.T) {
+	tests := []struct {
+		input  string
+		owner  string
+		repo   string
+		number int
+		ok     bool
+	}{
+		{"https://github.com/owner/repo/issues/12", "owner", "repo", 12, true},
+		{"github.com/owner/repo/issues/7/", "owner", "repo", 7, true},
+		{"https://github.com/owner/repo/issues/3#issuecomment-1", "owner", "repo", 3, true},
+		{"owner/repo#5", "owner", "repo", 5, true},
+		{"owner/repo", "", "", 0, false},
+		{"https://github.com/owner/repo/pull/12", "", "", 0, false},
+		{"owner/repo#0", "", "", 0, false},
+	}
+
+	for _, tt := range tests {
+		owner, repo, number, err := ParseIssueRef(tt.input)
+		if (err == nil) != tt.ok {
+			t.Errorf("ParseIssueRef(%q) error = %v, expected ok=%v", tt.input, err, tt.ok)
+			continue
+		}
+		if owner != tt.owner || repo != tt.repo || number != tt.number {
+			t.Errorf("ParseIssueRef(%q) = %s/%s#%d, expected %s/%s#%d", tt.input, owner, repo, number, tt.owner, tt.repo, tt.number)
+		}
+	}
+}
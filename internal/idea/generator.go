@@ -58,22 +58,9 @@ func (g *Generator) Generate(ctx context.Context, opts GenerateOptions) (*Genera
 	g.logger.Debug("Idea: %s", opts.Idea)
 	g.logger.Debug("Language: %s", opts.Language)
 
-	// Execute AI with retry
-	result, err := ai.ExecuteWithRetry(ctx, g.provider, &ai.ExecuteOptions{
-		Prompt:       prompt,
-		WorkDir:      ".",
-		Timeout:      opts.Timeout,
-		StreamOutput: g.config.AI.StreamOutput,
-	}, &ai.RetryConfig{
-		MaxRetries: 3,
-		Delay:      5 * time.Second,
-	})
+	result, err := g.execute(ctx, prompt, opts.Timeout)
 	if err != nil {
-		return nil, fmt.Errorf("AI execution failed: %w", err)
-	}
-
-	if !result.Success {
-		return nil, fmt.Errorf("AI execution failed: %s", result.Error)
+		return nil, err
 	}
 
 	prdContent := result.Output
@@ -100,3 +87,48 @@ func (g *Generator) Generate(ctx context.Context, opts GenerateOptions) (*Genera
 		Duration:   duration,
 	}, nil
 }
+
+// Revise applies a change request to a PRD and returns the revised PRD.
+// Nothing is written to disk.
+func (g *Generator) Revise(ctx context.Context, prd, change, language string, timeout int) (*GenerateResult, error) {
+	startTime := time.Now()
+
+	g.logger.Info("Revising PRD...")
+	g.logger.Debug("Change: %s", change)
+
+	result, err := g.execute(ctx, BuildRevisePrompt(prd, change, language), timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GenerateResult{
+		PRDContent: result.Output,
+		TokensUsed: result.TokensIn + result.TokensOut,
+		Duration:   time.Since(startTime),
+	}, nil
+}
+
+// execute runs a prompt with retry and fails on unsuccessful results
+func (g *Generator) execute(ctx context.Context, prompt string, timeout int) (*ai.ExecuteResult, error) {
+	result, err := ai.ExecuteWithRetry(ctx, g.provider, &ai.ExecuteOptions{
+		Prompt:       prompt,
+		WorkDir:      ".",
+		Timeout:      timeout,
+		StreamOutput: g.config.AI.StreamOutput,
+	}, &ai.RetryConfig{
+		MaxRetries:        3,
+		Delay:             5 * time.Second,
+		BackoffMultiplier: 2,
+		MaxDelay:          60 * time.Second,
+		Jitter:            true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("AI execution failed: %w", err)
+	}
+
+	if !result.Success {
+		return nil, fmt.Errorf("AI execution failed: %s", result.Error)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,57 @@
+package idea
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Revision is one version of a PRD produced by 'hermes idea --refine'
+type Revision struct {
+	Timestamp time.Time `json:"timestamp"`
+	Request   string    `json:"request"` // The idea for the first version, then the change request
+	Content   string    `json:"content"`
+}
+
+// HistoryPath returns the path of the PRD revision history in docsDir
+func HistoryPath(docsDir string) string {
+	return filepath.Join(docsDir, "PRD-history.json")
+}
+
+// LoadHistory reads the revision history. A missing file yields no revisions.
+func LoadHistory(path string) ([]Revision, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read PRD history: %w", err)
+	}
+
+	var history []Revision
+	if err := json.Unmarshal(data, &history); err != nil {
+		return nil, fmt.Errorf("failed to parse PRD history: %w", err)
+	}
+	return history, nil
+}
+
+// AppendHistory adds revisions to the history file
+func AppendHistory(path string, revisions []Revision) error {
+	history, err := LoadHistory(path)
+	if err != nil {
+		return err
+	}
+	history = append(history, revisions...)
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create PRD history directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(history, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode PRD history: %w", err)
+	}
+	return os.WriteFile(path, data, 0644)
+}
@@ -82,6 +82,29 @@ Note: Write the entire PRD in Turkish. Use Turkish section headers:
 	return sb.String()
 }
 
+// BuildRevisePrompt builds the AI prompt applying a change request to a PRD
+func BuildRevisePrompt(prd, change, language string) string {
+	var sb strings.Builder
+
+	sb.WriteString("Revise the following PRD:\n\n")
+	sb.WriteString(prd)
+	sb.WriteString("\n\n")
+
+	sb.WriteString("## Requested Changes\n")
+	sb.WriteString(change)
+	sb.WriteString("\n\n")
+
+	sb.WriteString(`## Output Format
+
+Output ONLY the complete revised PRD in Markdown format. Keep everything the change request does not touch.
+Do not include any explanations or meta-commentary.
+
+`)
+	sb.WriteString(fmt.Sprintf("Language: %s\n", getLanguageName(language)))
+
+	return sb.String()
+}
+
 func getLanguageName(code string) string {
 	switch code {
 	case "tr":
@@ -92,3 +115,66 @@ func getLanguageName(code string) string {
 		return "English"
 	}
 }
+
+// BuildStructuredPrompt builds the AI prompt for PRD generation as a JSON
+// document
+func BuildStructuredPrompt(idea, language, additionalContext string) string {
+	var sb strings.Builder
+
+	sb.WriteString("You are a senior product manager. Generate a PRD (Product Requirements Document) for the following idea as a JSON document.\n\n")
+
+	sb.WriteString("## Idea\n")
+	sb.WriteString(idea)
+	sb.WriteString("\n\n")
+
+	if additionalContext != "" {
+		sb.WriteString("## Additional Context\n")
+		sb.WriteString(additionalContext)
+		sb.WriteString("\n\n")
+	}
+
+	sb.WriteString(`## Requirements
+
+- List 3-6 features, with IDs F001, F002, ...
+- Break each feature into 2-6 implementable tasks. Task IDs are unique across
+  all features: T001, T002, ...
+- priority is one of P1, P2, P3, P4 (P1 = most important)
+- effort is an estimate such as "2h", "1d" or "3 SP"
+- files lists the files the task creates or changes
+- criteria lists testable success criteria
+
+## Output Format
+
+Output ONLY a JSON document with exactly this structure. Do not use a code
+fence and do not include any explanations or meta-commentary.
+
+{
+  "title": "Project name",
+  "version": "1.0.0",
+  "features": [
+    {
+      "id": "F001",
+      "name": "Feature name",
+      "priority": "P1",
+      "overview": "What the feature does and why",
+      "goals": ["Goal"],
+      "tasks": [
+        {
+          "id": "T001",
+          "name": "Task name",
+          "description": "What to implement",
+          "files": ["path/to/file"],
+          "effort": "2h",
+          "criteria": ["Success criterion"]
+        }
+      ]
+    }
+  ]
+}
+
+`)
+
+	sb.WriteString(fmt.Sprintf("Language of the text values: %s\n", getLanguageName(language)))
+
+	return sb.String()
+}
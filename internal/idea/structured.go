@@ -0,0 +1,93 @@
+package idea
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// Output formats of 'hermes idea'
+const (
+	FormatMarkdown = "markdown"
+	FormatJSON     = "json"
+	FormatYAML     = "yaml"
+)
+
+// StructuredPRD is a PRD as data, for pipelines that do not parse Markdown
+type StructuredPRD struct {
+	Title    string              `json:"title" yaml:"title"`
+	Version  string              `json:"version" yaml:"version"`
+	Features []StructuredFeature `json:"features" yaml:"features"`
+}
+
+// StructuredFeature is a feature of a StructuredPRD
+type StructuredFeature struct {
+	ID       string           `json:"id" yaml:"id"`
+	Name     string           `json:"name" yaml:"name"`
+	Priority string           `json:"priority" yaml:"priority"`
+	Overview string           `json:"overview" yaml:"overview"`
+	Goals    []string         `json:"goals" yaml:"goals"`
+	Tasks    []StructuredTask `json:"tasks" yaml:"tasks"`
+}
+
+// StructuredTask is a task of a StructuredFeature
+type StructuredTask struct {
+	ID          string   `json:"id" yaml:"id"`
+	Name        string   `json:"name" yaml:"name"`
+	Description string   `json:"description" yaml:"description"`
+	Files       []string `json:"files" yaml:"files"`
+	Effort      string   `json:"effort" yaml:"effort"`
+	Criteria    []string `json:"criteria" yaml:"criteria"`
+}
+
+// GenerateStructured generates a PRD as a StructuredPRD. The AI is asked
+// for JSON only; nothing is written to disk.
+func (g *Generator) GenerateStructured(ctx context.Context, opts GenerateOptions) (*StructuredPRD, error) {
+	g.logger.Info("Generating structured PRD...")
+	g.logger.Debug("Idea: %s", opts.Idea)
+	g.logger.Debug("Language: %s", opts.Language)
+
+	result, err := g.execute(ctx, BuildStructuredPrompt(opts.Idea, opts.Language, opts.AdditionalContext), opts.Timeout)
+	if err != nil {
+		return nil, err
+	}
+	return ParseStructuredPRD(result.Output)
+}
+
+// ParseStructuredPRD parses the JSON document in AI output, ignoring a
+// Markdown code fence or text around it
+func ParseStructuredPRD(output string) (*StructuredPRD, error) {
+	start := strings.Index(output, "{")
+	end := strings.LastIndex(output, "}")
+	if start < 0 || end < start {
+		return nil, fmt.Errorf("AI output contains no JSON document")
+	}
+
+	var prd StructuredPRD
+	if err := json.Unmarshal([]byte(output[start:end+1]), &prd); err != nil {
+		return nil, fmt.Errorf("failed to parse structured PRD: %w", err)
+	}
+	if prd.Title == "" || len(prd.Features) == 0 {
+		return nil, fmt.Errorf("structured PRD has no title or no features")
+	}
+	return &prd, nil
+}
+
+// Encode returns the PRD in the given format: json or yaml
+func (p *StructuredPRD) Encode(format string) ([]byte, error) {
+	switch format {
+	case FormatJSON:
+		data, err := json.MarshalIndent(p, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return append(data, '\n'), nil
+	case FormatYAML:
+		return yaml.Marshal(p)
+	default:
+		return nil, fmt.Errorf("unknown PRD format %q (use json or yaml)", format)
+	}
+}
@@ -0,0 +1,267 @@
+package isolation
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// copySkipPaths are project paths not copied into copy workspaces
+var copySkipPaths = map[string]bool{
+	".git":              true,
+	".hermes/worktrees": true,
+	".hermes/logs":      true,
+}
+
+// CopyWorkspace isolates a task in a copy of the project in a temp dir. The
+// copy has no git history: a fresh repository with a single base commit is
+// created in it to track the task's changes, which ApplyChanges writes back
+// as a patch. This works for projects that are not git repositories.
+type CopyWorkspace struct {
+	TaskID     string
+	BasePath   string // Original project path
+	WorkPath   string // Temp dir holding the copy, empty until Setup
+	baseCommit string
+}
+
+// NewCopyWorkspace creates a new copy workspace configuration
+func NewCopyWorkspace(taskID, basePath string) *CopyWorkspace {
+	return &CopyWorkspace{
+		TaskID:   taskID,
+		BasePath: basePath,
+	}
+}
+
+// Setup copies the project into a temp dir and commits it as the base
+func (w *CopyWorkspace) Setup() error {
+	if w.WorkPath != "" {
+		if err := w.Cleanup(); err != nil {
+			return fmt.Errorf("failed to cleanup existing copy: %w", err)
+		}
+	}
+
+	dir, err := os.MkdirTemp("", fmt.Sprintf("hermes-copy-%s-*", w.TaskID))
+	if err != nil {
+		return fmt.Errorf("failed to create copy dir: %w", err)
+	}
+	w.WorkPath = dir
+
+	if err := copyTree(w.BasePath, w.WorkPath); err != nil {
+		w.Cleanup()
+		return fmt.Errorf("failed to copy project: %w", err)
+	}
+
+	if _, err := w.git("init", "-q"); err != nil {
+		w.Cleanup()
+		return fmt.Errorf("failed to init copy: %w", err)
+	}
+	if err := w.CommitChanges("Base for task " + w.TaskID); err != nil {
+		w.Cleanup()
+		return err
+	}
+	head, err := w.git("rev-parse", "HEAD")
+	if err != nil {
+		w.Cleanup()
+		return fmt.Errorf("failed to get base commit: %w", err)
+	}
+	w.baseCommit = strings.TrimSpace(head)
+
+	return nil
+}
+
+// Cleanup removes the copy
+func (w *CopyWorkspace) Cleanup() error {
+	if w.WorkPath == "" {
+		return nil
+	}
+	if err := os.RemoveAll(w.WorkPath); err != nil {
+		return fmt.Errorf("failed to remove copy: %w", err)
+	}
+	w.WorkPath = ""
+	w.baseCommit = ""
+	return nil
+}
+
+// GetChanges returns the files changed in the copy since the base commit
+func (w *CopyWorkspace) GetChanges() ([]string, error) {
+	if _, err := w.git("add", "-A"); err != nil {
+		return nil, fmt.Errorf("failed to stage changes: %w", err)
+	}
+	output, err := w.git("diff", "--cached", "--name-only", w.baseCommit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get changes: %w", err)
+	}
+
+	var files []string
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		if line != "" {
+			files = append(files, line)
+		}
+	}
+	return files, nil
+}
+
+// GetDiff returns the diff of the changes made in the copy
+func (w *CopyWorkspace) GetDiff() (string, error) {
+	if _, err := w.git("add", "-A"); err != nil {
+		return "", fmt.Errorf("failed to stage changes: %w", err)
+	}
+	output, err := w.git("diff", "--cached", w.baseCommit)
+	if err != nil {
+		return "", fmt.Errorf("failed to get diff: %w", err)
+	}
+	return output, nil
+}
+
+// HasUncommittedChanges returns true if there are uncommitted changes in the copy
+func (w *CopyWorkspace) HasUncommittedChanges() bool {
+	output, err := w.git("status", "--porcelain")
+	if err != nil {
+		return false
+	}
+	return strings.TrimSpace(output) != ""
+}
+
+// CommitChanges commits all changes in the copy. The commit stays in the
+// copy; use ApplyChanges to bring the changes to the project.
+func (w *CopyWorkspace) CommitChanges(message string) error {
+	if _, err := w.git("add", "-A"); err != nil {
+		return fmt.Errorf("failed to stage changes: %w", err)
+	}
+	if _, err := w.git("diff", "--cached", "--quiet"); err == nil && w.baseCommit != "" {
+		// No changes to commit
+		return nil
+	}
+	// The copy has no user config, so the identity is given explicitly
+	if _, err := w.git("-c", "user.name=Hermes", "-c", "user.email=hermes@localhost",
+		"commit", "-q", "--allow-empty", "-m", message); err != nil {
+		return fmt.Errorf("failed to commit: %w", err)
+	}
+	return nil
+}
+
+// ApplyChanges applies the changes made in the copy to targetDir as a
+// patch. A patch that does not apply returns a *PatchConflictError and
+// leaves targetDir unchanged.
+func (w *CopyWorkspace) ApplyChanges(targetDir string) error {
+	patch, err := w.patch()
+	if err != nil {
+		return err
+	}
+	return ApplyPatch(targetDir, patch, false)
+}
+
+// CheckChanges reports whether ApplyChanges would succeed without changing
+// targetDir
+func (w *CopyWorkspace) CheckChanges(targetDir string) error {
+	patch, err := w.patch()
+	if err != nil {
+		return err
+	}
+	return ApplyPatch(targetDir, patch, true)
+}
+
+// patch returns the binary diff of everything changed since the base commit
+func (w *CopyWorkspace) patch() (string, error) {
+	if w.baseCommit == "" {
+		return "", fmt.Errorf("workspace for %s has no base commit (not set up)", w.TaskID)
+	}
+	if _, err := w.git("add", "-A"); err != nil {
+		return "", fmt.Errorf("failed to stage changes: %w", err)
+	}
+	output, err := w.git("diff", "--cached", "--binary", w.baseCommit)
+	if err != nil {
+		return "", fmt.Errorf("failed to get diff: %w", err)
+	}
+	return output, nil
+}
+
+// git runs a git command in the copy
+func (w *CopyWorkspace) git(args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = w.WorkPath
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", err, string(output))
+	}
+	return string(output), nil
+}
+
+// GetTaskID returns the ID of the task using this workspace
+func (w *CopyWorkspace) GetTaskID() string {
+	return w.TaskID
+}
+
+// GetBranch returns "" since copy workspaces do not use a task branch
+func (w *CopyWorkspace) GetBranch() string {
+	return ""
+}
+
+// GetWorkPath returns the path of the copy
+func (w *CopyWorkspace) GetWorkPath() string {
+	return w.WorkPath
+}
+
+// IsIsolated returns true while the copy exists
+func (w *CopyWorkspace) IsIsolated() bool {
+	return w.WorkPath != ""
+}
+
+// copyTree copies the files under src to dst, keeping file modes and
+// symlinks and skipping copySkipPaths
+func copyTree(src, dst string) error {
+	return filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		if copySkipPaths[filepath.ToSlash(rel)] {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		target := filepath.Join(dst, rel)
+
+		switch {
+		case info.IsDir():
+			return os.MkdirAll(target, info.Mode().Perm())
+		case info.Mode()&os.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(link, target)
+		case info.Mode().IsRegular():
+			return copyFile(path, target, info.Mode().Perm())
+		default:
+			// Sockets, devices and pipes are not project files
+			return nil
+		}
+	})
+}
+
+// copyFile copies a single regular file
+func copyFile(src, dst string, perm os.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,328 @@
+package isolation
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// DefaultDockerImage is the image used for Docker workspaces. It must contain
+// git and the AI CLI (claude, droid or gemini).
+const DefaultDockerImage = "hermes-agent:latest"
+
+// ContainerPath is where the project is copied inside the container
+const ContainerPath = "/workspace"
+
+// passthroughEnv lists the host variables forwarded to the AI CLI in the container
+var passthroughEnv = []string{"ANTHROPIC_API_KEY", "GEMINI_API_KEY", "GOOGLE_API_KEY", "FACTORY_API_KEY"}
+
+// Ensure all workspace kinds satisfy the interface
+var (
+	_ Workspace = (*WorktreeWorkspace)(nil)
+	_ Workspace = (*DockerWorkspace)(nil)
+	_ Workspace = (*CopyWorkspace)(nil)
+)
+
+// DockerWorkspace isolates a task in a container holding a copy of the
+// project. The AI CLI runs inside it through docker exec and the changed
+// files are copied back with CopyBack.
+type DockerWorkspace struct {
+	TaskID      string
+	BasePath    string // Original repository path
+	Image       string
+	ContainerID string
+	baseCommit  string // HEAD of the copied project, changes are diffed against it
+	wrapperDir  string
+}
+
+// NewDockerWorkspace creates a new Docker workspace configuration
+func NewDockerWorkspace(taskID, basePath, image string) *DockerWorkspace {
+	if image == "" {
+		image = DefaultDockerImage
+	}
+	return &DockerWorkspace{
+		TaskID:   taskID,
+		BasePath: basePath,
+		Image:    image,
+	}
+}
+
+// DockerAvailable returns true if the docker CLI is installed and the daemon responds
+func DockerAvailable() bool {
+	if _, err := exec.LookPath("docker"); err != nil {
+		return false
+	}
+	return exec.Command("docker", "info").Run() == nil
+}
+
+// Setup starts the container and copies the project into it
+func (w *DockerWorkspace) Setup() error {
+	if w.ContainerID != "" {
+		if err := w.Cleanup(); err != nil {
+			return fmt.Errorf("failed to cleanup existing container: %w", err)
+		}
+	}
+
+	// Prompt files are written to the host temp dir by the providers, so it
+	// is mounted read-only at the same path inside the container
+	tmp := os.TempDir()
+	args := []string{"run", "-d",
+		"--name", fmt.Sprintf("hermes-%s-%d", strings.ToLower(w.TaskID), os.Getpid()),
+		"-v", fmt.Sprintf("%s:%s:ro", tmp, tmp),
+		"-w", ContainerPath,
+		w.Image, "sleep", "infinity",
+	}
+	output, err := exec.Command("docker", args...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to start container: %w: %s", err, string(output))
+	}
+	w.ContainerID = strings.TrimSpace(string(output))
+
+	absBase, err := filepath.Abs(w.BasePath)
+	if err != nil {
+		w.Cleanup()
+		return err
+	}
+	cmd := exec.Command("docker", "cp", absBase+string(filepath.Separator)+".", w.ContainerID+":"+ContainerPath)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		w.Cleanup()
+		return fmt.Errorf("failed to copy project into container: %w: %s", err, string(output))
+	}
+
+	if head, err := w.exec("git", "rev-parse", "HEAD"); err == nil {
+		w.baseCommit = strings.TrimSpace(head)
+	}
+
+	return nil
+}
+
+// Cleanup removes the container and the CLI wrappers
+func (w *DockerWorkspace) Cleanup() error {
+	if w.wrapperDir != "" {
+		os.RemoveAll(w.wrapperDir)
+		w.wrapperDir = ""
+	}
+	if w.ContainerID == "" {
+		return nil
+	}
+
+	cmd := exec.Command("docker", "rm", "-f", w.ContainerID)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to remove container: %w: %s", err, string(output))
+	}
+	w.ContainerID = ""
+	return nil
+}
+
+// CommandWrapper writes an executable script that runs cli inside the
+// container and returns its path, for use as the provider's CLI path
+func (w *DockerWorkspace) CommandWrapper(cli string) (string, error) {
+	if w.ContainerID == "" {
+		return "", fmt.Errorf("container for %s is not running", w.TaskID)
+	}
+
+	if w.wrapperDir == "" {
+		dir, err := os.MkdirTemp("", "hermes-docker-*")
+		if err != nil {
+			return "", fmt.Errorf("failed to create wrapper dir: %w", err)
+		}
+		w.wrapperDir = dir
+	}
+
+	path := filepath.Join(w.wrapperDir, cli)
+	if err := os.WriteFile(path, []byte(w.wrapperScript(cli)), 0755); err != nil {
+		return "", fmt.Errorf("failed to write wrapper: %w", err)
+	}
+	return path, nil
+}
+
+// wrapperScript returns the shell script forwarding its arguments to cli in the container
+func (w *DockerWorkspace) wrapperScript(cli string) string {
+	var sb strings.Builder
+	sb.WriteString("#!/bin/sh\n")
+	sb.WriteString("exec docker exec -i -w " + ContainerPath)
+	for _, env := range passthroughEnv {
+		sb.WriteString(" -e " + env)
+	}
+	sb.WriteString(fmt.Sprintf(" %s %s \"$@\"\n", w.ContainerID, cli))
+	return sb.String()
+}
+
+// exec runs a command in the project directory inside the container
+func (w *DockerWorkspace) exec(name string, args ...string) (string, error) {
+	dockerArgs := append([]string{"exec", "-w", ContainerPath, w.ContainerID, name}, args...)
+	output, err := exec.Command("docker", dockerArgs...).CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", err, string(output))
+	}
+	return string(output), nil
+}
+
+// nameStatus stages everything in the container and returns the changes
+// since the copied HEAD as git --name-status output
+func (w *DockerWorkspace) nameStatus() (string, error) {
+	if _, err := w.exec("git", "add", "-A"); err != nil {
+		return "", fmt.Errorf("failed to stage changes: %w", err)
+	}
+	args := []string{"diff", "--cached", "--name-status", "--no-renames"}
+	if w.baseCommit != "" {
+		args = append(args, w.baseCommit)
+	}
+	return w.exec("git", args...)
+}
+
+// GetChanges returns the files changed in the container
+func (w *DockerWorkspace) GetChanges() ([]string, error) {
+	output, err := w.nameStatus()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get changes: %w", err)
+	}
+	changed, deleted := parseNameStatus(output)
+	return append(changed, deleted...), nil
+}
+
+// GetDiff returns the diff of the changes made in the container
+func (w *DockerWorkspace) GetDiff() (string, error) {
+	if _, err := w.exec("git", "add", "-A"); err != nil {
+		return "", fmt.Errorf("failed to stage changes: %w", err)
+	}
+	args := []string{"diff", "--cached"}
+	if w.baseCommit != "" {
+		args = append(args, w.baseCommit)
+	}
+	output, err := w.exec("git", args...)
+	if err != nil {
+		return "", fmt.Errorf("failed to get diff: %w", err)
+	}
+	return output, nil
+}
+
+// HasUncommittedChanges returns true if there are uncommitted changes in the container
+func (w *DockerWorkspace) HasUncommittedChanges() bool {
+	output, err := w.exec("git", "status", "--porcelain")
+	if err != nil {
+		return false
+	}
+	return strings.TrimSpace(output) != ""
+}
+
+// CommitChanges commits all changes inside the container. The commit stays
+// in the container; use CopyBack to bring the files to the project.
+func (w *DockerWorkspace) CommitChanges(message string) error {
+	if _, err := w.exec("git", "add", "-A"); err != nil {
+		return fmt.Errorf("failed to stage changes: %w", err)
+	}
+	if _, err := w.exec("git", "diff", "--cached", "--quiet"); err == nil {
+		// No changes to commit
+		return nil
+	}
+	if _, err := w.exec("git", "commit", "-m", message); err != nil {
+		return fmt.Errorf("failed to commit: %w", err)
+	}
+	return nil
+}
+
+// ApplyChanges applies the changes made in the container to targetDir as a
+// patch. A patch that does not apply returns a *PatchConflictError and leaves
+// targetDir unchanged.
+func (w *DockerWorkspace) ApplyChanges(targetDir string) error {
+	patch, err := w.patch()
+	if err != nil {
+		return err
+	}
+	return ApplyPatch(targetDir, patch, false)
+}
+
+// CheckChanges reports whether ApplyChanges would succeed without changing
+// targetDir
+func (w *DockerWorkspace) CheckChanges(targetDir string) error {
+	patch, err := w.patch()
+	if err != nil {
+		return err
+	}
+	return ApplyPatch(targetDir, patch, true)
+}
+
+// patch stages everything in the container and returns the binary diff
+// since the copied HEAD
+func (w *DockerWorkspace) patch() (string, error) {
+	if _, err := w.exec("git", "add", "-A"); err != nil {
+		return "", fmt.Errorf("failed to stage changes: %w", err)
+	}
+	args := []string{"diff", "--cached", "--binary"}
+	if w.baseCommit != "" {
+		args = append(args, w.baseCommit)
+	}
+	output, err := w.exec("git", args...)
+	if err != nil {
+		return "", fmt.Errorf("failed to get diff: %w", err)
+	}
+	return output, nil
+}
+
+// CopyBack copies the files changed in the container into the project and
+// removes the ones deleted there. It returns the files it touched.
+func (w *DockerWorkspace) CopyBack() ([]string, error) {
+	output, err := w.nameStatus()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get changes: %w", err)
+	}
+	changed, deleted := parseNameStatus(output)
+
+	for _, file := range changed {
+		dest := filepath.Join(w.BasePath, file)
+		if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+			return nil, err
+		}
+		src := w.ContainerID + ":" + ContainerPath + "/" + file
+		if output, err := exec.Command("docker", "cp", src, dest).CombinedOutput(); err != nil {
+			return nil, fmt.Errorf("failed to copy %s: %w: %s", file, err, string(output))
+		}
+	}
+	for _, file := range deleted {
+		if err := os.Remove(filepath.Join(w.BasePath, file)); err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("failed to remove %s: %w", file, err)
+		}
+	}
+
+	return append(changed, deleted...), nil
+}
+
+// parseNameStatus splits git --name-status output into changed and deleted files
+func parseNameStatus(output string) (changed, deleted []string) {
+	for _, line := range strings.Split(output, "\n") {
+		status, file, ok := strings.Cut(strings.TrimSpace(line), "\t")
+		if !ok || file == "" {
+			continue
+		}
+		if strings.HasPrefix(status, "D") {
+			deleted = append(deleted, file)
+		} else {
+			changed = append(changed, file)
+		}
+	}
+	return changed, deleted
+}
+
+// GetTaskID returns the ID of the task using this workspace
+func (w *DockerWorkspace) GetTaskID() string {
+	return w.TaskID
+}
+
+// GetBranch returns "" since Docker workspaces do not use a task branch
+func (w *DockerWorkspace) GetBranch() string {
+	return ""
+}
+
+// GetWorkPath returns the project path; the AI works in ContainerPath
+// inside the container, which the CLI wrapper takes care of
+func (w *DockerWorkspace) GetWorkPath() string {
+	return w.BasePath
+}
+
+// IsIsolated returns true while the container is running
+func (w *DockerWorkspace) IsIsolated() bool {
+	return w.ContainerID != ""
+}
@@ -0,0 +1,80 @@
+package isolation
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+)
+
+// patchErrorRegex matches the files named in git apply's error messages
+var patchErrorRegex = regexp.MustCompile(`^error: (?:patch failed: (.+):\d+|(.+): (?:patch does not apply|already exists in working directory|does not exist in index|No such file or directory))$`)
+
+// PatchConflictError is returned when a workspace's changes do not apply
+// to the target directory
+type PatchConflictError struct {
+	Files  []string // Files whose hunks failed
+	Output string   // git apply output
+}
+
+func (e *PatchConflictError) Error() string {
+	if len(e.Files) == 0 {
+		return fmt.Sprintf("patch does not apply: %s", strings.TrimSpace(e.Output))
+	}
+	return fmt.Sprintf("patch does not apply to %s", strings.Join(e.Files, ", "))
+}
+
+// ApplyPatch applies a git diff to targetDir with git apply. With check set
+// it only verifies that the patch applies. An empty patch is a no-op.
+func ApplyPatch(targetDir, patch string, check bool) error {
+	if strings.TrimSpace(patch) == "" {
+		return nil
+	}
+
+	file, err := os.CreateTemp("", "hermes-patch-*.diff")
+	if err != nil {
+		return fmt.Errorf("failed to create patch file: %w", err)
+	}
+	defer os.Remove(file.Name())
+	if _, err := file.WriteString(patch); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write patch file: %w", err)
+	}
+	file.Close()
+
+	args := []string{"apply", "--whitespace=nowarn"}
+	if check {
+		args = append(args, "--check")
+	}
+	cmd := exec.Command("git", append(args, file.Name())...)
+	cmd.Dir = targetDir
+	if output, err := cmd.CombinedOutput(); err != nil {
+		if _, ok := err.(*exec.ExitError); !ok {
+			return fmt.Errorf("failed to run git apply: %w", err)
+		}
+		return &PatchConflictError{Files: patchConflictFiles(string(output)), Output: string(output)}
+	}
+	return nil
+}
+
+// patchConflictFiles returns the files named in git apply errors
+func patchConflictFiles(output string) []string {
+	var files []string
+	seen := make(map[string]bool)
+	for _, line := range strings.Split(output, "\n") {
+		m := patchErrorRegex.FindStringSubmatch(strings.TrimSpace(line))
+		if m == nil {
+			continue
+		}
+		file := m[1]
+		if file == "" {
+			file = m[2]
+		}
+		if !seen[file] {
+			seen[file] = true
+			files = append(files, file)
+		}
+	}
+	return files
+}
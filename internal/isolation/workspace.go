@@ -8,21 +8,67 @@ import (
 	"strings"
 )
 
-// Workspace represents an isolated workspace for a task
-type Workspace struct {
+// Isolation modes for parallel task execution
+const (
+	ModeNone     = "none"     // All tasks work in the project directory
+	ModeWorktree = "worktree" // A git worktree on a task branch per task
+	ModeDocker   = "docker"   // A container with a copy of the project per task
+	ModeCopy     = "copy"     // A copy of the project in a temp dir per task
+)
+
+// Modes lists the valid isolation modes
+var Modes = []string{ModeNone, ModeWorktree, ModeDocker, ModeCopy}
+
+// NewWorkspaceByMode creates the workspace of a task for an isolation mode.
+// ModeNone and an empty mode return a nil workspace, since tasks then share
+// basePath. Docker workspaces use DefaultDockerImage.
+func NewWorkspaceByMode(mode, taskID, basePath string) (Workspace, error) {
+	switch mode {
+	case "", ModeNone:
+		return nil, nil
+	case ModeWorktree:
+		return NewWorkspace(taskID, basePath), nil
+	case ModeDocker:
+		return NewDockerWorkspace(taskID, basePath, ""), nil
+	case ModeCopy:
+		return NewCopyWorkspace(taskID, basePath), nil
+	default:
+		return nil, fmt.Errorf("unknown isolation mode: %s (use %s)", mode, strings.Join(Modes, ", "))
+	}
+}
+
+// Workspace is an isolated place for a task's AI agent to work in
+type Workspace interface {
+	Setup() error
+	Cleanup() error
+	GetTaskID() string
+	GetBranch() string
+	GetWorkPath() string
+	IsIsolated() bool
+	GetChanges() ([]string, error)
+	GetDiff() (string, error)
+	HasUncommittedChanges() bool
+	CommitChanges(message string) error
+	ApplyChanges(targetDir string) error
+	CheckChanges(targetDir string) error
+}
+
+// WorktreeWorkspace isolates a task in a git worktree on its own branch
+type WorktreeWorkspace struct {
 	TaskID   string
 	BasePath string // Original repository path
 	WorkPath string // Isolated workspace path (git worktree)
 	Branch   string
+	BaseSHA  string // Commit the task branch was created from
 }
 
 // NewWorkspace creates a new workspace configuration
-func NewWorkspace(taskID, basePath string) *Workspace {
+func NewWorkspace(taskID, basePath string) *WorktreeWorkspace {
 	branchName := fmt.Sprintf("hermes/%s", taskID)
 	// Create worktree in project directory instead of temp
 	workPath := filepath.Join(basePath, ".hermes", "worktrees", fmt.Sprintf("wt-%s", taskID))
 
-	return &Workspace{
+	return &WorktreeWorkspace{
 		TaskID:   taskID,
 		BasePath: basePath,
 		WorkPath: workPath,
@@ -31,7 +77,7 @@ func NewWorkspace(taskID, basePath string) *Workspace {
 }
 
 // Setup creates the isolated workspace using git worktree
-func (w *Workspace) Setup() error {
+func (w *WorktreeWorkspace) Setup() error {
 	// Check if worktree already exists
 	if _, err := os.Stat(w.WorkPath); err == nil {
 		// Remove existing worktree
@@ -46,6 +92,14 @@ func (w *Workspace) Setup() error {
 		return fmt.Errorf("failed to get current branch: %w", err)
 	}
 
+	cmd := exec.Command("git", "rev-parse", baseBranch)
+	cmd.Dir = w.BasePath
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to get base commit: %w: %s", err, string(output))
+	}
+	w.BaseSHA = strings.TrimSpace(string(output))
+
 	// Create new branch for the task
 	if err := w.createBranch(baseBranch); err != nil {
 		// Branch might already exist, try to continue
@@ -55,9 +109,9 @@ func (w *Workspace) Setup() error {
 	}
 
 	// Create worktree
-	cmd := exec.Command("git", "worktree", "add", w.WorkPath, w.Branch)
+	cmd = exec.Command("git", "worktree", "add", w.WorkPath, w.Branch)
 	cmd.Dir = w.BasePath
-	output, err := cmd.CombinedOutput()
+	output, err = cmd.CombinedOutput()
 	if err != nil {
 		return fmt.Errorf("failed to create worktree: %w: %s", err, string(output))
 	}
@@ -67,13 +121,13 @@ func (w *Workspace) Setup() error {
 
 // SetupShared creates a workspace using the shared repository (no isolation)
 // This is faster but doesn't provide isolation
-func (w *Workspace) SetupShared() error {
+func (w *WorktreeWorkspace) SetupShared() error {
 	w.WorkPath = w.BasePath
 	return nil
 }
 
 // Cleanup removes the isolated workspace
-func (w *Workspace) Cleanup() error {
+func (w *WorktreeWorkspace) Cleanup() error {
 	// Remove worktree
 	cmd := exec.Command("git", "worktree", "remove", w.WorkPath, "--force")
 	cmd.Dir = w.BasePath
@@ -93,7 +147,7 @@ func (w *Workspace) Cleanup() error {
 }
 
 // CleanupBranch removes the task branch
-func (w *Workspace) CleanupBranch() error {
+func (w *WorktreeWorkspace) CleanupBranch() error {
 	cmd := exec.Command("git", "branch", "-D", w.Branch)
 	cmd.Dir = w.BasePath
 	if output, err := cmd.CombinedOutput(); err != nil {
@@ -103,7 +157,7 @@ func (w *Workspace) CleanupBranch() error {
 }
 
 // GetChanges returns the files changed in this workspace
-func (w *Workspace) GetChanges() ([]string, error) {
+func (w *WorktreeWorkspace) GetChanges() ([]string, error) {
 	cmd := exec.Command("git", "diff", "--name-only", "HEAD")
 	cmd.Dir = w.WorkPath
 	output, err := cmd.CombinedOutput()
@@ -122,7 +176,7 @@ func (w *Workspace) GetChanges() ([]string, error) {
 }
 
 // GetDiff returns the git diff for changes in this workspace
-func (w *Workspace) GetDiff() (string, error) {
+func (w *WorktreeWorkspace) GetDiff() (string, error) {
 	cmd := exec.Command("git", "diff", "HEAD")
 	cmd.Dir = w.WorkPath
 	output, err := cmd.CombinedOutput()
@@ -133,7 +187,7 @@ func (w *Workspace) GetDiff() (string, error) {
 }
 
 // CommitChanges commits all changes in the workspace
-func (w *Workspace) CommitChanges(message string) error {
+func (w *WorktreeWorkspace) CommitChanges(message string) error {
 	// Stage all changes
 	cmd := exec.Command("git", "add", "-A")
 	cmd.Dir = w.WorkPath
@@ -159,8 +213,44 @@ func (w *Workspace) CommitChanges(message string) error {
 	return nil
 }
 
+// ApplyChanges applies the changes committed in the workspace since BaseSHA
+// to targetDir as a patch, without merging the task branch. targetDir does
+// not have to be a git repository. A patch that does not apply returns a
+// *PatchConflictError and leaves targetDir unchanged.
+func (w *WorktreeWorkspace) ApplyChanges(targetDir string) error {
+	patch, err := w.committedPatch()
+	if err != nil {
+		return err
+	}
+	return ApplyPatch(targetDir, patch, false)
+}
+
+// CheckChanges reports whether ApplyChanges would succeed without changing
+// targetDir
+func (w *WorktreeWorkspace) CheckChanges(targetDir string) error {
+	patch, err := w.committedPatch()
+	if err != nil {
+		return err
+	}
+	return ApplyPatch(targetDir, patch, true)
+}
+
+// committedPatch returns the binary diff of the commits since BaseSHA
+func (w *WorktreeWorkspace) committedPatch() (string, error) {
+	if w.BaseSHA == "" {
+		return "", fmt.Errorf("workspace for %s has no base commit (not set up)", w.TaskID)
+	}
+	cmd := exec.Command("git", "diff", "--binary", w.BaseSHA+"..HEAD")
+	cmd.Dir = w.WorkPath
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to get diff: %w", err)
+	}
+	return string(output), nil
+}
+
 // PushChanges pushes changes to remote
-func (w *Workspace) PushChanges() error {
+func (w *WorktreeWorkspace) PushChanges() error {
 	cmd := exec.Command("git", "push", "-u", "origin", w.Branch)
 	cmd.Dir = w.WorkPath
 	if output, err := cmd.CombinedOutput(); err != nil {
@@ -170,7 +260,7 @@ func (w *Workspace) PushChanges() error {
 }
 
 // HasUncommittedChanges returns true if there are uncommitted changes
-func (w *Workspace) HasUncommittedChanges() bool {
+func (w *WorktreeWorkspace) HasUncommittedChanges() bool {
 	cmd := exec.Command("git", "status", "--porcelain")
 	cmd.Dir = w.WorkPath
 	output, err := cmd.CombinedOutput()
@@ -181,7 +271,7 @@ func (w *Workspace) HasUncommittedChanges() bool {
 }
 
 // getCurrentBranch returns the current branch name
-func (w *Workspace) getCurrentBranch() (string, error) {
+func (w *WorktreeWorkspace) getCurrentBranch() (string, error) {
 	cmd := exec.Command("git", "rev-parse", "--abbrev-ref", "HEAD")
 	cmd.Dir = w.BasePath
 	output, err := cmd.CombinedOutput()
@@ -192,7 +282,7 @@ func (w *Workspace) getCurrentBranch() (string, error) {
 }
 
 // createBranch creates a new branch from the current HEAD
-func (w *Workspace) createBranch(baseBranch string) error {
+func (w *WorktreeWorkspace) createBranch(baseBranch string) error {
 	cmd := exec.Command("git", "branch", w.Branch, baseBranch)
 	cmd.Dir = w.BasePath
 	if output, err := cmd.CombinedOutput(); err != nil {
@@ -201,17 +291,22 @@ func (w *Workspace) createBranch(baseBranch string) error {
 	return nil
 }
 
+// GetTaskID returns the ID of the task using this workspace
+func (w *WorktreeWorkspace) GetTaskID() string {
+	return w.TaskID
+}
+
 // GetBranch returns the branch name for this workspace
-func (w *Workspace) GetBranch() string {
+func (w *WorktreeWorkspace) GetBranch() string {
 	return w.Branch
 }
 
 // GetWorkPath returns the workspace path
-func (w *Workspace) GetWorkPath() string {
+func (w *WorktreeWorkspace) GetWorkPath() string {
 	return w.WorkPath
 }
 
 // IsIsolated returns true if this workspace is isolated (using worktree)
-func (w *Workspace) IsIsolated() bool {
+func (w *WorktreeWorkspace) IsIsolated() bool {
 	return w.WorkPath != w.BasePath
 }
@@ -0,0 +1,278 @@
+package isolation
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNewWorkspace(t *testing.T) {
+	w := NewWorkspace("T001", "/repo")
+
+	if w.GetTaskID() != "T001" {
+		t.Errorf("expected task ID T001, got %s", w.GetTaskID())
+	}
+	if w.GetBranch() != "hermes/T001" {
+		t.Errorf("expected branch hermes/T001, got %s", w.GetBranch())
+	}
+	if w.GetWorkPath() != filepath.Join("/repo", ".hermes", "worktrees", "wt-T001") {
+		t.Errorf("unexpected work path %s", w.GetWorkPath())
+	}
+	if !w.IsIsolated() {
+		t.Error("expected worktree workspace to be isolated")
+	}
+}
+
+func TestNewDockerWorkspace(t *testing.T) {
+	w := NewDockerWorkspace("T002", "/repo", "")
+
+	if w.Image != DefaultDockerImage {
+		t.Errorf("expected default image, got %s", w.Image)
+	}
+	if w.GetBranch() != "" {
+		t.Errorf("expected no branch, got %s", w.GetBranch())
+	}
+	if w.GetWorkPath() != "/repo" {
+		t.Errorf("expected work path /repo, got %s", w.GetWorkPath())
+	}
+	if w.IsIsolated() {
+		t.Error("expected workspace without container to not be isolated")
+	}
+	if _, err := w.CommandWrapper("claude"); err == nil {
+		t.Error("expected error creating a wrapper without a container")
+	}
+	if err := w.Cleanup(); err != nil {
+		t.Errorf("expected cleanup without container to succeed, got %v", err)
+	}
+}
+
+func TestDockerCommandWrapper(t *testing.T) {
+	w := NewDockerWorkspace("T003", "/repo", "img")
+	w.ContainerID = "abc123"
+	defer func() {
+		w.ContainerID = ""
+		w.Cleanup()
+	}()
+
+	path, err := w.CommandWrapper("droid")
+	if err != nil {
+		t.Fatalf("CommandWrapper failed: %v", err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("wrapper not written: %v", err)
+	}
+	if info.Mode()&0100 == 0 {
+		t.Error("expected wrapper to be executable")
+	}
+
+	content, _ := os.ReadFile(path)
+	script := string(content)
+	if !strings.HasPrefix(script, "#!/bin/sh\n") {
+		t.Errorf("expected shell script, got %q", script)
+	}
+	if !strings.Contains(script, "docker exec -i -w /workspace") {
+		t.Errorf("expected docker exec in project dir, got %q", script)
+	}
+	if !strings.Contains(script, `abc123 droid "$@"`) {
+		t.Errorf("expected CLI to run in container with args, got %q", script)
+	}
+	if !strings.Contains(script, "-e ANTHROPIC_API_KEY") {
+		t.Errorf("expected API keys to be forwarded, got %q", script)
+	}
+}
+
+func TestParseNameStatus(t *testing.T) {
+	output := "M\tsrc/main.go\nA\tsrc/new.go\nD\told.go\n\n"
+
+	changed, deleted := parseNameStatus(output)
+
+	if !reflect.DeepEqual(changed, []string{"src/main.go", "src/new.go"}) {
+		t.Errorf("unexpected changed files: %v", changed)
+	}
+	if !reflect.DeepEqual(deleted, []string{"old.go"}) {
+		t.Errorf("unexpected deleted files: %v", deleted)
+	}
+
+	changed, deleted = parseNameStatus("")
+	if len(changed) != 0 || len(deleted) != 0 {
+		t.Errorf("expected no changes, got %v %v", changed, deleted)
+	}
+}
+
+func gitRun(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %v failed: %v: %s", args, err, output)
+	}
+}
+
+func TestApplyChanges(t *testing.T) {
+	repo := t.TempDir()
+	gitRun(t, repo, "init")
+	gitRun(t, repo, "config", "user.email", "test@test.com")
+	gitRun(t, repo, "config", "user.name", "Test User")
+	os.WriteFile(filepath.Join(repo, "main.go"), []byte("package main\n"), 0644)
+	os.WriteFile(filepath.Join(repo, "old.go"), []byte("package old\n"), 0644)
+	gitRun(t, repo, "add", "-A")
+	gitRun(t, repo, "commit", "-m", "initial")
+
+	w := NewWorkspace("T001", repo)
+	if err := w.Setup(); err != nil {
+		t.Fatal(err)
+	}
+	defer w.Cleanup()
+	if w.BaseSHA == "" {
+		t.Fatal("expected Setup to record the base commit")
+	}
+
+	os.WriteFile(filepath.Join(w.WorkPath, "main.go"), []byte("package main\n\nfunc main() {}\n"), 0644)
+	os.WriteFile(filepath.Join(w.WorkPath, "new.go"), []byte("package main\n"), 0644)
+	os.Remove(filepath.Join(w.WorkPath, "old.go"))
+	if err := w.CommitChanges("T001 changes"); err != nil {
+		t.Fatal(err)
+	}
+
+	// A plain directory holding a copy of the base files
+	target := t.TempDir()
+	os.WriteFile(filepath.Join(target, "main.go"), []byte("package main\n"), 0644)
+	os.WriteFile(filepath.Join(target, "old.go"), []byte("package old\n"), 0644)
+
+	if err := w.CheckChanges(target); err != nil {
+		t.Fatalf("CheckChanges failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(target, "new.go")); err == nil {
+		t.Fatal("CheckChanges should not change the target")
+	}
+
+	if err := w.ApplyChanges(target); err != nil {
+		t.Fatalf("ApplyChanges failed: %v", err)
+	}
+	data, _ := os.ReadFile(filepath.Join(target, "main.go"))
+	if !strings.Contains(string(data), "func main()") {
+		t.Errorf("expected main.go to be patched, got %q", data)
+	}
+	if _, err := os.Stat(filepath.Join(target, "new.go")); err != nil {
+		t.Error("expected new.go to be created")
+	}
+	if _, err := os.Stat(filepath.Join(target, "old.go")); !os.IsNotExist(err) {
+		t.Error("expected old.go to be removed")
+	}
+
+	// The main working directory changed the same lines in the meantime
+	os.WriteFile(filepath.Join(repo, "main.go"), []byte("package app\n"), 0644)
+	err := w.ApplyChanges(repo)
+	var conflict *PatchConflictError
+	if !errors.As(err, &conflict) {
+		t.Fatalf("expected PatchConflictError, got %v", err)
+	}
+	if !reflect.DeepEqual(conflict.Files, []string{"main.go"}) {
+		t.Errorf("expected conflict in main.go, got %v (%s)", conflict.Files, conflict.Output)
+	}
+	if _, err := os.Stat(filepath.Join(repo, "new.go")); err == nil {
+		t.Error("a conflicting patch should not be partially applied")
+	}
+}
+
+func TestNewWorkspaceByMode(t *testing.T) {
+	for _, mode := range []string{"", ModeNone} {
+		w, err := NewWorkspaceByMode(mode, "T001", "/repo")
+		if err != nil || w != nil {
+			t.Errorf("mode %q: expected no workspace, got %v, %v", mode, w, err)
+		}
+	}
+
+	tests := map[string]string{
+		ModeWorktree: "*isolation.WorktreeWorkspace",
+		ModeDocker:   "*isolation.DockerWorkspace",
+		ModeCopy:     "*isolation.CopyWorkspace",
+	}
+	for mode, want := range tests {
+		w, err := NewWorkspaceByMode(mode, "T001", "/repo")
+		if err != nil {
+			t.Fatalf("mode %s: %v", mode, err)
+		}
+		if got := reflect.TypeOf(w).String(); got != want {
+			t.Errorf("mode %s: expected %s, got %s", mode, want, got)
+		}
+		if w.GetTaskID() != "T001" {
+			t.Errorf("mode %s: expected task ID T001, got %s", mode, w.GetTaskID())
+		}
+	}
+
+	if _, err := NewWorkspaceByMode("vm", "T001", "/repo"); err == nil {
+		t.Error("expected error for unknown mode")
+	}
+}
+
+func TestCopyWorkspace(t *testing.T) {
+	// The project does not need to be a git repository
+	project := t.TempDir()
+	os.MkdirAll(filepath.Join(project, "src"), 0755)
+	os.MkdirAll(filepath.Join(project, ".hermes", "logs"), 0755)
+	os.WriteFile(filepath.Join(project, "src", "main.go"), []byte("package main\n"), 0644)
+	os.WriteFile(filepath.Join(project, "run.sh"), []byte("#!/bin/sh\n"), 0755)
+	os.WriteFile(filepath.Join(project, ".hermes", "logs", "hermes.log"), []byte("log"), 0644)
+
+	w := NewCopyWorkspace("T001", project)
+	if w.IsIsolated() {
+		t.Error("expected copy workspace to not be isolated before Setup")
+	}
+	if err := w.Setup(); err != nil {
+		t.Fatal(err)
+	}
+	defer w.Cleanup()
+
+	if !w.IsIsolated() || w.GetWorkPath() == project || w.GetBranch() != "" {
+		t.Errorf("unexpected workspace: path %s, branch %q", w.GetWorkPath(), w.GetBranch())
+	}
+	if info, err := os.Stat(filepath.Join(w.WorkPath, "run.sh")); err != nil || info.Mode().Perm()&0100 == 0 {
+		t.Error("expected run.sh to be copied with its mode")
+	}
+	if _, err := os.Stat(filepath.Join(w.WorkPath, ".hermes", "logs")); !os.IsNotExist(err) {
+		t.Error("expected logs not to be copied")
+	}
+	if w.HasUncommittedChanges() {
+		t.Error("expected a fresh copy to have no changes")
+	}
+
+	os.WriteFile(filepath.Join(w.WorkPath, "src", "main.go"), []byte("package main\n\nfunc main() {}\n"), 0644)
+	os.WriteFile(filepath.Join(w.WorkPath, "src", "util.go"), []byte("package main\n"), 0644)
+
+	changes, err := w.GetChanges()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(changes, []string{"src/main.go", "src/util.go"}) {
+		t.Errorf("unexpected changes: %v", changes)
+	}
+	if err := w.CommitChanges("T001 changes"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := w.ApplyChanges(project); err != nil {
+		t.Fatalf("ApplyChanges failed: %v", err)
+	}
+	data, _ := os.ReadFile(filepath.Join(project, "src", "main.go"))
+	if !strings.Contains(string(data), "func main()") {
+		t.Errorf("expected main.go to be patched, got %q", data)
+	}
+	if _, err := os.Stat(filepath.Join(project, "src", "util.go")); err != nil {
+		t.Error("expected util.go to be created")
+	}
+
+	path := w.WorkPath
+	if err := w.Cleanup(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("expected the copy to be removed")
+	}
+}
@@ -0,0 +1,366 @@
+package jira
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Fields holding story points. Their custom field IDs differ between JIRA
+// sites; these are the common defaults of JIRA Cloud and Server.
+var storyPointFields = []string{
+	"storyPoints", "story_points", "Story Points",
+	"customfield_10016", "customfield_10026", "customfield_10002", "customfield_10004",
+}
+
+// Fields holding the key of an issue's epic in company-managed projects
+var epicLinkFields = []string{
+	"epic", "epicKey", "epicLink", "epic_link", "Epic Link",
+	"customfield_10014", "customfield_10008",
+}
+
+// Link phrases, seen from the issue holding the link, saying that the issue
+// depends on the linked one, or that the linked one depends on it
+var (
+	dependsOnPhrases = []string{"is blocked by", "depends on", "requires"}
+	blocksPhrases    = []string{"blocks", "is depended on by", "is required by"}
+)
+
+// Issue is an issue of a JIRA export
+type Issue struct {
+	Key            string
+	Summary        string
+	Description    string
+	Type           string // e.g. "Epic", "Story", "Task", "Sub-task"
+	Subtask        bool
+	Priority       string
+	Status         string
+	StatusCategory string // "new", "indeterminate" or "done", if exported
+	StoryPoints    float64
+	Parent         string // Key of the parent: the epic, or the story of a sub-task
+	ParentType     string // Issue type of the parent, if exported
+	Epic           string // Key of the epic from the epic link field
+	Links          []Link
+	Subtasks       []Issue // Sub-tasks nested in the issue
+}
+
+// Link is a link from an issue to another one
+type Link struct {
+	Type   string // e.g. "Blocks"
+	Phrase string // The link seen from the issue, e.g. "is blocked by"
+	Key    string // Key of the linked issue
+}
+
+// IsEpic reports whether the issue is an epic
+func (i *Issue) IsEpic() bool {
+	return strings.EqualFold(i.Type, "Epic")
+}
+
+// IsSubtask reports whether the issue is a sub-task
+func (i *Issue) IsSubtask() bool {
+	if i.Subtask {
+		return true
+	}
+	t := strings.ToLower(strings.ReplaceAll(i.Type, " ", ""))
+	return t == "sub-task" || t == "subtask"
+}
+
+// Dependency reports the direction of a dependency link: dependsOn if the
+// issue holding the link depends on the linked issue, blocks if the linked
+// issue depends on it. Other links, e.g. "relates to", report neither.
+func (l Link) Dependency() (dependsOn, blocks bool) {
+	phrase := strings.ToLower(strings.TrimSpace(l.Phrase))
+	for _, p := range dependsOnPhrases {
+		if phrase == p {
+			return true, false
+		}
+	}
+	for _, p := range blocksPhrases {
+		if phrase == p {
+			return false, true
+		}
+	}
+	return false, false
+}
+
+// ReadExport reads the issues of a JIRA export file, see ParseExport
+func ReadExport(path string) ([]Issue, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	issues, err := ParseExport(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse JIRA export %s: %w", path, err)
+	}
+	return issues, nil
+}
+
+// ParseExport parses a JIRA export: an object with an "issues" array as
+// returned by the search API, or the array alone. Issue fields are read
+// from the issue's "fields" object or from the issue itself, so flattened
+// exports work too. Descriptions in Atlassian Document Format are converted
+// to plain text.
+func ParseExport(data []byte) ([]Issue, error) {
+	var export struct {
+		Issues []map[string]json.RawMessage `json:"issues"`
+	}
+	if trimmed := strings.TrimSpace(string(data)); strings.HasPrefix(trimmed, "[") {
+		if err := json.Unmarshal(data, &export.Issues); err != nil {
+			return nil, err
+		}
+	} else if err := json.Unmarshal(data, &export); err != nil {
+		return nil, err
+	}
+
+	issues := make([]Issue, 0, len(export.Issues))
+	for i, raw := range export.Issues {
+		issue, err := parseIssue(raw)
+		if err != nil {
+			return nil, fmt.Errorf("issue %d: %w", i+1, err)
+		}
+		issues = append(issues, issue)
+	}
+	return issues, nil
+}
+
+// parseIssue reads an issue, with its "fields" taking precedence over
+// fields on the issue itself
+func parseIssue(raw map[string]json.RawMessage) (Issue, error) {
+	fields := make(map[string]json.RawMessage, len(raw))
+	for k, v := range raw {
+		fields[k] = v
+	}
+	if nested, ok := raw["fields"]; ok {
+		var inner map[string]json.RawMessage
+		if err := json.Unmarshal(nested, &inner); err == nil {
+			for k, v := range inner {
+				fields[k] = v
+			}
+		}
+	}
+
+	issue := Issue{
+		Key:         stringField(fields, "key"),
+		Summary:     strings.TrimSpace(stringField(fields, "summary")),
+		Description: descriptionField(fields["description"]),
+		Type:        nameField(fields, "issuetype", "issueType", "type"),
+		Priority:    nameField(fields, "priority"),
+		Status:      nameField(fields, "status"),
+	}
+	if issue.Key == "" {
+		return issue, fmt.Errorf("missing key")
+	}
+
+	var issueType struct {
+		Subtask bool `json:"subtask"`
+	}
+	if json.Unmarshal(fields["issuetype"], &issueType) == nil {
+		issue.Subtask = issueType.Subtask
+	}
+
+	var status struct {
+		Category struct {
+			Key string `json:"key"`
+		} `json:"statusCategory"`
+	}
+	if json.Unmarshal(fields["status"], &status) == nil {
+		issue.StatusCategory = status.Category.Key
+	}
+
+	for _, name := range storyPointFields {
+		if points, ok := numberField(fields[name]); ok {
+			issue.StoryPoints = points
+			break
+		}
+	}
+
+	for _, name := range epicLinkFields {
+		if key := keyField(fields[name]); key != "" {
+			issue.Epic = key
+			break
+		}
+	}
+
+	issue.Parent = keyField(fields["parent"])
+	var parent struct {
+		Fields struct {
+			IssueType json.RawMessage `json:"issuetype"`
+		} `json:"fields"`
+	}
+	if json.Unmarshal(fields["parent"], &parent) == nil {
+		issue.ParentType = nameValue(parent.Fields.IssueType)
+	}
+
+	issue.Links = parseLinks(fields["issuelinks"])
+
+	var subtasks []map[string]json.RawMessage
+	if json.Unmarshal(fields["subtasks"], &subtasks) == nil {
+		for _, raw := range subtasks {
+			subtask, err := parseIssue(raw)
+			if err != nil {
+				continue
+			}
+			subtask.Subtask = true
+			subtask.Parent = issue.Key
+			issue.Subtasks = append(issue.Subtasks, subtask)
+		}
+	}
+
+	return issue, nil
+}
+
+// parseLinks reads issue links. A link holds the linked issue as
+// inwardIssue, read with the link type's inward phrase, or as outwardIssue,
+// read with its outward phrase.
+func parseLinks(raw json.RawMessage) []Link {
+	var links []struct {
+		Type struct {
+			Name    string `json:"name"`
+			Inward  string `json:"inward"`
+			Outward string `json:"outward"`
+		} `json:"type"`
+		InwardIssue  json.RawMessage `json:"inwardIssue"`
+		OutwardIssue json.RawMessage `json:"outwardIssue"`
+	}
+	if json.Unmarshal(raw, &links) != nil {
+		return nil
+	}
+
+	var result []Link
+	for _, l := range links {
+		if key := keyField(l.InwardIssue); key != "" {
+			result = append(result, Link{Type: l.Type.Name, Phrase: linkPhrase(l.Type.Inward, l.Type.Name, true), Key: key})
+		}
+		if key := keyField(l.OutwardIssue); key != "" {
+			result = append(result, Link{Type: l.Type.Name, Phrase: linkPhrase(l.Type.Outward, l.Type.Name, false), Key: key})
+		}
+	}
+	return result
+}
+
+// linkPhrase returns the phrase of a link, falling back to the phrases of
+// the standard "Blocks" link type when the export leaves them out
+func linkPhrase(phrase, typeName string, inward bool) string {
+	if phrase != "" || !strings.EqualFold(typeName, "Blocks") {
+		return phrase
+	}
+	if inward {
+		return "is blocked by"
+	}
+	return "blocks"
+}
+
+// stringField returns a string field, or "" if it is missing or not a string
+func stringField(fields map[string]json.RawMessage, name string) string {
+	var s string
+	if json.Unmarshal(fields[name], &s) != nil {
+		return ""
+	}
+	return s
+}
+
+// nameField returns the first of the given fields that is a name: a string,
+// or an object with a name, e.g. {"name": "High"}
+func nameField(fields map[string]json.RawMessage, names ...string) string {
+	for _, name := range names {
+		if value := nameValue(fields[name]); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+func nameValue(raw json.RawMessage) string {
+	var s string
+	if json.Unmarshal(raw, &s) == nil {
+		return strings.TrimSpace(s)
+	}
+	var named struct {
+		Name string `json:"name"`
+	}
+	if json.Unmarshal(raw, &named) == nil {
+		return strings.TrimSpace(named.Name)
+	}
+	return ""
+}
+
+// keyField returns an issue key given as a string or as an issue object
+func keyField(raw json.RawMessage) string {
+	var s string
+	if json.Unmarshal(raw, &s) == nil {
+		return strings.TrimSpace(s)
+	}
+	var issue struct {
+		Key string `json:"key"`
+	}
+	if json.Unmarshal(raw, &issue) == nil {
+		return strings.TrimSpace(issue.Key)
+	}
+	return ""
+}
+
+// numberField returns a positive number given as a JSON number or string
+func numberField(raw json.RawMessage) (float64, bool) {
+	var n float64
+	if json.Unmarshal(raw, &n) == nil && n > 0 {
+		return n, true
+	}
+	var s string
+	if json.Unmarshal(raw, &s) == nil {
+		if n, err := strconv.ParseFloat(strings.TrimSpace(s), 64); err == nil && n > 0 {
+			return n, true
+		}
+	}
+	return 0, false
+}
+
+// descriptionField returns a description given as plain text or in
+// Atlassian Document Format
+func descriptionField(raw json.RawMessage) string {
+	var s string
+	if json.Unmarshal(raw, &s) == nil {
+		return strings.TrimSpace(s)
+	}
+	var doc adfNode
+	if json.Unmarshal(raw, &doc) != nil {
+		return ""
+	}
+	var sb strings.Builder
+	doc.writeText(&sb)
+	text := strings.TrimSpace(sb.String())
+	for strings.Contains(text, "\n\n\n") {
+		text = strings.ReplaceAll(text, "\n\n\n", "\n\n")
+	}
+	return text
+}
+
+// adfNode is a node of an Atlassian Document Format document
+type adfNode struct {
+	Type    string    `json:"type"`
+	Text    string    `json:"text"`
+	Content []adfNode `json:"content"`
+}
+
+// writeText writes the text of a node, ending blocks with blank lines and
+// list items with a newline
+func (n *adfNode) writeText(sb *strings.Builder) {
+	switch n.Type {
+	case "hardBreak":
+		sb.WriteString("\n")
+	case "listItem":
+		sb.WriteString("- ")
+	}
+	sb.WriteString(n.Text)
+	for i := range n.Content {
+		n.Content[i].writeText(sb)
+	}
+	switch n.Type {
+	case "paragraph", "heading", "codeBlock", "blockquote", "bulletList", "orderedList", "rule":
+		sb.WriteString("\n\n")
+	case "listItem":
+		sb.WriteString("\n")
+	}
+}
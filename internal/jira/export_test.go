@@ -0,0 +1,116 @@
+package jira
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const testExport = `{
+  "issues": [
+    {
+      "key": "PROJ-1",
+      "fields": {
+        "summary": "Checkout",
+        "issuetype": {"name": "Epic"},
+        "description": {"type": "doc", "content": [
+          {"type": "paragraph", "content": [{"type": "text", "text": "Let users pay."}]},
+          {"type": "bulletList", "content": [
+            {"type": "listItem", "content": [{"type": "paragraph", "content": [{"type": "text", "text": "Cards"}]}]}
+          ]}
+        ]}
+      }
+    },
+    {
+      "key": "PROJ-2",
+      "fields": {
+        "summary": "Payment form",
+        "issuetype": {"name": "Story", "subtask": false},
+        "priority": {"name": "High"},
+        "status": {"name": "In Progress", "statusCategory": {"key": "indeterminate"}},
+        "customfield_10016": 5,
+        "parent": {"key": "PROJ-1", "fields": {"issuetype": {"name": "Epic"}}},
+        "issuelinks": [
+          {"type": {"name": "Blocks", "inward": "is blocked by", "outward": "blocks"}, "inwardIssue": {"key": "PROJ-3"}},
+          {"type": {"name": "Relates", "inward": "relates to", "outward": "relates to"}, "outwardIssue": {"key": "PROJ-9"}}
+        ],
+        "subtasks": [
+          {"key": "PROJ-4", "fields": {"summary": "Validate card", "issuetype": {"name": "Sub-task", "subtask": true}}}
+        ]
+      }
+    },
+    {"key": "PROJ-3", "summary": "Card API", "issuetype": "Task", "storyPoints": "3", "epicLink": "PROJ-1"}
+  ]
+}`
+
+func TestParseExport(t *testing.T) {
+	issues, err := ParseExport([]byte(testExport))
+	if err != nil {
+		t.Fatalf("ParseExport failed: %v", err)
+	}
+	if len(issues) != 3 {
+		t.Fatalf("expected 3 issues, got %d", len(issues))
+	}
+
+	epic := issues[0]
+	if !epic.IsEpic() || epic.Summary != "Checkout" {
+		t.Errorf("expected the epic first, got %+v", epic)
+	}
+	if epic.Description != "Let users pay.\n\n- Cards" {
+		t.Errorf("expected the ADF description as text, got %q", epic.Description)
+	}
+
+	story := issues[1]
+	if story.Priority != "High" || story.Status != "In Progress" || story.StatusCategory != "indeterminate" {
+		t.Errorf("unexpected priority or status: %+v", story)
+	}
+	if story.StoryPoints != 5 {
+		t.Errorf("expected 5 story points, got %v", story.StoryPoints)
+	}
+	if story.Parent != "PROJ-1" || story.ParentType != "Epic" {
+		t.Errorf("expected epic parent PROJ-1, got %q (%q)", story.Parent, story.ParentType)
+	}
+	if len(story.Links) != 2 {
+		t.Fatalf("expected 2 links, got %+v", story.Links)
+	}
+	if dependsOn, _ := story.Links[0].Dependency(); !dependsOn || story.Links[0].Key != "PROJ-3" {
+		t.Errorf("expected a dependency on PROJ-3, got %+v", story.Links[0])
+	}
+	if dependsOn, blocks := story.Links[1].Dependency(); dependsOn || blocks {
+		t.Errorf("expected 'relates to' not to be a dependency, got %+v", story.Links[1])
+	}
+	if len(story.Subtasks) != 1 || !story.Subtasks[0].IsSubtask() || story.Subtasks[0].Parent != "PROJ-2" {
+		t.Errorf("expected a nested sub-task of PROJ-2, got %+v", story.Subtasks)
+	}
+
+	// Flattened issues read their fields from the issue itself
+	flat := issues[2]
+	if flat.Type != "Task" || flat.StoryPoints != 3 || flat.Epic != "PROJ-1" {
+		t.Errorf("unexpected flattened issue: %+v", flat)
+	}
+}
+
+func TestParseExportArray(t *testing.T) {
+	issues, err := ParseExport([]byte(`[{"key": "A-1", "summary": "One"}, {"key": "A-2", "summary": "Two"}]`))
+	if err != nil {
+		t.Fatalf("ParseExport failed: %v", err)
+	}
+	if len(issues) != 2 || issues[1].Summary != "Two" {
+		t.Errorf("unexpected issues: %+v", issues)
+	}
+
+	if _, err := ParseExport([]byte(`[{"summary": "No key"}]`)); err == nil {
+		t.Error("expected an error for an issue without a key")
+	}
+}
+
+func TestReadExport(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "export.json")
+	os.WriteFile(path, []byte("not json"), 0644)
+	if _, err := ReadExport(path); err == nil {
+		t.Error("expected an error for invalid JSON")
+	}
+	if _, err := ReadExport(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("expected an error for a missing file")
+	}
+}
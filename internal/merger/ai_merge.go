@@ -94,16 +94,15 @@ Changes:
 4. Maintain code correctness and consistency
 
 ## Output Format
-Provide your response in the following format:
+Respond with a single JSON code block:
 
-MERGED_CODE_START
-[Your merged code here]
-MERGED_CODE_END
-
-EXPLANATION:
-[Brief explanation of how you merged the changes]
-
-CONFIDENCE: [0.0-1.0]
+`+"```json"+`
+{
+  "merged_code": "<the complete merged file>",
+  "explanation": "<brief explanation of how you merged the changes>",
+  "confidence": <0.0-1.0>
+}
+`+"```"+`
 `,
 		ctx.File,
 		ctx.OriginalCode,
@@ -143,8 +142,28 @@ func (m *AIMerger) executeAI(ctx context.Context, prompt string) (string, error)
 	return result.Output, nil
 }
 
-// parseResponse extracts the merged code from AI output
+// parseResponse extracts the merged code from AI output: the first JSON
+// block with a merged_code field, or the MERGED_CODE_START/END markers of
+// the older response format
 func (m *AIMerger) parseResponse(output string) (code, explanation string, confidence float64) {
+	for _, value := range ai.NewStructuredExtractor(output).ExtractAll("json") {
+		fields, ok := value.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		merged, ok := fields["merged_code"].(string)
+		if !ok {
+			continue
+		}
+		code = merged
+		explanation, _ = fields["explanation"].(string)
+		confidence, _ = fields["confidence"].(float64)
+		if confidence == 0 && code != "" {
+			confidence = 0.7
+		}
+		return code, strings.TrimSpace(explanation), confidence
+	}
+
 	// Extract merged code
 	if startIdx := strings.Index(output, "MERGED_CODE_START"); startIdx != -1 {
 		if endIdx := strings.Index(output, "MERGED_CODE_END"); endIdx != -1 && endIdx > startIdx {
@@ -234,27 +253,17 @@ type TaskMergeInfo struct {
 	Intent string
 }
 
-// ValidateMerge checks if the merged code is valid
-func (m *AIMerger) ValidateMerge(ctx context.Context, file, mergedCode string) (bool, string, error) {
-	// Basic validation: check for obvious issues
-	
-	// Check for conflict markers
-	if strings.Contains(mergedCode, "<<<<<<<") || 
-	   strings.Contains(mergedCode, "=======") || 
-	   strings.Contains(mergedCode, ">>>>>>>") {
-		return false, "Merged code contains conflict markers", nil
+// ValidateMerge checks if the merged code is valid. Go files are parsed with
+// go/parser; other files get a bracket balance check of their fenced code
+// blocks, or of the whole file if it has none and is not prose such as
+// Markdown. The returned slice holds every validation message.
+func (m *AIMerger) ValidateMerge(ctx context.Context, file, mergedCode string) (bool, []string, error) {
+	messages := validateMergedCode(file, mergedCode)
+	if len(messages) > 0 {
+		return false, messages, nil
 	}
 
-	// Check for empty result
-	if strings.TrimSpace(mergedCode) == "" {
-		return false, "Merged code is empty", nil
-	}
-
-	// TODO: Add syntax validation based on file type
-	// For Go files, we could use go/parser
-	// For now, we just do basic checks
-
-	return true, "Validation passed", nil
+	return true, []string{"Validation passed"}, nil
 }
 
 // AnalyzeSemanticConflict uses AI to detect semantic conflicts
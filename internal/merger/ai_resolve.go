@@ -0,0 +1,225 @@
+package merger
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"hermes/internal/ai"
+)
+
+// DefaultAIConfidenceThreshold is the confidence an AI merge must exceed to
+// be written without review
+const DefaultAIConfidenceThreshold = 0.75
+
+// conflictFileSuffix is appended to a file's path for the conflict marker
+// file written when a conflict is left for manual resolution
+const conflictFileSuffix = ".conflict"
+
+// SetAIProvider sets the provider used for AI-assisted resolution
+func (r *Resolver) SetAIProvider(provider ai.Provider) {
+	r.provider = provider
+}
+
+// SetAIConfidenceThreshold sets the confidence an AI merge must exceed to be
+// written. Lower confidence falls back to manual resolution.
+func (r *Resolver) SetAIConfidenceThreshold(threshold float64) {
+	r.aiConfidence = threshold
+}
+
+// SetMergeLog sets where AI merge attempts are logged, e.g.
+// scheduler.ParallelLogger.Merge
+func (r *Resolver) SetMergeLog(logf func(format string, args ...interface{})) {
+	r.mergeLog = logf
+}
+
+// logMerge writes to the merge log, if one is set
+func (r *Resolver) logMerge(format string, args ...interface{}) {
+	if r.mergeLog != nil {
+		r.mergeLog(format, args...)
+	}
+}
+
+// taskFileVersion is a task's version of a conflicting file
+type taskFileVersion struct {
+	taskID  string
+	content string
+}
+
+// aiAssistedMerge asks the AI to merge every task branch's version of
+// conflict.File. The merge is written if it validates and its confidence
+// exceeds the threshold; otherwise the conflict falls back to manual
+// resolution with a conflict marker file next to the original. Without a
+// provider the conflict is left for manual resolution as is.
+func (r *Resolver) aiAssistedMerge(conflict Conflict) ResolutionResult {
+	result := ResolutionResult{
+		Strategy: StrategyAIAssisted,
+	}
+
+	if r.provider == nil {
+		result.Strategy = StrategyManual
+		result.Description = "Conflict requires manual resolution (no AI provider configured)"
+		return result
+	}
+	if len(conflict.Tasks) < 2 {
+		result.Error = fmt.Errorf("need at least 2 tasks to merge")
+		return result
+	}
+
+	original, versions, err := r.taskFileVersions(conflict)
+	if err != nil {
+		result.Error = err
+		return result
+	}
+
+	r.logMerge("AI merge of %s (tasks: %v) started", conflict.File, conflict.Tasks)
+
+	ctx := context.Background()
+	aiMerger := NewAIMerger(r.provider, r.workDir)
+	merged, explanation, confidence, err := r.mergeVersions(ctx, aiMerger, conflict, original, versions)
+	if err != nil {
+		r.logMerge("AI merge of %s failed: %v", conflict.File, err)
+		return r.manualFallback(conflict, versions, err.Error(), err)
+	}
+
+	valid, messages, err := aiMerger.ValidateMerge(ctx, conflict.File, merged)
+	if err != nil {
+		r.logMerge("AI merge of %s failed: %v", conflict.File, err)
+		return r.manualFallback(conflict, versions, err.Error(), err)
+	}
+	if !valid {
+		reason := fmt.Sprintf("AI merge failed validation: %s", strings.Join(messages, "; "))
+		r.logMerge("AI merge of %s rejected: %s", conflict.File, reason)
+		return r.manualFallback(conflict, versions, reason, nil)
+	}
+	if confidence <= r.aiConfidence {
+		reason := fmt.Sprintf("AI merge confidence %.2f is not above %.2f", confidence, r.aiConfidence)
+		r.logMerge("AI merge of %s rejected: %s", conflict.File, reason)
+		return r.manualFallback(conflict, versions, reason, nil)
+	}
+
+	// The AI's code block comes back without its final newline
+	if !strings.HasSuffix(merged, "\n") {
+		merged += "\n"
+	}
+	path := filepath.Join(r.workDir, conflict.File)
+	if err := os.WriteFile(path, []byte(merged), 0644); err != nil {
+		result.Error = fmt.Errorf("failed to write merged file: %w", err)
+		r.logMerge("AI merge of %s failed: %v", conflict.File, result.Error)
+		return result
+	}
+
+	r.logMerge("AI merge of %s written with confidence %.2f: %s", conflict.File, confidence, explanation)
+	result.Success = true
+	result.MergedFile = path
+	result.Description = fmt.Sprintf("AI merged changes from tasks %v to %s (confidence %.2f)", conflict.Tasks, conflict.File, confidence)
+	return result
+}
+
+// taskFileVersions returns the file at the snapshot taken before the first
+// task started ("" without one) and each task branch's version of it
+func (r *Resolver) taskFileVersions(conflict Conflict) (string, []taskFileVersion, error) {
+	if !r.isGitRepo() {
+		return "", nil, fmt.Errorf("AI-assisted resolution needs a git repository")
+	}
+
+	var original string
+	if r.snapshots != nil {
+		if baseSHA, ok := r.snapshots.GetSnapshot(conflict.Tasks[0]); ok {
+			content, err := r.showFile(baseSHA, conflict.File)
+			if err != nil {
+				return "", nil, err
+			}
+			original = content
+		}
+	}
+
+	versions := make([]taskFileVersion, len(conflict.Tasks))
+	for i, taskID := range conflict.Tasks {
+		content, err := r.showFile(taskBranchPrefix+taskID, conflict.File)
+		if err != nil {
+			return "", nil, err
+		}
+		versions[i] = taskFileVersion{taskID: taskID, content: content}
+	}
+	return original, versions, nil
+}
+
+// mergeVersions merges the versions pairwise in task order. The confidence
+// of the result is the lowest of the pairwise merges.
+func (r *Resolver) mergeVersions(ctx context.Context, aiMerger *AIMerger, conflict Conflict, original string, versions []taskFileVersion) (string, string, float64, error) {
+	merged := versions[0].content
+	mergedID := versions[0].taskID
+	confidence := math.Inf(1)
+	var explanations []string
+
+	for _, version := range versions[1:] {
+		result := aiMerger.ResolveConflict(ctx, conflict, MergeContext{
+			File:         conflict.File,
+			OriginalCode: original,
+			Task1ID:      mergedID,
+			Task1Changes: merged,
+			Task1Intent:  conflict.Description,
+			Task2ID:      version.taskID,
+			Task2Changes: version.content,
+			Task2Intent:  conflict.Description,
+		})
+		if result.Error != nil {
+			return "", "", 0, result.Error
+		}
+		if !result.Success {
+			return "", "", 0, fmt.Errorf("AI returned no merged code")
+		}
+
+		merged = result.MergedCode
+		mergedID = mergedID + "+" + version.taskID
+		confidence = math.Min(confidence, result.Confidence)
+		if result.Explanation != "" {
+			explanations = append(explanations, result.Explanation)
+		}
+	}
+
+	return merged, strings.Join(explanations, " "), confidence, nil
+}
+
+// manualFallback leaves the conflict for manual resolution and writes every
+// task's version, between conflict markers, to <file>.conflict
+func (r *Resolver) manualFallback(conflict Conflict, versions []taskFileVersion, reason string, err error) ResolutionResult {
+	result := ResolutionResult{
+		Strategy: StrategyManual,
+		Error:    err,
+	}
+
+	path := filepath.Join(r.workDir, conflict.File) + conflictFileSuffix
+	if writeErr := os.WriteFile(path, []byte(conflictMarkers(versions)), 0644); writeErr != nil {
+		result.Error = fmt.Errorf("failed to write conflict file: %w", writeErr)
+		result.Description = fmt.Sprintf("%s; conflict requires manual resolution", reason)
+		return result
+	}
+
+	r.logMerge("Conflict in %s left for manual resolution, markers written to %s", conflict.File, conflict.File+conflictFileSuffix)
+	result.Description = fmt.Sprintf("%s; conflict markers written to %s", reason, conflict.File+conflictFileSuffix)
+	return result
+}
+
+// conflictMarkers renders the versions as one git-style conflict, labelled
+// with the first and last task IDs
+func conflictMarkers(versions []taskFileVersion) string {
+	var sb strings.Builder
+	for i, version := range versions {
+		if i == 0 {
+			sb.WriteString("<<<<<<< " + version.taskID + "\n")
+		} else {
+			sb.WriteString("=======\n")
+		}
+		sb.WriteString(version.content)
+		if version.content != "" && !strings.HasSuffix(version.content, "\n") {
+			sb.WriteString("\n")
+		}
+	}
+	sb.WriteString(">>>>>>> " + versions[len(versions)-1].taskID + "\n")
+	return sb.String()
+}
@@ -3,7 +3,10 @@ package merger
 import (
 	"fmt"
 	"path/filepath"
+	"sort"
 	"strings"
+
+	"hermes/internal/git"
 )
 
 // ConflictType represents the type of conflict between parallel tasks
@@ -59,6 +62,13 @@ type ConflictDetector struct {
 	fileChanges   map[string][]TaskChange // file -> changes by tasks
 	taskChanges   map[string][]string     // taskID -> files changed
 	conflicts     []Conflict
+	marked        []Conflict // Added by MarkConflict, kept by Analyze
+
+	// Set by SetRepository for WatchForConflicts
+	repo        *git.Git
+	baseBranch  string
+	branchHeads map[string]string        // taskID -> last analyzed commit
+	watchTask   func(taskID string) bool // Set by SetWatchFilter
 }
 
 // TaskChange represents changes made by a task to a file
@@ -69,6 +79,9 @@ type TaskChange struct {
 	Removed   []string // Lines removed
 	Modified  []string // Lines modified
 	Functions []string // Functions modified
+	Hunks     []DiffHunk
+	Diff      string   // Raw unified diff
+	Source    string   // Full contents of the modified file (optional)
 }
 
 // NewConflictDetector creates a new conflict detector
@@ -92,7 +105,9 @@ func (d *ConflictDetector) AddTaskChanges(taskID string, files []string, diffs m
 
 		// Parse diff if available
 		if diff, ok := diffs[file]; ok {
-			change.Added, change.Removed, change.Modified = parseDiff(diff)
+			change.Diff = diff
+			change.Hunks, _ = parseUnifiedDiff(diff)
+			change.Added, change.Removed, change.Modified = diffLines(change.Hunks)
 			change.Functions = extractModifiedFunctions(diff)
 		}
 
@@ -100,9 +115,49 @@ func (d *ConflictDetector) AddTaskChanges(taskID string, files []string, diffs m
 	}
 }
 
+// AddCommittedChanges adds the changes a task committed between the base and
+// head commits, reading the changed files and their diffs from repo. Go files
+// are loaded as of head for AST-based analysis.
+func (d *ConflictDetector) AddCommittedChanges(repo *git.Git, taskID, base, head string) error {
+	files, err := repo.GetCommittedFiles(base, head)
+	if err != nil {
+		return err
+	}
+
+	diffs := make(map[string]string, len(files))
+	for _, file := range files {
+		diff, err := repo.GetFileDiff(base, head, file)
+		if err != nil {
+			return err
+		}
+		diffs[file] = diff
+	}
+
+	d.AddTaskChanges(taskID, files, diffs)
+
+	for _, file := range files {
+		if !isGoFile(file) {
+			continue
+		}
+
+		content, err := repo.GetFileAt(head, file)
+		if err != nil {
+			continue // Deleted at head
+		}
+
+		changes := d.fileChanges[file]
+		for i := range changes {
+			if changes[i].TaskID == taskID {
+				changes[i].Source = content
+			}
+		}
+	}
+	return nil
+}
+
 // Analyze detects conflicts between all registered task changes
 func (d *ConflictDetector) Analyze() []Conflict {
-	d.conflicts = make([]Conflict, 0)
+	d.conflicts = append(make([]Conflict, 0, len(d.marked)), d.marked...)
 
 	// Check each file for conflicts
 	for file, changes := range d.fileChanges {
@@ -117,6 +172,14 @@ func (d *ConflictDetector) Analyze() []Conflict {
 	return d.conflicts
 }
 
+// MarkConflict records a conflict found outside Analyze, such as a task
+// branch that does not rebase onto the base branch. Marked conflicts are
+// kept when Analyze runs again.
+func (d *ConflictDetector) MarkConflict(c Conflict) {
+	d.marked = append(d.marked, c)
+	d.conflicts = append(d.conflicts, c)
+}
+
 // analyzeFileConflict analyzes conflicts for a single file
 func (d *ConflictDetector) analyzeFileConflict(file string, changes []TaskChange) Conflict {
 	taskIDs := make([]string, len(changes))
@@ -128,9 +191,17 @@ func (d *ConflictDetector) analyzeFileConflict(file string, changes []TaskChange
 		File:  file,
 		Tasks: taskIDs,
 	}
+	conflict.LineStart, conflict.LineEnd = hunkSpan(changes)
+
+	// Resolve modified functions using the Go AST where possible
+	resolved := make([]TaskChange, len(changes))
+	for i, c := range changes {
+		resolved[i] = c
+		resolved[i].Functions = goFunctionsForChange(c)
+	}
 
 	// Check for function-level conflicts
-	functionConflicts := d.detectFunctionConflicts(changes)
+	functionConflicts := d.detectFunctionConflicts(resolved)
 	if len(functionConflicts) > 0 {
 		conflict.Type = ConflictSameFunction
 		conflict.Severity = SeverityHigh
@@ -140,7 +211,8 @@ func (d *ConflictDetector) analyzeFileConflict(file string, changes []TaskChange
 	}
 
 	// Check for overlapping line modifications
-	if d.hasOverlappingChanges(changes) {
+	if start, end, ok := d.hasOverlappingChanges(changes); ok {
+		conflict.LineStart, conflict.LineEnd = start, end
 		conflict.Type = ConflictSameFile
 		conflict.Severity = SeverityMedium
 		conflict.Description = "Multiple tasks modified overlapping sections of the file"
@@ -173,28 +245,19 @@ func (d *ConflictDetector) detectFunctionConflicts(changes []TaskChange) []strin
 			conflicts = append(conflicts, fn)
 		}
 	}
+	sort.Strings(conflicts)
 
 	return conflicts
 }
 
-// hasOverlappingChanges checks if changes overlap
-func (d *ConflictDetector) hasOverlappingChanges(changes []TaskChange) bool {
-	// Simplified check: if any modified lines appear in multiple changes
-	modifiedLines := make(map[string]int)
-
-	for _, change := range changes {
-		for _, line := range change.Modified {
-			modifiedLines[line]++
-		}
-	}
-
-	for _, count := range modifiedLines {
-		if count > 1 {
-			return true
-		}
+// hasOverlappingChanges checks if hunks of different tasks change the same
+// lines of the original file and returns the first overlapping line range
+func (d *ConflictDetector) hasOverlappingChanges(changes []TaskChange) (int, int, bool) {
+	a, b, ok := overlappingHunks(changes)
+	if !ok {
+		return 0, 0, false
 	}
-
-	return false
+	return max(a.ChangeStart, b.ChangeStart), min(a.ChangeEnd, b.ChangeEnd), true
 }
 
 // GetConflicts returns all detected conflicts
@@ -290,31 +353,16 @@ func (d *ConflictDetector) PrintConflictSummary() {
 
 // Helper functions
 
-// parseDiff parses a unified diff and extracts added, removed, and modified lines
-func parseDiff(diff string) (added, removed, modified []string) {
-	lines := strings.Split(diff, "\n")
-
-	for _, line := range lines {
-		if strings.HasPrefix(line, "+") && !strings.HasPrefix(line, "+++") {
-			added = append(added, strings.TrimPrefix(line, "+"))
-		} else if strings.HasPrefix(line, "-") && !strings.HasPrefix(line, "---") {
-			removed = append(removed, strings.TrimPrefix(line, "-"))
-		}
-	}
-
-	// Modified lines are those that appear in both added and removed (after trimming whitespace)
-	removedSet := make(map[string]bool)
-	for _, r := range removed {
-		removedSet[strings.TrimSpace(r)] = true
-	}
-
-	for _, a := range added {
-		trimmed := strings.TrimSpace(a)
-		if removedSet[trimmed] {
-			modified = append(modified, trimmed)
+// diffLines collects the added and removed lines of the hunks. Modified
+// lines are the added lines of hunks that also remove lines, i.e. replacements.
+func diffLines(hunks []DiffHunk) (added, removed, modified []string) {
+	for _, h := range hunks {
+		added = append(added, h.Added...)
+		removed = append(removed, h.Removed...)
+		if len(h.Removed) > 0 {
+			modified = append(modified, h.Added...)
 		}
 	}
-
 	return
 }
 
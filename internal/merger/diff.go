@@ -0,0 +1,183 @@
+package merger
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var hunkHeaderRegex = regexp.MustCompile(`^@@ -(\d+)(?:,(\d+))? \+(\d+)(?:,(\d+))? @@ ?(.*)$`)
+
+// DiffHunk is one hunk of a unified diff
+type DiffHunk struct {
+	OldStart int
+	OldLines int
+	NewStart int
+	NewLines int
+	Section  string // Text after the header, usually the enclosing function
+
+	Added   []string
+	Removed []string
+	Context []string
+
+	// ChangeStart and ChangeEnd are the original-file lines replaced by the
+	// hunk, without context. A pure insertion covers the line it is
+	// inserted before.
+	ChangeStart int
+	ChangeEnd   int
+
+	// NewChanged lists the new-file lines touched by the hunk. Removed
+	// lines are attributed to the position where they were deleted.
+	NewChanged []int
+}
+
+// Overlaps returns true if the changed lines of both hunks intersect
+func (h DiffHunk) Overlaps(other DiffHunk) bool {
+	return h.ChangeStart <= other.ChangeEnd && other.ChangeStart <= h.ChangeEnd
+}
+
+// parseUnifiedDiff parses the hunks of a single-file unified diff. File
+// headers (diff --git, index, ---, +++) are skipped; inside a hunk the line
+// counts from the header decide what is content, so removed lines starting
+// with "--" are not mistaken for headers. A diff that ends while only
+// context lines are left ends in blank context lines.
+func parseUnifiedDiff(diff string) ([]DiffHunk, error) {
+	var hunks []DiffHunk
+	lines := strings.Split(diff, "\n")
+
+	for i := 0; i < len(lines); i++ {
+		m := hunkHeaderRegex.FindStringSubmatch(lines[i])
+		if m == nil {
+			continue
+		}
+
+		h := DiffHunk{
+			OldStart: atoiDefault(m[1], 0),
+			OldLines: atoiDefault(m[2], 1),
+			NewStart: atoiDefault(m[3], 0),
+			NewLines: atoiDefault(m[4], 1),
+			Section:  strings.TrimSpace(m[5]),
+		}
+
+		oldLeft, newLeft := h.OldLines, h.NewLines
+		oldLine, newLine := h.OldStart, h.NewStart
+		if h.OldLines == 0 {
+			// "-N,0" means the insertion goes after line N
+			oldLine++
+		}
+		h.ChangeStart, h.ChangeEnd = -1, -1
+		replacing := false // added lines right after removed ones replace them
+
+		for oldLeft > 0 || newLeft > 0 {
+			i++
+			if i >= len(lines) {
+				if oldLeft != newLeft {
+					return hunks, fmt.Errorf("hunk at line %d ends early", h.OldStart)
+				}
+				// Only context is left: blank lines lost when the diff's
+				// trailing whitespace was trimmed
+				h.Context = append(h.Context, make([]string, oldLeft)...)
+				break
+			}
+			line := lines[i]
+			if strings.HasPrefix(line, "\\") {
+				// "\ No newline at end of file"
+				continue
+			}
+
+			prefix, text := byte(' '), ""
+			if line != "" {
+				prefix, text = line[0], line[1:]
+			}
+
+			switch prefix {
+			case '+':
+				h.Added = append(h.Added, text)
+				if !replacing {
+					h.markChange(oldLine, oldLine)
+				}
+				h.NewChanged = append(h.NewChanged, newLine)
+				newLine++
+				newLeft--
+			case '-':
+				h.Removed = append(h.Removed, text)
+				h.markChange(oldLine, oldLine)
+				h.NewChanged = append(h.NewChanged, newLine)
+				replacing = true
+				oldLine++
+				oldLeft--
+			case ' ':
+				h.Context = append(h.Context, text)
+				replacing = false
+				oldLine++
+				newLine++
+				oldLeft--
+				newLeft--
+			default:
+				return hunks, fmt.Errorf("unexpected line in hunk at line %d: %q", h.OldStart, line)
+			}
+		}
+
+		hunks = append(hunks, h)
+	}
+
+	return hunks, nil
+}
+
+// markChange extends the changed range of the hunk to cover [start, end]
+func (h *DiffHunk) markChange(start, end int) {
+	if h.ChangeStart < 0 || start < h.ChangeStart {
+		h.ChangeStart = start
+	}
+	if end > h.ChangeEnd {
+		h.ChangeEnd = end
+	}
+}
+
+// overlappingHunks returns the first pair of hunks from different changes
+// whose changed lines intersect
+func overlappingHunks(changes []TaskChange) (DiffHunk, DiffHunk, bool) {
+	for i := 0; i < len(changes); i++ {
+		for j := i + 1; j < len(changes); j++ {
+			for _, a := range changes[i].Hunks {
+				for _, b := range changes[j].Hunks {
+					if a.Overlaps(b) {
+						return a, b, true
+					}
+				}
+			}
+		}
+	}
+	return DiffHunk{}, DiffHunk{}, false
+}
+
+// hunkSpan returns the first and last original-file lines changed by any hunk
+func hunkSpan(changes []TaskChange) (int, int) {
+	start, end := 0, 0
+	for _, c := range changes {
+		for _, h := range c.Hunks {
+			if h.ChangeStart < 0 {
+				continue // context only
+			}
+			if start == 0 || h.ChangeStart < start {
+				start = h.ChangeStart
+			}
+			if h.ChangeEnd > end {
+				end = h.ChangeEnd
+			}
+		}
+	}
+	return start, end
+}
+
+func atoiDefault(s string, def int) int {
+	if s == "" {
+		return def
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return def
+	}
+	return n
+}
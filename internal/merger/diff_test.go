@@ -0,0 +1,36 @@
+package merger
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseUnifiedDiffTrimmedBlankContext(t *testing.T) {
+	// The last context line " " was lost when the trailing whitespace of
+	// the diff was trimmed, leaving the hunk one line short
+	diff := "@@ -5,5 +5,5 @@ type Server struct{}\n" +
+		" // Start starts the server\n" +
+		" func (s *Server) Start() error {\n" +
+		"-\treturn nil\n" +
+		"+\treturn errA\n" +
+		" }"
+
+	hunks, err := parseUnifiedDiff(diff)
+	if err != nil {
+		t.Fatalf("parseUnifiedDiff failed: %v", err)
+	}
+	if len(hunks) != 1 {
+		t.Fatalf("Expected 1 hunk, got %d", len(hunks))
+	}
+	if want := []string{"// Start starts the server", "func (s *Server) Start() error {", "}", ""}; !reflect.DeepEqual(hunks[0].Context, want) {
+		t.Errorf("Expected context %q, got %q", want, hunks[0].Context)
+	}
+	if hunks[0].ChangeStart != 7 || hunks[0].ChangeEnd != 7 {
+		t.Errorf("Expected change at line 7, got %d-%d", hunks[0].ChangeStart, hunks[0].ChangeEnd)
+	}
+
+	// Changed lines are still missing when +/- lines were cut off
+	if _, err := parseUnifiedDiff("@@ -1,2 +1,2 @@\n a\n-b"); err == nil {
+		t.Error("Expected error for a hunk missing changed lines")
+	}
+}
@@ -0,0 +1,299 @@
+package merger
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/scanner"
+	"go/token"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// goFunction describes a function declaration and the lines it spans
+type goFunction struct {
+	Name      string
+	StartLine int
+	EndLine   int
+}
+
+// isGoFile returns true if the file should be analyzed with go/parser
+func isGoFile(file string) bool {
+	return filepath.Ext(file) == ".go"
+}
+
+// parseGoFunctions parses Go source and returns all function declarations
+func parseGoFunctions(src string) ([]goFunction, error) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "", src, parser.ParseComments|parser.SkipObjectResolution)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse Go source: %w", err)
+	}
+
+	var functions []goFunction
+	for _, decl := range f.Decls {
+		fd, ok := decl.(*ast.FuncDecl)
+		if !ok {
+			continue
+		}
+
+		start := fd.Pos()
+		if fd.Doc != nil {
+			start = fd.Doc.Pos()
+		}
+
+		functions = append(functions, goFunction{
+			Name:      funcDeclName(fd),
+			StartLine: fset.Position(start).Line,
+			EndLine:   fset.Position(fd.End()).Line,
+		})
+	}
+
+	return functions, nil
+}
+
+// funcDeclName returns the fully-qualified name of a function declaration.
+// Methods include their receiver type, e.g. "(*Server).Start" or "Config.Validate".
+func funcDeclName(fd *ast.FuncDecl) string {
+	if fd.Recv == nil || len(fd.Recv.List) == 0 {
+		return fd.Name.Name
+	}
+
+	recv := fd.Recv.List[0].Type
+	pointer := false
+	if star, ok := recv.(*ast.StarExpr); ok {
+		pointer = true
+		recv = star.X
+	}
+
+	// Strip type parameters from generic receivers
+	switch t := recv.(type) {
+	case *ast.IndexExpr:
+		recv = t.X
+	case *ast.IndexListExpr:
+		recv = t.X
+	}
+
+	typeName := "?"
+	if ident, ok := recv.(*ast.Ident); ok {
+		typeName = ident.Name
+	}
+
+	if pointer {
+		return fmt.Sprintf("(*%s).%s", typeName, fd.Name.Name)
+	}
+	return fmt.Sprintf("%s.%s", typeName, fd.Name.Name)
+}
+
+// diffChangedLines returns the line numbers in the new file touched by a unified diff
+func diffChangedLines(diff string) ([]int, error) {
+	hunks, err := parseUnifiedDiff(diff)
+	if err != nil {
+		return nil, err
+	}
+
+	var changed []int
+	for _, h := range hunks {
+		changed = append(changed, h.NewChanged...)
+	}
+	return changed, nil
+}
+
+// extractGoModifiedFunctions returns the fully-qualified names of functions in src
+// whose bodies overlap with the lines changed by diff
+func extractGoModifiedFunctions(src, diff string) ([]string, error) {
+	functions, err := parseGoFunctions(src)
+	if err != nil {
+		return nil, err
+	}
+
+	changed, err := diffChangedLines(diff)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	for _, line := range changed {
+		for _, fn := range functions {
+			if line >= fn.StartLine && line <= fn.EndLine {
+				seen[fn.Name] = true
+			}
+		}
+	}
+
+	result := make([]string, 0, len(seen))
+	for name := range seen {
+		result = append(result, name)
+	}
+	sort.Strings(result)
+
+	return result, nil
+}
+
+// goFunctionsForChange returns the modified functions for a change, using the
+// AST when the full Go source is available and the string scan otherwise
+func goFunctionsForChange(change TaskChange) []string {
+	if !isGoFile(change.File) || change.Source == "" || change.Diff == "" {
+		return change.Functions
+	}
+
+	functions, err := extractGoModifiedFunctions(change.Source, change.Diff)
+	if err != nil {
+		return change.Functions
+	}
+	return functions
+}
+
+// validateMergedCode returns validation failures for merged file contents
+func validateMergedCode(file, code string) []string {
+	var messages []string
+
+	// Check for empty result
+	if strings.TrimSpace(code) == "" {
+		return []string{"Merged code is empty"}
+	}
+
+	// Check for conflict markers
+	for i, line := range strings.Split(code, "\n") {
+		if strings.HasPrefix(line, "<<<<<<<") || strings.HasPrefix(line, ">>>>>>>") || line == "=======" {
+			messages = append(messages, fmt.Sprintf("Merged code contains conflict marker at line %d", i+1))
+			break
+		}
+	}
+
+	if isGoFile(file) {
+		if err := validateGoSyntax(code); err != nil {
+			messages = append(messages, err.Error())
+		}
+	} else if fenced, ok := fencedCode(code); ok {
+		// Braces in the prose around code blocks, e.g. "{" in an
+		// explanation, are not code
+		messages = append(messages, checkBraceBalance(fenced)...)
+	} else if !isProseFile(file) {
+		messages = append(messages, checkBraceBalance(code)...)
+	}
+
+	return messages
+}
+
+// isProseFile returns true for text files without code to brace-check
+// outside their fenced code blocks
+func isProseFile(file string) bool {
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".md", ".markdown", ".txt", ".rst", ".adoc":
+		return true
+	}
+	return false
+}
+
+// fencedCode returns text with everything outside its ``` fenced code
+// blocks blanked out, keeping line numbers, and whether it has any
+func fencedCode(text string) (string, bool) {
+	var sb strings.Builder
+	inFence, found := false, false
+	for _, line := range strings.SplitAfter(text, "\n") {
+		fence := strings.HasPrefix(strings.TrimSpace(line), "```")
+		if fence {
+			inFence = !inFence
+			found = true
+		}
+		if inFence && !fence {
+			sb.WriteString(line)
+		} else if strings.HasSuffix(line, "\n") {
+			sb.WriteString("\n")
+		}
+	}
+	return sb.String(), found
+}
+
+// validateGoSyntax parses Go source and returns the first syntax error
+func validateGoSyntax(src string) error {
+	_, err := parser.ParseFile(token.NewFileSet(), "", src, parser.AllErrors)
+	if err == nil {
+		return nil
+	}
+
+	if list, ok := err.(scanner.ErrorList); ok && len(list) > 0 {
+		return fmt.Errorf("syntax error at line %d: %s", list[0].Pos.Line, list[0].Msg)
+	}
+	return fmt.Errorf("syntax error: %v", err)
+}
+
+// checkBraceBalance verifies that (), [] and {} are balanced, skipping
+// string literals and C-style comments
+func checkBraceBalance(code string) []string {
+	type opener struct {
+		char rune
+		line int
+	}
+
+	pairs := map[rune]rune{')': '(', ']': '[', '}': '{'}
+	var stack []opener
+	var messages []string
+
+	line := 1
+	runes := []rune(code)
+	for i := 0; i < len(runes); i++ {
+		c := runes[i]
+		if c == '\n' {
+			line++
+			continue
+		}
+
+		// Skip comments
+		if c == '/' && i+1 < len(runes) {
+			if runes[i+1] == '/' {
+				for i < len(runes) && runes[i] != '\n' {
+					i++
+				}
+				i--
+				continue
+			}
+			if runes[i+1] == '*' {
+				i += 2
+				for i+1 < len(runes) && !(runes[i] == '*' && runes[i+1] == '/') {
+					if runes[i] == '\n' {
+						line++
+					}
+					i++
+				}
+				i++
+				continue
+			}
+		}
+
+		// Skip string literals
+		if c == '"' || c == '\'' || c == '`' {
+			quote := c
+			for i++; i < len(runes) && runes[i] != quote; i++ {
+				if runes[i] == '\\' && quote != '`' {
+					i++
+				} else if runes[i] == '\n' {
+					line++
+					if quote != '`' {
+						break // unterminated single-line string
+					}
+				}
+			}
+			continue
+		}
+
+		switch c {
+		case '(', '[', '{':
+			stack = append(stack, opener{char: c, line: line})
+		case ')', ']', '}':
+			if len(stack) == 0 || stack[len(stack)-1].char != pairs[c] {
+				messages = append(messages, fmt.Sprintf("Unbalanced '%c' at line %d", c, line))
+				return messages
+			}
+			stack = stack[:len(stack)-1]
+		}
+	}
+
+	for _, o := range stack {
+		messages = append(messages, fmt.Sprintf("Unclosed '%c' opened at line %d", o.char, o.line))
+	}
+
+	return messages
+}
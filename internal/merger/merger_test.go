@@ -0,0 +1,777 @@
+package merger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"hermes/internal/ai"
+	"hermes/internal/ai/mock"
+	gitpkg "hermes/internal/git"
+)
+
+const testGoSource = `package demo
+
+// Server handles requests
+type Server struct{}
+
+// Start starts the server
+func (s *Server) Start() error {
+	return nil
+}
+
+func (s Server) Name() (string, error) {
+	return "", nil
+}
+
+// legacy: func fake() was removed
+func helper() int {
+	return 1
+}
+
+type Cache[K comparable] struct{}
+
+func (c *Cache[K]) Get(key K) bool {
+	return false
+}
+`
+
+func TestParseGoFunctions(t *testing.T) {
+	functions, err := parseGoFunctions(testGoSource)
+	if err != nil {
+		t.Fatalf("parseGoFunctions failed: %v", err)
+	}
+
+	var names []string
+	for _, fn := range functions {
+		names = append(names, fn.Name)
+	}
+
+	expected := []string{"(*Server).Start", "Server.Name", "helper", "(*Cache).Get"}
+	if !reflect.DeepEqual(names, expected) {
+		t.Errorf("Expected %v, got %v", expected, names)
+	}
+
+	// Start includes its doc comment
+	if functions[0].StartLine != 6 || functions[0].EndLine != 9 {
+		t.Errorf("Expected Start to span 6-9, got %d-%d", functions[0].StartLine, functions[0].EndLine)
+	}
+}
+
+func TestParseGoFunctionsInvalid(t *testing.T) {
+	if _, err := parseGoFunctions("package demo\nfunc broken( {"); err == nil {
+		t.Error("Expected error for invalid Go source")
+	}
+}
+
+func TestDiffChangedLines(t *testing.T) {
+	diff := `--- a/demo.go
++++ b/demo.go
+@@ -7,3 +7,4 @@ func (s *Server) Start() error {
+ func (s *Server) Start() error {
+-	return nil
++	log.Println("start")
++	return s.run()
+ }
+`
+	lines, err := diffChangedLines(diff)
+	if err != nil {
+		t.Fatalf("diffChangedLines failed: %v", err)
+	}
+	expected := []int{8, 8, 9}
+	if !reflect.DeepEqual(lines, expected) {
+		t.Errorf("Expected %v, got %v", expected, lines)
+	}
+}
+
+func TestDiffChangedLinesHeaderLikeContent(t *testing.T) {
+	// Removed "-- old" and added "++ new" look like file headers once prefixed
+	diff := `--- a/demo.go
++++ b/demo.go
+@@ -3,3 +3,3 @@ func main() {
+ 	a := 1
+--- old
++++ new
+ 	b := 2
+`
+	lines, err := diffChangedLines(diff)
+	if err != nil {
+		t.Fatalf("diffChangedLines failed: %v", err)
+	}
+	expected := []int{4, 4}
+	if !reflect.DeepEqual(lines, expected) {
+		t.Errorf("Expected %v, got %v", expected, lines)
+	}
+}
+
+func TestExtractGoModifiedFunctions(t *testing.T) {
+	diff := `@@ -12,1 +12,1 @@
+-	return "", nil
++	return "server", nil
+@@ -17,1 +17,1 @@
+-	return 1
++	return 2
+`
+	functions, err := extractGoModifiedFunctions(testGoSource, diff)
+	if err != nil {
+		t.Fatalf("extractGoModifiedFunctions failed: %v", err)
+	}
+
+	expected := []string{"Server.Name", "helper"}
+	if !reflect.DeepEqual(functions, expected) {
+		t.Errorf("Expected %v, got %v", expected, functions)
+	}
+}
+
+func TestExtractGoModifiedFunctionsIgnoresComments(t *testing.T) {
+	diff := `@@ -15,1 +15,1 @@
+-// old comment
++// legacy: func fake() was removed
+`
+	functions, err := extractGoModifiedFunctions(testGoSource, diff)
+	if err != nil {
+		t.Fatalf("extractGoModifiedFunctions failed: %v", err)
+	}
+
+	// Line 15 is the doc comment of helper, not a declaration of fake
+	for _, fn := range functions {
+		if fn == "fake" {
+			t.Error("Comment mentioning func should not be treated as a function")
+		}
+	}
+
+	// The string approach is fooled by the same diff
+	legacy := extractModifiedFunctions(diff)
+	if len(legacy) == 0 || legacy[0] != "fake" {
+		t.Errorf("Expected string scan to report fake, got %v", legacy)
+	}
+}
+
+func TestConflictDetectorGoFunctionConflict(t *testing.T) {
+	d := NewConflictDetector()
+
+	diffA := "@@ -8,1 +8,1 @@\n-\treturn nil\n+\treturn errA\n"
+	diffB := "@@ -8,1 +8,1 @@\n-\treturn nil\n+\treturn errB\n"
+
+	d.AddTaskChanges("T001", []string{"server.go"}, map[string]string{"server.go": diffA})
+	d.AddTaskChanges("T002", []string{"server.go"}, map[string]string{"server.go": diffB})
+	for i := range d.fileChanges["server.go"] {
+		d.fileChanges["server.go"][i].Source = testGoSource
+	}
+
+	conflicts := d.Analyze()
+	if len(conflicts) != 1 {
+		t.Fatalf("Expected 1 conflict, got %d", len(conflicts))
+	}
+	if conflicts[0].Type != ConflictSameFunction {
+		t.Errorf("Expected SAME_FUNCTION, got %s", conflicts[0].Type)
+	}
+	if conflicts[0].Severity != SeverityHigh {
+		t.Errorf("Expected high severity, got %d", conflicts[0].Severity)
+	}
+}
+
+func TestConflictDetectorGoDifferentFunctions(t *testing.T) {
+	d := NewConflictDetector()
+
+	diffA := "@@ -8,1 +8,1 @@\n-\treturn nil\n+\treturn errA\n"
+	diffB := "@@ -12,1 +12,1 @@\n-\treturn \"\", nil\n+\treturn \"b\", nil\n"
+
+	d.AddTaskChanges("T001", []string{"server.go"}, map[string]string{"server.go": diffA})
+	d.AddTaskChanges("T002", []string{"server.go"}, map[string]string{"server.go": diffB})
+	for i := range d.fileChanges["server.go"] {
+		d.fileChanges["server.go"][i].Source = testGoSource
+	}
+
+	conflicts := d.Analyze()
+	if len(conflicts) != 1 {
+		t.Fatalf("Expected 1 conflict, got %d", len(conflicts))
+	}
+	if conflicts[0].Type == ConflictSameFunction {
+		t.Error("Changes to different methods should not be a function conflict")
+	}
+	if !conflicts[0].CanAutoResolve {
+		t.Error("Non-overlapping changes should be auto-resolvable")
+	}
+}
+
+func TestConflictDetectorNonGoFallback(t *testing.T) {
+	d := NewConflictDetector()
+
+	diff := "@@ -1,1 +1,1 @@\n-function render() {\n+func render() {\n"
+	d.AddTaskChanges("T001", []string{"app.js"}, map[string]string{"app.js": diff})
+	d.AddTaskChanges("T002", []string{"app.js"}, map[string]string{"app.js": diff})
+
+	conflicts := d.Analyze()
+	if len(conflicts) != 1 || conflicts[0].Type != ConflictSameFunction {
+		t.Errorf("Expected string-based function conflict for non-Go file, got %v", conflicts)
+	}
+}
+
+func TestConflictDetectorMarkConflict(t *testing.T) {
+	d := NewConflictDetector()
+	d.MarkConflict(Conflict{File: "main.go", Tasks: []string{"T001"}, Type: ConflictSameFile, Severity: SeverityHigh})
+	if !d.HasConflicts() || len(d.GetConflictsByTask("T001")) != 1 {
+		t.Fatalf("Expected the marked conflict, got %v", d.GetConflicts())
+	}
+
+	// Analyze keeps marked conflicts
+	d.AddTaskChanges("T002", []string{"app.js"}, nil)
+	if conflicts := d.Analyze(); len(conflicts) != 1 || conflicts[0].File != "main.go" {
+		t.Errorf("Expected Analyze to keep the marked conflict, got %v", conflicts)
+	}
+}
+
+func TestValidateMergeGo(t *testing.T) {
+	m := NewAIMerger(nil, ".")
+
+	valid, messages, err := m.ValidateMerge(context.Background(), "server.go", testGoSource)
+	if err != nil || !valid {
+		t.Errorf("Expected valid Go source, got %v (%v)", messages, err)
+	}
+
+	broken := "package demo\n\nfunc main() {\n\tfmt.Println(\"hi\"\n}\n"
+	valid, messages, _ = m.ValidateMerge(context.Background(), "main.go", broken)
+	if valid {
+		t.Fatal("Expected invalid Go source")
+	}
+	if len(messages) != 1 || !strings.Contains(messages[0], "syntax error at line") {
+		t.Errorf("Expected first parse error, got %v", messages)
+	}
+}
+
+func TestValidateMergeMarkersAndEmpty(t *testing.T) {
+	m := NewAIMerger(nil, ".")
+
+	withMarkers := "a\n<<<<<<< HEAD\nb\n=======\nc\n>>>>>>> branch\n"
+	if valid, messages, _ := m.ValidateMerge(context.Background(), "notes.txt", withMarkers); valid {
+		t.Errorf("Expected conflict markers to fail validation, got %v", messages)
+	}
+
+	if valid, _, _ := m.ValidateMerge(context.Background(), "app.js", "   \n"); valid {
+		t.Error("Expected empty result to fail validation")
+	}
+}
+
+func TestCheckBraceBalance(t *testing.T) {
+	tests := []struct {
+		code  string
+		valid bool
+	}{
+		{"function a() { return [1, 2]; }", true},
+		{"const s = \"{ not a brace\"; // } neither\n", true},
+		{"/* { */ if (x) { y(); }", true},
+		{"function a() { return 1;", false},
+		{"a = [1, 2)", false},
+		{"}", false},
+	}
+
+	for _, tt := range tests {
+		messages := checkBraceBalance(tt.code)
+		if (len(messages) == 0) != tt.valid {
+			t.Errorf("checkBraceBalance(%q) = %v, expected valid=%v", tt.code, messages, tt.valid)
+		}
+	}
+}
+
+func TestValidateMergeFencedCode(t *testing.T) {
+	m := NewAIMerger(nil, ".")
+
+	// Braces in prose are ignored, in Markdown and around code blocks
+	prose := "# Notes\n\nUse a map literal, e.g. `{` followed by pairs.\n"
+	if valid, messages, _ := m.ValidateMerge(context.Background(), "README.md", prose); !valid {
+		t.Errorf("Expected prose braces to be ignored, got %v", messages)
+	}
+	withCode := "Open the object with {\n\n```js\nfunction a() { return [1]; }\n```\n"
+	if valid, messages, _ := m.ValidateMerge(context.Background(), "docs.md", withCode); !valid {
+		t.Errorf("Expected only the code block to be checked, got %v", messages)
+	}
+
+	// Code blocks are still checked, with their line in the file
+	broken := "Intro {\n\n```js\nfunction a() {\n```\n"
+	valid, messages, _ := m.ValidateMerge(context.Background(), "docs.md", broken)
+	if valid || len(messages) != 1 || !strings.Contains(messages[0], "line 4") {
+		t.Errorf("Expected the unclosed brace at line 4, got %v", messages)
+	}
+}
+
+func TestResolverAutoMergeValidation(t *testing.T) {
+	dir, err := os.MkdirTemp("", "hermes-merger-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	os.WriteFile(filepath.Join(dir, "good.go"), []byte(testGoSource), 0644)
+	os.WriteFile(filepath.Join(dir, "bad.go"), []byte("package demo\nfunc broken( {\n"), 0644)
+
+	r := NewResolver(dir)
+	conflict := Conflict{
+		File:           "good.go",
+		Tasks:          []string{"T001", "T002"},
+		Type:           ConflictSameFile,
+		Severity:       SeverityLow,
+		CanAutoResolve: true,
+	}
+
+	if result := r.Resolve(conflict); !result.Success {
+		t.Errorf("Expected valid merge to succeed, got %v", result.Error)
+	}
+
+	conflict.File = "bad.go"
+	result := r.Resolve(conflict)
+	if result.Success {
+		t.Fatal("Expected invalid merge to fail")
+	}
+	if result.Conflict == nil || result.Conflict.Severity != SeverityHigh {
+		t.Errorf("Expected escalated high severity conflict, got %+v", result.Conflict)
+	}
+}
+
+const mergeBase = "one\ntwo\nthree\nfour\nfive\n"
+
+// setupMergeRepo creates a git repo with notes.txt committed and returns the dir and commit SHA
+func setupMergeRepo(t *testing.T) (string, string) {
+	dir, err := os.MkdirTemp("", "hermes-merger-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	git := func(args ...string) string {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		output, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v failed: %v: %s", args, err, output)
+		}
+		return strings.TrimSpace(string(output))
+	}
+
+	git("init", "-q")
+	git("config", "user.email", "test@test.com")
+	git("config", "user.name", "Test User")
+	os.WriteFile(filepath.Join(dir, "notes.txt"), []byte(mergeBase), 0644)
+	git("add", "-A")
+	git("commit", "-q", "-m", "base")
+	return dir, git("rev-parse", "HEAD")
+}
+
+func TestThreeWayMerge(t *testing.T) {
+	dir, base := setupMergeRepo(t)
+	r := NewResolver(dir)
+
+	ours := "ONE\ntwo\nthree\nfour\nfive\n"
+	theirs := "one\ntwo\nthree\nfour\nFIVE\n"
+	merged, err := r.ThreeWayMerge("notes.txt", base, ours, theirs)
+	if err != nil {
+		t.Fatalf("ThreeWayMerge failed: %v", err)
+	}
+	if merged != "ONE\ntwo\nthree\nfour\nFIVE\n" {
+		t.Errorf("Unexpected merge result: %q", merged)
+	}
+
+	_, err = r.ThreeWayMerge("notes.txt", base, "one\n2\nthree\nfour\nfive\n", "one\nzwei\nthree\nfour\nfive\n")
+	var conflictErr *MergeConflictError
+	if !errors.As(err, &conflictErr) {
+		t.Fatalf("Expected MergeConflictError, got %v", err)
+	}
+	if len(conflictErr.Hunks) != 1 {
+		t.Fatalf("Expected 1 hunk, got %d", len(conflictErr.Hunks))
+	}
+	hunk := conflictErr.Hunks[0]
+	if !reflect.DeepEqual(hunk.Ours, []string{"2"}) || !reflect.DeepEqual(hunk.Base, []string{"two"}) || !reflect.DeepEqual(hunk.Theirs, []string{"zwei"}) {
+		t.Errorf("Unexpected hunk %+v", hunk)
+	}
+
+	// Files added after the base are merged against an empty base
+	if _, err := r.ThreeWayMerge("new.txt", base, "a\n", "a\n"); err != nil {
+		t.Errorf("Expected identical new files to merge, got %v", err)
+	}
+}
+
+type snapshotMap map[string]string
+
+func (m snapshotMap) GetSnapshot(taskID string) (string, bool) {
+	sha, ok := m[taskID]
+	return sha, ok
+}
+
+func TestResolverAutoMergeThreeWay(t *testing.T) {
+	dir, base := setupMergeRepo(t)
+
+	commitBranch := func(branch, content string) {
+		exec.Command("git", "-C", dir, "checkout", "-q", "-b", branch, base).Run()
+		os.WriteFile(filepath.Join(dir, "notes.txt"), []byte(content), 0644)
+		exec.Command("git", "-C", dir, "commit", "-q", "-am", branch).Run()
+	}
+	commitBranch("hermes/T001", "ONE\ntwo\nthree\nfour\nfive\n")
+	commitBranch("hermes/T002", "one\ntwo\nthree\nfour\nFIVE\n")
+
+	r := NewResolver(dir)
+	r.SetSnapshots(snapshotMap{"T001": base, "T002": base})
+
+	conflict := Conflict{
+		File:           "notes.txt",
+		Tasks:          []string{"T001", "T002"},
+		Type:           ConflictSameFile,
+		Severity:       SeverityLow,
+		CanAutoResolve: true,
+	}
+	result := r.Resolve(conflict)
+	if !result.Success {
+		t.Fatalf("Expected three-way merge to succeed, got %v", result.Error)
+	}
+
+	content, _ := os.ReadFile(filepath.Join(dir, "notes.txt"))
+	if string(content) != "ONE\ntwo\nthree\nfour\nFIVE\n" {
+		t.Errorf("Expected both changes in merged file, got %q", content)
+	}
+}
+
+func TestResolverAIAssisted(t *testing.T) {
+	dir, base := setupMergeRepo(t)
+
+	commitBranch := func(branch, content string) {
+		exec.Command("git", "-C", dir, "checkout", "-q", "-b", branch, base).Run()
+		os.WriteFile(filepath.Join(dir, "notes.txt"), []byte(content), 0644)
+		exec.Command("git", "-C", dir, "commit", "-q", "-am", branch).Run()
+	}
+	commitBranch("hermes/T001", "one\n2\nthree\nfour\nfive\n")
+	commitBranch("hermes/T002", "one\nzwei\nthree\nfour\nfive\n")
+
+	provider := mock.NewMockProvider()
+	var logged []string
+	r := NewResolver(dir)
+	r.SetPreferredStrategy(StrategyAIAssisted)
+	r.SetSnapshots(snapshotMap{"T001": base, "T002": base})
+	r.SetAIProvider(provider)
+	r.SetMergeLog(func(format string, args ...interface{}) {
+		logged = append(logged, fmt.Sprintf(format, args...))
+	})
+
+	conflict := Conflict{
+		File:     "notes.txt",
+		Tasks:    []string{"T001", "T002"},
+		Type:     ConflictSameFile,
+		Severity: SeverityHigh,
+	}
+
+	// Confident merges are written
+	provider.SetDefault(&ai.ExecuteResult{
+		Success: true,
+		Output:  "MERGED_CODE_START\none\n2 zwei\nthree\nfour\nfive\nMERGED_CODE_END\nEXPLANATION: Kept both\nCONFIDENCE: 0.9",
+	})
+	result := r.Resolve(conflict)
+	if !result.Success || result.Strategy != StrategyAIAssisted {
+		t.Fatalf("Expected AI merge to succeed, got %+v", result)
+	}
+	content, _ := os.ReadFile(filepath.Join(dir, "notes.txt"))
+	if string(content) != "one\n2 zwei\nthree\nfour\nfive\n" {
+		t.Errorf("Expected AI merge in file, got %q", content)
+	}
+	provider.AssertCalled(t, "## Original Code\none\ntwo\n")
+	if len(logged) != 2 || !strings.Contains(logged[1], "confidence 0.90") {
+		t.Errorf("Expected the attempt in the merge log, got %v", logged)
+	}
+
+	// Below the threshold the conflict is left for manual resolution
+	logged = nil
+	r.SetAIConfidenceThreshold(0.95)
+	result = r.Resolve(conflict)
+	if result.Success || result.Strategy != StrategyManual {
+		t.Fatalf("Expected fallback to manual resolution, got %+v", result)
+	}
+	markers, err := os.ReadFile(filepath.Join(dir, "notes.txt.conflict"))
+	if err != nil {
+		t.Fatalf("Expected a conflict file: %v", err)
+	}
+	expected := "<<<<<<< T001\none\n2\nthree\nfour\nfive\n=======\none\nzwei\nthree\nfour\nfive\n>>>>>>> T002\n"
+	if string(markers) != expected {
+		t.Errorf("Unexpected conflict file %q", markers)
+	}
+	if len(logged) != 3 || !strings.Contains(logged[1], "not above 0.95") {
+		t.Errorf("Expected the rejection in the merge log, got %v", logged)
+	}
+
+	// Merges that fail validation are not written either
+	r.SetAIConfidenceThreshold(DefaultAIConfidenceThreshold)
+	provider.SetDefault(&ai.ExecuteResult{
+		Success: true,
+		Output:  "MERGED_CODE_START\n<<<<<<< ours\nMERGED_CODE_END\nCONFIDENCE: 0.9",
+	})
+	if result := r.Resolve(conflict); result.Success || result.Strategy != StrategyManual {
+		t.Errorf("Expected invalid AI merge to fall back to manual, got %+v", result)
+	}
+
+	// Without a provider nothing is written
+	os.Remove(filepath.Join(dir, "notes.txt.conflict"))
+	r.SetAIProvider(nil)
+	if result := r.Resolve(conflict); result.Success || result.Strategy != StrategyManual {
+		t.Errorf("Expected manual resolution without a provider, got %+v", result)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "notes.txt.conflict")); !os.IsNotExist(err) {
+		t.Error("Expected no conflict file without a provider")
+	}
+}
+
+func TestAIMergerParseResponse(t *testing.T) {
+	m := NewAIMerger(nil, "")
+
+	code, explanation, confidence := m.parseResponse("Merged.\n\n```json\n" +
+		`{"merged_code": "one\nzwei\n", "explanation": " Kept both edits. ", "confidence": 0.9}` +
+		"\n```\n")
+	if code != "one\nzwei\n" || explanation != "Kept both edits." || confidence != 0.9 {
+		t.Errorf("unexpected JSON parse: %q, %q, %v", code, explanation, confidence)
+	}
+
+	// The older marker format is still accepted
+	code, _, confidence = m.parseResponse("MERGED_CODE_START\n```\none\n```\nMERGED_CODE_END\nCONFIDENCE: 0.8\n")
+	if !strings.Contains(code, "one") || confidence != 0.8 {
+		t.Errorf("unexpected marker parse: %q, %v", code, confidence)
+	}
+}
+
+func TestAddCommittedChanges(t *testing.T) {
+	dir, base := setupMergeRepo(t)
+
+	commitBranch := func(branch, content string) string {
+		exec.Command("git", "-C", dir, "checkout", "-q", "-b", branch, base).Run()
+		os.WriteFile(filepath.Join(dir, "notes.txt"), []byte(content), 0644)
+		exec.Command("git", "-C", dir, "commit", "-q", "-am", branch).Run()
+		head, _ := exec.Command("git", "-C", dir, "rev-parse", "HEAD").Output()
+		return strings.TrimSpace(string(head))
+	}
+	head1 := commitBranch("hermes/T001", "ONE\ntwo\nthree\nfour\nfive\n")
+	head2 := commitBranch("hermes/T002", "ONE!\ntwo\nthree\nfour\nfive\n")
+
+	repo := gitpkg.New(dir, "")
+	d := NewConflictDetector()
+	if err := d.AddCommittedChanges(repo, "T001", base, head1); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.AddCommittedChanges(repo, "T002", base, head2); err != nil {
+		t.Fatal(err)
+	}
+
+	changes := d.fileChanges["notes.txt"]
+	if len(changes) != 2 || len(changes[0].Hunks) != 1 {
+		t.Fatalf("Expected two parsed changes to notes.txt, got %+v", changes)
+	}
+	if conflicts := d.Analyze(); len(conflicts) != 1 || conflicts[0].File != "notes.txt" {
+		t.Errorf("Expected a conflict in notes.txt, got %+v", conflicts)
+	}
+
+	if err := d.AddCommittedChanges(repo, "T003", "unknown", head1); err == nil {
+		t.Error("Expected error for unknown commit")
+	}
+
+	// Go files are loaded as of head for AST-based analysis
+	exec.Command("git", "-C", dir, "checkout", "-q", "-b", "hermes/T004", base).Run()
+	os.WriteFile(filepath.Join(dir, "server.go"), []byte(testGoSource), 0644)
+	exec.Command("git", "-C", dir, "add", "server.go").Run()
+	exec.Command("git", "-C", dir, "commit", "-q", "-m", "server").Run()
+	head4, _ := exec.Command("git", "-C", dir, "rev-parse", "HEAD").Output()
+	if err := d.AddCommittedChanges(repo, "T004", base, strings.TrimSpace(string(head4))); err != nil {
+		t.Fatal(err)
+	}
+	goChanges := d.fileChanges["server.go"]
+	if len(goChanges) != 1 || goChanges[0].Source != testGoSource {
+		t.Fatalf("Expected server.go source to be loaded from head, got %+v", goChanges)
+	}
+	if functions := goFunctionsForChange(goChanges[0]); !reflect.DeepEqual(functions, []string{"(*Cache).Get", "(*Server).Start", "Server.Name", "helper"}) {
+		t.Errorf("Expected all functions of the new file, got %v", functions)
+	}
+}
+
+// multiHunkDiffA changes config.yaml in two places; the second hunk removes
+// a line starting with "--", which must not be read as a file header
+const multiHunkDiffA = `diff --git a/config.yaml b/config.yaml
+index 3b18e51..a9c4f2d 100644
+--- a/config.yaml
++++ b/config.yaml
+@@ -2,7 +2,7 @@ server:
+   host: localhost
+   port: 8080
+   timeout: 30
+-  workers: 4
++  workers: 8
+   tls: false
+   log: info
+   cache: true
+@@ -40,6 +40,7 @@ database:
+   name: app
+   user: app
+   pool: 10
+---max-conns 5
++--max-conns 50
++--idle-conns 5
+   ssl: disable
+   retries: 3
+\ No newline at end of file
+`
+
+const multiHunkDiffB = `diff --git a/config.yaml b/config.yaml
+index 3b18e51..77d0e1a 100644
+--- a/config.yaml
++++ b/config.yaml
+@@ -20,6 +20,8 @@ features:
+   search: true
+   export: false
+   import: false
++  audit: true
++  metrics: true
+   beta: false
+   legacy: false
+   debug: false
+`
+
+func TestParseUnifiedDiff(t *testing.T) {
+	hunks, err := parseUnifiedDiff(multiHunkDiffA)
+	if err != nil {
+		t.Fatalf("parseUnifiedDiff failed: %v", err)
+	}
+	if len(hunks) != 2 {
+		t.Fatalf("Expected 2 hunks, got %d", len(hunks))
+	}
+
+	first := hunks[0]
+	if first.OldStart != 2 || first.OldLines != 7 || first.NewStart != 2 || first.NewLines != 7 {
+		t.Errorf("Unexpected first hunk header: %+v", first)
+	}
+	if first.Section != "server:" {
+		t.Errorf("Expected section 'server:', got %q", first.Section)
+	}
+	if first.ChangeStart != 5 || first.ChangeEnd != 5 {
+		t.Errorf("Expected first hunk to change line 5, got %d-%d", first.ChangeStart, first.ChangeEnd)
+	}
+	if len(first.Context) != 6 {
+		t.Errorf("Expected 6 context lines, got %d", len(first.Context))
+	}
+
+	second := hunks[1]
+	if !reflect.DeepEqual(second.Removed, []string{"--max-conns 5"}) {
+		t.Errorf("Expected removed '--max-conns 5', got %q", second.Removed)
+	}
+	if !reflect.DeepEqual(second.Added, []string{"--max-conns 50", "--idle-conns 5"}) {
+		t.Errorf("Unexpected added lines %q", second.Added)
+	}
+	if second.ChangeStart != 43 || second.ChangeEnd != 43 {
+		t.Errorf("Expected second hunk to replace line 43, got %d-%d", second.ChangeStart, second.ChangeEnd)
+	}
+
+	added, removed, modified := diffLines(hunks)
+	if len(added) != 3 || len(removed) != 2 || len(modified) != 3 {
+		t.Errorf("Expected 3 added, 2 removed, 3 modified, got %d, %d, %d", len(added), len(removed), len(modified))
+	}
+	for _, line := range append(added, removed...) {
+		if strings.HasPrefix(line, "++") || strings.HasPrefix(line, "-- a/") {
+			t.Errorf("File header parsed as content: %q", line)
+		}
+	}
+}
+
+func TestParseUnifiedDiffInsertionAndTruncated(t *testing.T) {
+	hunks, err := parseUnifiedDiff("@@ -10,0 +11,2 @@\n+one\n+two\n")
+	if err != nil {
+		t.Fatalf("parseUnifiedDiff failed: %v", err)
+	}
+	if len(hunks) != 1 || hunks[0].ChangeStart != 11 || hunks[0].ChangeEnd != 11 {
+		t.Errorf("Expected insertion before line 11, got %+v", hunks)
+	}
+
+	if _, err := parseUnifiedDiff("@@ -1,3 +1,3 @@\n a\n-b\n"); err == nil {
+		t.Error("Expected error for truncated hunk")
+	}
+}
+
+func TestConflictDetectorHunkRanges(t *testing.T) {
+	// Different sections of the file: auto-resolvable, spanning all hunks
+	d := NewConflictDetector()
+	d.AddTaskChanges("T001", []string{"config.yaml"}, map[string]string{"config.yaml": multiHunkDiffA})
+	d.AddTaskChanges("T002", []string{"config.yaml"}, map[string]string{"config.yaml": multiHunkDiffB})
+
+	conflicts := d.Analyze()
+	if len(conflicts) != 1 {
+		t.Fatalf("Expected 1 conflict, got %d", len(conflicts))
+	}
+	if !conflicts[0].CanAutoResolve || conflicts[0].Severity != SeverityLow {
+		t.Errorf("Expected auto-resolvable conflict, got %+v", conflicts[0])
+	}
+	if conflicts[0].LineStart != 5 || conflicts[0].LineEnd != 43 {
+		t.Errorf("Expected span 5-43, got %d-%d", conflicts[0].LineStart, conflicts[0].LineEnd)
+	}
+
+	// Same line changed differently: overlapping, with its position
+	overlap := "@@ -3,5 +3,5 @@ server:\n   port: 8080\n   timeout: 30\n-  workers: 4\n+  workers: 2\n   tls: false\n   log: info\n"
+	d = NewConflictDetector()
+	d.AddTaskChanges("T001", []string{"config.yaml"}, map[string]string{"config.yaml": multiHunkDiffA})
+	d.AddTaskChanges("T003", []string{"config.yaml"}, map[string]string{"config.yaml": overlap})
+
+	conflicts = d.Analyze()
+	if len(conflicts) != 1 || conflicts[0].CanAutoResolve {
+		t.Fatalf("Expected overlapping conflict, got %+v", conflicts)
+	}
+	if conflicts[0].Severity != SeverityMedium {
+		t.Errorf("Expected medium severity, got %d", conflicts[0].Severity)
+	}
+	if conflicts[0].LineStart != 5 || conflicts[0].LineEnd != 5 {
+		t.Errorf("Expected overlap at line 5, got %d-%d", conflicts[0].LineStart, conflicts[0].LineEnd)
+	}
+}
+
+func TestWatchForConflicts(t *testing.T) {
+	dir, base := setupMergeRepo(t)
+
+	commitBranch := func(branch, content string) {
+		exec.Command("git", "-C", dir, "checkout", "-q", "-B", branch, base).Run()
+		os.WriteFile(filepath.Join(dir, "notes.txt"), []byte(content), 0644)
+		exec.Command("git", "-C", dir, "commit", "-q", "-am", branch).Run()
+	}
+	commitBranch("hermes/T000", "ONE?\ntwo\nthree\nfour\nfive\n") // Left over from an earlier run
+	commitBranch("hermes/T001", "ONE\ntwo\nthree\nfour\nfive\n")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	d := NewConflictDetector()
+	d.SetRepository(gitpkg.New(dir, ""), base)
+	d.SetWatchFilter(func(taskID string) bool { return taskID != "T000" })
+	detected := d.WatchForConflicts(ctx, 20*time.Millisecond)
+
+	// A single task branch has nothing to conflict with
+	select {
+	case conflicts := <-detected:
+		t.Fatalf("Expected no conflicts yet, got %+v", conflicts)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	commitBranch("hermes/T002", "ONE!\ntwo\nthree\nfour\nfive\n")
+	select {
+	case conflicts := <-detected:
+		if len(conflicts) != 1 || conflicts[0].File != "notes.txt" || len(conflicts[0].Tasks) != 2 {
+			t.Errorf("Expected a conflict between T001 and T002 in notes.txt, got %+v", conflicts)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected the new conflict to be sent")
+	}
+
+	// New commits with the same conflict are not reported again
+	commitBranch("hermes/T002", "ONE?\ntwo\nthree\nfour\nfive\n")
+	select {
+	case conflicts := <-detected:
+		t.Errorf("Expected known conflict not to be sent again, got %+v", conflicts)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	cancel()
+	for range detected {
+	}
+}
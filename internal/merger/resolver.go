@@ -1,9 +1,15 @@
 package merger
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
+
+	"hermes/internal/ai"
 )
 
 // ResolutionStrategy represents how to resolve a conflict
@@ -42,12 +48,17 @@ type ResolutionResult struct {
 	MergedFile  string // Path to merged file
 	Description string
 	Error       error
+	Conflict    *Conflict // Escalated conflict when merge validation fails
 }
 
 // Resolver handles conflict resolution between parallel task changes
 type Resolver struct {
-	workDir     string
+	workDir           string
 	preferredStrategy ResolutionStrategy
+	snapshots         SnapshotProvider                         // Base commits for three-way merges (optional)
+	provider          ai.Provider                              // AI for AI-assisted resolution (optional)
+	aiConfidence      float64                                  // Confidence an AI merge must exceed
+	mergeLog          func(format string, args ...interface{}) // Merge log (optional)
 }
 
 // NewResolver creates a new conflict resolver
@@ -55,6 +66,7 @@ func NewResolver(workDir string) *Resolver {
 	return &Resolver{
 		workDir:           workDir,
 		preferredStrategy: StrategyAutoMerge,
+		aiConfidence:      DefaultAIConfidenceThreshold,
 	}
 }
 
@@ -81,10 +93,7 @@ func (r *Resolver) Resolve(conflict Conflict) ResolutionResult {
 	case StrategyTakeLast:
 		return r.takeLast(conflict)
 	case StrategyAIAssisted:
-		// AI-assisted resolution will be implemented in Phase 3
-		result.Description = "AI-assisted resolution not yet implemented"
-		result.Success = false
-		return result
+		return r.aiAssistedMerge(conflict)
 	default:
 		result.Strategy = StrategyManual
 		result.Description = "Conflict requires manual resolution"
@@ -137,9 +146,56 @@ func (r *Resolver) autoMerge(conflict Conflict) ResolutionResult {
 		return result
 	}
 
-	// Try git merge-file for 3-way merge
-	// This requires the base, ours, and theirs versions of the file
-	// For now, we'll use a simpler approach
+	path := filepath.Join(r.workDir, conflict.File)
+
+	// Three-way merge the task branches against the pre-task snapshot
+	if conflict.CanAutoResolve && r.snapshots != nil && r.isGitRepo() {
+		merged, err := r.threeWayMergeTasks(conflict)
+		if err != nil {
+			var mergeErr *MergeConflictError
+			if errors.As(err, &mergeErr) {
+				escalated := conflict
+				escalated.Severity = SeverityHigh
+				escalated.CanAutoResolve = false
+				escalated.Description = mergeErr.Error()
+				if len(mergeErr.Hunks) > 0 {
+					escalated.LineStart = mergeErr.Hunks[0].StartLine
+				}
+				result.Conflict = &escalated
+				result.Description = escalated.Description
+			}
+			result.Error = err
+			return result
+		}
+
+		if err := os.WriteFile(path, []byte(merged), 0644); err != nil {
+			result.Error = fmt.Errorf("failed to write merged file: %w", err)
+			return result
+		}
+		result.MergedFile = path
+	}
+
+	// Validate the merged file before marking the conflict as resolved
+	if content, err := os.ReadFile(path); err == nil {
+		validator := NewAIMerger(nil, r.workDir)
+		valid, messages, err := validator.ValidateMerge(context.Background(), conflict.File, string(content))
+		if err != nil {
+			result.Error = err
+			return result
+		}
+		if !valid {
+			escalated := conflict
+			escalated.Type = ConflictSemantic
+			escalated.Severity = SeverityHigh
+			escalated.CanAutoResolve = false
+			escalated.Description = fmt.Sprintf("Merged result failed validation: %s", strings.Join(messages, "; "))
+
+			result.Conflict = &escalated
+			result.Description = escalated.Description
+			result.Error = fmt.Errorf("merge validation failed for %s", conflict.File)
+			return result
+		}
+	}
 
 	result.Success = true
 	result.Description = fmt.Sprintf("Auto-merged changes from tasks %v to %s", conflict.Tasks, conflict.File)
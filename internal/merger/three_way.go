@@ -0,0 +1,180 @@
+package merger
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// taskBranchPrefix is the prefix of branches created for parallel tasks
+const taskBranchPrefix = "hermes/"
+
+// SnapshotProvider returns the commit recorded before a task started.
+// scheduler.Rollback implements it via GetSnapshot.
+type SnapshotProvider interface {
+	GetSnapshot(taskID string) (string, bool)
+}
+
+// ConflictHunk is one conflicting region reported by git merge-file
+type ConflictHunk struct {
+	StartLine int // Line of the <<<<<<< marker in the merged output
+	Ours      []string
+	Base      []string
+	Theirs    []string
+}
+
+// MergeConflictError is returned when a three-way merge leaves conflicts
+type MergeConflictError struct {
+	File   string
+	Merged string // Merged content including conflict markers
+	Hunks  []ConflictHunk
+}
+
+// Error implements the error interface
+func (e *MergeConflictError) Error() string {
+	lines := make([]string, len(e.Hunks))
+	for i, h := range e.Hunks {
+		lines[i] = fmt.Sprintf("%d", h.StartLine)
+	}
+	return fmt.Sprintf("%d conflicting hunk(s) in %s at line(s) %s", len(e.Hunks), e.File, strings.Join(lines, ", "))
+}
+
+// SetSnapshots sets the source of base commits used for three-way merges
+func (r *Resolver) SetSnapshots(snapshots SnapshotProvider) {
+	r.snapshots = snapshots
+}
+
+// ThreeWayMerge merges ours and theirs against the version of file at baseSHA.
+// A file that does not exist at baseSHA is merged against an empty base.
+// Returns *MergeConflictError when the changes overlap.
+func (r *Resolver) ThreeWayMerge(file, baseSHA, ours, theirs string) (string, error) {
+	base, err := r.showFile(baseSHA, file)
+	if err != nil {
+		return "", err
+	}
+
+	dir, err := os.MkdirTemp("", "hermes-merge-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temp dir: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	paths := make([]string, 3)
+	for i, content := range []string{ours, base, theirs} {
+		f, err := os.CreateTemp(dir, "merge-*")
+		if err != nil {
+			return "", fmt.Errorf("failed to create temp file: %w", err)
+		}
+		_, err = f.WriteString(content)
+		f.Close()
+		if err != nil {
+			return "", fmt.Errorf("failed to write temp file: %w", err)
+		}
+		paths[i] = f.Name()
+	}
+
+	cmd := exec.Command("git", "merge-file", "--stdout", "--diff3",
+		"-L", "ours", "-L", "base", "-L", "theirs",
+		paths[0], paths[1], paths[2])
+	cmd.Dir = r.workDir
+	output, err := cmd.Output()
+	if err != nil {
+		// merge-file exits with the number of conflicts, or a negative value on error
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) || exitErr.ExitCode() <= 0 || exitErr.ExitCode() > 127 {
+			return "", fmt.Errorf("git merge-file failed: %w", err)
+		}
+		return "", &MergeConflictError{
+			File:   file,
+			Merged: string(output),
+			Hunks:  parseConflictHunks(string(output)),
+		}
+	}
+
+	return string(output), nil
+}
+
+// showFile returns the contents of file at commit, or "" if the file did not exist there
+func (r *Resolver) showFile(commit, file string) (string, error) {
+	if err := r.runGit("rev-parse", "--verify", "--quiet", commit+"^{commit}"); err != nil {
+		return "", fmt.Errorf("unknown commit %s", commit)
+	}
+	if err := r.runGit("cat-file", "-e", commit+":"+file); err != nil {
+		return "", nil
+	}
+
+	cmd := exec.Command("git", "show", commit+":"+file)
+	cmd.Dir = r.workDir
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s at %s: %w", file, commit, err)
+	}
+	return string(output), nil
+}
+
+// isGitRepo reports whether the resolver's work directory is inside a git repository
+func (r *Resolver) isGitRepo() bool {
+	return r.runGit("rev-parse", "--git-dir") == nil
+}
+
+// threeWayMergeTasks merges every task branch's version of conflict.File on
+// top of the snapshot taken before the first task started
+func (r *Resolver) threeWayMergeTasks(conflict Conflict) (string, error) {
+	baseSHA, ok := r.snapshots.GetSnapshot(conflict.Tasks[0])
+	if !ok {
+		return "", fmt.Errorf("no snapshot recorded for task %s", conflict.Tasks[0])
+	}
+
+	merged, err := r.showFile(taskBranchPrefix+conflict.Tasks[0], conflict.File)
+	if err != nil {
+		return "", err
+	}
+
+	for _, taskID := range conflict.Tasks[1:] {
+		theirs, err := r.showFile(taskBranchPrefix+taskID, conflict.File)
+		if err != nil {
+			return "", err
+		}
+		merged, err = r.ThreeWayMerge(conflict.File, baseSHA, merged, theirs)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return merged, nil
+}
+
+// parseConflictHunks extracts the conflicting regions from diff3-style merge output
+func parseConflictHunks(merged string) []ConflictHunk {
+	var hunks []ConflictHunk
+	var current *ConflictHunk
+	section := ""
+
+	for i, line := range strings.Split(merged, "\n") {
+		switch {
+		case strings.HasPrefix(line, "<<<<<<<"):
+			current = &ConflictHunk{StartLine: i + 1}
+			section = "ours"
+		case current != nil && strings.HasPrefix(line, "|||||||"):
+			section = "base"
+		case current != nil && strings.HasPrefix(line, "======="):
+			section = "theirs"
+		case current != nil && strings.HasPrefix(line, ">>>>>>>"):
+			hunks = append(hunks, *current)
+			current = nil
+		case current != nil:
+			switch section {
+			case "ours":
+				current.Ours = append(current.Ours, line)
+			case "base":
+				current.Base = append(current.Base, line)
+			case "theirs":
+				current.Theirs = append(current.Theirs, line)
+			}
+		}
+	}
+
+	return hunks
+}
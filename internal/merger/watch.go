@@ -0,0 +1,151 @@
+package merger
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"hermes/internal/git"
+)
+
+// SetRepository sets the repository whose task branches WatchForConflicts
+// compares against baseBranch
+func (d *ConflictDetector) SetRepository(repo *git.Git, baseBranch string) {
+	d.repo = repo
+	d.baseBranch = baseBranch
+}
+
+// SetWatchFilter limits WatchForConflicts to the task branches whose task
+// the filter accepts, e.g. the tasks of the running batch. Branches left
+// over from earlier runs are ignored and the changes of tasks the filter
+// stops accepting are forgotten.
+func (d *ConflictDetector) SetWatchFilter(filter func(taskID string) bool) {
+	d.watchTask = filter
+}
+
+// WatchForConflicts polls the task branches (hermes/<task>) every interval
+// and re-runs Analyze when one of them has new commits. Conflicts not seen
+// before are sent on the returned channel, which is closed when ctx is done.
+//
+// The detector must not be used by other goroutines while it is watched.
+func (d *ConflictDetector) WatchForConflicts(ctx context.Context, interval time.Duration) <-chan []Conflict {
+	ch := make(chan []Conflict)
+
+	go func() {
+		defer close(ch)
+		if d.repo == nil || interval <= 0 {
+			return
+		}
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		seen := make(map[string]bool)
+		for {
+			if d.pollTaskBranches() {
+				var detected []Conflict
+				for _, c := range d.Analyze() {
+					if key := conflictKey(c); !seen[key] {
+						seen[key] = true
+						detected = append(detected, c)
+					}
+				}
+				if len(detected) > 0 {
+					select {
+					case ch <- detected:
+					case <-ctx.Done():
+						return
+					}
+				}
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	return ch
+}
+
+// pollTaskBranches reloads the changes of task branches with new commits
+// and reports whether any changed
+func (d *ConflictDetector) pollTaskBranches() bool {
+	if d.branchHeads == nil {
+		d.branchHeads = make(map[string]string)
+	}
+
+	branches, err := d.repo.ListBranches()
+	if err != nil {
+		return false
+	}
+
+	changed := false
+	watched := make(map[string]bool)
+	for _, branch := range branches {
+		taskID := strings.TrimPrefix(branch, taskBranchPrefix)
+		if taskID == branch || taskID == "" {
+			continue
+		}
+		if d.watchTask != nil && !d.watchTask(taskID) {
+			continue
+		}
+		watched[taskID] = true
+
+		head, err := d.repo.GetBranchCommit(branch)
+		if err != nil || head == d.branchHeads[taskID] {
+			continue
+		}
+		base, err := d.repo.MergeBase(d.baseBranch, head)
+		if err != nil {
+			continue
+		}
+
+		d.removeTaskChanges(taskID)
+		if base != head {
+			if err := d.AddCommittedChanges(d.repo, taskID, base, head); err != nil {
+				continue
+			}
+		}
+		d.branchHeads[taskID] = head
+		changed = true
+	}
+
+	// Forget tasks whose branch is gone or no longer watched
+	for taskID := range d.branchHeads {
+		if !watched[taskID] {
+			d.removeTaskChanges(taskID)
+			delete(d.branchHeads, taskID)
+		}
+	}
+	return changed
+}
+
+// removeTaskChanges forgets the changes registered for a task
+func (d *ConflictDetector) removeTaskChanges(taskID string) {
+	for _, file := range d.taskChanges[taskID] {
+		var kept []TaskChange
+		for _, change := range d.fileChanges[file] {
+			if change.TaskID != taskID {
+				kept = append(kept, change)
+			}
+		}
+		if len(kept) == 0 {
+			delete(d.fileChanges, file)
+		} else {
+			d.fileChanges[file] = kept
+		}
+	}
+	delete(d.taskChanges, taskID)
+}
+
+// conflictKey identifies a conflict by file, tasks and type
+func conflictKey(c Conflict) string {
+	tasks := append([]string(nil), c.Tasks...)
+	sort.Strings(tasks)
+	return fmt.Sprintf("%s|%s|%s", c.File, strings.Join(tasks, ","), c.Type)
+}
@@ -0,0 +1,181 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"hermes/internal/circuit"
+	"hermes/internal/scheduler"
+)
+
+// contentType is the Prometheus text exposition format
+const contentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// Collector tracks the metrics of a run and serves them in the Prometheus
+// text format
+type Collector struct {
+	mu             sync.Mutex
+	loops          int64
+	tasksCompleted map[string]int64 // by feature ID
+	tokens         map[string]int64 // by provider name
+	breaker        *circuit.Breaker
+	stats          func() scheduler.ResourceStats
+}
+
+// New creates a collector reading the circuit state from breaker (may be nil)
+func New(breaker *circuit.Breaker) *Collector {
+	return &Collector{
+		tasksCompleted: make(map[string]int64),
+		tokens:         make(map[string]int64),
+		breaker:        breaker,
+	}
+}
+
+// SetResourceStats sets the source of API call and cost metrics
+func (c *Collector) SetResourceStats(stats func() scheduler.ResourceStats) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.stats = stats
+}
+
+// IncLoops counts an execution loop
+func (c *Collector) IncLoops() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.loops++
+}
+
+// IncTasksCompleted counts a completed task of a feature
+func (c *Collector) IncTasksCompleted(featureID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.tasksCompleted[featureID]++
+}
+
+// AddTokens counts input and output tokens used by a provider
+func (c *Collector) AddTokens(provider string, tokens int) {
+	if tokens <= 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.tokens[provider] += int64(tokens)
+}
+
+// AddTaskResult counts a task executed in parallel mode as a loop and adds
+// its tokens. Tasks that never reached the provider are ignored.
+func (c *Collector) AddTaskResult(provider string, r *scheduler.TaskResult) {
+	if r == nil || r.Metrics.APICallCount == 0 {
+		return
+	}
+	c.IncLoops()
+	c.AddTokens(provider, r.Metrics.Tokens())
+}
+
+// ServeHTTP writes all metrics
+func (c *Collector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", contentType)
+	c.Write(w)
+}
+
+// Write writes all metrics in the Prometheus text format
+func (c *Collector) Write(w io.Writer) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	writeHeader(w, "hermes_loops_total", "counter", "Execution loops run")
+	fmt.Fprintf(w, "hermes_loops_total %d\n", c.loops)
+
+	writeHeader(w, "hermes_tasks_completed_total", "counter", "Tasks completed, by feature")
+	for _, feature := range sortedKeys(c.tasksCompleted) {
+		fmt.Fprintf(w, "hermes_tasks_completed_total{feature=%q} %d\n", feature, c.tasksCompleted[feature])
+	}
+
+	writeHeader(w, "hermes_ai_tokens_total", "counter", "AI input and output tokens, by provider")
+	for _, provider := range sortedKeys(c.tokens) {
+		fmt.Fprintf(w, "hermes_ai_tokens_total{provider=%q} %d\n", provider, c.tokens[provider])
+	}
+
+	if c.breaker != nil {
+		if state, err := c.breaker.GetState(); err == nil {
+			writeHeader(w, "hermes_circuit_breaker_state", "gauge", "Circuit breaker state (0=closed, 1=half-open, 2=open)")
+			fmt.Fprintf(w, "hermes_circuit_breaker_state %d\n", CircuitStateValue(state.State))
+		}
+	}
+
+	if c.stats != nil {
+		stats := c.stats()
+		writeHeader(w, "hermes_api_calls_total", "counter", "AI API calls")
+		fmt.Fprintf(w, "hermes_api_calls_total %d\n", stats.TotalAPICalls)
+		writeHeader(w, "hermes_ai_cost_dollars_total", "counter", "AI API cost in USD")
+		fmt.Fprintf(w, "hermes_ai_cost_dollars_total %g\n", stats.TotalCost)
+	}
+}
+
+// CircuitStateValue maps a circuit state to its gauge value
+func CircuitStateValue(state circuit.State) int {
+	switch state {
+	case circuit.StateHalfOpen:
+		return 1
+	case circuit.StateOpen:
+		return 2
+	default:
+		return 0
+	}
+}
+
+func writeHeader(w io.Writer, name, kind, help string) {
+	fmt.Fprintf(w, "# HELP %s %s\n", name, help)
+	fmt.Fprintf(w, "# TYPE %s %s\n", name, kind)
+}
+
+func sortedKeys(m map[string]int64) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Server serves a collector on /metrics
+type Server struct {
+	server   *http.Server
+	listener net.Listener
+}
+
+// Serve starts serving the collector on /metrics at port in the background
+func Serve(port int, c *Collector) (*Server, error) {
+	listener, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on port %d: %w", port, err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", c)
+	s := &Server{
+		server:   &http.Server{Handler: mux, ReadHeaderTimeout: 5 * time.Second},
+		listener: listener,
+	}
+	go s.server.Serve(listener)
+	return s, nil
+}
+
+// URL returns the local URL of the metrics endpoint
+func (s *Server) URL() string {
+	_, port, _ := net.SplitHostPort(s.listener.Addr().String())
+	return "http://localhost:" + port + "/metrics"
+}
+
+// Close stops the server
+func (s *Server) Close() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return s.server.Shutdown(ctx)
+}
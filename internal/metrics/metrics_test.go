@@ -0,0 +1,96 @@
+package metrics
+
+import (
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+
+	"hermes/internal/circuit"
+	"hermes/internal/scheduler"
+)
+
+func TestCollectorWrite(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "hermes-metrics-test-*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	breaker := circuit.New(tmpDir)
+	if err := breaker.Initialize(); err != nil {
+		t.Fatal(err)
+	}
+
+	c := New(breaker)
+	c.IncLoops()
+	c.IncLoops()
+	c.IncTasksCompleted("F001")
+	c.IncTasksCompleted("F002")
+	c.IncTasksCompleted("F001")
+	c.AddTokens("claude", 1500)
+	c.AddTaskResult("claude", &scheduler.TaskResult{Metrics: scheduler.TaskMetrics{TokensIn: 300, TokensOut: 200, APICallCount: 1}})
+	c.AddTaskResult("claude", &scheduler.TaskResult{}) // Never reached the provider
+	c.SetResourceStats(func() scheduler.ResourceStats {
+		return scheduler.ResourceStats{TotalAPICalls: 7, TotalCost: 1.25}
+	})
+
+	var sb strings.Builder
+	c.Write(&sb)
+	out := sb.String()
+
+	for _, want := range []string{
+		"# TYPE hermes_loops_total counter",
+		"hermes_loops_total 3\n",
+		`hermes_tasks_completed_total{feature="F001"} 2`,
+		`hermes_tasks_completed_total{feature="F002"} 1`,
+		`hermes_ai_tokens_total{provider="claude"} 2000`,
+		"# TYPE hermes_circuit_breaker_state gauge",
+		"hermes_circuit_breaker_state 0\n",
+		"hermes_api_calls_total 7\n",
+		"hermes_ai_cost_dollars_total 1.25\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output:\n%s", want, out)
+		}
+	}
+}
+
+func TestCircuitStateValue(t *testing.T) {
+	tests := map[circuit.State]int{
+		circuit.StateClosed:   0,
+		circuit.StateHalfOpen: 1,
+		circuit.StateOpen:     2,
+	}
+	for state, want := range tests {
+		if got := CircuitStateValue(state); got != want {
+			t.Errorf("CircuitStateValue(%s) = %d, want %d", state, got, want)
+		}
+	}
+}
+
+func TestServe(t *testing.T) {
+	c := New(nil)
+	c.IncLoops()
+
+	server, err := Serve(0, c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer server.Close()
+
+	resp, err := http.Get(server.URL())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	if !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/plain") {
+		t.Errorf("unexpected content type %q", resp.Header.Get("Content-Type"))
+	}
+	body, _ := io.ReadAll(resp.Body)
+	if !strings.Contains(string(body), "hermes_loops_total 1") {
+		t.Errorf("unexpected body:\n%s", body)
+	}
+}
@@ -0,0 +1,215 @@
+package notifier
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"hermes/internal/circuit"
+	"hermes/internal/config"
+	"hermes/internal/task"
+)
+
+// Event types sent to webhooks
+const (
+	EventTaskStarted       = "task.started"
+	EventTaskCompleted     = "task.completed"
+	EventCircuitOpened     = "circuit.opened"
+	EventCircuitHalfOpened = "circuit.half_opened"
+	EventCircuitClosed     = "circuit.closed"
+	EventTest              = "test" // Sent by SendTest to every webhook
+)
+
+// Payload is the JSON body sent to webhooks
+type Payload struct {
+	Event       string         `json:"event"`
+	TaskID      string         `json:"taskId,omitempty"`
+	TaskName    string         `json:"taskName,omitempty"`
+	FeatureFile string         `json:"featureFile,omitempty"`
+	Timestamp   time.Time      `json:"timestamp"`
+	Elapsed     string         `json:"elapsed,omitempty"` // Since the run started, e.g. "12m30s"
+	Progress    *task.Progress `json:"progress,omitempty"`
+	Message     string         `json:"message,omitempty"`
+}
+
+// Notifier sends event notifications to configured webhooks
+type Notifier struct {
+	webhooks     []config.WebhookConfig
+	client       *http.Client
+	maxAttempts  int
+	retryDelay   time.Duration
+	enabled      bool
+	onlyFailures bool
+	started      time.Time
+}
+
+// New creates a new notifier for the given webhooks
+func New(webhooks []config.WebhookConfig) *Notifier {
+	return &Notifier{
+		webhooks:    webhooks,
+		client:      &http.Client{Timeout: 10 * time.Second},
+		maxAttempts: 3,
+		retryDelay:  time.Second,
+		enabled:     true,
+		started:     time.Now(),
+	}
+}
+
+// Disable turns off all webhook notifications
+func (n *Notifier) Disable() {
+	n.enabled = false
+}
+
+// SetOnlyFailures suppresses all events except circuit breaker failures
+// and the message of SendTest
+func (n *Notifier) SetOnlyFailures(onlyFailures bool) {
+	n.onlyFailures = onlyFailures
+}
+
+// IsEnabled returns true if notifications will be sent
+func (n *Notifier) IsEnabled() bool {
+	return n.enabled && len(n.webhooks) > 0
+}
+
+// Send delivers an event to every webhook subscribed to it
+func (n *Notifier) Send(event string, payload Payload) error {
+	if !n.IsEnabled() {
+		return nil
+	}
+	if n.onlyFailures && event != EventTest && !IsFailureEvent(event) {
+		return nil
+	}
+
+	payload.Event = event
+	if payload.Timestamp.IsZero() {
+		payload.Timestamp = time.Now()
+	}
+	if payload.Elapsed == "" {
+		payload.Elapsed = payload.Timestamp.Sub(n.started).Round(time.Second).String()
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode payload: %w", err)
+	}
+	slackBody, err := slackMessage(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode Slack message: %w", err)
+	}
+
+	var errs []string
+	for _, hook := range n.webhooks {
+		if !subscribed(hook, event) {
+			continue
+		}
+		target, hookBody := hook, body
+		if hook.Slack != "" {
+			target = config.WebhookConfig{URL: hook.Slack, Headers: hook.Headers}
+			hookBody = slackBody
+		}
+		if err := n.deliver(target, hookBody); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", target.URL, err))
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("webhook delivery failed: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// SendTest sends a test message to every webhook, regardless of the events
+// it subscribes to and of SetOnlyFailures
+func (n *Notifier) SendTest() error {
+	return n.Send(EventTest, Payload{Message: "Notifications are working."})
+}
+
+// deliver sends the body to a single webhook with exponential backoff
+func (n *Notifier) deliver(hook config.WebhookConfig, body []byte) error {
+	method := strings.ToUpper(hook.Method)
+	if method == "" {
+		method = http.MethodPost
+	}
+	if method != http.MethodPost && method != http.MethodPut {
+		return fmt.Errorf("unsupported method %s (use POST or PUT)", hook.Method)
+	}
+
+	delay := n.retryDelay
+	var lastErr error
+
+	for attempt := 1; attempt <= n.maxAttempts; attempt++ {
+		retry, err := n.post(method, hook, body)
+		if err == nil {
+			return nil
+		}
+		lastErr = err
+
+		if !retry || attempt == n.maxAttempts {
+			break
+		}
+
+		time.Sleep(delay)
+		delay *= 2
+	}
+
+	return lastErr
+}
+
+// post performs a single request and reports whether a failure is retryable
+func (n *Notifier) post(method string, hook config.WebhookConfig, body []byte) (bool, error) {
+	req, err := http.NewRequest(method, hook.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range hook.Headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+
+	retry := resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests
+	return retry, fmt.Errorf("unexpected status %d", resp.StatusCode)
+}
+
+// subscribed returns true if the webhook should receive the event
+func subscribed(hook config.WebhookConfig, event string) bool {
+	if len(hook.Events) == 0 || event == EventTest {
+		return true
+	}
+	for _, e := range hook.Events {
+		if e == event || e == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// IsFailureEvent returns true for events reporting that the run is stalling
+func IsFailureEvent(event string) bool {
+	return event == EventCircuitOpened || event == EventCircuitHalfOpened
+}
+
+// CircuitEvent returns the event name for a circuit breaker state
+func CircuitEvent(state circuit.State) string {
+	switch state {
+	case circuit.StateOpen:
+		return EventCircuitOpened
+	case circuit.StateHalfOpen:
+		return EventCircuitHalfOpened
+	default:
+		return EventCircuitClosed
+	}
+}
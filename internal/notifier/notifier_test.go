@@ -0,0 +1,278 @@
+package notifier
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"hermes/internal/circuit"
+	"hermes/internal/config"
+	"hermes/internal/task"
+)
+
+func newTestNotifier(webhooks []config.WebhookConfig) *Notifier {
+	n := New(webhooks)
+	n.retryDelay = time.Millisecond
+	return n
+}
+
+func TestSendPostsPayload(t *testing.T) {
+	var received Payload
+	var method, auth, contentType string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method = r.Method
+		auth = r.Header.Get("Authorization")
+		contentType = r.Header.Get("Content-Type")
+		json.NewDecoder(r.Body).Decode(&received)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	n := newTestNotifier([]config.WebhookConfig{{
+		URL:     server.URL,
+		Headers: map[string]string{"Authorization": "Bearer secret"},
+	}})
+
+	progress := &task.Progress{Total: 4, Completed: 1, Percentage: 25}
+	if err := n.Send(EventTaskCompleted, Payload{TaskID: "T001", Progress: progress}); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+
+	if method != http.MethodPost {
+		t.Errorf("expected POST, got %s", method)
+	}
+	if auth != "Bearer secret" {
+		t.Errorf("expected custom header, got %q", auth)
+	}
+	if contentType != "application/json" {
+		t.Errorf("expected JSON content type, got %q", contentType)
+	}
+	if received.Event != EventTaskCompleted || received.TaskID != "T001" {
+		t.Errorf("unexpected payload: %+v", received)
+	}
+	if received.Progress == nil || received.Progress.Completed != 1 {
+		t.Errorf("expected progress in payload, got %+v", received.Progress)
+	}
+	if received.Timestamp.IsZero() {
+		t.Error("expected timestamp to be set")
+	}
+}
+
+func TestSendUsesPut(t *testing.T) {
+	var method string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method = r.Method
+	}))
+	defer server.Close()
+
+	n := newTestNotifier([]config.WebhookConfig{{URL: server.URL, Method: "put"}})
+	if err := n.Send(EventTaskStarted, Payload{}); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	if method != http.MethodPut {
+		t.Errorf("expected PUT, got %s", method)
+	}
+}
+
+func TestSendRetriesWithBackoff(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	n := newTestNotifier([]config.WebhookConfig{{URL: server.URL}})
+	if err := n.Send(EventCircuitOpened, Payload{}); err != nil {
+		t.Fatalf("expected success after retries, got %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("expected 3 attempts, got %d", calls)
+	}
+}
+
+func TestSendGivesUpAfterMaxAttempts(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	n := newTestNotifier([]config.WebhookConfig{{URL: server.URL}})
+	if err := n.Send(EventTaskCompleted, Payload{}); err == nil {
+		t.Error("expected error after exhausting retries")
+	}
+	if calls != 3 {
+		t.Errorf("expected 3 attempts, got %d", calls)
+	}
+}
+
+func TestSendDoesNotRetryClientErrors(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	n := newTestNotifier([]config.WebhookConfig{{URL: server.URL}})
+	if err := n.Send(EventTaskCompleted, Payload{}); err == nil {
+		t.Error("expected error for 400 response")
+	}
+	if calls != 1 {
+		t.Errorf("expected 1 attempt, got %d", calls)
+	}
+}
+
+func TestSendFiltersEvents(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+	}))
+	defer server.Close()
+
+	n := newTestNotifier([]config.WebhookConfig{{
+		URL:    server.URL,
+		Events: []string{EventCircuitOpened},
+	}})
+
+	n.Send(EventTaskCompleted, Payload{})
+	if calls != 0 {
+		t.Errorf("unsubscribed event should not be sent, got %d calls", calls)
+	}
+
+	n.Send(EventCircuitOpened, Payload{})
+	if calls != 1 {
+		t.Errorf("subscribed event should be sent, got %d calls", calls)
+	}
+}
+
+func TestSendDisabled(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+	}))
+	defer server.Close()
+
+	n := newTestNotifier([]config.WebhookConfig{{URL: server.URL}})
+	n.Disable()
+
+	if n.IsEnabled() {
+		t.Error("notifier should be disabled")
+	}
+	if err := n.Send(EventTaskCompleted, Payload{}); err != nil {
+		t.Errorf("disabled notifier should not error: %v", err)
+	}
+	if calls != 0 {
+		t.Errorf("disabled notifier should not send, got %d calls", calls)
+	}
+}
+
+func TestSendSlack(t *testing.T) {
+	var received struct {
+		Text   string `json:"text"`
+		Blocks []struct {
+			Type     string `json:"type"`
+			Text     *struct{ Text string }
+			Elements []struct{ Text string }
+		} `json:"blocks"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&received)
+	}))
+	defer server.Close()
+
+	n := newTestNotifier([]config.WebhookConfig{{Slack: server.URL}})
+	payload := Payload{
+		TaskID:      "T002",
+		TaskName:    "Add login form",
+		FeatureFile: "/project/.hermes/tasks/001-auth.md",
+		Progress:    &task.Progress{Total: 4, Completed: 2, Percentage: 50},
+	}
+	if err := n.Send(EventTaskCompleted, payload); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+
+	if !strings.Contains(received.Text, "T002") {
+		t.Errorf("expected task ID in fallback text, got %q", received.Text)
+	}
+
+	var text strings.Builder
+	for _, block := range received.Blocks {
+		if block.Text != nil {
+			text.WriteString(block.Text.Text + "\n")
+		}
+		for _, element := range block.Elements {
+			text.WriteString(element.Text + "\n")
+		}
+	}
+	for _, want := range []string{"Task completed", "T002 - Add login form", "[##########----------] 50.0%", "2/4 tasks", "Elapsed: ", "|001-auth.md>"} {
+		if !strings.Contains(text.String(), want) {
+			t.Errorf("expected %q in Slack message:\n%s", want, text.String())
+		}
+	}
+}
+
+func TestSendOnlyFailures(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+	}))
+	defer server.Close()
+
+	n := newTestNotifier([]config.WebhookConfig{{URL: server.URL}})
+	n.SetOnlyFailures(true)
+
+	n.Send(EventTaskStarted, Payload{})
+	n.Send(EventTaskCompleted, Payload{})
+	n.Send(EventCircuitClosed, Payload{})
+	if calls != 0 {
+		t.Errorf("non-failure events should be suppressed, got %d calls", calls)
+	}
+
+	n.Send(EventCircuitOpened, Payload{})
+	n.Send(EventCircuitHalfOpened, Payload{})
+	if calls != 2 {
+		t.Errorf("failure events should be sent, got %d calls", calls)
+	}
+}
+
+func TestSendTest(t *testing.T) {
+	var received Payload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&received)
+	}))
+	defer server.Close()
+
+	// The test message ignores event subscriptions and the failure filter
+	n := newTestNotifier([]config.WebhookConfig{{URL: server.URL, Events: []string{EventCircuitOpened}}})
+	n.SetOnlyFailures(true)
+	if err := n.SendTest(); err != nil {
+		t.Fatalf("SendTest failed: %v", err)
+	}
+	if received.Event != EventTest || received.Message == "" {
+		t.Errorf("unexpected test payload: %+v", received)
+	}
+}
+
+func TestCircuitEvent(t *testing.T) {
+	tests := map[circuit.State]string{
+		circuit.StateOpen:     EventCircuitOpened,
+		circuit.StateHalfOpen: EventCircuitHalfOpened,
+		circuit.StateClosed:   EventCircuitClosed,
+	}
+	for state, expected := range tests {
+		if got := CircuitEvent(state); got != expected {
+			t.Errorf("CircuitEvent(%s) = %s, expected %s", state, got, expected)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package notifier
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+
+	"hermes/internal/ui"
+)
+
+// slackTitles are the message headlines of each event
+var slackTitles = map[string]string{
+	EventTaskStarted:       ":arrow_forward: Task started",
+	EventTaskCompleted:     ":white_check_mark: Task completed",
+	EventCircuitOpened:     ":rotating_light: Circuit breaker opened",
+	EventCircuitHalfOpened: ":warning: Circuit breaker half-open",
+	EventCircuitClosed:     ":large_green_circle: Circuit breaker closed",
+	EventTest:              ":wave: Hermes test notification",
+}
+
+type slackText struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+type slackBlock struct {
+	Type     string      `json:"type"`
+	Text     *slackText  `json:"text,omitempty"`
+	Elements []slackText `json:"elements,omitempty"`
+}
+
+// slackMessage renders a payload as a Slack Block Kit message
+func slackMessage(p Payload) ([]byte, error) {
+	title, ok := slackTitles[p.Event]
+	if !ok {
+		title = p.Event
+	}
+
+	headline := fmt.Sprintf("*%s*", title)
+	if p.TaskID != "" {
+		headline += fmt.Sprintf("\n%s", p.TaskID)
+		if p.TaskName != "" {
+			headline += " - " + p.TaskName
+		}
+	}
+	if p.Message != "" {
+		headline += "\n" + p.Message
+	}
+
+	blocks := []slackBlock{{Type: "section", Text: &slackText{Type: "mrkdwn", Text: headline}}}
+
+	if p.Progress != nil && p.Progress.Total > 0 {
+		bar := fmt.Sprintf("```%s  %d/%d tasks```", ui.FormatProgressBar(p.Progress.Percentage, 20),
+			p.Progress.Completed, p.Progress.Total)
+		blocks = append(blocks, slackBlock{Type: "section", Text: &slackText{Type: "mrkdwn", Text: bar}})
+	}
+
+	var context []slackText
+	if p.Elapsed != "" {
+		context = append(context, slackText{Type: "mrkdwn", Text: "Elapsed: " + p.Elapsed})
+	}
+	if p.FeatureFile != "" {
+		link := fmt.Sprintf("Feature file: <file://%s|%s>", filepath.ToSlash(p.FeatureFile), filepath.Base(p.FeatureFile))
+		context = append(context, slackText{Type: "mrkdwn", Text: link})
+	}
+	if len(context) > 0 {
+		blocks = append(blocks, slackBlock{Type: "context", Elements: context})
+	}
+
+	// text is the fallback shown in notifications
+	fallback := title
+	if p.TaskID != "" {
+		fallback += ": " + p.TaskID
+	}
+	return json.Marshal(struct {
+		Text   string       `json:"text"`
+		Blocks []slackBlock `json:"blocks"`
+	}{Text: fallback, Blocks: blocks})
+}
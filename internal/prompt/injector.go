@@ -11,21 +11,28 @@ import (
 )
 
 const (
-	TaskSectionStart = "<!-- HERMES_TASK_START -->"
-	TaskSectionEnd   = "<!-- HERMES_TASK_END -->"
+	TaskSectionStart    = "<!-- HERMES_TASK_START -->"
+	TaskSectionEnd      = "<!-- HERMES_TASK_END -->"
+	ContextSectionStart = "<!-- HERMES_CONTEXT_START -->"
+	ContextSectionEnd   = "<!-- HERMES_CONTEXT_END -->"
 )
 
 // Injector manages PROMPT.md task injection
 type Injector struct {
 	basePath   string
 	promptPath string
+	templates  *TemplateManager
+	context    []string
+	skipTests  bool
 }
 
 // NewInjector creates a new prompt injector
 func NewInjector(basePath string) *Injector {
+	templates := NewTemplateManager(basePath)
 	return &Injector{
 		basePath:   basePath,
-		promptPath: filepath.Join(basePath, ".hermes", "PROMPT.md"),
+		promptPath: templates.DefaultPath(),
+		templates:  templates,
 	}
 }
 
@@ -40,9 +47,10 @@ func (i *Injector) Exists() bool {
 	return err == nil
 }
 
-// Read reads the prompt content
-func (i *Injector) Read() (string, error) {
-	data, err := os.ReadFile(i.promptPath)
+// Read reads the prompt content. If a feature ID is given, the feature's
+// PROMPT-<feature-id>.md is read when it exists.
+func (i *Injector) Read(featureID ...string) (string, error) {
+	data, err := os.ReadFile(i.resolvePath(featureID))
 	if err != nil {
 		return "", err
 	}
@@ -51,16 +59,47 @@ func (i *Injector) Read() (string, error) {
 
 // Write writes the prompt content
 func (i *Injector) Write(content string) error {
-	dir := filepath.Dir(i.promptPath)
-	if err := os.MkdirAll(dir, 0755); err != nil {
+	return writePrompt(i.promptPath, content)
+}
+
+// resolvePath returns the prompt file for an optional feature ID
+func (i *Injector) resolvePath(featureID []string) string {
+	if len(featureID) == 0 {
+		return i.promptPath
+	}
+	return i.templates.Resolve(featureID[0])
+}
+
+func writePrompt(path, content string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
 		return err
 	}
-	return os.WriteFile(i.promptPath, []byte(content), 0644)
+	return os.WriteFile(path, []byte(content), 0644)
 }
 
-// AddTask adds a task section to the prompt
+// AddContext adds content, e.g. a style guide, that AddTask inserts before
+// the task section of every prompt
+func (i *Injector) AddContext(content string) {
+	if content = strings.TrimSpace(content); content != "" {
+		i.context = append(i.context, content)
+	}
+}
+
+// SetSkipTests makes AddTask tell the AI not to run tests and to report
+// that in the status block
+func (i *Injector) SetSkipTests(skip bool) {
+	i.skipTests = skip
+}
+
+// AddTask adds a task section to the prompt of the task's feature, preceded
+// by the context added with AddContext
 func (i *Injector) AddTask(t *task.Task) error {
-	content, err := i.Read()
+	return writePrompt(i.templates.Resolve(t.FeatureID), i.TaskPrompt(t))
+}
+
+// TaskPrompt returns the prompt AddTask writes for a task, without writing it
+func (i *Injector) TaskPrompt(t *task.Task) string {
+	content, err := i.Read(t.FeatureID)
 	if err != nil {
 		content = ""
 	}
@@ -70,32 +109,39 @@ func (i *Injector) AddTask(t *task.Task) error {
 
 	// Add new task section
 	section := i.generateTaskSection(t)
+	if len(i.context) > 0 {
+		section = i.generateContextSection() + "\n\n" + section
+	}
 	if content != "" {
-		content = content + "\n\n" + section
-	} else {
-		content = section
+		return content + "\n\n" + section
 	}
-
-	return i.Write(content)
+	return section
 }
 
-// RemoveTask removes the task section from the prompt
-func (i *Injector) RemoveTask() error {
-	content, err := i.Read()
+// RemoveTask removes the task section from the prompt, optionally for a feature
+func (i *Injector) RemoveTask(featureID ...string) error {
+	content, err := i.Read(featureID...)
 	if err != nil {
 		return err
 	}
 
 	content = i.removeTaskSection(content)
-	return i.Write(strings.TrimSpace(content))
+	return writePrompt(i.resolvePath(featureID), strings.TrimSpace(content))
 }
 
+// removeTaskSection removes the task section and the context injected with it
 func (i *Injector) removeTaskSection(content string) string {
-	re := regexp.MustCompile(`(?s)` + regexp.QuoteMeta(TaskSectionStart) + `.*?` + regexp.QuoteMeta(TaskSectionEnd))
-	content = re.ReplaceAllString(content, "")
+	for _, markers := range [][2]string{{ContextSectionStart, ContextSectionEnd}, {TaskSectionStart, TaskSectionEnd}} {
+		re := regexp.MustCompile(`(?s)` + regexp.QuoteMeta(markers[0]) + `.*?` + regexp.QuoteMeta(markers[1]))
+		content = re.ReplaceAllString(content, "")
+	}
 	return strings.TrimSpace(content)
 }
 
+func (i *Injector) generateContextSection() string {
+	return ContextSectionStart + "\n" + strings.Join(i.context, "\n\n") + "\n" + ContextSectionEnd
+}
+
 func (i *Injector) generateTaskSection(t *task.Task) string {
 	var sb strings.Builder
 
@@ -152,25 +198,29 @@ func (i *Injector) generateTaskSection(t *task.Task) string {
 	sb.WriteString("4. Write tests for new functionality\n")
 	sb.WriteString("5. Verify all success criteria are met\n")
 	sb.WriteString("6. Output status block when complete\n\n")
+	if i.skipTests {
+		sb.WriteString("**Do not run tests; focus on implementation only.** ")
+		sb.WriteString("Set `skip_tests` to true in the status block.\n\n")
+	}
 
 	sb.WriteString("### Completion Status Block\n\n")
-	sb.WriteString("When task is complete, output this block:\n\n")
-	sb.WriteString("```\n")
-	sb.WriteString("---HERMES_STATUS---\n")
-	sb.WriteString("STATUS: COMPLETE\n")
-	sb.WriteString("EXIT_SIGNAL: true\n")
-	sb.WriteString("RECOMMENDATION: Move to next task\n")
-	sb.WriteString("---END_HERMES_STATUS---\n")
-	sb.WriteString("```\n\n")
+	sb.WriteString(StatusBlockSchema{
+		Status:         "COMPLETE",
+		ExitSignal:     true,
+		WorkType:       "implementation",
+		Recommendation: "Move to next task",
+		SkipTests:      i.skipTests,
+	}.Instructions())
+	sb.WriteString("\n")
 
 	sb.WriteString(TaskSectionEnd)
 
 	return sb.String()
 }
 
-// GetCurrentTaskID returns the task ID from the prompt
-func (i *Injector) GetCurrentTaskID() (string, error) {
-	content, err := i.Read()
+// GetCurrentTaskID returns the task ID from the prompt, optionally for a feature
+func (i *Injector) GetCurrentTaskID(featureID ...string) (string, error) {
+	content, err := i.Read(featureID...)
 	if err != nil {
 		return "", err
 	}
@@ -113,6 +113,72 @@ func TestAddTask(t *testing.T) {
 	}
 }
 
+func TestAddContext(t *testing.T) {
+	tmpDir, cleanup := setupTestDir(t)
+	defer cleanup()
+
+	i := NewInjector(tmpDir)
+	i.Write("# Base Prompt")
+	i.AddContext("# ADR 001\n\nUse PostgreSQL.")
+	i.AddContext("  ")
+	i.AddContext("Tabs, not spaces.")
+
+	testTask := &task.Task{ID: "T001", Name: "Implement login"}
+	if err := i.AddTask(testTask); err != nil {
+		t.Fatal(err)
+	}
+	// Replacing the task must not duplicate the context
+	if err := i.AddTask(testTask); err != nil {
+		t.Fatal(err)
+	}
+
+	content, _ := i.Read()
+	if strings.Count(content, "Use PostgreSQL.") != 1 || strings.Count(content, ContextSectionStart) != 1 {
+		t.Errorf("expected the context once, got:\n%s", content)
+	}
+	base := strings.Index(content, "# Base Prompt")
+	adr := strings.Index(content, "Use PostgreSQL.")
+	style := strings.Index(content, "Tabs, not spaces.")
+	taskStart := strings.Index(content, TaskSectionStart)
+	if !(base < adr && adr < style && style < taskStart) {
+		t.Errorf("expected context between the base prompt and the task section, got:\n%s", content)
+	}
+
+	if err := i.RemoveTask(); err != nil {
+		t.Fatal(err)
+	}
+	content, _ = i.Read()
+	if content != "# Base Prompt" {
+		t.Errorf("expected context removed with the task, got %q", content)
+	}
+}
+
+func TestSkipTests(t *testing.T) {
+	tmpDir, cleanup := setupTestDir(t)
+	defer cleanup()
+
+	i := NewInjector(tmpDir)
+	testTask := &task.Task{ID: "T001", Name: "Implement login"}
+	i.AddTask(testTask)
+	content, _ := i.Read()
+	if strings.Contains(content, "Do not run tests") || strings.Contains(content, `"skip_tests": true`) {
+		t.Errorf("expected no skip tests instruction by default, got:\n%s", content)
+	}
+
+	i.SetSkipTests(true)
+	i.AddTask(testTask)
+	content, _ = i.Read()
+	if !strings.Contains(content, "Do not run tests; focus on implementation only.") {
+		t.Errorf("expected the skip tests instruction, got:\n%s", content)
+	}
+	if !strings.Contains(content, `"skip_tests": true`) {
+		t.Errorf("expected skip_tests in the example status block, got:\n%s", content)
+	}
+	if end := strings.Index(content, TaskSectionEnd); strings.Index(content, "Do not run tests") > end {
+		t.Error("expected the instruction inside the task section")
+	}
+}
+
 func TestRemoveTask(t *testing.T) {
 	tmpDir, cleanup := setupTestDir(t)
 	defer cleanup()
@@ -327,3 +393,131 @@ func TestCleanupBackups(t *testing.T) {
 		t.Errorf("expected 2 backups after cleanup, got %d", len(backups))
 	}
 }
+
+func TestTemplateResolution(t *testing.T) {
+	tmpDir, cleanup := setupTestDir(t)
+	defer cleanup()
+
+	m := NewTemplateManager(tmpDir)
+
+	// Without any feature template, PROMPT.md is used
+	if got := m.Resolve("F001"); got != m.DefaultPath() {
+		t.Errorf("expected default path, got %s", got)
+	}
+	if got := m.Resolve(""); got != m.DefaultPath() {
+		t.Errorf("expected default path for empty feature, got %s", got)
+	}
+
+	os.MkdirAll(filepath.Join(tmpDir, ".hermes"), 0755)
+	os.WriteFile(m.DefaultPath(), []byte("default"), 0644)
+	os.WriteFile(m.FeaturePath("F001"), []byte("feature one"), 0644)
+
+	// Feature template takes priority over PROMPT.md
+	if got := m.Resolve("F001"); got != filepath.Join(tmpDir, ".hermes", "PROMPT-F001.md") {
+		t.Errorf("expected feature template, got %s", got)
+	}
+	if got := m.Resolve("f001"); got != m.FeaturePath("F001") {
+		t.Errorf("expected feature ID to be case-insensitive, got %s", got)
+	}
+	if got := m.Resolve("F002"); got != m.DefaultPath() {
+		t.Errorf("expected fallback to PROMPT.md for F002, got %s", got)
+	}
+
+	i := NewInjector(tmpDir)
+	if content, _ := i.Read("F001"); content != "feature one" {
+		t.Errorf("expected feature template content, got %q", content)
+	}
+	if content, _ := i.Read("F002"); content != "default" {
+		t.Errorf("expected default content, got %q", content)
+	}
+	if content, _ := i.Read(); content != "default" {
+		t.Errorf("expected default content, got %q", content)
+	}
+}
+
+func TestAddTaskUsesFeatureTemplate(t *testing.T) {
+	tmpDir, cleanup := setupTestDir(t)
+	defer cleanup()
+
+	i := NewInjector(tmpDir)
+	i.Write("default")
+	os.WriteFile(i.templates.FeaturePath("F002"), []byte("feature two"), 0644)
+
+	tk := &task.Task{ID: "T005", Name: "Feature task", FeatureID: "F002"}
+	if err := i.AddTask(tk); err != nil {
+		t.Fatal(err)
+	}
+
+	content, _ := i.Read("F002")
+	if !strings.HasPrefix(content, "feature two") || !strings.Contains(content, "## Current Task: T005") {
+		t.Errorf("expected task injected into feature template, got %q", content)
+	}
+	if def, _ := i.Read(); def != "default" {
+		t.Errorf("PROMPT.md should be untouched, got %q", def)
+	}
+
+	if err := i.RemoveTask("F002"); err != nil {
+		t.Fatal(err)
+	}
+	if content, _ := i.Read("F002"); content != "feature two" {
+		t.Errorf("expected task section removed, got %q", content)
+	}
+}
+
+func TestTemplateInstall(t *testing.T) {
+	tmpDir, cleanup := setupTestDir(t)
+	defer cleanup()
+
+	src := filepath.Join(tmpDir, "custom.md")
+	os.WriteFile(src, []byte("# Custom"), 0644)
+
+	m := NewTemplateManager(tmpDir)
+	if err := m.Install(src, ""); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(m.DefaultPath()); string(data) != "# Custom" {
+		t.Errorf("expected PROMPT.md to be installed, got %q", string(data))
+	}
+
+	if err := m.Install(src, "F003"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(m.FeaturePath("F003")); err != nil {
+		t.Errorf("expected feature template to be installed: %v", err)
+	}
+
+	if err := m.Install(filepath.Join(tmpDir, "missing.md"), ""); err == nil {
+		t.Error("expected error for missing template")
+	}
+}
+
+func TestStatusBlockSchema(t *testing.T) {
+	schema := StatusBlockSchema{}.JSONSchema()
+	for _, expected := range []string{`"hermes_status"`, `"exit_signal"`, `"boolean"`, `"COMPLETE"`} {
+		if !strings.Contains(schema, expected) {
+			t.Errorf("expected schema to contain %s, got:\n%s", expected, schema)
+		}
+	}
+
+	// The example in the instructions parses back into the same block
+	example := StatusBlockSchema{Status: "COMPLETE", ExitSignal: true, Recommendation: "Next"}
+	block := ParseStatusBlock(example.Instructions())
+	if block == nil || *block != example {
+		t.Errorf("expected %+v from instructions, got %+v", example, block)
+	}
+}
+
+func TestParseStatusBlock(t *testing.T) {
+	output := "Done.\n\n```json\n{\"other\": 1}\n```\n\n```json\n{\"hermes_status\": {\"status\": \"in_progress\", \"exit_signal\": false}}\n```\n"
+	block := ParseStatusBlock(output)
+	if block == nil || block.Status != "IN_PROGRESS" || block.ExitSignal {
+		t.Errorf("expected IN_PROGRESS block, got %+v", block)
+	}
+
+	if ParseStatusBlock("```json\n{\"hermes_status\": \"broken\"\n```") != nil {
+		t.Error("expected nil for invalid JSON")
+	}
+	if ParseStatusBlock("---HERMES_STATUS---\nSTATUS: COMPLETE\n---END_HERMES_STATUS---") != nil {
+		t.Error("expected nil for plain-text block")
+	}
+}
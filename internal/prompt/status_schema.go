@@ -0,0 +1,131 @@
+package prompt
+
+import (
+	"encoding/json"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// StatusBlockKey is the top-level key of the JSON status block
+const StatusBlockKey = "hermes_status"
+
+var fencedJSONRegex = regexp.MustCompile("(?s)```(?:json)?[ \\t]*\\n(\\{.*?\\})\\s*```")
+
+// StatusBlockSchema is the JSON status block the AI emits at the end of each loop:
+//
+//	{"hermes_status": {"status": "COMPLETE", "exit_signal": true, ...}}
+type StatusBlockSchema struct {
+	Status         string `json:"status" enum:"IN_PROGRESS,COMPLETE,BLOCKED" desc:"Task status after this loop"`
+	ExitSignal     bool   `json:"exit_signal" desc:"true when the task is complete and the loop should move on"`
+	WorkType       string `json:"work_type,omitempty" enum:"implementation,testing,documentation,refactoring" desc:"Main kind of work done"`
+	Recommendation string `json:"recommendation,omitempty" desc:"One-line next step"`
+	SkipTests      bool   `json:"skip_tests,omitempty" desc:"true when tests were not run because the task said so"`
+}
+
+// JSONSchema returns a JSON Schema describing the status block, generated
+// from the StatusBlockSchema fields
+func (StatusBlockSchema) JSONSchema() string {
+	t := reflect.TypeOf(StatusBlockSchema{})
+	properties := make(map[string]interface{})
+	var required []string
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, opts, _ := strings.Cut(field.Tag.Get("json"), ",")
+
+		prop := map[string]interface{}{"description": field.Tag.Get("desc")}
+		switch field.Type.Kind() {
+		case reflect.Bool:
+			prop["type"] = "boolean"
+		default:
+			prop["type"] = "string"
+		}
+		if enum := field.Tag.Get("enum"); enum != "" {
+			prop["enum"] = strings.Split(enum, ",")
+		}
+		properties[name] = prop
+
+		if !strings.Contains(opts, "omitempty") {
+			required = append(required, name)
+		}
+	}
+
+	schema := map[string]interface{}{
+		"type":     "object",
+		"required": []string{StatusBlockKey},
+		"properties": map[string]interface{}{
+			StatusBlockKey: map[string]interface{}{
+				"type":       "object",
+				"required":   required,
+				"properties": properties,
+			},
+		},
+	}
+
+	data, _ := json.MarshalIndent(schema, "", "  ")
+	return string(data)
+}
+
+// Example returns the status block wrapped in its top-level key
+func (s StatusBlockSchema) Example() string {
+	data, _ := json.MarshalIndent(map[string]StatusBlockSchema{StatusBlockKey: s}, "", "  ")
+	return string(data)
+}
+
+// Instructions returns the prompt text asking the AI to emit the JSON status block
+func (s StatusBlockSchema) Instructions() string {
+	var sb strings.Builder
+
+	sb.WriteString("When you finish, output a status block as JSON in a fenced code block. ")
+	sb.WriteString("Set `status` to COMPLETE and `exit_signal` to true only when every success criterion is met:\n\n")
+	sb.WriteString("```json\n")
+	sb.WriteString(s.Example())
+	sb.WriteString("\n```\n\n")
+	sb.WriteString("The block must match this JSON Schema:\n\n")
+	sb.WriteString("```json\n")
+	sb.WriteString(s.JSONSchema())
+	sb.WriteString("\n```\n")
+
+	return sb.String()
+}
+
+// ParseStatusBlock returns the last JSON status block found in a fenced code
+// block of output, or nil if there is none
+func ParseStatusBlock(output string) *StatusBlockSchema {
+	matches := fencedJSONRegex.FindAllStringSubmatch(output, -1)
+	for i := len(matches) - 1; i >= 0; i-- {
+		if block := parseStatusJSON(matches[i][1]); block != nil {
+			return block
+		}
+	}
+	return nil
+}
+
+// FindStatusBlock returns the raw fenced JSON status block in output, or ""
+func FindStatusBlock(output string) string {
+	matches := fencedJSONRegex.FindAllStringSubmatch(output, -1)
+	for i := len(matches) - 1; i >= 0; i-- {
+		if parseStatusJSON(matches[i][1]) != nil {
+			return matches[i][0]
+		}
+	}
+	return ""
+}
+
+func parseStatusJSON(data string) *StatusBlockSchema {
+	if !strings.Contains(data, StatusBlockKey) {
+		return nil
+	}
+
+	var wrapper map[string]*StatusBlockSchema
+	if err := json.Unmarshal([]byte(data), &wrapper); err != nil {
+		return nil
+	}
+	block := wrapper[StatusBlockKey]
+	if block == nil {
+		return nil
+	}
+	block.Status = strings.ToUpper(strings.TrimSpace(block.Status))
+	return block
+}
@@ -1,5 +1,12 @@
 package prompt
 
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
 // DefaultPromptTemplate is the default PROMPT.md content
 const DefaultPromptTemplate = `# Project Instructions
 
@@ -40,3 +47,55 @@ func (i *Injector) CreateDefault() error {
 func (i *Injector) EnsureExists() error {
 	return i.CreateDefault()
 }
+
+// TemplateManager resolves prompt templates, preferring a feature-specific
+// PROMPT-<feature-id>.md over the shared PROMPT.md
+type TemplateManager struct {
+	hermesDir string
+}
+
+// NewTemplateManager creates a new template manager
+func NewTemplateManager(basePath string) *TemplateManager {
+	return &TemplateManager{
+		hermesDir: filepath.Join(basePath, ".hermes"),
+	}
+}
+
+// DefaultPath returns the path to the shared PROMPT.md
+func (m *TemplateManager) DefaultPath() string {
+	return filepath.Join(m.hermesDir, "PROMPT.md")
+}
+
+// FeaturePath returns the path to the template for a feature
+func (m *TemplateManager) FeaturePath(featureID string) string {
+	return filepath.Join(m.hermesDir, fmt.Sprintf("PROMPT-%s.md", strings.ToUpper(featureID)))
+}
+
+// Resolve returns the feature template if it exists, otherwise PROMPT.md
+func (m *TemplateManager) Resolve(featureID string) string {
+	if featureID != "" {
+		path := m.FeaturePath(featureID)
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return m.DefaultPath()
+}
+
+// Install copies a template file into place. An empty feature ID installs PROMPT.md.
+func (m *TemplateManager) Install(src, featureID string) error {
+	content, err := os.ReadFile(src)
+	if err != nil {
+		return fmt.Errorf("failed to read template: %w", err)
+	}
+
+	dest := m.DefaultPath()
+	if featureID != "" {
+		dest = m.FeaturePath(featureID)
+	}
+
+	if err := os.MkdirAll(m.hermesDir, 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(dest, content, 0644)
+}
@@ -0,0 +1,171 @@
+package scheduler
+
+import (
+	"container/heap"
+	"context"
+	"fmt"
+	"sync"
+
+	"hermes/internal/task"
+)
+
+// DispatchPolicy decides which queued task an idle worker runs next
+type DispatchPolicy int
+
+const (
+	// PriorityPolicy runs the task with the highest priority (P1 first)
+	PriorityPolicy DispatchPolicy = iota
+	// FIFOPolicy runs tasks in the order they were submitted
+	FIFOPolicy
+	// ShortestJobFirstPolicy runs the task with the smallest effort estimate
+	ShortestJobFirstPolicy
+)
+
+// String returns the policy name
+func (d DispatchPolicy) String() string {
+	switch d {
+	case FIFOPolicy:
+		return "fifo"
+	case ShortestJobFirstPolicy:
+		return "shortest-job-first"
+	default:
+		return "priority"
+	}
+}
+
+// before reports whether a runs before b. Ties run in submission order.
+func (d DispatchPolicy) before(a, b *queuedTask) bool {
+	switch d {
+	case PriorityPolicy:
+		if pa, pb := priorityRank(a.task.Priority), priorityRank(b.task.Priority); pa != pb {
+			return pa < pb
+		}
+	case ShortestJobFirstPolicy:
+		if ma, mb := taskMinutes(a.task), taskMinutes(b.task); ma != mb {
+			return ma < mb
+		}
+	}
+	return a.seq < b.seq
+}
+
+// priorityRank returns the PriorityOrder of a priority, ranking unknown
+// priorities last
+func priorityRank(p task.Priority) int {
+	if rank, ok := PriorityOrder[p]; ok {
+		return rank
+	}
+	return len(PriorityOrder) + 1
+}
+
+// taskMinutes returns the effort estimate of a task, or defaultTaskMinutes
+func taskMinutes(t *task.Task) int {
+	if t.EstimatedMinutes > 0 {
+		return t.EstimatedMinutes
+	}
+	return defaultTaskMinutes
+}
+
+// queuedTask is a task waiting in a taskQueue
+type queuedTask struct {
+	task *task.Task
+	seq  uint64 // Submission order
+}
+
+// taskHeap orders queued tasks by a dispatch policy
+type taskHeap struct {
+	items  []*queuedTask
+	policy DispatchPolicy
+}
+
+func (h *taskHeap) Len() int           { return len(h.items) }
+func (h *taskHeap) Less(i, j int) bool { return h.policy.before(h.items[i], h.items[j]) }
+func (h *taskHeap) Swap(i, j int)      { h.items[i], h.items[j] = h.items[j], h.items[i] }
+func (h *taskHeap) Push(x interface{}) { h.items = append(h.items, x.(*queuedTask)) }
+func (h *taskHeap) Pop() interface{} {
+	last := h.items[len(h.items)-1]
+	h.items = h.items[:len(h.items)-1]
+	return last
+}
+
+// taskQueue is the queue workers take tasks from, ordered by a dispatch
+// policy. Pop blocks until a task is queued, the queue is closed or the
+// context is done.
+type taskQueue struct {
+	mu     sync.Mutex
+	cond   *sync.Cond
+	heap   taskHeap
+	seq    uint64
+	closed bool
+	ctx    context.Context
+	stop   func() bool // Stops waking waiters when ctx is done
+}
+
+// newTaskQueue creates an empty queue using PriorityPolicy
+func newTaskQueue(ctx context.Context) *taskQueue {
+	q := &taskQueue{ctx: ctx, heap: taskHeap{policy: PriorityPolicy}}
+	q.cond = sync.NewCond(&q.mu)
+	q.stop = context.AfterFunc(ctx, func() {
+		q.mu.Lock()
+		q.cond.Broadcast()
+		q.mu.Unlock()
+	})
+	return q
+}
+
+// setPolicy changes the dispatch policy, reordering the queued tasks
+func (q *taskQueue) setPolicy(policy DispatchPolicy) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.heap.policy = policy
+	heap.Init(&q.heap)
+}
+
+// push queues a task
+func (q *taskQueue) push(t *task.Task) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.closed {
+		return fmt.Errorf("task %s submitted after the pool was closed", t.ID)
+	}
+	if err := q.ctx.Err(); err != nil {
+		return err
+	}
+	q.seq++
+	heap.Push(&q.heap, &queuedTask{task: t, seq: q.seq})
+	q.cond.Signal()
+	return nil
+}
+
+// pop returns the next task by the dispatch policy. It returns false once
+// the queue is closed and empty, or the context is done.
+func (q *taskQueue) pop() (*task.Task, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for q.heap.Len() == 0 && !q.closed && q.ctx.Err() == nil {
+		q.cond.Wait()
+	}
+	if q.ctx.Err() != nil || q.heap.Len() == 0 {
+		return nil, false
+	}
+	return heap.Pop(&q.heap).(*queuedTask).task, true
+}
+
+// drain removes and returns all queued tasks in dispatch order
+func (q *taskQueue) drain() []*task.Task {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	var tasks []*task.Task
+	for q.heap.Len() > 0 {
+		tasks = append(tasks, heap.Pop(&q.heap).(*queuedTask).task)
+	}
+	return tasks
+}
+
+// close lets pop return false once the queued tasks are taken
+func (q *taskQueue) close() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.closed = true
+	q.stop()
+	q.cond.Broadcast()
+}
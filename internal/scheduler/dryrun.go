@@ -0,0 +1,150 @@
+package scheduler
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"hermes/internal/task"
+)
+
+// dryRun validates tasks in execution order without running the AI.
+// Each TaskResult succeeds if the task has no validation errors; file
+// conflicts between tasks of the same batch are reported in the result.
+func (s *Scheduler) dryRun(tasks []*task.Task) (*ExecutionResult, error) {
+	startTime := time.Now()
+	result := &ExecutionResult{
+		Results:   make([]*TaskResult, 0),
+		StartTime: startTime,
+		Conflicts: make(map[string][]string),
+	}
+
+	byID := make(map[string]*task.Task, len(tasks))
+	for _, t := range tasks {
+		byID[t.ID] = t
+	}
+
+	// Resolve dependencies first so that unknown IDs are reported per task
+	// instead of failing the graph build
+	depErrors := make(map[string][]string)
+	graphTasks := make([]*task.Task, len(tasks))
+	for i, t := range tasks {
+		deps := t.DependsOn
+		if len(deps) == 0 {
+			deps = t.Dependencies
+		}
+
+		var resolved []string
+		for _, dep := range deps {
+			if _, ok := byID[dep]; ok {
+				resolved = append(resolved, dep)
+			} else {
+				depErrors[t.ID] = append(depErrors[t.ID], fmt.Sprintf("unknown dependency %s", dep))
+			}
+		}
+
+		copied := *t
+		copied.DependsOn = resolved
+		copied.Dependencies = nil
+		graphTasks[i] = &copied
+	}
+
+	graph, err := NewTaskGraph(graphTasks)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build task graph: %w", err)
+	}
+	batches, err := graph.GetBatches()
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute execution batches: %w", err)
+	}
+
+	for batchNum, batch := range batches {
+		sort.Slice(batch, func(i, j int) bool { return batch[i].ID < batch[j].ID })
+
+		for file, taskIDs := range DetectFileConflicts(s.ignore.FilterTasks(batch)) {
+			sort.Strings(taskIDs)
+			result.Conflicts[file] = taskIDs
+		}
+
+		for _, t := range batch {
+			problems := append([]string{}, depErrors[t.ID]...)
+			problems = append(problems, s.validateFiles(t)...)
+
+			taskResult := &TaskResult{
+				TaskID:   t.ID,
+				TaskName: t.Name,
+				Batch:    batchNum + 1,
+				Success:  len(problems) == 0,
+			}
+			if len(problems) > 0 {
+				taskResult.Error = errors.New(strings.Join(problems, "; "))
+			}
+			result.Results = append(result.Results, taskResult)
+		}
+	}
+
+	result.EndTime = time.Now()
+	result.TotalTime = result.EndTime.Sub(startTime)
+	s.countResults(result)
+
+	return result, nil
+}
+
+// validateFiles checks that each file to touch stays inside the work
+// directory and, if it exists, is a regular file. Missing files are
+// treated as new files.
+func (s *Scheduler) validateFiles(t *task.Task) []string {
+	var problems []string
+	for _, file := range t.FilesToTouch {
+		if filepath.IsAbs(file) {
+			problems = append(problems, fmt.Sprintf("%s: absolute path", file))
+			continue
+		}
+		clean := filepath.Clean(file)
+		if clean == ".." || strings.HasPrefix(clean, ".."+string(filepath.Separator)) {
+			problems = append(problems, fmt.Sprintf("%s: outside the project", file))
+			continue
+		}
+		if info, err := os.Stat(filepath.Join(s.workDir, clean)); err == nil && info.IsDir() && !strings.HasSuffix(file, "/") {
+			problems = append(problems, fmt.Sprintf("%s: is a directory", file))
+		}
+	}
+	return problems
+}
+
+// PrintDryRunResult prints the tasks with their batch and any detected conflicts
+func (s *Scheduler) PrintDryRunResult(result *ExecutionResult) {
+	fmt.Println("\n🔍 Dry Run")
+	fmt.Println("═══════════════════════════════════════")
+	fmt.Printf("%-6s %-5s %-6s %s\n", "Batch", "Task", "Valid", "Name")
+	for _, r := range result.Results {
+		status := "✓"
+		if !r.Success {
+			status = "✗"
+		}
+		fmt.Printf("%-6d %-5s %-6s %s\n", r.Batch, r.TaskID, status, r.TaskName)
+		if r.Error != nil {
+			fmt.Printf("       └─ %v\n", r.Error)
+		}
+	}
+
+	if len(result.Conflicts) > 0 {
+		files := make([]string, 0, len(result.Conflicts))
+		for file := range result.Conflicts {
+			files = append(files, file)
+		}
+		sort.Strings(files)
+
+		fmt.Printf("\nFile conflicts: %d\n", len(files))
+		for _, file := range files {
+			fmt.Printf("  %s: %s\n", file, strings.Join(result.Conflicts[file], ", "))
+		}
+	} else {
+		fmt.Println("\nNo file conflicts detected")
+	}
+	fmt.Println("═══════════════════════════════════════")
+}
@@ -0,0 +1,41 @@
+package scheduler
+
+import (
+	"context"
+
+	"hermes/internal/task"
+)
+
+// WorkerEventType identifies what a WorkerEvent reports
+type WorkerEventType int
+
+const (
+	EventBatchStart WorkerEventType = iota // Batch and Batches are set
+	EventTaskStart                         // WorkerID and Task are set
+	EventTaskDone                          // WorkerID and Result are set
+	EventRunDone                           // Execute returned; no events follow
+)
+
+// WorkerEvent reports progress of a parallel run. Events are sent on the
+// channel given to Scheduler.SetEventChannel so that a UI can consume them
+// in its own goroutine.
+type WorkerEvent struct {
+	Type     WorkerEventType
+	WorkerID int
+	Task     *task.Task
+	Result   *TaskResult
+	Batch    int
+	Batches  int
+}
+
+// sendEvent sends an event on ch unless ch is nil. It gives up when ctx is
+// done, so a consumer that stopped reading does not block execution.
+func sendEvent(ctx context.Context, ch chan<- WorkerEvent, event WorkerEvent) {
+	if ch == nil {
+		return
+	}
+	select {
+	case ch <- event:
+	case <-ctx.Done():
+	}
+}
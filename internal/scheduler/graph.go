@@ -2,6 +2,9 @@ package scheduler
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
 
 	"hermes/internal/task"
 )
@@ -45,15 +48,19 @@ type TaskNode struct {
 
 // TaskGraph represents a directed acyclic graph of task dependencies
 type TaskGraph struct {
-	nodes map[string]*TaskNode
-	edges map[string][]string // task -> its dependencies
+	nodes    map[string]*TaskNode
+	edges    map[string][]string // task -> its dependencies
+	groups   map[string][]string // task -> tasks that must share its batch (co-deploy)
+	basePath string              // Files of requires-file constraints are checked relative to it
+	shard    *Shard              // Limits GetReadyTasks to one shard of a distributed run
 }
 
 // NewTaskGraph creates a new task graph from a list of tasks
 func NewTaskGraph(tasks []*task.Task) (*TaskGraph, error) {
 	g := &TaskGraph{
-		nodes: make(map[string]*TaskNode),
-		edges: make(map[string][]string),
+		nodes:    make(map[string]*TaskNode),
+		edges:    make(map[string][]string),
+		basePath: ".",
 	}
 
 	// Create nodes for all tasks
@@ -93,23 +100,124 @@ func NewTaskGraph(tasks []*task.Task) (*TaskGraph, error) {
 		return nil, fmt.Errorf("circular dependency detected in task graph")
 	}
 
+	groups, err := buildCoDeployGroups(g.nodes)
+	if err != nil {
+		return nil, err
+	}
+	g.groups = groups
+
 	// Mark tasks with no dependencies as ready
 	for _, node := range g.nodes {
-		if node.InDegree == 0 && node.Task.Status != task.StatusCompleted {
-			node.Status = NodeReady
-		} else if node.Task.Status == task.StatusCompleted {
+		if node.Task.Status == task.StatusCompleted {
 			node.Status = NodeCompleted
+		} else {
+			g.updateReadiness(node)
 		}
 	}
 
 	return g, nil
 }
 
-// GetReadyTasks returns tasks that are ready to be executed (no pending dependencies)
+// buildCoDeployGroups joins tasks linked by co-deploy constraints into groups
+func buildCoDeployGroups(nodes map[string]*TaskNode) (map[string][]string, error) {
+	parent := make(map[string]string)
+	var find func(id string) string
+	find = func(id string) string {
+		if p, ok := parent[id]; ok && p != id {
+			root := find(p)
+			parent[id] = root
+			return root
+		}
+		return id
+	}
+
+	for id, node := range nodes {
+		for _, other := range node.Task.ConstraintIDs(task.ConstraintCoDeploy) {
+			if _, exists := nodes[other]; !exists {
+				return nil, fmt.Errorf("task %s co-deploys with non-existent task %s", id, other)
+			}
+			for _, member := range []string{id, other} {
+				if _, ok := parent[member]; !ok {
+					parent[member] = member
+				}
+			}
+			parent[find(id)] = find(other)
+		}
+	}
+
+	members := make(map[string][]string)
+	for id := range parent {
+		root := find(id)
+		members[root] = append(members[root], id)
+	}
+
+	groups := make(map[string][]string)
+	for _, ids := range members {
+		sort.Strings(ids)
+		for _, id := range ids {
+			groups[id] = ids
+		}
+	}
+	return groups, nil
+}
+
+// SetBasePath sets the directory requires-file constraints are checked in
+// and re-evaluates which tasks are ready
+func (g *TaskGraph) SetBasePath(path string) {
+	g.basePath = path
+	for _, node := range g.nodes {
+		g.updateReadiness(node)
+	}
+}
+
+// MissingFiles returns the files required by the task's requires-file
+// constraints that do not exist yet
+func (g *TaskGraph) MissingFiles(taskID string) []string {
+	node, exists := g.nodes[taskID]
+	if !exists {
+		return nil
+	}
+
+	var missing []string
+	for _, depID := range node.Task.ConstraintIDs(task.ConstraintRequiresFile) {
+		dep, exists := g.nodes[depID]
+		if !exists {
+			continue
+		}
+		for _, file := range dep.Task.FilesToTouch {
+			if _, err := os.Stat(filepath.Join(g.basePath, file)); err != nil {
+				missing = append(missing, file)
+			}
+		}
+	}
+	return missing
+}
+
+// updateReadiness moves a pending or ready node to ready when its
+// dependencies are done and its required files exist, and back otherwise
+func (g *TaskGraph) updateReadiness(node *TaskNode) {
+	if node.Status != NodePending && node.Status != NodeReady {
+		return
+	}
+	if node.InDegree == 0 && len(g.MissingFiles(node.Task.ID)) == 0 {
+		node.Status = NodeReady
+	} else {
+		node.Status = NodePending
+	}
+}
+
+// GetCoDeployGroup returns the tasks that must run in the same batch as
+// the given task, including itself, or nil if it has no co-deploy constraints
+func (g *TaskGraph) GetCoDeployGroup(taskID string) []string {
+	return g.groups[taskID]
+}
+
+// GetReadyTasks returns tasks that are ready to be executed (no pending
+// dependencies), limited to the graph's shard if one is set
 func (g *TaskGraph) GetReadyTasks() []*task.Task {
 	var ready []*task.Task
 	for _, node := range g.nodes {
-		if node.Status == NodeReady {
+		if node.Status == NodeReady && g.shard.Contains(node.Task.ID) {
 			ready = append(ready, node.Task)
 		}
 	}
@@ -175,9 +283,7 @@ func (g *TaskGraph) MarkComplete(taskID string) error {
 	for _, depID := range node.Dependents {
 		depNode := g.nodes[depID]
 		depNode.InDegree--
-		if depNode.InDegree == 0 && depNode.Status == NodePending {
-			depNode.Status = NodeReady
-		}
+		g.updateReadiness(depNode)
 	}
 
 	return nil
@@ -296,29 +402,39 @@ func (g *TaskGraph) GetBatches() ([][]*task.Task, error) {
 	}
 
 	for remaining > 0 {
-		var readyTasks []*task.Task
-
 		// Find all tasks with in-degree 0 (dependencies satisfied)
+		var readyIDs []string
 		for id, deg := range inDegree {
 			if deg == 0 {
-				readyTasks = append(readyTasks, g.nodes[id].Task)
+				readyIDs = append(readyIDs, id)
 			}
 		}
 
-		if len(readyTasks) == 0 && remaining > 0 {
+		if len(readyIDs) == 0 && remaining > 0 {
 			return nil, fmt.Errorf("cycle detected or all tasks blocked")
 		}
 
-		// Split ready tasks into batches of maxTasksPerBatch
-		for len(readyTasks) > 0 {
-			batchSize := len(readyTasks)
-			if batchSize > maxTasksPerBatch {
-				batchSize = maxTasksPerBatch
+		units, err := g.coDeployUnits(readyIDs, inDegree)
+		if err != nil {
+			return nil, err
+		}
+
+		// Pack units into batches of maxTasksPerBatch; a co-deploy group
+		// larger than that gets a batch of its own
+		var packed [][]*task.Task
+		var current []*task.Task
+		for _, unit := range units {
+			if len(current) > 0 && len(current)+len(unit) > maxTasksPerBatch {
+				packed = append(packed, current)
+				current = nil
 			}
-			
-			batch := readyTasks[:batchSize]
-			readyTasks = readyTasks[batchSize:]
-			
+			current = append(current, unit...)
+		}
+		if len(current) > 0 {
+			packed = append(packed, current)
+		}
+
+		for _, batch := range packed {
 			batches = append(batches, batch)
 
 			// Remove this batch from consideration
@@ -339,6 +455,48 @@ func (g *TaskGraph) GetBatches() ([][]*task.Task, error) {
 	return batches, nil
 }
 
+// coDeployUnits groups ready tasks into units that must share a batch.
+// Co-deploy groups are held back until all their pending members are ready.
+func (g *TaskGraph) coDeployUnits(readyIDs []string, inDegree map[string]int) ([][]*task.Task, error) {
+	sort.Strings(readyIDs)
+
+	var units [][]*task.Task
+	seen := make(map[string]bool)
+	for _, id := range readyIDs {
+		if seen[id] {
+			continue
+		}
+		group := g.groups[id]
+		if len(group) == 0 {
+			seen[id] = true
+			units = append(units, []*task.Task{g.nodes[id].Task})
+			continue
+		}
+
+		var unit []*task.Task
+		ready := true
+		for _, member := range group {
+			seen[member] = true
+			switch inDegree[member] {
+			case -1:
+				// Completed or already scheduled
+			case 0:
+				unit = append(unit, g.nodes[member].Task)
+			default:
+				ready = false
+			}
+		}
+		if ready && len(unit) > 0 {
+			units = append(units, unit)
+		}
+	}
+
+	if len(units) == 0 {
+		return nil, fmt.Errorf("co-deploy group %v cannot be scheduled in one batch: its tasks depend on each other", g.groups[readyIDs[0]])
+	}
+	return units, nil
+}
+
 // GetNode returns a node by task ID
 func (g *TaskGraph) GetNode(taskID string) (*TaskNode, bool) {
 	node, exists := g.nodes[taskID]
@@ -0,0 +1,67 @@
+package scheduler
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// GraphStatePath returns the path of the persisted task graph state
+func GraphStatePath(workDir string) string {
+	return filepath.Join(workDir, ".hermes", "graph-state.json")
+}
+
+// SaveState writes the status of every node, keyed by task ID, to a JSON
+// file. Task data is not saved; it is read from the task files again.
+func (g *TaskGraph) SaveState(path string) error {
+	state := make(map[string]string, len(g.nodes))
+	for id, node := range g.nodes {
+		state[id] = node.Status.String()
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create graph state directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode graph state: %w", err)
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// LoadState marks the tasks a previous run completed as completed and
+// updates their dependents. Other statuses are not restored, so failed and
+// interrupted tasks run again. Unknown task IDs are ignored and a missing
+// file leaves the graph unchanged.
+func (g *TaskGraph) LoadState(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to read graph state: %w", err)
+	}
+
+	var state map[string]string
+	if err := json.Unmarshal(data, &state); err != nil {
+		return fmt.Errorf("failed to parse graph state: %w", err)
+	}
+
+	for id, status := range state {
+		node, exists := g.nodes[id]
+		if !exists || status != NodeCompleted.String() || node.Status == NodeCompleted {
+			continue
+		}
+		node.Status = NodeCompleted
+		for _, depID := range node.Dependents {
+			g.nodes[depID].InDegree--
+		}
+	}
+
+	for _, node := range g.nodes {
+		g.updateReadiness(node)
+	}
+	return nil
+}
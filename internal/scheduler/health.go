@@ -0,0 +1,149 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"time"
+
+	"hermes/internal/git"
+	"hermes/internal/task"
+)
+
+const (
+	// DefaultHealthRetryLimit is how often a task is re-queued while a health
+	// check fails before it is given up
+	DefaultHealthRetryLimit = 3
+	// defaultHealthRetryDelay is the wait before a re-queued task is submitted again
+	defaultHealthRetryDelay = 30 * time.Second
+	// networkCheckTimeout bounds the connection attempt of NetworkCheck
+	networkCheckTimeout = 5 * time.Second
+)
+
+// HealthCheck verifies that the environment can run a task. A non-nil error
+// holds the task back.
+type HealthCheck func(ctx context.Context) error
+
+// namedHealthCheck is a registered health check
+type namedHealthCheck struct {
+	name  string
+	check HealthCheck
+}
+
+// DiskSpaceCheck fails when less than minMB megabytes are free in the
+// current directory
+func DiskSpaceCheck(minMB int) HealthCheck {
+	return func(ctx context.Context) error {
+		free, err := freeDiskMB(".")
+		if err != nil {
+			return fmt.Errorf("failed to read free disk space: %w", err)
+		}
+		if free < uint64(minMB) {
+			return fmt.Errorf("only %d MB of disk space free, need %d MB", free, minMB)
+		}
+		return nil
+	}
+}
+
+// NetworkCheck fails when no TCP connection can be made to host, given as
+// host:port (port 443 if omitted)
+func NetworkCheck(host string) HealthCheck {
+	if _, _, err := net.SplitHostPort(host); err != nil {
+		host = net.JoinHostPort(host, "443")
+	}
+	return func(ctx context.Context) error {
+		dialer := net.Dialer{Timeout: networkCheckTimeout}
+		conn, err := dialer.DialContext(ctx, "tcp", host)
+		if err != nil {
+			return fmt.Errorf("cannot reach %s: %w", host, err)
+		}
+		return conn.Close()
+	}
+}
+
+// GitRepoCheck fails when dir is not a usable git repository
+func GitRepoCheck(dir string) HealthCheck {
+	return func(ctx context.Context) error {
+		if !git.New(dir, "").IsRepository() {
+			return fmt.Errorf("%s is not a git repository", dir)
+		}
+		return nil
+	}
+}
+
+// SetHealthCheck replaces the registered health checks with fn; nil removes them
+func (p *WorkerPool) SetHealthCheck(fn HealthCheck) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.healthChecks = nil
+	if fn != nil {
+		p.healthChecks = []namedHealthCheck{{name: "health", check: fn}}
+	}
+}
+
+// AddHealthCheck registers a check that every worker runs before each task.
+// While a check fails the task is re-queued after a delay, up to the health
+// retry limit, and then fails.
+func (p *WorkerPool) AddHealthCheck(name string, fn HealthCheck) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.healthChecks = append(p.healthChecks, namedHealthCheck{name: name, check: fn})
+}
+
+// checkHealth runs the health checks for a task about to start on a worker.
+// It returns true if the task was re-queued, and otherwise records a final
+// failure for executeTask.
+func (p *WorkerPool) checkHealth(workerID int, t *task.Task) bool {
+	p.mu.Lock()
+	checks := p.healthChecks
+	p.mu.Unlock()
+
+	var failure error
+	for _, hc := range checks {
+		err := hc.check(p.ctx)
+		if p.logger != nil {
+			if err != nil {
+				p.logger.Worker(workerID+1, "Health check %s failed before task %s: %v", hc.name, t.ID, err)
+			} else {
+				p.logger.Worker(workerID+1, "Health check %s passed", hc.name)
+			}
+		}
+		if err != nil {
+			failure = fmt.Errorf("health check %s failed: %w", hc.name, err)
+			break
+		}
+	}
+	if failure == nil {
+		return false
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.healthRetries[t.ID] >= p.healthRetryLimit {
+		p.unhealthy[t.ID] = failure
+		return false
+	}
+	p.healthRetries[t.ID]++
+	if p.logger != nil {
+		p.logger.Worker(workerID+1, "Re-queuing task %s in %v (attempt %d of %d)",
+			t.ID, p.healthRetryDelay, p.healthRetries[t.ID], p.healthRetryLimit)
+	}
+
+	p.requeues.Add(1)
+	go func() {
+		defer p.requeues.Done()
+		select {
+		case <-time.After(p.healthRetryDelay):
+			p.Submit(t)
+		case <-p.ctx.Done():
+		}
+	}()
+	return true
+}
+
+// healthFailure returns the health check error that made a task give up
+func (p *WorkerPool) healthFailure(taskID string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.unhealthy[taskID]
+}
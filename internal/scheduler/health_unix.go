@@ -0,0 +1,15 @@
+//go:build !windows
+
+package scheduler
+
+import "syscall"
+
+// freeDiskMB returns the megabytes available to unprivileged users on the
+// filesystem of path
+func freeDiskMB(path string) (uint64, error) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(path, &stat); err != nil {
+		return 0, err
+	}
+	return uint64(stat.Bavail) * uint64(stat.Bsize) / (1024 * 1024), nil
+}
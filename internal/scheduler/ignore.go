@@ -0,0 +1,156 @@
+package scheduler
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"hermes/internal/task"
+)
+
+// IgnoreFileName is the file listing paths excluded from conflict detection
+const IgnoreFileName = ".hermesignore"
+
+// IgnoreMatcher matches file paths against .hermesignore patterns. The
+// patterns use gitignore syntax: '#' comments, '!' negation, a trailing '/'
+// for directories, a leading or inner '/' to anchor at the project root and
+// '**' for any number of directories. The last matching pattern wins.
+type IgnoreMatcher struct {
+	patterns []ignorePattern
+}
+
+type ignorePattern struct {
+	segments []string
+	negate   bool
+	dirOnly  bool
+}
+
+// IgnoreFilePath returns the path of the ignore file in the project root
+func IgnoreFilePath(workDir string) string {
+	return filepath.Join(workDir, IgnoreFileName)
+}
+
+// LoadIgnoreFile reads an ignore file. A missing file returns nil, which
+// matches nothing.
+func LoadIgnoreFile(path string) (*IgnoreMatcher, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read %s: %w", IgnoreFileName, err)
+	}
+	return ParseIgnore(string(data)), nil
+}
+
+// ParseIgnore parses ignore patterns, one per line
+func ParseIgnore(content string) *IgnoreMatcher {
+	m := &IgnoreMatcher{}
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		var p ignorePattern
+		if strings.HasPrefix(line, "!") {
+			p.negate = true
+			line = line[1:]
+		}
+		if strings.HasSuffix(line, "/") {
+			p.dirOnly = true
+			line = strings.TrimRight(line, "/")
+		}
+		anchored := strings.Contains(line, "/")
+		line = strings.TrimPrefix(line, "/")
+		if line == "" {
+			continue
+		}
+
+		p.segments = strings.Split(line, "/")
+		if !anchored {
+			p.segments = append([]string{"**"}, p.segments...)
+		}
+		m.patterns = append(m.patterns, p)
+	}
+	return m
+}
+
+// Match reports whether a file path relative to the project root is ignored.
+// A path is also ignored when one of its parent directories matches.
+func (m *IgnoreMatcher) Match(file string) bool {
+	if m == nil {
+		return false
+	}
+
+	file = strings.TrimPrefix(path.Clean(filepath.ToSlash(file)), "/")
+	file = strings.TrimPrefix(file, "./")
+	parts := strings.Split(file, "/")
+
+	ignored := false
+	for _, p := range m.patterns {
+		for n := 1; n <= len(parts); n++ {
+			// Paths in task files name files, so only parents are directories
+			if p.dirOnly && n == len(parts) {
+				continue
+			}
+			if matchSegments(p.segments, parts[:n]) {
+				ignored = !p.negate
+				break
+			}
+		}
+	}
+	return ignored
+}
+
+// FilterTasks returns copies of the tasks without ignored files in their
+// files to touch and exclusive files, for use in conflict detection
+func (m *IgnoreMatcher) FilterTasks(tasks []*task.Task) []*task.Task {
+	if m == nil {
+		return tasks
+	}
+
+	filtered := make([]*task.Task, len(tasks))
+	for i, t := range tasks {
+		copied := *t
+		copied.FilesToTouch = m.filterFiles(t.FilesToTouch)
+		copied.ExclusiveFiles = m.filterFiles(t.ExclusiveFiles)
+		filtered[i] = &copied
+	}
+	return filtered
+}
+
+func (m *IgnoreMatcher) filterFiles(files []string) []string {
+	var kept []string
+	for _, file := range files {
+		if !m.Match(file) {
+			kept = append(kept, file)
+		}
+	}
+	return kept
+}
+
+// matchSegments matches path segments against pattern segments, where "**"
+// matches zero or more segments
+func matchSegments(pattern, parts []string) bool {
+	if len(pattern) == 0 {
+		return len(parts) == 0
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(parts); i++ {
+			if matchSegments(pattern[1:], parts[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(parts) == 0 {
+		return false
+	}
+	if ok, _ := path.Match(pattern[0], parts[0]); !ok {
+		return false
+	}
+	return matchSegments(pattern[1:], parts[1:])
+}
@@ -2,9 +2,19 @@ package scheduler
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"hermes/internal/ai"
+	"hermes/internal/ai/mock"
+	"hermes/internal/config"
+	"hermes/internal/isolation"
 	"hermes/internal/task"
 )
 
@@ -129,6 +139,73 @@ func TestResourceMonitor(t *testing.T) {
 	}
 }
 
+func TestResourceMonitorCostLimit(t *testing.T) {
+	monitor := NewResourceMonitor(0, 0, 0)
+	monitor.SetCostLimit(0.05)
+
+	monitor.RecordAPICall(0.02)
+	if monitor.CostLimitReached() {
+		t.Error("Cost limit should not be reached yet")
+	}
+
+	monitor.RecordAPICall(0.04)
+	if !monitor.CostLimitReached() {
+		t.Error("Cost limit should be reached")
+	}
+}
+
+func TestResourceStatsPersistence(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "hermes-resources-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	path := ResourceStatsPath(tmpDir)
+
+	// Missing file yields empty stats
+	stats, err := LoadResourceStats(path)
+	if err != nil {
+		t.Fatalf("LoadResourceStats failed: %v", err)
+	}
+	if stats.TotalAPICalls != 0 || stats.TotalCost != 0 {
+		t.Errorf("Expected empty stats, got %+v", stats)
+	}
+
+	if err := (ResourceStats{TotalAPICalls: 3, TotalCost: 0.5}).Save(path); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	stats, err = LoadResourceStats(path)
+	if err != nil {
+		t.Fatalf("LoadResourceStats failed: %v", err)
+	}
+	if stats.TotalAPICalls != 3 || stats.TotalCost != 0.5 {
+		t.Errorf("Expected 3 calls and $0.5, got %+v", stats)
+	}
+	if stats.LastUpdated.IsZero() {
+		t.Error("Expected LastUpdated to be set")
+	}
+
+	// Execute adds the session's usage to the persisted totals
+	sched := New(&config.ParallelConfig{MaxWorkers: 1}, nil, tmpDir, nil)
+	monitor := NewResourceMonitor(0, 0, 0)
+	monitor.RecordAPICall(0.25)
+	sched.SetResourceMonitor(monitor)
+
+	if _, err := sched.Execute(context.Background(), nil); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	stats, err = LoadResourceStats(path)
+	if err != nil {
+		t.Fatalf("LoadResourceStats failed: %v", err)
+	}
+	if stats.TotalAPICalls != 4 || stats.TotalCost != 0.75 {
+		t.Errorf("Expected 4 calls and $0.75, got %+v", stats)
+	}
+}
+
 func TestRateLimiter(t *testing.T) {
 	limiter := NewRateLimiter(60) // 60 per minute = 1 per second
 
@@ -192,12 +269,26 @@ func TestGroupByConflicts(t *testing.T) {
 	}
 }
 
-func TestRollback(t *testing.T) {
-	// Skip if not in a git repo
-	rollback := NewRollback(".")
-	if rollback.GetBaseBranch() == "" {
-		t.Skip("Not in a git repository")
+func setupRollbackRepo(t *testing.T) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	for _, args := range [][]string{
+		{"init"},
+		{"config", "user.email", "test@test.com"},
+		{"config", "user.name", "Test"},
+		{"commit", "--allow-empty", "-m", "initial"},
+	} {
+		if err := runGitCommand(dir, args...); err != nil {
+			t.Skipf("git not available: %v", err)
+		}
 	}
+	return dir
+}
+
+func TestRollback(t *testing.T) {
+	dir := setupRollbackRepo(t)
+	rollback := NewRollback(dir)
 
 	// Test snapshot saving
 	err := rollback.SaveSnapshot("TEST-001")
@@ -214,3 +305,642 @@ func TestRollback(t *testing.T) {
 		t.Error("Should be able to retrieve snapshot")
 	}
 }
+
+func TestRollbackPersistence(t *testing.T) {
+	dir := setupRollbackRepo(t)
+	rollback := NewRollback(dir)
+
+	if err := rollback.SaveSnapshot("INITIAL"); err != nil {
+		t.Fatalf("Failed to save snapshot: %v", err)
+	}
+	initial, _ := rollback.GetSnapshot("INITIAL")
+
+	if err := runGitCommand(dir, "commit", "--allow-empty", "-m", "task work"); err != nil {
+		t.Fatalf("Failed to commit: %v", err)
+	}
+	if err := rollback.SaveSnapshot("T002"); err != nil {
+		t.Fatalf("Failed to save snapshot: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, ".hermes", "snapshots.json")); err != nil {
+		t.Fatalf("Snapshots should be persisted: %v", err)
+	}
+
+	loaded, err := LoadRollback(dir)
+	if err != nil {
+		t.Fatalf("Failed to load snapshots: %v", err)
+	}
+
+	target, err := loaded.AllTarget()
+	if err != nil {
+		t.Fatalf("AllTarget failed: %v", err)
+	}
+	if target != initial {
+		t.Errorf("Expected earliest snapshot %s, got %s", initial, target)
+	}
+
+	if err := loaded.RollbackTask("T002"); err != nil {
+		t.Errorf("RollbackTask failed: %v", err)
+	}
+	if _, err := loaded.TaskTarget("T999"); err == nil {
+		t.Error("Expected error for unknown task")
+	}
+}
+
+func TestRollbackValidateCommit(t *testing.T) {
+	dir := setupRollbackRepo(t)
+	rollback := NewRollback(dir)
+
+	if err := rollback.ValidateCommit("0123456789abcdef0123456789abcdef01234567"); err == nil {
+		t.Error("Expected error for missing commit")
+	}
+
+	if _, err := LoadRollback(t.TempDir()); err == nil {
+		t.Error("Expected error when no snapshots file exists")
+	}
+}
+
+func TestSchedulerDryRun(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "hermes-scheduler-test-*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+	os.WriteFile(filepath.Join(tmpDir, "db.go"), []byte("package db\n"), 0644)
+
+	tasks := []*task.Task{
+		{ID: "T001", Name: "Setup Database", Status: task.StatusNotStarted, FilesToTouch: []string{"db.go"}},
+		{ID: "T002", Name: "Create Models", Status: task.StatusNotStarted, FilesToTouch: []string{"models.go"}, DependsOn: []string{"T001"}},
+		{ID: "T003", Name: "Create API", Status: task.StatusNotStarted, FilesToTouch: []string{"models.go"}, DependsOn: []string{"T001"}},
+		{ID: "T004", Name: "Create UI", Status: task.StatusNotStarted, FilesToTouch: []string{"../outside.go"}, DependsOn: []string{"T009"}},
+	}
+
+	// The provider is never called in dry run mode
+	sched := New(&config.ParallelConfig{MaxWorkers: 2}, nil, tmpDir, nil)
+	sched.DryRun = true
+
+	result, err := sched.Execute(context.Background(), tasks)
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	if len(result.Results) != 4 {
+		t.Fatalf("Expected 4 results, got %d", len(result.Results))
+	}
+	if result.Successful != 3 || result.Failed != 1 {
+		t.Errorf("Expected 3 valid and 1 invalid task, got %d/%d", result.Successful, result.Failed)
+	}
+
+	batches := make(map[string]int)
+	for _, r := range result.Results {
+		batches[r.TaskID] = r.Batch
+		if r.TaskID == "T004" && (r.Success || r.Error == nil) {
+			t.Error("Expected T004 to fail validation")
+		}
+	}
+	if batches["T001"] != 1 || batches["T002"] != 2 || batches["T003"] != 2 {
+		t.Errorf("Unexpected batch assignment: %v", batches)
+	}
+
+	if ids, ok := result.Conflicts["models.go"]; !ok || len(ids) != 2 {
+		t.Errorf("Expected models.go conflict between T002 and T003, got %v", result.Conflicts)
+	}
+}
+
+func TestWorkerPoolWithMockProvider(t *testing.T) {
+	provider := mock.NewMockProvider()
+	provider.SetResponse("T001", &ai.ExecuteResult{Output: "done", Success: true, Cost: 0.1, TokensIn: 1000, TokensOut: 200})
+	provider.SetError("T002", errors.New("provider crashed"))
+
+	monitor := NewResourceMonitor(0, 0, 0)
+	pool := NewWorkerPoolWithConfig(context.Background(), provider, t.TempDir(), WorkerPoolConfig{
+		Workers: 2,
+		Monitor: monitor,
+	})
+	pool.Start()
+	defer pool.Stop()
+
+	tasks := []*task.Task{
+		{ID: "T001", Name: "Setup Database"},
+		{ID: "T002", Name: "Create Models"},
+	}
+	if err := pool.SubmitBatch(tasks); err != nil {
+		t.Fatalf("SubmitBatch failed: %v", err)
+	}
+
+	results := make(map[string]*TaskResult)
+	for _, r := range pool.WaitForBatch(len(tasks)) {
+		results[r.TaskID] = r
+	}
+	if r := results["T001"]; r == nil || !r.Success || r.Output != "done" {
+		t.Errorf("Expected T001 to succeed with canned output, got %+v", r)
+	}
+	want := TaskMetrics{TokensIn: 1000, TokensOut: 200, CostUSD: 0.1, APICallCount: 1}
+	if r := results["T001"]; r != nil && r.Metrics != want {
+		t.Errorf("Expected T001 metrics %+v, got %+v", want, r.Metrics)
+	}
+	if r := results["T002"]; r == nil || r.Success || r.Error == nil {
+		t.Errorf("Expected T002 to fail, got %+v", r)
+	}
+
+	provider.AssertCalled(t, "T001", "T002")
+	if stats := monitor.GetStats(); stats.TotalAPICalls != 2 {
+		t.Errorf("Expected 2 API calls, got %d", stats.TotalAPICalls)
+	}
+}
+
+func TestSchedulerExecuteWithMockProvider(t *testing.T) {
+	provider := mock.NewMockProvider()
+	provider.SetResponse("T001", &ai.ExecuteResult{Output: "done", Success: true, Cost: 0.25, TokensIn: 100})
+	provider.SetResponse("T003", &ai.ExecuteResult{Output: "done", Success: true, Cost: 0.5, TokensIn: 300})
+	provider.SetError("T004", errors.New("provider crashed"))
+
+	tasks := []*task.Task{
+		{ID: "T001", Name: "Setup Database", Status: task.StatusNotStarted},
+		{ID: "T002", Name: "Create Models", Status: task.StatusNotStarted, DependsOn: []string{"T001"}},
+		{ID: "T003", Name: "Create API", Status: task.StatusNotStarted, DependsOn: []string{"T001"}},
+		{ID: "T004", Name: "Create UI", Status: task.StatusNotStarted, DependsOn: []string{"T002"}},
+	}
+
+	sched := New(&config.ParallelConfig{MaxWorkers: 2, FailureStrategy: "continue"}, provider, t.TempDir(), nil)
+	result, err := sched.Execute(context.Background(), tasks)
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	if len(result.Results) != 4 {
+		t.Fatalf("Expected 4 results, got %d", len(result.Results))
+	}
+	if result.Successful != 3 || result.Failed != 1 {
+		t.Errorf("Expected 3 successful and 1 failed task, got %d/%d", result.Successful, result.Failed)
+	}
+	if provider.CallCount() != 4 {
+		t.Errorf("Expected 4 provider calls, got %d", provider.CallCount())
+	}
+	provider.AssertCalled(t, "T001", "T002", "T003", "T004")
+
+	if result.TotalCostUSD != 0.75 || result.Metrics.TokensIn != 400 || result.Metrics.APICallCount != 4 {
+		t.Errorf("Expected aggregated metrics of $0.75, 400 tokens in and 4 calls, got $%v, %+v", result.TotalCostUSD, result.Metrics)
+	}
+}
+
+func TestWorkerPoolPauseResume(t *testing.T) {
+	provider := mock.NewMockProvider()
+	pool := NewWorkerPool(context.Background(), 1, provider, t.TempDir())
+	defer pool.Stop()
+
+	// Queued before the workers start, then drained by Pause
+	tasks := []*task.Task{{ID: "T001", Name: "One"}, {ID: "T002", Name: "Two"}}
+	if err := pool.SubmitBatch(tasks); err != nil {
+		t.Fatal(err)
+	}
+	pool.Pause()
+	if !pool.IsPaused() || pool.PausedCount() != 2 {
+		t.Fatalf("Expected 2 held tasks while paused, got %d", pool.PausedCount())
+	}
+
+	// Tasks submitted while paused are held too
+	pool.Submit(&task.Task{ID: "T003", Name: "Three"})
+	pool.Start()
+	time.Sleep(50 * time.Millisecond)
+	if provider.CallCount() != 0 || pool.PausedCount() != 3 {
+		t.Fatalf("Expected no execution while paused, got %d calls and %d held", provider.CallCount(), pool.PausedCount())
+	}
+
+	pool.Resume()
+	if pool.IsPaused() {
+		t.Error("Expected pool to be resumed")
+	}
+	if results := pool.WaitForBatch(3); len(results) != 3 {
+		t.Fatalf("Expected 3 results after resume, got %d", len(results))
+	}
+	provider.AssertCalled(t, "T001", "T002", "T003")
+
+	// Resuming twice or pausing an empty pool is harmless
+	pool.Resume()
+	pool.Pause()
+	pool.Pause()
+	if pool.PausedCount() != 0 {
+		t.Errorf("Expected no held tasks, got %d", pool.PausedCount())
+	}
+}
+
+func TestSchedulerPauseResume(t *testing.T) {
+	provider := mock.NewMockProvider()
+	tasks := []*task.Task{
+		{ID: "T001", Name: "Setup Database", Status: task.StatusNotStarted},
+		{ID: "T002", Name: "Create Models", Status: task.StatusNotStarted, DependsOn: []string{"T001"}},
+	}
+
+	sched := New(&config.ParallelConfig{MaxWorkers: 2}, provider, t.TempDir(), nil)
+	sched.Pause()
+
+	done := make(chan *ExecutionResult)
+	go func() {
+		result, _ := sched.Execute(context.Background(), tasks)
+		done <- result
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	if provider.CallCount() != 0 {
+		t.Fatalf("Expected no execution while paused, got %d calls", provider.CallCount())
+	}
+
+	sched.Resume()
+	select {
+	case result := <-done:
+		if result.Successful != 2 {
+			t.Errorf("Expected 2 successful tasks, got %d", result.Successful)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Execution did not finish after resume")
+	}
+}
+
+func TestRunInfoPersistence(t *testing.T) {
+	tmpDir := t.TempDir()
+	path := RunInfoPath(tmpDir)
+
+	info, err := LoadRunInfo(path)
+	if err != nil || info != nil {
+		t.Fatalf("Expected no run info without a file, got %+v, %v", info, err)
+	}
+
+	started := time.Now().Truncate(time.Second)
+	if err := (RunInfo{Strategy: StrategyParallel, Requested: StrategyAuto, Workers: 3, StartedAt: started}).Save(path); err != nil {
+		t.Fatal(err)
+	}
+
+	info, err = LoadRunInfo(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Strategy != StrategyParallel || info.Requested != StrategyAuto || info.Workers != 3 || !info.StartedAt.Equal(started) {
+		t.Errorf("Unexpected run info: %+v", info)
+	}
+}
+
+func TestSchedulerGraphStateRecovery(t *testing.T) {
+	tmpDir := t.TempDir()
+	newTasks := func() []*task.Task {
+		return []*task.Task{
+			{ID: "T001", Name: "Setup Database", Status: task.StatusNotStarted},
+			{ID: "T002", Name: "Create Models", Status: task.StatusNotStarted, DependsOn: []string{"T001"}},
+			{ID: "T003", Name: "Create UI", Status: task.StatusNotStarted, DependsOn: []string{"T002"}},
+		}
+	}
+	cfg := &config.ParallelConfig{MaxWorkers: 2, FailureStrategy: "continue"}
+
+	// The first run completes T001 and T002, then T003 fails
+	provider := mock.NewMockProvider()
+	provider.SetError("T003", errors.New("provider crashed"))
+	if _, err := New(cfg, provider, tmpDir, nil).Execute(context.Background(), newTasks()); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	graph, _ := NewTaskGraph(newTasks())
+	if err := graph.LoadState(GraphStatePath(tmpDir)); err != nil {
+		t.Fatal(err)
+	}
+	if graph.GetCompletedCount() != 2 || graph.nodes["T003"].Status != NodeReady {
+		t.Fatalf("Expected T001 and T002 restored as completed, got %d completed", graph.GetCompletedCount())
+	}
+
+	// The next run only executes T003 and removes the state
+	provider = mock.NewMockProvider()
+	result, err := New(cfg, provider, tmpDir, nil).Execute(context.Background(), newTasks())
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if len(result.Results) != 1 || provider.CallCount() != 1 {
+		t.Fatalf("Expected only T003 to run, got %d results and %d calls", len(result.Results), provider.CallCount())
+	}
+	provider.AssertCalled(t, "T003")
+	if _, err := os.Stat(GraphStatePath(tmpDir)); !os.IsNotExist(err) {
+		t.Error("Expected graph state to be removed after a successful run")
+	}
+
+	// Ignoring the state runs every task again
+	if err := graph.SaveState(GraphStatePath(tmpDir)); err != nil {
+		t.Fatal(err)
+	}
+	provider = mock.NewMockProvider()
+	sched := New(cfg, provider, tmpDir, nil)
+	sched.SetIgnoreState(true)
+	if _, err := sched.Execute(context.Background(), newTasks()); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if provider.CallCount() != 3 {
+		t.Errorf("Expected 3 provider calls with --ignore-state, got %d", provider.CallCount())
+	}
+}
+
+func TestSchedulerShardStateRecovery(t *testing.T) {
+	tmpDir := t.TempDir()
+	newTasks := func() []*task.Task {
+		return []*task.Task{
+			{ID: "T001", Name: "Setup Database", Status: task.StatusNotStarted},
+			{ID: "T002", Name: "Create Models", Status: task.StatusNotStarted, DependsOn: []string{"T001"}},
+		}
+	}
+	cfg := &config.ParallelConfig{MaxWorkers: 2, FailureStrategy: "continue"}
+	shard := &Shard{Index: 1, Count: 1}
+	run := func(provider *mock.MockProvider) {
+		sched := New(cfg, provider, tmpDir, nil)
+		sched.SetShard(shard, nil)
+		if _, err := sched.Execute(context.Background(), newTasks()); err != nil {
+			t.Fatalf("Execute failed: %v", err)
+		}
+	}
+
+	// The state of a sharded run goes to the shard's file only
+	provider := mock.NewMockProvider()
+	provider.SetError("T002", errors.New("provider crashed"))
+	run(provider)
+	if _, err := os.Stat(ShardStatePath(tmpDir, shard)); err != nil {
+		t.Fatalf("Expected shard state to be saved: %v", err)
+	}
+	if _, err := os.Stat(GraphStatePath(tmpDir)); !os.IsNotExist(err) {
+		t.Error("Expected no unsharded graph state")
+	}
+
+	// The next run of the shard resumes from it
+	provider = mock.NewMockProvider()
+	run(provider)
+	if provider.CallCount() != 1 {
+		t.Fatalf("Expected only T002 to run, got %d calls", provider.CallCount())
+	}
+	provider.AssertCalled(t, "T002")
+	if _, err := os.Stat(ShardStatePath(tmpDir, shard)); !os.IsNotExist(err) {
+		t.Error("Expected shard state to be removed after a successful run")
+	}
+}
+
+// gatedProvider holds the tasks whose prompt mentions gated until release
+// is closed
+type gatedProvider struct {
+	*mock.MockProvider
+	gated   string
+	release chan struct{}
+}
+
+func (p *gatedProvider) Execute(ctx context.Context, opts *ai.ExecuteOptions) (*ai.ExecuteResult, error) {
+	if strings.Contains(opts.Prompt, p.gated) {
+		select {
+		case <-p.release:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	return p.MockProvider.Execute(ctx, opts)
+}
+
+func TestSchedulerSavesStatePerResult(t *testing.T) {
+	tmpDir := t.TempDir()
+	provider := &gatedProvider{MockProvider: mock.NewMockProvider(), gated: "T002", release: make(chan struct{})}
+	tasks := []*task.Task{
+		{ID: "T001", Name: "Setup Database", Status: task.StatusNotStarted},
+		{ID: "T002", Name: "Create Models", Status: task.StatusNotStarted},
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := New(&config.ParallelConfig{MaxWorkers: 2}, provider, tmpDir, nil).Execute(context.Background(), tasks)
+		done <- err
+	}()
+
+	// T001 is saved as completed while T002 of the same batch still runs
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		data, _ := os.ReadFile(GraphStatePath(tmpDir))
+		var state map[string]string
+		if json.Unmarshal(data, &state) == nil && state["T001"] == NodeCompleted.String() {
+			if state["T002"] == NodeCompleted.String() {
+				t.Fatal("Expected T002 to still be running")
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("Expected T001 to be saved before the batch finished")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	close(provider.release)
+	if err := <-done; err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+}
+
+func TestSchedulerMergeConflictAI(t *testing.T) {
+	setup := func(t *testing.T) string {
+		dir := setupRollbackRepo(t)
+		file := filepath.Join(dir, "notes.txt")
+		os.WriteFile(file, []byte("one\ntwo\n"), 0644)
+		runGitCommand(dir, "add", "notes.txt")
+		runGitCommand(dir, "commit", "-qm", "base")
+		base, _ := runGitCommandOutput(dir, "rev-parse", "--abbrev-ref", "HEAD")
+		for branch, line := range map[string]string{"hermes/T001": "uno", "hermes/T002": "dos"} {
+			runGitCommand(dir, "checkout", "-qb", branch, base)
+			os.WriteFile(file, []byte("one\n"+line+"\n"), 0644)
+			runGitCommand(dir, "commit", "-qam", branch)
+		}
+		runGitCommand(dir, "checkout", "-q", base)
+		return dir
+	}
+	cfg := &config.ParallelConfig{ConflictResolution: "ai-assisted"}
+
+	// The AI merges the second branch with the first one
+	dir := setup(t)
+	provider := mock.NewMockProvider()
+	provider.SetDefault(&ai.ExecuteResult{
+		Success: true,
+		Output:  "```json\n{\"merged_code\": \"one\\nuno dos\\n\", \"explanation\": \"Kept both\", \"confidence\": 0.9}\n```",
+	})
+	sched := New(cfg, provider, dir, nil)
+	for _, id := range []string{"T001", "T002"} {
+		if err := sched.mergeBranch(isolation.NewWorkspace(id, dir)); err != nil {
+			t.Fatalf("Failed to merge %s: %v", id, err)
+		}
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "notes.txt")); string(data) != "one\nuno dos\n" {
+		t.Errorf("Expected the AI merge, got %q", data)
+	}
+	if status, _ := runGitCommandOutput(dir, "status", "--porcelain"); status != "" {
+		t.Errorf("Expected the merge to be committed, got %q", status)
+	}
+	sched.deleteMergedBranches()
+	if branches, _ := runGitCommandOutput(dir, "branch", "--list", "hermes/*"); branches != "" {
+		t.Errorf("Expected merged branches to be deleted, got %q", branches)
+	}
+
+	// Without a provider the second branch wins and no marker file is written
+	dir = setup(t)
+	sched = New(cfg, nil, dir, nil)
+	for _, id := range []string{"T001", "T002"} {
+		if err := sched.mergeBranch(isolation.NewWorkspace(id, dir)); err != nil {
+			t.Fatalf("Failed to merge %s: %v", id, err)
+		}
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "notes.txt")); string(data) != "one\ndos\n" {
+		t.Errorf("Expected the second branch to win, got %q", data)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "notes.txt.conflict")); !os.IsNotExist(err) {
+		t.Error("Expected no conflict file without a provider")
+	}
+}
+
+func TestSchedulerEventChannel(t *testing.T) {
+	provider := mock.NewMockProvider()
+	provider.SetError("T002", errors.New("provider crashed"))
+
+	tasks := []*task.Task{
+		{ID: "T001", Name: "Setup Database", Status: task.StatusNotStarted},
+		{ID: "T002", Name: "Create Models", Status: task.StatusNotStarted, DependsOn: []string{"T001"}},
+	}
+
+	events := make(chan WorkerEvent, 16)
+	sched := New(&config.ParallelConfig{MaxWorkers: 1, FailureStrategy: "continue"}, provider, t.TempDir(), nil)
+	sched.SetEventChannel(events)
+	if _, err := sched.Execute(context.Background(), tasks); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	close(events)
+
+	var got []WorkerEventType
+	var failed *TaskResult
+	for event := range events {
+		got = append(got, event.Type)
+		if event.Type == EventTaskDone && !event.Result.Success {
+			failed = event.Result
+		}
+		if event.Type == EventTaskStart && (event.WorkerID != 1 || event.Task == nil) {
+			t.Errorf("Unexpected task start event: %+v", event)
+		}
+	}
+
+	want := []WorkerEventType{
+		EventBatchStart, EventTaskStart, EventTaskDone,
+		EventBatchStart, EventTaskStart, EventTaskDone,
+		EventRunDone,
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Expected events %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("Expected events %v, got %v", want, got)
+		}
+	}
+	if failed == nil || failed.TaskID != "T002" {
+		t.Errorf("Expected a failed result for T002, got %+v", failed)
+	}
+}
+
+func TestEstimateCost(t *testing.T) {
+	ai.ProviderPricing["mock"] = ai.Pricing{InputPerMillion: 3, OutputPerMillion: 15}
+	defer delete(ai.ProviderPricing, "mock")
+
+	tasks := []*task.Task{
+		{ID: "T001", Status: task.StatusCompleted, EstimatedMinutes: 600},
+		{ID: "T002", Status: task.StatusNotStarted, EstimatedMinutes: 20, Dependencies: []string{"T001"}},
+		{ID: "T003", Status: task.StatusNotStarted}, // falls back to the default
+	}
+	cfg := &config.ParallelConfig{MaxWorkers: 2, TokensPerMinute: 10000}
+	sched := New(cfg, mock.NewMockProvider(), t.TempDir(), nil)
+
+	// The completed task is not counted: 30 minutes x 10000 = 300000 tokens,
+	// 240000 in at $3/M and 60000 out at $15/M
+	cost, err := sched.EstimateCost(tasks)
+	if err != nil {
+		t.Fatalf("EstimateCost failed: %v", err)
+	}
+	if cost < 1.619 || cost > 1.621 {
+		t.Errorf("expected $1.62, got $%.4f", cost)
+	}
+
+	plan, err := sched.GetExecutionPlan(tasks)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plan.EstimatedCost != cost {
+		t.Errorf("expected the plan to include the cost $%.4f, got $%.4f", cost, plan.EstimatedCost)
+	}
+
+	delete(ai.ProviderPricing, "mock")
+	if _, err := sched.EstimateCost(tasks); err == nil {
+		t.Error("expected an error for a provider without pricing")
+	}
+}
+
+func TestCapWorkers(t *testing.T) {
+	provider := mock.NewMockProvider()
+	if got := CapWorkers(provider, 8); got != 8 {
+		t.Errorf("expected no cap without a limit, got %d", got)
+	}
+
+	provider.Caps.MaxParallelRequests = 2
+	if got := CapWorkers(provider, 8); got != 2 {
+		t.Errorf("expected 2 workers, got %d", got)
+	}
+	if got := CapWorkers(provider, 1); got != 1 {
+		t.Errorf("expected 1 worker, got %d", got)
+	}
+
+	pool := NewWorkerPool(context.Background(), 4, provider, t.TempDir())
+	if pool.WorkerCount() != 2 {
+		t.Errorf("expected the pool to use 2 workers, got %d", pool.WorkerCount())
+	}
+}
+
+func TestWorkerPoolHealthCheck(t *testing.T) {
+	provider := mock.NewMockProvider()
+	provider.SetResponse("T001", &ai.ExecuteResult{Output: "done", Success: true})
+	provider.SetResponse("T002", &ai.ExecuteResult{Output: "done", Success: true})
+
+	// Fails twice, then passes: the task is re-queued and runs
+	var calls atomic.Int32
+	pool := NewWorkerPoolWithConfig(context.Background(), provider, t.TempDir(), WorkerPoolConfig{
+		Workers:          1,
+		HealthRetryLimit: 3,
+		HealthRetryDelay: time.Millisecond,
+	})
+	pool.AddHealthCheck("flaky", func(ctx context.Context) error {
+		if calls.Add(1) <= 2 {
+			return errors.New("not yet")
+		}
+		return nil
+	})
+	pool.Start()
+	pool.Submit(&task.Task{ID: "T001", Name: "Setup Database"})
+	results := pool.WaitForBatch(1)
+	pool.Stop()
+	if len(results) != 1 || !results[0].Success {
+		t.Fatalf("Expected T001 to succeed after re-queuing, got %+v", results)
+	}
+	if calls.Load() != 3 {
+		t.Errorf("Expected 3 health checks, got %d", calls.Load())
+	}
+
+	// Always fails: the task gives up after the retry limit
+	calls.Store(0)
+	pool = NewWorkerPoolWithConfig(context.Background(), provider, t.TempDir(), WorkerPoolConfig{
+		Workers:          1,
+		HealthRetryLimit: 1,
+		HealthRetryDelay: time.Millisecond,
+	})
+	pool.SetHealthCheck(func(ctx context.Context) error {
+		calls.Add(1)
+		return errors.New("disk full")
+	})
+	pool.Start()
+	pool.Submit(&task.Task{ID: "T002", Name: "Create Models"})
+	results = pool.WaitForBatch(1)
+	pool.Stop()
+	if len(results) != 1 || results[0].Success || results[0].Error == nil || !strings.Contains(results[0].Error.Error(), "disk full") {
+		t.Fatalf("Expected T002 to fail with the health check error, got %+v", results)
+	}
+	if calls.Load() != 2 {
+		t.Errorf("Expected 2 health checks, got %d", calls.Load())
+	}
+}
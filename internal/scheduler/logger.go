@@ -1,14 +1,20 @@
 package scheduler
 
 import (
+	"bufio"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 )
 
+// logTimestampLayout is the timestamp format of LogWriter lines
+const logTimestampLayout = "2006-01-02 15:04:05"
+
 // ParallelLogger provides thread-safe logging for parallel task execution
 type ParallelLogger struct {
 	basePath    string
@@ -50,7 +56,7 @@ func (w *LogWriter) Write(format string, args ...interface{}) {
 	w.mu.Lock()
 	defer w.mu.Unlock()
 
-	timestamp := time.Now().Format("2006-01-02 15:04:05")
+	timestamp := time.Now().Format(logTimestampLayout)
 	message := fmt.Sprintf(format, args...)
 	
 	if w.prefix != "" {
@@ -238,3 +244,132 @@ func (l *ParallelLogger) GetWorkerLogPath(workerID int) string {
 func (l *ParallelLogger) GetMergeLogPath() string {
 	return filepath.Join(l.GetLogDirectory(), "merge.log")
 }
+
+// AggregateLogPath returns the path of the aggregated worker log
+func AggregateLogPath(basePath string) string {
+	return filepath.Join(basePath, ".hermes", "logs", "parallel", "aggregate.log")
+}
+
+// GetAggregateLogPath returns the aggregated worker log file path
+func (l *ParallelLogger) GetAggregateLogPath() string {
+	return AggregateLogPath(l.basePath)
+}
+
+// AggregatedLog is the combined log of all workers, ordered by timestamp.
+// It reads as the lines joined by newlines.
+type AggregatedLog struct {
+	Lines  []string
+	reader *strings.Reader
+}
+
+// Read implements io.Reader
+func (a *AggregatedLog) Read(p []byte) (int, error) {
+	if a.reader == nil {
+		a.reader = strings.NewReader(a.String())
+	}
+	return a.reader.Read(p)
+}
+
+// String returns the log as text
+func (a *AggregatedLog) String() string {
+	if len(a.Lines) == 0 {
+		return ""
+	}
+	return strings.Join(a.Lines, "\n") + "\n"
+}
+
+// WriteToFile writes the log to path, creating its directory if needed
+func (a *AggregatedLog) WriteToFile(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create log directory: %w", err)
+	}
+	return os.WriteFile(path, []byte(a.String()), 0644)
+}
+
+// aggregateEntry is a timestamped worker log line with its continuation lines
+type aggregateEntry struct {
+	time   time.Time
+	worker int
+	lines  []string
+}
+
+// Aggregate merges the worker logs into one log ordered by timestamp, each
+// line prefixed with "[Worker N]". Lines without a timestamp, such as raw
+// output, stay after the line they followed. Lines with the same timestamp
+// keep their worker order.
+func (l *ParallelLogger) Aggregate() (*AggregatedLog, error) {
+	l.mu.RLock()
+	workers := make([]int, 0, len(l.workerLogs))
+	for id := range l.workerLogs {
+		workers = append(workers, id)
+	}
+	l.mu.RUnlock()
+	sort.Ints(workers)
+
+	var entries []aggregateEntry
+	for _, id := range workers {
+		workerEntries, err := readWorkerLog(l.GetWorkerLogPath(id), id)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, workerEntries...)
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].time.Before(entries[j].time)
+	})
+
+	aggregated := &AggregatedLog{}
+	for _, e := range entries {
+		prefix := fmt.Sprintf("[Worker %d] ", e.worker)
+		for _, line := range e.lines {
+			aggregated.Lines = append(aggregated.Lines, prefix+line)
+		}
+	}
+	return aggregated, nil
+}
+
+// readWorkerLog reads a worker log into timestamped entries
+func readWorkerLog(path string, worker int) ([]aggregateEntry, error) {
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read worker log: %w", err)
+	}
+	defer file.Close()
+
+	var entries []aggregateEntry
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if t, ok := parseLogTimestamp(line); ok {
+			entries = append(entries, aggregateEntry{time: t, worker: worker, lines: []string{line}})
+			continue
+		}
+		if len(entries) == 0 {
+			// Untimestamped lines before the first entry sort first
+			entries = append(entries, aggregateEntry{worker: worker})
+		}
+		last := &entries[len(entries)-1]
+		last.lines = append(last.lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read worker log: %w", err)
+	}
+	return entries, nil
+}
+
+// parseLogTimestamp parses the "[2006-01-02 15:04:05]" prefix of a log line
+func parseLogTimestamp(line string) (time.Time, bool) {
+	if len(line) < len(logTimestampLayout)+2 || line[0] != '[' || line[len(logTimestampLayout)+1] != ']' {
+		return time.Time{}, false
+	}
+	t, err := time.ParseInLocation(logTimestampLayout, line[1:len(logTimestampLayout)+1], time.Local)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
+}
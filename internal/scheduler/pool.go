@@ -3,10 +3,12 @@ package scheduler
 import (
 	"context"
 	"fmt"
+	"sort"
 	"sync"
 	"time"
 
 	"hermes/internal/ai"
+	"hermes/internal/git"
 	"hermes/internal/isolation"
 	"hermes/internal/task"
 )
@@ -23,12 +25,35 @@ type TaskResult struct {
 	StartTime time.Time
 	EndTime   time.Time
 	WorkerID  int
+	Batch     int // 1-based batch number (dry run)
+	Metrics   TaskMetrics
+}
+
+// TaskMetrics contains the AI usage of a task, summed over retries
+type TaskMetrics struct {
+	TokensIn     int
+	TokensOut    int
+	CostUSD      float64
+	APICallCount int
+}
+
+// Tokens returns the total number of input and output tokens
+func (m TaskMetrics) Tokens() int {
+	return m.TokensIn + m.TokensOut
+}
+
+// Add adds the usage of other to m
+func (m *TaskMetrics) Add(other TaskMetrics) {
+	m.TokensIn += other.TokensIn
+	m.TokensOut += other.TokensOut
+	m.CostUSD += other.CostUSD
+	m.APICallCount += other.APICallCount
 }
 
 // WorkerPool manages multiple AI agent instances for parallel execution
 type WorkerPool struct {
 	workers        int
-	taskQueue      chan *task.Task
+	queue          *taskQueue
 	results        chan *TaskResult
 	ctx            context.Context
 	cancel         context.CancelFunc
@@ -37,45 +62,147 @@ type WorkerPool struct {
 	workDir        string
 	mu             sync.Mutex
 	running        int
-	useIsolation   bool
-	workspaces     map[string]*isolation.Workspace
+	isolationMode  string
+	dockerImage    string
+	workspaces     map[string]isolation.Workspace
 	logger         *ParallelLogger
 	streamOutput   bool
+	branchManager  *git.ParallelBranchManager
+	monitor        *ResourceMonitor
+	hooks          ExecutionHooks
+	events         chan<- WorkerEvent
+	retry          ai.RetryConfig
+	promptContext  string
+	extraArgs      map[string]string
+	systemPrompt   string
+	outputFilter   *ai.OutputFilter
+	paused         bool
+	pausedTasks    []*task.Task // Queued tasks held back while paused
+
+	healthChecks     []namedHealthCheck
+	healthRetryLimit int
+	healthRetryDelay time.Duration
+	healthRetries    map[string]int   // Task ID -> times re-queued by a failed health check
+	unhealthy        map[string]error // Task ID -> health check failure it gave up on
+	requeues         sync.WaitGroup   // Re-queued tasks waiting for their delay
 }
 
 // WorkerPoolConfig contains configuration for the worker pool
 type WorkerPoolConfig struct {
 	Workers      int
-	UseIsolation bool
 	Logger       *ParallelLogger
 	StreamOutput bool
+
+	// Isolation selects the workspace kind (one of isolation.Modes); empty
+	// means isolation.ModeNone, where all tasks share the work directory
+	Isolation   string
+	DockerImage string
+
+	// BranchManager, when set, commits each task's branch separately (auto-commit)
+	BranchManager *git.ParallelBranchManager
+	Monitor       *ResourceMonitor
+	Hooks         ExecutionHooks
+
+	// Retry repeats executions failing with transient errors; a zero value
+	// makes a single attempt
+	Retry ai.RetryConfig
+
+	// Events, when set, receives a WorkerEvent when a task starts and ends
+	Events chan<- WorkerEvent
+
+	// PromptContext is prepended to every task prompt (e.g. style guides)
+	PromptContext string
+
+	// ExtraArgs are additional CLI flags passed to the provider
+	ExtraArgs map[string]string
+
+	// SystemPrompt is the system prompt of every task, for providers that
+	// support one
+	SystemPrompt string
+
+	// OutputFilter is applied to each task's output before it is logged
+	OutputFilter *ai.OutputFilter
+
+	// HealthChecks run before each task, see AddHealthCheck
+	HealthChecks map[string]HealthCheck
+	// HealthRetryLimit is how often a task is re-queued while a health check
+	// fails (0 = DefaultHealthRetryLimit, negative = never)
+	HealthRetryLimit int
+	// HealthRetryDelay is the wait before a re-queued task runs again (0 = 30s)
+	HealthRetryDelay time.Duration
 }
 
 // NewWorkerPool creates a new worker pool
 func NewWorkerPool(ctx context.Context, workers int, provider ai.Provider, workDir string) *WorkerPool {
 	return NewWorkerPoolWithConfig(ctx, provider, workDir, WorkerPoolConfig{
-		Workers:      workers,
-		UseIsolation: false,
-		Logger:       nil,
+		Workers: workers,
+		Logger:  nil,
 	})
 }
 
-// NewWorkerPoolWithConfig creates a new worker pool with configuration
+// NewWorkerPoolWithConfig creates a new worker pool with configuration. The
+// workers are capped at the provider's parallel request limit.
 func NewWorkerPoolWithConfig(ctx context.Context, provider ai.Provider, workDir string, cfg WorkerPoolConfig) *WorkerPool {
 	ctx, cancel := context.WithCancel(ctx)
-	return &WorkerPool{
-		workers:      cfg.Workers,
-		taskQueue:    make(chan *task.Task, cfg.Workers*2),
-		results:      make(chan *TaskResult, cfg.Workers*2),
-		ctx:          ctx,
-		cancel:       cancel,
-		provider:     provider,
-		workDir:      workDir,
-		useIsolation: cfg.UseIsolation,
-		workspaces:   make(map[string]*isolation.Workspace),
-		logger:       cfg.Logger,
-		streamOutput: cfg.StreamOutput,
+	workers := CapWorkers(provider, cfg.Workers)
+	pool := &WorkerPool{
+		workers:       workers,
+		queue:         newTaskQueue(ctx),
+		results:       make(chan *TaskResult, workers*2),
+		ctx:           ctx,
+		cancel:        cancel,
+		provider:      provider,
+		workDir:       workDir,
+		isolationMode: cfg.Isolation,
+		dockerImage:   cfg.DockerImage,
+		workspaces:    make(map[string]isolation.Workspace),
+		logger:        cfg.Logger,
+		streamOutput:  cfg.StreamOutput,
+		branchManager: cfg.BranchManager,
+		monitor:       cfg.Monitor,
+		hooks:         cfg.Hooks,
+		events:        cfg.Events,
+		retry:         cfg.Retry,
+		promptContext: cfg.PromptContext,
+		extraArgs:     cfg.ExtraArgs,
+		systemPrompt:  cfg.SystemPrompt,
+		outputFilter:  cfg.OutputFilter,
+
+		healthRetryLimit: cfg.HealthRetryLimit,
+		healthRetryDelay: cfg.HealthRetryDelay,
+		healthRetries:    make(map[string]int),
+		unhealthy:        make(map[string]error),
+	}
+	if pool.healthRetryLimit == 0 {
+		pool.healthRetryLimit = DefaultHealthRetryLimit
+	} else if pool.healthRetryLimit < 0 {
+		pool.healthRetryLimit = 0
+	}
+	if pool.healthRetryDelay <= 0 {
+		pool.healthRetryDelay = defaultHealthRetryDelay
+	}
+
+	// Registered in name order so the checks run the same way every time
+	names := make([]string, 0, len(cfg.HealthChecks))
+	for name := range cfg.HealthChecks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		pool.AddHealthCheck(name, cfg.HealthChecks[name])
+	}
+	return pool
+}
+
+// CapWorkers returns workers limited to the provider's MaxParallelRequests
+func CapWorkers(provider ai.Provider, workers int) int {
+	if provider == nil {
+		return workers
+	}
+	if limit := provider.Capabilities().MaxParallelRequests; limit > 0 && workers > limit {
+		return limit
 	}
+	return workers
 }
 
 // Start starts the worker pool
@@ -91,22 +218,21 @@ func (p *WorkerPool) worker(workerID int) {
 	defer p.wg.Done()
 
 	for {
+		t, ok := p.queue.pop()
+		if !ok {
+			return
+		}
+		if p.checkHealth(workerID, t) {
+			continue
+		}
+		p.incrementRunning()
+		result := p.executeTask(workerID, t)
+		p.decrementRunning()
+
 		select {
+		case p.results <- result:
 		case <-p.ctx.Done():
 			return
-		case t, ok := <-p.taskQueue:
-			if !ok {
-				return
-			}
-			p.incrementRunning()
-			result := p.executeTask(workerID, t)
-			p.decrementRunning()
-			
-			select {
-			case p.results <- result:
-			case <-p.ctx.Done():
-				return
-			}
 		}
 	}
 }
@@ -145,14 +271,56 @@ func (p *WorkerPool) executeTask(workerID int, t *task.Task) *TaskResult {
 	if p.logger != nil {
 		p.logger.TaskStart(workerID+1, t.ID, t.Name)
 	}
+	if p.hooks.OnTaskStart != nil {
+		p.hooks.OnTaskStart(workerID+1, t)
+	}
+	if p.hooks.OnTaskDone != nil {
+		defer func() { p.hooks.OnTaskDone(result) }()
+	}
+	sendEvent(p.ctx, p.events, WorkerEvent{Type: EventTaskStart, WorkerID: workerID + 1, Task: t})
+	defer func() {
+		sendEvent(p.ctx, p.events, WorkerEvent{Type: EventTaskDone, WorkerID: workerID + 1, Result: result})
+	}()
+
+	// Refuse new work once the cost limit is spent
+	if p.monitor != nil && p.monitor.CostLimitReached() {
+		result.EndTime = time.Now()
+		result.Duration = result.EndTime.Sub(startTime)
+		result.Error = fmt.Errorf("cost limit reached")
+		if p.logger != nil {
+			p.logger.TaskFailed(workerID+1, t.ID, result.Error)
+		}
+		return result
+	}
+
+	// Give up on a task whose environment stayed unhealthy
+	if err := p.healthFailure(t.ID); err != nil {
+		result.EndTime = time.Now()
+		result.Duration = result.EndTime.Sub(startTime)
+		result.Error = err
+		if p.logger != nil {
+			p.logger.TaskFailed(workerID+1, t.ID, result.Error)
+		}
+		return result
+	}
 
 	// Setup isolated workspace if enabled
 	workDir := p.workDir
-	var workspace *isolation.Workspace
-	if p.useIsolation {
-		workspace = isolation.NewWorkspace(t.ID, p.workDir)
-		if err := workspace.Setup(); err != nil {
+	var cliPath string
+	workspace, err := p.newWorkspace(t.ID)
+	if err != nil || workspace != nil {
+		if err == nil {
+			err = workspace.Setup()
+		}
+		if docker, ok := workspace.(*isolation.DockerWorkspace); ok && err == nil {
+			// Run the AI CLI inside the container
+			if cliPath, err = docker.CommandWrapper(p.provider.Name()); err != nil {
+				docker.Cleanup()
+			}
+		}
+		if err != nil {
 			// Fall back to shared workspace
+			workspace = nil
 			if p.logger != nil {
 				p.logger.Worker(workerID+1, "Failed to create isolated workspace, using shared: %v", err)
 			}
@@ -167,16 +335,47 @@ func (p *WorkerPool) executeTask(workerID int, t *task.Task) *TaskResult {
 
 	// Create task executor with appropriate work directory
 	executor := ai.NewTaskExecutor(p.provider, workDir)
+	if cliPath != "" {
+		executor.SetCLIPath(cliPath)
+	}
+	executor.SetExtraArgs(p.extraArgs)
+	executor.SetSystemPrompt(p.systemPrompt)
+	executor.SetOutputFilter(p.outputFilter)
 
 	// Build prompt content from task
 	promptContent := p.buildPromptContent(t)
+	if tokens, over := p.provider.Capabilities().ExceedsContext(promptContent); over && p.logger != nil {
+		p.logger.Worker(workerID+1, "Prompt of %s is about %d tokens, more than the %d token context of %s",
+			t.ID, tokens, p.provider.Capabilities().MaxContextTokens, p.provider.Name())
+	}
 
 	// Execute the task
-	execResult, err := executor.ExecuteTask(p.ctx, t, promptContent, p.streamOutput)
+	execResult, err := executor.ExecuteTaskWithRetry(p.ctx, t, promptContent, p.streamOutput, p.retry)
 
 	result.EndTime = time.Now()
 	result.Duration = result.EndTime.Sub(startTime)
 
+	usage := executor.Usage()
+	result.Metrics = TaskMetrics{
+		TokensIn:     usage.TokensIn,
+		TokensOut:    usage.TokensOut,
+		CostUSD:      usage.Cost,
+		APICallCount: executor.Calls(),
+	}
+
+	if p.monitor != nil {
+		p.monitor.RecordAPICall(result.Metrics.CostUSD)
+	}
+	if p.logger != nil {
+		output := ""
+		if execResult != nil {
+			output = execResult.Output
+		}
+		if err := p.logger.WriteOutput(t.ID, output+formatTaskMetrics(result)); err != nil {
+			p.logger.Worker(workerID+1, "Failed to write task output: %v", err)
+		}
+	}
+
 	if err != nil {
 		result.Success = false
 		result.Error = err
@@ -194,8 +393,18 @@ func (p *WorkerPool) executeTask(workerID int, t *task.Task) *TaskResult {
 		p.logger.TaskComplete(workerID+1, t.ID, result.Duration)
 	}
 
-	// Commit changes in isolated workspace
-	if workspace != nil && workspace.HasUncommittedChanges() {
+	// Commit changes in isolated workspace. Docker workspaces have no task
+	// branch; their files are copied back after the batch.
+	if _, inContainer := workspace.(*isolation.DockerWorkspace); inContainer {
+		return result
+	}
+	if workspace != nil && p.branchManager != nil {
+		if err := p.branchManager.CommitTaskBranch(t.ID, t.Name, workspace.GetWorkPath()); err != nil {
+			if p.logger != nil {
+				p.logger.Worker(workerID+1, "Failed to commit changes: %v", err)
+			}
+		}
+	} else if workspace != nil && workspace.HasUncommittedChanges() {
 		commitMsg := fmt.Sprintf("Complete task %s: %s", t.ID, t.Name)
 		if err := workspace.CommitChanges(commitMsg); err != nil {
 			if p.logger != nil {
@@ -207,6 +416,22 @@ func (p *WorkerPool) executeTask(workerID int, t *task.Task) *TaskResult {
 	return result
 }
 
+// formatTaskMetrics renders the metrics footer of a task output log
+func formatTaskMetrics(r *TaskResult) string {
+	return fmt.Sprintf("\n\n---METRICS---\nduration: %v\ntokensIn: %d\ntokensOut: %d\ncostUSD: %.4f\napiCalls: %d\n",
+		r.Duration.Round(time.Millisecond), r.Metrics.TokensIn, r.Metrics.TokensOut, r.Metrics.CostUSD, r.Metrics.APICallCount)
+}
+
+// newWorkspace creates the workspace for a task according to the isolation
+// mode; it is nil when tasks share the work directory
+func (p *WorkerPool) newWorkspace(taskID string) (isolation.Workspace, error) {
+	workspace, err := isolation.NewWorkspaceByMode(p.isolationMode, taskID, p.workDir)
+	if docker, ok := workspace.(*isolation.DockerWorkspace); ok && p.dockerImage != "" {
+		docker.Image = p.dockerImage
+	}
+	return workspace, err
+}
+
 // buildPromptContent builds the prompt content for a task
 func (p *WorkerPool) buildPromptContent(t *task.Task) string {
 	content := fmt.Sprintf(`# Current Task
@@ -238,17 +463,30 @@ func (p *WorkerPool) buildPromptContent(t *task.Task) string {
 		t.SuccessCriteria,
 	)
 
+	if p.promptContext != "" {
+		content = p.promptContext + "\n\n" + content
+	}
 	return content
 }
 
-// Submit submits a task for execution
+// SetDispatchPolicy sets the order in which idle workers take queued
+// tasks; the default is PriorityPolicy
+func (p *WorkerPool) SetDispatchPolicy(policy DispatchPolicy) {
+	p.queue.setPolicy(policy)
+}
+
+// Submit submits a task for execution. While paused the task is held
+// back until Resume.
 func (p *WorkerPool) Submit(t *task.Task) error {
-	select {
-	case p.taskQueue <- t:
+	p.mu.Lock()
+	if p.paused {
+		p.pausedTasks = append(p.pausedTasks, t)
+		p.mu.Unlock()
 		return nil
-	case <-p.ctx.Done():
-		return p.ctx.Err()
 	}
+	p.mu.Unlock()
+
+	return p.queue.push(t)
 }
 
 // SubmitBatch submits multiple tasks for execution
@@ -261,6 +499,51 @@ func (p *WorkerPool) SubmitBatch(tasks []*task.Task) error {
 	return nil
 }
 
+// Pause stops workers from starting new tasks. Running tasks finish; queued
+// ones are held back until Resume.
+func (p *WorkerPool) Pause() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.paused {
+		return
+	}
+	p.paused = true
+	p.pausedTasks = append(p.pausedTasks, p.queue.drain()...)
+}
+
+// Resume queues the tasks held back by Pause again
+func (p *WorkerPool) Resume() {
+	p.mu.Lock()
+	if !p.paused {
+		p.mu.Unlock()
+		return
+	}
+	p.paused = false
+	held := p.pausedTasks
+	p.pausedTasks = nil
+	p.mu.Unlock()
+
+	for _, t := range held {
+		if err := p.Submit(t); err != nil {
+			return
+		}
+	}
+}
+
+// IsPaused returns true between Pause and Resume
+func (p *WorkerPool) IsPaused() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.paused
+}
+
+// PausedCount returns the number of tasks held back by Pause
+func (p *WorkerPool) PausedCount() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.pausedTasks)
+}
+
 // Results returns the results channel
 func (p *WorkerPool) Results() <-chan *TaskResult {
 	return p.results
@@ -268,7 +551,8 @@ func (p *WorkerPool) Results() <-chan *TaskResult {
 
 // Wait waits for all submitted tasks to complete
 func (p *WorkerPool) Wait() {
-	close(p.taskQueue)
+	p.requeues.Wait()
+	p.queue.close()
 	p.wg.Wait()
 	close(p.results)
 }
@@ -307,14 +591,14 @@ func (p *WorkerPool) IsRunning() bool {
 }
 
 // GetWorkspaces returns all workspaces created by the pool
-func (p *WorkerPool) GetWorkspaces() map[string]*isolation.Workspace {
+func (p *WorkerPool) GetWorkspaces() map[string]isolation.Workspace {
 	p.mu.Lock()
 	defer p.mu.Unlock()
 	return p.workspaces
 }
 
 // GetWorkspace returns the workspace for a specific task
-func (p *WorkerPool) GetWorkspace(taskID string) *isolation.Workspace {
+func (p *WorkerPool) GetWorkspace(taskID string) isolation.Workspace {
 	p.mu.Lock()
 	defer p.mu.Unlock()
 	return p.workspaces[taskID]
@@ -1,6 +1,7 @@
 package scheduler
 
 import (
+	"fmt"
 	"sort"
 
 	"hermes/internal/task"
@@ -166,21 +167,58 @@ func GroupByConflicts(tasks []*task.Task) [][]*task.Task {
 	return groups
 }
 
+// defaultTaskMinutes is assumed for tasks without a parseable effort estimate
+const defaultTaskMinutes = 10
+
+// EstimateParallelMinutes estimates wall-clock minutes to run the given batches.
+// Each batch takes the sum of its task estimates divided by the workers it can use.
+func EstimateParallelMinutes(batches [][]*task.Task, workers int) int {
+	if workers < 1 {
+		workers = 1
+	}
+
+	total := 0
+	for _, batch := range batches {
+		if len(batch) == 0 {
+			continue
+		}
+
+		sum := 0
+		for _, t := range batch {
+			if t.EstimatedMinutes > 0 {
+				sum += t.EstimatedMinutes
+			} else {
+				sum += defaultTaskMinutes
+			}
+		}
+
+		batchWorkers := workers
+		if batchWorkers > len(batch) {
+			batchWorkers = len(batch)
+		}
+		total += (sum + batchWorkers - 1) / batchWorkers
+	}
+
+	return total
+}
+
 // EstimateParallelTime estimates execution time with parallel execution
 func EstimateParallelTime(tasks []*task.Task, workers int) string {
 	if len(tasks) == 0 {
 		return "0s"
 	}
 
-	// Simple estimation: assume each task takes 10 minutes on average
-	avgTaskTime := 10 // minutes
-	batches := (len(tasks) + workers - 1) / workers
-	totalMinutes := batches * avgTaskTime
+	batches := [][]*task.Task{tasks}
+	if graph, err := NewTaskGraph(tasks); err == nil {
+		if b, err := graph.GetBatches(); err == nil {
+			batches = b
+		}
+	}
+
+	totalMinutes := EstimateParallelMinutes(batches, workers)
 
 	if totalMinutes < 60 {
-		return string(rune(totalMinutes)) + "m"
+		return fmt.Sprintf("%dm", totalMinutes)
 	}
-	hours := totalMinutes / 60
-	minutes := totalMinutes % 60
-	return string(rune(hours)) + "h " + string(rune(minutes)) + "m"
+	return fmt.Sprintf("%dh %dm", totalMinutes/60, totalMinutes%60)
 }
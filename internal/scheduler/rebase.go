@@ -0,0 +1,104 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"hermes/internal/git"
+	"hermes/internal/isolation"
+	"hermes/internal/merger"
+	"hermes/internal/task"
+)
+
+// SetRebaseBeforeMerge rebases each worktree task branch onto the base
+// branch before it is merged, so tasks that ran long merge onto the current
+// base. Conflicting rebases are recorded in RebaseConflicts and resolved by
+// the AI instead.
+func (s *Scheduler) SetRebaseBeforeMerge(enabled bool) {
+	s.rebase = enabled
+	if enabled && s.conflicts == nil {
+		s.conflicts = merger.NewConflictDetector()
+	}
+}
+
+// RebaseConflicts returns the conflicts found while rebasing task branches
+func (s *Scheduler) RebaseConflicts() []merger.Conflict {
+	if s.conflicts == nil {
+		return nil
+	}
+	return s.conflicts.GetConflicts()
+}
+
+// rebaseOntoBase rebases the branch of a worktree workspace onto the base
+// branch. If the rebase conflicts, the conflict is marked and the base
+// branch is merged into the task branch with the AI resolving each
+// conflicting file. Failures are logged; the merge then runs as usual.
+func (s *Scheduler) rebaseOntoBase(ctx context.Context, workspace isolation.Workspace, t *task.Task) {
+	taskID := workspace.GetTaskID()
+	manager := git.NewParallelBranchManager(git.New(s.workDir, ""))
+	ok, reason := manager.CanRebase(taskID)
+	if !ok {
+		s.logError("Not rebasing task %s: %s", taskID, reason)
+		return
+	}
+
+	err := manager.RebaseOntoBase(taskID)
+	var conflict *git.RebaseConflictError
+	switch {
+	case err == nil:
+		s.logInfo("Rebased task %s onto %s (was %s)", taskID, manager.GetBaseBranch(), reason)
+		return
+	case !errors.As(err, &conflict):
+		s.logError("Failed to rebase task %s: %v", taskID, err)
+		return
+	}
+
+	for _, file := range conflict.Files {
+		s.conflicts.MarkConflict(merger.Conflict{
+			File:        file,
+			Tasks:       []string{taskID},
+			Type:        merger.ConflictSameFile,
+			Severity:    merger.SeverityHigh,
+			Description: fmt.Sprintf("Task %s and %s changed the same lines", taskID, conflict.Base),
+		})
+	}
+	s.logError("%v, resolving with AI", err)
+
+	aiMerger := merger.NewAIMerger(s.provider, workspace.GetWorkPath())
+	resolve := func(file, original, ours, theirs string) (string, error) {
+		result := aiMerger.ResolveConflict(ctx, merger.Conflict{File: file, Tasks: []string{taskID}}, merger.MergeContext{
+			File:         file,
+			OriginalCode: original,
+			Task1ID:      taskID,
+			Task1Changes: ours,
+			Task1Intent:  taskIntent(t),
+			Task2ID:      conflict.Base,
+			Task2Changes: theirs,
+			Task2Intent:  fmt.Sprintf("Changes merged into %s while task %s ran", conflict.Base, taskID),
+		})
+		if result.Error != nil {
+			return "", result.Error
+		}
+		if !result.Success {
+			return "", fmt.Errorf("AI returned no merged code")
+		}
+		return result.MergedCode, nil
+	}
+	if err := manager.MergeBaseIntoTask(taskID, resolve); err != nil {
+		s.logError("AI resolution of task %s failed: %v", taskID, err)
+		return
+	}
+	s.logInfo("Merged %s into task %s, conflicts resolved by AI", conflict.Base, taskID)
+}
+
+// taskIntent describes what a task changes for the AI merge prompt
+func taskIntent(t *task.Task) string {
+	if t == nil {
+		return ""
+	}
+	if t.Description == "" {
+		return t.Name
+	}
+	return t.Name + ": " + t.Description
+}
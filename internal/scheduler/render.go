@@ -0,0 +1,141 @@
+package scheduler
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"hermes/internal/task"
+)
+
+// Node fill colours used when rendering the graph
+const (
+	colorCompleted  = "#b7e1a1"
+	colorInProgress = "#ffe599"
+	colorFailed     = "#f4a6a6"
+	colorNotStarted = "#ffffff"
+)
+
+// nodeColor returns the fill colour for a node based on its task status
+func nodeColor(node *TaskNode) string {
+	if node.Status == NodeFailed {
+		return colorFailed
+	}
+	switch node.Task.Status {
+	case task.StatusCompleted:
+		return colorCompleted
+	case task.StatusInProgress:
+		return colorInProgress
+	case task.StatusBlocked:
+		return colorFailed
+	default:
+		return colorNotStarted
+	}
+}
+
+// nodeClass returns the Mermaid class name matching nodeColor
+func nodeClass(node *TaskNode) string {
+	switch nodeColor(node) {
+	case colorCompleted:
+		return "completed"
+	case colorInProgress:
+		return "inProgress"
+	case colorFailed:
+		return "failed"
+	default:
+		return "notStarted"
+	}
+}
+
+// sortedIDs returns the task IDs of the graph in sorted order
+func (g *TaskGraph) sortedIDs() []string {
+	ids := make([]string, 0, len(g.nodes))
+	for id := range g.nodes {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// WriteDOT writes the graph in Graphviz DOT format. Edges point from a
+// dependency to the task that depends on it.
+func (g *TaskGraph) WriteDOT(w io.Writer) error {
+	var sb strings.Builder
+
+	sb.WriteString("digraph tasks {\n")
+	sb.WriteString("  rankdir=LR;\n")
+	sb.WriteString("  node [shape=box, style=\"rounded,filled\", fontname=\"Helvetica\"];\n\n")
+
+	ids := g.sortedIDs()
+	for _, id := range ids {
+		node := g.nodes[id]
+		label := fmt.Sprintf("%s: %s", id, node.Task.Name)
+		sb.WriteString(fmt.Sprintf("  %s [label=%s, fillcolor=%s];\n",
+			dotQuote(id), dotQuote(label), dotQuote(nodeColor(node))))
+	}
+
+	sb.WriteString("\n")
+	for _, id := range ids {
+		for _, dep := range g.edges[id] {
+			sb.WriteString(fmt.Sprintf("  %s -> %s;\n", dotQuote(dep), dotQuote(id)))
+		}
+	}
+	sb.WriteString("}\n")
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// WriteMermaid writes the graph as a Mermaid flowchart
+func (g *TaskGraph) WriteMermaid(w io.Writer) error {
+	var sb strings.Builder
+
+	sb.WriteString("flowchart LR\n")
+
+	ids := g.sortedIDs()
+	for _, id := range ids {
+		label := fmt.Sprintf("%s: %s", id, g.nodes[id].Task.Name)
+		sb.WriteString(fmt.Sprintf("    %s[\"%s\"]\n", id, mermaidEscape(label)))
+	}
+
+	for _, id := range ids {
+		for _, dep := range g.edges[id] {
+			sb.WriteString(fmt.Sprintf("    %s --> %s\n", dep, id))
+		}
+	}
+
+	sb.WriteString(fmt.Sprintf("    classDef completed fill:%s,stroke:#333\n", colorCompleted))
+	sb.WriteString(fmt.Sprintf("    classDef inProgress fill:%s,stroke:#333\n", colorInProgress))
+	sb.WriteString(fmt.Sprintf("    classDef failed fill:%s,stroke:#333\n", colorFailed))
+	sb.WriteString(fmt.Sprintf("    classDef notStarted fill:%s,stroke:#333\n", colorNotStarted))
+
+	classes := make(map[string][]string)
+	for _, id := range ids {
+		class := nodeClass(g.nodes[id])
+		classes[class] = append(classes[class], id)
+	}
+	for _, class := range []string{"completed", "inProgress", "failed", "notStarted"} {
+		if len(classes[class]) > 0 {
+			sb.WriteString(fmt.Sprintf("    class %s %s\n", strings.Join(classes[class], ","), class))
+		}
+	}
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// dotQuote returns s as a quoted DOT identifier
+func dotQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	s = strings.ReplaceAll(s, "\n", " ")
+	return `"` + s + `"`
+}
+
+// mermaidEscape escapes characters that would break a quoted Mermaid label
+func mermaidEscape(s string) string {
+	s = strings.ReplaceAll(s, `"`, "#quot;")
+	s = strings.ReplaceAll(s, "\n", " ")
+	return s
+}
@@ -0,0 +1,103 @@
+package scheduler
+
+import (
+	"fmt"
+	"strings"
+
+	"hermes/internal/isolation"
+	"hermes/internal/merger"
+)
+
+// conflictResolutionAI is the conflictResolution setting that resolves
+// merge conflicts between task branches with the AI
+const conflictResolutionAI = "ai-assisted"
+
+// newResolver returns the resolver for merge conflicts between task
+// branches, or nil unless conflictResolution is "ai-assisted" and an AI
+// provider is set
+func (s *Scheduler) newResolver() *merger.Resolver {
+	if s.provider == nil || s.config == nil || s.config.ConflictResolution != conflictResolutionAI {
+		return nil
+	}
+
+	resolver := merger.NewResolver(s.workDir)
+	resolver.SetPreferredStrategy(merger.StrategyAIAssisted)
+	resolver.SetAIProvider(s.provider)
+	if s.config.AIConfidence > 0 {
+		resolver.SetAIConfidenceThreshold(s.config.AIConfidence)
+	}
+	if s.parallelLogger != nil {
+		resolver.SetMergeLog(s.parallelLogger.Merge)
+	}
+	if s.rollback != nil {
+		// Task snapshots are the common ancestors for three-way merges
+		resolver.SetSnapshots(s.rollback)
+	}
+	return resolver
+}
+
+// resolveMergeConflicts resolves the files a task branch merge left
+// conflicting with the AI. Each file is merged from the versions of the
+// tasks of the batch merged before that changed it and the task's own
+// version. The merge is committed once every file is resolved.
+func (s *Scheduler) resolveMergeConflicts(resolver *merger.Resolver, workspace isolation.Workspace) error {
+	files, err := resolver.GetConflictingFiles()
+	if err != nil {
+		return fmt.Errorf("failed to list conflicting files: %w", err)
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("no conflicting files found")
+	}
+
+	taskID := workspace.GetTaskID()
+	for _, file := range files {
+		tasks := s.mergedTasksChanging(workspace.GetBranch(), file)
+		if len(tasks) == 0 {
+			return fmt.Errorf("%s conflicts with no task merged in this batch", file)
+		}
+		result := resolver.Resolve(merger.Conflict{
+			File:        file,
+			Tasks:       append(tasks, taskID),
+			Type:        merger.ConflictSameFile,
+			Severity:    merger.SeverityHigh,
+			Description: fmt.Sprintf("Tasks %s and %s changed the same lines", strings.Join(tasks, ", "), taskID),
+		})
+		if !result.Success {
+			return fmt.Errorf("%s: %s", file, result.Description)
+		}
+		if err := resolver.MarkResolved(file); err != nil {
+			return fmt.Errorf("failed to mark %s resolved: %w", file, err)
+		}
+	}
+
+	if err := runGitCommand(s.workDir, "commit", "--no-edit"); err != nil {
+		return fmt.Errorf("failed to commit resolved merge: %w", err)
+	}
+	return nil
+}
+
+// mergedTasksChanging returns the tasks merged earlier in the batch whose
+// branch changed file since it forked from branch
+func (s *Scheduler) mergedTasksChanging(branch, file string) []string {
+	var tasks []string
+	for _, merged := range s.mergedBranches {
+		base, err := runGitCommandOutput(s.workDir, "merge-base", merged, branch)
+		if err != nil {
+			continue
+		}
+		// diff --quiet fails when the file differs
+		if runGitCommand(s.workDir, "diff", "--quiet", base, merged, "--", file) != nil {
+			tasks = append(tasks, strings.TrimPrefix(merged, "hermes/"))
+		}
+	}
+	return tasks
+}
+
+// deleteMergedBranches deletes the task branches merged in the batch,
+// which are kept until then as the other side of later merge conflicts
+func (s *Scheduler) deleteMergedBranches() {
+	for _, branch := range s.mergedBranches {
+		runGitCommand(s.workDir, "branch", "-d", branch) // Ignore errors, branch deletion is optional
+	}
+	s.mergedBranches = nil
+}
@@ -2,7 +2,10 @@ package scheduler
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"os"
+	"path/filepath"
 	"runtime"
 	"sync"
 	"sync/atomic"
@@ -63,6 +66,13 @@ func (m *ResourceMonitor) RecordAPICall(cost float64) {
 	m.apiCallsWindow = newWindow
 }
 
+// CostLimitReached returns true if the configured cost limit has been spent
+func (m *ResourceMonitor) CostLimitReached() bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.maxCostPerHour > 0 && m.totalCost >= m.maxCostPerHour
+}
+
 // CanMakeAPICall checks if we can make another API call
 func (m *ResourceMonitor) CanMakeAPICall() bool {
 	m.mu.RLock()
@@ -175,6 +185,60 @@ type ResourceStats struct {
 	MaxMemoryMB     int64
 	MaxCallsPerMin  int
 	MaxCostPerHour  float64
+	LastUpdated     time.Time
+}
+
+// resourceStatsFile is the persisted form of cumulative resource usage
+type resourceStatsFile struct {
+	TotalAPICalls int64     `json:"totalApiCalls"`
+	TotalCost     float64   `json:"totalCost"`
+	LastUpdated   time.Time `json:"lastUpdated"`
+}
+
+// ResourceStatsPath returns the path of the persisted resource statistics
+func ResourceStatsPath(workDir string) string {
+	return filepath.Join(workDir, ".hermes", "resource-stats.json")
+}
+
+// Save writes the cumulative API calls and cost to a JSON file
+func (s ResourceStats) Save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create stats directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(resourceStatsFile{
+		TotalAPICalls: s.TotalAPICalls,
+		TotalCost:     s.TotalCost,
+		LastUpdated:   time.Now(),
+	}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode resource stats: %w", err)
+	}
+
+	return os.WriteFile(path, data, 0644)
+}
+
+// LoadResourceStats reads cumulative resource statistics from a JSON file.
+// A missing file yields empty statistics.
+func LoadResourceStats(path string) (*ResourceStats, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &ResourceStats{}, nil
+		}
+		return nil, fmt.Errorf("failed to read resource stats: %w", err)
+	}
+
+	var file resourceStatsFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse resource stats: %w", err)
+	}
+
+	return &ResourceStats{
+		TotalAPICalls: file.TotalAPICalls,
+		TotalCost:     file.TotalCost,
+		LastUpdated:   file.LastUpdated,
+	}, nil
 }
 
 // Print prints resource statistics
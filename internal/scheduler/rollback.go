@@ -1,8 +1,11 @@
 package scheduler
 
 import (
+	"encoding/json"
 	"fmt"
+	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
 )
 
@@ -10,9 +13,17 @@ import (
 type Rollback struct {
 	workDir    string
 	snapshots  map[string]string // taskID -> commit hash before task
+	order      []string          // taskIDs in the order snapshots were taken
 	baseBranch string
 }
 
+// snapshotFile is the on-disk representation of saved snapshots
+type snapshotFile struct {
+	BaseBranch string            `json:"baseBranch"`
+	Snapshots  map[string]string `json:"snapshots"`
+	Order      []string          `json:"order"`
+}
+
 // NewRollback creates a new rollback manager
 func NewRollback(workDir string) *Rollback {
 	baseBranch, _ := getCurrentBranch(workDir)
@@ -23,21 +34,141 @@ func NewRollback(workDir string) *Rollback {
 	}
 }
 
-// SaveSnapshot saves the current state before a task
+// LoadRollback creates a rollback manager from snapshots persisted on disk
+func LoadRollback(workDir string) (*Rollback, error) {
+	data, err := os.ReadFile(snapshotPath(workDir))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("no snapshots found (run 'hermes run --parallel' first)")
+		}
+		return nil, fmt.Errorf("failed to read snapshots: %w", err)
+	}
+
+	var file snapshotFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse snapshots: %w", err)
+	}
+
+	r := &Rollback{
+		workDir:    workDir,
+		snapshots:  file.Snapshots,
+		order:      file.Order,
+		baseBranch: file.BaseBranch,
+	}
+	if r.snapshots == nil {
+		r.snapshots = make(map[string]string)
+	}
+	return r, nil
+}
+
+// SaveSnapshot saves the current state before a task and persists it to disk
 func (r *Rollback) SaveSnapshot(taskID string) error {
 	commitHash, err := getCurrentCommit(r.workDir)
 	if err != nil {
 		return fmt.Errorf("failed to get current commit: %w", err)
 	}
+	if _, exists := r.snapshots[taskID]; !exists {
+		r.order = append(r.order, taskID)
+	}
 	r.snapshots[taskID] = commitHash
+	return r.save()
+}
+
+// save writes the snapshot map to .hermes/snapshots.json
+func (r *Rollback) save() error {
+	path := snapshotPath(r.workDir)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create snapshot directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(snapshotFile{
+		BaseBranch: r.baseBranch,
+		Snapshots:  r.snapshots,
+		Order:      r.order,
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0644)
+}
+
+// TaskTarget returns the commit a task rollback would reset to
+func (r *Rollback) TaskTarget(taskID string) (string, error) {
+	commitHash, ok := r.snapshots[taskID]
+	if !ok {
+		return "", fmt.Errorf("no snapshot found for task %s", taskID)
+	}
+	if err := r.ValidateCommit(commitHash); err != nil {
+		return "", err
+	}
+	return commitHash, nil
+}
+
+// BatchTarget returns the commit a batch rollback would reset to
+func (r *Rollback) BatchTarget(taskIDs []string) (string, error) {
+	requested := make(map[string]bool)
+	for _, id := range taskIDs {
+		requested[id] = true
+	}
+
+	// Find earliest snapshot among the requested tasks
+	commitHash := r.earliestSnapshot(requested)
+	if commitHash == "" {
+		return "", fmt.Errorf("no snapshots found for batch")
+	}
+	if err := r.ValidateCommit(commitHash); err != nil {
+		return "", err
+	}
+	return commitHash, nil
+}
+
+// AllTarget returns the commit a full rollback would reset to
+func (r *Rollback) AllTarget() (string, error) {
+	commitHash := r.earliestSnapshot(nil)
+	if commitHash == "" {
+		return "", fmt.Errorf("no snapshots available")
+	}
+	if err := r.ValidateCommit(commitHash); err != nil {
+		return "", err
+	}
+	return commitHash, nil
+}
+
+// earliestSnapshot returns the first snapshot taken, optionally limited to a set of tasks
+func (r *Rollback) earliestSnapshot(taskIDs map[string]bool) string {
+	for _, taskID := range r.order {
+		if taskIDs != nil && !taskIDs[taskID] {
+			continue
+		}
+		if commit, ok := r.snapshots[taskID]; ok {
+			return commit
+		}
+	}
+
+	// Snapshots without recorded order
+	for taskID, commit := range r.snapshots {
+		if taskIDs == nil || taskIDs[taskID] {
+			return commit
+		}
+	}
+
+	return ""
+}
+
+// ValidateCommit checks that a snapshot commit still exists in the repository
+func (r *Rollback) ValidateCommit(commitHash string) error {
+	if err := runGitCommand(r.workDir, "cat-file", "-e", commitHash+"^{commit}"); err != nil {
+		return fmt.Errorf("snapshot commit %s no longer exists in repository", shortHash(commitHash))
+	}
 	return nil
 }
 
 // RollbackTask reverts changes made by a specific task
 func (r *Rollback) RollbackTask(taskID string) error {
-	commitHash, ok := r.snapshots[taskID]
-	if !ok {
-		return fmt.Errorf("no snapshot found for task %s", taskID)
+	commitHash, err := r.TaskTarget(taskID)
+	if err != nil {
+		return err
 	}
 
 	// Reset to the snapshot
@@ -50,38 +181,22 @@ func (r *Rollback) RollbackBatch(taskIDs []string) error {
 		return nil
 	}
 
-	// Find earliest snapshot
-	var earliestCommit string
-	for _, taskID := range taskIDs {
-		if commit, ok := r.snapshots[taskID]; ok {
-			if earliestCommit == "" {
-				earliestCommit = commit
-			}
-		}
-	}
-
-	if earliestCommit == "" {
-		return fmt.Errorf("no snapshots found for batch")
+	commitHash, err := r.BatchTarget(taskIDs)
+	if err != nil {
+		return err
 	}
 
-	return runGitCommand(r.workDir, "reset", "--hard", earliestCommit)
+	return runGitCommand(r.workDir, "reset", "--hard", commitHash)
 }
 
 // RollbackAll reverts all changes to the initial state
 func (r *Rollback) RollbackAll() error {
-	// Get the earliest snapshot
-	var earliestCommit string
-	for _, commit := range r.snapshots {
-		if earliestCommit == "" {
-			earliestCommit = commit
-		}
-	}
-
-	if earliestCommit == "" {
-		return fmt.Errorf("no snapshots available")
+	commitHash, err := r.AllTarget()
+	if err != nil {
+		return err
 	}
 
-	return runGitCommand(r.workDir, "reset", "--hard", earliestCommit)
+	return runGitCommand(r.workDir, "reset", "--hard", commitHash)
 }
 
 // CleanupTaskBranches removes all task branches
@@ -142,7 +257,8 @@ func (r *Rollback) Cleanup() error {
 	return r.CleanupTaskBranches()
 }
 
-// GetSnapshot returns the commit hash for a task snapshot
+// GetSnapshot returns the commit hash for a task snapshot. The snapshot is the
+// common ancestor used by merger.Resolver for three-way merges.
 func (r *Rollback) GetSnapshot(taskID string) (string, bool) {
 	commit, ok := r.snapshots[taskID]
 	return commit, ok
@@ -168,7 +284,7 @@ func (r *Rollback) PrintStatus() {
 	if len(r.snapshots) > 0 {
 		fmt.Println("\nTask Snapshots:")
 		for taskID, commit := range r.snapshots {
-			fmt.Printf("  %s: %s\n", taskID, commit[:8])
+			fmt.Printf("  %s: %s\n", taskID, shortHash(commit))
 		}
 	}
 	fmt.Println("═══════════════════════════════════════")
@@ -176,6 +292,17 @@ func (r *Rollback) PrintStatus() {
 
 // Helper functions
 
+func snapshotPath(workDir string) string {
+	return filepath.Join(workDir, ".hermes", "snapshots.json")
+}
+
+func shortHash(commitHash string) string {
+	if len(commitHash) > 8 {
+		return commitHash[:8]
+	}
+	return commitHash
+}
+
 func getCurrentBranch(workDir string) (string, error) {
 	return runGitCommandOutput(workDir, "rev-parse", "--abbrev-ref", "HEAD")
 }
@@ -3,6 +3,7 @@ package scheduler
 import (
 	"context"
 	"fmt"
+	"os"
 	"os/exec"
 	"strings"
 	"sync"
@@ -10,7 +11,9 @@ import (
 
 	"hermes/internal/ai"
 	"hermes/internal/config"
+	"hermes/internal/git"
 	"hermes/internal/isolation"
+	"hermes/internal/merger"
 	"hermes/internal/task"
 	"hermes/internal/ui"
 )
@@ -22,34 +25,85 @@ type Scheduler struct {
 	workDir        string
 	logger         *ui.Logger
 	parallelLogger *ParallelLogger
+	rollback       *Rollback
+	monitor        *ResourceMonitor
+	branchManager  *git.ParallelBranchManager
+	hooks          ExecutionHooks
+	events         chan<- WorkerEvent
+	isolationMode  string
+	dockerImage    string
+	promptContext  string
+	extraArgs      map[string]string
+	systemPrompt   string
+	outputFilter   *ai.OutputFilter
+	retry          *ai.RetryConfig // Back-off of transient provider errors, nil for the default
+	ignoreState    bool
+	ignore         *IgnoreMatcher
+	noApply        bool
+	rebase         bool                     // Rebase task branches before merging
+	conflicts      *merger.ConflictDetector // Conflicts found while rebasing
+	mergedBranches []string                 // Task branches merged in the running batch
+	shard          *Shard
+	shardLock      ShardLock
+	skipped        map[string]bool // Tasks left to other shards or machines
+	healthChecks   map[string]HealthCheck
+	healthRetries  int
+	pool           *WorkerPool     // Pool of the running batch
+	batch          map[string]bool // Task IDs of the running batch
+	paused         bool
 	mu             sync.Mutex
+
+	// DryRun validates tasks in execution order instead of running the AI
+	DryRun bool
+}
+
+// ExecutionHooks receives progress notifications during parallel execution
+type ExecutionHooks struct {
+	OnBatchStart func(batch, total int)
+	OnTaskStart  func(workerID int, t *task.Task)
+	OnTaskDone   func(result *TaskResult)
 }
 
 // ExecutionPlan represents the planned execution order
 type ExecutionPlan struct {
-	Batches      [][]*task.Task
-	TotalTasks   int
+	Batches       [][]*task.Task
+	TotalTasks    int
 	EstimatedTime time.Duration
+	EstimatedCost float64 // USD, 0 when the provider has no known pricing
 }
 
 // ExecutionResult represents the result of executing all tasks
 type ExecutionResult struct {
-	Results     []*TaskResult
-	TotalTime   time.Duration
-	Successful  int
-	Failed      int
-	StartTime   time.Time
-	EndTime     time.Time
+	Results      []*TaskResult
+	TotalTime    time.Duration
+	Successful   int
+	Failed       int
+	StartTime    time.Time
+	EndTime      time.Time
+	Conflicts    map[string][]string // file -> task IDs sharing a batch (dry run)
+	Metrics      TaskMetrics         // AI usage summed over all results
+	TotalCostUSD float64
 }
 
 // New creates a new scheduler
 func New(cfg *config.ParallelConfig, provider ai.Provider, workDir string, logger *ui.Logger) *Scheduler {
-	return &Scheduler{
+	s := &Scheduler{
 		config:   cfg,
 		provider: provider,
 		workDir:  workDir,
 		logger:   logger,
 	}
+	if cfg != nil {
+		s.isolationMode = cfg.IsolationMode
+	}
+
+	ignore, err := LoadIgnoreFile(IgnoreFilePath(workDir))
+	if err != nil {
+		s.logError("Failed to load %s: %v", IgnoreFileName, err)
+	}
+	s.ignore = ignore
+
+	return s
 }
 
 // SetParallelLogger sets the parallel logger for per-worker logging
@@ -57,6 +111,144 @@ func (s *Scheduler) SetParallelLogger(logger *ParallelLogger) {
 	s.parallelLogger = logger
 }
 
+// SetRollback sets the rollback manager used to snapshot each task before execution
+func (s *Scheduler) SetRollback(rollback *Rollback) {
+	s.rollback = rollback
+}
+
+// SetResourceMonitor sets the monitor used to track API calls and enforce the cost limit
+func (s *Scheduler) SetResourceMonitor(monitor *ResourceMonitor) {
+	s.monitor = monitor
+}
+
+// SetAutoCommit enables committing each task's branch separately via a branch manager
+func (s *Scheduler) SetAutoCommit(manager *git.ParallelBranchManager) {
+	s.branchManager = manager
+}
+
+// SetIsolation overrides the configured isolation mode (one of
+// isolation.Modes) and sets the image used for Docker containers
+func (s *Scheduler) SetIsolation(mode, dockerImage string) {
+	s.isolationMode = mode
+	s.dockerImage = dockerImage
+}
+
+// SetPromptContext sets content prepended to every task prompt
+func (s *Scheduler) SetPromptContext(content string) {
+	s.promptContext = content
+}
+
+// SetExtraArgs sets additional CLI flags passed to the provider
+func (s *Scheduler) SetExtraArgs(args map[string]string) {
+	s.extraArgs = args
+}
+
+// SetSystemPrompt sets the system prompt of every task, for providers that
+// support one
+func (s *Scheduler) SetSystemPrompt(prompt string) {
+	s.systemPrompt = prompt
+}
+
+// SetOutputFilter sets the filter applied to each task's output before it
+// is logged
+func (s *Scheduler) SetOutputFilter(f *ai.OutputFilter) {
+	s.outputFilter = f
+}
+
+// SetRetry sets the back-off of the retries of transient provider errors.
+// The number of attempts is still the configured maxRetries.
+func (s *Scheduler) SetRetry(retry ai.RetryConfig) {
+	s.retry = &retry
+}
+
+// SetIgnoreState discards the graph state saved by an interrupted run
+// instead of skipping the tasks it completed
+func (s *Scheduler) SetIgnoreState(ignore bool) {
+	s.ignoreState = ignore
+}
+
+// SetIgnoreMatcher replaces the .hermesignore patterns loaded by New; nil
+// checks every file for conflicts
+func (s *Scheduler) SetIgnoreMatcher(matcher *IgnoreMatcher) {
+	s.ignore = matcher
+}
+
+// SetNoApply keeps the results of isolated tasks out of the project: each
+// workspace's changes are only checked to apply cleanly, and the task
+// branches are kept instead of merged
+func (s *Scheduler) SetNoApply(noApply bool) {
+	s.noApply = noApply
+}
+
+// SetShard runs only the tasks of one shard of a distributed run; tasks
+// of other shards are skipped. A non-nil lock additionally claims each task
+// before it runs, so machines running the same shard do not duplicate work.
+func (s *Scheduler) SetShard(shard *Shard, lock ShardLock) {
+	s.shard = shard
+	s.shardLock = lock
+}
+
+// AddHealthCheck registers a check the workers run before each task, see
+// WorkerPool.AddHealthCheck
+func (s *Scheduler) AddHealthCheck(name string, fn HealthCheck) {
+	if s.healthChecks == nil {
+		s.healthChecks = make(map[string]HealthCheck)
+	}
+	s.healthChecks[name] = fn
+}
+
+// SetHealthRetryLimit sets how often a task is re-queued while a health
+// check fails (0 = DefaultHealthRetryLimit, negative = never)
+func (s *Scheduler) SetHealthRetryLimit(limit int) {
+	s.healthRetries = limit
+}
+
+// Pause stops new tasks from starting; running tasks finish. Batches
+// started while paused wait for Resume.
+func (s *Scheduler) Pause() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.paused = true
+	if s.pool != nil {
+		s.pool.Pause()
+	}
+}
+
+// Resume starts the tasks held back by Pause
+func (s *Scheduler) Resume() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.paused = false
+	if s.pool != nil {
+		s.pool.Resume()
+	}
+}
+
+// IsPaused returns true between Pause and Resume
+func (s *Scheduler) IsPaused() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.paused
+}
+
+// InBatch returns true if the task belongs to the running batch
+func (s *Scheduler) InBatch(taskID string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.batch[taskID]
+}
+
+// SetHooks sets callbacks for execution progress
+func (s *Scheduler) SetHooks(hooks ExecutionHooks) {
+	s.hooks = hooks
+}
+
+// SetEventChannel sets a channel receiving a WorkerEvent for each batch
+// start, task start and task end, and EventRunDone when Execute returns
+func (s *Scheduler) SetEventChannel(events chan<- WorkerEvent) {
+	s.events = events
+}
+
 // GetExecutionPlan returns the planned execution order without executing
 func (s *Scheduler) GetExecutionPlan(tasks []*task.Task) (*ExecutionPlan, error) {
 	graph, err := NewTaskGraph(tasks)
@@ -69,26 +261,124 @@ func (s *Scheduler) GetExecutionPlan(tasks []*task.Task) (*ExecutionPlan, error)
 		return nil, fmt.Errorf("failed to compute batches: %w", err)
 	}
 
-	return &ExecutionPlan{
-		Batches:    batches,
-		TotalTasks: len(tasks),
-	}, nil
+	plan := &ExecutionPlan{
+		Batches:       batches,
+		TotalTasks:    len(tasks),
+		EstimatedTime: time.Duration(EstimateParallelMinutes(batches, s.config.MaxWorkers)) * time.Minute,
+	}
+	if cost, err := s.batchesCost(batches); err == nil {
+		plan.EstimatedCost = cost
+	}
+	return plan, nil
+}
+
+// EstimateCost returns the estimated USD cost of running the tasks that are
+// not completed yet. Each task is assumed to use the configured tokens per
+// minute for its estimated minutes, priced by the provider's pricing.
+func (s *Scheduler) EstimateCost(tasks []*task.Task) (float64, error) {
+	graph, err := NewTaskGraph(tasks)
+	if err != nil {
+		return 0, fmt.Errorf("failed to build task graph: %w", err)
+	}
+
+	batches, err := graph.GetBatches()
+	if err != nil {
+		return 0, fmt.Errorf("failed to compute batches: %w", err)
+	}
+
+	return s.batchesCost(batches)
+}
+
+// batchesCost returns the estimated cost of the tasks in the batches
+func (s *Scheduler) batchesCost(batches [][]*task.Task) (float64, error) {
+	if s.provider == nil {
+		return 0, fmt.Errorf("no AI provider to estimate cost for")
+	}
+	pricing, err := ai.GetPricing(s.provider.Name())
+	if err != nil {
+		return 0, err
+	}
+
+	tokensPerMinute := s.config.TokensPerMinute
+	if tokensPerMinute <= 0 {
+		return 0, fmt.Errorf("tokensPerMinute must be positive to estimate cost")
+	}
+
+	minutes := 0
+	for _, batch := range batches {
+		for _, t := range batch {
+			if t.EstimatedMinutes > 0 {
+				minutes += t.EstimatedMinutes
+			} else {
+				minutes += defaultTaskMinutes
+			}
+		}
+	}
+	return pricing.EstimateCost(minutes * tokensPerMinute), nil
 }
 
 // Execute runs all tasks respecting dependencies
 func (s *Scheduler) Execute(ctx context.Context, tasks []*task.Task) (*ExecutionResult, error) {
+	if s.DryRun {
+		return s.dryRun(tasks)
+	}
+
 	startTime := time.Now()
-	
+	defer sendEvent(ctx, s.events, WorkerEvent{Type: EventRunDone})
+
+	// Combine the worker logs once all batches are done
+	if s.parallelLogger != nil {
+		defer s.writeAggregateLog()
+	}
+
 	result := &ExecutionResult{
 		Results:   make([]*TaskResult, 0),
 		StartTime: startTime,
 	}
 
+	// Add this run's usage to the stats persisted by previous runs
+	if s.monitor != nil {
+		statsPath := ResourceStatsPath(s.workDir)
+		previous, err := LoadResourceStats(statsPath)
+		if err != nil {
+			s.logError("Failed to load resource stats: %v", err)
+			previous = &ResourceStats{}
+		}
+		defer func() {
+			current := s.monitor.GetStats()
+			cumulative := ResourceStats{
+				TotalAPICalls: previous.TotalAPICalls + current.TotalAPICalls,
+				TotalCost:     previous.TotalCost + current.TotalCost,
+			}
+			if err := cumulative.Save(statsPath); err != nil {
+				s.logError("Failed to save resource stats: %v", err)
+			}
+		}()
+	}
+
 	// Build task graph
 	graph, err := NewTaskGraph(tasks)
 	if err != nil {
 		return nil, fmt.Errorf("failed to build task graph: %w", err)
 	}
+	graph.SetBasePath(s.workDir)
+	graph.SetShard(s.shard)
+	s.skipped = make(map[string]bool)
+
+	// Skip the tasks an interrupted run already completed
+	statePath := ShardStatePath(s.workDir, s.shard)
+	if s.ignoreState {
+		if err := os.Remove(statePath); err != nil && !os.IsNotExist(err) {
+			s.logError("Failed to remove graph state: %v", err)
+		}
+	} else {
+		before := graph.GetCompletedCount()
+		if err := graph.LoadState(statePath); err != nil {
+			s.logError("Failed to load graph state: %v", err)
+		} else if restored := graph.GetCompletedCount() - before; restored > 0 {
+			s.logInfo("Skipping %d task(s) completed by an interrupted run (use --ignore-state to run them again)", restored)
+		}
+	}
 
 	// Get execution plan
 	batches, err := graph.GetBatches()
@@ -104,6 +394,9 @@ func (s *Scheduler) Execute(ctx context.Context, tasks []*task.Task) (*Execution
 		}
 		s.logInfo("  Batch %d: %v", i+1, taskIDs)
 	}
+	if s.shard != nil {
+		s.logInfo("Running shard %s", s.shard)
+	}
 
 	// Execute each batch
 	for batchNum, batch := range batches {
@@ -115,8 +408,33 @@ func (s *Scheduler) Execute(ctx context.Context, tasks []*task.Task) (*Execution
 		default:
 		}
 
+		batch = s.shardBatch(ctx, batch)
+		if len(batch) == 0 {
+			s.logInfo("Batch %d/%d has no tasks for this shard", batchNum+1, len(batches))
+			continue
+		}
+		if err := s.waitForShardDependencies(ctx, graph, batch); err != nil {
+			result.EndTime = time.Now()
+			result.TotalTime = result.EndTime.Sub(startTime)
+			s.countResults(result)
+			return result, err
+		}
+
 		s.logInfo("Starting batch %d/%d with %d tasks", batchNum+1, len(batches), len(batch))
 
+		if s.hooks.OnBatchStart != nil {
+			s.hooks.OnBatchStart(batchNum+1, len(batches))
+		}
+		sendEvent(ctx, s.events, WorkerEvent{Type: EventBatchStart, Batch: batchNum + 1, Batches: len(batches)})
+
+		if s.rollback != nil {
+			for _, t := range batch {
+				if err := s.rollback.SaveSnapshot(t.ID); err != nil {
+					s.logError("Failed to save snapshot for task %s: %v", t.ID, err)
+				}
+			}
+		}
+
 		batchResults, err := s.executeBatch(ctx, graph, batch)
 		if err != nil {
 			s.logError("Batch %d failed: %v", batchNum+1, err)
@@ -144,9 +462,27 @@ func (s *Scheduler) Execute(ctx context.Context, tasks []*task.Task) (*Execution
 	result.TotalTime = result.EndTime.Sub(startTime)
 	s.countResults(result)
 
+	// Nothing is left to recover once every task ran successfully
+	if result.Failed == 0 {
+		if err := os.Remove(statePath); err != nil && !os.IsNotExist(err) {
+			s.logError("Failed to remove graph state: %v", err)
+		}
+	}
+
 	return result, nil
 }
 
+// writeAggregateLog writes the merged worker logs to aggregate.log
+func (s *Scheduler) writeAggregateLog() {
+	aggregated, err := s.parallelLogger.Aggregate()
+	if err == nil {
+		err = aggregated.WriteToFile(s.parallelLogger.GetAggregateLogPath())
+	}
+	if err != nil {
+		s.logError("Failed to write aggregate log: %v", err)
+	}
+}
+
 // executeBatch executes a single batch of tasks in parallel
 func (s *Scheduler) executeBatch(ctx context.Context, graph *TaskGraph, batch []*task.Task) ([]*TaskResult, error) {
 	workers := s.config.MaxWorkers
@@ -154,62 +490,113 @@ func (s *Scheduler) executeBatch(ctx context.Context, graph *TaskGraph, batch []
 		workers = len(batch)
 	}
 
+	retry := *ai.DefaultRetryConfig()
+	if s.retry != nil {
+		retry = *s.retry
+	}
+	retry.MaxRetries = s.config.MaxRetries
+
 	pool := NewWorkerPoolWithConfig(ctx, s.provider, s.workDir, WorkerPoolConfig{
-		Workers:      workers,
-		UseIsolation: s.config.IsolatedWorkspaces,
-		Logger:       s.parallelLogger,
-		StreamOutput: false, // Parallel mode should not stream to avoid mixed output
+		Workers:       workers,
+		Isolation:     s.isolationMode,
+		DockerImage:   s.dockerImage,
+		Logger:        s.parallelLogger,
+		StreamOutput:  false, // Parallel mode should not stream to avoid mixed output
+		BranchManager: s.branchManager,
+		Monitor:       s.monitor,
+		Hooks:         s.hooks,
+		Events:        s.events,
+		Retry:         retry,
+		PromptContext: s.promptContext,
+		ExtraArgs:     s.extraArgs,
+		SystemPrompt:  s.systemPrompt,
+		OutputFilter:  s.outputFilter,
+
+		HealthChecks:     s.healthChecks,
+		HealthRetryLimit: s.healthRetries,
 	})
+	s.mu.Lock()
+	s.pool = pool
+	s.batch = make(map[string]bool, len(batch))
+	for _, t := range batch {
+		s.batch[t.ID] = true
+	}
+	if s.paused {
+		pool.Pause()
+	}
+	s.mu.Unlock()
 	pool.Start()
 
-	// Mark tasks as running and submit to pool
+	// Mark tasks as running and submit to pool. Tasks whose requires-file
+	// constraints are not met fail without running.
+	var skipped []*TaskResult
+	submitted := 0
 	for _, t := range batch {
+		if missing := graph.MissingFiles(t.ID); len(missing) > 0 {
+			skipped = append(skipped, &TaskResult{
+				TaskID:   t.ID,
+				TaskName: t.Name,
+				Error:    fmt.Errorf("required files missing: %v", missing),
+			})
+			continue
+		}
 		if err := graph.MarkRunning(t.ID); err != nil {
 			s.logError("Failed to mark task %s as running: %v", t.ID, err)
 		}
 		if err := pool.Submit(t); err != nil {
 			return nil, fmt.Errorf("failed to submit task %s: %w", t.ID, err)
 		}
+		submitted++
 	}
 
-	// Collect results
-	results := pool.WaitForBatch(len(batch))
+	batchTasks := make(map[string]*task.Task, len(batch))
+	for _, t := range batch {
+		batchTasks[t.ID] = t
+	}
 
-	// Update graph based on results
+	// Record each result as it arrives, so an interrupted batch keeps the
+	// tasks that finished. A successful task only counts as completed once
+	// its changes have landed in the project.
 	var batchErr error
-	var successfulTasks []string
-	for _, result := range results {
-		if result.Success {
-			if err := graph.MarkComplete(result.TaskID); err != nil {
-				s.logError("Failed to mark task %s as complete: %v", result.TaskID, err)
+	results := skipped
+	for _, result := range skipped {
+		if err := s.recordResult(graph, result); err != nil {
+			batchErr = err
+		}
+	}
+collect:
+	for i := 0; i < submitted; i++ {
+		var result *TaskResult
+		select {
+		case r, ok := <-pool.Results():
+			if !ok {
+				break collect
 			}
-			s.logInfo("Task %s completed successfully in %v", result.TaskID, result.Duration)
-			successfulTasks = append(successfulTasks, result.TaskID)
-		} else {
-			if err := graph.MarkFailed(result.TaskID); err != nil {
-				s.logError("Failed to mark task %s as failed: %v", result.TaskID, err)
+			result = r
+		case <-ctx.Done():
+			break collect
+		}
+
+		if result.Success && s.isolated() {
+			if err := s.landChanges(ctx, pool.GetWorkspace(result.TaskID), batchTasks[result.TaskID]); err != nil {
+				result.Success = false
+				result.Error = err
 			}
-			s.logError("Task %s failed: %v", result.TaskID, result.Error)
-			batchErr = fmt.Errorf("task %s failed: %w", result.TaskID, result.Error)
-		}
-	}
-
-	// Merge and cleanup workspaces for isolated execution
-	if s.config.IsolatedWorkspaces && len(successfulTasks) > 0 {
-		s.logInfo("Merging %d successful task branches...", len(successfulTasks))
-		for _, taskID := range successfulTasks {
-			workspace := pool.GetWorkspace(taskID)
-			if workspace != nil && workspace.IsIsolated() {
-				// Merge branch to main
-				if err := s.mergeBranch(workspace); err != nil {
-					s.logError("Failed to merge branch for task %s: %v", taskID, err)
-				} else {
-					s.logInfo("Merged branch %s for task %s", workspace.GetBranch(), taskID)
-				}
-				// Cleanup worktree
-				if err := workspace.Cleanup(); err != nil {
-					s.logError("Failed to cleanup workspace for task %s: %v", taskID, err)
-				}
+		}
+		if err := s.recordResult(graph, result); err != nil {
+			batchErr = err
+		}
+		results = append(results, result)
+	}
+
+	s.deleteMergedBranches()
+
+	// Containers and copies of failed tasks are not reused, remove them
+	for _, workspace := range pool.GetWorkspaces() {
+		switch workspace.(type) {
+		case *isolation.DockerWorkspace, *isolation.CopyWorkspace:
+			if workspace.IsIsolated() {
+				workspace.Cleanup()
 			}
 		}
 	}
@@ -220,8 +607,97 @@ func (s *Scheduler) executeBatch(ctx context.Context, graph *TaskGraph, batch []
 	return results, batchErr
 }
 
+// recordResult marks a task completed or failed in the graph and saves the
+// graph state. It returns the error of a failed task.
+func (s *Scheduler) recordResult(graph *TaskGraph, result *TaskResult) error {
+	var taskErr error
+	if result.Success {
+		if err := graph.MarkComplete(result.TaskID); err != nil {
+			s.logError("Failed to mark task %s as complete: %v", result.TaskID, err)
+		}
+		s.logInfo("Task %s completed successfully in %v", result.TaskID, result.Duration)
+	} else {
+		if err := graph.MarkFailed(result.TaskID); err != nil {
+			s.logError("Failed to mark task %s as failed: %v", result.TaskID, err)
+		}
+		s.logError("Task %s failed: %v", result.TaskID, result.Error)
+		taskErr = fmt.Errorf("task %s failed: %w", result.TaskID, result.Error)
+	}
+	if err := graph.SaveState(ShardStatePath(s.workDir, s.shard)); err != nil {
+		s.logError("Failed to save graph state: %v", err)
+	}
+	return taskErr
+}
+
+// landChanges brings the changes of a successful task from its isolated
+// workspace into the project, by merging its branch or applying its files,
+// and removes the workspace. With noApply the changes are only checked.
+func (s *Scheduler) landChanges(ctx context.Context, workspace isolation.Workspace, t *task.Task) error {
+	if workspace == nil || !workspace.IsIsolated() {
+		return nil
+	}
+	taskID := workspace.GetTaskID()
+	if s.noApply {
+		s.checkChanges(workspace)
+		return nil
+	}
+
+	var landErr error
+	switch ws := workspace.(type) {
+	case *isolation.DockerWorkspace:
+		// Copy the container's changes into the project
+		if files, err := ws.CopyBack(); err != nil {
+			landErr = fmt.Errorf("failed to copy back changes: %w", err)
+		} else {
+			s.logInfo("Copied %d changed file(s) back for task %s", len(files), taskID)
+		}
+	case *isolation.CopyWorkspace:
+		// Copies have no branch, their changes are applied as a patch
+		if err := ws.ApplyChanges(s.workDir); err != nil {
+			landErr = fmt.Errorf("failed to apply changes: %w", err)
+		} else {
+			s.logInfo("Applied changes of task %s", taskID)
+		}
+	default:
+		if s.rebase && t != nil {
+			s.rebaseOntoBase(ctx, workspace, t)
+		}
+		if err := s.mergeBranch(workspace); err != nil {
+			landErr = fmt.Errorf("failed to merge branch %s: %w", workspace.GetBranch(), err)
+		} else {
+			s.logInfo("Merged branch %s for task %s", workspace.GetBranch(), taskID)
+		}
+	}
+
+	if err := workspace.Cleanup(); err != nil {
+		s.logError("Failed to cleanup workspace for task %s: %v", taskID, err)
+	}
+	return landErr
+}
+
+// isolated reports whether tasks run in isolated workspaces
+func (s *Scheduler) isolated() bool {
+	return s.isolationMode != "" && s.isolationMode != isolation.ModeNone
+}
+
+// checkChanges reports whether a workspace's changes apply to the project,
+// then removes the workspace without applying them
+func (s *Scheduler) checkChanges(workspace isolation.Workspace) {
+	taskID := workspace.GetTaskID()
+	if err := workspace.CheckChanges(s.workDir); err != nil {
+		s.logError("Changes of task %s do not apply: %v", taskID, err)
+	} else if branch := workspace.GetBranch(); branch != "" {
+		s.logInfo("Changes of task %s apply cleanly (not applied, kept on branch %s)", taskID, branch)
+	} else {
+		s.logInfo("Changes of task %s apply cleanly (not applied)", taskID)
+	}
+	if err := workspace.Cleanup(); err != nil {
+		s.logError("Failed to cleanup workspace for task %s: %v", taskID, err)
+	}
+}
+
 // mergeBranch merges a workspace branch back to the base branch
-func (s *Scheduler) mergeBranch(workspace *isolation.Workspace) error {
+func (s *Scheduler) mergeBranch(workspace isolation.Workspace) error {
 	// Get current branch (should be base branch)
 	cmd := exec.Command("git", "rev-parse", "--abbrev-ref", "HEAD")
 	cmd.Dir = s.workDir
@@ -233,36 +709,47 @@ func (s *Scheduler) mergeBranch(workspace *isolation.Workspace) error {
 
 	// Merge the task branch
 	cmd = exec.Command("git", "merge", workspace.GetBranch(), "--no-edit", "-m", 
-		fmt.Sprintf("Merge branch '%s' (task %s)", workspace.GetBranch(), workspace.TaskID))
+		fmt.Sprintf("Merge branch '%s' (task %s)", workspace.GetBranch(), workspace.GetTaskID()))
 	cmd.Dir = s.workDir
 	if output, err := cmd.CombinedOutput(); err != nil {
 		// Check if it's a merge conflict
-		if strings.Contains(string(output), "CONFLICT") {
-			s.logError("Merge conflict detected for %s, attempting auto-resolution...", workspace.TaskID)
-			// Try to abort and use theirs strategy
-			exec.Command("git", "merge", "--abort").Run()
+		if !strings.Contains(string(output), "CONFLICT") {
+			return fmt.Errorf("merge failed: %w: %s", err, string(output))
+		}
+		s.logError("Merge conflict detected for %s, attempting auto-resolution...", workspace.GetTaskID())
+
+		resolved := false
+		if resolver := s.newResolver(); resolver != nil {
+			if err := s.resolveMergeConflicts(resolver, workspace); err != nil {
+				s.logError("AI resolution of the conflicts of %s failed: %v", workspace.GetTaskID(), err)
+			} else {
+				s.logInfo("Resolved the merge conflicts of %s with AI", workspace.GetTaskID())
+				resolved = true
+			}
+		}
+		if !resolved {
+			// Abort and use theirs strategy
+			abort := exec.Command("git", "merge", "--abort")
+			abort.Dir = s.workDir
+			abort.Run()
 			cmd = exec.Command("git", "merge", workspace.GetBranch(), "--no-edit", "-X", "theirs", "-m",
-				fmt.Sprintf("Merge branch '%s' (task %s) with auto-resolution", workspace.GetBranch(), workspace.TaskID))
+				fmt.Sprintf("Merge branch '%s' (task %s) with auto-resolution", workspace.GetBranch(), workspace.GetTaskID()))
 			cmd.Dir = s.workDir
 			if output, err := cmd.CombinedOutput(); err != nil {
 				return fmt.Errorf("merge failed even with auto-resolution: %w: %s", err, string(output))
 			}
-		} else {
-			return fmt.Errorf("merge failed: %w: %s", err, string(output))
 		}
 	}
 
 	s.logInfo("Successfully merged %s into %s", workspace.GetBranch(), baseBranch)
 
-	// Optionally delete the merged branch
-	cmd = exec.Command("git", "branch", "-d", workspace.GetBranch())
-	cmd.Dir = s.workDir
-	cmd.Run() // Ignore errors, branch deletion is optional
+	// The branch is deleted after the batch, later conflicts may need it
+	s.mergedBranches = append(s.mergedBranches, workspace.GetBranch())
 
 	return nil
 }
 
-// countResults updates the result counts
+// countResults updates the result counts and usage totals
 func (s *Scheduler) countResults(result *ExecutionResult) {
 	for _, r := range result.Results {
 		if r.Success {
@@ -270,7 +757,9 @@ func (s *Scheduler) countResults(result *ExecutionResult) {
 		} else {
 			result.Failed++
 		}
+		result.Metrics.Add(r.Metrics)
 	}
+	result.TotalCostUSD = result.Metrics.CostUSD
 }
 
 func (s *Scheduler) logInfo(format string, args ...interface{}) {
@@ -291,7 +780,14 @@ func (s *Scheduler) PrintExecutionPlan(plan *ExecutionPlan) {
 	fmt.Println("═══════════════════════════════════════")
 	fmt.Printf("Total Tasks: %d\n", plan.TotalTasks)
 	fmt.Printf("Batches: %d\n", len(plan.Batches))
-	fmt.Printf("Max Workers: %d\n\n", s.config.MaxWorkers)
+	fmt.Printf("Max Workers: %d\n", s.config.MaxWorkers)
+	if plan.EstimatedTime > 0 {
+		fmt.Printf("Estimated Time: %v\n", plan.EstimatedTime)
+	}
+	if plan.EstimatedCost > 0 {
+		fmt.Printf("Estimated Cost: $%.2f\n", plan.EstimatedCost)
+	}
+	fmt.Println()
 
 	for i, batch := range plan.Batches {
 		fmt.Printf("Batch %d (%d tasks):\n", i+1, len(batch))
@@ -331,5 +827,13 @@ func (s *Scheduler) PrintExecutionResult(result *ExecutionResult) {
 			fmt.Printf("     Error: %v\n", r.Error)
 		}
 	}
+
+	if result.Metrics.APICallCount > 0 {
+		fmt.Println()
+		for _, r := range result.Results {
+			fmt.Printf("%s: %v | %d tok | $%.3f\n", r.TaskID, r.Duration.Round(100*time.Millisecond), r.Metrics.Tokens(), r.Metrics.CostUSD)
+		}
+		fmt.Printf("Total: %d tok | $%.3f\n", result.Metrics.Tokens(), result.TotalCostUSD)
+	}
 	fmt.Println("═══════════════════════════════════════")
 }
@@ -1,6 +1,14 @@
 package scheduler
 
 import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"hermes/internal/task"
@@ -150,6 +158,142 @@ func TestTaskGraphGetBatches(t *testing.T) {
 	}
 }
 
+func TestTaskGraphCoDeploy(t *testing.T) {
+	tasks := []*task.Task{
+		{ID: "T001", Name: "Schema", Status: task.StatusNotStarted},
+		{ID: "T002", Name: "API", Status: task.StatusNotStarted, DependsOn: []string{"T001"}},
+		{ID: "T003", Name: "Client", Status: task.StatusNotStarted,
+			Constraints: []task.Constraint{{Type: task.ConstraintCoDeploy, TaskID: "T002"}}},
+		{ID: "T004", Name: "Docs", Status: task.StatusNotStarted},
+	}
+
+	graph, err := NewTaskGraph(tasks)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if group := graph.GetCoDeployGroup("T002"); strings.Join(group, ",") != "T002,T003" {
+		t.Errorf("expected group T002,T003, got %v", group)
+	}
+
+	batches, err := graph.GetBatches()
+	if err != nil {
+		t.Fatal(err)
+	}
+	batchOf := make(map[string]int)
+	for i, batch := range batches {
+		for _, tk := range batch {
+			batchOf[tk.ID] = i
+		}
+	}
+	// T003 is ready at once but waits for T002 to share its batch
+	if batchOf["T002"] != batchOf["T003"] || batchOf["T003"] == batchOf["T001"] {
+		t.Errorf("expected T002 and T003 in the same batch after T001, got %v", batchOf)
+	}
+
+	// Co-deployed tasks that depend on each other cannot share a batch
+	tasks[2].DependsOn = []string{"T002"}
+	graph, _ = NewTaskGraph(tasks)
+	if _, err := graph.GetBatches(); err == nil {
+		t.Error("expected error for co-deploy tasks depending on each other")
+	}
+
+	tasks[2].Constraints = []task.Constraint{{Type: task.ConstraintCoDeploy, TaskID: "T999"}}
+	if _, err := NewTaskGraph(tasks); err == nil {
+		t.Error("expected error for unknown co-deploy task")
+	}
+}
+
+func TestTaskGraphRequiresFile(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	tasks := []*task.Task{
+		{ID: "T001", Name: "Schema", Status: task.StatusNotStarted, FilesToTouch: []string{"schema.sql"}},
+		{ID: "T002", Name: "API", Status: task.StatusNotStarted, DependsOn: []string{"T001"},
+			Constraints: []task.Constraint{{Type: task.ConstraintRequiresFile, TaskID: "T001"}}},
+	}
+
+	graph, err := NewTaskGraph(tasks)
+	if err != nil {
+		t.Fatal(err)
+	}
+	graph.SetBasePath(tmpDir)
+
+	graph.MarkRunning("T001")
+	graph.MarkComplete("T001")
+	if node, _ := graph.GetNode("T002"); node.Status != NodePending {
+		t.Errorf("T002 should stay pending while schema.sql is missing, got %s", node.Status)
+	}
+	if missing := graph.MissingFiles("T002"); len(missing) != 1 || missing[0] != "schema.sql" {
+		t.Errorf("expected schema.sql missing, got %v", missing)
+	}
+
+	os.WriteFile(filepath.Join(tmpDir, "schema.sql"), []byte("CREATE TABLE t;"), 0644)
+	graph.SetBasePath(tmpDir)
+	if node, _ := graph.GetNode("T002"); node.Status != NodeReady {
+		t.Errorf("T002 should be ready once schema.sql exists, got %s", node.Status)
+	}
+}
+
+func TestTaskGraphWriteDOT(t *testing.T) {
+	tasks := []*task.Task{
+		{ID: "T001", Name: "Setup \"db\"", Status: task.StatusCompleted},
+		{ID: "T002", Name: "API", Status: task.StatusInProgress, DependsOn: []string{"T001"}},
+		{ID: "T003", Name: "UI", Status: task.StatusBlocked, DependsOn: []string{"T002"}},
+		{ID: "T004", Name: "Docs", Status: task.StatusNotStarted},
+	}
+
+	graph, _ := NewTaskGraph(tasks)
+	var sb strings.Builder
+	if err := graph.WriteDOT(&sb); err != nil {
+		t.Fatalf("WriteDOT failed: %v", err)
+	}
+	out := sb.String()
+
+	expected := []string{
+		"digraph tasks {",
+		`"T001" [label="T001: Setup \"db\"", fillcolor="` + colorCompleted + `"];`,
+		`"T002" [label="T002: API", fillcolor="` + colorInProgress + `"];`,
+		`"T003" [label="T003: UI", fillcolor="` + colorFailed + `"];`,
+		`"T004" [label="T004: Docs", fillcolor="` + colorNotStarted + `"];`,
+		`"T001" -> "T002";`,
+		`"T002" -> "T003";`,
+	}
+	for _, e := range expected {
+		if !strings.Contains(out, e) {
+			t.Errorf("Expected DOT output to contain %q, got:\n%s", e, out)
+		}
+	}
+}
+
+func TestTaskGraphWriteMermaid(t *testing.T) {
+	tasks := []*task.Task{
+		{ID: "T001", Name: "Setup \"db\"", Status: task.StatusCompleted},
+		{ID: "T002", Name: "API", Status: task.StatusNotStarted, DependsOn: []string{"T001"}},
+	}
+
+	graph, _ := NewTaskGraph(tasks)
+	graph.MarkFailed("T002")
+
+	var sb strings.Builder
+	if err := graph.WriteMermaid(&sb); err != nil {
+		t.Fatalf("WriteMermaid failed: %v", err)
+	}
+	out := sb.String()
+
+	expected := []string{
+		"flowchart LR",
+		`T001["T001: Setup #quot;db#quot;"]`,
+		"T001 --> T002",
+		"class T001 completed",
+		"class T002 failed",
+	}
+	for _, e := range expected {
+		if !strings.Contains(out, e) {
+			t.Errorf("Expected Mermaid output to contain %q, got:\n%s", e, out)
+		}
+	}
+}
+
 func TestSortByPriority(t *testing.T) {
 	tasks := []*task.Task{
 		{ID: "T001", Name: "Task 1", Priority: task.PriorityP3},
@@ -170,6 +314,32 @@ func TestSortByPriority(t *testing.T) {
 	}
 }
 
+func TestEstimateParallelTime(t *testing.T) {
+	tasks := []*task.Task{
+		{ID: "T001", EstimatedMinutes: 480},
+		{ID: "T002", EstimatedMinutes: 240},
+		{ID: "T003", EstimatedMinutes: 0}, // falls back to the default
+		{ID: "T004", EstimatedMinutes: 180, Dependencies: []string{"T001"}},
+	}
+
+	// Batch 1: (480+240+10)/2 = 365, batch 2: 180 on a single worker
+	if got := EstimateParallelTime(tasks, 2); got != "9h 5m" {
+		t.Errorf("Expected 9h 5m, got %s", got)
+	}
+
+	// Batch 1 with one worker: 730, batch 2: 180
+	if got := EstimateParallelMinutes([][]*task.Task{tasks[:3], tasks[3:]}, 1); got != 910 {
+		t.Errorf("Expected 910 minutes, got %d", got)
+	}
+
+	if got := EstimateParallelTime([]*task.Task{{ID: "T001", EstimatedMinutes: 45}}, 3); got != "45m" {
+		t.Errorf("Expected 45m, got %s", got)
+	}
+	if got := EstimateParallelTime(nil, 3); got != "0s" {
+		t.Errorf("Expected 0s, got %s", got)
+	}
+}
+
 func TestDetectFileConflicts(t *testing.T) {
 	tasks := []*task.Task{
 		{ID: "T001", Name: "Task 1", FilesToTouch: []string{"file1.go", "file2.go"}},
@@ -192,3 +362,382 @@ func TestDetectFileConflicts(t *testing.T) {
 		t.Error("file2.go should be a conflict")
 	}
 }
+
+func TestIgnoreMatcher(t *testing.T) {
+	m := ParseIgnore("# generated\nvendor/\nnode_modules\n*.pb.go\n!api/keep.pb.go\n/build\ndocs/**/*.html\n")
+
+	tests := map[string]bool{
+		"vendor/github.com/x/y.go":  true,
+		"pkg/vendor/lib.go":         true,
+		"vendor":                    false, // a file, not a directory
+		"web/node_modules/a/b.js":   true,
+		"api/user.pb.go":            true,
+		"api/keep.pb.go":            false,
+		"build/out.js":              true,
+		"cmd/build/main.go":         false,
+		"docs/index.html":           true,
+		"docs/guide/intro.html":     true,
+		"./internal/server/main.go": false,
+	}
+	for file, want := range tests {
+		if got := m.Match(file); got != want {
+			t.Errorf("Match(%q) = %v, want %v", file, got, want)
+		}
+	}
+
+	var none *IgnoreMatcher
+	if none.Match("vendor/a.go") {
+		t.Error("nil matcher should match nothing")
+	}
+
+	tasks := []*task.Task{
+		{ID: "T001", FilesToTouch: []string{"api/user.pb.go", "api/user.go"}},
+		{ID: "T002", FilesToTouch: []string{"api/user.pb.go", "vendor/lib.go"}},
+	}
+	if conflicts := DetectFileConflicts(m.FilterTasks(tasks)); len(conflicts) != 0 {
+		t.Errorf("expected no conflicts after filtering, got %v", conflicts)
+	}
+	if len(tasks[0].FilesToTouch) != 2 {
+		t.Error("FilterTasks should not modify the original tasks")
+	}
+}
+
+func TestChooseStrategy(t *testing.T) {
+	chain := []*task.Task{
+		{ID: "T001", Status: task.StatusNotStarted},
+		{ID: "T002", Status: task.StatusNotStarted, DependsOn: []string{"T001"}},
+	}
+	if s, err := ChooseStrategy(chain); err != nil || s != StrategySequential {
+		t.Errorf("Expected sequential for a chain, got %s, %v", s, err)
+	}
+
+	fanOut := append(chain, &task.Task{ID: "T003", Status: task.StatusNotStarted, DependsOn: []string{"T001"}})
+	if s, err := ChooseStrategy(fanOut); err != nil || s != StrategyParallel {
+		t.Errorf("Expected parallel for independent tasks, got %s, %v", s, err)
+	}
+
+	if !IsValidStrategy(StrategyAuto) || IsValidStrategy("fastest") {
+		t.Error("IsValidStrategy returned unexpected results")
+	}
+}
+
+func TestAggregate(t *testing.T) {
+	dir := t.TempDir()
+	logger, err := NewParallelLogger(dir, 2)
+	if err != nil {
+		t.Fatalf("NewParallelLogger failed: %v", err)
+	}
+	defer logger.Close()
+
+	worker1 := "[2024-01-01 10:00:02] [W1] second\nraw output\n[2024-01-01 10:00:04] [W1] fourth\n"
+	worker2 := "[2024-01-01 10:00:01] [W2] first\n[2024-01-01 10:00:03] [W2] third\n"
+	if err := os.WriteFile(logger.GetWorkerLogPath(1), []byte(worker1), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(logger.GetWorkerLogPath(2), []byte(worker2), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	aggregated, err := logger.Aggregate()
+	if err != nil {
+		t.Fatalf("Aggregate failed: %v", err)
+	}
+
+	expected := []string{
+		"[Worker 2] [2024-01-01 10:00:01] [W2] first",
+		"[Worker 1] [2024-01-01 10:00:02] [W1] second",
+		"[Worker 1] raw output",
+		"[Worker 2] [2024-01-01 10:00:03] [W2] third",
+		"[Worker 1] [2024-01-01 10:00:04] [W1] fourth",
+	}
+	if strings.Join(aggregated.Lines, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Unexpected aggregate:\n%s", strings.Join(aggregated.Lines, "\n"))
+	}
+
+	data, err := io.ReadAll(aggregated)
+	if err != nil || string(data) != strings.Join(expected, "\n")+"\n" {
+		t.Errorf("Read returned %q, %v", data, err)
+	}
+
+	path := logger.GetAggregateLogPath()
+	if err := aggregated.WriteToFile(path); err != nil {
+		t.Fatalf("WriteToFile failed: %v", err)
+	}
+	if written, _ := os.ReadFile(path); string(written) != string(data) {
+		t.Errorf("aggregate.log does not match: %q", written)
+	}
+}
+
+func TestShard(t *testing.T) {
+	for _, invalid := range []string{"", "1", "0/3", "4/3", "a/b", "1/0"} {
+		if _, err := ParseShard(invalid); err == nil {
+			t.Errorf("ParseShard(%q) should fail", invalid)
+		}
+	}
+
+	var shards []*Shard
+	for i := 1; i <= 3; i++ {
+		shard, err := ParseShard(fmt.Sprintf("%d/3", i))
+		if err != nil {
+			t.Fatalf("ParseShard failed: %v", err)
+		}
+		shards = append(shards, shard)
+	}
+	if shards[1].String() != "2/3" {
+		t.Errorf("String() = %q, want 2/3", shards[1].String())
+	}
+
+	// Every task belongs to exactly one shard
+	var tasks []*task.Task
+	for i := 1; i <= 30; i++ {
+		id := fmt.Sprintf("T%03d", i)
+		owners := 0
+		for _, shard := range shards {
+			if shard.Contains(id) {
+				owners++
+			}
+		}
+		if owners != 1 {
+			t.Errorf("%s belongs to %d shards", id, owners)
+		}
+		tasks = append(tasks, &task.Task{ID: id, Status: task.StatusNotStarted})
+	}
+
+	graph, err := NewTaskGraph(tasks)
+	if err != nil {
+		t.Fatalf("Failed to create graph: %v", err)
+	}
+	graph.SetShard(shards[0])
+	ready := graph.GetReadyTasks()
+	if len(ready) == 0 || len(ready) == len(tasks) {
+		t.Errorf("Expected a part of the tasks to be ready, got %d of %d", len(ready), len(tasks))
+	}
+	for _, r := range ready {
+		if !shards[0].Contains(r.ID) {
+			t.Errorf("Ready task %s belongs to another shard", r.ID)
+		}
+	}
+
+	s := &Scheduler{shard: shards[0], skipped: make(map[string]bool)}
+	mine := s.shardBatch(context.Background(), tasks)
+	if len(mine) != len(ready) || len(s.skipped) != len(tasks)-len(ready) {
+		t.Errorf("shardBatch kept %d and skipped %d tasks, want %d and %d",
+			len(mine), len(s.skipped), len(ready), len(tasks)-len(ready))
+	}
+}
+
+func TestRedisLock(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("Cannot listen: %v", err)
+	}
+	defer listener.Close()
+
+	// A minimal server handling SET NX and SELECT
+	keys := make(map[string]string)
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			r := bufio.NewReader(conn)
+			for {
+				line, err := r.ReadString('\n')
+				if err != nil {
+					break
+				}
+				var args []string
+				var n int
+				fmt.Sscanf(line, "*%d", &n)
+				for i := 0; i < n; i++ {
+					r.ReadString('\n')
+					arg, _ := r.ReadString('\n')
+					args = append(args, strings.TrimRight(arg, "\r\n"))
+				}
+				switch {
+				case args[0] == "SET" && keys[args[1]] != "":
+					io.WriteString(conn, "$-1\r\n")
+				case args[0] == "SET":
+					keys[args[1]] = args[2]
+					io.WriteString(conn, "+OK\r\n")
+				default:
+					io.WriteString(conn, "+OK\r\n")
+				}
+			}
+			conn.Close()
+		}
+	}()
+
+	if _, err := NewRedisLock("http://localhost"); err == nil {
+		t.Error("NewRedisLock should reject non-redis URLs")
+	}
+	lock, err := NewRedisLock("redis://" + listener.Addr().String() + "/2")
+	if err != nil {
+		t.Fatalf("NewRedisLock failed: %v", err)
+	}
+
+	ctx := context.Background()
+	if acquired, err := lock.Acquire(ctx, "T001"); err != nil || !acquired {
+		t.Fatalf("First Acquire = %v, %v; want true", acquired, err)
+	}
+	if acquired, err := lock.Acquire(ctx, "T001"); err != nil || acquired {
+		t.Errorf("Second Acquire = %v, %v; want false", acquired, err)
+	}
+	if acquired, err := lock.Acquire(ctx, "T002"); err != nil || !acquired {
+		t.Errorf("Acquire of another task = %v, %v; want true", acquired, err)
+	}
+}
+
+func TestDispatchPolicy(t *testing.T) {
+	tasks := []*task.Task{
+		{ID: "T001", Priority: task.PriorityP3, EstimatedMinutes: 30},
+		{ID: "T002", Priority: task.PriorityP1, EstimatedMinutes: 120},
+		{ID: "T003", Priority: task.PriorityP2, EstimatedMinutes: 5},
+		{ID: "T004", Priority: task.PriorityP1, EstimatedMinutes: 60},
+	}
+	tests := []struct {
+		policy   DispatchPolicy
+		expected string
+	}{
+		{PriorityPolicy, "T002 T004 T003 T001"},
+		{FIFOPolicy, "T001 T002 T003 T004"},
+		{ShortestJobFirstPolicy, "T003 T001 T004 T002"},
+	}
+
+	for _, tt := range tests {
+		q := newTaskQueue(context.Background())
+		q.setPolicy(tt.policy)
+		for _, tk := range tasks {
+			q.push(tk)
+		}
+		q.close()
+
+		var order []string
+		for {
+			tk, ok := q.pop()
+			if !ok {
+				break
+			}
+			order = append(order, tk.ID)
+		}
+		if got := strings.Join(order, " "); got != tt.expected {
+			t.Errorf("%s: expected %s, got %s", tt.policy, tt.expected, got)
+		}
+	}
+
+	q := newTaskQueue(context.Background())
+	q.close()
+	if err := q.push(tasks[0]); err == nil {
+		t.Error("expected error pushing to a closed queue")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	q = newTaskQueue(ctx)
+	done := make(chan bool)
+	go func() {
+		_, ok := q.pop()
+		done <- ok
+	}()
+	cancel()
+	if ok := <-done; ok {
+		t.Error("expected pop to return false after cancel")
+	}
+}
+
+// syntheticTaskGraph returns 20 tasks in 4 layers of 5, each depending on
+// one task of the layer before, with mixed priorities and efforts
+func syntheticTaskGraph() []*task.Task {
+	priorities := []task.Priority{task.PriorityP1, task.PriorityP2, task.PriorityP3, task.PriorityP4}
+	var tasks []*task.Task
+	for i := 0; i < 20; i++ {
+		t := &task.Task{
+			ID:               fmt.Sprintf("T%03d", i+1),
+			Status:           task.StatusNotStarted,
+			Priority:         priorities[(i*3)%4],
+			EstimatedMinutes: 10 + (i*37)%110,
+		}
+		if i >= 5 {
+			t.DependsOn = []string{fmt.Sprintf("T%03d", i-5+(i%2)+1)}
+		}
+		tasks = append(tasks, t)
+	}
+	return tasks
+}
+
+// simulateDispatch runs tasks on workers in simulated minutes, queueing each
+// task once its dependencies finish, and returns each task's completion time
+func simulateDispatch(tasks []*task.Task, workers int, policy DispatchPolicy) map[string]int {
+	q := newTaskQueue(context.Background())
+	q.setPolicy(policy)
+	remaining := make(map[string]int)
+	dependents := make(map[string][]*task.Task)
+	for _, t := range tasks {
+		remaining[t.ID] = len(t.DependsOn)
+		for _, dep := range t.DependsOn {
+			dependents[dep] = append(dependents[dep], t)
+		}
+		if len(t.DependsOn) == 0 {
+			q.push(t)
+		}
+	}
+
+	type running struct {
+		task *task.Task
+		ends int
+	}
+	var active []running
+	finished := make(map[string]int)
+	now := 0
+	for len(finished) < len(tasks) {
+		for len(active) < workers && q.heap.Len() > 0 {
+			t, _ := q.pop()
+			active = append(active, running{t, now + taskMinutes(t)})
+		}
+
+		// Advance to the next completion
+		next := 0
+		for i := range active {
+			if active[i].ends < active[next].ends {
+				next = i
+			}
+		}
+		done := active[next]
+		active = append(active[:next], active[next+1:]...)
+		now = done.ends
+		finished[done.task.ID] = now
+		for _, d := range dependents[done.task.ID] {
+			if remaining[d.ID]--; remaining[d.ID] == 0 {
+				q.push(d)
+			}
+		}
+	}
+	return finished
+}
+
+func BenchmarkDispatchPolicy(b *testing.B) {
+	tasks := syntheticTaskGraph()
+	for _, policy := range []DispatchPolicy{FIFOPolicy, PriorityPolicy, ShortestJobFirstPolicy} {
+		b.Run(policy.String(), func(b *testing.B) {
+			var finished map[string]int
+			for i := 0; i < b.N; i++ {
+				finished = simulateDispatch(tasks, 3, policy)
+			}
+
+			var total, p1Total, p1Count, last int
+			for _, t := range tasks {
+				total += finished[t.ID]
+				if t.Priority == task.PriorityP1 {
+					p1Total += finished[t.ID]
+					p1Count++
+				}
+				if finished[t.ID] > last {
+					last = finished[t.ID]
+				}
+			}
+			b.ReportMetric(float64(total)/float64(len(tasks)), "mean-min")
+			b.ReportMetric(float64(p1Total)/float64(p1Count), "p1-mean-min")
+			b.ReportMetric(float64(last), "makespan-min")
+		})
+	}
+}
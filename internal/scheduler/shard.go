@@ -0,0 +1,269 @@
+package scheduler
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"hash/fnv"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"hermes/internal/task"
+)
+
+const (
+	// shardLockPrefix prefixes the Redis key of each task's lock
+	shardLockPrefix = "hermes:task:"
+	// shardLockTTL is how long a task stays claimed by the machine that took it
+	shardLockTTL = 6 * time.Hour
+	// shardPollInterval is how often the task files are re-read while a task
+	// waits for a dependency run by another shard
+	shardPollInterval = 30 * time.Second
+	// redisDialTimeout bounds connecting and talking to the lock server
+	redisDialTimeout = 10 * time.Second
+)
+
+// Shard selects the part of the tasks one machine runs when a run is
+// distributed over Count machines. Index is 1-based.
+type Shard struct {
+	Index int
+	Count int
+}
+
+// ParseShard parses a shard given as "N/M", e.g. "2/3"
+func ParseShard(s string) (*Shard, error) {
+	n, m, ok := strings.Cut(strings.TrimSpace(s), "/")
+	if !ok {
+		return nil, fmt.Errorf("invalid shard %q (use N/M, e.g. 1/3)", s)
+	}
+	index, err1 := strconv.Atoi(strings.TrimSpace(n))
+	count, err2 := strconv.Atoi(strings.TrimSpace(m))
+	if err1 != nil || err2 != nil {
+		return nil, fmt.Errorf("invalid shard %q (use N/M, e.g. 1/3)", s)
+	}
+	if count < 1 || index < 1 || index > count {
+		return nil, fmt.Errorf("invalid shard %q: N must be between 1 and M", s)
+	}
+	return &Shard{Index: index, Count: count}, nil
+}
+
+// String returns the shard as "N/M"
+func (s *Shard) String() string {
+	return fmt.Sprintf("%d/%d", s.Index, s.Count)
+}
+
+// Contains reports whether the task belongs to the shard. Tasks are spread
+// by a hash of their ID, so every machine computes the same split.
+func (s *Shard) Contains(taskID string) bool {
+	if s == nil || s.Count <= 1 {
+		return true
+	}
+	h := fnv.New32a()
+	h.Write([]byte(taskID))
+	return int(h.Sum32()%uint32(s.Count)) == s.Index-1
+}
+
+// SetShard limits GetReadyTasks to the tasks of a shard; nil returns all
+func (g *TaskGraph) SetShard(shard *Shard) {
+	g.shard = shard
+}
+
+// ShardStatePath returns the graph state file of a shard, so machines
+// sharing a filesystem do not overwrite each other's state
+func ShardStatePath(workDir string, shard *Shard) string {
+	if shard == nil {
+		return GraphStatePath(workDir)
+	}
+	return strings.TrimSuffix(GraphStatePath(workDir), ".json") +
+		fmt.Sprintf("-%dof%d.json", shard.Index, shard.Count)
+}
+
+// ShardLock claims tasks so that no two machines run the same task
+type ShardLock interface {
+	// Acquire claims the task. It returns false if another machine holds it.
+	Acquire(ctx context.Context, taskID string) (bool, error)
+}
+
+// RedisLock is a ShardLock that claims each task with a Redis SET NX
+type RedisLock struct {
+	addr     string
+	password string
+	db       int
+	owner    string
+	ttl      time.Duration
+}
+
+// NewRedisLock creates a lock from a redis://[:password@]host[:port][/db] URL
+func NewRedisLock(rawURL string) (*RedisLock, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Scheme != "redis" || u.Hostname() == "" {
+		return nil, fmt.Errorf("invalid lock URL %q (use redis://host:port/db)", rawURL)
+	}
+
+	lock := &RedisLock{addr: u.Host, ttl: shardLockTTL}
+	if u.Port() == "" {
+		lock.addr = net.JoinHostPort(u.Hostname(), "6379")
+	}
+	if u.User != nil {
+		lock.password, _ = u.User.Password()
+		if lock.password == "" {
+			lock.password = u.User.Username()
+		}
+	}
+	if db := strings.Trim(u.Path, "/"); db != "" {
+		if lock.db, err = strconv.Atoi(db); err != nil {
+			return nil, fmt.Errorf("invalid Redis database %q in lock URL", db)
+		}
+	}
+
+	host, _ := os.Hostname()
+	lock.owner = fmt.Sprintf("%s:%d", host, os.Getpid())
+	return lock, nil
+}
+
+// Acquire claims the task for shardLockTTL
+func (l *RedisLock) Acquire(ctx context.Context, taskID string) (bool, error) {
+	dialer := net.Dialer{Timeout: redisDialTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", l.addr)
+	if err != nil {
+		return false, fmt.Errorf("failed to connect to lock server: %w", err)
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(redisDialTimeout))
+
+	r := bufio.NewReader(conn)
+	if l.password != "" {
+		if _, err := redisCommand(conn, r, "AUTH", l.password); err != nil {
+			return false, err
+		}
+	}
+	if l.db != 0 {
+		if _, err := redisCommand(conn, r, "SELECT", strconv.Itoa(l.db)); err != nil {
+			return false, err
+		}
+	}
+
+	reply, err := redisCommand(conn, r, "SET", shardLockPrefix+taskID, l.owner,
+		"NX", "PX", strconv.FormatInt(l.ttl.Milliseconds(), 10))
+	if err != nil {
+		return false, err
+	}
+	// SET NX replies OK when set and a null bulk string when the key exists
+	return reply == "OK", nil
+}
+
+// redisCommand sends a command in the Redis protocol and returns its simple
+// string reply, or "" for a null reply
+func redisCommand(conn net.Conn, r *bufio.Reader, args ...string) (string, error) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "*%d\r\n", len(args))
+	for _, arg := range args {
+		fmt.Fprintf(&b, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+	if _, err := conn.Write([]byte(b.String())); err != nil {
+		return "", fmt.Errorf("lock server: %w", err)
+	}
+
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return "", fmt.Errorf("lock server: %w", err)
+	}
+	line = strings.TrimRight(line, "\r\n")
+	if line == "" {
+		return "", fmt.Errorf("lock server: empty reply")
+	}
+
+	switch line[0] {
+	case '+':
+		return line[1:], nil
+	case '-':
+		return "", fmt.Errorf("lock server: %s", line[1:])
+	case '$', '*':
+		if line[1:] == "-1" {
+			return "", nil
+		}
+	}
+	return "", fmt.Errorf("lock server: unexpected reply %q", line)
+}
+
+// shardBatch returns the tasks of the batch this machine runs. Tasks of
+// other shards, and tasks another machine already claimed, are recorded as
+// skipped so their dependents wait for them instead of failing.
+func (s *Scheduler) shardBatch(ctx context.Context, batch []*task.Task) []*task.Task {
+	if s.shard == nil && s.shardLock == nil {
+		return batch
+	}
+
+	var mine []*task.Task
+	for _, t := range batch {
+		if !s.shard.Contains(t.ID) {
+			s.logInfo("Skipping task %s: belongs to another shard", t.ID)
+			s.skipped[t.ID] = true
+			continue
+		}
+		if s.shardLock != nil {
+			acquired, err := s.shardLock.Acquire(ctx, t.ID)
+			if err != nil {
+				s.logError("Failed to claim task %s: %v", t.ID, err)
+				s.skipped[t.ID] = true
+				continue
+			}
+			if !acquired {
+				s.logInfo("Skipping task %s: claimed by another machine", t.ID)
+				s.skipped[t.ID] = true
+				continue
+			}
+		}
+		mine = append(mine, t)
+	}
+	return mine
+}
+
+// waitForShardDependencies blocks until every dependency of the batch that
+// another shard runs is completed in the task files. The task files must be
+// shared between the machines, e.g. on a shared filesystem.
+func (s *Scheduler) waitForShardDependencies(ctx context.Context, graph *TaskGraph, batch []*task.Task) error {
+	var waiting []string
+	seen := make(map[string]bool)
+	for _, t := range batch {
+		for _, dep := range graph.edges[t.ID] {
+			if s.skipped[dep] && !seen[dep] {
+				seen[dep] = true
+				waiting = append(waiting, dep)
+			}
+		}
+	}
+	if len(waiting) == 0 {
+		return nil
+	}
+
+	reader := task.NewReader(s.workDir)
+	logged := false
+	for {
+		var pending []string
+		for _, id := range waiting {
+			t, err := reader.GetTaskByID(id)
+			if err != nil || t == nil || t.Status != task.StatusCompleted {
+				pending = append(pending, id)
+			}
+		}
+		if len(pending) == 0 {
+			return nil
+		}
+		if !logged {
+			s.logInfo("Waiting for task(s) %s run by other shards", strings.Join(pending, ", "))
+			logged = true
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(shardPollInterval):
+		}
+		waiting = pending
+	}
+}
@@ -0,0 +1,91 @@
+package scheduler
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"hermes/internal/task"
+)
+
+// Execution strategies for hermes run
+const (
+	StrategySequential = "sequential"
+	StrategyParallel   = "parallel"
+	StrategyAuto       = "auto" // Parallel if any batch has more than one task
+)
+
+// IsValidStrategy returns true if s is a known execution strategy
+func IsValidStrategy(s string) bool {
+	switch s {
+	case StrategySequential, StrategyParallel, StrategyAuto:
+		return true
+	}
+	return false
+}
+
+// ChooseStrategy resolves StrategyAuto for the given tasks: parallel if the
+// task graph has a batch of more than one task, sequential otherwise
+func ChooseStrategy(tasks []*task.Task) (string, error) {
+	graph, err := NewTaskGraph(tasks)
+	if err != nil {
+		return "", fmt.Errorf("failed to build task graph: %w", err)
+	}
+	batches, err := graph.GetBatches()
+	if err != nil {
+		return "", fmt.Errorf("failed to compute batches: %w", err)
+	}
+
+	for _, batch := range batches {
+		if len(batch) > 1 {
+			return StrategyParallel, nil
+		}
+	}
+	return StrategySequential, nil
+}
+
+// RunInfo records how the last run executed
+type RunInfo struct {
+	Strategy  string    `json:"strategy"`
+	Requested string    `json:"requested,omitempty"` // Strategy asked for, e.g. "auto"
+	Workers   int       `json:"workers,omitempty"`
+	StartedAt time.Time `json:"startedAt"`
+}
+
+// RunInfoPath returns the path of the persisted last run information
+func RunInfoPath(workDir string) string {
+	return filepath.Join(workDir, ".hermes", "last-run.json")
+}
+
+// Save writes the run information to a JSON file
+func (r RunInfo) Save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create run info directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode run info: %w", err)
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// LoadRunInfo reads the last run information from a JSON file. A missing
+// file yields nil.
+func LoadRunInfo(path string) (*RunInfo, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read run info: %w", err)
+	}
+
+	var r RunInfo
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, fmt.Errorf("failed to parse run info: %w", err)
+	}
+	return &r, nil
+}
@@ -0,0 +1,100 @@
+package task
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// ArchiveDir returns the directory archived feature files are moved to, in
+// a subdirectory per month (YYYY-MM)
+func ArchiveDir(basePath string) string {
+	return filepath.Join(basePath, ".hermes", "archive")
+}
+
+// SetIncludeArchived makes GetFeatureFiles, and so all features and tasks,
+// include the archived features
+func (r *Reader) SetIncludeArchived(include bool) {
+	r.includeArchived = include
+}
+
+// archivedFeatureFiles returns the archived feature files, oldest month
+// first
+func (r *Reader) archivedFeatureFiles() []string {
+	months, _ := filepath.Glob(filepath.Join(ArchiveDir(r.basePath), "[0-9][0-9][0-9][0-9]-[0-9][0-9]"))
+	var files []string
+	for _, month := range months {
+		files = append(files, featureFilesIn(month)...)
+	}
+	return files
+}
+
+// GetArchivedTasks returns the tasks of the archived features, e.g. to
+// resolve dependencies on them
+func (r *Reader) GetArchivedTasks() []Task {
+	var tasks []Task
+	for _, file := range r.archivedFeatureFiles() {
+		if feature, err := r.ReadFeature(file); err == nil {
+			tasks = append(tasks, feature.Tasks...)
+		}
+	}
+	return tasks
+}
+
+// ArchiveFeature moves the file of a feature to the archive directory of
+// the month of at. It returns the old and new paths.
+func (w *Writer) ArchiveFeature(featureID string, at time.Time) (string, string, error) {
+	feature, err := NewReader(w.basePath).GetFeatureByID(featureID)
+	if err != nil {
+		return "", "", err
+	}
+	if feature == nil {
+		return "", "", fmt.Errorf("feature %s not found", featureID)
+	}
+
+	dir := filepath.Join(ArchiveDir(w.basePath), at.Format("2006-01"))
+	target := filepath.Join(dir, filepath.Base(feature.FilePath))
+	if err := moveFeatureFile(feature.FilePath, target); err != nil {
+		return "", "", err
+	}
+	return feature.FilePath, target, nil
+}
+
+// UnarchiveFeature moves the file of an archived feature back to the tasks
+// directory. It returns the old and new paths.
+func (w *Writer) UnarchiveFeature(featureID string) (string, string, error) {
+	reader := NewReader(w.basePath)
+	if active, err := reader.GetFeatureByID(featureID); err != nil {
+		return "", "", err
+	} else if active != nil {
+		return "", "", fmt.Errorf("feature %s is not archived (%s)", featureID, active.FilePath)
+	}
+
+	for _, file := range reader.archivedFeatureFiles() {
+		feature, err := reader.ReadFeature(file)
+		if err != nil || feature.ID != featureID {
+			continue
+		}
+		target := filepath.Join(w.basePath, ".hermes", "tasks", filepath.Base(file))
+		if err := moveFeatureFile(file, target); err != nil {
+			return "", "", err
+		}
+		// Leave no empty month directories behind
+		os.Remove(filepath.Dir(file))
+		return file, target, nil
+	}
+	return "", "", fmt.Errorf("archived feature %s not found", featureID)
+}
+
+// moveFeatureFile renames a feature file, refusing to replace an existing
+// file
+func moveFeatureFile(from, to string) error {
+	if _, err := os.Stat(to); err == nil {
+		return fmt.Errorf("%s already exists", to)
+	}
+	if err := os.MkdirAll(filepath.Dir(to), 0755); err != nil {
+		return err
+	}
+	return os.Rename(from, to)
+}
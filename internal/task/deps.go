@@ -0,0 +1,67 @@
+package task
+
+import "fmt"
+
+// DependencyIDs returns the IDs of the tasks this task depends on. Explicit
+// DependsOn entries take precedence over the Dependencies list, as in the
+// parallel scheduler.
+func (t *Task) DependencyIDs() []string {
+	if len(t.DependsOn) > 0 {
+		return t.DependsOn
+	}
+	return t.Dependencies
+}
+
+// GetDependencyTree returns every task the given task depends on, directly
+// or through other tasks, keyed by task ID
+func (r *Reader) GetDependencyTree(taskID string) (map[string]*Task, error) {
+	return r.walkDependencies(taskID, false)
+}
+
+// GetDependents returns every task that depends on the given task, directly
+// or through other tasks, keyed by task ID
+func (r *Reader) GetDependents(taskID string) (map[string]*Task, error) {
+	return r.walkDependencies(taskID, true)
+}
+
+// walkDependencies collects the tasks reachable from taskID along the
+// dependency edges, or against them when reverse is set. Unknown IDs in
+// dependency lists are skipped and cycles are walked once.
+func (r *Reader) walkDependencies(taskID string, reverse bool) (map[string]*Task, error) {
+	tasks, err := r.GetAllTasks()
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[string]*Task, len(tasks))
+	edges := make(map[string][]string)
+	for i := range tasks {
+		t := &tasks[i]
+		byID[t.ID] = t
+		for _, dep := range t.DependencyIDs() {
+			if reverse {
+				edges[dep] = append(edges[dep], t.ID)
+			} else {
+				edges[t.ID] = append(edges[t.ID], dep)
+			}
+		}
+	}
+
+	if byID[taskID] == nil {
+		return nil, fmt.Errorf("task %s not found", taskID)
+	}
+
+	found := make(map[string]*Task)
+	queue := append([]string(nil), edges[taskID]...)
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		if id == taskID || found[id] != nil || byID[id] == nil {
+			continue
+		}
+		found[id] = byID[id]
+		queue = append(queue, edges[id]...)
+	}
+
+	return found, nil
+}
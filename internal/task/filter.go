@@ -0,0 +1,160 @@
+package task
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Filter restricts the tasks handed out by a Reader. A task matches if it
+// is not in an excluded feature, touches a file under one of the scope
+// paths when a scope is set and, when any includes are set, it is in one
+// of the included features or is one of the included tasks.
+type Filter struct {
+	Features        []string
+	Tasks           []string
+	ExcludeFeatures []string
+	Scope           []string // Paths relative to the project root
+}
+
+// ParseFilter builds a filter from "feature=<id>" or "task=<id>" include
+// expressions and "feature=<id>" exclude expressions
+func ParseFilter(includes, excludes []string) (*Filter, error) {
+	f := &Filter{}
+
+	for _, expr := range includes {
+		key, id, err := splitFilterExpr(expr)
+		if err != nil {
+			return nil, err
+		}
+		switch key {
+		case "feature":
+			f.Features = append(f.Features, id)
+		case "task":
+			f.Tasks = append(f.Tasks, id)
+		default:
+			return nil, fmt.Errorf("invalid filter %q: use feature=<id> or task=<id>", expr)
+		}
+	}
+
+	for _, expr := range excludes {
+		key, id, err := splitFilterExpr(expr)
+		if err != nil {
+			return nil, err
+		}
+		if key != "feature" {
+			return nil, fmt.Errorf("invalid exclude %q: use feature=<id>", expr)
+		}
+		f.ExcludeFeatures = append(f.ExcludeFeatures, id)
+	}
+
+	return f, nil
+}
+
+func splitFilterExpr(expr string) (string, string, error) {
+	key, id, ok := strings.Cut(expr, "=")
+	id = strings.ToUpper(strings.TrimSpace(id))
+	if !ok || id == "" {
+		return "", "", fmt.Errorf("invalid filter %q: expected key=<id>", expr)
+	}
+	return strings.ToLower(strings.TrimSpace(key)), id, nil
+}
+
+// IsEmpty returns true if the filter matches every task
+func (f *Filter) IsEmpty() bool {
+	return f == nil || (len(f.Features) == 0 && len(f.Tasks) == 0 && len(f.ExcludeFeatures) == 0 && len(f.Scope) == 0)
+}
+
+// Match returns true if the task passes the filter
+func (f *Filter) Match(t Task) bool {
+	if f.IsEmpty() {
+		return true
+	}
+	if contains(f.ExcludeFeatures, t.FeatureID) {
+		return false
+	}
+	if len(f.Scope) > 0 && !InScope(t, f.Scope) {
+		return false
+	}
+	if len(f.Features) == 0 && len(f.Tasks) == 0 {
+		return true
+	}
+	return contains(f.Features, t.FeatureID) || contains(f.Tasks, t.ID)
+}
+
+// String describes the filter, e.g. "feature=F001, exclude feature=F002"
+func (f *Filter) String() string {
+	if f.IsEmpty() {
+		return ""
+	}
+	var parts []string
+	for _, id := range f.Features {
+		parts = append(parts, "feature="+id)
+	}
+	for _, id := range f.Tasks {
+		parts = append(parts, "task="+id)
+	}
+	for _, id := range f.ExcludeFeatures {
+		parts = append(parts, "exclude feature="+id)
+	}
+	for _, dir := range f.Scope {
+		parts = append(parts, "scope="+dir)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// ScopeFilter returns the tasks with at least one file to touch under one
+// of the scope paths. An empty scope returns all tasks.
+func ScopeFilter(tasks []*Task, scope []string) []*Task {
+	if len(scope) == 0 {
+		return tasks
+	}
+	var filtered []*Task
+	for _, t := range tasks {
+		if InScope(*t, scope) {
+			filtered = append(filtered, t)
+		}
+	}
+	return filtered
+}
+
+// InScope returns true if one of the task's files to touch is under one of
+// the scope paths. A glob counts if the files it matches may be under a
+// scope path. Tasks without files to touch are in no scope.
+func InScope(t Task, scope []string) bool {
+	for _, file := range t.FilesToTouch {
+		file = cleanScopePath(file)
+		glob := strings.IndexAny(file, "*?[")
+		for _, dir := range scope {
+			dir = cleanScopePath(dir)
+			if dir == "." || isUnder(file, dir) {
+				return true
+			}
+			// The literal directory before the first wildcard may contain dir
+			if glob >= 0 && isUnder(dir, path.Dir(file[:glob]+"x")) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// cleanScopePath normalizes a project-relative path to slash form
+func cleanScopePath(p string) string {
+	return path.Clean(filepath.ToSlash(strings.TrimSpace(p)))
+}
+
+// isUnder reports whether p is dir or a path inside it
+func isUnder(p, dir string) bool {
+	return dir == "." || p == dir || strings.HasPrefix(p, dir+"/")
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
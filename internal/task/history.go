@@ -0,0 +1,102 @@
+package task
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// maxHistoryEntries is how many status changes task-history.json keeps
+const maxHistoryEntries = 1000
+
+// HistoryEntry is a status change of a task in task-history.json
+type HistoryEntry struct {
+	TaskID    string    `json:"taskId"`
+	From      Status    `json:"from,omitempty"`
+	To        Status    `json:"to"`
+	SessionID string    `json:"sessionId,omitempty"` // Run that made the change, empty outside 'hermes run'
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// HistoryPath returns the path of the task status history
+func HistoryPath(basePath string) string {
+	return filepath.Join(basePath, ".hermes", "task-history.json")
+}
+
+// LoadHistory reads the task status history, oldest change first. A
+// missing file is an empty history.
+func LoadHistory(path string) ([]HistoryEntry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var history []HistoryEntry
+	if err := json.Unmarshal(data, &history); err != nil {
+		return nil, err
+	}
+	return history, nil
+}
+
+// appendHistory adds a status change to the history, keeping the last
+// maxHistoryEntries
+func appendHistory(path string, entry HistoryEntry) error {
+	history, err := LoadHistory(path)
+	if err != nil {
+		// Start over rather than fail status updates on a corrupt file
+		history = nil
+	}
+
+	history = append(history, entry)
+	if len(history) > maxHistoryEntries {
+		history = history[len(history)-maxHistoryEntries:]
+	}
+
+	data, err := json.MarshalIndent(history, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return writeFileAtomic(path, data)
+}
+
+// LastChanges returns the time of each task's last status change
+func LastChanges(history []HistoryEntry) map[string]time.Time {
+	changes := make(map[string]time.Time)
+	for _, entry := range history {
+		if entry.Timestamp.After(changes[entry.TaskID]) {
+			changes[entry.TaskID] = entry.Timestamp
+		}
+	}
+	return changes
+}
+
+// ChangedBySession returns the tasks whose status was changed in a run.
+// The session ID may be shortened to a prefix.
+func ChangedBySession(history []HistoryEntry, sessionID string) map[string]bool {
+	changed := make(map[string]bool)
+	if sessionID == "" {
+		return changed
+	}
+	for _, entry := range history {
+		if strings.HasPrefix(entry.SessionID, sessionID) {
+			changed[entry.TaskID] = true
+		}
+	}
+	return changed
+}
+
+// sectionStatus returns the status of a task section
+func sectionStatus(section string) Status {
+	if m := featureStatusRegex.FindStringSubmatch(section); len(m) > 1 {
+		return Status(m[1])
+	}
+	return ""
+}
@@ -0,0 +1,61 @@
+package task
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MergeRegeneratedTasks replaces the unfinished tasks of a feature file with
+// the tasks of a regenerated version of it. Completed tasks stay in place and
+// regenerated tasks reusing their IDs are dropped; everything outside the
+// task sections is kept as it is. It returns the merged content, the removed
+// tasks and the added tasks.
+func MergeRegeneratedTasks(content, generated string) (string, []Task, []Task, error) {
+	existing, err := ParseFeature(content, "")
+	if err != nil {
+		return "", nil, nil, err
+	}
+	regenerated, err := ParseFeature(generated, "")
+	if err != nil {
+		return "", nil, nil, err
+	}
+	if len(regenerated.Tasks) == 0 {
+		return "", nil, nil, fmt.Errorf("no tasks found in regenerated feature")
+	}
+
+	merged := content
+	kept := &Feature{ID: existing.ID}
+	keptIDs := make(map[string]bool)
+	var removed, added []Task
+	for _, t := range existing.Tasks {
+		if t.Status == StatusCompleted {
+			kept.Tasks = append(kept.Tasks, t)
+			keptIDs[t.ID] = true
+			continue
+		}
+		merged, _, err = cutTaskSection(merged, t.ID)
+		if err != nil {
+			return "", nil, nil, err
+		}
+		removed = append(removed, t)
+	}
+
+	for _, t := range regenerated.Tasks {
+		if keptIDs[t.ID] {
+			continue
+		}
+		start, end, ok := findTaskSection(generated, t.ID)
+		if !ok {
+			continue
+		}
+		section := strings.TrimRight(generated[start:end], "\n") + "\n"
+		merged = appendTaskSection(merged, kept, section)
+
+		t.FeatureID = existing.ID
+		kept.Tasks = append(kept.Tasks, t)
+		keptIDs[t.ID] = true
+		added = append(added, t)
+	}
+
+	return merged, removed, added, nil
+}
@@ -0,0 +1,249 @@
+package task
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var tasksHeadingRegex = regexp.MustCompile(`(?m)^## Tasks[ \t]*$`)
+
+// MoveTask moves a task's section from its feature file to the end of the
+// target feature's tasks. The move is refused if it would make features
+// depend on each other in a circle. Both files are written only once both
+// updated contents are ready. It returns the source and target files.
+func (w *Writer) MoveTask(taskID, targetFeatureID string) (string, string, error) {
+	features, err := NewReader(w.basePath).GetAllFeatures()
+	if err != nil {
+		return "", "", err
+	}
+
+	var source, target *Feature
+	for i := range features {
+		if features[i].ID == targetFeatureID {
+			target = &features[i]
+		}
+		for _, t := range features[i].Tasks {
+			if t.ID == taskID {
+				source = &features[i]
+			}
+		}
+	}
+	if source == nil {
+		return "", "", fmt.Errorf("task %s not found", taskID)
+	}
+	if target == nil {
+		return "", "", fmt.Errorf("feature %s not found", targetFeatureID)
+	}
+	if source.ID == target.ID {
+		return "", "", fmt.Errorf("task %s is already in feature %s", taskID, target.ID)
+	}
+
+	// Check the feature dependencies as they would be after the move. A
+	// circle that already exists is not the move's fault.
+	var before, after []Task
+	for _, f := range features {
+		for _, t := range f.Tasks {
+			before = append(before, t)
+			if t.ID == taskID {
+				t.FeatureID = target.ID
+			}
+			after = append(after, t)
+		}
+	}
+	if cycle := featureCycle(after); cycle != nil && featureCycle(before) == nil {
+		return "", "", fmt.Errorf("moving %s to %s creates a circular feature dependency: %s",
+			taskID, target.ID, strings.Join(cycle, " -> "))
+	}
+
+	sourceContent, err := os.ReadFile(source.FilePath)
+	if err != nil {
+		return "", "", err
+	}
+	targetContent, err := os.ReadFile(target.FilePath)
+	if err != nil {
+		return "", "", err
+	}
+
+	remaining, section, err := cutTaskSection(string(sourceContent), taskID)
+	if err != nil {
+		return "", "", err
+	}
+	updated := appendTaskSection(string(targetContent), target, section)
+
+	err = writeFilesAtomic(map[string][]byte{
+		source.FilePath: []byte(remaining),
+		target.FilePath: []byte(updated),
+	})
+	return source.FilePath, target.FilePath, err
+}
+
+// cutTaskSection removes a task section and the "---" separator next to it,
+// returning the remaining content and the section
+func cutTaskSection(content, taskID string) (string, string, error) {
+	start, end, ok := findTaskSection(content, taskID)
+	if !ok {
+		return "", "", fmt.Errorf("task %s not found", taskID)
+	}
+	section := strings.TrimRight(content[start:end], "\n") + "\n"
+
+	before := content[:start]
+	after := strings.TrimLeft(content[end:], "\n")
+	if strings.HasPrefix(after, "---") {
+		// Drop the separator that followed the task
+		after = strings.TrimLeft(strings.TrimPrefix(after, "---"), " \t\n")
+	} else if trimmed := strings.TrimRight(before, " \t\n"); strings.HasSuffix(trimmed, "\n---") {
+		// Last task: drop the separator before it
+		before = strings.TrimSuffix(trimmed, "---")
+		before = strings.TrimRight(before, "\n") + "\n\n"
+		if after == "" {
+			before = strings.TrimRight(before, "\n") + "\n"
+		}
+	}
+
+	return before + after, section, nil
+}
+
+// appendTaskSection inserts a task section after the last task of the
+// feature, or under its "## Tasks" heading when it has none
+func appendTaskSection(content string, feature *Feature, section string) string {
+	for i := len(feature.Tasks) - 1; i >= 0; i-- {
+		if _, end, ok := findTaskSection(content, feature.Tasks[i].ID); ok {
+			insert := "\n---\n\n" + section
+			if end < len(content) {
+				insert += "\n"
+			}
+			return strings.TrimRight(content[:end], "\n") + "\n" + insert + strings.TrimLeft(content[end:], "\n")
+		}
+	}
+
+	if loc := tasksHeadingRegex.FindStringIndex(content); loc != nil {
+		rest := strings.TrimLeft(content[loc[1]:], "\n")
+		if rest != "" {
+			rest = "\n" + rest
+		}
+		return content[:loc[1]] + "\n\n" + section + rest
+	}
+
+	return strings.TrimRight(content, "\n") + "\n\n## Tasks\n\n" + section
+}
+
+// featureCycle returns a circle of features depending on each other through
+// their tasks' dependencies, or nil if there is none
+func featureCycle(tasks []Task) []string {
+	featureOf := make(map[string]string, len(tasks))
+	for _, t := range tasks {
+		featureOf[t.ID] = t.FeatureID
+	}
+
+	edges := make(map[string]map[string]bool)
+	for _, t := range tasks {
+		for _, dep := range t.DependencyIDs() {
+			other, ok := featureOf[dep]
+			if !ok || other == t.FeatureID {
+				continue
+			}
+			if edges[t.FeatureID] == nil {
+				edges[t.FeatureID] = make(map[string]bool)
+			}
+			edges[t.FeatureID][other] = true
+		}
+	}
+
+	var features []string
+	for f := range edges {
+		features = append(features, f)
+	}
+	sort.Strings(features)
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make(map[string]int)
+	var path []string
+	var visit func(f string) []string
+	visit = func(f string) []string {
+		state[f] = visiting
+		path = append(path, f)
+
+		var next []string
+		for n := range edges[f] {
+			next = append(next, n)
+		}
+		sort.Strings(next)
+		for _, n := range next {
+			switch state[n] {
+			case visiting:
+				for i, p := range path {
+					if p == n {
+						return append(append([]string(nil), path[i:]...), n)
+					}
+				}
+			case unvisited:
+				if cycle := visit(n); cycle != nil {
+					return cycle
+				}
+			}
+		}
+
+		path = path[:len(path)-1]
+		state[f] = visited
+		return nil
+	}
+
+	for _, f := range features {
+		if state[f] == unvisited {
+			if cycle := visit(f); cycle != nil {
+				return cycle
+			}
+		}
+	}
+	return nil
+}
+
+// writeFilesAtomic writes every file to a temporary file first and renames
+// them into place only when all were written
+func writeFilesAtomic(files map[string][]byte) error {
+	temps := make(map[string]string, len(files))
+	cleanup := func() {
+		for _, tmp := range temps {
+			os.Remove(tmp)
+		}
+	}
+
+	for path, data := range files {
+		tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+		if err != nil {
+			cleanup()
+			return err
+		}
+		temps[path] = tmp.Name()
+		if _, err := tmp.Write(data); err != nil {
+			tmp.Close()
+			cleanup()
+			return err
+		}
+		if err := tmp.Close(); err != nil {
+			cleanup()
+			return err
+		}
+		if err := os.Chmod(tmp.Name(), 0644); err != nil {
+			cleanup()
+			return err
+		}
+	}
+
+	for path, tmp := range temps {
+		if err := os.Rename(tmp, path); err != nil {
+			cleanup()
+			return err
+		}
+		delete(temps, path)
+	}
+	return nil
+}
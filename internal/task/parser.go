@@ -1,8 +1,11 @@
 package task
 
 import (
+	"errors"
 	"fmt"
+	"math"
 	"regexp"
+	"strconv"
 	"strings"
 )
 
@@ -11,16 +14,37 @@ var (
 	featureIDRegex        = regexp.MustCompile(`\*\*Feature ID:\*\*\s*(F?\d+)`)
 	featureStatusRegex    = regexp.MustCompile(`\*\*Status:\*\*\s*(\w+)`)
 	taskHeaderRegex       = regexp.MustCompile(`(?m)^###\s*(T\d+):\s*(.+)$`)
-	priorityRegex         = regexp.MustCompile(`\*\*Priority:\*\*\s*(P[1-4])`)
+	priorityRegex         = regexp.MustCompile(`\*\*Priority:\*\*[ \t]*(.+)`)
 	filesToTouchRegex     = regexp.MustCompile(`\*\*Files to Touch:\*\*\s*(.+)`)
 	dependenciesRegex     = regexp.MustCompile(`\*\*Dependencies:\*\*\s*(.+)`)
 	targetVersionRegex    = regexp.MustCompile(`\*\*Target Version:\*\*\s*(.+)`)
 	estimatedDurationRegex = regexp.MustCompile(`\*\*Estimated Duration:\*\*\s*(.+)`)
 	estimatedEffortRegex  = regexp.MustCompile(`\*\*Estimated Effort:\*\*\s*(.+)`)
+	blockedReasonRegex    = regexp.MustCompile(`\*\*Blocked Reason:\*\*\s*(.+)`)
+	completionNoteRegex   = regexp.MustCompile(`\*\*Completion Note:\*\*\s*(.+)`)
+	constraintItemRegex   = regexp.MustCompile(`^(T\d+(?:-T?\d+)?)\s*(?:\(([^)]*)\))?`)
+	effortValueRegex      = regexp.MustCompile(`(?i)^(\d+(?:\.\d+)?)\s*(weeks?|wks?|w|days?|d|hours?|hrs?|h|minutes?|mins?|m)\b`)
+	storyPointsRegex      = regexp.MustCompile(`(?i)^(?:sp|story\s*points?|fibonacci)\s*[:=]?\s*(\d+(?:\.\d+)?)$`)
+	storyPointsSuffixRegex = regexp.MustCompile(`(?i)^(\d+(?:\.\d+)?)\s*(?:sp|story\s*points?)$`)
+)
+
+// ErrUnknownEffortFormat is returned by ParseEffort for estimates it cannot read
+var ErrUnknownEffortFormat = errors.New("unknown effort format")
+
+// Minutes per effort unit (a working day is 8 hours, a week is 5 days)
+const (
+	minutesPerHour = 60
+	minutesPerDay  = 8 * minutesPerHour
+	minutesPerWeek = 5 * minutesPerDay
 )
 
 // ParseFeature parses a feature file content
 func ParseFeature(content, filePath string) (*Feature, error) {
+	return parseFeature(content, filePath, DefaultEffortUnits())
+}
+
+// parseFeature parses a feature file content, converting efforts with units
+func parseFeature(content, filePath string, units EffortUnits) (*Feature, error) {
 	feature := &Feature{
 		FilePath: filePath,
 		Status:   StatusNotStarted,
@@ -48,7 +72,9 @@ func ParseFeature(content, filePath string) (*Feature, error) {
 
 	// Parse feature priority
 	if m := priorityRegex.FindStringSubmatch(content); len(m) > 1 {
-		feature.Priority = Priority(m[1])
+		if p, err := ParsePriority(m[1]); err == nil {
+			feature.Priority = p
+		}
 	}
 
 	// Parse target version
@@ -74,7 +100,7 @@ func ParseFeature(content, filePath string) (*Feature, error) {
 	feature.RiskAssessment = parseSection(content, "## Risk Assessment")
 
 	// Parse tasks
-	feature.Tasks = parseTasks(content, feature.ID)
+	feature.Tasks = parseTasks(content, feature.ID, units)
 
 	return feature, nil
 }
@@ -140,7 +166,7 @@ func parseListSection(content, header string) []string {
 	return items
 }
 
-func parseTasks(content, featureID string) []Task {
+func parseTasks(content, featureID string, units EffortUnits) []Task {
 	var tasks []Task
 
 	// Find all task headers
@@ -176,10 +202,19 @@ func parseTasks(content, featureID string) []Task {
 			task.Status = Status(m[1])
 		}
 		if m := priorityRegex.FindStringSubmatch(taskContent); len(m) > 1 {
-			task.Priority = Priority(m[1])
+			if p, err := ParsePriority(m[1]); err == nil {
+				task.Priority = p
+			}
 		}
 		if m := estimatedEffortRegex.FindStringSubmatch(taskContent); len(m) > 1 {
 			task.EstimatedEffort = strings.TrimSpace(m[1])
+			task.EstimatedMinutes = units.Minutes(task.EstimatedEffort)
+		}
+		if m := blockedReasonRegex.FindStringSubmatch(taskContent); len(m) > 1 {
+			task.BlockedReason = strings.TrimSpace(m[1])
+		}
+		if m := completionNoteRegex.FindStringSubmatch(taskContent); len(m) > 1 {
+			task.CompletionNote = strings.TrimSpace(m[1])
 		}
 		// Parse files to touch (both inline and section formats)
 		if m := filesToTouchRegex.FindStringSubmatch(taskContent); len(m) > 1 {
@@ -196,6 +231,7 @@ func parseTasks(content, featureID string) []Task {
 		if len(task.Dependencies) == 0 {
 			task.Dependencies = parseTaskListSection(taskContent, "#### Dependencies")
 		}
+		applyConstraints(&task, taskContent)
 
 		// Parse description
 		task.Description = parseTaskSubsection(taskContent, "#### Description")
@@ -269,6 +305,7 @@ func parseTaskListSection(content, header string) []string {
 			if idx := strings.Index(item, "("); idx > 0 {
 				item = strings.TrimSpace(item[:idx])
 			}
+			item = UnquoteItem(item)
 			if item != "" && item != "None" && item != "none" {
 				// Handle comma-separated items in a single line
 				if strings.Contains(item, ",") {
@@ -278,6 +315,7 @@ func parseTaskListSection(content, header string) []string {
 						if idx := strings.Index(subItem, "("); idx > 0 {
 							subItem = strings.TrimSpace(subItem[:idx])
 						}
+						subItem = UnquoteItem(subItem)
 						if subItem != "" {
 							// Expand range format like T031-T038
 							expanded := expandTaskRange(subItem)
@@ -318,10 +356,199 @@ func expandTaskRange(item string) []string {
 	return []string{item}
 }
 
+// ParsePriority parses a priority written as "P1", "P1 - CRITICAL" or "CRITICAL"
+func ParsePriority(s string) (Priority, error) {
+	value := strings.ToUpper(strings.TrimSpace(s))
+	if code, _, ok := strings.Cut(value, " "); ok && IsValidPriority(Priority(code)) {
+		value = code
+	}
+	if IsValidPriority(Priority(value)) {
+		return Priority(value), nil
+	}
+	for p, label := range priorityLabels {
+		if value == label {
+			return p, nil
+		}
+	}
+	return "", fmt.Errorf("invalid priority %q (expected P1, P2, P3 or P4)", strings.TrimSpace(s))
+}
+
+// EffortUnits converts estimates that are not durations into minutes
+type EffortUnits struct {
+	StoryPoint int            // Minutes per story point ("SP: 5", "Fibonacci: 8")
+	Sizes      map[string]int // Minutes per T-shirt size: XS, S, M, L, XL
+}
+
+// DefaultEffortUnits returns the units used unless a Reader is given others:
+// a story point is half a day, and sizes range from an hour (XS) to a week (XL)
+func DefaultEffortUnits() EffortUnits {
+	return EffortUnits{
+		StoryPoint: 4 * minutesPerHour,
+		Sizes: map[string]int{
+			"XS": minutesPerHour,
+			"S":  4 * minutesPerHour,
+			"M":  minutesPerDay,
+			"L":  3 * minutesPerDay,
+			"XL": minutesPerWeek,
+		},
+	}
+}
+
+// withDefaults returns the units with zero values replaced by the defaults
+func (u EffortUnits) withDefaults() EffortUnits {
+	defaults := DefaultEffortUnits()
+	if u.StoryPoint <= 0 {
+		u.StoryPoint = defaults.StoryPoint
+	}
+	sizes := defaults.Sizes
+	for size, minutes := range u.Sizes {
+		if minutes > 0 {
+			sizes[strings.ToUpper(size)] = minutes
+		}
+	}
+	u.Sizes = sizes
+	return u
+}
+
+// ParseEffortMinutes converts an effort estimate such as "0.5 day", "2 days",
+// "3h", "SP: 5" or "M" into working minutes. Returns 0 if the effort cannot
+// be parsed.
+func ParseEffortMinutes(effort string) int {
+	return DefaultEffortUnits().Minutes(effort)
+}
+
+// ParseEffort converts an effort estimate into working minutes like
+// ParseEffortMinutes. An empty effort is 0; an effort in an unknown format
+// returns an error wrapping ErrUnknownEffortFormat.
+func ParseEffort(effort string) (int, error) {
+	return DefaultEffortUnits().Parse(effort)
+}
+
+// Minutes is ParseEffortMinutes with these units
+func (u EffortUnits) Minutes(effort string) int {
+	minutes, _ := u.Parse(effort)
+	return minutes
+}
+
+// Parse is ParseEffort with these units
+func (u EffortUnits) Parse(effort string) (int, error) {
+	trimmed := strings.TrimSpace(effort)
+	if trimmed == "" {
+		return 0, nil
+	}
+
+	if minutes, ok := u.Sizes[strings.ToUpper(trimmed)]; ok {
+		return minutes, nil
+	}
+
+	m := storyPointsRegex.FindStringSubmatch(trimmed)
+	if m == nil {
+		m = storyPointsSuffixRegex.FindStringSubmatch(trimmed)
+	}
+	if m != nil {
+		points, err := strconv.ParseFloat(m[1], 64)
+		if err != nil {
+			return 0, fmt.Errorf("%w: %q", ErrUnknownEffortFormat, effort)
+		}
+		return int(math.Round(points * float64(u.StoryPoint))), nil
+	}
+
+	m = effortValueRegex.FindStringSubmatch(trimmed)
+	if len(m) < 3 {
+		return 0, fmt.Errorf("%w: %q", ErrUnknownEffortFormat, effort)
+	}
+
+	value, err := strconv.ParseFloat(m[1], 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %q", ErrUnknownEffortFormat, effort)
+	}
+
+	var unit float64
+	switch strings.ToLower(m[2])[0] {
+	case 'w':
+		unit = minutesPerWeek
+	case 'd':
+		unit = minutesPerDay
+	case 'h':
+		unit = minutesPerHour
+	case 'm':
+		unit = 1
+	}
+
+	return int(math.Round(value * unit)), nil
+}
+
+// ParseEffortDays converts an effort estimate into working days (8 hours).
+// Returns 0 if the effort cannot be parsed.
+func ParseEffortDays(effort string) float64 {
+	return DefaultEffortUnits().Days(effort)
+}
+
+// Days is ParseEffortDays with these units
+func (u EffortUnits) Days(effort string) float64 {
+	return float64(u.Minutes(effort)) / minutesPerDay
+}
+
+// applyConstraints reads the dependency constraint types, e.g.
+// "- T001 (requires-file)", into Constraints. Co-deploy dependencies do not
+// order execution, so they are dropped from Dependencies.
+func applyConstraints(t *Task, content string) {
+	var items []string
+	if m := dependenciesRegex.FindStringSubmatch(content); len(m) > 1 {
+		items = parseCommaSeparated(m[1])
+	} else {
+		inSection := false
+		for _, line := range strings.Split(content, "\n") {
+			trimmed := strings.TrimSpace(line)
+			if strings.HasPrefix(trimmed, "#### Dependencies") {
+				inSection = true
+				continue
+			}
+			if inSection && (strings.HasPrefix(trimmed, "###") || strings.HasPrefix(trimmed, "---")) {
+				break
+			}
+			if inSection && (strings.HasPrefix(trimmed, "- ") || strings.HasPrefix(trimmed, "* ")) {
+				items = append(items, parseCommaSeparated(trimmed[2:])...)
+			}
+		}
+	}
+
+	coDeploy := make(map[string]bool)
+	for _, item := range items {
+		m := constraintItemRegex.FindStringSubmatch(strings.TrimSpace(item))
+		if m == nil {
+			continue
+		}
+		constraintType := strings.ToLower(strings.TrimSpace(m[2]))
+		if !IsValidConstraintType(constraintType) {
+			// Other parentheticals are comments
+			constraintType = ConstraintAfter
+		}
+		for _, id := range expandTaskRange(m[1]) {
+			t.Constraints = append(t.Constraints, Constraint{Type: constraintType, TaskID: id})
+			if constraintType == ConstraintCoDeploy {
+				coDeploy[id] = true
+			}
+		}
+	}
+
+	var deps []string
+	for _, dep := range t.Dependencies {
+		// Inline lists keep the parenthetical
+		if idx := strings.Index(dep, "("); idx > 0 {
+			dep = strings.TrimSpace(dep[:idx])
+		}
+		if !coDeploy[dep] {
+			deps = append(deps, dep)
+		}
+	}
+	t.Dependencies = deps
+}
+
 func parseCommaSeparated(s string) []string {
 	var items []string
 	for _, item := range strings.Split(s, ",") {
-		item = strings.TrimSpace(item)
+		item = UnquoteItem(strings.TrimSpace(item))
 		if item != "" && item != "None" && item != "none" {
 			items = append(items, item)
 		}
@@ -329,6 +556,15 @@ func parseCommaSeparated(s string) []string {
 	return items
 }
 
+// UnquoteItem removes the backticks or quotes around a list item, as in
+// "- `path/to/file.go` (new)"
+func UnquoteItem(item string) string {
+	if len(item) >= 2 && strings.ContainsRune("`\"'", rune(item[0])) && item[len(item)-1] == item[0] {
+		return strings.TrimSpace(item[1 : len(item)-1])
+	}
+	return item
+}
+
 func parseSuccessCriteria(content string) []string {
 	var criteria []string
 	lines := strings.Split(content, "\n")
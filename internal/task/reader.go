@@ -8,29 +8,61 @@ import (
 
 // Reader reads and parses task files
 type Reader struct {
-	basePath string
-	tasksDir string
+	basePath        string
+	tasksDir        string
+	filter          *Filter
+	includeArchived bool
+	effortUnits     EffortUnits
 }
 
 // NewReader creates a new task reader
 func NewReader(basePath string) *Reader {
 	return &Reader{
-		basePath: basePath,
-		tasksDir: filepath.Join(basePath, ".hermes", "tasks"),
+		basePath:    basePath,
+		tasksDir:    filepath.Join(basePath, ".hermes", "tasks"),
+		effortUnits: DefaultEffortUnits(),
 	}
 }
 
+// SetEffortUnits replaces the story point and T-shirt size values used to
+// compute the estimated minutes of the tasks read. Zero values keep the
+// defaults.
+func (r *Reader) SetEffortUnits(units EffortUnits) {
+	r.effortUnits = units.withDefaults()
+}
+
+// EffortUnits returns the units used to compute estimated minutes
+func (r *Reader) EffortUnits() EffortUnits {
+	return r.effortUnits
+}
+
+// SetFilter limits GetNextTask to tasks matching the filter. Dependencies
+// are still resolved against all tasks.
+func (r *Reader) SetFilter(filter *Filter) {
+	r.filter = filter
+}
+
 // HasTasks returns true if tasks directory exists and has files
 func (r *Reader) HasTasks() bool {
 	files, err := r.GetFeatureFiles()
 	return err == nil && len(files) > 0
 }
 
-// GetFeatureFiles returns all feature files sorted by name
+// GetFeatureFiles returns all feature files sorted by name, followed by the
+// archived ones if SetIncludeArchived is set
 func (r *Reader) GetFeatureFiles() ([]string, error) {
+	files := featureFilesIn(r.tasksDir)
+	if r.includeArchived {
+		files = append(files, r.archivedFeatureFiles()...)
+	}
+	return files, nil
+}
+
+// featureFilesIn returns the feature files of a directory sorted by name
+func featureFilesIn(dir string) []string {
 	// Try both patterns: XXX-*.md and FXXX-*.md
-	pattern1 := filepath.Join(r.tasksDir, "[0-9][0-9][0-9]-*.md")
-	pattern2 := filepath.Join(r.tasksDir, "F[0-9][0-9][0-9]-*.md")
+	pattern1 := filepath.Join(dir, "[0-9][0-9][0-9]-*.md")
+	pattern2 := filepath.Join(dir, "F[0-9][0-9][0-9]-*.md")
 
 	files1, _ := filepath.Glob(pattern1)
 	files2, _ := filepath.Glob(pattern2)
@@ -50,7 +82,7 @@ func (r *Reader) GetFeatureFiles() ([]string, error) {
 	}
 
 	sort.Strings(files)
-	return files, nil
+	return files
 }
 
 // ReadFeature reads and parses a single feature file
@@ -59,7 +91,7 @@ func (r *Reader) ReadFeature(filePath string) (*Feature, error) {
 	if err != nil {
 		return nil, err
 	}
-	return ParseFeature(string(content), filePath)
+	return parseFeature(string(content), filePath, r.effortUnits)
 }
 
 // GetAllFeatures returns all features
@@ -167,9 +199,14 @@ func (r *Reader) GetNextTask() (*Task, error) {
 		return nil, err
 	}
 
-	// Build completed tasks map
+	// Build completed tasks map. Dependencies on archived tasks count as
+	// met when those tasks were completed.
+	done := tasks
+	if !r.includeArchived {
+		done = append(done, r.GetArchivedTasks()...)
+	}
 	completed := make(map[string]bool)
-	for _, t := range tasks {
+	for _, t := range done {
 		if t.Status == StatusCompleted {
 			completed[t.ID] = true
 		}
@@ -178,7 +215,7 @@ func (r *Reader) GetNextTask() (*Task, error) {
 	// Find first available task by priority
 	var candidates []Task
 	for _, t := range tasks {
-		if t.CanStart(completed) {
+		if t.CanStart(completed) && r.filter.Match(t) {
 			candidates = append(candidates, t)
 		}
 	}
@@ -202,6 +239,16 @@ func (r *Reader) GetProgress() (*Progress, error) {
 		return nil, err
 	}
 
+	return newProgress(tasks), nil
+}
+
+// Progress returns the progress of the feature's tasks
+func (f *Feature) Progress() *Progress {
+	return newProgress(f.Tasks)
+}
+
+// newProgress counts the tasks by status
+func newProgress(tasks []Task) *Progress {
 	p := &Progress{Total: len(tasks)}
 	for _, t := range tasks {
 		switch t.Status {
@@ -220,5 +267,5 @@ func (r *Reader) GetProgress() (*Progress, error) {
 		p.Percentage = float64(p.Completed) / float64(p.Total) * 100
 	}
 
-	return p, nil
+	return p
 }
@@ -3,13 +3,23 @@ package task
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 	"regexp"
 	"strings"
+	"time"
+)
+
+const (
+	blockedReasonPrefix  = "**Blocked Reason:**"
+	completionNotePrefix = "**Completion Note:**"
+	priorityPrefix       = "**Priority:**"
+	effortPrefix         = "**Estimated Effort:**"
 )
 
 // StatusUpdater updates task status in files
 type StatusUpdater struct {
 	basePath string
+	session  string
 }
 
 // NewStatusUpdater creates a new status updater
@@ -17,6 +27,27 @@ func NewStatusUpdater(basePath string) *StatusUpdater {
 	return &StatusUpdater{basePath: basePath}
 }
 
+// SetSession sets the run session recorded with each status change in the
+// task history
+func (u *StatusUpdater) SetSession(sessionID string) {
+	u.session = sessionID
+}
+
+// recordChange adds a status change of a task to the task history. The
+// status update itself has succeeded, so failing to record it is ignored.
+func (u *StatusUpdater) recordChange(taskID string, from, to Status) {
+	if from == to {
+		return
+	}
+	appendHistory(HistoryPath(u.basePath), HistoryEntry{
+		TaskID:    taskID,
+		From:      from,
+		To:        to,
+		SessionID: u.session,
+		Timestamp: time.Now(),
+	})
+}
+
 // UpdateTaskStatus updates the status of a task in its feature file
 func (u *StatusUpdater) UpdateTaskStatus(taskID string, newStatus Status) error {
 	reader := NewReader(u.basePath)
@@ -36,8 +67,17 @@ func (u *StatusUpdater) UpdateTaskStatus(taskID string, newStatus Status) error
 			continue
 		}
 
+		var from Status
+		if start, end, ok := findTaskSection(contentStr, taskID); ok {
+			from = sectionStatus(contentStr[start:end])
+		}
+
 		updated := updateTaskStatusInContent(contentStr, taskID, newStatus)
-		return os.WriteFile(file, []byte(updated), 0644)
+		if err := writeFileAtomic(file, []byte(updated)); err != nil {
+			return err
+		}
+		u.recordChange(taskID, from, newStatus)
+		return nil
 	}
 
 	return fmt.Errorf("task %s not found", taskID)
@@ -62,7 +102,7 @@ func (u *StatusUpdater) UpdateFeatureStatus(featureID string, newStatus Status)
 		}
 
 		updated := updateFeatureStatusInContent(string(content), newStatus)
-		return os.WriteFile(f.FilePath, []byte(updated), 0644)
+		return writeFileAtomic(f.FilePath, []byte(updated))
 	}
 
 	return fmt.Errorf("feature %s not found", featureID)
@@ -135,3 +175,138 @@ func (u *StatusUpdater) MarkTaskCompleted(taskID string) error {
 func (u *StatusUpdater) MarkTaskBlocked(taskID string) error {
 	return u.UpdateTaskStatus(taskID, StatusBlocked)
 }
+
+// BlockTask marks a task as BLOCKED and records a timestamped reason
+func (u *StatusUpdater) BlockTask(taskID, reason string) error {
+	value := fmt.Sprintf("%s (%s)", strings.TrimSpace(reason), time.Now().Format("2006-01-02 15:04"))
+	return u.updateTaskSection(taskID, func(section string) string {
+		section = updateTaskStatusInContent(section, taskID, StatusBlocked)
+		return setBlockedReason(section, value)
+	})
+}
+
+// UnblockTask clears a task's block reason and sets it back to NOT_STARTED
+func (u *StatusUpdater) UnblockTask(taskID string) error {
+	return u.updateTaskSection(taskID, func(section string) string {
+		section = updateTaskStatusInContent(section, taskID, StatusNotStarted)
+		return setBlockedReason(section, "")
+	})
+}
+
+// CompleteTask marks a task as COMPLETED by hand, clearing a block reason.
+// A non-empty note is recorded as the task's Completion Note.
+func (u *StatusUpdater) CompleteTask(taskID, note string) error {
+	if note = strings.TrimSpace(note); note != "" {
+		note = fmt.Sprintf("%s (%s)", note, time.Now().Format("2006-01-02 15:04"))
+	}
+	return u.updateTaskSection(taskID, func(section string) string {
+		section = updateTaskStatusInContent(section, taskID, StatusCompleted)
+		section = setBlockedReason(section, "")
+		return setDetailLine(section, completionNotePrefix, note)
+	})
+}
+
+// SetTaskPriority updates the Priority line of a task, keeping the
+// "P1 - CRITICAL" form if the file already uses it
+func (u *StatusUpdater) SetTaskPriority(taskID string, priority Priority) error {
+	if !IsValidPriority(priority) {
+		return fmt.Errorf("invalid priority: %s", priority)
+	}
+	return u.updateTaskSection(taskID, func(section string) string {
+		return setPriorityLine(section, priority)
+	})
+}
+
+// updateTaskSection rewrites a single task section in its feature file
+func (u *StatusUpdater) updateTaskSection(taskID string, update func(string) string) error {
+	file, err := NewWriter(u.basePath).FindTaskFile(taskID)
+	if err != nil {
+		return err
+	}
+
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+
+	contentStr := string(content)
+	start, end, _ := findTaskSection(contentStr, taskID)
+	section := update(contentStr[start:end])
+	updated := contentStr[:start] + section + contentStr[end:]
+
+	if err := writeFileAtomic(file, []byte(updated)); err != nil {
+		return err
+	}
+	u.recordChange(taskID, sectionStatus(contentStr[start:end]), sectionStatus(section))
+	return nil
+}
+
+// setBlockedReason replaces the Blocked Reason line of a task section, placing
+// it after the status line. An empty value removes the line.
+func setBlockedReason(section, value string) string {
+	return setDetailLine(section, blockedReasonPrefix, value)
+}
+
+// setDetailLine replaces the line starting with prefix in a task section,
+// placing it after the status line. An empty value removes the line.
+func setDetailLine(section, prefix, value string) string {
+	lines := strings.Split(section, "\n")
+	var result []string
+	inserted := false
+
+	for _, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), prefix) {
+			continue
+		}
+		result = append(result, line)
+		if value != "" && !inserted && strings.Contains(line, "**Status:**") {
+			result = append(result, prefix+" "+value)
+			inserted = true
+		}
+	}
+
+	return strings.Join(result, "\n")
+}
+
+// setPriorityLine replaces the value of the first Priority line in a task section
+func setPriorityLine(section string, priority Priority) string {
+	lines := strings.Split(section, "\n")
+	for i, line := range lines {
+		idx := strings.Index(line, priorityPrefix)
+		if idx < 0 {
+			continue
+		}
+		value := string(priority)
+		if strings.Contains(line[idx+len(priorityPrefix):], " - ") {
+			value = fmt.Sprintf("%s - %s", priority, priority.Label())
+		}
+		lines[i] = line[:idx] + priorityPrefix + " " + value
+		return strings.Join(lines, "\n")
+	}
+	return section
+}
+
+// writeFileAtomic writes data to a temporary file and renames it into place
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := os.Chmod(tmpPath, 0644); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+
+	return os.Rename(tmpPath, path)
+}
@@ -1,9 +1,13 @@
 package task
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 	"testing"
+	"time"
 )
 
 const testFeatureContent = `# Feature 1: User Authentication
@@ -203,6 +207,9 @@ func TestParseTasks(t *testing.T) {
 	if task1.EstimatedEffort != "2 days" {
 		t.Errorf("expected EstimatedEffort = '2 days', got %s", task1.EstimatedEffort)
 	}
+	if task1.EstimatedMinutes != 960 {
+		t.Errorf("expected EstimatedMinutes = 960, got %d", task1.EstimatedMinutes)
+	}
 	if task1.Description == "" {
 		t.Error("expected Description to be populated")
 	}
@@ -232,6 +239,88 @@ func TestParseTasks(t *testing.T) {
 	}
 }
 
+func TestParseEffortMinutes(t *testing.T) {
+	tests := []struct {
+		effort   string
+		expected int
+	}{
+		{"0.5 day", 240},
+		{"1 day", 480},
+		{"2 days", 960},
+		{"1.5 days", 720},
+		{"2d", 960},
+		{"3h", 180},
+		{"3 hours", 180},
+		{"1 hr", 60},
+		{"1.5h", 90},
+		{"45m", 45},
+		{"30 min", 30},
+		{"1 week", 2400},
+		{"2 DAYS", 960},
+		{"  4h  ", 240},
+		{"", 0},
+		{"unknown", 0},
+		{"a few days", 0},
+		{"SP: 5", 1200},
+		{"Story Points: 13", 3120},
+		{"Fibonacci: 3", 720},
+		{"sp 2", 480},
+		{"8 SP", 1920},
+		{"XS", 60},
+		{"m", 480},
+		{"XL", 2400},
+	}
+
+	for _, tt := range tests {
+		if got := ParseEffortMinutes(tt.effort); got != tt.expected {
+			t.Errorf("ParseEffortMinutes(%q) = %d, expected %d", tt.effort, got, tt.expected)
+		}
+	}
+}
+
+func TestParseEffort(t *testing.T) {
+	if _, err := ParseEffort("a few days"); !errors.Is(err, ErrUnknownEffortFormat) {
+		t.Errorf("expected ErrUnknownEffortFormat, got %v", err)
+	} else if !strings.Contains(err.Error(), "a few days") {
+		t.Errorf("error should contain the effort: %v", err)
+	}
+	if minutes, err := ParseEffort(""); minutes != 0 || err != nil {
+		t.Errorf("ParseEffort(\"\") = %d, %v; expected 0, nil", minutes, err)
+	}
+
+	// Zero values keep the defaults
+	dir := t.TempDir()
+	tasksDir := filepath.Join(dir, ".hermes", "tasks")
+	if err := os.MkdirAll(tasksDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	content := "# Feature 1: Demo\n**Feature ID:** F001\n\n### T001: Task\n**Estimated Effort:** SP: 2\n"
+	if err := os.WriteFile(filepath.Join(tasksDir, "001-demo.md"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	reader := NewReader(dir)
+	reader.SetEffortUnits(EffortUnits{StoryPoint: 60, Sizes: map[string]int{"xl": 4800}})
+	units := reader.EffortUnits()
+	for effort, expected := range map[string]int{"SP: 5": 300, "XL": 4800, "M": 480, "2h": 120} {
+		if got, err := units.Parse(effort); err != nil || got != expected {
+			t.Errorf("Parse(%q) = %d, %v; expected %d", effort, got, err, expected)
+		}
+	}
+
+	tasks, err := reader.GetAllTasks()
+	if err != nil || len(tasks) != 1 {
+		t.Fatalf("GetAllTasks() = %v, %v", tasks, err)
+	}
+	if tasks[0].EstimatedMinutes != 120 {
+		t.Errorf("expected 120 estimated minutes, got %d", tasks[0].EstimatedMinutes)
+	}
+
+	// The package functions are not affected by a reader's units
+	if got := ParseEffortMinutes("SP: 5"); got != 1200 {
+		t.Errorf("ParseEffortMinutes(\"SP: 5\") = %d; expected 1200", got)
+	}
+}
+
 func TestReader(t *testing.T) {
 	tmpDir := setupTestDir(t)
 	defer os.RemoveAll(tmpDir)
@@ -318,6 +407,28 @@ func TestGetProgress(t *testing.T) {
 	}
 }
 
+func TestFeatureProgress(t *testing.T) {
+	tmpDir := setupTestDir(t)
+	defer os.RemoveAll(tmpDir)
+
+	feature, err := NewReader(tmpDir).GetFeatureByID("F001")
+	if err != nil || feature == nil {
+		t.Fatalf("GetFeatureByID failed: %v", err)
+	}
+
+	progress := feature.Progress()
+	if progress.Total != 3 || progress.Completed != 1 || progress.Blocked != 1 {
+		t.Errorf("unexpected progress: %+v", progress)
+	}
+	if progress.Percentage < 33.3 || progress.Percentage > 33.4 {
+		t.Errorf("expected 33.3%%, got %.1f", progress.Percentage)
+	}
+
+	if empty := (&Feature{}).Progress(); empty.Total != 0 || empty.Percentage != 0 {
+		t.Errorf("expected empty progress, got %+v", empty)
+	}
+}
+
 func TestStatusUpdater(t *testing.T) {
 	tmpDir := setupTestDir(t)
 	defer os.RemoveAll(tmpDir)
@@ -341,6 +452,82 @@ func TestStatusUpdater(t *testing.T) {
 	}
 }
 
+func TestBlockUnblockTask(t *testing.T) {
+	tmpDir := setupTestDir(t)
+	defer os.RemoveAll(tmpDir)
+
+	updater := NewStatusUpdater(tmpDir)
+	reader := NewReader(tmpDir)
+
+	if err := updater.BlockTask("T002", "Waiting for API keys"); err != nil {
+		t.Fatal(err)
+	}
+
+	blocked, _ := reader.GetTaskByID("T002")
+	if blocked.Status != StatusBlocked {
+		t.Errorf("expected Status = BLOCKED, got %s", blocked.Status)
+	}
+	if !strings.HasPrefix(blocked.BlockedReason, "Waiting for API keys (") {
+		t.Errorf("expected timestamped reason, got %q", blocked.BlockedReason)
+	}
+	if blocked.Name != "Add password hashing" || len(blocked.Dependencies) != 1 {
+		t.Errorf("blocking should not change other fields: %+v", blocked)
+	}
+
+	// Other tasks are untouched
+	t1, _ := reader.GetTaskByID("T001")
+	if t1.Status != StatusCompleted || t1.BlockedReason != "" {
+		t.Errorf("T001 should be unchanged, got %s %q", t1.Status, t1.BlockedReason)
+	}
+
+	// Blocked tasks are skipped
+	if next, _ := reader.GetNextTask(); next != nil && next.ID == "T002" {
+		t.Error("blocked task should not be returned by GetNextTask")
+	}
+
+	// Atomic write leaves no temporary files behind
+	entries, _ := os.ReadDir(filepath.Join(tmpDir, ".hermes", "tasks"))
+	if len(entries) != 1 {
+		t.Errorf("expected only the feature file, got %d entries", len(entries))
+	}
+
+	if err := updater.UnblockTask("T002"); err != nil {
+		t.Fatal(err)
+	}
+
+	unblocked, _ := reader.GetTaskByID("T002")
+	if unblocked.Status != StatusNotStarted || unblocked.BlockedReason != "" {
+		t.Errorf("expected NOT_STARTED without reason, got %s %q", unblocked.Status, unblocked.BlockedReason)
+	}
+	if next, _ := reader.GetNextTask(); next == nil || next.ID != "T002" {
+		t.Errorf("expected T002 to be next after unblocking, got %v", next)
+	}
+}
+
+func TestCompleteTask(t *testing.T) {
+	tmpDir := setupTestDir(t)
+	defer os.RemoveAll(tmpDir)
+
+	updater := NewStatusUpdater(tmpDir)
+	reader := NewReader(tmpDir)
+
+	updater.BlockTask("T002", "Waiting for API keys")
+	if err := updater.CompleteTask("T002", "Finished by hand"); err != nil {
+		t.Fatal(err)
+	}
+
+	completed, _ := reader.GetTaskByID("T002")
+	if completed.Status != StatusCompleted || completed.BlockedReason != "" {
+		t.Errorf("expected COMPLETED without block reason, got %s %q", completed.Status, completed.BlockedReason)
+	}
+	if !strings.HasPrefix(completed.CompletionNote, "Finished by hand (") {
+		t.Errorf("expected timestamped completion note, got %q", completed.CompletionNote)
+	}
+	if !strings.Contains(FormatTask(completed), "**Completion Note:** Finished by hand") {
+		t.Error("expected FormatTask to write the completion note")
+	}
+}
+
 func TestCanStart(t *testing.T) {
 	completed := map[string]bool{"T001": true}
 
@@ -359,3 +546,1031 @@ func TestCanStart(t *testing.T) {
 		t.Error("T004 should NOT be able to start (already in progress)")
 	}
 }
+
+func TestWriterReadTaskSection(t *testing.T) {
+	tmpDir := setupTestDir(t)
+	defer os.RemoveAll(tmpDir)
+
+	writer := NewWriter(tmpDir)
+	section, file, err := writer.ReadTaskSection("T002")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if filepath.Base(file) != "001-user-auth.md" {
+		t.Errorf("expected 001-user-auth.md, got %s", file)
+	}
+	if !strings.HasPrefix(section, "### T002: Add password hashing") {
+		t.Errorf("section should start with task header, got %q", section[:30])
+	}
+	if strings.Contains(section, "---") || strings.Contains(section, "T003:") {
+		t.Error("section should stop before separator and next task")
+	}
+
+	// Last task stops before the next top-level section
+	section, _, _ = writer.ReadTaskSection("T003")
+	if strings.Contains(section, "## Performance Targets") {
+		t.Error("last task section should not include feature sections")
+	}
+}
+
+func TestWriterWriteTask(t *testing.T) {
+	tmpDir := setupTestDir(t)
+	defer os.RemoveAll(tmpDir)
+
+	reader := NewReader(tmpDir)
+	original, _ := reader.GetTaskByID("T002")
+
+	edited := *original
+	if err := ApplyField(&edited, "status", "in_progress"); err != nil {
+		t.Fatal(err)
+	}
+	if err := ApplyField(&edited, "priority", "p3"); err != nil {
+		t.Fatal(err)
+	}
+
+	writer := NewWriter(tmpDir)
+	if err := writer.WriteTask(&edited); err != nil {
+		t.Fatal(err)
+	}
+
+	updated, _ := reader.GetTaskByID("T002")
+	if updated.Status != StatusInProgress || updated.Priority != PriorityP3 {
+		t.Errorf("expected IN_PROGRESS/P3, got %s/%s", updated.Status, updated.Priority)
+	}
+	if updated.Description != original.Description {
+		t.Errorf("description should be preserved, got %q", updated.Description)
+	}
+
+	// Other tasks remain intact
+	tasks, _ := reader.GetAllTasks()
+	if len(tasks) != 3 {
+		t.Fatalf("expected 3 tasks, got %d", len(tasks))
+	}
+	other, _ := reader.GetTaskByID("T003")
+	if other.Status != StatusBlocked || len(other.Dependencies) != 2 {
+		t.Errorf("T003 should be unchanged, got %+v", other)
+	}
+
+	changes := DiffTasks(original, updated)
+	if len(changes) != 2 {
+		t.Errorf("expected 2 changes, got %v", changes)
+	}
+}
+
+func TestWriterUpdateTask(t *testing.T) {
+	tmpDir := setupTestDir(t)
+	defer os.RemoveAll(tmpDir)
+
+	writer := NewWriter(tmpDir)
+	file, err := writer.FindTaskFile("T002")
+	if err != nil {
+		t.Fatal(err)
+	}
+	before, _ := os.ReadFile(file)
+
+	reader := NewReader(tmpDir)
+	original, _ := reader.GetTaskByID("T002")
+	edited := *original
+	edited.Description = "Hash passwords with bcrypt"
+	edited.SuccessCriteria = []string{"Cost factor is 12", "Old hashes still verify"}
+
+	if err := writer.UpdateTask(file, &edited); err != nil {
+		t.Fatal(err)
+	}
+
+	updated, _ := reader.GetTaskByID("T002")
+	if updated.Description != edited.Description {
+		t.Errorf("expected new description, got %q", updated.Description)
+	}
+	if len(updated.SuccessCriteria) != 2 || updated.SuccessCriteria[1] != "Old hashes still verify" {
+		t.Errorf("expected new success criteria, got %v", updated.SuccessCriteria)
+	}
+
+	// Markdown outside the section is preserved
+	after, _ := os.ReadFile(file)
+	oldSection, _, _ := NewWriter(tmpDir).ReadTaskSection("T001")
+	if !strings.Contains(string(after), oldSection) {
+		t.Error("T001 section should be unchanged")
+	}
+	beforeTail := string(before)[strings.Index(string(before), "### T003:"):]
+	if !strings.HasSuffix(string(after), beforeTail) {
+		t.Error("content after the task should be unchanged")
+	}
+
+	edited.ID = "T999"
+	if err := writer.UpdateTask(file, &edited); err == nil {
+		t.Error("expected error for unknown task")
+	}
+}
+
+// prdTemplateFeature is a feature file in the layout 'hermes prd' asks for,
+// with content FormatTask does not model
+const prdTemplateFeature = `# Feature 1: Payments
+
+**Feature ID:** F001
+**Status:** NOT_STARTED
+
+## Tasks
+
+### T001: Charge cards
+
+**Status:** IN_PROGRESS
+**Priority:** P1 - CRITICAL
+**Estimated Effort:** 2 days
+
+#### Description
+
+Charge saved cards through the gateway.
+
+#### Files to Touch
+
+- ` + "`services/pay/charge.go`" + ` (new)
+- ` + "`services/pay/client.go`" + ` (update)
+
+#### Dependencies
+
+- None (first task)
+
+#### Success Criteria
+
+- [x] Gateway client configured
+- [ ] Declines are retried once
+
+#### Notes
+
+Keep the gateway timeout under 5s.
+
+---
+`
+
+func TestWriterPreservesSection(t *testing.T) {
+	tmpDir := t.TempDir()
+	tasksDir := filepath.Join(tmpDir, ".hermes", "tasks")
+	os.MkdirAll(tasksDir, 0755)
+	file := filepath.Join(tasksDir, "001-payments.md")
+	os.WriteFile(file, []byte(prdTemplateFeature), 0644)
+
+	reader := NewReader(tmpDir)
+	writer := NewWriter(tmpDir)
+	original, err := reader.GetTaskByID("T001")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Changing one field rewrites only its line
+	edited := *original
+	if err := ApplyField(&edited, "priority", "p2"); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.WriteTask(&edited); err != nil {
+		t.Fatal(err)
+	}
+	after, _ := os.ReadFile(file)
+	want := strings.Replace(prdTemplateFeature, "**Priority:** P1 - CRITICAL", "**Priority:** P2 - HIGH", 1)
+	if string(after) != want {
+		t.Errorf("expected only the priority line to change, got:\n%s", after)
+	}
+
+	// Editing a subsection keeps the others, and kept list items keep their
+	// checkboxes
+	current, _ := reader.GetTaskByID("T001")
+	edited = *current
+	edited.Description = "Charge saved cards and wallets."
+	edited.SuccessCriteria = append(edited.SuccessCriteria, "Receipts are emailed")
+	if err := writer.UpdateTask(file, &edited); err != nil {
+		t.Fatal(err)
+	}
+	after, _ = os.ReadFile(file)
+	want = strings.Replace(want, "Charge saved cards through the gateway.", "Charge saved cards and wallets.", 1)
+	want = strings.Replace(want, "- [ ] Declines are retried once\n", "- [ ] Declines are retried once\n- [ ] Receipts are emailed\n", 1)
+	if string(after) != want {
+		t.Errorf("expected only the description and criteria to change, got:\n%s", after)
+	}
+
+	// Removing a file keeps the annotation of the other
+	current, _ = reader.GetTaskByID("T001")
+	edited = *current
+	edited.FilesToTouch = edited.FilesToTouch[1:]
+	if err := writer.UpdateTask(file, &edited); err != nil {
+		t.Fatal(err)
+	}
+	after, _ = os.ReadFile(file)
+	want = strings.Replace(want, "- `services/pay/charge.go` (new)\n", "", 1)
+	if string(after) != want {
+		t.Errorf("expected only the removed file to change, got:\n%s", after)
+	}
+}
+
+func TestValidateTask(t *testing.T) {
+	feature, _ := ParseFeature(testFeatureContent, "test.md")
+	all := feature.Tasks
+
+	valid := all[1]
+	if err := ValidateTask(&valid, all); err != nil {
+		t.Errorf("expected valid task, got %v", err)
+	}
+
+	badStatus := all[1]
+	badStatus.Status = "DONE"
+	if err := ValidateTask(&badStatus, all); err == nil {
+		t.Error("expected error for invalid status")
+	}
+
+	missingDep := all[1]
+	missingDep.Dependencies = []string{"T999"}
+	if err := ValidateTask(&missingDep, all); err == nil {
+		t.Error("expected error for missing dependency")
+	}
+
+	cycle := all[0]
+	cycle.Dependencies = []string{"T003"}
+	if err := ValidateTask(&cycle, all); err == nil {
+		t.Error("expected error for dependency cycle")
+	}
+
+	if err := ApplyField(&valid, "unknown", "x"); err == nil {
+		t.Error("expected error for unknown field")
+	}
+}
+
+func TestParseTaskSection(t *testing.T) {
+	parsed, err := ParseTaskSection(FormatTask(&Task{
+		ID:           "T010",
+		Name:         "Round trip",
+		Status:       StatusBlocked,
+		Priority:     PriorityP4,
+		Description:  "Some description",
+		FilesToTouch: []string{"a.go", "b.go"},
+		Dependencies: []string{"T001"},
+	}), "F001")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if parsed.ID != "T010" || parsed.Status != StatusBlocked || parsed.Priority != PriorityP4 {
+		t.Errorf("unexpected parsed task: %+v", parsed)
+	}
+	if len(parsed.FilesToTouch) != 2 || len(parsed.Dependencies) != 1 {
+		t.Errorf("lists not round-tripped: %+v", parsed)
+	}
+
+	if _, err := ParseTaskSection("no header here", "F001"); err == nil {
+		t.Error("expected error without task header")
+	}
+}
+
+func TestParseConstraints(t *testing.T) {
+	content := `### T005: Deploy
+
+**Status:** NOT_STARTED
+
+#### Dependencies
+
+- T001
+- T002 (requires-file)
+- T003 (co-deploy)
+- T004 (schema must exist)
+
+#### Success Criteria
+
+- T009 (co-deploy) is not a dependency
+`
+	parsed, err := ParseTaskSection(content, "F001")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Join(parsed.Dependencies, ",") != "T001,T002,T004" {
+		t.Errorf("expected co-deploy dependency to be dropped, got %v", parsed.Dependencies)
+	}
+	want := []Constraint{
+		{Type: ConstraintAfter, TaskID: "T001"},
+		{Type: ConstraintRequiresFile, TaskID: "T002"},
+		{Type: ConstraintCoDeploy, TaskID: "T003"},
+		{Type: ConstraintAfter, TaskID: "T004"},
+	}
+	if len(parsed.Constraints) != len(want) {
+		t.Fatalf("expected %d constraints, got %+v", len(want), parsed.Constraints)
+	}
+	for i, c := range want {
+		if parsed.Constraints[i] != c {
+			t.Errorf("constraint %d: expected %+v, got %+v", i, c, parsed.Constraints[i])
+		}
+	}
+
+	// Inline form
+	inline, _ := ParseTaskSection("### T006: Inline\n\n**Dependencies:** T001 (requires-file), T002\n", "F001")
+	if strings.Join(inline.Dependencies, ",") != "T001,T002" || len(inline.ConstraintIDs(ConstraintRequiresFile)) != 1 {
+		t.Errorf("unexpected inline constraints: %v %+v", inline.Dependencies, inline.Constraints)
+	}
+
+	// Constraint types survive a write
+	again, _ := ParseTaskSection(FormatTask(parsed), "F001")
+	if len(again.Constraints) != 4 || again.ConstraintIDs(ConstraintCoDeploy)[0] != "T003" || again.ConstraintIDs(ConstraintRequiresFile)[0] != "T002" {
+		t.Errorf("constraints not round-tripped: %+v", again.Constraints)
+	}
+}
+
+const priorityFeatureContent = `# Feature 2: Reports
+
+**Feature ID:** F002
+**Priority:** P2 - HIGH
+**Status:** NOT_STARTED
+
+## Tasks
+
+### T010: Export CSV
+
+**Status:** NOT_STARTED
+**Priority:** P1 - CRITICAL
+
+---
+
+### T011: Export PDF
+
+**Status:** NOT_STARTED
+**Priority:** P3
+
+---
+`
+
+func TestParsePriority(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected Priority
+		valid    bool
+	}{
+		{"P1", PriorityP1, true},
+		{"p3", PriorityP3, true},
+		{"P1 - CRITICAL", PriorityP1, true},
+		{"low", PriorityP4, true},
+		{"P5", "", false},
+		{"urgent", "", false},
+	}
+
+	for _, tt := range tests {
+		p, err := ParsePriority(tt.input)
+		if (err == nil) != tt.valid || p != tt.expected {
+			t.Errorf("ParsePriority(%q) = %q, %v; want %q (valid=%v)", tt.input, p, err, tt.expected, tt.valid)
+		}
+	}
+}
+
+func TestSetTaskPriorityChangesNextTask(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "hermes-task-test-*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	tasksDir := filepath.Join(tmpDir, ".hermes", "tasks")
+	os.MkdirAll(tasksDir, 0755)
+	featurePath := filepath.Join(tasksDir, "002-reports.md")
+	os.WriteFile(featurePath, []byte(priorityFeatureContent), 0644)
+
+	reader := NewReader(tmpDir)
+	next, _ := reader.GetNextTask()
+	if next == nil || next.ID != "T010" {
+		t.Fatalf("expected T010 first, got %v", next)
+	}
+
+	updater := NewStatusUpdater(tmpDir)
+	if err := updater.SetTaskPriority("T010", PriorityP4); err != nil {
+		t.Fatal(err)
+	}
+	if err := updater.SetTaskPriority("T011", PriorityP2); err != nil {
+		t.Fatal(err)
+	}
+
+	next, _ = reader.GetNextTask()
+	if next == nil || next.ID != "T011" {
+		t.Fatalf("expected T011 after priority change, got %v", next)
+	}
+
+	content, _ := os.ReadFile(featurePath)
+	if !strings.Contains(string(content), "**Priority:** P4 - LOW") {
+		t.Error("expected labelled form to be preserved")
+	}
+	if !strings.Contains(string(content), "**Priority:** P2\n") {
+		t.Error("expected short form to be preserved")
+	}
+
+	if err := NewWriter(tmpDir).SortTasksByPriority(featurePath); err != nil {
+		t.Fatal(err)
+	}
+	feature, _ := reader.ReadFeature(featurePath)
+	if len(feature.Tasks) != 2 || feature.Tasks[0].ID != "T011" || feature.Tasks[1].ID != "T010" {
+		t.Errorf("expected tasks sorted as T011, T010, got %+v", feature.Tasks)
+	}
+	if feature.Priority != PriorityP2 {
+		t.Errorf("expected feature priority unchanged, got %s", feature.Priority)
+	}
+}
+
+const unsortedFeatureContent = `# Feature 3: Search
+
+**Feature ID:** F003
+**Status:** NOT_STARTED
+
+## Contents
+
+- T022: Ranking
+- T020: Index
+- T021: Query
+
+## Tasks
+
+### T022: Ranking
+
+**Status:** NOT_STARTED
+**Priority:** P3
+**Dependencies:** T021
+
+---
+
+### T020: Index
+
+**Status:** NOT_STARTED
+**Priority:** P2
+**Dependencies:** None
+
+---
+
+### T021: Query
+
+**Status:** NOT_STARTED
+**Priority:** P1
+**Dependencies:** T020
+
+## Notes
+
+Keep ranking pluggable.
+`
+
+func TestSortTasks(t *testing.T) {
+	tmpDir := t.TempDir()
+	featurePath := filepath.Join(tmpDir, "003-search.md")
+	os.WriteFile(featurePath, []byte(unsortedFeatureContent), 0644)
+	writer := NewWriter(tmpDir)
+
+	before, after, err := writer.SortTasks(featurePath, "dependency")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(before, ",") != "T022,T020,T021" || strings.Join(after, ",") != "T020,T021,T022" {
+		t.Errorf("unexpected order %v -> %v", before, after)
+	}
+
+	content, _ := os.ReadFile(featurePath)
+	feature, err := ParseFeature(string(content), featurePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, tk := range feature.Tasks {
+		ids = append(ids, tk.ID)
+	}
+	if strings.Join(ids, ",") != "T020,T021,T022" {
+		t.Errorf("expected tasks in dependency order, got %v", ids)
+	}
+	for _, kept := range []string{"## Contents\n\n- T022: Ranking", "**Dependencies:** T020\n\n---\n\n### T022", "**Dependencies:** T021\n\n## Notes\n\nKeep ranking pluggable.\n"} {
+		if !strings.Contains(string(content), kept) {
+			t.Errorf("expected %q in sorted file:\n%s", kept, content)
+		}
+	}
+
+	if _, after, _ = writer.SortTasks(featurePath, "priority"); strings.Join(after, ",") != "T021,T020,T022" {
+		t.Errorf("unexpected priority order %v", after)
+	}
+	if _, after, _ = writer.SortTasks(featurePath, "name"); strings.Join(after, ",") != "T020,T021,T022" {
+		t.Errorf("unexpected name order %v", after)
+	}
+	if _, _, err := writer.SortTasks(featurePath, "effort"); err == nil {
+		t.Error("expected error for unknown sort key")
+	}
+
+	cyclic := strings.Replace(unsortedFeatureContent, "**Dependencies:** None", "**Dependencies:** T022", 1)
+	os.WriteFile(featurePath, []byte(cyclic), 0644)
+	if _, _, err := writer.SortTasks(featurePath, "dependency"); err == nil {
+		t.Error("expected error for circular dependencies")
+	}
+	if content, _ := os.ReadFile(featurePath); string(content) != cyclic {
+		t.Error("expected file unchanged after failed sort")
+	}
+}
+
+func TestParseFilter(t *testing.T) {
+	f, err := ParseFilter([]string{"feature=f001", "task=T002"}, []string{"feature=F003"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(f.Features) != 1 || f.Features[0] != "F001" {
+		t.Errorf("expected feature F001, got %v", f.Features)
+	}
+	if len(f.Tasks) != 1 || f.Tasks[0] != "T002" {
+		t.Errorf("expected task T002, got %v", f.Tasks)
+	}
+	if f.String() != "feature=F001, task=T002, exclude feature=F003" {
+		t.Errorf("unexpected description %q", f.String())
+	}
+
+	for _, bad := range []string{"F001", "epic=E1", "feature="} {
+		if _, err := ParseFilter([]string{bad}, nil); err == nil {
+			t.Errorf("expected error for filter %q", bad)
+		}
+	}
+	if _, err := ParseFilter(nil, []string{"task=T001"}); err == nil {
+		t.Error("expected error for task exclude")
+	}
+
+	empty, _ := ParseFilter(nil, nil)
+	if !empty.IsEmpty() || !empty.Match(Task{ID: "T001", FeatureID: "F001"}) {
+		t.Error("expected empty filter to match everything")
+	}
+}
+
+func TestScopeFilter(t *testing.T) {
+	auth := &Task{ID: "T001", FilesToTouch: []string{"./services/auth/login.go", "README.md"}}
+	billing := &Task{ID: "T002", FilesToTouch: []string{"services/billing/invoice.go"}}
+	glob := &Task{ID: "T003", FilesToTouch: []string{"services/*/config.yaml"}}
+	prefix := &Task{ID: "T004", FilesToTouch: []string{"services/authz/policy.go"}}
+	none := &Task{ID: "T005"}
+	tasks := []*Task{auth, billing, glob, prefix, none}
+
+	tests := []struct {
+		scope []string
+		want  []string
+	}{
+		{nil, []string{"T001", "T002", "T003", "T004", "T005"}},
+		{[]string{"services/auth/"}, []string{"T001", "T003"}},
+		{[]string{"services/auth", "services/billing"}, []string{"T001", "T002", "T003"}},
+		{[]string{"docs"}, nil},
+		{[]string{"."}, []string{"T001", "T002", "T003", "T004"}},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, task := range ScopeFilter(tasks, tt.scope) {
+			got = append(got, task.ID)
+		}
+		if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("scope %v: expected %v, got %v", tt.scope, tt.want, got)
+		}
+	}
+
+	f := &Filter{Scope: []string{"services/billing"}}
+	if f.IsEmpty() || f.Match(*auth) || !f.Match(*billing) {
+		t.Error("expected a scoped filter to match only tasks in scope")
+	}
+	if f.String() != "scope=services/billing" {
+		t.Errorf("unexpected description %q", f.String())
+	}
+
+	// Files in the PRD template form are unquoted when parsed
+	parsed, err := ParseTaskSection("### T006: Login\n\n#### Files to Touch\n\n- `services/auth/login.go` (new)\n- \"services/auth/session.go\" (update)\n", "F001")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(parsed.FilesToTouch, ",") != "services/auth/login.go,services/auth/session.go" {
+		t.Errorf("unexpected files to touch %v", parsed.FilesToTouch)
+	}
+	if !InScope(*parsed, []string{"services/auth"}) {
+		t.Error("expected a PRD template task to be in scope")
+	}
+}
+
+func TestGetNextTaskWithFilter(t *testing.T) {
+	tmpDir := setupTestDir(t)
+	defer os.RemoveAll(tmpDir)
+
+	tasksDir := filepath.Join(tmpDir, ".hermes", "tasks")
+	os.WriteFile(filepath.Join(tasksDir, "002-reports.md"), []byte(priorityFeatureContent), 0644)
+
+	reader := NewReader(tmpDir)
+
+	tests := []struct {
+		includes []string
+		excludes []string
+		expected string
+	}{
+		{nil, nil, "T002"},
+		{[]string{"feature=F002"}, nil, "T010"},
+		{[]string{"task=T011"}, nil, "T011"},
+		{nil, []string{"feature=F001"}, "T010"},
+		{[]string{"feature=F001"}, []string{"feature=F001"}, ""},
+		{[]string{"feature=F009"}, nil, ""},
+	}
+
+	for _, tt := range tests {
+		filter, err := ParseFilter(tt.includes, tt.excludes)
+		if err != nil {
+			t.Fatal(err)
+		}
+		reader.SetFilter(filter)
+
+		next, err := reader.GetNextTask()
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := ""
+		if next != nil {
+			got = next.ID
+		}
+		if got != tt.expected {
+			t.Errorf("filter %s: expected %q, got %q", filter, tt.expected, got)
+		}
+	}
+}
+
+// writeDependencyFeature writes a feature whose tasks depend on each other as given
+func writeDependencyFeature(t *testing.T, deps [][2]string) string {
+	tmpDir, err := os.MkdirTemp("", "hermes-task-test-*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tasksDir := filepath.Join(tmpDir, ".hermes", "tasks")
+	os.MkdirAll(tasksDir, 0755)
+
+	var sb strings.Builder
+	sb.WriteString("# Feature 1: Graph\n\n**Feature ID:** F001\n\n## Tasks\n\n")
+	for _, d := range deps {
+		sb.WriteString("### " + d[0] + ": Task " + d[0] + "\n\n**Status:** NOT_STARTED\n**Priority:** P2\n")
+		if d[1] != "" {
+			sb.WriteString("**Dependencies:** " + d[1] + "\n")
+		}
+		sb.WriteString("\n---\n\n")
+	}
+	if err := os.WriteFile(filepath.Join(tasksDir, "001-graph.md"), []byte(sb.String()), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return tmpDir
+}
+
+func dependencyKeys(m map[string]*Task) string {
+	var ids []string
+	for id := range m {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return strings.Join(ids, ",")
+}
+
+func TestGetDependencyTreeChain(t *testing.T) {
+	tmpDir := writeDependencyFeature(t, [][2]string{
+		{"T001", ""},
+		{"T002", "T001"},
+		{"T003", "T002"},
+		{"T004", "T003"},
+	})
+	defer os.RemoveAll(tmpDir)
+	reader := NewReader(tmpDir)
+
+	tree, err := reader.GetDependencyTree("T004")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := dependencyKeys(tree); got != "T001,T002,T003" {
+		t.Errorf("expected T001,T002,T003, got %s", got)
+	}
+
+	dependents, err := reader.GetDependents("T002")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := dependencyKeys(dependents); got != "T003,T004" {
+		t.Errorf("expected T003,T004, got %s", got)
+	}
+
+	if tree, _ := reader.GetDependencyTree("T001"); len(tree) != 0 {
+		t.Errorf("expected no dependencies for T001, got %s", dependencyKeys(tree))
+	}
+	if _, err := reader.GetDependencyTree("T099"); err == nil {
+		t.Error("expected error for unknown task")
+	}
+}
+
+func TestGetDependencyTreeDiamond(t *testing.T) {
+	// T001 <- T002, T003 <- T004, with an unknown dependency on T004
+	tmpDir := writeDependencyFeature(t, [][2]string{
+		{"T001", ""},
+		{"T002", "T001"},
+		{"T003", "T001"},
+		{"T004", "T002, T003, T009"},
+		{"T005", "T004"},
+	})
+	defer os.RemoveAll(tmpDir)
+	reader := NewReader(tmpDir)
+
+	tree, err := reader.GetDependencyTree("T005")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := dependencyKeys(tree); got != "T001,T002,T003,T004" {
+		t.Errorf("expected T001-T004, got %s", got)
+	}
+
+	dependents, err := reader.GetDependents("T001")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := dependencyKeys(dependents); got != "T002,T003,T004,T005" {
+		t.Errorf("expected T002-T005, got %s", got)
+	}
+
+	dependents, _ = reader.GetDependents("T003")
+	if got := dependencyKeys(dependents); got != "T004,T005" {
+		t.Errorf("expected T004,T005, got %s", got)
+	}
+}
+
+func TestMoveTask(t *testing.T) {
+	tmpDir := setupTestDir(t)
+	defer os.RemoveAll(tmpDir)
+
+	tasksDir := filepath.Join(tmpDir, ".hermes", "tasks")
+	os.WriteFile(filepath.Join(tasksDir, "002-reports.md"), []byte(priorityFeatureContent), 0644)
+
+	writer := NewWriter(tmpDir)
+	reader := NewReader(tmpDir)
+
+	// T003 in F001 depends on T002, which would depend on T001 back in F001
+	if _, _, err := writer.MoveTask("T002", "F002"); err == nil || !strings.Contains(err.Error(), "circular") {
+		t.Errorf("expected circular feature dependency error, got %v", err)
+	}
+
+	from, to, err := writer.MoveTask("T003", "F002")
+	if err != nil {
+		t.Fatalf("MoveTask failed: %v", err)
+	}
+	if filepath.Base(from) != "001-user-auth.md" || filepath.Base(to) != "002-reports.md" {
+		t.Errorf("unexpected files: %s -> %s", from, to)
+	}
+
+	moved, _ := reader.GetTaskByID("T003")
+	if moved == nil || moved.FeatureID != "F002" || moved.Status != StatusBlocked || len(moved.FilesToTouch) != 1 {
+		t.Fatalf("expected T003 with its fields in F002, got %+v", moved)
+	}
+
+	source, _ := reader.GetFeatureByID("F001")
+	if len(source.Tasks) != 2 {
+		t.Errorf("expected 2 tasks left in F001, got %d", len(source.Tasks))
+	}
+	content, _ := os.ReadFile(from)
+	if strings.Contains(string(content), "T003") || strings.Contains(string(content), "---\n\n---") {
+		t.Errorf("expected T003 and its separator removed, got:\n%s", content)
+	}
+	if !strings.Contains(string(content), "## Performance Targets") {
+		t.Error("expected the rest of the feature file to remain")
+	}
+
+	target, _ := reader.GetFeatureByID("F002")
+	if len(target.Tasks) != 3 || target.Tasks[2].ID != "T003" {
+		t.Errorf("expected T003 appended to F002, got %v", target.Tasks)
+	}
+
+	// Now T002 can follow T003
+	if _, _, err := writer.MoveTask("T002", "F002"); err != nil {
+		t.Errorf("expected T002 move to succeed, got %v", err)
+	}
+
+	if _, _, err := writer.MoveTask("T099", "F002"); err == nil {
+		t.Error("expected error for unknown task")
+	}
+	if _, _, err := writer.MoveTask("T001", "F009"); err == nil {
+		t.Error("expected error for unknown feature")
+	}
+	if _, _, err := writer.MoveTask("T001", "F001"); err == nil {
+		t.Error("expected error when moving within the same feature")
+	}
+}
+
+func TestMergeRegeneratedTasks(t *testing.T) {
+	generated := `# Feature 1: User Authentication
+
+**Feature ID:** F001
+
+## Tasks
+
+### T001: Create login endpoint
+
+**Status:** NOT_STARTED
+
+---
+
+### T004: Add password hashing with argon2
+
+**Status:** NOT_STARTED
+**Priority:** P1
+
+#### Dependencies
+
+- T001
+
+---
+
+### T005: Add refresh tokens
+
+**Status:** NOT_STARTED
+**Priority:** P2
+
+## Notes
+
+Regenerated notes are ignored.
+`
+
+	merged, removed, added, err := MergeRegeneratedTasks(testFeatureContent, generated)
+	if err != nil {
+		t.Fatalf("MergeRegeneratedTasks failed: %v", err)
+	}
+
+	if len(removed) != 2 || removed[0].ID != "T002" || removed[1].ID != "T003" {
+		t.Errorf("expected T002 and T003 removed, got %+v", removed)
+	}
+	if len(added) != 2 || added[0].ID != "T004" || added[1].ID != "T005" {
+		t.Errorf("expected T004 and T005 added, got %+v", added)
+	}
+
+	feature, _ := ParseFeature(merged, "")
+	var ids []string
+	for _, tk := range feature.Tasks {
+		ids = append(ids, tk.ID)
+	}
+	if strings.Join(ids, ",") != "T001,T004,T005" {
+		t.Errorf("expected tasks T001,T004,T005, got %v", ids)
+	}
+	if feature.Tasks[0].Status != StatusCompleted {
+		t.Error("expected the completed task to be kept unchanged")
+	}
+	if !strings.Contains(merged, "## Performance Targets") || strings.Contains(merged, "Regenerated notes") {
+		t.Errorf("expected only task sections to change, got:\n%s", merged)
+	}
+
+	if _, _, _, err := MergeRegeneratedTasks(testFeatureContent, "# Feature 1: Empty\n"); err == nil {
+		t.Error("expected error when the regenerated feature has no tasks")
+	}
+}
+
+func TestTaskTemplate(t *testing.T) {
+	tmpDir := setupTestDir(t)
+	defer os.RemoveAll(tmpDir)
+
+	tasksDir := filepath.Join(tmpDir, ".hermes", "tasks")
+	os.WriteFile(filepath.Join(tasksDir, "002-reports.md"), []byte(priorityFeatureContent), 0644)
+
+	source, _ := NewReader(tmpDir).GetTaskByID("T001")
+	tmpl := TemplateFromTask("endpoint", source, map[string]string{"Action": "login"})
+	if tmpl.TaskName != "Create {{.Action}} endpoint" || tmpl.FilesPattern[1] != "handlers/{{.Action}}.go" {
+		t.Errorf("expected login to become a variable, got %+v", tmpl)
+	}
+	if vars := tmpl.Variables(); len(vars) != 1 || vars[0] != "Action" {
+		t.Errorf("expected variable Action, got %v", vars)
+	}
+
+	if _, err := SaveTemplate(tmpDir, tmpl, false); err != nil {
+		t.Fatalf("SaveTemplate failed: %v", err)
+	}
+	if _, err := SaveTemplate(tmpDir, tmpl, false); err == nil {
+		t.Error("expected an error when the template exists")
+	}
+	if _, err := SaveTemplate(tmpDir, &Template{Name: "../escape"}, false); err == nil {
+		t.Error("expected an error for an invalid name")
+	}
+
+	templates, err := ListTemplates(tmpDir)
+	if err != nil || len(templates) != 1 || templates[0].Name != "endpoint" {
+		t.Fatalf("expected the saved template, got %v, %v", templates, err)
+	}
+
+	if _, err := templates[0].Render("T020", "F002", nil); err == nil {
+		t.Error("expected an error for a missing variable")
+	}
+	rendered, err := templates[0].Render("T020", "F002", map[string]string{"Action": "logout"})
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	if rendered.Name != "Create logout endpoint" || rendered.FilesToTouch[1] != "handlers/logout.go" ||
+		rendered.Status != StatusNotStarted || rendered.EstimatedEffort != "2 days" {
+		t.Errorf("unexpected rendered task: %+v", rendered)
+	}
+
+	writer := NewWriter(tmpDir)
+	file, err := writer.AddTask("F002", rendered)
+	if err != nil {
+		t.Fatalf("AddTask failed: %v", err)
+	}
+	if filepath.Base(file) != "002-reports.md" {
+		t.Errorf("unexpected file %s", file)
+	}
+	added, _ := NewReader(tmpDir).GetTaskByID("T020")
+	if added == nil || added.FeatureID != "F002" || added.Name != "Create logout endpoint" {
+		t.Errorf("expected T020 in F002, got %+v", added)
+	}
+	if _, err := writer.AddTask("F002", rendered); err == nil {
+		t.Error("expected an error when adding an existing task ID")
+	}
+}
+
+func TestArchiveFeature(t *testing.T) {
+	tmpDir := t.TempDir()
+	tasksDir := filepath.Join(tmpDir, ".hermes", "tasks")
+	os.MkdirAll(tasksDir, 0755)
+	os.WriteFile(filepath.Join(tasksDir, "001-setup.md"), []byte("# Feature 1: Setup\n\n**Feature ID:** F001\n\n## Tasks\n\n### T001: Init\n\n**Status:** COMPLETED\n**Priority:** P2\n\n---\n"), 0644)
+	os.WriteFile(filepath.Join(tasksDir, "002-api.md"), []byte("# Feature 2: API\n\n**Feature ID:** F002\n\n## Tasks\n\n### T002: Endpoint\n\n**Status:** NOT_STARTED\n**Priority:** P2\n**Dependencies:** T001\n\n---\n"), 0644)
+
+	writer := NewWriter(tmpDir)
+	reader := NewReader(tmpDir)
+	at := time.Date(2026, 3, 14, 0, 0, 0, 0, time.UTC)
+
+	from, to, err := writer.ArchiveFeature("F001", at)
+	if err != nil {
+		t.Fatalf("ArchiveFeature failed: %v", err)
+	}
+	if from != filepath.Join(tasksDir, "001-setup.md") || to != filepath.Join(ArchiveDir(tmpDir), "2026-03", "001-setup.md") {
+		t.Errorf("unexpected paths: %s -> %s", from, to)
+	}
+
+	if f, _ := reader.GetFeatureByID("F001"); f != nil {
+		t.Error("expected the archived feature to be left out")
+	}
+	if next, _ := reader.GetNextTask(); next == nil || next.ID != "T002" {
+		t.Errorf("expected the dependency on an archived task to be met, got %v", next)
+	}
+	if archived := reader.GetArchivedTasks(); len(archived) != 1 || archived[0].ID != "T001" {
+		t.Errorf("expected the archived task, got %v", archived)
+	}
+
+	reader.SetIncludeArchived(true)
+	if f, _ := reader.GetFeatureByID("F001"); f == nil {
+		t.Error("expected the archived feature with SetIncludeArchived")
+	}
+
+	if _, _, err := writer.ArchiveFeature("F001", at); err == nil {
+		t.Error("expected an error for a feature that is already archived")
+	}
+	if _, _, err := writer.UnarchiveFeature("F002"); err == nil {
+		t.Error("expected an error for a feature that is not archived")
+	}
+
+	from, to, err = writer.UnarchiveFeature("F001")
+	if err != nil {
+		t.Fatalf("UnarchiveFeature failed: %v", err)
+	}
+	if to != filepath.Join(tasksDir, "001-setup.md") {
+		t.Errorf("expected the file back in the tasks directory, got %s", to)
+	}
+	if _, err := os.Stat(filepath.Dir(from)); !os.IsNotExist(err) {
+		t.Error("expected the empty month directory to be removed")
+	}
+}
+
+func TestTaskHistory(t *testing.T) {
+	tmpDir := setupTestDir(t)
+	defer os.RemoveAll(tmpDir)
+
+	updater := NewStatusUpdater(tmpDir)
+	updater.SetSession("3f2b9c1e-0000-4000-8000-000000000000")
+	if err := updater.UpdateTaskStatus("T002", StatusInProgress); err != nil {
+		t.Fatal(err)
+	}
+	// Setting the same status again is not a change
+	if err := updater.UpdateTaskStatus("T002", StatusInProgress); err != nil {
+		t.Fatal(err)
+	}
+	// Neither is a priority change
+	if err := updater.SetTaskPriority("T002", PriorityP3); err != nil {
+		t.Fatal(err)
+	}
+
+	outside := NewStatusUpdater(tmpDir)
+	if err := outside.BlockTask("T002", "waiting for keys"); err != nil {
+		t.Fatal(err)
+	}
+
+	history, err := LoadHistory(HistoryPath(tmpDir))
+	if err != nil {
+		t.Fatalf("LoadHistory failed: %v", err)
+	}
+	if len(history) != 2 {
+		t.Fatalf("expected 2 status changes, got %+v", history)
+	}
+	if history[0].TaskID != "T002" || history[0].From != StatusNotStarted || history[0].To != StatusInProgress || history[0].SessionID == "" {
+		t.Errorf("unexpected first change: %+v", history[0])
+	}
+	if history[1].From != StatusInProgress || history[1].To != StatusBlocked || history[1].SessionID != "" {
+		t.Errorf("unexpected second change: %+v", history[1])
+	}
+
+	if changes := LastChanges(history); !changes["T002"].Equal(history[1].Timestamp) {
+		t.Errorf("expected the last change of T002, got %v", changes)
+	}
+	if changed := ChangedBySession(history, "3f2b9c1e"); !changed["T002"] || len(changed) != 1 {
+		t.Errorf("expected T002 changed by the session prefix, got %v", changed)
+	}
+	if changed := ChangedBySession(history, "ffff"); len(changed) != 0 {
+		t.Errorf("expected no tasks for an unknown session, got %v", changed)
+	}
+
+	if history, err := LoadHistory(filepath.Join(tmpDir, "missing.json")); err != nil || history != nil {
+		t.Errorf("expected an empty history for a missing file, got %v, %v", history, err)
+	}
+}
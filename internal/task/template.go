@@ -0,0 +1,248 @@
+package task
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// templateNameRegex limits template names to safe file names
+var templateNameRegex = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]*$`)
+
+// Template is a reusable task pattern stored in .hermes/templates/<name>.yaml.
+// Its text fields are text/template strings, e.g. "internal/{{.EntityName}}.go".
+type Template struct {
+	Name             string   `yaml:"name"`
+	Description      string   `yaml:"description"`
+	TaskName         string   `yaml:"taskName"`
+	Priority         Priority `yaml:"priority,omitempty"`
+	EstimatedEffort  string   `yaml:"estimatedEffort,omitempty"`
+	TechnicalDetails string   `yaml:"technicalDetails,omitempty"`
+	FilesPattern     []string `yaml:"filesPattern,omitempty"`
+	SuccessCriteria  []string `yaml:"successCriteria,omitempty"`
+}
+
+// TemplatesDir returns the directory holding task templates
+func TemplatesDir(basePath string) string {
+	return filepath.Join(basePath, ".hermes", "templates")
+}
+
+// TemplateFromTask creates a template from a task. Each value in vars is
+// replaced by a reference to its variable, so a task for "User" with
+// vars {EntityName: User} gives "{{.EntityName}}" wherever "User" appeared.
+func TemplateFromTask(name string, t *Task, vars map[string]string) *Template {
+	// Longer values first, so "UserRole" is not split by "User"
+	keys := make([]string, 0, len(vars))
+	for key := range vars {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return len(vars[keys[i]]) > len(vars[keys[j]]) })
+
+	var pairs []string
+	for _, key := range keys {
+		if vars[key] != "" {
+			pairs = append(pairs, vars[key], "{{."+key+"}}")
+		}
+	}
+	replacer := strings.NewReplacer(pairs...)
+	replaceAll := func(items []string) []string {
+		var result []string
+		for _, item := range items {
+			result = append(result, replacer.Replace(item))
+		}
+		return result
+	}
+
+	return &Template{
+		Name:             name,
+		Description:      replacer.Replace(t.Description),
+		TaskName:         replacer.Replace(t.Name),
+		Priority:         t.Priority,
+		EstimatedEffort:  t.EstimatedEffort,
+		TechnicalDetails: replacer.Replace(t.TechnicalDetails),
+		FilesPattern:     replaceAll(t.FilesToTouch),
+		SuccessCriteria:  replaceAll(t.SuccessCriteria),
+	}
+}
+
+// SaveTemplate writes a template to the templates directory. An existing
+// template with the same name is only replaced if overwrite is set.
+func SaveTemplate(basePath string, tmpl *Template, overwrite bool) (string, error) {
+	if !templateNameRegex.MatchString(tmpl.Name) {
+		return "", fmt.Errorf("invalid template name %q (use letters, digits, - and _)", tmpl.Name)
+	}
+
+	path := filepath.Join(TemplatesDir(basePath), tmpl.Name+".yaml")
+	if _, err := os.Stat(path); err == nil && !overwrite {
+		return "", fmt.Errorf("template %s already exists: %s", tmpl.Name, path)
+	}
+
+	data, err := yaml.Marshal(tmpl)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(TemplatesDir(basePath), 0755); err != nil {
+		return "", err
+	}
+	return path, writeFileAtomic(path, data)
+}
+
+// LoadTemplate reads a template by name
+func LoadTemplate(basePath, name string) (*Template, error) {
+	if !templateNameRegex.MatchString(name) {
+		return nil, fmt.Errorf("invalid template name %q", name)
+	}
+
+	data, err := os.ReadFile(filepath.Join(TemplatesDir(basePath), name+".yaml"))
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("template %s not found", name)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var tmpl Template
+	if err := yaml.Unmarshal(data, &tmpl); err != nil {
+		return nil, fmt.Errorf("failed to parse template %s: %w", name, err)
+	}
+	if tmpl.Name == "" {
+		tmpl.Name = name
+	}
+	return &tmpl, nil
+}
+
+// ListTemplates returns all templates sorted by name
+func ListTemplates(basePath string) ([]*Template, error) {
+	files, err := filepath.Glob(filepath.Join(TemplatesDir(basePath), "*.yaml"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+
+	var templates []*Template
+	for _, file := range files {
+		tmpl, err := LoadTemplate(basePath, strings.TrimSuffix(filepath.Base(file), ".yaml"))
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, tmpl)
+	}
+	return templates, nil
+}
+
+// Variables returns the names of the variables used by the template
+func (tm *Template) Variables() []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, field := range tm.fields() {
+		for _, m := range templateVarRegex.FindAllStringSubmatch(field, -1) {
+			if !seen[m[1]] {
+				seen[m[1]] = true
+				names = append(names, m[1])
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// templateVarRegex finds simple variable references like {{.EntityName}}
+var templateVarRegex = regexp.MustCompile(`\{\{-?\s*\.([A-Za-z_][A-Za-z0-9_]*)`)
+
+// fields returns all text fields of the template
+func (tm *Template) fields() []string {
+	fields := []string{tm.TaskName, tm.Description, tm.TechnicalDetails}
+	fields = append(fields, tm.FilesPattern...)
+	return append(fields, tm.SuccessCriteria...)
+}
+
+// Render returns a NOT_STARTED task with the template's fields rendered with
+// vars. Every variable used must be given.
+func (tm *Template) Render(id, featureID string, vars map[string]string) (*Task, error) {
+	render := func(field, text string) (string, error) {
+		t, err := template.New(field).Option("missingkey=error").Parse(text)
+		if err != nil {
+			return "", fmt.Errorf("template %s: invalid %s: %w", tm.Name, field, err)
+		}
+		var buf bytes.Buffer
+		if err := t.Execute(&buf, vars); err != nil {
+			return "", fmt.Errorf("template %s: %s: %w", tm.Name, field, err)
+		}
+		return buf.String(), nil
+	}
+	renderAll := func(field string, items []string) ([]string, error) {
+		var result []string
+		for _, item := range items {
+			rendered, err := render(field, item)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, rendered)
+		}
+		return result, nil
+	}
+
+	t := &Task{
+		ID:              id,
+		FeatureID:       featureID,
+		Status:          StatusNotStarted,
+		Priority:        tm.Priority,
+		EstimatedEffort: tm.EstimatedEffort,
+		Parallelizable:  true,
+	}
+	if t.Priority == "" {
+		t.Priority = PriorityP2
+	}
+	t.EstimatedMinutes = ParseEffortMinutes(t.EstimatedEffort)
+
+	var err error
+	if t.Name, err = render("taskName", tm.TaskName); err != nil {
+		return nil, err
+	}
+	if t.Name == "" {
+		t.Name = tm.Name
+	}
+	if t.Description, err = render("description", tm.Description); err != nil {
+		return nil, err
+	}
+	if t.TechnicalDetails, err = render("technicalDetails", tm.TechnicalDetails); err != nil {
+		return nil, err
+	}
+	if t.FilesToTouch, err = renderAll("filesPattern", tm.FilesPattern); err != nil {
+		return nil, err
+	}
+	if t.SuccessCriteria, err = renderAll("successCriteria", tm.SuccessCriteria); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// AddTask appends a new task to the end of a feature's tasks and returns
+// the feature file
+func (w *Writer) AddTask(featureID string, t *Task) (string, error) {
+	reader := NewReader(w.basePath)
+	feature, err := reader.GetFeatureByID(featureID)
+	if err != nil {
+		return "", err
+	}
+	if feature == nil {
+		return "", fmt.Errorf("feature %s not found", featureID)
+	}
+	if existing, _ := reader.GetTaskByID(t.ID); existing != nil {
+		return "", fmt.Errorf("task %s already exists", t.ID)
+	}
+
+	content, err := os.ReadFile(feature.FilePath)
+	if err != nil {
+		return "", err
+	}
+	updated := appendTaskSection(string(content), feature, FormatTask(t))
+	return feature.FilePath, writeFileAtomic(feature.FilePath, []byte(updated))
+}
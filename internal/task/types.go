@@ -22,6 +22,19 @@ const (
 	PriorityP4 Priority = "P4" // Low
 )
 
+// priorityLabels are the names used in the "P1 - CRITICAL" form
+var priorityLabels = map[Priority]string{
+	PriorityP1: "CRITICAL",
+	PriorityP2: "HIGH",
+	PriorityP3: "MEDIUM",
+	PriorityP4: "LOW",
+}
+
+// Label returns the priority name, e.g. "CRITICAL" for P1
+func (p Priority) Label() string {
+	return priorityLabels[p]
+}
+
 // Feature represents a feature with its tasks
 type Feature struct {
 	ID                string   `json:"id"`
@@ -46,16 +59,54 @@ type Task struct {
 	Status           Status   `json:"status"`
 	Priority         Priority `json:"priority"`
 	EstimatedEffort  string   `json:"estimatedEffort"`
+	EstimatedMinutes int      `json:"estimatedMinutes"`
 	Description      string   `json:"description"`
 	TechnicalDetails string   `json:"technicalDetails"`
 	FilesToTouch     []string `json:"filesToTouch"`
 	Dependencies     []string `json:"dependencies"`
 	SuccessCriteria  []string `json:"successCriteria"`
 	FeatureID        string   `json:"featureId"`
+	BlockedReason    string   `json:"blockedReason,omitempty"`
+	CompletionNote   string   `json:"completionNote,omitempty"` // Set when completed with 'hermes task complete'
 	// Parallel execution fields
 	DependsOn      []string `json:"dependsOn"`      // Explicit task dependencies (task IDs)
 	Parallelizable bool     `json:"parallelizable"` // Can run in parallel (default: true)
 	ExclusiveFiles []string `json:"exclusiveFiles"` // Files only this task should modify
+	// Constraints are the typed dependencies, written as "- T001 (requires-file)"
+	Constraints []Constraint `json:"constraints,omitempty"`
+}
+
+// Dependency constraint types
+const (
+	ConstraintAfter        = "after"         // Start after the dependency completes (default)
+	ConstraintRequiresFile = "requires-file" // Also requires the dependency's files to touch to exist
+	ConstraintCoDeploy     = "co-deploy"     // Run in the same parallel batch as the dependency
+)
+
+// Constraint is a typed dependency on another task
+type Constraint struct {
+	Type   string `json:"type"`
+	TaskID string `json:"taskId"`
+}
+
+// IsValidConstraintType returns true if the constraint type is known
+func IsValidConstraintType(t string) bool {
+	switch t {
+	case ConstraintAfter, ConstraintRequiresFile, ConstraintCoDeploy:
+		return true
+	}
+	return false
+}
+
+// ConstraintIDs returns the IDs of the tasks this task depends on with the given constraint type
+func (t *Task) ConstraintIDs(constraintType string) []string {
+	var ids []string
+	for _, c := range t.Constraints {
+		if c.Type == constraintType {
+			ids = append(ids, c.TaskID)
+		}
+	}
+	return ids
 }
 
 // Progress represents overall task progress
@@ -73,14 +124,14 @@ func (t *Task) IsComplete() bool {
 	return t.Status == StatusCompleted
 }
 
-// IsBlocked returns true if task is blocked
+// IsBlocked returns true if task is blocked by status or has a block reason
 func (t *Task) IsBlocked() bool {
-	return t.Status == StatusBlocked
+	return t.Status == StatusBlocked || t.BlockedReason != ""
 }
 
 // CanStart returns true if task can be started
 func (t *Task) CanStart(completedTasks map[string]bool) bool {
-	if t.Status != StatusNotStarted {
+	if t.Status != StatusNotStarted || t.IsBlocked() {
 		return false
 	}
 	for _, dep := range t.Dependencies {
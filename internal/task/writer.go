@@ -0,0 +1,762 @@
+package task
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Writer writes tasks back into their feature files
+type Writer struct {
+	basePath string
+}
+
+// NewWriter creates a new task writer
+func NewWriter(basePath string) *Writer {
+	return &Writer{basePath: basePath}
+}
+
+// FieldChange describes a single field that differs between two tasks
+type FieldChange struct {
+	Field string
+	Old   string
+	New   string
+}
+
+// FindTaskFile returns the feature file containing the given task
+func (w *Writer) FindTaskFile(taskID string) (string, error) {
+	reader := NewReader(w.basePath)
+	files, err := reader.GetFeatureFiles()
+	if err != nil {
+		return "", err
+	}
+
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			continue
+		}
+		if _, _, ok := findTaskSection(string(content), taskID); ok {
+			return file, nil
+		}
+	}
+
+	return "", fmt.Errorf("task %s not found", taskID)
+}
+
+// ReadTaskSection returns the raw Markdown section of a task and its file
+func (w *Writer) ReadTaskSection(taskID string) (string, string, error) {
+	file, err := w.FindTaskFile(taskID)
+	if err != nil {
+		return "", "", err
+	}
+
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return "", "", err
+	}
+
+	start, end, _ := findTaskSection(string(content), taskID)
+	return string(content)[start:end], file, nil
+}
+
+// ReplaceTaskSection replaces the raw Markdown section of a task
+func (w *Writer) ReplaceTaskSection(taskID, section string) error {
+	file, err := w.FindTaskFile(taskID)
+	if err != nil {
+		return err
+	}
+
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+
+	contentStr := string(content)
+	start, end, _ := findTaskSection(contentStr, taskID)
+	updated := contentStr[:start] + strings.TrimRight(section, "\n") + "\n" + contentStr[end:]
+
+	return writeFileAtomic(file, []byte(updated))
+}
+
+// WriteTask writes the fields of a task that differ from its feature file
+// back into its section (see UpdateTask)
+func (w *Writer) WriteTask(t *Task) error {
+	file, err := w.FindTaskFile(t.ID)
+	if err != nil {
+		return err
+	}
+	return w.UpdateTask(file, t)
+}
+
+// UpdateTask writes the fields of a task that differ from its section in
+// the given feature file. Only the lines and subsections of changed fields
+// are rewritten; checkboxes, annotations and content the parser does not
+// model are kept as written.
+func (w *Writer) UpdateTask(featureFile string, updated *Task) error {
+	content, err := os.ReadFile(featureFile)
+	if err != nil {
+		return err
+	}
+
+	contentStr := string(content)
+	start, end, ok := findTaskSection(contentStr, updated.ID)
+	if !ok {
+		return fmt.Errorf("task %s not found in %s", updated.ID, featureFile)
+	}
+	section := contentStr[start:end]
+	current, err := ParseTaskSection(section, updated.FeatureID)
+	if err != nil {
+		return err
+	}
+	section = strings.TrimRight(patchTaskSection(section, current, updated), "\n") + "\n"
+
+	return writeFileAtomic(featureFile, []byte(contentStr[:start]+section+contentStr[end:]))
+}
+
+// subsectionOrder is the order of a task's subsections in FormatTask, used
+// to place a subsection the section does not have yet
+var subsectionOrder = []string{
+	"#### Description",
+	"#### Technical Details",
+	"#### Files to Touch",
+	"#### Dependencies",
+	"#### Success Criteria",
+}
+
+// patchTaskSection rewrites the parts of a task section whose fields differ
+// between current, the task parsed from the section, and updated
+func patchTaskSection(section string, current, updated *Task) string {
+	if current.Name != updated.Name {
+		if loc := taskHeaderRegex.FindStringIndex(section); loc != nil {
+			section = section[:loc[0]] + fmt.Sprintf("### %s: %s", updated.ID, updated.Name) + section[loc[1]:]
+		}
+	}
+	if current.Status != updated.Status {
+		section = updateTaskStatusInContent(section, updated.ID, updated.Status)
+	}
+	if current.Priority != updated.Priority {
+		if strings.Contains(section, priorityPrefix) {
+			section = setPriorityLine(section, updated.Priority)
+		} else {
+			section = setDetailLine(section, priorityPrefix, string(updated.Priority))
+		}
+	}
+	if current.EstimatedEffort != updated.EstimatedEffort {
+		section = setFieldLine(section, effortPrefix, updated.EstimatedEffort)
+	}
+	if current.BlockedReason != updated.BlockedReason {
+		section = setFieldLine(section, blockedReasonPrefix, updated.BlockedReason)
+	}
+	if current.CompletionNote != updated.CompletionNote {
+		section = setFieldLine(section, completionNotePrefix, updated.CompletionNote)
+	}
+
+	if current.Description != updated.Description {
+		section = setSubsectionText(section, "#### Description", updated.Description)
+	}
+	if current.TechnicalDetails != updated.TechnicalDetails {
+		section = setSubsectionText(section, "#### Technical Details", updated.TechnicalDetails)
+	}
+	if strings.Join(current.FilesToTouch, "\n") != strings.Join(updated.FilesToTouch, "\n") {
+		section = setSubsectionList(section, "#### Files to Touch", updated.FilesToTouch)
+	}
+	if strings.Join(dependencyItems(current), "\n") != strings.Join(dependencyItems(updated), "\n") {
+		section = setSubsectionList(section, "#### Dependencies", dependencyItems(updated))
+	}
+	if strings.Join(current.SuccessCriteria, "\n") != strings.Join(updated.SuccessCriteria, "\n") {
+		section = setSubsectionList(section, "#### Success Criteria", updated.SuccessCriteria)
+	}
+
+	return section
+}
+
+// setFieldLine replaces the value of the line starting with prefix in a
+// task section, keeping it in place. A missing line is added after the
+// status line; an empty value removes the line.
+func setFieldLine(section, prefix, value string) string {
+	lines := strings.Split(section, "\n")
+	for i, line := range lines {
+		if !strings.HasPrefix(strings.TrimSpace(line), prefix) {
+			continue
+		}
+		if value == "" {
+			lines = append(lines[:i], lines[i+1:]...)
+		} else {
+			lines[i] = prefix + " " + value
+		}
+		return strings.Join(lines, "\n")
+	}
+	return setDetailLine(section, prefix, value)
+}
+
+// subsectionBody returns the range of lines after a subsection header, up
+// to the next header or separator
+func subsectionBody(lines []string, header string) (int, int, bool) {
+	for i, line := range lines {
+		if !strings.HasPrefix(strings.TrimSpace(line), header) {
+			continue
+		}
+		end := i + 1
+		for end < len(lines) {
+			trimmed := strings.TrimSpace(lines[end])
+			if strings.HasPrefix(trimmed, "###") || trimmed == "---" {
+				break
+			}
+			end++
+		}
+		return i + 1, end, true
+	}
+	return 0, 0, false
+}
+
+// setSubsectionText replaces the text of a subsection, keeping the blank
+// lines around it. Empty text removes the subsection.
+func setSubsectionText(section, header, text string) string {
+	lines := strings.Split(section, "\n")
+	start, end, ok := subsectionBody(lines, header)
+	if !ok {
+		if text == "" {
+			return section
+		}
+		return insertSubsection(lines, header, strings.Split(text, "\n"))
+	}
+	if text == "" {
+		return joinLines(lines[:start-1], lines[end:])
+	}
+
+	first, last := start, end
+	for first < last && strings.TrimSpace(lines[first]) == "" {
+		first++
+	}
+	for last > first && strings.TrimSpace(lines[last-1]) == "" {
+		last--
+	}
+	body := strings.Split(text, "\n")
+	if first == last {
+		// Only blank lines: keep one before and after the text
+		first, last = start, end
+		body = append(append([]string{""}, body...), "")
+	}
+	return joinLines(lines[:first], body, lines[last:])
+}
+
+// setSubsectionList replaces the list items of a subsection. Lines of
+// items that are kept are reused as written, with their checkboxes and
+// annotations, and text between the items is kept. An empty list is
+// written as "- None".
+func setSubsectionList(section, header string, items []string) string {
+	lines := strings.Split(section, "\n")
+	start, end, ok := subsectionBody(lines, header)
+
+	existing := make(map[string]string)
+	checkboxes := false
+	var body []string
+	at := -1
+	if ok {
+		for _, line := range lines[start:end] {
+			trimmed := strings.TrimSpace(line)
+			if !strings.HasPrefix(trimmed, "- ") && !strings.HasPrefix(trimmed, "* ") {
+				body = append(body, line)
+				continue
+			}
+			if at < 0 {
+				at = len(body)
+			}
+			existing[listItemKey(header, line)] = line
+			if strings.HasPrefix(trimmed[2:], "[ ] ") || strings.HasPrefix(trimmed[2:], "[x] ") {
+				checkboxes = true
+			}
+		}
+	}
+
+	var listLines []string
+	for _, item := range items {
+		if line, ok := existing[listItemKey(header, "- "+item)]; ok {
+			listLines = append(listLines, line)
+		} else if checkboxes {
+			listLines = append(listLines, "- [ ] "+item)
+		} else {
+			listLines = append(listLines, "- "+item)
+		}
+	}
+	if len(listLines) == 0 {
+		listLines = []string{"- None"}
+	}
+
+	if !ok {
+		if len(items) == 0 {
+			return section
+		}
+		return insertSubsection(lines, header, listLines)
+	}
+	if at < 0 {
+		at = 0
+		for at < len(body) && strings.TrimSpace(body[at]) == "" {
+			at++
+		}
+		if at == 0 {
+			listLines = append([]string{""}, listLines...)
+		}
+	}
+	return joinLines(lines[:start], body[:at], listLines, body[at:], lines[end:])
+}
+
+// listItemKey identifies a list line by the items the parser reads from it
+func listItemKey(header, line string) string {
+	return strings.Join(parseTaskListSection(header+"\n"+strings.TrimSpace(line), header), "\n")
+}
+
+// insertSubsection adds a subsection before the first one that follows it
+// in subsectionOrder, or at the end of the section
+func insertSubsection(lines []string, header string, body []string) string {
+	subsection := append(append([]string{header, ""}, body...), "")
+
+	following := false
+	for _, next := range subsectionOrder {
+		if next == header {
+			following = true
+			continue
+		}
+		if !following {
+			continue
+		}
+		if start, _, ok := subsectionBody(lines, next); ok {
+			return joinLines(lines[:start-1], subsection, lines[start-1:])
+		}
+	}
+
+	end := len(lines)
+	for end > 0 && strings.TrimSpace(lines[end-1]) == "" {
+		end--
+	}
+	return joinLines(lines[:end], []string{""}, subsection)
+}
+
+// joinLines joins groups of lines into one text
+func joinLines(groups ...[]string) string {
+	var all []string
+	for _, group := range groups {
+		all = append(all, group...)
+	}
+	return strings.Join(all, "\n")
+}
+
+// SortKeys lists the keys SortTasks can order a feature's tasks by
+var SortKeys = []string{"dependency", "priority", "id", "name"}
+
+// SortTasksByPriority reorders the task sections of a feature file by
+// priority, keeping the original order for tasks with equal priority
+func (w *Writer) SortTasksByPriority(file string) error {
+	_, _, err := w.SortTasks(file, "priority")
+	return err
+}
+
+// SortTasks reorders the task sections of a feature file by one of
+// SortKeys, keeping the original order for ties. "dependency" puts every
+// task after the tasks of the feature it depends on. Everything around the
+// task sections, such as the feature header and other sections, is kept in
+// place. It returns the task IDs in their old and new order.
+func (w *Writer) SortTasks(file, by string) ([]string, []string, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil, nil, err
+	}
+	contentStr := string(content)
+
+	feature, err := ParseFeature(contentStr, file)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	type section struct {
+		id         string
+		start, end int
+	}
+	var sections []section
+	var tasks []Task
+	for _, t := range feature.Tasks {
+		start, end, ok := findTaskSection(contentStr, t.ID)
+		if ok {
+			sections = append(sections, section{t.ID, start, end})
+			tasks = append(tasks, t)
+		}
+	}
+
+	sorted, err := sortTasks(tasks, by)
+	if err != nil {
+		return nil, nil, err
+	}
+	before := make([]string, len(tasks))
+	after := make([]string, len(sorted))
+	for i := range tasks {
+		before[i] = tasks[i].ID
+		after[i] = sorted[i].ID
+	}
+	if len(sections) < 2 || strings.Join(before, ",") == strings.Join(after, ",") {
+		return before, after, nil
+	}
+
+	byID := make(map[string]section, len(sections))
+	for _, s := range sections {
+		byID[s.id] = s
+	}
+
+	// Substitute sections in sorted order, keeping separators between them
+	// and the trailing blank lines of each slot in place
+	var sb strings.Builder
+	offset := 0
+	for i, s := range sections {
+		slot := contentStr[s.start:s.end]
+		moved := byID[after[i]]
+		sb.WriteString(contentStr[offset:s.start])
+		sb.WriteString(strings.TrimRight(contentStr[moved.start:moved.end], " \t\n"))
+		sb.WriteString(slot[len(strings.TrimRight(slot, " \t\n")):])
+		offset = s.end
+	}
+	sb.WriteString(contentStr[offset:])
+
+	return before, after, writeFileAtomic(file, []byte(sb.String()))
+}
+
+// sortTasks returns the tasks ordered by one of SortKeys
+func sortTasks(tasks []Task, by string) ([]Task, error) {
+	sorted := make([]Task, len(tasks))
+	copy(sorted, tasks)
+
+	switch by {
+	case "dependency":
+		return dependencyOrder(sorted)
+	case "priority":
+		sort.SliceStable(sorted, func(i, j int) bool {
+			return sorted[i].Priority < sorted[j].Priority
+		})
+	case "id":
+		sort.SliceStable(sorted, func(i, j int) bool {
+			return sorted[i].ID < sorted[j].ID
+		})
+	case "name":
+		sort.SliceStable(sorted, func(i, j int) bool {
+			return strings.ToLower(sorted[i].Name) < strings.ToLower(sorted[j].Name)
+		})
+	default:
+		return nil, fmt.Errorf("invalid sort key %q (valid: %s)", by, strings.Join(SortKeys, ", "))
+	}
+	return sorted, nil
+}
+
+// dependencyOrder sorts tasks topologically, so each task comes after the
+// tasks it depends on. Of the tasks whose dependencies are placed, the one
+// first in the given order goes next. Dependencies on tasks not in the list
+// are ignored.
+func dependencyOrder(tasks []Task) ([]Task, error) {
+	index := make(map[string]int, len(tasks))
+	for i, t := range tasks {
+		index[t.ID] = i
+	}
+
+	pending := make([]int, len(tasks))
+	dependents := make(map[int][]int)
+	for i, t := range tasks {
+		for _, dep := range t.DependencyIDs() {
+			if j, ok := index[dep]; ok && j != i {
+				pending[i]++
+				dependents[j] = append(dependents[j], i)
+			}
+		}
+	}
+
+	placed := make([]bool, len(tasks))
+	var sorted []Task
+	for len(sorted) < len(tasks) {
+		next := -1
+		for i := range tasks {
+			if !placed[i] && pending[i] == 0 {
+				next = i
+				break
+			}
+		}
+		if next < 0 {
+			var cycle []string
+			for i, t := range tasks {
+				if !placed[i] {
+					cycle = append(cycle, t.ID)
+				}
+			}
+			return nil, fmt.Errorf("circular dependency between tasks %s", strings.Join(cycle, ", "))
+		}
+		placed[next] = true
+		sorted = append(sorted, tasks[next])
+		for _, i := range dependents[next] {
+			pending[i]--
+		}
+	}
+	return sorted, nil
+}
+
+// FormatTask renders a task using the standard feature file layout
+func FormatTask(t *Task) string {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("### %s: %s\n\n", t.ID, t.Name))
+	sb.WriteString(fmt.Sprintf("**Status:** %s\n", t.Status))
+	sb.WriteString(fmt.Sprintf("**Priority:** %s\n", t.Priority))
+	if t.BlockedReason != "" {
+		sb.WriteString(fmt.Sprintf("%s %s\n", blockedReasonPrefix, t.BlockedReason))
+	}
+	if t.CompletionNote != "" {
+		sb.WriteString(fmt.Sprintf("%s %s\n", completionNotePrefix, t.CompletionNote))
+	}
+	if t.EstimatedEffort != "" {
+		sb.WriteString(fmt.Sprintf("**Estimated Effort:** %s\n", t.EstimatedEffort))
+	}
+
+	if t.Description != "" {
+		sb.WriteString("\n#### Description\n\n")
+		sb.WriteString(t.Description + "\n")
+	}
+
+	if t.TechnicalDetails != "" {
+		sb.WriteString("\n#### Technical Details\n\n")
+		sb.WriteString(t.TechnicalDetails + "\n")
+	}
+
+	sb.WriteString("\n#### Files to Touch\n\n")
+	writeList(&sb, t.FilesToTouch)
+
+	sb.WriteString("\n#### Dependencies\n\n")
+	writeList(&sb, dependencyItems(t))
+
+	if len(t.SuccessCriteria) > 0 {
+		sb.WriteString("\n#### Success Criteria\n\n")
+		writeList(&sb, t.SuccessCriteria)
+	}
+
+	return sb.String()
+}
+
+// dependencyItems returns the dependency list entries of a task, annotated
+// with their constraint type unless it is the default "after"
+func dependencyItems(t *Task) []string {
+	types := make(map[string]string)
+	for _, c := range t.Constraints {
+		types[c.TaskID] = c.Type
+	}
+
+	var items []string
+	for _, dep := range t.Dependencies {
+		if constraintType := types[dep]; constraintType != "" && constraintType != ConstraintAfter && constraintType != ConstraintCoDeploy {
+			items = append(items, fmt.Sprintf("%s (%s)", dep, constraintType))
+		} else {
+			items = append(items, dep)
+		}
+	}
+	for _, id := range t.ConstraintIDs(ConstraintCoDeploy) {
+		items = append(items, fmt.Sprintf("%s (%s)", id, ConstraintCoDeploy))
+	}
+	return items
+}
+
+func writeList(sb *strings.Builder, items []string) {
+	if len(items) == 0 {
+		sb.WriteString("- None\n")
+		return
+	}
+	for _, item := range items {
+		sb.WriteString("- " + item + "\n")
+	}
+}
+
+// ParseTaskSection parses a single task section
+func ParseTaskSection(section, featureID string) (*Task, error) {
+	tasks := parseTasks(section, featureID, DefaultEffortUnits())
+	if len(tasks) == 0 {
+		return nil, fmt.Errorf("no task header found (expected '### TXXX: Name')")
+	}
+	if len(tasks) > 1 {
+		return nil, fmt.Errorf("expected a single task, found %d", len(tasks))
+	}
+	return &tasks[0], nil
+}
+
+// ValidateTask checks a task's status, priority and dependencies against all known tasks
+func ValidateTask(t *Task, allTasks []Task) error {
+	if !IsValidStatus(t.Status) {
+		return fmt.Errorf("invalid status: %s", t.Status)
+	}
+	if !IsValidPriority(t.Priority) {
+		return fmt.Errorf("invalid priority: %s", t.Priority)
+	}
+	if strings.TrimSpace(t.Name) == "" {
+		return fmt.Errorf("task name cannot be empty")
+	}
+
+	deps := make(map[string][]string)
+	for _, other := range allTasks {
+		deps[other.ID] = other.Dependencies
+	}
+	deps[t.ID] = t.Dependencies
+
+	for _, dep := range t.Dependencies {
+		if dep == t.ID {
+			return fmt.Errorf("task %s cannot depend on itself", t.ID)
+		}
+		if _, ok := deps[dep]; !ok {
+			return fmt.Errorf("dependency %s does not exist", dep)
+		}
+	}
+
+	// Walk the dependency chain to make sure it never leads back to this task
+	visited := make(map[string]bool)
+	var reaches func(id string) bool
+	reaches = func(id string) bool {
+		if id == t.ID {
+			return true
+		}
+		if visited[id] {
+			return false
+		}
+		visited[id] = true
+		for _, dep := range deps[id] {
+			if reaches(dep) {
+				return true
+			}
+		}
+		return false
+	}
+	for _, dep := range t.Dependencies {
+		if reaches(dep) {
+			return fmt.Errorf("dependency %s creates a cycle", dep)
+		}
+	}
+
+	return nil
+}
+
+// IsValidStatus returns true if the status is a known value
+func IsValidStatus(s Status) bool {
+	switch s {
+	case StatusNotStarted, StatusInProgress, StatusCompleted, StatusBlocked, StatusAtRisk, StatusPaused:
+		return true
+	}
+	return false
+}
+
+// IsValidPriority returns true if the priority is a known value
+func IsValidPriority(p Priority) bool {
+	switch p {
+	case PriorityP1, PriorityP2, PriorityP3, PriorityP4:
+		return true
+	}
+	return false
+}
+
+// ApplyField sets a task field from a key=value style edit
+func ApplyField(t *Task, key, value string) error {
+	value = strings.TrimSpace(value)
+
+	switch strings.ToLower(strings.TrimSpace(key)) {
+	case "name":
+		t.Name = value
+	case "status":
+		t.Status = Status(strings.ToUpper(value))
+	case "priority":
+		p, err := ParsePriority(value)
+		if err != nil {
+			return err
+		}
+		t.Priority = p
+	case "effort", "estimatedeffort":
+		t.EstimatedEffort = value
+		t.EstimatedMinutes = ParseEffortMinutes(value)
+	case "description":
+		t.Description = value
+	case "technicaldetails", "details":
+		t.TechnicalDetails = value
+	case "files", "filestotouch":
+		t.FilesToTouch = parseCommaSeparated(value)
+	case "deps", "dependencies":
+		t.Dependencies = nil
+		for _, dep := range parseCommaSeparated(value) {
+			t.Dependencies = append(t.Dependencies, strings.ToUpper(dep))
+		}
+	case "criteria", "successcriteria":
+		t.SuccessCriteria = parseCommaSeparated(value)
+	default:
+		return fmt.Errorf("unknown field: %s", key)
+	}
+
+	return nil
+}
+
+// DiffTasks returns the fields that differ between two versions of a task
+func DiffTasks(old, updated *Task) []FieldChange {
+	var changes []FieldChange
+
+	add := func(field, o, n string) {
+		if o != n {
+			changes = append(changes, FieldChange{Field: field, Old: o, New: n})
+		}
+	}
+
+	add("Name", old.Name, updated.Name)
+	add("Status", string(old.Status), string(updated.Status))
+	add("Priority", string(old.Priority), string(updated.Priority))
+	add("Estimated Effort", old.EstimatedEffort, updated.EstimatedEffort)
+	add("Blocked Reason", old.BlockedReason, updated.BlockedReason)
+	add("Completion Note", old.CompletionNote, updated.CompletionNote)
+	add("Description", old.Description, updated.Description)
+	add("Technical Details", old.TechnicalDetails, updated.TechnicalDetails)
+	add("Files to Touch", strings.Join(old.FilesToTouch, ", "), strings.Join(updated.FilesToTouch, ", "))
+	add("Dependencies", strings.Join(old.Dependencies, ", "), strings.Join(updated.Dependencies, ", "))
+	add("Success Criteria", strings.Join(old.SuccessCriteria, ", "), strings.Join(updated.SuccessCriteria, ", "))
+
+	return changes
+}
+
+// findTaskSection returns the byte range of a task section, from its header
+// up to the next task header, separator, top-level section, or end of file
+func findTaskSection(content, taskID string) (int, int, bool) {
+	headerPattern := regexp.MustCompile(`(?m)^###\s*` + regexp.QuoteMeta(taskID) + `:.*$`)
+	loc := headerPattern.FindStringIndex(content)
+	if loc == nil {
+		return 0, 0, false
+	}
+
+	start := loc[0]
+	end := len(content)
+	offset := loc[1]
+
+	for offset < len(content) {
+		lineEnd := strings.Index(content[offset+1:], "\n")
+		var line string
+		if lineEnd < 0 {
+			line = content[offset+1:]
+		} else {
+			line = content[offset+1 : offset+1+lineEnd]
+		}
+
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "### T") || strings.HasPrefix(trimmed, "## ") || trimmed == "---" {
+			end = offset + 1
+			break
+		}
+
+		if lineEnd < 0 {
+			break
+		}
+		offset += lineEnd + 1
+	}
+
+	// Keep trailing blank lines outside the section
+	for end > start && (content[end-1] == '\n' || content[end-1] == ' ') {
+		end--
+	}
+	if end < len(content) {
+		end++ // include the final newline of the section
+	}
+
+	return start, end, true
+}
@@ -42,6 +42,7 @@ const (
 type runResultMsg struct {
 	taskID  string
 	success bool
+	single  bool // A task run with 'r' on the Tasks screen, not part of the loop
 	err     error
 }
 
@@ -116,6 +117,13 @@ func (a App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		a.logs.SetSize(msg.Width, msg.Height-4)
 
 	case tea.KeyMsg:
+		// The edit modal takes every key, including the global shortcuts
+		if a.screen == ScreenTaskDetail && a.taskDetail.Editing() {
+			break
+		}
+		if a.screen == ScreenTasks && a.tasks.Confirming() {
+			break
+		}
 		switch msg.String() {
 		case "q", "ctrl+c":
 			return a, tea.Quit
@@ -147,13 +155,21 @@ func (a App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			a.tasks.Refresh()
 			a.logs.Refresh()
 		case "r":
-			// Start run
-			if !a.running {
-				a.running = true
-				a.loopCount = 0
-				a.runStatus = "Starting..."
-				return a, a.startRun()
+			if a.running {
+				break
+			}
+			// On the Tasks screen, run the selected task after confirmation
+			if a.screen == ScreenTasks {
+				if selected := a.tasks.Selected(); selected != nil {
+					return a, a.tasks.ConfirmRun(selected)
+				}
+				break
 			}
+			// Start run
+			a.running = true
+			a.loopCount = 0
+			a.runStatus = "Starting..."
+			return a, a.startRun(nil)
 		case "s":
 			// Stop run
 			if a.running && a.runCancel != nil {
@@ -163,15 +179,31 @@ func (a App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		}
 
+	case runTaskMsg:
+		if !a.running {
+			a.running = true
+			a.runStatus = "Starting " + msg.task.ID + "..."
+			return a, a.startRun(msg.task)
+		}
+
 	case runResultMsg:
 		if msg.err != nil {
 			a.runStatus = fmt.Sprintf("Error: %v", msg.err)
 		} else if msg.success {
 			a.runStatus = fmt.Sprintf("Completed: %s", msg.taskID)
 		}
+		if msg.single {
+			if msg.err == nil && !msg.success {
+				a.runStatus = fmt.Sprintf("Not complete yet: %s", msg.taskID)
+			}
+			a.running = false
+			a.tasks.Refresh()
+			a.dashboard.Refresh()
+			return a, nil
+		}
 		// Continue to next task
 		if a.running {
-			return a, a.startRun()
+			return a, a.startRun(nil)
 		}
 	}
 
@@ -247,6 +279,8 @@ func (a App) footerView() string {
 	help := "[1]Dashboard [2]Tasks [3]Logs [?]Help [r]Run [Shift+R]Refresh [q]Quit"
 	if a.running {
 		help = "[RUNNING] " + a.runStatus + " | [s]Stop [q]Quit"
+	} else if a.runStatus != "" {
+		help = a.runStatus + " | " + help
 	}
 	return style.Render(help)
 }
@@ -266,7 +300,7 @@ Navigation:
   Esc         Back to previous screen
 
 Actions:
-  r           Start task execution
+  r           Start task execution (on Tasks: run the selected task now)
   s           Stop execution
   Shift+R     Manual refresh
   Enter       Open task detail (from Tasks)
@@ -279,8 +313,14 @@ Dashboard:
 
 Tasks:
   a/c/p/n/b   Filter: All/Completed/InProgress/NotStarted/Blocked
+  r           Run the selected task now, ignoring the task order
   Enter       View task details
 
+Task Detail:
+  e           Edit description, technical details and success criteria
+  Ctrl+S      Save edits to the feature file
+  Esc         Cancel editing
+
 Logs:
   g           Go to top
   Shift+G     Go to bottom
@@ -291,8 +331,9 @@ Press any key to return...
 	return style.Render(help)
 }
 
-// startRun starts executing the next task
-func (a *App) startRun() tea.Cmd {
+// startRun starts executing the next task, or override if it is set. An
+// override runs regardless of the task order and ends the run afterwards.
+func (a *App) startRun(override *task.Task) tea.Cmd {
 	return func() tea.Msg {
 		ctx, cancel := context.WithCancel(context.Background())
 		a.runCancel = cancel
@@ -305,13 +346,17 @@ func (a *App) startRun() tea.Cmd {
 		}
 
 		// Get next task
-		nextTask, err := a.taskReader.GetNextTask()
-		if err != nil {
-			return runResultMsg{err: err}
-		}
-		if nextTask == nil {
-			a.running = false
-			return runResultMsg{err: fmt.Errorf("all tasks completed")}
+		nextTask, single := override, override != nil
+		if !single {
+			var err error
+			nextTask, err = a.taskReader.GetNextTask()
+			if err != nil {
+				return runResultMsg{err: err}
+			}
+			if nextTask == nil {
+				a.running = false
+				return runResultMsg{err: fmt.Errorf("all tasks completed")}
+			}
 		}
 
 		a.loopCount++
@@ -334,7 +379,7 @@ func (a *App) startRun() tea.Cmd {
 
 		if err != nil {
 			a.breaker.AddLoopResult(false, true, a.loopCount)
-			return runResultMsg{taskID: nextTask.ID, err: err}
+			return runResultMsg{taskID: nextTask.ID, single: single, err: err}
 		}
 
 		// Analyze response
@@ -349,9 +394,9 @@ func (a *App) startRun() tea.Cmd {
 			statusUpdater := task.NewStatusUpdater(a.basePath)
 			statusUpdater.UpdateTaskStatus(nextTask.ID, task.StatusCompleted)
 			injector.RemoveTask()
-			return runResultMsg{taskID: nextTask.ID, success: true}
+			return runResultMsg{taskID: nextTask.ID, success: true, single: single}
 		}
 
-		return runResultMsg{taskID: nextTask.ID, success: false}
+		return runResultMsg{taskID: nextTask.ID, success: false, single: single}
 	}
 }
@@ -8,6 +8,7 @@ import (
 	"github.com/charmbracelet/lipgloss"
 	"hermes/internal/circuit"
 	"hermes/internal/task"
+	"hermes/internal/ui"
 )
 
 // DashboardModel is the dashboard screen model
@@ -19,6 +20,7 @@ type DashboardModel struct {
 	breaker        *circuit.BreakerState
 	currentTask    *task.Task
 	currentFeature *task.Feature
+	features       []task.Feature
 }
 
 // NewDashboardModel creates a new dashboard model
@@ -34,6 +36,7 @@ func NewDashboardModel(basePath string) *DashboardModel {
 func (m *DashboardModel) Refresh() {
 	reader := task.NewReader(m.basePath)
 	m.progress, _ = reader.GetProgress()
+	m.features, _ = reader.GetAllFeatures()
 
 	breaker := circuit.New(m.basePath)
 	m.breaker, _ = breaker.GetState()
@@ -84,6 +87,11 @@ func (m *DashboardModel) View() string {
 		Width(m.width - 4).
 		Render(taskContent)
 
+	// Features box
+	featuresBox := boxStyle.
+		Width(m.width - 4).
+		Render(m.FeaturesView())
+
 	// Layout
 	topRow := lipgloss.JoinHorizontal(lipgloss.Top, progressBox, circuitBox)
 
@@ -91,9 +99,44 @@ func (m *DashboardModel) View() string {
 		lipgloss.Left,
 		topRow,
 		taskBox,
+		featuresBox,
 	)
 }
 
+// maxDashboardFeatures is the number of features listed on the dashboard
+const maxDashboardFeatures = 8
+
+// FeaturesView renders the progress of each feature
+func (m *DashboardModel) FeaturesView() string {
+	var sb strings.Builder
+
+	sb.WriteString(lipgloss.NewStyle().Bold(true).Render("Features"))
+	sb.WriteString("\n\n")
+
+	if len(m.features) == 0 {
+		sb.WriteString("No features found")
+		return sb.String()
+	}
+
+	doneStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("42"))
+	lines := ui.FormatFeatureProgress(m.features)
+	for i, line := range lines {
+		if i >= maxDashboardFeatures {
+			sb.WriteString(fmt.Sprintf("\n... and %d more", len(lines)-maxDashboardFeatures))
+			break
+		}
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		if p := m.features[i].Progress(); p.Total > 0 && p.Completed == p.Total {
+			line = doneStyle.Render(line)
+		}
+		sb.WriteString(line)
+	}
+
+	return sb.String()
+}
+
 func (m *DashboardModel) progressView() string {
 	var sb strings.Builder
 
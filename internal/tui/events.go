@@ -0,0 +1,123 @@
+package tui
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"time"
+
+	"hermes/internal/scheduler"
+	"hermes/internal/task"
+)
+
+// Pipe event types, the "type" field of a PipeEvent
+const (
+	PipeEventStart      = "start"       // Workers and Total are set; always the first event
+	PipeEventBatchStart = "batch_start" // Batch and Batches are set
+	PipeEventTaskStart  = "task_start"  // Worker, TaskID and TaskName are set
+	PipeEventTaskDone   = "task_done"   // Worker, TaskID, TaskName, Success, Error, Branch and DurationMs are set
+	PipeEventRunDone    = "run_done"    // The run finished; no events follow
+)
+
+// PipeEvent is the JSON form of a scheduler.WorkerEvent, written one per
+// line by 'hermes run --parallel --tui-pipe' and read by
+// 'hermes tui --attach'
+type PipeEvent struct {
+	Type       string    `json:"type"`
+	Time       time.Time `json:"time"`
+	Workers    int       `json:"workers,omitempty"`
+	Total      int       `json:"total,omitempty"`
+	Batch      int       `json:"batch,omitempty"`
+	Batches    int       `json:"batches,omitempty"`
+	Worker     int       `json:"worker,omitempty"`
+	TaskID     string    `json:"task_id,omitempty"`
+	TaskName   string    `json:"task_name,omitempty"`
+	Success    bool      `json:"success,omitempty"`
+	Error      string    `json:"error,omitempty"`
+	Branch     string    `json:"branch,omitempty"`
+	DurationMs int64     `json:"duration_ms,omitempty"`
+}
+
+// NewStartEvent returns the event that opens a stream, telling the reader
+// how many workers and pending tasks the run has
+func NewStartEvent(workers, total int) PipeEvent {
+	return PipeEvent{Type: PipeEventStart, Time: time.Now(), Workers: workers, Total: total}
+}
+
+// NewPipeEvent converts a scheduler event to its JSON form
+func NewPipeEvent(event scheduler.WorkerEvent) PipeEvent {
+	e := PipeEvent{Time: time.Now(), Worker: event.WorkerID}
+	switch event.Type {
+	case scheduler.EventBatchStart:
+		e.Type = PipeEventBatchStart
+		e.Batch = event.Batch
+		e.Batches = event.Batches
+	case scheduler.EventTaskStart:
+		e.Type = PipeEventTaskStart
+		e.TaskID = event.Task.ID
+		e.TaskName = event.Task.Name
+	case scheduler.EventTaskDone:
+		e.Type = PipeEventTaskDone
+		e.TaskID = event.Result.TaskID
+		e.TaskName = event.Result.TaskName
+		e.Success = event.Result.Success
+		e.Branch = event.Result.Branch
+		e.DurationMs = event.Result.Duration.Milliseconds()
+		if event.Result.Error != nil {
+			e.Error = event.Result.Error.Error()
+		}
+	case scheduler.EventRunDone:
+		e.Type = PipeEventRunDone
+	}
+	return e
+}
+
+// WorkerEvent converts the event back to a scheduler event for
+// ParallelModel. It returns false for the start event and unknown types,
+// which have no scheduler equivalent.
+func (e PipeEvent) WorkerEvent() (scheduler.WorkerEvent, bool) {
+	switch e.Type {
+	case PipeEventBatchStart:
+		return scheduler.WorkerEvent{Type: scheduler.EventBatchStart, Batch: e.Batch, Batches: e.Batches}, true
+	case PipeEventTaskStart:
+		return scheduler.WorkerEvent{
+			Type:     scheduler.EventTaskStart,
+			WorkerID: e.Worker,
+			Task:     &task.Task{ID: e.TaskID, Name: e.TaskName},
+		}, true
+	case PipeEventTaskDone:
+		result := &scheduler.TaskResult{
+			TaskID:   e.TaskID,
+			TaskName: e.TaskName,
+			Success:  e.Success,
+			Branch:   e.Branch,
+			Duration: time.Duration(e.DurationMs) * time.Millisecond,
+			EndTime:  e.Time,
+			WorkerID: e.Worker,
+		}
+		if e.Error != "" {
+			result.Error = errors.New(e.Error)
+		}
+		return scheduler.WorkerEvent{Type: scheduler.EventTaskDone, WorkerID: e.Worker, Result: result}, true
+	case PipeEventRunDone:
+		return scheduler.WorkerEvent{Type: scheduler.EventRunDone}, true
+	}
+	return scheduler.WorkerEvent{}, false
+}
+
+// EventDecoder parses a stream of PipeEvents, one JSON object per line
+type EventDecoder struct {
+	dec *json.Decoder
+}
+
+// NewEventDecoder creates a decoder reading from r
+func NewEventDecoder(r io.Reader) *EventDecoder {
+	return &EventDecoder{dec: json.NewDecoder(r)}
+}
+
+// Decode returns the next event, or io.EOF once the stream is closed
+func (d *EventDecoder) Decode() (PipeEvent, error) {
+	var e PipeEvent
+	err := d.dec.Decode(&e)
+	return e, err
+}
@@ -13,18 +13,25 @@ import (
 
 // LogsModel is the logs viewer model
 type LogsModel struct {
-	basePath string
-	width    int
-	height   int
-	lines    []string
-	scroll   int
+	logPath    string
+	title      string
+	width      int
+	height     int
+	lines      []string
+	scroll     int
 	autoScroll bool
 }
 
 // NewLogsModel creates a new logs model
 func NewLogsModel(basePath string) *LogsModel {
+	return NewLogFileModel(filepath.Join(basePath, ".hermes", "logs", "hermes.log"), "Logs")
+}
+
+// NewLogFileModel creates a logs model showing the given log file
+func NewLogFileModel(logPath, title string) *LogsModel {
 	m := &LogsModel{
-		basePath:   basePath,
+		logPath:    logPath,
+		title:      title,
 		autoScroll: true,
 	}
 	m.Refresh()
@@ -33,9 +40,7 @@ func NewLogsModel(basePath string) *LogsModel {
 
 // Refresh reloads log file
 func (m *LogsModel) Refresh() {
-	logPath := filepath.Join(m.basePath, ".hermes", "logs", "hermes.log")
-	
-	file, err := os.Open(logPath)
+	file, err := os.Open(m.logPath)
 	if err != nil {
 		m.lines = []string{"No log file found.", "", "Logs will appear here when you run tasks."}
 		return
@@ -123,12 +128,12 @@ func (m *LogsModel) View() string {
 		Bold(true).
 		Foreground(lipgloss.Color("86")).
 		MarginBottom(1)
-	
+
 	autoScrollIndicator := ""
 	if m.autoScroll {
 		autoScrollIndicator = " [AUTO-SCROLL]"
 	}
-	sb.WriteString(headerStyle.Render(fmt.Sprintf("Logs%s", autoScrollIndicator)))
+	sb.WriteString(headerStyle.Render(fmt.Sprintf("%s%s", m.title, autoScrollIndicator)))
 	sb.WriteString("\n\n")
 
 	// Calculate visible lines
@@ -155,12 +160,12 @@ func (m *LogsModel) View() string {
 	var content strings.Builder
 	for i := startIdx; i < endIdx; i++ {
 		line := m.lines[i]
-		
+
 		// Truncate long lines
 		if len(line) > m.width-8 {
 			line = line[:m.width-11] + "..."
 		}
-		
+
 		// Color based on log level
 		lineStyle := lipgloss.NewStyle()
 		if strings.Contains(line, "[ERROR]") {
@@ -172,7 +177,7 @@ func (m *LogsModel) View() string {
 		} else if strings.Contains(line, "[DEBUG]") {
 			lineStyle = lineStyle.Foreground(lipgloss.Color("241"))
 		}
-		
+
 		content.WriteString(lineStyle.Render(line))
 		content.WriteString("\n")
 	}
@@ -0,0 +1,52 @@
+package tui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// LogViewer shows a single log file full screen, e.g. for 'hermes log --parallel'
+type LogViewer struct {
+	logs  *LogsModel
+	ready bool
+}
+
+// NewLogViewer creates a viewer for the log file at logPath
+func NewLogViewer(logPath, title string) LogViewer {
+	return LogViewer{logs: NewLogFileModel(logPath, title)}
+}
+
+// Init initializes the viewer
+func (v LogViewer) Init() tea.Cmd {
+	return nil
+}
+
+// Update handles messages
+func (v LogViewer) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		v.logs.SetSize(msg.Width, msg.Height-1)
+		v.logs.Refresh()
+		v.ready = true
+		return v, nil
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "q", "esc", "ctrl+c":
+			return v, tea.Quit
+		case "r":
+			v.logs.Refresh()
+			return v, nil
+		}
+	}
+
+	model, cmd := v.logs.Update(msg)
+	v.logs = model.(*LogsModel)
+	return v, cmd
+}
+
+// View renders the viewer
+func (v LogViewer) View() string {
+	if !v.ready {
+		return "Loading..."
+	}
+	return v.logs.View() + "\n[r] Refresh [q] Quit"
+}
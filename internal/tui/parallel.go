@@ -3,11 +3,11 @@ package tui
 import (
 	"fmt"
 	"strings"
-	"sync"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"hermes/internal/merger"
 	"hermes/internal/scheduler"
 	"hermes/internal/task"
 )
@@ -23,7 +23,10 @@ type WorkerStatus struct {
 	Duration  time.Duration
 }
 
-// ParallelModel is the parallel execution TUI model
+// ParallelModel is the parallel execution TUI model. Scheduler progress
+// arrives as WorkerEvent messages on the Events channel, so all state is
+// changed inside the Bubbletea loop; the setters are only safe to call
+// before the program starts.
 type ParallelModel struct {
 	basePath    string
 	width       int
@@ -38,8 +41,42 @@ type ParallelModel struct {
 	startTime   time.Time
 	graph       *scheduler.TaskGraph
 	results     []*scheduler.TaskResult
-	mu          sync.Mutex
 	done        bool
+	pauser      Pauser
+	paused      bool
+	conflicts   []merger.Conflict
+
+	// workerEventCh delivers scheduler progress into the Update loop
+	workerEventCh chan scheduler.WorkerEvent
+
+	// conflictCh delivers conflicts detected while tasks run
+	conflictCh chan []merger.Conflict
+}
+
+// conflictsMsg reports newly detected conflicts
+type conflictsMsg []merger.Conflict
+
+// Pauser pauses and resumes execution, e.g. a *scheduler.Scheduler
+type Pauser interface {
+	Pause()
+	Resume()
+}
+
+// pausedMsg reports that execution was paused or resumed
+type pausedMsg struct {
+	paused bool
+}
+
+// pauseCmd pauses or resumes execution outside the UI loop
+func pauseCmd(p Pauser, pause bool) tea.Cmd {
+	return func() tea.Msg {
+		if pause {
+			p.Pause()
+		} else {
+			p.Resume()
+		}
+		return pausedMsg{paused: pause}
+	}
 }
 
 // NewParallelModel creates a new parallel execution model
@@ -53,10 +90,40 @@ func NewParallelModel(basePath string, maxWorkers int) *ParallelModel {
 	}
 
 	return &ParallelModel{
-		basePath:   basePath,
-		maxWorkers: maxWorkers,
-		workers:    workers,
-		startTime:  time.Now(),
+		basePath:      basePath,
+		maxWorkers:    maxWorkers,
+		workers:       workers,
+		startTime:     time.Now(),
+		workerEventCh: make(chan scheduler.WorkerEvent, maxWorkers*4),
+		conflictCh:    make(chan []merger.Conflict, 4),
+	}
+}
+
+// Events returns the channel to pass to Scheduler.SetEventChannel. The
+// model applies the events in its Update loop.
+func (m *ParallelModel) Events() chan<- scheduler.WorkerEvent {
+	return m.workerEventCh
+}
+
+// waitForEvent blocks until the scheduler sends an event and returns it as
+// a message
+func (m *ParallelModel) waitForEvent() tea.Cmd {
+	return func() tea.Msg {
+		return <-m.workerEventCh
+	}
+}
+
+// Conflicts returns the channel to send conflicts detected during execution
+// on, e.g. from ConflictDetector.WatchForConflicts
+func (m *ParallelModel) Conflicts() chan<- []merger.Conflict {
+	return m.conflictCh
+}
+
+// waitForConflicts blocks until conflicts are detected and returns them as
+// a message
+func (m *ParallelModel) waitForConflicts() tea.Cmd {
+	return func() tea.Msg {
+		return conflictsMsg(<-m.conflictCh)
 	}
 }
 
@@ -72,6 +139,21 @@ func (m *ParallelModel) SetGraph(graph *scheduler.TaskGraph) {
 	m.total = len(graph.GetAllNodes())
 }
 
+// SetPauser enables the [p] key to pause and resume execution
+func (m *ParallelModel) SetPauser(p Pauser) {
+	m.pauser = p
+}
+
+// IsPaused returns true while execution is paused
+func (m *ParallelModel) IsPaused() bool {
+	return m.paused
+}
+
+// SetTotal sets the number of tasks to execute
+func (m *ParallelModel) SetTotal(total int) {
+	m.total = total
+}
+
 // SetBatchInfo sets batch information
 func (m *ParallelModel) SetBatchInfo(current, total int) {
 	m.currentBatch = current
@@ -80,17 +162,15 @@ func (m *ParallelModel) SetBatchInfo(current, total int) {
 
 // UpdateWorker updates a worker's status
 func (m *ParallelModel) UpdateWorker(workerID int, taskID, taskName, status string, progress int) {
-	m.mu.Lock()
-	defer m.mu.Unlock()
-
 	if workerID > 0 && workerID <= len(m.workers) {
 		w := &m.workers[workerID-1]
 		w.TaskID = taskID
 		w.TaskName = taskName
 		w.Status = status
 		w.Progress = progress
-		if status == "running" && w.StartTime.IsZero() {
+		if status == "running" {
 			w.StartTime = time.Now()
+			w.Duration = 0
 		}
 		if status == "completed" || status == "failed" {
 			w.Duration = time.Since(w.StartTime)
@@ -100,9 +180,6 @@ func (m *ParallelModel) UpdateWorker(workerID int, taskID, taskName, status stri
 
 // AddResult adds a task result
 func (m *ParallelModel) AddResult(result *scheduler.TaskResult) {
-	m.mu.Lock()
-	defer m.mu.Unlock()
-
 	m.results = append(m.results, result)
 	if result.Success {
 		m.completed++
@@ -116,9 +193,33 @@ func (m *ParallelModel) SetDone() {
 	m.done = true
 }
 
+// IsDone returns true if the execution has been marked complete
+func (m *ParallelModel) IsDone() bool {
+	return m.done
+}
+
 // Init initializes the model
 func (m *ParallelModel) Init() tea.Cmd {
-	return tickCmd()
+	return tea.Batch(tickCmd(), m.waitForEvent(), m.waitForConflicts())
+}
+
+// handleEvent applies a scheduler event to the model
+func (m *ParallelModel) handleEvent(event scheduler.WorkerEvent) {
+	switch event.Type {
+	case scheduler.EventBatchStart:
+		m.SetBatchInfo(event.Batch, event.Batches)
+	case scheduler.EventTaskStart:
+		m.UpdateWorker(event.WorkerID, event.Task.ID, event.Task.Name, "running", 0)
+	case scheduler.EventTaskDone:
+		status := "completed"
+		if !event.Result.Success {
+			status = "failed"
+		}
+		m.UpdateWorker(event.WorkerID, event.Result.TaskID, event.Result.TaskName, status, 100)
+		m.AddResult(event.Result)
+	case scheduler.EventRunDone:
+		m.SetDone()
+	}
 }
 
 // Update handles messages
@@ -129,17 +230,28 @@ func (m *ParallelModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "q", "ctrl+c":
 			return m, tea.Quit
 		case "p":
-			// Pause (future feature)
+			if m.pauser != nil && !m.IsDone() {
+				return m, pauseCmd(m.pauser, !m.IsPaused())
+			}
 		}
+	case pausedMsg:
+		m.paused = msg.paused
+	case conflictsMsg:
+		m.conflicts = append(m.conflicts, msg...)
+		return m, m.waitForConflicts()
+	case scheduler.WorkerEvent:
+		m.handleEvent(msg)
+		if m.done {
+			return m, nil
+		}
+		return m, m.waitForEvent()
 	case tickMsg:
 		// Update durations
-		m.mu.Lock()
 		for i := range m.workers {
 			if m.workers[i].Status == "running" {
 				m.workers[i].Duration = time.Since(m.workers[i].StartTime)
 			}
 		}
-		m.mu.Unlock()
 		return m, tickCmd()
 	case tea.WindowSizeMsg:
 		m.SetSize(msg.Width, msg.Height)
@@ -149,9 +261,6 @@ func (m *ParallelModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 // View renders the parallel execution view
 func (m *ParallelModel) View() string {
-	m.mu.Lock()
-	defer m.mu.Unlock()
-
 	var sb strings.Builder
 
 	// Header
@@ -163,6 +272,13 @@ func (m *ParallelModel) View() string {
 	header := headerStyle.Render("HERMES PARALLEL EXECUTION")
 	version := lipgloss.NewStyle().Foreground(lipgloss.Color("241")).Render("v2.0.0")
 	headerLine := fmt.Sprintf("%s %s", header, version)
+	if m.paused {
+		headerLine += "  " + lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("196")).Render("PAUSED")
+	}
+	if len(m.conflicts) > 0 {
+		headerLine += "  " + lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("226")).
+			Render(fmt.Sprintf("CONFLICTS: %d", len(m.conflicts)))
+	}
 
 	sb.WriteString(headerLine)
 	sb.WriteString("\n")
@@ -207,6 +323,14 @@ func (m *ParallelModel) View() string {
 
 		workerLine := fmt.Sprintf("  %s Worker %d: ", icon, w.ID)
 
+		if m.paused && w.Status != "running" {
+			// Idle workers take no new task until resumed
+			workerContent.WriteString(fmt.Sprintf("  ⏸️ Worker %d: ", w.ID))
+			workerContent.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Render("paused"))
+			workerContent.WriteString("\n")
+			continue
+		}
+
 		if w.TaskID != "" {
 			taskInfo := fmt.Sprintf("%s - %s", w.TaskID, w.TaskName)
 			if len(taskInfo) > 40 {
@@ -237,6 +361,21 @@ func (m *ParallelModel) View() string {
 	sb.WriteString(boxStyle.Render(workerContent.String()))
 	sb.WriteString("\n\n")
 
+	// Latest conflicts, so they can be dealt with before the batch ends
+	if len(m.conflicts) > 0 {
+		alertStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("226"))
+		start := len(m.conflicts) - 3
+		if start < 0 {
+			start = 0
+		}
+		for _, c := range m.conflicts[start:] {
+			sb.WriteString(alertStyle.Render(fmt.Sprintf("  ⚠ Conflict in %s (%s): %s",
+				c.File, strings.Join(c.Tasks, ", "), c.Type)))
+			sb.WriteString("\n")
+		}
+		sb.WriteString("\n")
+	}
+
 	// Summary stats
 	elapsed := time.Since(m.startTime).Round(time.Second)
 	sb.WriteString(fmt.Sprintf("  Completed: %d/%d", m.completed, m.total))
@@ -258,7 +397,14 @@ func (m *ParallelModel) View() string {
 	sb.WriteString(strings.Repeat("─", m.width-2))
 	sb.WriteString("\n")
 	controlStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
-	sb.WriteString(controlStyle.Render("  [q] Quit  [p] Pause"))
+	controls := "  [q] Quit"
+	if m.pauser != nil {
+		controls += "  [p] Pause"
+		if m.paused {
+			controls = "  [q] Quit  [p] Resume"
+		}
+	}
+	sb.WriteString(controlStyle.Render(controls))
 
 	if m.done {
 		sb.WriteString("\n\n")
@@ -291,15 +437,11 @@ func (m *ParallelModel) progressBar(percentage float64, width int) string {
 
 // GetCompletedCount returns the number of completed tasks
 func (m *ParallelModel) GetCompletedCount() int {
-	m.mu.Lock()
-	defer m.mu.Unlock()
 	return m.completed
 }
 
 // GetFailedCount returns the number of failed tasks
 func (m *ParallelModel) GetFailedCount() int {
-	m.mu.Lock()
-	defer m.mu.Unlock()
 	return m.failed
 }
 
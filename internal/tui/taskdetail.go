@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/charmbracelet/bubbles/textarea"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"hermes/internal/task"
@@ -17,8 +18,17 @@ type TaskDetailModel struct {
 	task     *task.Task
 	feature  *task.Feature
 	scroll   int
+
+	// Edit modal
+	editing bool
+	editors []textarea.Model // Description, Technical Details, Success Criteria
+	focus   int
+	message string
 }
 
+// editLabels are the titles of the edit modal fields, in editor order
+var editLabels = []string{"Description", "Technical Details", "Success Criteria (one per line)"}
+
 // NewTaskDetailModel creates a new task detail model
 func NewTaskDetailModel(basePath string) *TaskDetailModel {
 	return &TaskDetailModel{
@@ -30,6 +40,8 @@ func NewTaskDetailModel(basePath string) *TaskDetailModel {
 func (m *TaskDetailModel) SetTask(t *task.Task) {
 	m.task = t
 	m.scroll = 0
+	m.editing = false
+	m.message = ""
 	
 	// Load feature info
 	if t != nil {
@@ -49,8 +61,17 @@ func (m *TaskDetailModel) Init() tea.Cmd {
 	return nil
 }
 
+// Editing returns true while the edit modal is open and should receive all keys
+func (m *TaskDetailModel) Editing() bool {
+	return m.editing
+}
+
 // Update handles messages
 func (m *TaskDetailModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if m.editing {
+		return m.updateEditor(msg)
+	}
+
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		switch msg.String() {
@@ -60,16 +81,155 @@ func (m *TaskDetailModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if m.scroll > 0 {
 				m.scroll--
 			}
+		case "e":
+			if m.task != nil {
+				return m, m.startEdit()
+			}
 		}
 	}
 	return m, nil
 }
 
+// startEdit opens the edit modal pre-populated with the task's fields
+func (m *TaskDetailModel) startEdit() tea.Cmd {
+	values := []string{
+		m.task.Description,
+		m.task.TechnicalDetails,
+		strings.Join(m.task.SuccessCriteria, "\n"),
+	}
+
+	height := (m.height - 14) / len(values)
+	if height < 3 {
+		height = 3
+	}
+
+	m.editors = make([]textarea.Model, len(values))
+	for i, value := range values {
+		ta := textarea.New()
+		ta.ShowLineNumbers = false
+		ta.CharLimit = 0
+		ta.SetWidth(m.width - 8)
+		ta.SetHeight(height)
+		ta.SetValue(value)
+		m.editors[i] = ta
+	}
+
+	m.editing = true
+	m.message = ""
+	m.focus = 0
+	return m.editors[0].Focus()
+}
+
+// updateEditor handles messages while the edit modal is open
+func (m *TaskDetailModel) updateEditor(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if key, ok := msg.(tea.KeyMsg); ok {
+		switch key.String() {
+		case "esc":
+			m.editing = false
+			m.message = "Edit cancelled"
+			return m, nil
+		case "ctrl+s":
+			if err := m.saveEdit(); err != nil {
+				m.message = fmt.Sprintf("Save failed: %v", err)
+				return m, nil
+			}
+			m.editing = false
+			m.message = "Saved " + m.task.ID
+			return m, nil
+		case "tab", "shift+tab":
+			m.editors[m.focus].Blur()
+			if key.String() == "tab" {
+				m.focus = (m.focus + 1) % len(m.editors)
+			} else {
+				m.focus = (m.focus + len(m.editors) - 1) % len(m.editors)
+			}
+			return m, m.editors[m.focus].Focus()
+		}
+	}
+
+	var cmd tea.Cmd
+	m.editors[m.focus], cmd = m.editors[m.focus].Update(msg)
+	return m, cmd
+}
+
+// saveEdit writes the edited fields back to the task's feature file
+func (m *TaskDetailModel) saveEdit() error {
+	updated := *m.task
+	updated.Description = strings.TrimSpace(m.editors[0].Value())
+	updated.TechnicalDetails = strings.TrimSpace(m.editors[1].Value())
+	updated.SuccessCriteria = nil
+	for _, line := range strings.Split(m.editors[2].Value(), "\n") {
+		line = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), "- "))
+		if line != "" {
+			updated.SuccessCriteria = append(updated.SuccessCriteria, line)
+		}
+	}
+
+	writer := task.NewWriter(m.basePath)
+	file := ""
+	if m.feature != nil {
+		file = m.feature.FilePath
+	}
+	if file == "" {
+		var err error
+		if file, err = writer.FindTaskFile(updated.ID); err != nil {
+			return err
+		}
+	}
+
+	if err := writer.UpdateTask(file, &updated); err != nil {
+		return err
+	}
+	m.task = &updated
+	return nil
+}
+
+// editorView renders the edit modal
+func (m *TaskDetailModel) editorView() string {
+	var sb strings.Builder
+
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("86"))
+	labelStyle := lipgloss.NewStyle().Bold(true)
+	focusStyle := labelStyle.Foreground(lipgloss.Color("226"))
+
+	sb.WriteString(titleStyle.Render(fmt.Sprintf("Edit Task: %s - %s", m.task.ID, m.task.Name)))
+	sb.WriteString("\n\n")
+
+	for i, editor := range m.editors {
+		style := labelStyle
+		if i == m.focus {
+			style = focusStyle
+		}
+		sb.WriteString(style.Render(editLabels[i]))
+		sb.WriteString("\n")
+		sb.WriteString(editor.View())
+		sb.WriteString("\n\n")
+	}
+
+	if m.message != "" {
+		sb.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Render(m.message))
+		sb.WriteString("\n")
+	}
+
+	modalStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("86")).
+		Padding(1, 2).
+		Width(m.width - 4)
+
+	footerStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+	return modalStyle.Render(sb.String()) + "\n\n" +
+		footerStyle.Render("[Ctrl+S] Save | [Esc] Cancel | [Tab] Next field")
+}
+
 // View renders the task detail
 func (m *TaskDetailModel) View() string {
 	if m.task == nil {
 		return "No task selected"
 	}
+	if m.editing {
+		return m.editorView()
+	}
 
 	var sb strings.Builder
 	t := m.task
@@ -199,7 +359,11 @@ func (m *TaskDetailModel) View() string {
 
 	// Footer
 	footerStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
-	sb.WriteString(footerStyle.Render("[Esc] Back to tasks | [j/k] Scroll"))
+	if m.message != "" {
+		sb.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("42")).Render(m.message))
+		sb.WriteString("  ")
+	}
+	sb.WriteString(footerStyle.Render("[Esc] Back to tasks | [e] Edit | [j/k] Scroll"))
 
 	return sb.String()
 }
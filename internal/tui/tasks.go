@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"hermes/internal/task"
@@ -17,6 +18,16 @@ type TasksModel struct {
 	tasks    []task.Task
 	cursor   int
 	filter   task.Status
+
+	// Run confirmation dialog
+	confirming bool
+	confirm    textinput.Model
+	runTask    *task.Task
+}
+
+// runTaskMsg asks the app to run a task now, outside the normal order
+type runTaskMsg struct {
+	task *task.Task
 }
 
 // NewTasksModel creates a new tasks model
@@ -41,6 +52,61 @@ func (m *TasksModel) SetSize(width, height int) {
 	m.height = height
 }
 
+// Selected returns the highlighted task, or nil if the list is empty
+func (m *TasksModel) Selected() *task.Task {
+	tasks := m.filteredTasks()
+	if m.cursor >= len(tasks) {
+		return nil
+	}
+	t := tasks[m.cursor]
+	return &t
+}
+
+// Confirming returns true while the run confirmation is open and should
+// receive all keys
+func (m *TasksModel) Confirming() bool {
+	return m.confirming
+}
+
+// ConfirmRun opens the confirmation for running a task now
+func (m *TasksModel) ConfirmRun(t *task.Task) tea.Cmd {
+	question := fmt.Sprintf("Run %s now? [y/N] ", t.ID)
+	if t.Status == task.StatusCompleted {
+		question = fmt.Sprintf("%s is COMPLETED, run it again? [y/N] ", t.ID)
+	}
+
+	m.confirm = textinput.New()
+	m.confirm.Prompt = question
+	m.confirm.CharLimit = 3
+	m.runTask = t
+	m.confirming = true
+	return m.confirm.Focus()
+}
+
+// updateConfirm handles messages while the run confirmation is open.
+// Enter with y or yes runs the task, anything else cancels.
+func (m *TasksModel) updateConfirm(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if key, ok := msg.(tea.KeyMsg); ok {
+		switch key.String() {
+		case "esc":
+			m.confirming = false
+			return m, nil
+		case "enter":
+			m.confirming = false
+			answer := strings.ToLower(strings.TrimSpace(m.confirm.Value()))
+			if answer != "y" && answer != "yes" {
+				return m, nil
+			}
+			t := m.runTask
+			return m, func() tea.Msg { return runTaskMsg{task: t} }
+		}
+	}
+
+	var cmd tea.Cmd
+	m.confirm, cmd = m.confirm.Update(msg)
+	return m, cmd
+}
+
 // Init initializes the tasks screen
 func (m *TasksModel) Init() tea.Cmd {
 	return nil
@@ -48,6 +114,10 @@ func (m *TasksModel) Init() tea.Cmd {
 
 // Update handles messages
 func (m *TasksModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if m.confirming {
+		return m.updateConfirm(msg)
+	}
+
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		switch msg.String() {
@@ -98,7 +168,7 @@ func (m *TasksModel) View() string {
 		Foreground(lipgloss.Color("241")).
 		MarginBottom(1)
 
-	filterBar := "[a]All [c]Completed [p]In Progress [n]Not Started [b]Blocked"
+	filterBar := "[a]All [c]Completed [p]In Progress [n]Not Started [b]Blocked [r]Run selected"
 	if m.filter != "" {
 		filterBar += fmt.Sprintf(" | Filter: %s", m.filter)
 	}
@@ -192,6 +262,15 @@ func (m *TasksModel) View() string {
 		sb.WriteString(fmt.Sprintf("\nShowing %d tasks", len(tasks)))
 	}
 
+	if m.confirming {
+		confirmStyle := lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(lipgloss.Color("226")).
+			Padding(0, 1)
+		sb.WriteString("\n\n")
+		sb.WriteString(confirmStyle.Render(m.confirm.View() + "\n[Enter] Confirm | [Esc] Cancel"))
+	}
+
 	return sb.String()
 }
 
@@ -1,6 +1,7 @@
 package ui
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -20,6 +21,12 @@ const (
 	LogSuccess
 )
 
+// Log output formats
+const (
+	LogFormatText = "text"
+	LogFormatJSON = "json"
+)
+
 var (
 	debugColor   = color.New(color.FgHiBlack)
 	infoColor    = color.New(color.FgCyan)
@@ -34,6 +41,19 @@ type Logger struct {
 	logPath  string
 	minLevel LogLevel
 	debug    bool
+	format   string
+	taskID   string
+	loop     int
+	quiet    bool
+}
+
+// jsonLogEntry is a single structured log line
+type jsonLogEntry struct {
+	Timestamp string `json:"ts"`
+	Level     string `json:"level"`
+	Message   string `json:"msg"`
+	Loop      int    `json:"loop"`
+	TaskID    string `json:"task_id,omitempty"`
 }
 
 // NewLogger creates a new logger
@@ -59,9 +79,38 @@ func NewLogger(basePath string, debug bool) (*Logger, error) {
 		logPath:  logPath,
 		minLevel: minLevel,
 		debug:    debug,
+		format:   LogFormatText,
 	}, nil
 }
 
+// SetFormat sets the output format ("text" or "json")
+func (l *Logger) SetFormat(format string) error {
+	switch format {
+	case "", LogFormatText:
+		l.format = LogFormatText
+	case LogFormatJSON:
+		l.format = LogFormatJSON
+	default:
+		return fmt.Errorf("unknown log format: %s (use text or json)", format)
+	}
+	return nil
+}
+
+// SetQuiet suppresses console output while still writing to the log file
+func (l *Logger) SetQuiet(quiet bool) {
+	l.quiet = quiet
+}
+
+// SetTaskContext attaches a task ID to all subsequent log lines
+func (l *Logger) SetTaskContext(taskID string) {
+	l.taskID = taskID
+}
+
+// SetLoop sets the current loop number for subsequent log lines
+func (l *Logger) SetLoop(loop int) {
+	l.loop = loop
+}
+
 // Close closes the log file
 func (l *Logger) Close() {
 	if l.logFile != nil {
@@ -76,6 +125,11 @@ func (l *Logger) log(level LogLevel, levelStr string, format string, args ...int
 
 	msg := fmt.Sprintf(format, args...)
 
+	if l.format == LogFormatJSON {
+		l.logJSON(levelStr, msg)
+		return
+	}
+
 	// Console output with color
 	var c *color.Color
 	switch level {
@@ -91,7 +145,9 @@ func (l *Logger) log(level LogLevel, levelStr string, format string, args ...int
 		c = successColor
 	}
 
-	c.Printf("[%s] %s\n", levelStr, msg)
+	if !l.quiet {
+		c.Printf("[%s] %s\n", levelStr, msg)
+	}
 
 	// File output
 	if l.logFile != nil {
@@ -100,6 +156,27 @@ func (l *Logger) log(level LogLevel, levelStr string, format string, args ...int
 	}
 }
 
+// logJSON writes a single JSON line to console and file
+func (l *Logger) logJSON(levelStr, msg string) {
+	data, err := json.Marshal(jsonLogEntry{
+		Timestamp: time.Now().Format(time.RFC3339),
+		Level:     levelStr,
+		Message:   msg,
+		Loop:      l.loop,
+		TaskID:    l.taskID,
+	})
+	if err != nil {
+		return
+	}
+
+	if !l.quiet {
+		fmt.Println(string(data))
+	}
+	if l.logFile != nil {
+		fmt.Fprintln(l.logFile, string(data))
+	}
+}
+
 // Debug logs a debug message
 func (l *Logger) Debug(format string, args ...interface{}) {
 	l.log(LogDebug, "DEBUG", format, args...)
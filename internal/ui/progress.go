@@ -51,6 +51,56 @@ func PrintProgress(progress *task.Progress) {
 	fmt.Println(strings.Repeat("-", 40))
 }
 
+// FormatFeatureProgress returns one line per feature, e.g.
+// "F001 - Auth | 3/5 tasks | 60%", with the columns aligned
+func FormatFeatureProgress(features []task.Feature) []string {
+	labels := make([]string, len(features))
+	counts := make([]string, len(features))
+	labelWidth, countWidth := 0, 0
+	for i, f := range features {
+		p := f.Progress()
+		labels[i] = fmt.Sprintf("%s - %s", f.ID, f.Name)
+		counts[i] = fmt.Sprintf("%d/%d tasks", p.Completed, p.Total)
+		if len(labels[i]) > labelWidth {
+			labelWidth = len(labels[i])
+		}
+		if len(counts[i]) > countWidth {
+			countWidth = len(counts[i])
+		}
+	}
+
+	lines := make([]string, len(features))
+	for i, f := range features {
+		lines[i] = fmt.Sprintf("%s | %s | %.0f%%",
+			padRight(labels[i], labelWidth), padRight(counts[i], countWidth), f.Progress().Percentage)
+	}
+	return lines
+}
+
+// PrintFeatureProgress prints the progress of each feature to console
+func PrintFeatureProgress(features []task.Feature) {
+	fmt.Println()
+	fmt.Println("Feature Progress")
+	fmt.Println(strings.Repeat("-", 40))
+
+	green := color.New(color.FgGreen)
+	for i, line := range FormatFeatureProgress(features) {
+		p := features[i].Progress()
+		if p.Total > 0 && p.Completed == p.Total {
+			green.Println(line)
+		} else {
+			fmt.Println(line)
+		}
+	}
+
+	fmt.Println(strings.Repeat("-", 40))
+}
+
+// ClearScreen moves the cursor home and clears the terminal using ANSI escapes
+func ClearScreen() {
+	fmt.Print("\033[H\033[2J")
+}
+
 // PrintHeader prints a styled header
 func PrintHeader(title string) {
 	cyan := color.New(color.FgCyan, color.Bold)
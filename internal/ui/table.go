@@ -77,6 +77,69 @@ func FormatTaskTable(tasks []task.Task) string {
 	return sb.String()
 }
 
+var taskListColumns = []Column{
+	{"ID", 6},
+	{"Feature", 7},
+	{"Name", 35},
+	{"Status", 11},
+	{"Priority", 8},
+	{"Effort", 8},
+	{"Dependencies", 20},
+}
+
+// FormatTaskList formats tasks as an ASCII table with effort and dependency
+// columns, in the given order
+func FormatTaskList(tasks []task.Task) string {
+	if len(tasks) == 0 {
+		return "No tasks found."
+	}
+
+	rule := formatRule(taskListColumns)
+	names := make([]string, len(taskListColumns))
+	for i, col := range taskListColumns {
+		names[i] = col.Name
+	}
+
+	var sb strings.Builder
+	sb.WriteString(rule)
+	sb.WriteString(formatRow(taskListColumns, names))
+	sb.WriteString(rule)
+	for _, t := range tasks {
+		sb.WriteString(formatRow(taskListColumns, []string{
+			t.ID, t.FeatureID, t.Name, string(t.Status), string(t.Priority),
+			orDash(t.EstimatedEffort), orDash(strings.Join(t.DependencyIDs(), ", ")),
+		}))
+	}
+	sb.WriteString(rule)
+
+	return sb.String()
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+// formatRule returns a horizontal table line for the columns
+func formatRule(columns []Column) string {
+	var parts []string
+	for _, col := range columns {
+		parts = append(parts, strings.Repeat("-", col.Width+2))
+	}
+	return "+" + strings.Join(parts, "+") + "+\n"
+}
+
+// formatRow returns a table row, truncating values to the column widths
+func formatRow(columns []Column, values []string) string {
+	parts := make([]string, len(columns))
+	for i, col := range columns {
+		parts[i] = truncate(values[i], col.Width)
+	}
+	return "| " + strings.Join(parts, " | ") + " |\n"
+}
+
 func formatSeparator(position string) string {
 	var left, mid, right, line string
 	switch position {
@@ -1,10 +1,12 @@
 package ui
 
 import (
+	"encoding/json"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"hermes/internal/task"
 )
@@ -39,6 +41,60 @@ func TestNewLogger(t *testing.T) {
 	}
 }
 
+func TestLoggerJSONFormat(t *testing.T) {
+	tmpDir, cleanup := setupTestDir(t)
+	defer cleanup()
+
+	logger, _ := NewLogger(tmpDir, false)
+	if err := logger.SetFormat(LogFormatJSON); err != nil {
+		t.Fatal(err)
+	}
+	logger.SetLoop(3)
+	logger.Info("before task")
+	logger.SetTaskContext("T002")
+	logger.Warn("working on %s", "task")
+	logger.Close()
+
+	content, _ := os.ReadFile(logger.GetLogPath())
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 log lines, got %d", len(lines))
+	}
+
+	var first, second map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[0]), &first); err != nil {
+		t.Fatalf("invalid JSON line: %v", err)
+	}
+	if err := json.Unmarshal([]byte(lines[1]), &second); err != nil {
+		t.Fatalf("invalid JSON line: %v", err)
+	}
+
+	if _, ok := first["task_id"]; ok {
+		t.Error("task_id should be omitted when not set")
+	}
+	if first["level"] != "INFO" || first["msg"] != "before task" || first["loop"] != float64(3) {
+		t.Errorf("unexpected first entry: %v", first)
+	}
+	if second["task_id"] != "T002" || second["level"] != "WARN" || second["msg"] != "working on task" {
+		t.Errorf("unexpected second entry: %v", second)
+	}
+	if _, err := time.Parse(time.RFC3339, second["ts"].(string)); err != nil {
+		t.Errorf("ts should be RFC3339: %v", err)
+	}
+}
+
+func TestLoggerSetFormatInvalid(t *testing.T) {
+	tmpDir, cleanup := setupTestDir(t)
+	defer cleanup()
+
+	logger, _ := NewLogger(tmpDir, false)
+	defer logger.Close()
+
+	if err := logger.SetFormat("xml"); err == nil {
+		t.Error("expected error for unknown format")
+	}
+}
+
 func TestLoggerDebugLevel(t *testing.T) {
 	tmpDir, cleanup := setupTestDir(t)
 	defer cleanup()
@@ -120,6 +176,25 @@ func TestFormatProgressBar(t *testing.T) {
 	}
 }
 
+func TestFormatFeatureProgress(t *testing.T) {
+	features := []task.Feature{
+		{ID: "F001", Name: "Auth", Tasks: []task.Task{
+			{Status: task.StatusCompleted}, {Status: task.StatusCompleted}, {Status: task.StatusCompleted},
+			{Status: task.StatusNotStarted}, {Status: task.StatusInProgress},
+		}},
+		{ID: "F002", Name: "Billing", Tasks: []task.Task{{Status: task.StatusCompleted}}},
+	}
+
+	lines := FormatFeatureProgress(features)
+	expected := []string{
+		"F001 - Auth    | 3/5 tasks | 60%",
+		"F002 - Billing | 1/1 tasks | 100%",
+	}
+	if strings.Join(lines, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("FormatFeatureProgress() =\n%s\nwant\n%s", strings.Join(lines, "\n"), strings.Join(expected, "\n"))
+	}
+}
+
 func TestTruncate(t *testing.T) {
 	tests := []struct {
 		input    string